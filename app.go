@@ -6,15 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
+	"time"
 
 	"modbus_simulator/internal/application"
 	"modbus_simulator/internal/domain/protocol"
 	"modbus_simulator/internal/infrastructure/httpapi"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
-	"go.bug.st/serial"
 )
 
 const defaultHTTPAPIPort = 8765
@@ -42,9 +41,10 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
-	// 通信イベントエミッターを設定
-	emitter := protocol.NewWailsEventEmitter(ctx)
-	a.plcService.SetEventEmitter(emitter)
+	// 通信イベントエミッターを設定（Wails UI と WebSocket ストリームの両方へ配信）
+	// 受信・送信イベントは 100ms ごとに comm:stats としてまとめて UI に送る
+	emitter := protocol.NewCoalescingEventEmitter(protocol.NewWailsEventEmitter(ctx), protocol.DefaultCoalesceInterval)
+	a.plcService.SetEventEmitter(protocol.NewMultiEventEmitter(emitter, a.httpAPI.StreamHub()))
 
 	// アプリケーション状態イベントエミッターを設定
 	appEmitter := application.NewWailsAppStateEmitter(ctx)
@@ -55,6 +55,11 @@ func (a *App) startup(ctx context.Context) {
 		appEmitter.EmitConsoleLogAdded(entry)
 	})
 
+	// 診断ログのライブ表示
+	a.plcService.SetLogCallback(func(entry application.LogEntryDTO) {
+		appEmitter.EmitLogEntry(entry)
+	})
+
 	// HostGrpcServer を起動（OPC UA 等のプラグインが変数アクセスに使用）
 	if _, err := a.plcService.StartHostGrpcServer(); err != nil {
 		fmt.Printf("[WARN] HostGrpcServer の起動に失敗しました: %v\n", err)
@@ -211,11 +216,56 @@ func (a *App) StopServer(protocolType string) error {
 	return a.plcService.StopServer(protocolType)
 }
 
+// StartAllServers は全サーバーを追加順に起動する
+func (a *App) StartAllServers() error {
+	return a.plcService.StartAllServers()
+}
+
+// StopAllServers は全サーバーを追加順に停止する
+func (a *App) StopAllServers() error {
+	return a.plcService.StopAllServers()
+}
+
+// SetSharedMemory は全サーバーで同じメモリ内容を共有するかを設定する
+func (a *App) SetSharedMemory(enabled bool) {
+	a.plcService.SetSharedMemory(enabled)
+}
+
+// IsSharedMemory は全サーバーでメモリ内容を共有しているかを返す
+func (a *App) IsSharedMemory() bool {
+	return a.plcService.IsSharedMemory()
+}
+
 // GetServerStatus はサーバーのステータスを返す
 func (a *App) GetServerStatus(protocolType string) string {
 	return a.plcService.GetServerStatus(protocolType)
 }
 
+// GetServerError はサーバーのエラー原因を取得する
+func (a *App) GetServerError(protocolType string) string {
+	return a.plcService.GetServerError(protocolType)
+}
+
+// GetUnitActivity は UnitID ごとの最終ポーリング時刻を返す
+func (a *App) GetUnitActivity() map[int]time.Time {
+	return a.plcService.GetUnitActivity()
+}
+
+// GetConnections は接続中のクライアント一覧を返す
+func (a *App) GetConnections() []application.ConnectionDTO {
+	return a.plcService.GetConnections()
+}
+
+// SetConnectionTimeout は無通信のユニットを接続数から外すまでの時間（ミリ秒）を設定する
+func (a *App) SetConnectionTimeout(ms int) error {
+	return a.plcService.SetConnectionTimeout(ms)
+}
+
+// GetConnectionTimeout はセッションのタイムアウト（ミリ秒）を返す
+func (a *App) GetConnectionTimeout() int {
+	return a.plcService.GetConnectionTimeout()
+}
+
 // === プロトコル管理API ===
 
 // GetAvailableProtocols は利用可能なプロトコル一覧を返す
@@ -267,6 +317,81 @@ func (a *App) GetMemoryAreas(protocolType string) []application.MemoryAreaDTO {
 	return a.plcService.GetMemoryAreas(protocolType)
 }
 
+// ResizeArea はメモリエリアの点数を変更する（サーバー停止中のみ）
+func (a *App) ResizeArea(protocolType, area string, size int) error {
+	return a.plcService.ResizeArea(protocolType, area, size)
+}
+
+// SetOutOfRangePolicy は範囲外アドレス読み取り時の動作（error/zero/wrap）を設定する
+func (a *App) SetOutOfRangePolicy(protocolType, policy string) error {
+	return a.plcService.SetOutOfRangePolicy(protocolType, policy)
+}
+
+// GetOutOfRangePolicy は範囲外アドレス読み取り時の動作を返す
+func (a *App) GetOutOfRangePolicy(protocolType string) string {
+	return a.plcService.GetOutOfRangePolicy(protocolType)
+}
+
+// ReadAllAreas は全エリアの現在値をまとめて返す
+func (a *App) ReadAllAreas(protocolType string) map[string]interface{} {
+	return a.plcService.ReadAllAreas(protocolType)
+}
+
+// GetMemoryPage はエリアの一部分をページ単位で読み取る
+func (a *App) GetMemoryPage(protocolType, area string, offset, length int) (*application.MemoryPageDTO, error) {
+	return a.plcService.GetMemoryPage(protocolType, area, offset, length)
+}
+
+// ReadMixed はビット・ワードの複数の読み取り要求をまとめて実行する
+func (a *App) ReadMixed(requests []application.MixedReadDTO) ([]application.MixedResultDTO, error) {
+	return a.plcService.ReadMixed(requests)
+}
+
+// ExportRangeHex はワード範囲を16進文字列として返す
+func (a *App) ExportRangeHex(protocolType, area string, start, count int) (string, error) {
+	return a.plcService.ExportRangeHex(protocolType, area, start, count)
+}
+
+// ImportRangeHex は16進文字列をワード範囲に書き込む
+func (a *App) ImportRangeHex(protocolType, area string, start int, hex string) error {
+	return a.plcService.ImportRangeHex(protocolType, area, start, hex)
+}
+
+// StartDemoMode は指定範囲にランダムな値を定期的に書き込むデモモードを開始する
+func (a *App) StartDemoMode(protocolType, area string, start, count, intervalMs int) error {
+	return a.plcService.StartDemoMode(protocolType, area, start, count, intervalMs)
+}
+
+// StopDemoMode はデモモードを停止する
+func (a *App) StopDemoMode() {
+	a.plcService.StopDemoMode()
+}
+
+// IsDemoModeRunning はデモモードが実行中かどうかを返す
+func (a *App) IsDemoModeRunning() bool {
+	return a.plcService.IsDemoModeRunning()
+}
+
+// StartAutosave はメモリ内容を指定ディレクトリへ定期的に保存するオートセーブを開始する
+func (a *App) StartAutosave(dir string, intervalMs int) error {
+	return a.plcService.StartAutosave(dir, intervalMs)
+}
+
+// StopAutosave はオートセーブを停止する
+func (a *App) StopAutosave() {
+	a.plcService.StopAutosave()
+}
+
+// IsAutosaveRunning はオートセーブが実行中かどうかを返す
+func (a *App) IsAutosaveRunning() bool {
+	return a.plcService.IsAutosaveRunning()
+}
+
+// RestoreAutosave はオートセーブしたメモリ内容を復元する
+func (a *App) RestoreAutosave(dir string) error {
+	return a.plcService.RestoreAutosave(dir)
+}
+
 // ReadBits は指定エリアの複数ビット値を読み込む
 func (a *App) ReadBits(protocolType, area string, address, count int) ([]bool, error) {
 	return a.plcService.ReadBits(protocolType, area, address, count)
@@ -287,6 +412,51 @@ func (a *App) WriteWord(protocolType, area string, address int, value int) error
 	return a.plcService.WriteWord(protocolType, area, address, value)
 }
 
+// ReadBitsForUnit は指定 UnitID のメモリから複数ビット値を読み込む（UnitID 別メモリが有効な場合）
+func (a *App) ReadBitsForUnit(protocolType string, unitId int, area string, address, count int) ([]bool, error) {
+	return a.plcService.ReadBitsForUnit(protocolType, unitId, area, address, count)
+}
+
+// WriteBitForUnit は指定 UnitID のメモリにビット値を書き込む
+func (a *App) WriteBitForUnit(protocolType string, unitId int, area string, address int, value bool) error {
+	return a.plcService.WriteBitForUnit(protocolType, unitId, area, address, value)
+}
+
+// ReadWordsForUnit は指定 UnitID のメモリから複数ワード値を読み込む（UnitID 別メモリが有効な場合）
+func (a *App) ReadWordsForUnit(protocolType string, unitId int, area string, address, count int) ([]int, error) {
+	return a.plcService.ReadWordsForUnit(protocolType, unitId, area, address, count)
+}
+
+// WriteWordForUnit は指定 UnitID のメモリにワード値を書き込む
+func (a *App) WriteWordForUnit(protocolType string, unitId int, area string, address int, value int) error {
+	return a.plcService.WriteWordForUnit(protocolType, unitId, area, address, value)
+}
+
+// LockArea は指定エリアへの UI からの書き込みを禁止する
+func (a *App) LockArea(protocolType, area string) error {
+	return a.plcService.LockArea(protocolType, area)
+}
+
+// UnlockArea は指定エリアへの UI からの書き込みを再び許可する
+func (a *App) UnlockArea(protocolType, area string) error {
+	return a.plcService.UnlockArea(protocolType, area)
+}
+
+// GetLockedAreas は指定サーバーでロック中のエリアIDを返す
+func (a *App) GetLockedAreas(protocolType string) ([]string, error) {
+	return a.plcService.GetLockedAreas(protocolType)
+}
+
+// SetAreaPersist はプロジェクトのエクスポートでエリアのメモリ内容を保存するかを設定する
+func (a *App) SetAreaPersist(protocolType, area string, persist bool) error {
+	return a.plcService.SetAreaPersist(protocolType, area, persist)
+}
+
+// GetExcludedAreas は指定サーバーでメモリ内容を保存しないエリアIDを返す
+func (a *App) GetExcludedAreas(protocolType string) ([]string, error) {
+	return a.plcService.GetExcludedAreas(protocolType)
+}
+
 // === スクリプト管理 ===
 
 // CreateScript は新しいスクリプトを作成する
@@ -324,11 +494,81 @@ func (a *App) StopScript(id string) error {
 	return a.plcService.StopScript(id)
 }
 
+// AddDriftBehavior はワードを範囲内でランダムウォークさせるドリフト動作を追加し、その ID を返す
+func (a *App) AddDriftBehavior(protocolType, area string, address, min, max, stepMax, intervalMs int) (string, error) {
+	return a.plcService.AddDriftBehavior(protocolType, area, address, min, max, stepMax, intervalMs)
+}
+
+// RemoveDriftBehavior はドリフト動作を削除する
+func (a *App) RemoveDriftBehavior(id string) error {
+	return a.plcService.RemoveDriftBehavior(id)
+}
+
+// GetDriftBehaviors はドリフト動作の一覧を返す
+func (a *App) GetDriftBehaviors() []application.DriftBehaviorDTO {
+	return a.plcService.GetDriftBehaviors()
+}
+
+// CreateTrigger はメモリ書き込みでスクリプトを実行するトリガーを作成する
+func (a *App) CreateTrigger(protocolType, area string, address int, scriptId string) (*application.TriggerDTO, error) {
+	return a.plcService.CreateTrigger(protocolType, area, address, scriptId)
+}
+
+// DeleteTrigger はトリガーを削除する
+func (a *App) DeleteTrigger(id string) error {
+	return a.plcService.DeleteTrigger(id)
+}
+
+// GetTriggers はトリガー一覧を返す
+func (a *App) GetTriggers() []application.TriggerDTO {
+	return a.plcService.GetTriggers()
+}
+
+// SetScriptLibrary はスクリプトをライブラリ（require(name) で読み込まれる）にするかを設定する
+func (a *App) SetScriptLibrary(id string, library bool) error {
+	return a.plcService.SetScriptLibrary(id, library)
+}
+
+// PauseScript はスクリプトの周期実行を一時停止する
+func (a *App) PauseScript(id string) error {
+	return a.plcService.PauseScript(id)
+}
+
+// ResumeScript は一時停止中のスクリプトを再開する
+func (a *App) ResumeScript(id string) error {
+	return a.plcService.ResumeScript(id)
+}
+
+// StepScript はスクリプトを1周期分だけ実行する
+func (a *App) StepScript(id string) error {
+	return a.plcService.StepScript(id)
+}
+
+// SetDefaultByteOrder はスクリプトの float 関数で使う既定のバイト順を設定する
+func (a *App) SetDefaultByteOrder(order string) error {
+	return a.plcService.SetDefaultByteOrder(order)
+}
+
+// GetDefaultByteOrder はスクリプトの既定のバイト順を返す
+func (a *App) GetDefaultByteOrder() string {
+	return a.plcService.GetDefaultByteOrder()
+}
+
 // RunScriptOnce はスクリプトを1回だけ実行する
 func (a *App) RunScriptOnce(code string) (interface{}, error) {
 	return a.plcService.RunScriptOnce(code)
 }
 
+// SetScriptTimeout はスクリプト1回の実行の最大実行時間（ミリ秒、0 で無制限）を設定する
+func (a *App) SetScriptTimeout(ms int) error {
+	return a.plcService.SetScriptTimeout(ms)
+}
+
+// GetScriptTimeout はスクリプト1回の実行の最大実行時間（ミリ秒）を返す
+func (a *App) GetScriptTimeout() int {
+	return a.plcService.GetScriptTimeout()
+}
+
 // ClearScriptError はスクリプトのエラー情報をクリアする
 func (a *App) ClearScriptError(id string) {
 	a.plcService.ClearScriptError(id)
@@ -344,6 +584,26 @@ func (a *App) ClearConsoleLogs() {
 	a.plcService.ClearConsoleLogs()
 }
 
+// GetLogs は level 以上の診断ログを返す（limit > 0 の場合は新しいものから最大 limit 件）
+func (a *App) GetLogs(level string, limit int) ([]application.LogEntryDTO, error) {
+	return a.plcService.GetLogs(level, limit)
+}
+
+// ClearLogs は診断ログをクリアする
+func (a *App) ClearLogs() {
+	a.plcService.ClearLogs()
+}
+
+// SetLogLevel は診断ログの出力レベルを設定する
+func (a *App) SetLogLevel(level string) error {
+	return a.plcService.SetLogLevel(level)
+}
+
+// GetLogLevel は診断ログの出力レベルを返す
+func (a *App) GetLogLevel() string {
+	return a.plcService.GetLogLevel()
+}
+
 // GetIntervalPresets は周期プリセットを取得する
 func (a *App) GetIntervalPresets() []application.IntervalPresetDTO {
 	return a.plcService.GetIntervalPresets()
@@ -369,21 +629,21 @@ func (a *App) ExportProject() error {
 		return nil // キャンセルされた
 	}
 
-	// プロジェクトデータを取得
-	data := a.plcService.ExportProject()
-
-	// JSONに変換
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	// ファイルに書き込み
-	return os.WriteFile(filepath, jsonData, 0644)
+	// プロジェクトデータをファイルに書き込み
+	return a.plcService.ExportProjectToFile(filepath)
 }
 
 // ImportProject はファイルからプロジェクトをインポートする
 func (a *App) ImportProject() error {
+	return a.importProjectFile(application.ImportOptions{})
+}
+
+// ImportProjectSkipChecksum はチェックサムを検証せずにファイルからプロジェクトをインポートする（手で編集したファイル向け）
+func (a *App) ImportProjectSkipChecksum() error {
+	return a.importProjectFile(application.ImportOptions{SkipChecksum: true})
+}
+
+func (a *App) importProjectFile(opts application.ImportOptions) error {
 	// ファイル選択ダイアログを表示
 	filepath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
 		Title: "プロジェクトをインポート",
@@ -405,14 +665,101 @@ func (a *App) ImportProject() error {
 		return err
 	}
 
-	// JSONをパース
-	var data application.ProjectDataDTO
-	if err := json.Unmarshal(jsonData, &data); err != nil {
+	// プロジェクトをインポート
+	return a.plcService.ImportProjectJSON(jsonData, opts)
+}
+
+// StartMonitoringCSVLog は保存先を選択し、モニタリング値の CSV ログを開始する。
+// durationMs が 0 の場合は StopMonitoringCSVLog まで記録する。
+func (a *App) StartMonitoringCSVLog(durationMs, intervalMs int) error {
+	filepath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "モニタリング値を CSV に記録",
+		DefaultFilename: "monitoring.csv",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "CSV Files (*.csv)", Pattern: "*.csv"},
+			{DisplayName: "All Files (*.*)", Pattern: "*.*"},
+		},
+	})
+	if err != nil {
 		return err
 	}
+	if filepath == "" {
+		return nil // キャンセルされた
+	}
+	_, err = a.plcService.LogMonitoringToCSV(filepath, durationMs, intervalMs)
+	return err
+}
 
-	// プロジェクトをインポート
-	return a.plcService.ImportProject(&data)
+// GetAccessHeatmap は指定エリアのアドレスごとのマスターからの読み書き回数を返す
+func (a *App) GetAccessHeatmap(protocolType, area string) ([]application.AccessCountDTO, error) {
+	return a.plcService.GetAccessHeatmap(protocolType, area)
+}
+
+// ResetHeatmap は全サーバーのアクセス回数を破棄する
+func (a *App) ResetHeatmap() {
+	a.plcService.ResetHeatmap()
+}
+
+// GetCommEventCounter は Modbus のイベントカウンタ（正常に応答したリクエスト数）を返す
+func (a *App) GetCommEventCounter(protocolType string) (int, error) {
+	return a.plcService.GetCommEventCounter(protocolType)
+}
+
+// ClearCommEventCounter は Modbus のイベントカウンタを 0 に戻す
+func (a *App) ClearCommEventCounter(protocolType string) error {
+	return a.plcService.ClearCommEventCounter(protocolType)
+}
+
+// SetBusy は durationMs ミリ秒の間、全リクエストに Slave Device Busy 例外を返すようにする（0 で解除）
+func (a *App) SetBusy(durationMs int) error {
+	return a.plcService.SetBusy(durationMs)
+}
+
+// SelfTest は自己診断を実行し、項目ごとの結果を返す
+func (a *App) SelfTest() application.SelfTestReportDTO {
+	return a.plcService.SelfTest()
+}
+
+// SetInitialStateFile はサーバー追加時に読み込む初期値ファイルを設定する（空文字列で解除）
+func (a *App) SetInitialStateFile(path string) error {
+	return a.plcService.SetInitialStateFile(path)
+}
+
+// GetInitialStateFile は設定中の初期値ファイルのパスを返す
+func (a *App) GetInitialStateFile() string {
+	return a.plcService.GetInitialStateFile()
+}
+
+// StopMonitoringCSVLog はモニタリング値の CSV ログを停止する
+func (a *App) StopMonitoringCSVLog() error {
+	return a.plcService.StopMonitoringCSVLog()
+}
+
+// IsMonitoringCSVLogRunning はモニタリング値の CSV ログが実行中かどうかを返す
+func (a *App) IsMonitoringCSVLogRunning() bool {
+	return a.plcService.IsMonitoringCSVLogRunning()
+}
+
+// === レシピ管理 ===
+
+// SaveRecipe は現在のメモリ内容をレシピとして保存する
+func (a *App) SaveRecipe(name string) error {
+	return a.plcService.SaveRecipe(name)
+}
+
+// LoadRecipe はレシピの値をメモリに書き込む
+func (a *App) LoadRecipe(name string) error {
+	return a.plcService.LoadRecipe(name)
+}
+
+// ListRecipes は保存済みのレシピ一覧を返す
+func (a *App) ListRecipes() []*application.RecipeDTO {
+	return a.plcService.ListRecipes()
+}
+
+// DeleteRecipe はレシピを削除する
+func (a *App) DeleteRecipe(name string) error {
+	return a.plcService.DeleteRecipe(name)
 }
 
 // === モニタリング管理 ===
@@ -422,16 +769,41 @@ func (a *App) GetMonitoringItems() []*application.MonitoringItemDTO {
 	return a.plcService.GetMonitoringItems()
 }
 
+// GetMonitoringItemsByGroup はモニタリング項目をグループ名ごとにまとめて返す
+func (a *App) GetMonitoringItemsByGroup() map[string][]*application.MonitoringItemDTO {
+	return a.plcService.GetMonitoringItemsByGroup()
+}
+
 // AddMonitoringItem はモニタリング項目を追加する
 func (a *App) AddMonitoringItem(item *application.MonitoringItemDTO) (*application.MonitoringItemDTO, error) {
 	return a.plcService.AddMonitoringItem(item)
 }
 
+// AddMonitoringItemWithOptions は重複時の動作を指定してモニタリング項目を追加する
+func (a *App) AddMonitoringItemWithOptions(item *application.MonitoringItemDTO, opts application.AddMonitoringOptions) (*application.MonitoringItemDTO, error) {
+	return a.plcService.AddMonitoringItemWithOptions(item, opts)
+}
+
+// SuggestMonitoringDefaults はモニタリング項目追加時の初期値をエリアの種類から提案する
+func (a *App) SuggestMonitoringDefaults(protocolType, area string, address int) (*application.MonitoringItemDTO, error) {
+	return a.plcService.SuggestMonitoringDefaults(protocolType, area, address)
+}
+
+// SetMonitoringItems はモニタリング項目一覧をまとめて置き換える
+func (a *App) SetMonitoringItems(items []*application.MonitoringItemDTO) error {
+	return a.plcService.SetMonitoringItems(items)
+}
+
 // UpdateMonitoringItem はモニタリング項目を更新する
 func (a *App) UpdateMonitoringItem(item *application.MonitoringItemDTO) error {
 	return a.plcService.UpdateMonitoringItem(item)
 }
 
+// WriteMonitoringValue はモニタリング項目に工学値で書き込む（換算を設定した項目は生値に戻して書き込む）
+func (a *App) WriteMonitoringValue(id string, value float64) error {
+	return a.plcService.WriteMonitoringValue(id, value)
+}
+
 // DeleteMonitoringItem はモニタリング項目を削除する
 func (a *App) DeleteMonitoringItem(id string) error {
 	return a.plcService.DeleteMonitoringItem(id)
@@ -454,14 +826,24 @@ func (a *App) ClearMonitoringItems() {
 
 // === シリアルポート ===
 
-// GetSerialPorts はシステムで利用可能なシリアルポートの一覧を返す
+// GetSerialPorts はシステムで利用可能なシリアルポートの名前の一覧を返す
 func (a *App) GetSerialPorts() []string {
-	ports, err := serial.GetPortsList()
-	if err != nil {
-		return []string{}
+	ports := application.ListSerialPorts()
+	names := make([]string, len(ports))
+	for i, p := range ports {
+		names[i] = p.Name
 	}
-	sort.Strings(ports)
-	return ports
+	return names
+}
+
+// GetSerialPortDetails はシリアルポートの一覧を USB 情報（VID/PID・製品名）付きで返す
+func (a *App) GetSerialPortDetails() []application.SerialPortInfoDTO {
+	return application.ListSerialPorts()
+}
+
+// DetectSerialMode はシリアルポートで受信したフレームから "rtu" / "ascii" / "unknown" を推定する（サーバーは起動しない）
+func (a *App) DetectSerialMode(port string, timeoutMs int) (string, error) {
+	return a.plcService.DetectSerialMode(port, timeoutMs)
 }
 
 // === 変数管理 ===
@@ -0,0 +1,41 @@
+//go:build windows
+
+package mmapstore
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+func mmap(f *os.File, size int) ([]byte, error) {
+	h, err := windows.CreateFileMapping(windows.Handle(f.Fd()), nil, windows.PAGE_READWRITE, uint32(uint64(size)>>32), uint32(size), nil)
+	if err != nil {
+		return nil, err
+	}
+	// ビューがマッピングを参照し続けるため、ハンドルはすぐに閉じてよい
+	defer windows.CloseHandle(h)
+
+	addr, err := windows.MapViewOfFile(h, windows.FILE_MAP_WRITE, 0, 0, uintptr(size))
+	if err != nil {
+		return nil, err
+	}
+	// addr は Go のヒープ外のアドレスのため、スライスヘッダーを組み立てて参照する
+	return *(*[]byte)(unsafe.Pointer(&sliceHeader{data: addr, len: size, cap: size})), nil
+}
+
+// sliceHeader は []byte の内部表現
+type sliceHeader struct {
+	data uintptr
+	len  int
+	cap  int
+}
+
+func munmap(b []byte) error {
+	return windows.UnmapViewOfFile(uintptr(unsafe.Pointer(&b[0])))
+}
+
+func msync(b []byte) error {
+	return windows.FlushViewOfFile(uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)))
+}
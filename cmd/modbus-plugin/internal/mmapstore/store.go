@@ -0,0 +1,438 @@
+// Package mmapstore はメモリマップトファイルを使った protocol.DataStore 実装を提供する。
+// 数百万ワード規模のメモリをヒープに確保せずに扱うためのもので、値はファイルに直接保持される。
+package mmapstore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strings"
+	"sync"
+
+	"modbus_simulator/internal/domain/datastore"
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// AreaSpec はメモリエリアの定義
+type AreaSpec struct {
+	ID          string
+	DisplayName string
+	IsBit       bool
+	Size        uint32
+	OneOrigin   bool
+}
+
+// ファイル先頭のヘッダー: マジック(8) + レイアウトのチェックサム(4) + 予約(4)
+const (
+	headerSize = 16
+	magic      = "PLCMMAP1"
+)
+
+type area struct {
+	spec   AreaSpec
+	offset int // データ領域の先頭からのバイトオフセット
+	bytes  int
+}
+
+// Store はメモリマップトファイルを使ったデータストア。
+// ビットは1バイトに8点、ワードはリトルエンディアンの2バイトで格納する。
+type Store struct {
+	mu    sync.RWMutex
+	file  *os.File
+	data  []byte // マップされたファイル全体（ヘッダーを含む）
+	areas []*area
+	byID  map[string]*area
+
+	hookMu     sync.RWMutex
+	changeHook ChangeHook
+}
+
+// ChangeHook は書き込み時に呼ばれるコールバック（isBit=true の場合は bitValues、false の場合は values を参照する）
+type ChangeHook = func(area string, address uint32, values []uint16, isBit bool, bitValues []bool)
+
+// SetChangeHook は書き込み時に呼ばれるフックを設定する（nil で解除）
+func (s *Store) SetChangeHook(hook ChangeHook) {
+	s.hookMu.Lock()
+	s.changeHook = hook
+	s.hookMu.Unlock()
+}
+
+// callChangeHook はフックを安全に呼び出す（ロック外で呼ぶこと）
+func (s *Store) callChangeHook(area string, address uint32, values []uint16, isBit bool, bitValues []bool) {
+	s.hookMu.RLock()
+	hook := s.changeHook
+	s.hookMu.RUnlock()
+	if hook != nil {
+		hook(area, address, values, isBit, bitValues)
+	}
+}
+
+var _ protocol.DataStore = (*Store)(nil)
+
+// Open は path のファイルをマップしたデータストアを作成する。
+// ファイルが存在しない、またはエリア構成が異なる場合はゼロクリアされた状態で初期化する。
+// 同じ構成で開き直した場合は前回の値がそのまま残る。
+func Open(path string, specs []AreaSpec) (*Store, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("mmapstore: no areas")
+	}
+
+	s := &Store{byID: make(map[string]*area, len(specs))}
+	offset := 0
+	for _, spec := range specs {
+		if _, dup := s.byID[spec.ID]; dup {
+			return nil, fmt.Errorf("mmapstore: duplicate area %s", spec.ID)
+		}
+		a := &area{spec: spec, offset: offset}
+		if spec.IsBit {
+			a.bytes = int((spec.Size + 7) / 8)
+		} else {
+			a.bytes = int(spec.Size) * 2
+		}
+		offset += a.bytes
+		s.areas = append(s.areas, a)
+		s.byID[spec.ID] = a
+	}
+	size := headerSize + offset
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size() != int64(size) {
+		// サイズが異なる場合は作り直す
+		if err := f.Truncate(0); err != nil {
+			f.Close()
+			return nil, err
+		}
+		if err := f.Truncate(int64(size)); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	data, err := mmap(f, size)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("mmapstore: %w", err)
+	}
+	s.file = f
+	s.data = data
+
+	sum := layoutChecksum(specs)
+	if string(data[:8]) != magic || binary.LittleEndian.Uint32(data[8:12]) != sum {
+		clear(data)
+		copy(data, magic)
+		binary.LittleEndian.PutUint32(data[8:12], sum)
+	}
+	return s, nil
+}
+
+// layoutChecksum はエリア構成のチェックサムを計算する
+func layoutChecksum(specs []AreaSpec) uint32 {
+	var b strings.Builder
+	for _, spec := range specs {
+		fmt.Fprintf(&b, "%s:%t:%d;", spec.ID, spec.IsBit, spec.Size)
+	}
+	return crc32.ChecksumIEEE([]byte(b.String()))
+}
+
+// Flush はマップされた内容をファイルに書き出す
+func (s *Store) Flush() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data == nil {
+		return nil
+	}
+	return msync(s.data)
+}
+
+// Close は内容を書き出してマップを解除し、ファイルを閉じる
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return nil
+	}
+	err := msync(s.data)
+	if uerr := munmap(s.data); err == nil {
+		err = uerr
+	}
+	s.data = nil
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// GetAreas は利用可能なメモリエリアの一覧を返す
+func (s *Store) GetAreas() []protocol.MemoryArea {
+	areas := make([]protocol.MemoryArea, len(s.areas))
+	for i, a := range s.areas {
+		areas[i] = a.memoryArea()
+	}
+	return areas
+}
+
+// GetArea は指定IDのメモリエリアを返す
+func (s *Store) GetArea(id string) (protocol.MemoryArea, bool) {
+	a, ok := s.byID[id]
+	if !ok {
+		return protocol.MemoryArea{}, false
+	}
+	return a.memoryArea(), true
+}
+
+func (a *area) memoryArea() protocol.MemoryArea {
+	return protocol.MemoryArea{
+		ID:          a.spec.ID,
+		DisplayName: a.spec.DisplayName,
+		IsBit:       a.spec.IsBit,
+		Size:        a.spec.Size,
+		OneOrigin:   a.spec.OneOrigin,
+	}
+}
+
+// lookup は種類が一致するエリアと範囲を検証する（ロック取得済みであること）
+func (s *Store) lookup(id string, isBit bool, address uint32, count int) (*area, error) {
+	if s.data == nil {
+		return nil, fmt.Errorf("mmapstore: store is closed")
+	}
+	a, ok := s.byID[id]
+	if !ok || a.spec.IsBit != isBit {
+		return nil, datastore.ErrAreaNotFound
+	}
+	if err := (datastore.AddrRange{Address: address, Count: uint32(count)}).Validate(a.spec.Size); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *Store) bytesOf(a *area) []byte {
+	start := headerSize + a.offset
+	return s.data[start : start+a.bytes]
+}
+
+func getBit(b []byte, i uint32) bool {
+	return b[i/8]&(1<<(i%8)) != 0
+}
+
+func setBit(b []byte, i uint32, v bool) {
+	if v {
+		b[i/8] |= 1 << (i % 8)
+	} else {
+		b[i/8] &^= 1 << (i % 8)
+	}
+}
+
+// ReadBit はビット値を読み込む
+func (s *Store) ReadBit(areaID string, address uint32) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, err := s.lookup(areaID, true, address, 1)
+	if err != nil {
+		return false, err
+	}
+	return getBit(s.bytesOf(a), address), nil
+}
+
+// WriteBit はビット値を書き込む
+func (s *Store) WriteBit(areaID string, address uint32, value bool) error {
+	s.mu.Lock()
+	a, err := s.lookup(areaID, true, address, 1)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	setBit(s.bytesOf(a), address, value)
+	s.mu.Unlock()
+	s.callChangeHook(areaID, address, nil, true, []bool{value})
+	return nil
+}
+
+// ReadBits は複数のビット値を読み込む
+func (s *Store) ReadBits(areaID string, address uint32, count uint16) ([]bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, err := s.lookup(areaID, true, address, int(count))
+	if err != nil {
+		return nil, err
+	}
+	b := s.bytesOf(a)
+	values := make([]bool, count)
+	for i := range values {
+		values[i] = getBit(b, address+uint32(i))
+	}
+	return values, nil
+}
+
+// WriteBits は複数のビット値を書き込む
+func (s *Store) WriteBits(areaID string, address uint32, values []bool) error {
+	s.mu.Lock()
+	a, err := s.lookup(areaID, true, address, len(values))
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	b := s.bytesOf(a)
+	for i, v := range values {
+		setBit(b, address+uint32(i), v)
+	}
+	s.mu.Unlock()
+	s.callChangeHook(areaID, address, nil, true, values)
+	return nil
+}
+
+// ReadWord はワード値を読み込む
+func (s *Store) ReadWord(areaID string, address uint32) (uint16, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, err := s.lookup(areaID, false, address, 1)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(s.bytesOf(a)[address*2:]), nil
+}
+
+// WriteWord はワード値を書き込む
+func (s *Store) WriteWord(areaID string, address uint32, value uint16) error {
+	s.mu.Lock()
+	a, err := s.lookup(areaID, false, address, 1)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	binary.LittleEndian.PutUint16(s.bytesOf(a)[address*2:], value)
+	s.mu.Unlock()
+	s.callChangeHook(areaID, address, []uint16{value}, false, nil)
+	return nil
+}
+
+// ReadWords は複数のワード値を読み込む
+func (s *Store) ReadWords(areaID string, address uint32, count uint16) ([]uint16, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, err := s.lookup(areaID, false, address, int(count))
+	if err != nil {
+		return nil, err
+	}
+	b := s.bytesOf(a)[address*2:]
+	values := make([]uint16, count)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return values, nil
+}
+
+// WriteWords は複数のワード値を書き込む
+func (s *Store) WriteWords(areaID string, address uint32, values []uint16) error {
+	s.mu.Lock()
+	a, err := s.lookup(areaID, false, address, len(values))
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	b := s.bytesOf(a)[address*2:]
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(b[i*2:], v)
+	}
+	s.mu.Unlock()
+	s.callChangeHook(areaID, address, values, false, nil)
+	return nil
+}
+
+// Snapshot は全エリアのデータのスナップショットを返す（ビットエリアは []bool、ワードエリアは []uint16）
+func (s *Store) Snapshot() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]interface{}, len(s.areas))
+	if s.data == nil {
+		return result
+	}
+	for _, a := range s.areas {
+		b := s.bytesOf(a)
+		if a.spec.IsBit {
+			values := make([]bool, a.spec.Size)
+			for i := range values {
+				values[i] = getBit(b, uint32(i))
+			}
+			result[a.spec.ID] = values
+		} else {
+			values := make([]uint16, a.spec.Size)
+			for i := range values {
+				values[i] = binary.LittleEndian.Uint16(b[i*2:])
+			}
+			result[a.spec.ID] = values
+		}
+	}
+	return result
+}
+
+// Restore はスナップショットからデータを復元する。
+// エリアサイズを超える値は切り捨て、未知のエリアは無視する。
+func (s *Store) Restore(data map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return fmt.Errorf("mmapstore: store is closed")
+	}
+
+	for _, a := range s.areas {
+		v, ok := data[a.spec.ID]
+		if !ok {
+			continue
+		}
+		b := s.bytesOf(a)
+		if a.spec.IsBit {
+			bits, ok := datastore.SnapshotBits(v)
+			if !ok {
+				continue
+			}
+			for i := 0; i < len(bits) && i < int(a.spec.Size); i++ {
+				setBit(b, uint32(i), bits[i])
+			}
+		} else {
+			words, ok := datastore.SnapshotWords(v)
+			if !ok {
+				continue
+			}
+			for i := 0; i < len(words) && i < int(a.spec.Size); i++ {
+				binary.LittleEndian.PutUint16(b[i*2:], words[i])
+			}
+		}
+	}
+	return nil
+}
+
+// SnapshotSparse は全エリアのデータを 0 以外の値が連続する範囲（ラン）のみのスパース形式で返す
+func (s *Store) SnapshotSparse() datastore.SparseSnapshot {
+	// Snapshot は型付きスライスのみを返すため変換は失敗しない
+	sparse, _ := datastore.SparseFromSnapshot(s.Snapshot())
+	return sparse
+}
+
+// RestoreSparse は SnapshotSparse の出力からデータを復元する（ランに含まれないアドレスは 0 になる）
+func (s *Store) RestoreSparse(sparse datastore.SparseSnapshot) error {
+	snap, err := sparse.Expand()
+	if err != nil {
+		return err
+	}
+	return s.Restore(snap)
+}
+
+// ClearAll は全データをクリアする
+func (s *Store) ClearAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return
+	}
+	clear(s.data[headerSize:])
+}
@@ -0,0 +1,292 @@
+package mmapstore
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+var testSpecs = []AreaSpec{
+	{ID: "coils", DisplayName: "Coils", IsBit: true, Size: 10, OneOrigin: true},
+	{ID: "holdingRegisters", DisplayName: "Holding Registers", Size: 10, OneOrigin: true},
+}
+
+func openTestStore(t *testing.T, path string, specs []AreaSpec) *Store {
+	t.Helper()
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "memory.bin")
+	}
+	s, err := Open(path, specs)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStore_GetAreas(t *testing.T) {
+	s := openTestStore(t, "", testSpecs)
+	areas := s.GetAreas()
+	if len(areas) != 2 {
+		t.Fatalf("expected 2 areas, got %d", len(areas))
+	}
+	if areas[0].ID != "coils" || !areas[0].IsBit || areas[0].Size != 10 || !areas[0].OneOrigin {
+		t.Errorf("unexpected coils area: %+v", areas[0])
+	}
+	if areas[1].ID != "holdingRegisters" || areas[1].IsBit {
+		t.Errorf("unexpected holdingRegisters area: %+v", areas[1])
+	}
+}
+
+func TestStore_GetArea(t *testing.T) {
+	s := openTestStore(t, "", testSpecs)
+	area, ok := s.GetArea("holdingRegisters")
+	if !ok {
+		t.Fatal("holdingRegisters not found")
+	}
+	if area.DisplayName != "Holding Registers" || area.IsBit || area.Size != 10 {
+		t.Errorf("unexpected area: %+v", area)
+	}
+	if _, ok := s.GetArea("inputRegisters"); ok {
+		t.Error("unknown area should not be found")
+	}
+}
+
+func TestStore_ReadWriteBits(t *testing.T) {
+	s := openTestStore(t, "", testSpecs)
+
+	if err := s.WriteBit("coils", 9, true); err != nil {
+		t.Fatalf("WriteBit: %v", err)
+	}
+	if v, err := s.ReadBit("coils", 9); err != nil || !v {
+		t.Errorf("ReadBit(9) = %v, %v; want true", v, err)
+	}
+
+	values := []bool{true, false, true, true}
+	if err := s.WriteBits("coils", 2, values); err != nil {
+		t.Fatalf("WriteBits: %v", err)
+	}
+	got, err := s.ReadBits("coils", 2, 4)
+	if err != nil || !reflect.DeepEqual(got, values) {
+		t.Errorf("ReadBits = %v, %v; want %v", got, err, values)
+	}
+	if v, _ := s.ReadBit("coils", 1); v {
+		t.Error("neighbouring bit changed")
+	}
+}
+
+func TestStore_ReadWriteWords(t *testing.T) {
+	s := openTestStore(t, "", testSpecs)
+
+	if err := s.WriteWord("holdingRegisters", 0, 0x1234); err != nil {
+		t.Fatalf("WriteWord: %v", err)
+	}
+	if v, err := s.ReadWord("holdingRegisters", 0); err != nil || v != 0x1234 {
+		t.Errorf("ReadWord(0) = 0x%04X, %v", v, err)
+	}
+
+	values := []uint16{0x1111, 0x2222, 0x3333}
+	if err := s.WriteWords("holdingRegisters", 7, values); err != nil {
+		t.Fatalf("WriteWords: %v", err)
+	}
+	got, err := s.ReadWords("holdingRegisters", 7, 3)
+	if err != nil || !reflect.DeepEqual(got, values) {
+		t.Errorf("ReadWords = %v, %v; want %v", got, err, values)
+	}
+}
+
+func TestStore_ChangeHook(t *testing.T) {
+	s := openTestStore(t, "", testSpecs)
+	type change struct {
+		area    string
+		address uint32
+		words   []uint16
+		bits    []bool
+	}
+	var got []change
+	s.SetChangeHook(func(area string, address uint32, values []uint16, isBit bool, bitValues []bool) {
+		got = append(got, change{area, address, values, bitValues})
+	})
+
+	_ = s.WriteBit("coils", 1, true)
+	_ = s.WriteWords("holdingRegisters", 2, []uint16{5, 6})
+	_ = s.WriteWord("holdingRegisters", 99, 1) // 範囲外は通知しない
+	want := []change{
+		{"coils", 1, nil, []bool{true}},
+		{"holdingRegisters", 2, []uint16{5, 6}, nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %+v, want %+v", got, want)
+	}
+
+	s.SetChangeHook(nil)
+	_ = s.WriteWord("holdingRegisters", 0, 1)
+	if len(got) != 2 {
+		t.Error("hook should not be called after it is cleared")
+	}
+}
+
+func TestStore_OutOfRangeAndAreaNotFound(t *testing.T) {
+	s := openTestStore(t, "", testSpecs)
+
+	if _, err := s.ReadBit("coils", 10); !errors.Is(err, datastore.ErrAddressOutOfRange) {
+		t.Errorf("ReadBit out of range: %v", err)
+	}
+	if err := s.WriteBits("coils", 8, []bool{true, true, true}); !errors.Is(err, datastore.ErrAddressOutOfRange) {
+		t.Errorf("WriteBits out of range: %v", err)
+	}
+	if _, err := s.ReadWords("holdingRegisters", 5, 6); !errors.Is(err, datastore.ErrAddressOutOfRange) {
+		t.Errorf("ReadWords out of range: %v", err)
+	}
+	if err := s.WriteWord("holdingRegisters", 0xFFFFFFFF, 1); !errors.Is(err, datastore.ErrAddressOutOfRange) {
+		t.Errorf("WriteWord at max address: %v", err)
+	}
+	if _, err := s.ReadBit("unknown", 0); !errors.Is(err, datastore.ErrAreaNotFound) {
+		t.Errorf("ReadBit unknown area: %v", err)
+	}
+	// ビットエリアをワードとして読むことはできない
+	if _, err := s.ReadWord("coils", 0); !errors.Is(err, datastore.ErrAreaNotFound) {
+		t.Errorf("ReadWord on bit area: %v", err)
+	}
+}
+
+func TestStore_SnapshotRestore(t *testing.T) {
+	src := openTestStore(t, "", testSpecs)
+	_ = src.WriteBit("coils", 3, true)
+	_ = src.WriteWord("holdingRegisters", 4, 0xABCD)
+
+	snap := src.Snapshot()
+	if coils, ok := snap["coils"].([]bool); !ok || len(coils) != 10 || !coils[3] {
+		t.Errorf("snapshot coils = %v", snap["coils"])
+	}
+	if regs, ok := snap["holdingRegisters"].([]uint16); !ok || len(regs) != 10 || regs[4] != 0xABCD {
+		t.Errorf("snapshot holdingRegisters = %v", snap["holdingRegisters"])
+	}
+
+	// gRPC 経由と同様に JSON を経由させる
+	b, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := openTestStore(t, "", testSpecs)
+	if err := dst.Restore(data); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if v, _ := dst.ReadBit("coils", 3); !v {
+		t.Error("expected coil[3] to be true")
+	}
+	if v, _ := dst.ReadWord("holdingRegisters", 4); v != 0xABCD {
+		t.Errorf("expected 0xABCD, got 0x%04X", v)
+	}
+
+	// サイズを超える値は切り捨てる
+	if err := dst.Restore(map[string]interface{}{"holdingRegisters": make([]uint16, 20)}); err != nil {
+		t.Errorf("Restore with oversized values: %v", err)
+	}
+}
+
+func TestStore_SnapshotSparse(t *testing.T) {
+	specs := []AreaSpec{
+		{ID: "coils", IsBit: true, Size: 1000},
+		{ID: "holdingRegisters", Size: 32768},
+	}
+	src := openTestStore(t, "", specs)
+	src.WriteWords("holdingRegisters", 30000, []uint16{7, 8})
+	src.WriteBit("coils", 3, true)
+
+	sparse := src.SnapshotSparse()
+	if runs := sparse["holdingRegisters"].Runs; len(runs) != 1 || runs[0].Start != 30000 {
+		t.Errorf("holding register runs = %+v", runs)
+	}
+	sparseJSON, _ := json.Marshal(sparse)
+	fullJSON, _ := json.Marshal(src.Snapshot())
+	if len(sparseJSON)*100 > len(fullJSON) {
+		t.Errorf("sparse snapshot is %d bytes, full snapshot is %d bytes", len(sparseJSON), len(fullJSON))
+	}
+
+	dst := openTestStore(t, "", specs)
+	dst.WriteWord("holdingRegisters", 1, 1)
+	var decoded datastore.SparseSnapshot
+	if err := json.Unmarshal(sparseJSON, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.RestoreSparse(decoded); err != nil {
+		t.Fatalf("RestoreSparse: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Snapshot(), src.Snapshot()) {
+		t.Error("store after RestoreSparse differs from source")
+	}
+}
+
+func TestStore_ClearAll(t *testing.T) {
+	s := openTestStore(t, "", testSpecs)
+	_ = s.WriteBit("coils", 0, true)
+	_ = s.WriteWord("holdingRegisters", 0, 1)
+
+	s.ClearAll()
+
+	if v, _ := s.ReadBit("coils", 0); v {
+		t.Error("coil not cleared")
+	}
+	if v, _ := s.ReadWord("holdingRegisters", 0); v != 0 {
+		t.Error("holding register not cleared")
+	}
+}
+
+func TestStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.bin")
+
+	s, err := Open(path, testSpecs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.WriteWord("holdingRegisters", 9, 0xBEEF)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := s.ReadWord("holdingRegisters", 9); err == nil {
+		t.Error("expected error after Close")
+	}
+
+	reopened := openTestStore(t, path, testSpecs)
+	if v, _ := reopened.ReadWord("holdingRegisters", 9); v != 0xBEEF {
+		t.Errorf("value after reopen = 0x%04X, want 0xBEEF", v)
+	}
+	reopened.Close()
+
+	// 構成が変わった場合はゼロクリアされる
+	changed := []AreaSpec{{ID: "holdingRegisters", Size: 10}, {ID: "coils", IsBit: true, Size: 10}}
+	other := openTestStore(t, path, changed)
+	if v, _ := other.ReadWord("holdingRegisters", 9); v != 0 {
+		t.Errorf("value after layout change = 0x%04X, want 0", v)
+	}
+}
+
+func TestStore_LargeArea(t *testing.T) {
+	s := openTestStore(t, "", []AreaSpec{{ID: "words", Size: 4_000_000}})
+	if err := s.WriteWord("words", 3_999_999, 42); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.ReadWord("words", 3_999_999); v != 42 {
+		t.Errorf("last word = %d, want 42", v)
+	}
+}
+
+func TestOpen_InvalidSpecs(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Open(filepath.Join(dir, "a.bin"), nil); err == nil {
+		t.Error("expected error for no areas")
+	}
+	if _, err := Open(filepath.Join(dir, "b.bin"), []AreaSpec{{ID: "x", Size: 1}, {ID: "x", Size: 1}}); err == nil {
+		t.Error("expected error for duplicate area")
+	}
+}
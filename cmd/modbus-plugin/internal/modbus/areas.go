@@ -0,0 +1,106 @@
+package modbus
+
+import (
+	"fmt"
+	"strings"
+
+	"modbus_simulator/internal/domain/datastore"
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// AreaOrder は GetAreas が返すエリアのデフォルトの順序
+// （コイル → ディスクリート入力 → 保持レジスタ → 入力レジスタ）
+var AreaOrder = []string{AreaCoils, AreaDiscreteInputs, AreaHoldingRegs, AreaInputRegs}
+
+// SetVisibleAreas は GetAreas で返すエリアとその順序を設定する。
+// nil または空の場合は AreaOrder の全エリアを返す。
+// 非表示にしたエリアも ReadWords などで直接読み書きでき、Modbus クライアントからもアクセスできる。
+func (s *ModbusDataStore) SetVisibleAreas(ids []string) error {
+	visible, err := normalizeVisibleAreas(ids)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.visibleAreas = visible
+	return nil
+}
+
+// VisibleAreas は GetAreas で返すエリアの ID を順に返す
+func (s *ModbusDataStore) VisibleAreas() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.visibleAreaIDs()
+}
+
+// visibleAreaIDs は表示するエリア ID を返す（ロック取得済みであること）
+func (s *ModbusDataStore) visibleAreaIDs() []string {
+	if len(s.visibleAreas) == 0 {
+		return append([]string(nil), AreaOrder...)
+	}
+	return append([]string(nil), s.visibleAreas...)
+}
+
+// normalizeVisibleAreas はエリア ID を検証し、重複を取り除く
+func normalizeVisibleAreas(ids []string) ([]string, error) {
+	var visible []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		if !isModbusArea(id) {
+			return nil, fmt.Errorf("%w: %s", datastore.ErrAreaNotFound, id)
+		}
+		seen[id] = true
+		visible = append(visible, id)
+	}
+	return visible, nil
+}
+
+func isModbusArea(id string) bool {
+	for _, a := range AreaOrder {
+		if a == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parseVisibleAreas は設定値（カンマ区切りの文字列または文字列の配列）をエリア ID の一覧に変換する
+func parseVisibleAreas(v interface{}) ([]string, error) {
+	var ids []string
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		ids = strings.Split(t, ",")
+	case []string:
+		ids = t
+	case []interface{}:
+		for _, e := range t {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("visibleAreas: invalid element %v", e)
+			}
+			ids = append(ids, s)
+		}
+	default:
+		return nil, fmt.Errorf("visibleAreas: unsupported type %T", v)
+	}
+	return normalizeVisibleAreas(ids)
+}
+
+// applyVisibleAreas は設定の visibleAreas を共有データストアに反映する
+func (s *ModbusServer) applyVisibleAreas() error {
+	store := s.store
+	if u, ok := store.(interface{ Unwrap() protocol.DataStore }); ok {
+		store = u.Unwrap()
+	}
+	ms, ok := store.(*ModbusDataStore)
+	if !ok {
+		return nil
+	}
+	return ms.SetVisibleAreas(s.config.VisibleAreas)
+}
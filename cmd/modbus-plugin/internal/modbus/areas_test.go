@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+func areaIDs(s *ModbusDataStore) []string {
+	var ids []string
+	for _, a := range s.GetAreas() {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestModbusDataStore_GetAreas_DefaultOrder(t *testing.T) {
+	s := NewModbusDataStore(1, 1, 1, 1)
+	if got := areaIDs(s); !reflect.DeepEqual(got, AreaOrder) {
+		t.Errorf("GetAreas order = %v, want %v", got, AreaOrder)
+	}
+}
+
+func TestModbusDataStore_VisibleAreas_HideDiscreteInputs(t *testing.T) {
+	s := NewModbusDataStore(10, 10, 10, 10)
+	_ = s.WriteBit(AreaDiscreteInputs, 3, true)
+
+	want := []string{AreaHoldingRegs, AreaCoils, AreaInputRegs}
+	if err := s.SetVisibleAreas(want); err != nil {
+		t.Fatalf("SetVisibleAreas: %v", err)
+	}
+	if got := areaIDs(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAreas = %v, want %v", got, want)
+	}
+
+	// 非表示でも直接の読み取りは可能
+	v, err := s.ReadBit(AreaDiscreteInputs, 3)
+	if err != nil || !v {
+		t.Errorf("ReadBit(discreteInputs, 3) = %v, %v; want true, nil", v, err)
+	}
+
+	// 空にすると全エリアに戻る
+	if err := s.SetVisibleAreas(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := areaIDs(s); !reflect.DeepEqual(got, AreaOrder) {
+		t.Errorf("GetAreas after reset = %v, want %v", got, AreaOrder)
+	}
+}
+
+func TestModbusDataStore_SetVisibleAreas_UnknownArea(t *testing.T) {
+	s := NewModbusDataStore(1, 1, 1, 1)
+	if err := s.SetVisibleAreas([]string{AreaCoils, "bogus"}); !errors.Is(err, datastore.ErrAreaNotFound) {
+		t.Errorf("expected ErrAreaNotFound, got %v", err)
+	}
+}
+
+func TestModbusServerFactory_VisibleAreasConfig(t *testing.T) {
+	f := NewModbusTCPServerFactory()
+	cfg, err := f.MapToConfig("", map[string]interface{}{"tcpPort": 1502, "visibleAreas": "coils, holdingRegisters"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if got := f.ConfigToMap(cfg)["visibleAreas"]; got != "coils,holdingRegisters" {
+		t.Errorf("ConfigToMap visibleAreas = %v", got)
+	}
+
+	store := NewModbusDataStore(10, 10, 10, 10)
+	srv, err := f.CreateServer(cfg, store)
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	if got := areaIDs(store); !reflect.DeepEqual(got, []string{AreaCoils, AreaHoldingRegs}) {
+		t.Errorf("GetAreas = %v", got)
+	}
+
+	// UnitID 別ストアは非表示エリアも同じサイズで作成される
+	ms := srv.(*ModbusServer)
+	ms.handler.SetPerUnitStore(true)
+	if _, err := ms.UnitDataStore(5).ReadBits(AreaDiscreteInputs, 9, 1); err != nil {
+		t.Errorf("hidden area on unit store: %v", err)
+	}
+
+	if _, err := f.MapToConfig("", map[string]interface{}{"visibleAreas": "coils,bogus"}); err == nil {
+		t.Error("expected error for unknown area in config")
+	}
+}
@@ -0,0 +1,41 @@
+package modbus
+
+import (
+	"sync/atomic"
+	"time"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+)
+
+// busyWindow は SetBusy で指定した期間の終了時刻（UnixNano、0 で無効）を保持する
+type busyWindow struct {
+	until atomic.Int64
+}
+
+// SetBusy は d の間、全リクエストに Slave Device Busy (0x06) 例外を返すようにする（0以下で解除）
+func (h *DataStoreHandler) SetBusy(d time.Duration) {
+	if d <= 0 {
+		h.busy.until.Store(0)
+		return
+	}
+	h.busy.until.Store(time.Now().Add(d).UnixNano())
+}
+
+// IsBusy は SetBusy で指定した期間中かどうかを返す
+func (h *DataStoreHandler) IsBusy() bool {
+	return time.Now().UnixNano() < h.busy.until.Load()
+}
+
+// checkBusy はビジー期間中なら Slave Device Busy 例外を返す
+func (a *RTUDataStoreAdapter) checkBusy() error {
+	if a.handler.IsBusy() {
+		return rtu.NewModbusException(rtu.ExceptionSlaveDeviceBusy)
+	}
+	return nil
+}
+
+// SetBusy は d の間、全リクエストに Slave Device Busy (0x06) 例外を返すようにする（0以下で解除）
+func (s *ModbusServer) SetBusy(d time.Duration) error {
+	s.handler.SetBusy(d)
+	return nil
+}
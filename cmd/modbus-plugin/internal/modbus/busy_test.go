@@ -0,0 +1,62 @@
+package modbus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+var _ protocol.BusySimulator = (*ModbusServer)(nil)
+
+func TestSetBusy_TCP(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	srv.SetBusy(200 * time.Millisecond)
+	if _, err := client.ReadRegisters(0, 1, modbus.HOLDING_REGISTER); !errors.Is(err, modbus.ErrServerDeviceBusy) {
+		t.Fatalf("read during busy window: err = %v, want ErrServerDeviceBusy", err)
+	}
+	if err := client.WriteRegister(0, 1); !errors.Is(err, modbus.ErrServerDeviceBusy) {
+		t.Fatalf("write during busy window: err = %v, want ErrServerDeviceBusy", err)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if _, err := client.ReadRegisters(0, 1, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("read after busy window: %v", err)
+	}
+}
+
+func TestSetBusy_RTU(t *testing.T) {
+	srv := NewModbusServer(DefaultRTUConfig(), NewModbusDataStore(10, 10, 10, 10))
+	p := rtu.NewProcessor(NewRTUDataStoreAdapter(srv.handler))
+
+	srv.SetBusy(time.Hour)
+	busy := rtu.BuildExceptionResponse(1, rtu.FuncReadCoils, rtu.ExceptionSlaveDeviceBusy)
+	if got := processFrame(t, p, 1, rtu.FuncReadCoils, 0, 0, 0, 1); !bytes.Equal(got, busy) {
+		t.Errorf("busy response = % X, want % X", got, busy)
+	}
+	// FC 0x0B はビジー中のステータスワード 0xFFFF を返す
+	if got, want := processFrame(t, p, 1, rtu.FuncGetCommEventCounter), rtu.AppendCRC([]byte{1, 0x0B, 0xFF, 0xFF, 0x00, 0x00}); !bytes.Equal(got, want) {
+		t.Errorf("FC 0x0B during busy = % X, want % X", got, want)
+	}
+
+	srv.SetBusy(0)
+	if got := processFrame(t, p, 1, rtu.FuncReadCoils, 0, 0, 0, 1); !bytes.Equal(got, rtu.BuildReadBitsResponse(1, rtu.FuncReadCoils, []bool{false})) {
+		t.Errorf("response after clearing busy = % X", got)
+	}
+}
@@ -0,0 +1,123 @@
+package modbus
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// clientIdleTimeout は無通信のクライアントを一覧から外すまでの時間。
+// TCP サーバーのアイドル切断時間と同じ値にし、切断済みの接続が残らないようにする。
+const clientIdleTimeout = 120 * time.Second
+
+// clientPruneInterval は Record でタイムアウトしたクライアントを掃除する最短間隔
+const clientPruneInterval = time.Second
+
+// ClientTracker は TCP クライアントのアドレスごとに最終アクティビティを記録する
+type ClientTracker struct {
+	mu        sync.Mutex
+	timeout   time.Duration
+	clients   map[string]protocol.ClientInfo
+	lastPrune time.Time
+	onEvict   func(addr string)
+}
+
+// NewClientTracker は新しい ClientTracker を作成する
+func NewClientTracker(timeout time.Duration) *ClientTracker {
+	return &ClientTracker{
+		timeout: timeout,
+		clients: make(map[string]protocol.ClientInfo),
+	}
+}
+
+// SetOnEvict は一覧から外したクライアント（タイムアウト・切断・Reset）のアドレスを受け取るコールバックを設定する
+func (t *ClientTracker) SetOnEvict(fn func(addr string)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onEvict = fn
+}
+
+// Record はクライアント addr からのリクエストを記録する（addr が空の場合は何もしない）。
+// 一定間隔ごとにタイムアウトしたクライアントもあわせて削除する。
+func (t *ClientTracker) Record(addr string, unitID uint8) {
+	if addr == "" {
+		return
+	}
+	t.mu.Lock()
+	now := time.Now()
+	t.clients[addr] = protocol.ClientInfo{RemoteAddr: addr, UnitID: unitID, LastActivity: now}
+	var evicted []string
+	if now.Sub(t.lastPrune) >= clientPruneInterval {
+		evicted = t.pruneLocked(now)
+	}
+	onEvict := t.onEvict
+	t.mu.Unlock()
+	notifyEvicted(onEvict, evicted)
+}
+
+// Remove は切断されたクライアント addr を一覧から外す
+func (t *ClientTracker) Remove(addr string) {
+	t.mu.Lock()
+	_, ok := t.clients[addr]
+	delete(t.clients, addr)
+	onEvict := t.onEvict
+	t.mu.Unlock()
+	if ok {
+		notifyEvicted(onEvict, []string{addr})
+	}
+}
+
+// Snapshot はタイムアウトしていないクライアントの一覧をアドレス順で返す。
+// タイムアウトしたクライアントはこの時点で削除する。
+func (t *ClientTracker) Snapshot() []protocol.ClientInfo {
+	t.mu.Lock()
+	evicted := t.pruneLocked(time.Now())
+	result := make([]protocol.ClientInfo, 0, len(t.clients))
+	for _, c := range t.clients {
+		result = append(result, c)
+	}
+	onEvict := t.onEvict
+	t.mu.Unlock()
+	notifyEvicted(onEvict, evicted)
+
+	sort.Slice(result, func(i, j int) bool { return result[i].RemoteAddr < result[j].RemoteAddr })
+	return result
+}
+
+// Reset は記録をすべて破棄する
+func (t *ClientTracker) Reset() {
+	t.mu.Lock()
+	evicted := make([]string, 0, len(t.clients))
+	for addr := range t.clients {
+		evicted = append(evicted, addr)
+	}
+	t.clients = make(map[string]protocol.ClientInfo)
+	onEvict := t.onEvict
+	t.mu.Unlock()
+	notifyEvicted(onEvict, evicted)
+}
+
+// pruneLocked はタイムアウトしたクライアントを削除し、そのアドレスを返す（t.mu のロック必須）
+func (t *ClientTracker) pruneLocked(now time.Time) []string {
+	t.lastPrune = now
+	var evicted []string
+	for addr, c := range t.clients {
+		if now.Sub(c.LastActivity) > t.timeout {
+			delete(t.clients, addr)
+			evicted = append(evicted, addr)
+		}
+	}
+	return evicted
+}
+
+// notifyEvicted は一覧から外したアドレスをコールバックに通知する（ロック外で呼び出す）
+func notifyEvicted(onEvict func(addr string), evicted []string) {
+	if onEvict == nil {
+		return
+	}
+	for _, addr := range evicted {
+		onEvict(addr)
+	}
+}
@@ -0,0 +1,121 @@
+package modbus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+var _ protocol.ClientLister = (*ModbusServer)(nil)
+
+func TestModbusServer_GetClients_TCP(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	if clients := srv.GetClients(); len(clients) != 0 {
+		t.Fatalf("clients before connect = %v, want none", clients)
+	}
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	client.SetUnitId(7)
+	before := time.Now()
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("ReadRegister: %v", err)
+	}
+
+	clients := srv.GetClients()
+	if len(clients) != 1 {
+		t.Fatalf("clients = %v, want 1 entry", clients)
+	}
+	c := clients[0]
+	if !strings.HasPrefix(c.RemoteAddr, "127.0.0.1:") {
+		t.Errorf("RemoteAddr = %q, want 127.0.0.1:<port>", c.RemoteAddr)
+	}
+	if c.UnitID != 7 {
+		t.Errorf("UnitID = %d, want 7", c.UnitID)
+	}
+	if c.LastActivity.Before(before) {
+		t.Errorf("LastActivity = %v, want after %v", c.LastActivity, before)
+	}
+
+	// 停止すると一覧は空になる
+	srv.Stop()
+	if clients := srv.GetClients(); len(clients) != 0 {
+		t.Errorf("clients after stop = %v, want none", clients)
+	}
+}
+
+func TestClientTracker_ExpiresIdleClients(t *testing.T) {
+	tracker := NewClientTracker(50 * time.Millisecond)
+	tracker.Record("10.0.0.2:5000", 1)
+	tracker.Record("10.0.0.1:5000", 2)
+	tracker.Record("", 3) // アドレス不明（RTU 等）は記録しない
+
+	clients := tracker.Snapshot()
+	if len(clients) != 2 || clients[0].RemoteAddr != "10.0.0.1:5000" || clients[1].RemoteAddr != "10.0.0.2:5000" {
+		t.Fatalf("clients = %v, want 2 entries sorted by address", clients)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	tracker.Record("10.0.0.2:5000", 4)
+	clients = tracker.Snapshot()
+	if len(clients) != 1 || clients[0].RemoteAddr != "10.0.0.2:5000" || clients[0].UnitID != 4 {
+		t.Errorf("clients after timeout = %v, want only 10.0.0.2:5000 with unit 4", clients)
+	}
+}
+
+func TestClientTracker_NotifiesEvictedClients(t *testing.T) {
+	tracker := NewClientTracker(50 * time.Millisecond)
+	var evicted []string
+	tracker.SetOnEvict(func(addr string) { evicted = append(evicted, addr) })
+
+	tracker.Record("10.0.0.1:5000", 1)
+	tracker.Record("10.0.0.2:5000", 1)
+	tracker.Remove("10.0.0.1:5000")
+	tracker.Remove("10.0.0.9:5000") // 記録のないアドレスは通知しない
+	if len(evicted) != 1 || evicted[0] != "10.0.0.1:5000" {
+		t.Fatalf("evicted after Remove = %v, want [10.0.0.1:5000]", evicted)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	tracker.Snapshot()
+	if len(evicted) != 2 || evicted[1] != "10.0.0.2:5000" {
+		t.Fatalf("evicted after timeout = %v, want 10.0.0.2:5000 appended", evicted)
+	}
+
+	tracker.Record("10.0.0.3:5000", 1)
+	tracker.Reset()
+	if len(evicted) != 3 || evicted[2] != "10.0.0.3:5000" {
+		t.Errorf("evicted after Reset = %v, want 10.0.0.3:5000 appended", evicted)
+	}
+}
+
+func TestDataStoreHandler_ForgetsRateLimitOfEvictedClients(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(10, 10, 10, 10))
+	handler.rateLimiter.SetLimit(100, false)
+
+	handler.clients.Record("10.0.0.1:5000", 1)
+	if err := handler.rateLimiter.Wait("10.0.0.1:5000"); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	handler.clients.Remove("10.0.0.1:5000")
+
+	handler.rateLimiter.mu.Lock()
+	_, ok := handler.rateLimiter.buckets["10.0.0.1:5000"]
+	handler.rateLimiter.mu.Unlock()
+	if ok {
+		t.Error("expected rate limit bucket to be forgotten after the client was removed")
+	}
+}
@@ -0,0 +1,53 @@
+package modbus
+
+import "sync/atomic"
+
+// commEventCounter は正常に応答したリクエスト数（Modbus のイベントカウンタ）を数える。
+// FC 0x0B の仕様どおり 16bit で折り返す。
+type commEventCounter struct {
+	count atomic.Uint32
+}
+
+// RecordCommEvent は正常に応答したリクエストを1件数える
+func (h *DataStoreHandler) RecordCommEvent() {
+	h.commEvents.count.Add(1)
+}
+
+// CommEventCount はイベントカウンタの値を返す
+func (h *DataStoreHandler) CommEventCount() uint16 {
+	return uint16(h.commEvents.count.Load())
+}
+
+// ClearCommEventCounter はイベントカウンタを 0 に戻す
+func (h *DataStoreHandler) ClearCommEventCounter() {
+	h.commEvents.count.Store(0)
+}
+
+// RecordCommEvent は正常に応答したリクエストを1件数える
+func (a *RTUDataStoreAdapter) RecordCommEvent() {
+	a.handler.RecordCommEvent()
+}
+
+// CommEventCounter はステータスワード（ビジー期間中は 0xFFFF、それ以外は 0x0000）とイベントカウンタを返す (FC 0x0B)
+func (a *RTUDataStoreAdapter) CommEventCounter() (status, count uint16) {
+	if a.handler.IsBusy() {
+		status = 0xFFFF
+	}
+	return status, a.handler.CommEventCount()
+}
+
+// ClearCommEventCounter はイベントカウンタをクリアする (FC 0x08 サブファンクション 0x0A)
+func (a *RTUDataStoreAdapter) ClearCommEventCounter() {
+	a.handler.ClearCommEventCounter()
+}
+
+// CommEventCount はマスターからのリクエストのうち正常に応答した数を返す（FC 0x0B のイベントカウンタ）
+func (s *ModbusServer) CommEventCount() (uint16, error) {
+	return s.handler.CommEventCount(), nil
+}
+
+// ClearCommEventCounter はイベントカウンタを 0 に戻す
+func (s *ModbusServer) ClearCommEventCounter() error {
+	s.handler.ClearCommEventCounter()
+	return nil
+}
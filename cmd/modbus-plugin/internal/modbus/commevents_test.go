@@ -0,0 +1,88 @@
+package modbus
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+var _ protocol.CommEventCounter = (*ModbusServer)(nil)
+
+// processFrame はリクエストフレームを Processor で処理する
+func processFrame(t *testing.T, p *rtu.Processor, pdu ...byte) []byte {
+	t.Helper()
+	req, err := rtu.ParseRequest(rtu.AppendCRC(pdu))
+	if err != nil {
+		t.Fatalf("ParseRequest(% X): %v", pdu, err)
+	}
+	return p.Process(req)
+}
+
+func TestCommEventCounter_RTU(t *testing.T) {
+	srv := NewModbusServer(DefaultRTUConfig(), NewModbusDataStore(10, 10, 10, 10))
+	p := rtu.NewProcessor(NewRTUDataStoreAdapter(srv.handler))
+
+	processFrame(t, p, 1, rtu.FuncReadHoldingRegisters, 0, 0, 0, 2)
+	processFrame(t, p, 1, rtu.FuncWriteSingleRegister, 0, 1, 0x12, 0x34)
+	// 例外応答とブロードキャストは数えない
+	processFrame(t, p, 1, rtu.FuncReadHoldingRegisters, 0, 9, 0, 2)
+	processFrame(t, p, 0, rtu.FuncWriteSingleRegister, 0, 1, 0, 0)
+
+	got := processFrame(t, p, 1, rtu.FuncGetCommEventCounter)
+	if want := rtu.AppendCRC([]byte{1, 0x0B, 0x00, 0x00, 0x00, 0x02}); !bytes.Equal(got, want) {
+		t.Errorf("FC 0x0B response = % X, want % X", got, want)
+	}
+	// FC 0x0B 自身も数えない
+	if n, _ := srv.CommEventCount(); n != 2 {
+		t.Errorf("CommEventCount = %d, want 2", n)
+	}
+
+	// FC 0x08 サブファンクション 0x0A でクリア（正常応答はエコー）
+	clearReq := []byte{1, rtu.FuncDiagnostics, 0x00, 0x0A, 0x00, 0x00}
+	if got := processFrame(t, p, clearReq...); !bytes.Equal(got, rtu.AppendCRC(clearReq)) {
+		t.Errorf("clear response = % X", got)
+	}
+	got = processFrame(t, p, 1, rtu.FuncGetCommEventCounter)
+	if want := rtu.AppendCRC([]byte{1, 0x0B, 0x00, 0x00, 0x00, 0x00}); !bytes.Equal(got, want) {
+		t.Errorf("after clear = % X, want % X", got, want)
+	}
+}
+
+func TestCommEventCounter_TCP(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	for i := 0; i < 3; i++ {
+		if _, err := client.ReadRegisters(0, 1, modbus.INPUT_REGISTER); err != nil {
+			t.Fatalf("ReadRegisters: %v", err)
+		}
+		if n, _ := srv.CommEventCount(); n != uint16(i+1) {
+			t.Fatalf("after %d requests: CommEventCount = %d", i+1, n)
+		}
+	}
+	if _, err := client.ReadRegisters(9, 2, modbus.INPUT_REGISTER); err == nil {
+		t.Fatal("expected out-of-range read to fail")
+	}
+	if n, _ := srv.CommEventCount(); n != 3 {
+		t.Errorf("exception counted: CommEventCount = %d, want 3", n)
+	}
+
+	srv.ClearCommEventCounter()
+	if n, _ := srv.CommEventCount(); n != 0 {
+		t.Errorf("after clear: CommEventCount = %d", n)
+	}
+}
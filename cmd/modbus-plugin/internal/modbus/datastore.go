@@ -10,7 +10,7 @@ import (
 // DataChangeHook はデータ変更時に呼ばれるコールバック型
 // プラグインサーバーが SubscribeChanges ストリームで変更通知を送るために使用する。
 // isBit=true の場合は bitValues を、isBit=false の場合は values を参照する。
-type DataChangeHook func(area string, address uint32, values []uint16, isBit bool, bitValues []bool)
+type DataChangeHook = func(area string, address uint32, values []uint16, isBit bool, bitValues []bool)
 
 // ModbusDataStore はModbusプロトコル用のデータストア
 type ModbusDataStore struct {
@@ -19,6 +19,9 @@ type ModbusDataStore struct {
 	discreteInputs []bool
 	holdingRegs    []uint16
 	inputRegs      []uint16
+	rangePolicy    datastore.OutOfRangePolicy
+	visibleAreas   []string // GetAreas で返すエリアと順序（空の場合は AreaOrder）
+	readOnlyAreas  map[string]bool
 
 	hookMu     sync.RWMutex
 	changeHook DataChangeHook
@@ -61,47 +64,77 @@ func NewModbusDataStore(coilCount, discreteCount, holdingCount, inputCount int)
 	}
 }
 
-// GetAreas は利用可能なメモリエリアの一覧を返す
+// GetAreas は利用可能なメモリエリアの一覧を返す。
+// 順序は SetVisibleAreas で指定した順（デフォルトは AreaOrder）。
 func (s *ModbusDataStore) GetAreas() []protocol.MemoryArea {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return []protocol.MemoryArea{
-		{
+	all := s.areaDefinitions()
+	ids := s.visibleAreaIDs()
+	areas := make([]protocol.MemoryArea, 0, len(ids))
+	for _, id := range ids {
+		areas = append(areas, all[id])
+	}
+	return areas
+}
+
+// GetArea は指定IDのメモリエリアを返す。SetVisibleAreas で非表示にしたエリアは返さない。
+func (s *ModbusDataStore) GetArea(id string) (protocol.MemoryArea, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, visible := range s.visibleAreaIDs() {
+		if visible == id {
+			return s.areaDefinitions()[id], true
+		}
+	}
+	return protocol.MemoryArea{}, false
+}
+
+// areaDefinitions は全エリアの定義を返す（s.mu 保持前提）
+func (s *ModbusDataStore) areaDefinitions() map[string]protocol.MemoryArea {
+	return map[string]protocol.MemoryArea{
+		AreaCoils: {
 			ID:          AreaCoils,
 			DisplayName: "コイル (0x)",
 			IsBit:       true,
 			Size:        uint32(len(s.coils)),
-			ReadOnly:    false,
+			ReadOnly:    s.readOnlyAreas[AreaCoils],
 			OneOrigin:   true,
 		},
-		{
+		AreaDiscreteInputs: {
 			ID:          AreaDiscreteInputs,
 			DisplayName: "ディスクリート入力 (1x)",
 			IsBit:       true,
 			Size:        uint32(len(s.discreteInputs)),
-			ReadOnly:    false, // シミュレーターなので書き込み可能
+			ReadOnly:    s.readOnlyAreas[AreaDiscreteInputs],
 			OneOrigin:   true,
 		},
-		{
+		AreaHoldingRegs: {
 			ID:          AreaHoldingRegs,
 			DisplayName: "保持レジスタ (4x)",
 			IsBit:       false,
 			Size:        uint32(len(s.holdingRegs)),
-			ReadOnly:    false,
+			ReadOnly:    s.readOnlyAreas[AreaHoldingRegs],
 			OneOrigin:   true,
 		},
-		{
+		AreaInputRegs: {
 			ID:          AreaInputRegs,
 			DisplayName: "入力レジスタ (3x)",
 			IsBit:       false,
 			Size:        uint32(len(s.inputRegs)),
-			ReadOnly:    false, // シミュレーターなので書き込み可能
+			ReadOnly:    s.readOnlyAreas[AreaInputRegs],
 			OneOrigin:   true,
 		},
 	}
 }
 
+// checkRange は address から count 点が点数 size のエリアに収まるかを検査する
+func checkRange(size int, address uint32, count int) error {
+	return datastore.AddrRange{Address: address, Count: uint32(count)}.Validate(uint32(size))
+}
+
 // ReadBit はビット値を読み込む
 func (s *ModbusDataStore) ReadBit(area string, address uint32) (bool, error) {
 	s.mu.RLock()
@@ -109,13 +142,13 @@ func (s *ModbusDataStore) ReadBit(area string, address uint32) (bool, error) {
 
 	switch area {
 	case AreaCoils:
-		if int(address) >= len(s.coils) {
-			return false, datastore.ErrAddressOutOfRange
+		if err := checkRange(len(s.coils), address, 1); err != nil {
+			return false, err
 		}
 		return s.coils[address], nil
 	case AreaDiscreteInputs:
-		if int(address) >= len(s.discreteInputs) {
-			return false, datastore.ErrAddressOutOfRange
+		if err := checkRange(len(s.discreteInputs), address, 1); err != nil {
+			return false, err
 		}
 		return s.discreteInputs[address], nil
 	default:
@@ -128,15 +161,15 @@ func (s *ModbusDataStore) WriteBit(area string, address uint32, value bool) erro
 	s.mu.Lock()
 	switch area {
 	case AreaCoils:
-		if int(address) >= len(s.coils) {
+		if err := checkRange(len(s.coils), address, 1); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		s.coils[address] = value
 	case AreaDiscreteInputs:
-		if int(address) >= len(s.discreteInputs) {
+		if err := checkRange(len(s.discreteInputs), address, 1); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		s.discreteInputs[address] = value
 	default:
@@ -148,26 +181,33 @@ func (s *ModbusDataStore) WriteBit(area string, address uint32, value bool) erro
 	return nil
 }
 
-// ReadBits は複数のビット値を読み込む
+// SetOutOfRangePolicy は ReadBits/ReadWords で範囲外を読み取った場合の動作を設定する
+func (s *ModbusDataStore) SetOutOfRangePolicy(policy datastore.OutOfRangePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rangePolicy = policy
+}
+
+// OutOfRangePolicy は範囲外読み取り時の動作を返す
+func (s *ModbusDataStore) OutOfRangePolicy() datastore.OutOfRangePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.rangePolicy == "" {
+		return datastore.OutOfRangeError
+	}
+	return s.rangePolicy
+}
+
+// ReadBits は複数のビット値を読み込む（範囲外の扱いは OutOfRangePolicy に従う）
 func (s *ModbusDataStore) ReadBits(area string, address uint32, count uint16) ([]bool, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	switch area {
 	case AreaCoils:
-		if int(address)+int(count) > len(s.coils) {
-			return nil, datastore.ErrAddressOutOfRange
-		}
-		result := make([]bool, count)
-		copy(result, s.coils[address:address+uint32(count)])
-		return result, nil
+		return datastore.ReadRange(s.coils, address, count, s.rangePolicy)
 	case AreaDiscreteInputs:
-		if int(address)+int(count) > len(s.discreteInputs) {
-			return nil, datastore.ErrAddressOutOfRange
-		}
-		result := make([]bool, count)
-		copy(result, s.discreteInputs[address:address+uint32(count)])
-		return result, nil
+		return datastore.ReadRange(s.discreteInputs, address, count, s.rangePolicy)
 	default:
 		return nil, datastore.ErrAreaNotFound
 	}
@@ -178,15 +218,15 @@ func (s *ModbusDataStore) WriteBits(area string, address uint32, values []bool)
 	s.mu.Lock()
 	switch area {
 	case AreaCoils:
-		if int(address)+len(values) > len(s.coils) {
+		if err := checkRange(len(s.coils), address, len(values)); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		copy(s.coils[address:], values)
 	case AreaDiscreteInputs:
-		if int(address)+len(values) > len(s.discreteInputs) {
+		if err := checkRange(len(s.discreteInputs), address, len(values)); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		copy(s.discreteInputs[address:], values)
 	default:
@@ -205,13 +245,13 @@ func (s *ModbusDataStore) ReadWord(area string, address uint32) (uint16, error)
 
 	switch area {
 	case AreaHoldingRegs:
-		if int(address) >= len(s.holdingRegs) {
-			return 0, datastore.ErrAddressOutOfRange
+		if err := checkRange(len(s.holdingRegs), address, 1); err != nil {
+			return 0, err
 		}
 		return s.holdingRegs[address], nil
 	case AreaInputRegs:
-		if int(address) >= len(s.inputRegs) {
-			return 0, datastore.ErrAddressOutOfRange
+		if err := checkRange(len(s.inputRegs), address, 1); err != nil {
+			return 0, err
 		}
 		return s.inputRegs[address], nil
 	default:
@@ -224,15 +264,15 @@ func (s *ModbusDataStore) WriteWord(area string, address uint32, value uint16) e
 	s.mu.Lock()
 	switch area {
 	case AreaHoldingRegs:
-		if int(address) >= len(s.holdingRegs) {
+		if err := checkRange(len(s.holdingRegs), address, 1); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		s.holdingRegs[address] = value
 	case AreaInputRegs:
-		if int(address) >= len(s.inputRegs) {
+		if err := checkRange(len(s.inputRegs), address, 1); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		s.inputRegs[address] = value
 	default:
@@ -244,26 +284,16 @@ func (s *ModbusDataStore) WriteWord(area string, address uint32, value uint16) e
 	return nil
 }
 
-// ReadWords は複数のワード値を読み込む
+// ReadWords は複数のワード値を読み込む（範囲外の扱いは OutOfRangePolicy に従う）
 func (s *ModbusDataStore) ReadWords(area string, address uint32, count uint16) ([]uint16, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	switch area {
 	case AreaHoldingRegs:
-		if int(address)+int(count) > len(s.holdingRegs) {
-			return nil, datastore.ErrAddressOutOfRange
-		}
-		result := make([]uint16, count)
-		copy(result, s.holdingRegs[address:address+uint32(count)])
-		return result, nil
+		return datastore.ReadRange(s.holdingRegs, address, count, s.rangePolicy)
 	case AreaInputRegs:
-		if int(address)+int(count) > len(s.inputRegs) {
-			return nil, datastore.ErrAddressOutOfRange
-		}
-		result := make([]uint16, count)
-		copy(result, s.inputRegs[address:address+uint32(count)])
-		return result, nil
+		return datastore.ReadRange(s.inputRegs, address, count, s.rangePolicy)
 	default:
 		return nil, datastore.ErrAreaNotFound
 	}
@@ -274,15 +304,15 @@ func (s *ModbusDataStore) WriteWords(area string, address uint32, values []uint1
 	s.mu.Lock()
 	switch area {
 	case AreaHoldingRegs:
-		if int(address)+len(values) > len(s.holdingRegs) {
+		if err := checkRange(len(s.holdingRegs), address, len(values)); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		copy(s.holdingRegs[address:], values)
 	case AreaInputRegs:
-		if int(address)+len(values) > len(s.inputRegs) {
+		if err := checkRange(len(s.inputRegs), address, len(values)); err != nil {
 			s.mu.Unlock()
-			return datastore.ErrAddressOutOfRange
+			return err
 		}
 		copy(s.inputRegs[address:], values)
 	default:
@@ -320,12 +350,13 @@ func (s *ModbusDataStore) Snapshot() map[string]interface{} {
 }
 
 // Restore はスナップショットからデータを復元する
+// gRPC 経由の場合は JSON デコード後の []interface{} が渡されるため、それも受け付ける
 func (s *ModbusDataStore) Restore(data map[string]interface{}) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if coils, ok := data[AreaCoils]; ok {
-		if bools, ok := coils.([]bool); ok {
+		if bools, ok := datastore.SnapshotBits(coils); ok {
 			count := len(bools)
 			if count > len(s.coils) {
 				count = len(s.coils)
@@ -335,7 +366,7 @@ func (s *ModbusDataStore) Restore(data map[string]interface{}) error {
 	}
 
 	if discreteInputs, ok := data[AreaDiscreteInputs]; ok {
-		if bools, ok := discreteInputs.([]bool); ok {
+		if bools, ok := datastore.SnapshotBits(discreteInputs); ok {
 			count := len(bools)
 			if count > len(s.discreteInputs) {
 				count = len(s.discreteInputs)
@@ -345,7 +376,7 @@ func (s *ModbusDataStore) Restore(data map[string]interface{}) error {
 	}
 
 	if holdingRegs, ok := data[AreaHoldingRegs]; ok {
-		if words, ok := holdingRegs.([]uint16); ok {
+		if words, ok := datastore.SnapshotWords(holdingRegs); ok {
 			count := len(words)
 			if count > len(s.holdingRegs) {
 				count = len(s.holdingRegs)
@@ -355,7 +386,7 @@ func (s *ModbusDataStore) Restore(data map[string]interface{}) error {
 	}
 
 	if inputRegs, ok := data[AreaInputRegs]; ok {
-		if words, ok := inputRegs.([]uint16); ok {
+		if words, ok := datastore.SnapshotWords(inputRegs); ok {
 			count := len(words)
 			if count > len(s.inputRegs) {
 				count = len(s.inputRegs)
@@ -386,6 +417,58 @@ func (s *ModbusDataStore) ClearAll() {
 	}
 }
 
+// SnapshotBinary は全エリアのデータを gzip 圧縮したバイナリ形式で返す。
+// 大きなメモリでは JSON の Snapshot より大幅に小さくなる。
+func (s *ModbusDataStore) SnapshotBinary() []byte {
+	return datastore.EncodeSnapshotBinary(s.Snapshot())
+}
+
+// RestoreBinary は SnapshotBinary の出力からデータを復元する
+func (s *ModbusDataStore) RestoreBinary(data []byte) error {
+	snap, err := datastore.DecodeSnapshotBinary(data)
+	if err != nil {
+		return err
+	}
+	return s.Restore(snap)
+}
+
+// SnapshotSparse は全エリアのデータを 0 以外の値が連続する範囲（ラン）のみのスパース形式で返す。
+// ほとんどが 0 の大きなメモリでは Snapshot より大幅に小さくなる。
+func (s *ModbusDataStore) SnapshotSparse() datastore.SparseSnapshot {
+	// Snapshot は型付きスライスのみを返すため変換は失敗しない
+	sparse, _ := datastore.SparseFromSnapshot(s.Snapshot())
+	return sparse
+}
+
+// RestoreSparse は SnapshotSparse の出力からデータを復元する（ランに含まれないアドレスは 0 になる）
+func (s *ModbusDataStore) RestoreSparse(sparse datastore.SparseSnapshot) error {
+	snap, err := sparse.Expand()
+	if err != nil {
+		return err
+	}
+	return s.Restore(snap)
+}
+
+// DeltaSince は baseline（Snapshot の出力）から変更されたアドレスの値のみを返す。
+// 形式は datastore.SnapshotDelta を参照。
+func (s *ModbusDataStore) DeltaSince(baseline map[string]interface{}) map[string]interface{} {
+	delta, err := datastore.SnapshotDelta(baseline, s.Snapshot())
+	if err != nil {
+		// baseline が不正な場合は全体を差分とする
+		delta, _ = datastore.SnapshotDelta(nil, s.Snapshot())
+	}
+	return delta
+}
+
+// ApplyDelta は DeltaSince の出力を現在のデータに適用する
+func (s *ModbusDataStore) ApplyDelta(delta map[string]interface{}) error {
+	snap, err := datastore.ApplySnapshotDelta(s.Snapshot(), delta)
+	if err != nil {
+		return err
+	}
+	return s.Restore(snap)
+}
+
 // === 旧RegisterStoreとの互換性のためのメソッド ===
 
 // GetCoil はコイルの値を取得する
@@ -1,22 +1,71 @@
 package modbus
 
 import (
+	"sync/atomic"
+
 	"modbus_simulator/internal/domain/protocol"
 	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
 
 	"github.com/simonvetter/modbus"
 )
 
+// emitterRef は実行中に差し替えられるイベントエミッターへの参照。
+// サーバーとリクエストハンドラーで共有し、起動後の SetEventEmitter を処理中のハンドラーへ反映する。
+type emitterRef struct {
+	p atomic.Pointer[emitterBox]
+}
+
+// emitterBox は nil を含むエミッターを atomic.Pointer で扱うための入れ物
+type emitterBox struct {
+	emitter protocol.CommunicationEventEmitter
+}
+
+// Load は現在のエミッターを返す（未設定なら nil）
+func (r *emitterRef) Load() protocol.CommunicationEventEmitter {
+	if box := r.p.Load(); box != nil {
+		return box.emitter
+	}
+	return nil
+}
+
+// Store はエミッターを差し替える
+func (r *emitterRef) Store(emitter protocol.CommunicationEventEmitter) {
+	r.p.Store(&emitterBox{emitter: emitter})
+}
+
+// emitRxTx は受信・送信イベントを発行する
+func (r *emitterRef) emitRxTx() {
+	if emitter := r.Load(); emitter != nil {
+		emitter.EmitRx()
+		emitter.EmitTx()
+	}
+}
+
+// recordRequest は応答したリクエストのファンクションコードと例外応答の有無を、
+// 現在のエミッターが protocol.RequestRecorder を実装していれば記録する
+func (r *emitterRef) recordRequest(functionCode byte, exception bool) {
+	if recorder, ok := r.Load().(protocol.RequestRecorder); ok {
+		recorder.RecordRequest(functionCode, exception)
+	}
+}
+
+// EmitConnection は接続数の変化を現在のエミッターに通知する（rtu.ConnectionEmitter）
+func (r *emitterRef) EmitConnection(count int) {
+	if emitter := r.Load(); emitter != nil {
+		emitter.EmitConnection(count)
+	}
+}
+
 // DataStoreRequestHandler はDataStoreHandlerをsimonvetter/modbusのRequestHandlerに適合させるアダプター
 type DataStoreRequestHandler struct {
 	handler        *DataStoreHandler
 	sessionManager *protocol.SessionManager
-	eventEmitter   protocol.CommunicationEventEmitter
+	emitter        *emitterRef
 }
 
 // NewDataStoreRequestHandler は新しいDataStoreRequestHandlerを作成する
 func NewDataStoreRequestHandler(handler *DataStoreHandler) *DataStoreRequestHandler {
-	return &DataStoreRequestHandler{handler: handler}
+	return &DataStoreRequestHandler{handler: handler, emitter: &emitterRef{}}
 }
 
 // SetSessionManager はセッションマネージャーを設定する
@@ -24,111 +73,200 @@ func (h *DataStoreRequestHandler) SetSessionManager(manager *protocol.SessionMan
 	h.sessionManager = manager
 }
 
-// SetEventEmitter はイベントエミッターを設定する
+// SetEventEmitter はイベントエミッターを設定する（処理中のリクエストにも反映される）
 func (h *DataStoreRequestHandler) SetEventEmitter(emitter protocol.CommunicationEventEmitter) {
-	h.eventEmitter = emitter
+	h.emitter.Store(emitter)
 }
 
-// emitRxTx は受信・送信イベントを発行する
-func (h *DataStoreRequestHandler) emitRxTx(unitID uint8) {
+// emitRxTx は受信・送信イベントを発行し、クライアントのアクティビティを記録する
+func (h *DataStoreRequestHandler) emitRxTx(unitID uint8, clientAddr string) {
+	h.handler.clients.Record(clientAddr, unitID)
 	if h.sessionManager != nil {
 		h.sessionManager.RecordActivityWithUnitID(unitID)
 	}
-	if h.eventEmitter != nil {
-		h.eventEmitter.EmitRx()
-		h.eventEmitter.EmitTx()
+	h.emitter.emitRxTx()
+}
+
+// throttle はビジー期間と接続ごとのレート制限を適用する（ビジー期間中や busy モードで超過した場合は例外 0x06）
+func (h *DataStoreRequestHandler) throttle(clientAddr string) error {
+	if h.handler.IsBusy() {
+		return modbus.ErrServerDeviceBusy
+	}
+	if err := h.handler.rateLimiter.Wait(clientAddr); err != nil {
+		return modbus.ErrServerDeviceBusy
 	}
+	return nil
 }
 
 // HandleCoils はコイル読み取りを処理する (Function Code 1)
 func (h *DataStoreRequestHandler) HandleCoils(req *modbus.CoilsRequest) ([]bool, error) {
-	h.emitRxTx(req.UnitId)
+	h.emitRxTx(req.UnitId, req.ClientAddr)
 	if !h.handler.IsUnitIdEnabled(req.UnitId) {
 		return nil, modbus.ErrIllegalFunction
 	}
-	return h.handler.store.ReadBits(AreaCoils, uint32(req.Addr), req.Quantity)
+	if err := h.throttle(req.ClientAddr); err != nil {
+		return nil, err
+	}
+	if !req.IsWrite && !h.handler.quantityLimits.Allowed(req.UnitId, rtu.FuncReadCoils, req.Quantity) {
+		return nil, modbus.ErrIllegalDataValue
+	}
+	values, err := h.handler.StoreForUnit(req.UnitId).ReadBits(AreaCoils, uint32(req.Addr), req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if !req.IsWrite {
+		h.handler.access.RecordRead(AreaCoils, uint32(req.Addr), int(req.Quantity))
+	}
+	h.handler.RecordCommEvent()
+	return values, nil
 }
 
 // HandleDiscreteInputs はディスクリート入力読み取りを処理する (Function Code 2)
 func (h *DataStoreRequestHandler) HandleDiscreteInputs(req *modbus.DiscreteInputsRequest) ([]bool, error) {
-	h.emitRxTx(req.UnitId)
+	h.emitRxTx(req.UnitId, req.ClientAddr)
 	if !h.handler.IsUnitIdEnabled(req.UnitId) {
 		return nil, modbus.ErrIllegalFunction
 	}
-	return h.handler.store.ReadBits(AreaDiscreteInputs, uint32(req.Addr), req.Quantity)
+	if err := h.throttle(req.ClientAddr); err != nil {
+		return nil, err
+	}
+	if !h.handler.quantityLimits.Allowed(req.UnitId, rtu.FuncReadDiscreteInputs, req.Quantity) {
+		return nil, modbus.ErrIllegalDataValue
+	}
+	values, err := h.handler.StoreForUnit(req.UnitId).ReadBits(AreaDiscreteInputs, uint32(req.Addr), req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	h.handler.access.RecordRead(AreaDiscreteInputs, uint32(req.Addr), int(req.Quantity))
+	h.handler.RecordCommEvent()
+	return values, nil
 }
 
 // HandleHoldingRegisters は保持レジスタ読み取りを処理する (Function Code 3)
 func (h *DataStoreRequestHandler) HandleHoldingRegisters(req *modbus.HoldingRegistersRequest) ([]uint16, error) {
-	h.emitRxTx(req.UnitId)
+	h.emitRxTx(req.UnitId, req.ClientAddr)
 	if !h.handler.IsUnitIdEnabled(req.UnitId) {
 		return nil, modbus.ErrIllegalFunction
 	}
+	if err := h.throttle(req.ClientAddr); err != nil {
+		return nil, err
+	}
+	if !req.IsWrite && !h.handler.quantityLimits.Allowed(req.UnitId, rtu.FuncReadHoldingRegisters, req.Quantity) {
+		return nil, modbus.ErrIllegalDataValue
+	}
 
 	if req.IsWrite {
 		// 書き込みリクエスト (Function Code 6, 16)
-		if err := h.handler.store.WriteWords(AreaHoldingRegs, uint32(req.Addr), req.Args); err != nil {
+		if h.handler.isReadOnly(AreaHoldingRegs) {
 			return nil, modbus.ErrIllegalDataAddress
 		}
+		args := h.handler.wordSwap.apply(AreaHoldingRegs, req.Args)
+		if err := h.handler.StoreForUnit(req.UnitId).WriteWords(AreaHoldingRegs, uint32(req.Addr), args); err != nil {
+			return nil, modbus.ErrIllegalDataAddress
+		}
+		h.handler.access.RecordWrite(AreaHoldingRegs, uint32(req.Addr), len(args))
+		h.handler.RecordCommEvent()
 		return req.Args, nil
 	}
 
 	// 読み取りリクエスト
-	return h.handler.store.ReadWords(AreaHoldingRegs, uint32(req.Addr), req.Quantity)
+	values, err := h.handler.StoreForUnit(req.UnitId).ReadWords(AreaHoldingRegs, uint32(req.Addr), req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	h.handler.access.RecordRead(AreaHoldingRegs, uint32(req.Addr), int(req.Quantity))
+	h.handler.RecordCommEvent()
+	return h.handler.wordSwap.apply(AreaHoldingRegs, values), nil
 }
 
 // HandleInputRegisters は入力レジスタ読み取りを処理する (Function Code 4)
 func (h *DataStoreRequestHandler) HandleInputRegisters(req *modbus.InputRegistersRequest) ([]uint16, error) {
-	h.emitRxTx(req.UnitId)
+	h.emitRxTx(req.UnitId, req.ClientAddr)
 	if !h.handler.IsUnitIdEnabled(req.UnitId) {
 		return nil, modbus.ErrIllegalFunction
 	}
-	return h.handler.store.ReadWords(AreaInputRegs, uint32(req.Addr), req.Quantity)
+	if err := h.throttle(req.ClientAddr); err != nil {
+		return nil, err
+	}
+	if !h.handler.quantityLimits.Allowed(req.UnitId, rtu.FuncReadInputRegisters, req.Quantity) {
+		return nil, modbus.ErrIllegalDataValue
+	}
+	values, err := h.handler.StoreForUnit(req.UnitId).ReadWords(AreaInputRegs, uint32(req.Addr), req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	h.handler.access.RecordRead(AreaInputRegs, uint32(req.Addr), int(req.Quantity))
+	h.handler.RecordCommEvent()
+	return h.handler.wordSwap.apply(AreaInputRegs, values), nil
 }
 
 // HandleWriteSingleCoil は単一コイル書き込みを処理する (Function Code 5)
 func (h *DataStoreRequestHandler) HandleWriteSingleCoil(req *modbus.CoilsRequest) error {
-	h.emitRxTx(req.UnitId)
+	h.emitRxTx(req.UnitId, req.ClientAddr)
 	if !h.handler.IsUnitIdEnabled(req.UnitId) {
 		return modbus.ErrIllegalFunction
 	}
+	if err := h.throttle(req.ClientAddr); err != nil {
+		return err
+	}
 	if len(req.Args) == 0 {
 		return modbus.ErrIllegalDataValue
 	}
-	return h.handler.store.WriteBit(AreaCoils, uint32(req.Addr), req.Args[0])
+	if h.handler.isReadOnly(AreaCoils) {
+		return modbus.ErrIllegalDataAddress
+	}
+	if err := h.handler.StoreForUnit(req.UnitId).WriteBit(AreaCoils, uint32(req.Addr), req.Args[0]); err != nil {
+		return err
+	}
+	h.handler.access.RecordWrite(AreaCoils, uint32(req.Addr), 1)
+	h.handler.RecordCommEvent()
+	return nil
 }
 
 // HandleWriteMultipleCoils は複数コイル書き込みを処理する (Function Code 15)
 func (h *DataStoreRequestHandler) HandleWriteMultipleCoils(req *modbus.CoilsRequest) error {
-	h.emitRxTx(req.UnitId)
+	h.emitRxTx(req.UnitId, req.ClientAddr)
 	if !h.handler.IsUnitIdEnabled(req.UnitId) {
 		return modbus.ErrIllegalFunction
 	}
-	return h.handler.store.WriteBits(AreaCoils, uint32(req.Addr), req.Args)
+	if err := h.throttle(req.ClientAddr); err != nil {
+		return err
+	}
+	if h.handler.isReadOnly(AreaCoils) {
+		return modbus.ErrIllegalDataAddress
+	}
+	if err := h.handler.StoreForUnit(req.UnitId).WriteBits(AreaCoils, uint32(req.Addr), req.Args); err != nil {
+		return err
+	}
+	h.handler.access.RecordWrite(AreaCoils, uint32(req.Addr), len(req.Args))
+	h.handler.RecordCommEvent()
+	return nil
 }
 
 // RTUDataStoreAdapter はDataStoreHandlerをrtu.RequestHandlerに適合させるアダプター
 type RTUDataStoreAdapter struct {
-	handler      *DataStoreHandler
-	eventEmitter protocol.CommunicationEventEmitter
+	handler *DataStoreHandler
+	emitter *emitterRef
 }
 
 // NewRTUDataStoreAdapter は新しいRTUDataStoreAdapterを作成する
 func NewRTUDataStoreAdapter(handler *DataStoreHandler) *RTUDataStoreAdapter {
-	return &RTUDataStoreAdapter{handler: handler}
+	return &RTUDataStoreAdapter{handler: handler, emitter: &emitterRef{}}
 }
 
-// SetEventEmitter はイベントエミッターを設定する
+// SetEventEmitter はイベントエミッターを設定する（処理中のリクエストにも反映される）
 func (a *RTUDataStoreAdapter) SetEventEmitter(emitter protocol.CommunicationEventEmitter) {
-	a.eventEmitter = emitter
+	a.emitter.Store(emitter)
 }
 
 // emitRxTx は受信・送信イベントを発行する
 func (a *RTUDataStoreAdapter) emitRxTx() {
-	if a.eventEmitter != nil {
-		a.eventEmitter.EmitRx()
-		a.eventEmitter.EmitTx()
-	}
+	a.emitter.emitRxTx()
+}
+
+// RecordRequest は応答したリクエストを通信統計に記録する（rtu.RequestRecorder）
+func (a *RTUDataStoreAdapter) RecordRequest(functionCode byte, exception bool) {
+	a.emitter.recordRequest(functionCode, exception)
 }
 
 // HandleReadCoils はコイル読み取りを処理する (FC 01)
@@ -137,10 +275,17 @@ func (a *RTUDataStoreAdapter) HandleReadCoils(unitID byte, address, quantity uin
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return nil, rtu.ErrIllegalFunction
 	}
-	values, err := a.handler.store.ReadBits(AreaCoils, uint32(address), quantity)
+	if err := a.checkBusy(); err != nil {
+		return nil, err
+	}
+	if !a.handler.quantityLimits.Allowed(unitID, rtu.FuncReadCoils, quantity) {
+		return nil, rtu.ErrIllegalDataValue
+	}
+	values, err := a.handler.StoreForUnit(unitID).ReadBits(AreaCoils, uint32(address), quantity)
 	if err != nil {
 		return nil, rtu.ErrIllegalDataAddress
 	}
+	a.handler.access.RecordRead(AreaCoils, uint32(address), int(quantity))
 	return values, nil
 }
 
@@ -150,10 +295,17 @@ func (a *RTUDataStoreAdapter) HandleReadDiscreteInputs(unitID byte, address, qua
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return nil, rtu.ErrIllegalFunction
 	}
-	values, err := a.handler.store.ReadBits(AreaDiscreteInputs, uint32(address), quantity)
+	if err := a.checkBusy(); err != nil {
+		return nil, err
+	}
+	if !a.handler.quantityLimits.Allowed(unitID, rtu.FuncReadDiscreteInputs, quantity) {
+		return nil, rtu.ErrIllegalDataValue
+	}
+	values, err := a.handler.StoreForUnit(unitID).ReadBits(AreaDiscreteInputs, uint32(address), quantity)
 	if err != nil {
 		return nil, rtu.ErrIllegalDataAddress
 	}
+	a.handler.access.RecordRead(AreaDiscreteInputs, uint32(address), int(quantity))
 	return values, nil
 }
 
@@ -163,10 +315,17 @@ func (a *RTUDataStoreAdapter) HandleReadHoldingRegisters(unitID byte, address, q
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return nil, rtu.ErrIllegalFunction
 	}
-	values, err := a.handler.store.ReadWords(AreaHoldingRegs, uint32(address), quantity)
+	if err := a.checkBusy(); err != nil {
+		return nil, err
+	}
+	if !a.handler.quantityLimits.Allowed(unitID, rtu.FuncReadHoldingRegisters, quantity) {
+		return nil, rtu.ErrIllegalDataValue
+	}
+	values, err := a.handler.StoreForUnit(unitID).ReadWords(AreaHoldingRegs, uint32(address), quantity)
 	if err != nil {
 		return nil, rtu.ErrIllegalDataAddress
 	}
+	a.handler.access.RecordRead(AreaHoldingRegs, uint32(address), int(quantity))
 	return values, nil
 }
 
@@ -176,10 +335,17 @@ func (a *RTUDataStoreAdapter) HandleReadInputRegisters(unitID byte, address, qua
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return nil, rtu.ErrIllegalFunction
 	}
-	values, err := a.handler.store.ReadWords(AreaInputRegs, uint32(address), quantity)
+	if err := a.checkBusy(); err != nil {
+		return nil, err
+	}
+	if !a.handler.quantityLimits.Allowed(unitID, rtu.FuncReadInputRegisters, quantity) {
+		return nil, rtu.ErrIllegalDataValue
+	}
+	values, err := a.handler.StoreForUnit(unitID).ReadWords(AreaInputRegs, uint32(address), quantity)
 	if err != nil {
 		return nil, rtu.ErrIllegalDataAddress
 	}
+	a.handler.access.RecordRead(AreaInputRegs, uint32(address), int(quantity))
 	return values, nil
 }
 
@@ -189,9 +355,16 @@ func (a *RTUDataStoreAdapter) HandleWriteSingleCoil(unitID byte, address uint16,
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return rtu.ErrIllegalFunction
 	}
-	if err := a.handler.store.WriteBit(AreaCoils, uint32(address), value); err != nil {
+	if err := a.checkBusy(); err != nil {
+		return err
+	}
+	if a.handler.isReadOnly(AreaCoils) {
+		return rtu.ErrIllegalDataAddress
+	}
+	if err := a.handler.StoreForUnit(unitID).WriteBit(AreaCoils, uint32(address), value); err != nil {
 		return rtu.ErrIllegalDataAddress
 	}
+	a.handler.access.RecordWrite(AreaCoils, uint32(address), 1)
 	return nil
 }
 
@@ -201,9 +374,16 @@ func (a *RTUDataStoreAdapter) HandleWriteSingleRegister(unitID byte, address, va
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return rtu.ErrIllegalFunction
 	}
-	if err := a.handler.store.WriteWord(AreaHoldingRegs, uint32(address), value); err != nil {
+	if err := a.checkBusy(); err != nil {
+		return err
+	}
+	if a.handler.isReadOnly(AreaHoldingRegs) {
+		return rtu.ErrIllegalDataAddress
+	}
+	if err := a.handler.StoreForUnit(unitID).WriteWord(AreaHoldingRegs, uint32(address), value); err != nil {
 		return rtu.ErrIllegalDataAddress
 	}
+	a.handler.access.RecordWrite(AreaHoldingRegs, uint32(address), 1)
 	return nil
 }
 
@@ -213,9 +393,16 @@ func (a *RTUDataStoreAdapter) HandleWriteMultipleCoils(unitID byte, address uint
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return rtu.ErrIllegalFunction
 	}
-	if err := a.handler.store.WriteBits(AreaCoils, uint32(address), values); err != nil {
+	if err := a.checkBusy(); err != nil {
+		return err
+	}
+	if a.handler.isReadOnly(AreaCoils) {
 		return rtu.ErrIllegalDataAddress
 	}
+	if err := a.handler.StoreForUnit(unitID).WriteBits(AreaCoils, uint32(address), values); err != nil {
+		return rtu.ErrIllegalDataAddress
+	}
+	a.handler.access.RecordWrite(AreaCoils, uint32(address), len(values))
 	return nil
 }
 
@@ -225,9 +412,16 @@ func (a *RTUDataStoreAdapter) HandleWriteMultipleRegisters(unitID byte, address
 	if !a.handler.IsUnitIdEnabled(unitID) {
 		return rtu.ErrIllegalFunction
 	}
-	if err := a.handler.store.WriteWords(AreaHoldingRegs, uint32(address), values); err != nil {
+	if err := a.checkBusy(); err != nil {
+		return err
+	}
+	if a.handler.isReadOnly(AreaHoldingRegs) {
+		return rtu.ErrIllegalDataAddress
+	}
+	if err := a.handler.StoreForUnit(unitID).WriteWords(AreaHoldingRegs, uint32(address), values); err != nil {
 		return rtu.ErrIllegalDataAddress
 	}
+	a.handler.access.RecordWrite(AreaHoldingRegs, uint32(address), len(values))
 	return nil
 }
 
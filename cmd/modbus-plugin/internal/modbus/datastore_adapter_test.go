@@ -0,0 +1,38 @@
+package modbus
+
+import (
+	"testing"
+
+	"modbus_simulator/internal/domain/protocol"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestDataStoreRequestHandler_EmitsRxTxPerRequest(t *testing.T) {
+	srv := NewModbusServer(DefaultTCPConfig(), NewModbusDataStore(10, 10, 10, 10))
+	h := NewDataStoreRequestHandler(srv.handler)
+	emitter := protocol.NewRecordingEmitter()
+	h.SetEventEmitter(emitter)
+
+	if _, err := h.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Quantity: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.HandleInputRegisters(&modbus.InputRegistersRequest{UnitId: 1, Quantity: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 3, Quantity: 1, IsWrite: true, Args: []uint16{7}}); err != nil {
+		t.Fatal(err)
+	}
+	// 例外応答になるリクエストも受信・送信として数える
+	if _, err := h.HandleInputRegisters(&modbus.InputRegistersRequest{UnitId: 1, Addr: 9, Quantity: 2}); err == nil {
+		t.Fatal("expected out-of-range read to fail")
+	}
+
+	got := emitter.Snapshot()
+	if got.Rx != 4 || got.Tx != 4 {
+		t.Errorf("rx/tx = %d/%d, want 4/4", got.Rx, got.Tx)
+	}
+	if got.Connection != 0 {
+		t.Errorf("connection events = %d, want 0", got.Connection)
+	}
+}
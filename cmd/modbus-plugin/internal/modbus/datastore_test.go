@@ -1,6 +1,9 @@
 package modbus
 
 import (
+	"encoding/json"
+	"math"
+	"reflect"
 	"testing"
 
 	"modbus_simulator/internal/domain/datastore"
@@ -48,6 +51,32 @@ func TestModbusDataStore_GetAreas(t *testing.T) {
 	}
 }
 
+func TestModbusDataStore_GetArea(t *testing.T) {
+	store := NewModbusDataStore(100, 50, 200, 150)
+
+	area, ok := store.GetArea(AreaHoldingRegs)
+	if !ok {
+		t.Fatal("holdingRegisters not found")
+	}
+	if area.ID != AreaHoldingRegs || area.IsBit || area.Size != 200 {
+		t.Errorf("unexpected area: %+v", area)
+	}
+	if area, ok := store.GetArea(AreaCoils); !ok || !area.IsBit || area.Size != 100 {
+		t.Errorf("coils = %+v, %v", area, ok)
+	}
+	if _, ok := store.GetArea("unknown"); ok {
+		t.Error("unknown area should not be found")
+	}
+
+	// 非表示のエリアは GetAreas と同様に返さない
+	if err := store.SetVisibleAreas([]string{AreaCoils, AreaHoldingRegs}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.GetArea(AreaInputRegs); ok {
+		t.Error("hidden area should not be found")
+	}
+}
+
 func TestModbusDataStore_ReadWriteBit(t *testing.T) {
 	store := NewModbusDataStore(100, 50, 200, 150)
 
@@ -248,6 +277,47 @@ func TestModbusDataStore_ReadWriteWords_OutOfRange(t *testing.T) {
 	}
 }
 
+func TestModbusDataStore_AddressNearMaxUint32(t *testing.T) {
+	store := NewModbusDataStore(100, 50, 200, 150)
+	const addr = math.MaxUint32 - 1
+
+	// 32bit 環境でも加算がオーバーフローせず、パニックではなく範囲外エラーになる
+	if _, err := store.ReadBit(AreaCoils, addr); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("ReadBit: expected ErrAddressOutOfRange, got %v", err)
+	}
+	if err := store.WriteBit(AreaCoils, addr, true); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("WriteBit: expected ErrAddressOutOfRange, got %v", err)
+	}
+	if _, err := store.ReadBits(AreaDiscreteInputs, addr, 10); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("ReadBits: expected ErrAddressOutOfRange, got %v", err)
+	}
+	if err := store.WriteBits(AreaDiscreteInputs, addr, []bool{true, true, true}); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("WriteBits: expected ErrAddressOutOfRange, got %v", err)
+	}
+	if _, err := store.ReadWord(AreaHoldingRegs, addr); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("ReadWord: expected ErrAddressOutOfRange, got %v", err)
+	}
+	if err := store.WriteWord(AreaHoldingRegs, addr, 1); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("WriteWord: expected ErrAddressOutOfRange, got %v", err)
+	}
+	if _, err := store.ReadWords(AreaInputRegs, addr, 10); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("ReadWords: expected ErrAddressOutOfRange, got %v", err)
+	}
+	if err := store.WriteWords(AreaInputRegs, addr, []uint16{1, 2, 3}); err != datastore.ErrAddressOutOfRange {
+		t.Errorf("WriteWords: expected ErrAddressOutOfRange, got %v", err)
+	}
+
+	// zero / wrap ポリシーでも読み取りはパニックしない
+	store.SetOutOfRangePolicy(datastore.OutOfRangeZero)
+	if got, err := store.ReadWords(AreaHoldingRegs, addr, 3); err != nil || !reflect.DeepEqual(got, []uint16{0, 0, 0}) {
+		t.Errorf("ReadWords (zero) = %v, %v", got, err)
+	}
+	store.SetOutOfRangePolicy(datastore.OutOfRangeWrap)
+	if got, err := store.ReadBits(AreaCoils, addr, 3); err != nil || len(got) != 3 {
+		t.Errorf("ReadBits (wrap) = %v, %v", got, err)
+	}
+}
+
 func TestModbusDataStore_Snapshot(t *testing.T) {
 	store := NewModbusDataStore(10, 10, 10, 10)
 
@@ -333,6 +403,36 @@ func TestModbusDataStore_Restore(t *testing.T) {
 	}
 }
 
+func TestModbusDataStore_Restore_JSONRoundTrip(t *testing.T) {
+	src := NewModbusDataStore(10, 10, 10, 10)
+	_ = src.WriteBit(AreaCoils, 3, true)
+	_ = src.WriteWord(AreaHoldingRegs, 4, 0xABCD)
+
+	// gRPC 経由と同様に JSON を経由させる
+	b, err := json.Marshal(src.Snapshot())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	dst := NewModbusDataStore(10, 10, 10, 10)
+	if err := dst.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	val, _ := dst.ReadBit(AreaCoils, 3)
+	if !val {
+		t.Error("expected coil[3] to be true")
+	}
+	word, _ := dst.ReadWord(AreaHoldingRegs, 4)
+	if word != 0xABCD {
+		t.Errorf("expected 0xABCD, got 0x%04x", word)
+	}
+}
+
 func TestModbusDataStore_ClearAll(t *testing.T) {
 	store := NewModbusDataStore(10, 10, 10, 10)
 
@@ -455,3 +555,105 @@ func TestModbusDataStore_SetAll(t *testing.T) {
 		}
 	}
 }
+
+func TestModbusDataStore_SnapshotBinary(t *testing.T) {
+	src := NewModbusDataStore(65536, 65536, 65536, 65536)
+	_ = src.WriteBit(AreaCoils, 100, true)
+	_ = src.WriteBits(AreaDiscreteInputs, 65530, []bool{true, false, true})
+	_ = src.WriteWords(AreaHoldingRegs, 0, []uint16{1, 2, 3})
+	_ = src.WriteWord(AreaInputRegs, 65535, 0xFFFF)
+
+	bin := src.SnapshotBinary()
+	dst := NewModbusDataStore(65536, 65536, 65536, 65536)
+	if err := dst.RestoreBinary(bin); err != nil {
+		t.Fatalf("RestoreBinary failed: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Snapshot(), src.Snapshot()) {
+		t.Error("restored store differs from source")
+	}
+
+	jsonData, err := json.Marshal(src.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bin)*10 > len(jsonData) {
+		t.Errorf("binary snapshot is %d bytes, expected much smaller than JSON (%d bytes)", len(bin), len(jsonData))
+	}
+
+	if err := dst.RestoreBinary([]byte("garbage")); err == nil {
+		t.Error("expected error for invalid binary snapshot")
+	}
+}
+
+func TestModbusDataStore_SnapshotSparse(t *testing.T) {
+	src := NewModbusDataStore(100, 100, 32768, 100)
+	src.WriteWords(AreaHoldingRegs, 20000, []uint16{1, 2, 3})
+	src.WriteBit(AreaCoils, 99, true)
+
+	sparse := src.SnapshotSparse()
+	if runs := sparse[AreaHoldingRegs].Runs; len(runs) != 1 || runs[0].Start != 20000 {
+		t.Errorf("holding register runs = %+v", runs)
+	}
+	sparseJSON, _ := json.Marshal(sparse)
+	fullJSON, _ := json.Marshal(src.Snapshot())
+	if len(sparseJSON)*100 > len(fullJSON) {
+		t.Errorf("sparse snapshot is %d bytes, full snapshot is %d bytes", len(sparseJSON), len(fullJSON))
+	}
+
+	// ランに含まれないアドレスは 0 に戻る
+	dst := NewModbusDataStore(100, 100, 32768, 100)
+	dst.WriteWord(AreaHoldingRegs, 5, 55)
+	var decoded datastore.SparseSnapshot
+	if err := json.Unmarshal(sparseJSON, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.RestoreSparse(decoded); err != nil {
+		t.Fatalf("RestoreSparse: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Snapshot(), src.Snapshot()) {
+		t.Error("store after RestoreSparse differs from source")
+	}
+}
+
+func TestModbusDataStore_DeltaSince(t *testing.T) {
+	src := NewModbusDataStore(100, 100, 100, 100)
+	src.WriteWord(AreaHoldingRegs, 1, 11)
+	baseline := src.Snapshot()
+
+	src.WriteWord(AreaHoldingRegs, 5, 500)
+	src.WriteWord(AreaHoldingRegs, 1, 12)
+	src.WriteBit(AreaCoils, 7, true)
+
+	delta := src.DeltaSince(baseline)
+	want := map[string]interface{}{
+		AreaHoldingRegs: map[uint32]uint16{1: 12, 5: 500},
+		AreaCoils:       map[uint32]bool{7: true},
+	}
+	if !reflect.DeepEqual(delta, want) {
+		t.Fatalf("DeltaSince = %v, want %v", delta, want)
+	}
+
+	// JSON を経由した差分でも基準の状態から現在の状態を再現できる
+	data, err := json.Marshal(delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	dst := NewModbusDataStore(100, 100, 100, 100)
+	if err := dst.Restore(baseline); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.ApplyDelta(decoded); err != nil {
+		t.Fatalf("ApplyDelta: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Snapshot(), src.Snapshot()) {
+		t.Error("store after ApplyDelta differs from source")
+	}
+
+	if d := src.DeltaSince(src.Snapshot()); len(d) != 0 {
+		t.Errorf("expected empty delta, got %v", d)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 
+	"modbus_simulator/internal/domain/datastore"
 	"modbus_simulator/internal/domain/protocol"
 )
 
@@ -66,12 +70,16 @@ func (f *ModbusServerFactory) CreateServer(config protocol.ProtocolConfig, store
 		return nil, fmt.Errorf("invalid config type: expected ModbusConfig")
 	}
 
-	return NewModbusServer(modbusConfig, store), nil
+	srv := NewModbusServer(modbusConfig, store)
+	if err := srv.applyConfig(); err != nil {
+		return nil, err
+	}
+	return srv, nil
 }
 
 // CreateDataStore はプロトコル用のデータストアを作成する
 func (f *ModbusServerFactory) CreateDataStore() protocol.DataStore {
-	return NewModbusDataStore(65536, 65536, 65536, 65536)
+	return f.createDataStore()
 }
 
 // DefaultConfig はデフォルト設定を返す
@@ -102,14 +110,25 @@ func (f *ModbusServerFactory) CreateConfigFromVariant(_ string) protocol.Protoco
 
 // GetConfigFields は設定フィールドを返す（fixedVariant を使用）
 func (f *ModbusServerFactory) GetConfigFields(_ string) []protocol.ConfigField {
+	var fields []protocol.ConfigField
 	switch f.fixedVariant {
 	case VariantTCP:
-		return []protocol.ConfigField{
+		fields = []protocol.ConfigField{
 			{Name: "tcpAddress", Label: "アドレス", Description: "待ち受けるネットワークアドレス。0.0.0.0 で全インターフェースに対応します。", Type: "text", Required: true, Default: "0.0.0.0"},
 			{Name: "tcpPort", Label: "ポート", Description: "Modbus TCP の待ち受けポート番号。標準ポートは 502 です。", Type: "number", Required: true, Default: 502, Min: intPtr(1), Max: intPtr(65535)},
+			{Name: "fallbackPort", Label: "代替ポート", Description: "権限不足で 1024 未満のポートにバインドできない場合に代わりに使うポート番号。0 で無効です。", Type: "number", Default: 0, Min: intPtr(0), Max: intPtr(65535)},
+			{Name: "nativeTCP", Label: "自作TCP実装", Description: "MBAP ヘッダーを自前で解析する TCP 実装を使います。既定の実装と同じ処理で応答したうえで FIFO キューの読み取り (FC 0x18) などにも対応し、トランザクションIDと UnitID をログに記録します。", Type: "select", Default: "false", Options: []protocol.FieldOption{
+				{Value: "false", Label: "無効"},
+				{Value: "true", Label: "有効"},
+			}},
+			{Name: "rateLimit", Label: "レート制限", Description: "1接続あたりの秒間リクエスト数の上限。低速なゲートウェイの再現に使います。0 で無制限です。", Type: "number", Default: 0, Min: intPtr(0), Category: "通信シミュレーション"},
+			{Name: "rateLimitBusy", Label: "レート制限超過時", Description: "上限を超えたリクエストを待たせて処理するか、Server Device Busy (0x06) 例外を返すかを選びます。", Type: "select", Default: "false", Category: "通信シミュレーション", Options: []protocol.FieldOption{
+				{Value: "false", Label: "待機して応答"},
+				{Value: "true", Label: "ビジー例外を返す"},
+			}},
 		}
 	case VariantRTU:
-		return []protocol.ConfigField{
+		fields = []protocol.ConfigField{
 			{Name: "serialPort", Label: "シリアルポート", Description: "通信に使用するシリアルポート（例: COM1、COM3）。", Type: "serialport", Required: true, Default: "COM1", Category: "基本設定"},
 			{Name: "baudRate", Label: "ボーレート", Description: "シリアル通信の速度（bps）。接続先デバイスと一致させてください。", Type: "select", Required: true, Default: 115200, Category: "基本設定", Options: []protocol.FieldOption{
 				{Value: "9600", Label: "9600"},
@@ -131,9 +150,10 @@ func (f *ModbusServerFactory) GetConfigFields(_ string) []protocol.ConfigField {
 				{Value: "E", Label: "Even"},
 				{Value: "O", Label: "Odd"},
 			}},
+			autoReconnectField,
 		}
 	case VariantASCII:
-		return []protocol.ConfigField{
+		fields = []protocol.ConfigField{
 			{Name: "serialPort", Label: "シリアルポート", Description: "通信に使用するシリアルポート（例: COM1、COM3）。", Type: "serialport", Required: true, Default: "COM1", Category: "基本設定"},
 			{Name: "baudRate", Label: "ボーレート", Description: "シリアル通信の速度（bps）。接続先デバイスと一致させてください。", Type: "select", Required: true, Default: 9600, Category: "基本設定", Options: []protocol.FieldOption{
 				{Value: "9600", Label: "9600"},
@@ -155,7 +175,74 @@ func (f *ModbusServerFactory) GetConfigFields(_ string) []protocol.ConfigField {
 				{Value: "E", Label: "Even"},
 				{Value: "O", Label: "Odd"},
 			}},
+			autoReconnectField,
 		}
+	default:
+		return nil
+	}
+	return append(fields,
+		protocol.ConfigField{
+			Name: "visibleAreas", Label: "表示エリア", Description: "表示するエリアをカンマ区切りで指定します（例: holdingRegisters,coils）。空の場合は全エリアを表示します。非表示のエリアも通信ではアクセスできます。", Type: "text", Default: "",
+		},
+		protocol.ConfigField{
+			Name: "wordSwapAreas", Label: "ワード入れ替え", Description: "Modbus TCP の読み書きで2レジスタずつ上位/下位ワードを入れ替えるエリアをカンマ区切りで指定します（holdingRegisters, inputRegisters）。", Type: "text", Default: "",
+		},
+		protocol.ConfigField{
+			Name: "readOnlyAreas", Label: "読み取り専用エリア", Description: "Modbus クライアントからの書き込みを拒否するエリアをカンマ区切りで指定します。UI では編集できないエリアとして表示されます。", Type: "text", Default: "",
+		},
+		protocol.ConfigField{
+			Name: "areaSizes", Label: "エリアの点数", Description: "エリアの点数を変更します。\"エリア=点数\" をカンマ区切りで指定します（例: holdingRegisters=20000,coils=1000）。指定しないエリアは 65536 点です。サーバー停止中のみ変更できます。", Type: "text", Default: "",
+		},
+		protocol.ConfigField{
+			Name: "outOfRangePolicy", Label: "範囲外アドレスの読み取り", Description: "エリアの点数を超えるアドレスを読み取った場合の動作です。", Type: "select", Default: "error", Category: "通信シミュレーション", Options: []protocol.FieldOption{
+				{Value: "error", Label: "例外応答"},
+				{Value: "zero", Label: "ゼロを返す"},
+				{Value: "wrap", Label: "先頭に折り返す"},
+			},
+		},
+		protocol.ConfigField{
+			Name: "maxReadQuantities", Label: "最大読み取り数", Description: "非準拠機器の再現用に FC 1-4 の1回の最大読み取り数を変更します。\"FC=上限\" または \"UnitID:FC=上限\" をカンマ区切りで指定します（例: 3=64,2:3=16）。超えた読み取りには Illegal Data Value (0x03) 例外を返します。空の場合は仕様上の最大値です。", Type: "text", Default: "", Category: "通信シミュレーション",
+		},
+		protocol.ConfigField{
+			Name: "mmapPath", Label: "メモリマップトファイル", Description: "メモリの内容を指定したファイルにマップして保持します（大きなエリアをヒープに確保しない・再起動後も値が残る）。空の場合はプロセス内のメモリを使います。エリアの点数を変えた場合はファイルの内容を初期化します。", Type: "text", Default: "",
+		},
+		protocol.ConfigField{
+			Name: "perUnitStore", Label: "UnitID別メモリ", Description: "UnitID ごとに別々のメモリを持たせます。無効の場合は全 UnitID が同じメモリに応答します。UnitID 1 は常に共通のメモリを使います。", Type: "select", Default: "false", Options: []protocol.FieldOption{
+				{Value: "false", Label: "無効"},
+				{Value: "true", Label: "有効"},
+			},
+		},
+		protocol.ConfigField{
+			Name: "unitIdRemap", Label: "UnitIDの読み替え", Description: "ゲートウェイによる UnitID の付け替えを再現します。\"要求された UnitID=応答に使う UnitID\" をカンマ区切りで指定します（例: 5=1）。UnitID別メモリが有効な場合のみ意味を持ちます。", Type: "text", Default: "",
+		},
+		protocol.ConfigField{
+			Name: "connectionTimeout", Label: "接続のタイムアウト", Description: "無通信のユニットを接続数から外すまでの時間（ミリ秒）です。", Type: "number", Default: int(defaultConnectionTimeout.Milliseconds()), Min: intPtr(int(minConnectionTimeout.Milliseconds())), Max: intPtr(int(maxConnectionTimeout.Milliseconds())),
+		},
+	)
+}
+
+// autoReconnectField はシリアルポート喪失時の動作を選ぶ設定フィールド（RTU/ASCII 共通）
+var autoReconnectField = protocol.ConfigField{
+	Name: "autoReconnect", Label: "ポート喪失時の再接続", Description: "USB 抜去などでシリアルポートを失ったときに自動で再接続を試みます。無効の場合はサーバーを Error 状態で停止します。", Type: "select", Default: "false", Category: "基本設定", Options: []protocol.FieldOption{
+		{Value: "false", Label: "無効"},
+		{Value: "true", Label: "有効"},
+	},
+}
+
+// TransportFields は待ち受けとデータストアに関わる設定フィールドを返す（fixedVariant を使用）。
+// これらが変わる場合はサーバーを作り直す。エリア関連のフィールドはサーバー実行中でも UpdateConfig で反映できる。
+// プラグインは GetMetadata でこの一覧をホストへ返す。
+func (f *ModbusServerFactory) TransportFields(_ string) []string {
+	return transportFields(f.fixedVariant)
+}
+
+// transportFields はバリアントごとの待ち受けとデータストアに関わる設定フィールド名を返す
+func transportFields(variant ModbusVariant) []string {
+	switch variant {
+	case VariantTCP:
+		return []string{"tcpAddress", "tcpPort", "fallbackPort", "nativeTCP", "mmapPath"}
+	case VariantRTU, VariantASCII:
+		return []string{"serialPort", "baudRate", "dataBits", "stopBits", "parity", "mmapPath"}
 	}
 	return nil
 }
@@ -175,18 +262,38 @@ func (f *ModbusServerFactory) ConfigToMap(config protocol.ProtocolConfig) map[st
 	if !ok {
 		return nil
 	}
+	return configToMap(mc)
+}
+
+// configToMap は mc の設定をバリアントに応じたフィールド名の map に変換する
+func configToMap(mc *ModbusConfig) map[string]interface{} {
 	result := make(map[string]interface{})
 	switch mc.variant {
 	case VariantTCP:
 		result["tcpAddress"] = mc.TCPAddress
 		result["tcpPort"] = mc.TCPPort
+		result["fallbackPort"] = mc.FallbackPort
+		result["nativeTCP"] = strconv.FormatBool(mc.NativeTCP)
+		result["rateLimit"] = mc.RateLimit
+		result["rateLimitBusy"] = strconv.FormatBool(mc.RateLimitBusy)
 	case VariantRTU, VariantASCII:
 		result["serialPort"] = mc.SerialPort
 		result["baudRate"] = mc.BaudRate
 		result["dataBits"] = mc.DataBits
 		result["stopBits"] = mc.StopBits
 		result["parity"] = mc.Parity
+		result["autoReconnect"] = strconv.FormatBool(mc.AutoReconnect)
 	}
+	result["visibleAreas"] = strings.Join(mc.VisibleAreas, ",")
+	result["wordSwapAreas"] = strings.Join(mc.WordSwapAreas, ",")
+	result["readOnlyAreas"] = strings.Join(mc.ReadOnlyAreas, ",")
+	result["areaSizes"] = formatAreaSizes(mc.AreaSizes)
+	result["outOfRangePolicy"] = string(mc.outOfRangePolicy())
+	result["maxReadQuantities"] = formatReadQuantityLimits(mc.MaxReadQuantities)
+	result["perUnitStore"] = strconv.FormatBool(mc.PerUnitStore)
+	result["unitIdRemap"] = formatUnitIDRemap(mc.UnitIDRemap)
+	result["connectionTimeout"] = int(mc.connectionTimeout().Milliseconds())
+	result["mmapPath"] = mc.MmapPath
 	return result
 }
 
@@ -204,6 +311,22 @@ func (f *ModbusServerFactory) MapToConfig(_ string, settings map[string]interfac
 		} else if v, ok := settings["tcpPort"].(int); ok {
 			config.TCPPort = v
 		}
+		if v, ok := settings["fallbackPort"].(float64); ok {
+			config.FallbackPort = int(v)
+		} else if v, ok := settings["fallbackPort"].(int); ok {
+			config.FallbackPort = v
+		}
+		if v, ok := settings["nativeTCP"].(bool); ok {
+			config.NativeTCP = v
+		} else if v, ok := settings["nativeTCP"].(string); ok {
+			config.NativeTCP = v == "true"
+		}
+		if v, ok := settingInt(settings, "rateLimit"); ok {
+			config.RateLimit = v
+		}
+		if v, ok := settingBool(settings, "rateLimitBusy"); ok {
+			config.RateLimitBusy = v
+		}
 	case VariantRTU, VariantASCII:
 		if v, ok := settings["serialPort"].(string); ok {
 			config.SerialPort = v
@@ -226,6 +349,62 @@ func (f *ModbusServerFactory) MapToConfig(_ string, settings map[string]interfac
 		if v, ok := settings["parity"].(string); ok {
 			config.Parity = v
 		}
+		if v, ok := settingBool(settings, "autoReconnect"); ok {
+			config.AutoReconnect = v
+		}
+	}
+
+	visible, err := parseVisibleAreas(settings["visibleAreas"])
+	if err != nil {
+		return nil, err
+	}
+	config.VisibleAreas = visible
+
+	swap, err := parseWordSwapAreas(settings["wordSwapAreas"])
+	if err != nil {
+		return nil, err
+	}
+	config.WordSwapAreas = swap
+
+	readOnly, err := parseVisibleAreas(settings["readOnlyAreas"])
+	if err != nil {
+		return nil, fmt.Errorf("readOnlyAreas: %w", err)
+	}
+	config.ReadOnlyAreas = readOnly
+
+	sizes, err := parseAreaSizes(settings["areaSizes"])
+	if err != nil {
+		return nil, err
+	}
+	config.AreaSizes = sizes
+
+	if v, ok := settings["outOfRangePolicy"].(string); ok {
+		policy, err := datastore.ParseOutOfRangePolicy(v)
+		if err != nil {
+			return nil, fmt.Errorf("outOfRangePolicy: %w", err)
+		}
+		config.OutOfRangePolicy = policy
+	}
+
+	limits, err := parseReadQuantityLimits(settings["maxReadQuantities"])
+	if err != nil {
+		return nil, err
+	}
+	config.MaxReadQuantities = limits
+
+	if v, ok := settingBool(settings, "perUnitStore"); ok {
+		config.PerUnitStore = v
+	}
+	remap, err := parseUnitIDRemap(settings["unitIdRemap"])
+	if err != nil {
+		return nil, err
+	}
+	config.UnitIDRemap = remap
+	if v, ok := settingInt(settings, "connectionTimeout"); ok {
+		config.ConnectionTimeout = v
+	}
+	if v, ok := settings["mmapPath"].(string); ok {
+		config.MmapPath = strings.TrimSpace(v)
 	}
 
 	return config, nil
@@ -235,6 +414,28 @@ func intPtr(i int) *int {
 	return &i
 }
 
+// settingInt は数値の設定値を返す（JSON 経由の float64 と int の両方を受け付ける）
+func settingInt(settings map[string]interface{}, name string) (int, bool) {
+	switch v := settings[name].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	}
+	return 0, false
+}
+
+// settingBool は真偽値の設定値を返す（select フィールドの "true"/"false" と bool の両方を受け付ける）
+func settingBool(settings map[string]interface{}, name string) (bool, bool) {
+	switch v := settings[name].(type) {
+	case bool:
+		return v, true
+	case string:
+		return v == "true", true
+	}
+	return false, false
+}
+
 // ModbusVariant はModbusのバリアント
 type ModbusVariant string
 
@@ -252,12 +453,56 @@ type ModbusConfig struct {
 	TCPAddress string `json:"tcpAddress"`
 	TCPPort    int    `json:"tcpPort"`
 
+	// 特権ポートへのバインドが権限不足で失敗した場合に使うポート（0 の場合は使わない）
+	FallbackPort int `json:"fallbackPort,omitempty"`
+
+	// simonvetter/modbus の代わりに MBAP ヘッダーを自前で解析する TCP 実装を使う
+	NativeTCP bool `json:"nativeTCP,omitempty"`
+
+	// Modbus TCP の1接続あたりの秒間リクエスト数（0 の場合は無制限）。
+	// RateLimitBusy が true の場合は超過したリクエストに Server Device Busy 例外を返す
+	RateLimit     int  `json:"rateLimit,omitempty"`
+	RateLimitBusy bool `json:"rateLimitBusy,omitempty"`
+
 	// RTU設定
 	SerialPort string `json:"serialPort"`
 	BaudRate   int    `json:"baudRate"`
 	DataBits   int    `json:"dataBits"`
 	StopBits   int    `json:"stopBits"`
 	Parity     string `json:"parity"`
+
+	// シリアルポート喪失時（USB 抜去等）に自動で再接続を試みる（false の場合は Error 状態で停止する）
+	AutoReconnect bool `json:"autoReconnect,omitempty"`
+
+	// GetAreas で公開するエリアと順序（空の場合は全エリアを AreaOrder の順で公開）
+	VisibleAreas []string `json:"visibleAreas,omitempty"`
+
+	// Modbus TCP で読み書き時にワードペアを入れ替えるレジスタエリア（空の場合は入れ替えなし）
+	WordSwapAreas []string `json:"wordSwapAreas,omitempty"`
+
+	// Modbus クライアントからの書き込みを拒否し、UI に読み取り専用として報告するエリア
+	ReadOnlyAreas []string `json:"readOnlyAreas,omitempty"`
+
+	// エリアごとの点数（指定のないエリアはデータストア作成時の点数）
+	AreaSizes map[string]int `json:"areaSizes,omitempty"`
+
+	// 範囲外アドレスを読み取った場合の動作（空の場合は error）
+	OutOfRangePolicy datastore.OutOfRangePolicy `json:"outOfRangePolicy,omitempty"`
+
+	// FC 1-4 の最大読み取り数の変更（空の場合は仕様上の最大値）
+	MaxReadQuantities []ReadQuantityLimit `json:"maxReadQuantities,omitempty"`
+
+	// UnitID ごとに別々のデータストアを使う（false の場合は全 UnitID が共有のデータストアに応答する）
+	PerUnitStore bool `json:"perUnitStore,omitempty"`
+
+	// UnitID の読み替え表（要求された UnitID → データストアを選ぶ UnitID）
+	UnitIDRemap map[uint8]uint8 `json:"unitIdRemap,omitempty"`
+
+	// 無通信のユニットを接続数から外すまでの時間（ミリ秒、0 の場合は defaultConnectionTimeout）
+	ConnectionTimeout int `json:"connectionTimeout,omitempty"`
+
+	// メモリマップトファイルのパス（空の場合はヒープ上の ModbusDataStore を使う）
+	MmapPath string `json:"mmapPath,omitempty"`
 }
 
 // ProtocolType はプロトコルの種類を返す
@@ -286,6 +531,12 @@ func (c *ModbusConfig) Validate() error {
 		if c.TCPPort < 1 || c.TCPPort > 65535 {
 			return fmt.Errorf("invalid TCP port: %d", c.TCPPort)
 		}
+		if c.FallbackPort < 0 || c.FallbackPort > 65535 {
+			return fmt.Errorf("invalid fallback port: %d", c.FallbackPort)
+		}
+		if c.RateLimit < 0 {
+			return fmt.Errorf("invalid rate limit: %d", c.RateLimit)
+		}
 	case VariantRTU, VariantASCII:
 		if c.SerialPort == "" {
 			return fmt.Errorf("serial port is required")
@@ -296,21 +547,48 @@ func (c *ModbusConfig) Validate() error {
 	default:
 		return fmt.Errorf("unknown variant: %s", c.variant)
 	}
+	if c.ConnectionTimeout != 0 {
+		if d := c.connectionTimeout(); d < minConnectionTimeout || d > maxConnectionTimeout {
+			return fmt.Errorf("invalid connection timeout: %dms", c.ConnectionTimeout)
+		}
+	}
 	return nil
 }
 
 // Clone は設定のコピーを作成する
 func (c *ModbusConfig) Clone() protocol.ProtocolConfig {
-	return &ModbusConfig{
-		variant:    c.variant,
-		TCPAddress: c.TCPAddress,
-		TCPPort:    c.TCPPort,
-		SerialPort: c.SerialPort,
-		BaudRate:   c.BaudRate,
-		DataBits:   c.DataBits,
-		StopBits:   c.StopBits,
-		Parity:     c.Parity,
+	clone := *c
+	clone.VisibleAreas = append([]string(nil), c.VisibleAreas...)
+	clone.WordSwapAreas = append([]string(nil), c.WordSwapAreas...)
+	clone.ReadOnlyAreas = append([]string(nil), c.ReadOnlyAreas...)
+	clone.MaxReadQuantities = append([]ReadQuantityLimit(nil), c.MaxReadQuantities...)
+	if c.AreaSizes != nil {
+		clone.AreaSizes = make(map[string]int, len(c.AreaSizes))
+		for area, size := range c.AreaSizes {
+			clone.AreaSizes[area] = size
+		}
 	}
+	if c.UnitIDRemap != nil {
+		clone.UnitIDRemap = make(map[uint8]uint8, len(c.UnitIDRemap))
+		for from, to := range c.UnitIDRemap {
+			clone.UnitIDRemap[from] = to
+		}
+	}
+	return &clone
+}
+
+// sameTransport は待ち受けに関わる設定（transportFields が返すフィールド）が同じかどうかを返す
+func (c *ModbusConfig) sameTransport(other *ModbusConfig) bool {
+	if c.variant != other.variant {
+		return false
+	}
+	mine, theirs := configToMap(c), configToMap(other)
+	for _, name := range transportFields(c.variant) {
+		if mine[name] != theirs[name] {
+			return false
+		}
+	}
+	return true
 }
 
 // GetVariant はバリアントを返す
@@ -357,47 +635,69 @@ type ModbusServer struct {
 	store          protocol.DataStore
 	handler        *DataStoreHandler
 	innerServer    *Server
-	status         protocol.ServerStatus
 	eventEmitter   protocol.CommunicationEventEmitter
-	sessionManager *protocol.SessionManager
+	traffic        *protocol.TrafficStats   // 処理したリクエストの通信統計（plc.stats() 用にホストへ返す）
+	sessionManager *protocol.SessionManager // UnitID ごとのセッション（既定の TCP 待ち受けの接続数）
+
+	// 設定の areaSizes にないエリアを戻す点数（初回の applyAreaSizes で記録する）
+	baseAreaSizes map[string]int
+
+	// status・lastErr はシリアル受信ループからも更新されるため stateMu で保護する
+	stateMu sync.Mutex
+	status  protocol.ServerStatus
+	lastErr error
 }
 
 // NewModbusServer は新しいModbusServerを作成する
 func NewModbusServer(config *ModbusConfig, store protocol.DataStore) *ModbusServer {
-	return &ModbusServer{
+	s := &ModbusServer{
 		config:  config,
 		store:   store,
 		handler: NewDataStoreHandler(store),
+		traffic: protocol.NewTrafficStats(),
 		status:  protocol.StatusStopped,
 	}
+	s.sessionManager = protocol.NewSessionManager(defaultConnectionTimeout, s.events())
+	return s
 }
 
 // Start はサーバーを起動する
 func (s *ModbusServer) Start(ctx context.Context) error {
-	if s.status == protocol.StatusRunning {
+	if s.Status() == protocol.StatusRunning {
 		return fmt.Errorf("server is already running")
 	}
 
-	// 内部サーバーを作成
-	s.innerServer = NewServerWithHandler(s.config, s.handler)
-
-	// イベントエミッターとセッションマネージャーを設定
-	if s.eventEmitter != nil {
-		s.innerServer.SetEventEmitter(s.eventEmitter)
-	}
-	if s.sessionManager != nil {
-		s.innerServer.SetSessionManager(s.sessionManager)
-	}
-
-	if err := s.innerServer.Start(); err != nil {
-		s.status = protocol.StatusError
+	s.innerServer = s.newInnerServer(s.config)
+	if err := s.startWithPortFallback(); err != nil {
+		// シリアルポートが存在しない場合などは Error 状態として原因を保持する
+		s.innerServer = nil
+		s.setState(protocol.StatusError, err)
 		return err
 	}
 
-	s.status = protocol.StatusRunning
+	s.sessionManager.Start()
+	s.setState(protocol.StatusRunning, nil)
 	return nil
 }
 
+// events は内部サーバーとセッションマネージャーに渡すエミッターを返す。
+// 通信統計に集計したうえで、SetEventEmitter で設定されたエミッターにも配信する。
+func (s *ModbusServer) events() protocol.CommunicationEventEmitter {
+	return protocol.NewMultiEventEmitter(s.traffic, s.eventEmitter)
+}
+
+// newInnerServer は config で内部サーバーを作成し、イベントエミッター等を設定する
+func (s *ModbusServer) newInnerServer(config *ModbusConfig) *Server {
+	inner := NewServerWithHandler(config, s.handler)
+	inner.SetAutoReconnect(config.AutoReconnect)
+	inner.SetOnPortStateChanged(s.onPortStateChanged)
+
+	// イベントエミッターとセッションマネージャーを設定
+	inner.SetEventEmitter(s.events())
+	inner.SetSessionManager(s.sessionManager)
+	return inner
+}
+
 // Stop はサーバーを停止する
 func (s *ModbusServer) Stop() error {
 	if s.innerServer != nil {
@@ -406,15 +706,49 @@ func (s *ModbusServer) Stop() error {
 		}
 		s.innerServer = nil
 	}
-	s.status = protocol.StatusStopped
+	s.handler.clients.Reset()
+	s.sessionManager.Stop()
+	s.setState(protocol.StatusStopped, nil)
 	return nil
 }
 
 // Status はサーバーの状態を返す
 func (s *ModbusServer) Status() protocol.ServerStatus {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
 	return s.status
 }
 
+// LastError は直近の起動失敗・ポート喪失の原因を返す（エラーがなければ nil）
+func (s *ModbusServer) LastError() error {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.lastErr
+}
+
+func (s *ModbusServer) setState(status protocol.ServerStatus, err error) {
+	s.stateMu.Lock()
+	s.status = status
+	s.lastErr = err
+	s.stateMu.Unlock()
+}
+
+// onPortStateChanged はシリアルポートの喪失（Error 状態）と復旧（Running 状態）を反映する
+func (s *ModbusServer) onPortStateChanged(err error) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	if s.status == protocol.StatusStopped {
+		return
+	}
+	if err != nil {
+		s.status = protocol.StatusError
+		s.lastErr = err
+		return
+	}
+	s.status = protocol.StatusRunning
+	s.lastErr = nil
+}
+
 // ProtocolType はプロトコルの種類を返す
 func (s *ModbusServer) ProtocolType() protocol.ProtocolType {
 	return s.config.ProtocolType()
@@ -425,22 +759,69 @@ func (s *ModbusServer) Config() protocol.ProtocolConfig {
 	return s.config
 }
 
-// UpdateConfig は設定を更新する
+// UpdateConfig は設定を更新する。
+// 実行中は待ち受けに関わらない設定（エリア関連）の変更のみ受け付け、接続を維持したまま反映する。
 func (s *ModbusServer) UpdateConfig(config protocol.ProtocolConfig) error {
-	if s.status == protocol.StatusRunning {
-		return fmt.Errorf("cannot update config while server is running")
-	}
-
 	modbusConfig, ok := config.(*ModbusConfig)
 	if !ok {
 		return fmt.Errorf("invalid config type: expected ModbusConfig")
 	}
+	if _, err := normalizeVisibleAreas(modbusConfig.VisibleAreas); err != nil {
+		return err
+	}
+	if err := validateWordSwapAreas(modbusConfig.WordSwapAreas); err != nil {
+		return err
+	}
+	if _, err := normalizeVisibleAreas(modbusConfig.ReadOnlyAreas); err != nil {
+		return err
+	}
+
+	if s.Status() == protocol.StatusRunning {
+		if !s.config.sameTransport(modbusConfig) {
+			return fmt.Errorf("cannot update config while server is running")
+		}
+		// ハンドラーとの競合を避けるため、エリアの点数は停止中のみ変更できる
+		if !sameAreaSizes(s.config.AreaSizes, modbusConfig.AreaSizes) {
+			return fmt.Errorf("cannot resize area while server is running")
+		}
+		// 内部サーバーが参照しているハンドラーはそのまま使う
+		s.config = modbusConfig
+		return s.applyConfig()
+	}
 
-	// ハンドラーの無効化UnitIDリストを保持
-	disabledIDs := s.handler.GetDisabledUnitIDs()
+	// 無効化UnitID・アクセス回数・UnitID別ストアなどのハンドラーの状態はまとめて引き継ぐ
 	s.config = modbusConfig
-	s.handler = NewDataStoreHandler(s.store)
-	s.handler.SetDisabledUnitIDs(disabledIDs)
+	s.handler = newDataStoreHandlerWithState(s.store, s.handler.handlerState)
+	return s.applyConfig()
+}
+
+// applyConfig は設定のうち待ち受け以外の項目（エリア・通信シミュレーション）をハンドラーとデータストアに反映する
+func (s *ModbusServer) applyConfig() error {
+	if err := s.handler.SetWordSwapAreas(s.config.WordSwapAreas); err != nil {
+		return err
+	}
+	if err := s.applyReadOnlyAreas(); err != nil {
+		return err
+	}
+	if err := s.applyVisibleAreas(); err != nil {
+		return err
+	}
+	if err := s.applyAreaSizes(); err != nil {
+		return err
+	}
+	if err := s.applyOutOfRangePolicy(); err != nil {
+		return err
+	}
+	if err := s.handler.quantityLimits.Replace(s.config.MaxReadQuantities); err != nil {
+		return err
+	}
+	s.handler.rateLimiter.SetLimit(s.config.RateLimit, s.config.RateLimitBusy)
+	s.handler.SetPerUnitStore(s.config.PerUnitStore)
+	s.handler.SetUnitIDRemap(s.config.UnitIDRemap)
+	s.sessionManager.SetTimeout(s.config.connectionTimeout())
+	if s.innerServer != nil {
+		s.innerServer.SetAutoReconnect(s.config.AutoReconnect)
+	}
 	return nil
 }
 
@@ -464,34 +845,87 @@ func (s *ModbusServer) SetDisabledUnitIDs(ids []uint8) {
 	s.handler.SetDisabledUnitIDs(ids)
 }
 
-// SetEventEmitter はイベントエミッターを設定する
-func (s *ModbusServer) SetEventEmitter(emitter protocol.CommunicationEventEmitter) {
-	s.eventEmitter = emitter
-	if s.innerServer != nil {
-		s.innerServer.SetEventEmitter(emitter)
-	}
+// GetClients は Modbus TCP で接続中のクライアント一覧を返す（RTU/ASCII では常に空）
+func (s *ModbusServer) GetClients() []protocol.ClientInfo {
+	return s.handler.clients.Snapshot()
+}
+
+// GetAccessCounts は area のアドレスごとのマスターからの読み書き回数をアドレス順で返す
+func (s *ModbusServer) GetAccessCounts(area string) []protocol.AccessCount {
+	return s.handler.access.Snapshot(area)
+}
+
+// ResetAccessCounts はマスターからの読み書き回数を破棄する
+func (s *ModbusServer) ResetAccessCounts() {
+	s.handler.access.Reset()
 }
 
-// SetSessionManager はセッションマネージャーを設定する
-func (s *ModbusServer) SetSessionManager(manager *protocol.SessionManager) {
-	s.sessionManager = manager
+// UnitDataStore は指定 UnitID が応答に使用するデータストアを返す
+func (s *ModbusServer) UnitDataStore(unitId uint8) protocol.DataStore {
+	return s.handler.StoreForUnit(unitId)
+}
+
+// SetEventEmitter はイベントエミッターを設定する（通信統計への集計は設定に関わらず続ける）
+func (s *ModbusServer) SetEventEmitter(emitter protocol.CommunicationEventEmitter) {
+	s.eventEmitter = emitter
+	events := s.events()
+	s.sessionManager.SetEmitter(events)
 	if s.innerServer != nil {
-		s.innerServer.SetSessionManager(manager)
+		s.innerServer.SetEventEmitter(events)
 	}
 }
 
 // DataStoreHandler はDataStoreを使用するModbusハンドラー
 type DataStoreHandler struct {
-	store           protocol.DataStore
+	*handlerState
+
+	store    protocol.DataStore
+	clients  *ClientTracker
+	wordSwap wordSwapSet
+}
+
+// handlerState は設定の更新でハンドラーを作り直しても引き継ぐ状態
+type handlerState struct {
 	disabledUnitIDs map[uint8]bool
+	rateLimiter     *RateLimiter
+	quantityLimits  *QuantityLimits
+	access          *AccessTracker
+	fifo            *FIFOQueues
+	commEvents      *commEventCounter
+	busy            *busyWindow
+
+	// UnitID ごとのデータストア（perUnit が有効な場合のみ使用）
+	unitMu      sync.RWMutex
+	perUnit     bool
+	unitStores  map[uint8]*ModbusDataStore
+	rangePolicy datastore.OutOfRangePolicy // 新しく作成する UnitID 別ストアに適用する
+	unitRemap   map[uint8]uint8            // 要求された UnitID → データストアを選ぶ UnitID
 }
 
 // NewDataStoreHandler は新しいDataStoreHandlerを作成する
 func NewDataStoreHandler(store protocol.DataStore) *DataStoreHandler {
-	return &DataStoreHandler{
-		store:           store,
+	return newDataStoreHandlerWithState(store, &handlerState{
 		disabledUnitIDs: make(map[uint8]bool),
+		rateLimiter:     NewRateLimiter(),
+		quantityLimits:  NewQuantityLimits(),
+		access:          NewAccessTracker(),
+		fifo:            NewFIFOQueues(),
+		commEvents:      &commEventCounter{},
+		busy:            &busyWindow{},
+		unitStores:      make(map[uint8]*ModbusDataStore),
+	})
+}
+
+// newDataStoreHandlerWithState は既存の状態を引き継いだハンドラーを作成する
+func newDataStoreHandlerWithState(store protocol.DataStore, state *handlerState) *DataStoreHandler {
+	h := &DataStoreHandler{
+		handlerState: state,
+		store:        store,
+		clients:      NewClientTracker(clientIdleTimeout),
 	}
+	// 一覧から外れた（切断・タイムアウトした）接続のレート制限バケットを破棄する
+	h.clients.SetOnEvict(func(addr string) { h.rateLimiter.Forget(addr) })
+	return h
 }
 
 // SetUnitIdEnabled sets whether a unit ID responds
@@ -0,0 +1,245 @@
+package modbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestModbusServer_Start_SerialPortNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *ModbusConfig
+	}{
+		{"RTU", DefaultRTUConfig()},
+		{"ASCII", DefaultASCIIConfig()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.SerialPort = "/dev/nonexistent-serial-port"
+			srv := NewModbusServer(tt.config, NewModbusDataStore(10, 10, 10, 10))
+
+			if err := srv.Start(context.Background()); err == nil {
+				srv.Stop()
+				t.Fatal("expected error for non-existent serial port")
+			}
+
+			// Running のままにならず Error 状態になること
+			if srv.Status() != protocol.StatusError {
+				t.Errorf("expected status Error, got %s", srv.Status())
+			}
+			if srv.LastError() == nil {
+				t.Error("expected LastError to be set")
+			}
+
+			// 停止するとエラーはクリアされる
+			if err := srv.Stop(); err != nil {
+				t.Fatalf("Stop failed: %v", err)
+			}
+			if srv.Status() != protocol.StatusStopped {
+				t.Errorf("expected status Stopped, got %s", srv.Status())
+			}
+			if srv.LastError() != nil {
+				t.Errorf("expected LastError to be cleared, got %v", srv.LastError())
+			}
+		})
+	}
+}
+
+func TestModbusServerFactory_AutoReconnectSetting(t *testing.T) {
+	factory := NewModbusRTUServerFactory()
+	cfg, err := factory.MapToConfig("", map[string]interface{}{"serialPort": "COM3", "autoReconnect": "true"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if got := factory.ConfigToMap(cfg)["autoReconnect"]; got != "true" {
+		t.Errorf("ConfigToMap autoReconnect = %v, want true", got)
+	}
+
+	srv, err := factory.CreateServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	ms := srv.(*ModbusServer)
+	ms.innerServer = ms.newInnerServer(ms.config)
+	if !ms.innerServer.autoReconnect {
+		t.Error("inner server should be created with autoReconnect enabled")
+	}
+
+	// 待ち受けに関わらない設定なので作成済みの内部サーバーに反映される
+	off := cfg.Clone().(*ModbusConfig)
+	off.AutoReconnect = false
+	if err := ms.UpdateConfig(off); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if ms.innerServer.autoReconnect {
+		t.Error("UpdateConfig should disable autoReconnect on the inner server")
+	}
+}
+
+func TestModbusServerFactory_TransportFields(t *testing.T) {
+	for _, factory := range []*ModbusServerFactory{NewModbusTCPServerFactory(), NewModbusRTUServerFactory(), NewModbusASCIIServerFactory()} {
+		cfg := factory.CreateConfigFromVariant("").(*ModbusConfig)
+		settings := factory.ConfigToMap(cfg)
+		for _, name := range factory.TransportFields("") {
+			// sameTransport は ConfigToMap の値で比較するため、一覧のフィールドはすべて含まれている必要がある
+			if _, ok := settings[name]; !ok {
+				t.Errorf("%s: transport field %q is missing from ConfigToMap", factory.fixedVariant, name)
+			}
+			// 一覧のフィールドが変われば待ち受けの設定が変わったとみなす
+			changed, err := factory.MapToConfig("", withChangedSetting(settings, name))
+			if err != nil {
+				t.Fatalf("%s: MapToConfig: %v", factory.fixedVariant, err)
+			}
+			if cfg.sameTransport(changed.(*ModbusConfig)) {
+				t.Errorf("%s: changing %q should change the transport", factory.fixedVariant, name)
+			}
+		}
+	}
+}
+
+// withChangedSetting は settings を複製し、name の値を別の値に変えたものを返す
+func withChangedSetting(settings map[string]interface{}, name string) map[string]interface{} {
+	out := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		out[k] = v
+	}
+	switch v := settings[name].(type) {
+	case int:
+		out[name] = v + 1
+	case string:
+		if v == "false" {
+			out[name] = "true"
+		} else {
+			out[name] = v + "x"
+		}
+	}
+	return out
+}
+
+func TestModbusServer_SetEventEmitterAfterStart(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	if _, err := client.ReadRegisters(0, 1, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("ReadRegisters: %v", err)
+	}
+
+	// 起動後に設定したエミッターにも以降のリクエストのイベントが届く
+	emitter := protocol.NewRecordingEmitter()
+	srv.SetEventEmitter(emitter)
+	for i := 0; i < 2; i++ {
+		if _, err := client.ReadRegisters(0, 1, modbus.HOLDING_REGISTER); err != nil {
+			t.Fatalf("ReadRegisters: %v", err)
+		}
+	}
+	if got := emitter.Snapshot(); got.Rx != 2 || got.Tx != 2 {
+		t.Errorf("rx/tx = %d/%d, want 2/2", got.Rx, got.Tx)
+	}
+
+	// 差し替えると古いエミッターには届かない
+	next := protocol.NewRecordingEmitter()
+	srv.SetEventEmitter(next)
+	if _, err := client.ReadRegisters(0, 1, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("ReadRegisters: %v", err)
+	}
+	if got := emitter.Snapshot(); got.Rx != 2 {
+		t.Errorf("replaced emitter rx = %d, want 2", got.Rx)
+	}
+	if got := next.Snapshot(); got.Rx != 1 {
+		t.Errorf("new emitter rx = %d, want 1", got.Rx)
+	}
+}
+
+func TestModbusServer_NativeTCP_SetEventEmitterAfterStart(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	cfg.NativeTCP = true
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	// 起動後に設定したエミッターにも接続数とリクエストのイベントが届く
+	emitter := protocol.NewRecordingEmitter()
+	srv.SetEventEmitter(emitter)
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	if _, err := client.ReadRegisters(0, 1, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("ReadRegisters: %v", err)
+	}
+	got := emitter.Snapshot()
+	if len(got.ConnectionCounts) == 0 || got.ConnectionCounts[0] != 1 {
+		t.Errorf("connection counts = %v, want [1 ...]", got.ConnectionCounts)
+	}
+	if got.Rx != 1 || got.Tx != 1 {
+		t.Errorf("rx/tx = %d/%d, want 1/1", got.Rx, got.Tx)
+	}
+
+	// TCP の UnitID 0 はブロードキャストではないため、既定の待ち受けと同じくイベントカウンタに数える
+	client.SetUnitId(0)
+	if _, err := client.ReadRegisters(0, 1, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("ReadRegisters(unit 0): %v", err)
+	}
+	if n, _ := srv.CommEventCount(); n != 2 {
+		t.Errorf("CommEventCount = %d, want 2", n)
+	}
+}
+
+func TestRTUDataStoreAdapter_SetEventEmitterAfterStart(t *testing.T) {
+	inner := NewServerWithHandler(DefaultRTUConfig(), NewDataStoreHandler(NewModbusDataStore(10, 10, 10, 10)))
+	adapter := NewRTUDataStoreAdapter(inner.dsHandler)
+	adapter.emitter = &inner.emitter
+	p := rtu.NewProcessor(adapter)
+
+	processFrame(t, p, 1, rtu.FuncReadCoils, 0, 0, 0, 1)
+	emitter := protocol.NewRecordingEmitter()
+	inner.SetEventEmitter(emitter)
+	processFrame(t, p, 1, rtu.FuncReadCoils, 0, 0, 0, 1)
+	if got := emitter.Snapshot(); got.Rx != 1 || got.Tx != 1 {
+		t.Errorf("rx/tx = %d/%d, want 1/1", got.Rx, got.Tx)
+	}
+}
+
+func TestModbusServer_UpdateConfig_KeepsHandlerState(t *testing.T) {
+	srv := NewModbusServer(DefaultTCPConfig(), NewModbusDataStore(10, 10, 10, 10))
+	srv.SetUnitIdEnabled(3, false)
+	srv.EnqueueFIFO(5, 42)
+	srv.handler.access.RecordRead(AreaHoldingRegs, 2, 1)
+	srv.handler.RecordCommEvent()
+	old := srv.handler
+
+	if err := srv.UpdateConfig(DefaultTCPConfig()); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+	if srv.handler == old {
+		t.Fatal("expected handler to be recreated while stopped")
+	}
+	if srv.IsUnitIdEnabled(3) {
+		t.Error("expected unit 3 to stay disabled")
+	}
+	if values, ok := srv.handler.fifo.Read(5); !ok || len(values) != 1 || values[0] != 42 {
+		t.Errorf("expected FIFO queue to be kept, got %v (ok=%v)", values, ok)
+	}
+	if counts := srv.GetAccessCounts(AreaHoldingRegs); len(counts) != 1 || counts[0].Reads != 1 {
+		t.Errorf("expected access counts to be kept, got %+v", counts)
+	}
+	if n, _ := srv.CommEventCount(); n != 1 {
+		t.Errorf("expected comm event count 1, got %d", n)
+	}
+}
@@ -0,0 +1,72 @@
+package modbus
+
+import (
+	"sync"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+)
+
+// FIFOQueues は FC 0x18 (Read FIFO Queue) で返すキューを FIFO ポインタアドレスごとに保持する。
+// 一度でも EnqueueFIFO されたポインタのみが読み取り対象になる。
+type FIFOQueues struct {
+	mu     sync.Mutex
+	queues map[uint16][]uint16
+}
+
+// NewFIFOQueues は新しい FIFOQueues を作成する
+func NewFIFOQueues() *FIFOQueues {
+	return &FIFOQueues{queues: make(map[uint16][]uint16)}
+}
+
+// Enqueue は pointer のキューの末尾に value を追加する。
+// 仕様上限（31件）を超える場合は最も古い値を捨てる。
+func (q *FIFOQueues) Enqueue(pointer, value uint16) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	queue := append(q.queues[pointer], value)
+	if len(queue) > rtu.MaxFIFOCount {
+		queue = queue[len(queue)-rtu.MaxFIFOCount:]
+	}
+	q.queues[pointer] = queue
+}
+
+// Read は pointer のキューの内容を古い順に返す（キューは変更しない）。
+// 設定されていないポインタの場合は false を返す。
+func (q *FIFOQueues) Read(pointer uint16) ([]uint16, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	queue, ok := q.queues[pointer]
+	if !ok {
+		return nil, false
+	}
+	return append([]uint16{}, queue...), true
+}
+
+// EnqueueFIFO は FC 0x18 で返す pointer のキューに value を追加する
+func (h *DataStoreHandler) EnqueueFIFO(pointer, value uint16) {
+	h.fifo.Enqueue(pointer, value)
+}
+
+// HandleReadFIFOQueue は FIFO キュー読み取りを処理する (FC 0x18)
+func (a *RTUDataStoreAdapter) HandleReadFIFOQueue(unitID byte, pointer uint16) ([]uint16, error) {
+	a.emitRxTx()
+	if !a.handler.IsUnitIdEnabled(unitID) {
+		return nil, rtu.ErrIllegalFunction
+	}
+	if err := a.checkBusy(); err != nil {
+		return nil, err
+	}
+	values, ok := a.handler.fifo.Read(pointer)
+	if !ok {
+		return nil, rtu.ErrIllegalDataAddress
+	}
+	return values, nil
+}
+
+// EnqueueFIFO は FC 0x18 (RTU/ASCII) で返す pointer のキューに value を追加する
+func (s *ModbusServer) EnqueueFIFO(pointer, value uint16) error {
+	s.handler.EnqueueFIFO(pointer, value)
+	return nil
+}
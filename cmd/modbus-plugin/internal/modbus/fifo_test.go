@@ -0,0 +1,66 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/domain/protocol"
+)
+
+var _ protocol.FIFOQueuer = (*ModbusServer)(nil)
+
+// readFIFOFrame は FC 0x18 のリクエストフレームを組み立てて Processor で処理する
+func readFIFOFrame(t *testing.T, p *rtu.Processor, unitID byte, pointer uint16) []byte {
+	t.Helper()
+	frame := rtu.AppendCRC([]byte{unitID, rtu.FuncReadFIFOQueue, byte(pointer >> 8), byte(pointer)})
+	req, err := rtu.ParseRequest(frame)
+	if err != nil {
+		t.Fatalf("ParseRequest: %v", err)
+	}
+	return p.Process(req)
+}
+
+func TestReadFIFOQueue_RTU(t *testing.T) {
+	srv := NewModbusServer(DefaultRTUConfig(), NewModbusDataStore(10, 10, 10, 10))
+	srv.EnqueueFIFO(100, 0x1234)
+	srv.EnqueueFIFO(100, 0xABCD)
+	p := rtu.NewProcessor(NewRTUDataStoreAdapter(srv.handler))
+
+	got := readFIFOFrame(t, p, 1, 100)
+	want := rtu.AppendCRC([]byte{1, 0x18, 0x00, 0x06, 0x00, 0x02, 0x12, 0x34, 0xAB, 0xCD})
+	if !bytes.Equal(got, want) {
+		t.Errorf("response = % X, want % X", got, want)
+	}
+
+	// 読み取ってもキューは変わらない
+	if again := readFIFOFrame(t, p, 1, 100); !bytes.Equal(again, want) {
+		t.Errorf("second read = % X, want % X", again, want)
+	}
+
+	// 設定されていないポインタは Illegal Data Address
+	if got := readFIFOFrame(t, p, 1, 200); !bytes.Equal(got, rtu.BuildExceptionResponse(1, 0x18, rtu.ExceptionIllegalDataAddress)) {
+		t.Errorf("unknown pointer = % X", got)
+	}
+}
+
+func TestFIFOQueues_KeepsLatest31(t *testing.T) {
+	q := NewFIFOQueues()
+	for i := 0; i < 40; i++ {
+		q.Enqueue(5, uint16(i))
+	}
+	values, ok := q.Read(5)
+	if !ok || len(values) != rtu.MaxFIFOCount {
+		t.Fatalf("Read = %v, %v; want %d values", values, ok, rtu.MaxFIFOCount)
+	}
+	if values[0] != 9 || values[len(values)-1] != 39 {
+		t.Errorf("values = %v, want 9..39", values)
+	}
+}
+
+func TestReadFIFOQueue_ASCIIResponse(t *testing.T) {
+	got := rtu.BuildASCIIReadFIFOResponse(1, []uint16{0x0001})
+	if want := rtu.BuildASCIIFrame([]byte{1, 0x18, 0x00, 0x04, 0x00, 0x01, 0x00, 0x01}); !bytes.Equal(got, want) {
+		t.Errorf("ASCII response = %q, want %q", got, want)
+	}
+}
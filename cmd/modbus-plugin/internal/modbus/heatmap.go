@@ -0,0 +1,75 @@
+package modbus
+
+import (
+	"sort"
+	"sync"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// AccessTracker はマスターからの読み取り・書き込み回数をエリア・アドレスごとに数える。
+// アクセスされたアドレスのみを保持する（エリア全体分の配列は確保しない）。
+type AccessTracker struct {
+	mu     sync.Mutex
+	counts map[string]map[uint32]*protocol.AccessCount
+}
+
+// NewAccessTracker は新しい AccessTracker を作成する
+func NewAccessTracker() *AccessTracker {
+	return &AccessTracker{counts: make(map[string]map[uint32]*protocol.AccessCount)}
+}
+
+// RecordRead は area の address から quantity 個のアドレスの読み取り回数を増やす
+func (t *AccessTracker) RecordRead(area string, address uint32, quantity int) {
+	t.record(area, address, quantity, false)
+}
+
+// RecordWrite は area の address から quantity 個のアドレスの書き込み回数を増やす
+func (t *AccessTracker) RecordWrite(area string, address uint32, quantity int) {
+	t.record(area, address, quantity, true)
+}
+
+func (t *AccessTracker) record(area string, address uint32, quantity int, write bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byAddr := t.counts[area]
+	if byAddr == nil {
+		byAddr = make(map[uint32]*protocol.AccessCount)
+		t.counts[area] = byAddr
+	}
+	for i := 0; i < quantity; i++ {
+		addr := address + uint32(i)
+		c := byAddr[addr]
+		if c == nil {
+			c = &protocol.AccessCount{Address: addr}
+			byAddr[addr] = c
+		}
+		if write {
+			c.Writes++
+		} else {
+			c.Reads++
+		}
+	}
+}
+
+// Snapshot は area でアクセスされたアドレスの回数をアドレス順で返す
+func (t *AccessTracker) Snapshot(area string) []protocol.AccessCount {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byAddr := t.counts[area]
+	result := make([]protocol.AccessCount, 0, len(byAddr))
+	for _, c := range byAddr {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Address < result[j].Address })
+	return result
+}
+
+// Reset は記録をすべて破棄する
+func (t *AccessTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.counts = make(map[string]map[uint32]*protocol.AccessCount)
+}
@@ -0,0 +1,78 @@
+package modbus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+var _ protocol.AccessCounter = (*ModbusServer)(nil)
+
+func TestModbusServer_AccessCounts_TCP(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	for i := 0; i < 3; i++ {
+		if _, err := client.ReadRegisters(0, 2, modbus.HOLDING_REGISTER); err != nil {
+			t.Fatalf("ReadRegisters: %v", err)
+		}
+	}
+	if err := client.WriteRegister(1, 5); err != nil {
+		t.Fatalf("WriteRegister: %v", err)
+	}
+	if err := client.WriteRegisters(1, []uint16{6, 7}); err != nil {
+		t.Fatalf("WriteRegisters: %v", err)
+	}
+	// 範囲外の読み取りは数えない
+	if _, err := client.ReadRegisters(9, 2, modbus.HOLDING_REGISTER); err == nil {
+		t.Fatal("expected out-of-range read to fail")
+	}
+
+	want := []protocol.AccessCount{
+		{Address: 0, Reads: 3},
+		{Address: 1, Reads: 3, Writes: 2},
+		{Address: 2, Writes: 1},
+	}
+	if got := srv.GetAccessCounts(AreaHoldingRegs); !reflect.DeepEqual(got, want) {
+		t.Errorf("holding registers = %+v, want %+v", got, want)
+	}
+	if got := srv.GetAccessCounts(AreaInputRegs); len(got) != 0 {
+		t.Errorf("input registers = %+v, want none", got)
+	}
+
+	srv.ResetAccessCounts()
+	if got := srv.GetAccessCounts(AreaHoldingRegs); len(got) != 0 {
+		t.Errorf("after reset = %+v, want none", got)
+	}
+}
+
+func TestRTUDataStoreAdapter_AccessCounts(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(10, 10, 10, 10))
+	adapter := NewRTUDataStoreAdapter(handler)
+
+	if _, err := adapter.HandleReadInputRegisters(1, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.HandleWriteMultipleCoils(1, 0, []bool{true, false}); err != nil {
+		t.Fatal(err)
+	}
+	if got := handler.access.Snapshot(AreaInputRegs); len(got) != 3 || got[0].Address != 2 || got[2].Reads != 1 {
+		t.Errorf("input registers = %+v", got)
+	}
+	if got := handler.access.Snapshot(AreaCoils); !reflect.DeepEqual(got, []protocol.AccessCount{{Address: 0, Writes: 1}, {Address: 1, Writes: 1}}) {
+		t.Errorf("coils = %+v", got)
+	}
+}
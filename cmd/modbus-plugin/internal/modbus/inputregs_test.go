@@ -0,0 +1,96 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+// 入力レジスタにはマスターからの書き込み経路がない。
+// FC 06/16 は常に保持レジスタに書き込み、入力レジスタはスクリプト等のホスト側の書き込みだけを反映する。
+
+// TestInputRegisters_NoMasterWrite_TCP は TCP の FC 06/16 が入力レジスタを変更しないことを確認する
+func TestInputRegisters_NoMasterWrite_TCP(t *testing.T) {
+	// 保持レジスタより入力レジスタを大きくし、保持レジスタ範囲外の書き込みが入力レジスタに落ちないことも確認する
+	store := NewModbusDataStore(10, 10, 5, 20)
+	_ = store.WriteWord(AreaInputRegs, 0, 111)
+	_ = store.WriteWord(AreaInputRegs, 10, 777)
+
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	srv := NewModbusServer(cfg, store)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+
+	// FC 06/16 は保持レジスタに書き込まれる
+	if err := client.WriteRegister(0, 1); err != nil {
+		t.Fatalf("WriteRegister: %v", err)
+	}
+	if err := client.WriteRegisters(1, []uint16{2, 3}); err != nil {
+		t.Fatalf("WriteRegisters: %v", err)
+	}
+	if got, _ := store.ReadWords(AreaHoldingRegs, 0, 3); got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("holding registers = %v, want [1 2 3]", got)
+	}
+
+	// 保持レジスタの範囲外は入力レジスタの範囲内でも拒否される
+	if err := client.WriteRegister(10, 1); !errors.Is(err, modbus.ErrIllegalDataAddress) {
+		t.Errorf("FC06 beyond holding registers: err = %v, want illegal data address", err)
+	}
+	if err := client.WriteRegisters(10, []uint16{1, 2}); !errors.Is(err, modbus.ErrIllegalDataAddress) {
+		t.Errorf("FC16 beyond holding registers: err = %v, want illegal data address", err)
+	}
+
+	// 入力レジスタはホスト側で書いた値のまま
+	for addr, want := range map[uint16]uint16{0: 111, 10: 777} {
+		got, err := client.ReadRegister(addr, modbus.INPUT_REGISTER)
+		if err != nil {
+			t.Fatalf("ReadRegister input %d: %v", addr, err)
+		}
+		if got != want {
+			t.Errorf("input register %d = %d, want %d", addr, got, want)
+		}
+	}
+}
+
+// TestInputRegisters_NoMasterWrite_RTU は RTU の FC 06/16 が入力レジスタを変更しないことを確認する
+func TestInputRegisters_NoMasterWrite_RTU(t *testing.T) {
+	store := NewModbusDataStore(10, 10, 5, 20)
+	_ = store.WriteWord(AreaInputRegs, 10, 777)
+	srv := NewModbusServer(DefaultRTUConfig(), store)
+	adapter := NewRTUDataStoreAdapter(srv.handler)
+
+	// FC 06: アドレス 10 に 1 を書き込む
+	single := []byte{1, rtu.FuncWriteSingleRegister, 0, 0, 0, 0}
+	binary.BigEndian.PutUint16(single[2:], 10)
+	binary.BigEndian.PutUint16(single[4:], 1)
+	// FC 16: アドレス 10 から 1 ワード書き込む
+	multiple := []byte{1, rtu.FuncWriteMultipleRegisters, 0, 10, 0, 1, 2, 0, 1}
+
+	for _, frame := range [][]byte{single, multiple} {
+		resp, err := rtu.Loopback(adapter, rtu.AppendCRC(frame))
+		if err != nil {
+			t.Fatalf("Loopback FC%02X: %v", frame[1], err)
+		}
+		_, ex, ok := rtu.DecodeExceptionResponse(resp[:len(resp)-2])
+		if !ok || ex.Code != rtu.ExceptionIllegalDataAddress {
+			t.Errorf("FC%02X response = % X, want illegal data address exception", frame[1], resp)
+		}
+	}
+
+	if got, _ := store.ReadWord(AreaInputRegs, 10); got != 777 {
+		t.Errorf("input register 10 = %d, want 777", got)
+	}
+}
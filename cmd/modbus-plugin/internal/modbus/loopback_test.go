@@ -0,0 +1,94 @@
+package modbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+// TestEndToEnd_RTULoopback は RTU フレームを Processor に通して保持レジスタを読み取る
+func TestEndToEnd_RTULoopback(t *testing.T) {
+	store := NewModbusDataStore(10, 10, 10, 10)
+	_ = store.WriteWord(AreaHoldingRegs, 2, 1234)
+	srv := NewModbusServer(DefaultRTUConfig(), store)
+
+	resp, err := rtu.Loopback(NewRTUDataStoreAdapter(srv.handler), rtu.BuildReadRequest(1, rtu.FuncReadHoldingRegisters, 2, 1))
+	if err != nil {
+		t.Fatalf("Loopback: %v", err)
+	}
+	values, err := rtu.ParseReadRegistersResponse(resp)
+	if err != nil {
+		t.Fatalf("ParseReadRegistersResponse: %v", err)
+	}
+	if len(values) != 1 || values[0] != 1234 {
+		t.Errorf("holding register 2 = %v, want [1234]", values)
+	}
+}
+
+// TestEndToEnd_TCP はローカルホストで起動した TCP サーバーから保持レジスタを読み取る
+func TestEndToEnd_TCP(t *testing.T) {
+	store := NewModbusDataStore(10, 10, 10, 10)
+	_ = store.WriteWord(AreaHoldingRegs, 0, 4321)
+
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	srv := NewModbusServer(cfg, store)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	got, err := client.ReadRegister(0, modbus.HOLDING_REGISTER)
+	if err != nil {
+		t.Fatalf("ReadRegister: %v", err)
+	}
+	if got != 4321 {
+		t.Errorf("holding register 0 = %d, want 4321", got)
+	}
+}
+
+// TestEndToEnd_NativeTCP は nativeTCP 設定で起動したサーバーから Modbus TCP クライアントで読み書きする
+func TestEndToEnd_NativeTCP(t *testing.T) {
+	store := NewModbusDataStore(10, 10, 10, 10)
+	_ = store.WriteWord(AreaHoldingRegs, 0, 4321)
+
+	f := NewModbusTCPServerFactory()
+	cfg, err := f.MapToConfig("", map[string]interface{}{"tcpAddress": "127.0.0.1", "tcpPort": testutil.FreeTCPPort(t), "nativeTCP": "true"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	mc := cfg.(*ModbusConfig)
+	if !mc.NativeTCP || f.ConfigToMap(mc)["nativeTCP"] != "true" {
+		t.Fatal("nativeTCP setting should round-trip")
+	}
+	srv := NewModbusServer(mc, store)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+	if srv.innerServer.nativeTCP == nil {
+		t.Fatal("native TCP server should be used")
+	}
+
+	client := testutil.DialModbusTCP(t, mc.TCPPort, 5*time.Second)
+	got, err := client.ReadRegister(0, modbus.HOLDING_REGISTER)
+	if err != nil {
+		t.Fatalf("ReadRegister: %v", err)
+	}
+	if got != 4321 {
+		t.Errorf("holding register 0 = %d, want 4321", got)
+	}
+	if err := client.WriteRegister(1, 99); err != nil {
+		t.Fatalf("WriteRegister: %v", err)
+	}
+	if v, _ := store.ReadWord(AreaHoldingRegs, 1); v != 99 {
+		t.Errorf("holding register 1 = %d, want 99", v)
+	}
+}
@@ -0,0 +1,47 @@
+package modbus
+
+import (
+	"modbus_simulator/cmd/modbus-plugin/internal/mmapstore"
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/pkg/logging"
+)
+
+// defaultAreaSize は CreateDataStore が作成する各エリアの点数
+const defaultAreaSize = 65536
+
+// openMmapStore は Modbus の4エリアを持つメモリマップトストアを path に開く。
+// 各エリアの点数は設定の areaSizes（指定のないエリアは defaultAreaSize）。
+func openMmapStore(path string, sizes map[string]int) (*mmapstore.Store, error) {
+	size := func(area string) uint32 {
+		if n, ok := sizes[area]; ok {
+			return uint32(n)
+		}
+		return defaultAreaSize
+	}
+	return mmapstore.Open(path, []mmapstore.AreaSpec{
+		{ID: AreaCoils, DisplayName: "コイル (0x)", IsBit: true, Size: size(AreaCoils), OneOrigin: true},
+		{ID: AreaDiscreteInputs, DisplayName: "ディスクリート入力 (1x)", IsBit: true, Size: size(AreaDiscreteInputs), OneOrigin: true},
+		{ID: AreaHoldingRegs, DisplayName: "保持レジスタ (4x)", Size: size(AreaHoldingRegs), OneOrigin: true},
+		{ID: AreaInputRegs, DisplayName: "入力レジスタ (3x)", Size: size(AreaInputRegs), OneOrigin: true},
+	})
+}
+
+// createDataStore はヒープ上のデータストアを作成する
+func (f *ModbusServerFactory) createDataStore() protocol.DataStore {
+	return NewModbusDataStore(defaultAreaSize, defaultAreaSize, defaultAreaSize, defaultAreaSize)
+}
+
+// CreateDataStoreForConfig は設定に応じたデータストアを作成する。
+// mmapPath が設定されている場合はメモリマップトファイルを使い、開けない場合はヒープ上のストアにフォールバックする。
+func (f *ModbusServerFactory) CreateDataStoreForConfig(config protocol.ProtocolConfig) protocol.DataStore {
+	mc, ok := config.(*ModbusConfig)
+	if !ok || mc.MmapPath == "" {
+		return f.createDataStore()
+	}
+	store, err := openMmapStore(mc.MmapPath, mc.AreaSizes)
+	if err != nil {
+		logging.Default().Warn("failed to open mmap data store, falling back to memory", "path", mc.MmapPath, "error", err)
+		return f.createDataStore()
+	}
+	return store
+}
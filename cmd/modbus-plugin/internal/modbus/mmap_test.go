@@ -0,0 +1,66 @@
+package modbus
+
+import (
+	"path/filepath"
+	"testing"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/mmapstore"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestModbusServerFactory_MmapStore(t *testing.T) {
+	f := NewModbusTCPServerFactory()
+	cfg, err := f.MapToConfig("", map[string]interface{}{
+		"mmapPath":  filepath.Join(t.TempDir(), "modbus.mem"),
+		"areaSizes": "holdingRegisters=20000",
+	})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if got := f.ConfigToMap(cfg)["mmapPath"]; got == "" {
+		t.Error("mmapPath should be kept in the settings")
+	}
+
+	store, ok := f.CreateDataStoreForConfig(cfg).(*mmapstore.Store)
+	if !ok {
+		t.Fatalf("CreateDataStoreForConfig returned %T, want *mmapstore.Store", f.CreateDataStoreForConfig(cfg))
+	}
+	defer store.Close()
+	for _, a := range store.GetAreas() {
+		want := uint32(defaultAreaSize)
+		if a.ID == AreaHoldingRegs {
+			want = 20000
+		}
+		if a.Size != want {
+			t.Errorf("%s size = %d, want %d", a.ID, a.Size, want)
+		}
+	}
+
+	srv, err := f.CreateServer(cfg, store)
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	req := NewDataStoreRequestHandler(srv.(*ModbusServer).handler)
+	if _, err := req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 10, Quantity: 1, IsWrite: true, Args: []uint16{77}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if v, _ := store.ReadWord(AreaHoldingRegs, 10); v != 77 {
+		t.Errorf("holding register 10 = %d, want 77", v)
+	}
+}
+
+func TestModbusServerFactory_MmapStoreFallback(t *testing.T) {
+	f := NewModbusTCPServerFactory()
+	cfg, err := f.MapToConfig("", map[string]interface{}{"mmapPath": filepath.Join(t.TempDir(), "missing", "modbus.mem")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.CreateDataStoreForConfig(cfg).(*ModbusDataStore); !ok {
+		t.Error("expected fallback to ModbusDataStore when the file cannot be opened")
+	}
+
+	if _, ok := f.CreateDataStoreForConfig(DefaultTCPConfig()).(*ModbusDataStore); !ok {
+		t.Error("expected ModbusDataStore when mmap is disabled")
+	}
+}
@@ -0,0 +1,49 @@
+package modbus
+
+import (
+	"fmt"
+
+	"modbus_simulator/internal/domain/datastore"
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// outOfRangePolicySetter は範囲外読み取りポリシーを設定できるデータストア
+type outOfRangePolicySetter interface {
+	SetOutOfRangePolicy(policy datastore.OutOfRangePolicy)
+	OutOfRangePolicy() datastore.OutOfRangePolicy
+}
+
+// outOfRangePolicy は設定の範囲外読み取りポリシーを返す（未設定の場合は OutOfRangeError）
+func (c *ModbusConfig) outOfRangePolicy() datastore.OutOfRangePolicy {
+	if c.OutOfRangePolicy == "" {
+		return datastore.OutOfRangeError
+	}
+	return c.OutOfRangePolicy
+}
+
+// applyOutOfRangePolicy は設定の範囲外読み取りポリシーを
+// 共有データストアと UnitID 別データストア（今後作成されるものを含む）に適用する。
+func (s *ModbusServer) applyOutOfRangePolicy() error {
+	p := s.config.outOfRangePolicy()
+
+	store := s.handler.store
+	if u, ok := store.(interface{ Unwrap() protocol.DataStore }); ok {
+		store = u.Unwrap()
+	}
+	ps, ok := store.(outOfRangePolicySetter)
+	if !ok {
+		if p != datastore.OutOfRangeError {
+			return fmt.Errorf("data store does not support out-of-range policy")
+		}
+		return nil
+	}
+	ps.SetOutOfRangePolicy(p)
+
+	s.handler.unitMu.Lock()
+	defer s.handler.unitMu.Unlock()
+	s.handler.rangePolicy = p
+	for _, us := range s.handler.unitStores {
+		us.SetOutOfRangePolicy(p)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package modbus
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/domain/datastore"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestModbusDataStore_OutOfRangePolicy(t *testing.T) {
+	s := NewModbusDataStore(4, 4, 4, 4)
+	_ = s.WriteWords(AreaHoldingRegs, 0, []uint16{1, 2, 3, 4})
+	_ = s.WriteBits(AreaCoils, 0, []bool{true, false, false, true})
+
+	tests := []struct {
+		policy    datastore.OutOfRangePolicy
+		wantWords []uint16
+		wantBits  []bool
+		wantErr   error
+	}{
+		{datastore.OutOfRangeError, nil, nil, datastore.ErrAddressOutOfRange},
+		{datastore.OutOfRangeZero, []uint16{3, 4, 0, 0}, []bool{false, true, false, false}, nil},
+		{datastore.OutOfRangeWrap, []uint16{3, 4, 1, 2}, []bool{false, true, true, false}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			s.SetOutOfRangePolicy(tt.policy)
+
+			words, err := s.ReadWords(AreaHoldingRegs, 2, 4)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadWords err = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(words, tt.wantWords) {
+				t.Errorf("ReadWords = %v, want %v", words, tt.wantWords)
+			}
+
+			bits, err := s.ReadBits(AreaCoils, 2, 4)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadBits err = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(bits, tt.wantBits) {
+				t.Errorf("ReadBits = %v, want %v", bits, tt.wantBits)
+			}
+		})
+	}
+}
+
+func TestModbusDataStore_OutOfRangePolicy_DefaultIsError(t *testing.T) {
+	s := NewModbusDataStore(4, 4, 4, 4)
+	if got := s.OutOfRangePolicy(); got != datastore.OutOfRangeError {
+		t.Errorf("default policy = %q, want %q", got, datastore.OutOfRangeError)
+	}
+	// 範囲内の読み取りはポリシーに関係なく成功する
+	if _, err := s.ReadWords(AreaInputRegs, 0, 4); err != nil {
+		t.Errorf("in-range read failed: %v", err)
+	}
+}
+
+func TestModbusServer_OutOfRangePolicySetting(t *testing.T) {
+	factory := NewModbusTCPServerFactory()
+	srv := NewModbusServer(DefaultTCPConfig(), NewModbusDataStore(4, 4, 4, 4))
+	req := NewDataStoreRequestHandler(srv.handler)
+	read := func() ([]uint16, error) {
+		return req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 3, Quantity: 2})
+	}
+	update := func(policy string) {
+		t.Helper()
+		cfg, err := factory.MapToConfig("", map[string]interface{}{"outOfRangePolicy": policy, "perUnitStore": "true"})
+		if err != nil {
+			t.Fatalf("MapToConfig(%q): %v", policy, err)
+		}
+		if err := srv.UpdateConfig(cfg); err != nil {
+			t.Fatalf("UpdateConfig(%q): %v", policy, err)
+		}
+		req = NewDataStoreRequestHandler(srv.handler)
+	}
+
+	if _, err := read(); err == nil {
+		t.Error("expected error with default policy")
+	}
+	if got := factory.ConfigToMap(DefaultTCPConfig())["outOfRangePolicy"]; got != "error" {
+		t.Errorf("default outOfRangePolicy = %v, want error", got)
+	}
+
+	update("zero")
+	if vals, err := read(); err != nil || !reflect.DeepEqual(vals, []uint16{0, 0}) {
+		t.Errorf("zero policy: vals=%v err=%v", vals, err)
+	}
+	if got := factory.ConfigToMap(srv.Config())["outOfRangePolicy"]; got != "zero" {
+		t.Errorf("outOfRangePolicy = %v, want zero", got)
+	}
+
+	// UnitID 別ストアにも適用される
+	if _, err := req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 7, Addr: 3, Quantity: 2}); err != nil {
+		t.Errorf("unit 7 with zero policy: %v", err)
+	}
+
+	if _, err := factory.MapToConfig("", map[string]interface{}{"outOfRangePolicy": "bogus"}); !errors.Is(err, datastore.ErrInvalidData) {
+		t.Errorf("expected ErrInvalidData for unknown policy, got %v", err)
+	}
+	update("error")
+	if _, err := read(); err == nil {
+		t.Error("expected error after restoring error policy")
+	}
+	if _, err := req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 7, Addr: 3, Quantity: 2}); err == nil {
+		t.Error("unit 7 should follow the restored error policy")
+	}
+}
+
+func TestRTUProcessor_OutOfRangeZeroPolicy(t *testing.T) {
+	store := NewModbusDataStore(4, 4, 4, 4)
+	handler := NewDataStoreHandler(store)
+	store.SetOutOfRangePolicy(datastore.OutOfRangeZero)
+
+	frame := rtu.AppendCRC([]byte{0x01, rtu.FuncReadHoldingRegisters, 0x00, 0x03, 0x00, 0x02})
+	parsed, err := rtu.ParseRequest(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := rtu.NewProcessor(NewRTUDataStoreAdapter(handler)).Process(parsed)
+	if _, _, isEx := rtu.DecodeExceptionResponse(resp[:len(resp)-2]); isEx {
+		t.Errorf("expected normal response with zero policy, got % X", resp)
+	}
+}
@@ -0,0 +1,48 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"modbus_simulator/pkg/logging"
+)
+
+// ErrPrivilegedPort は権限不足で特権ポート（1024 未満）にバインドできなかった場合のエラー
+var ErrPrivilegedPort = errors.New("privileged port requires root")
+
+// privilegedPortLimit は Unix で root 権限が必要なポート番号の上限（この値未満）
+const privilegedPortLimit = 1024
+
+// suggestedUnprivilegedPort は特権ポートの代わりに案内するポート番号
+const suggestedUnprivilegedPort = 1502
+
+// classifyStartError は TCP サーバーの起動エラーが特権ポートの権限不足によるものであれば
+// ErrPrivilegedPort をラップした案内付きのエラーに変換する。それ以外はそのまま返す。
+func classifyStartError(port int, err error) error {
+	if err == nil || port <= 0 || port >= privilegedPortLimit || !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return fmt.Errorf("%w: ポート %d へのバインドには管理者権限が必要です。%d などの 1024 以上のポートを使用してください (%v)",
+		ErrPrivilegedPort, port, suggestedUnprivilegedPort, err)
+}
+
+// startWithPortFallback は内部サーバーを起動し、特権ポートの権限不足で失敗した場合は
+// FallbackPort が設定されていればそのポートで起動し直す
+func (s *ModbusServer) startWithPortFallback() error {
+	err := classifyStartError(s.config.TCPPort, s.innerServer.Start())
+	if !errors.Is(err, ErrPrivilegedPort) || s.config.FallbackPort <= 0 {
+		return err
+	}
+
+	logging.Default().Warn("privileged port denied, using fallback port",
+		"port", s.config.TCPPort, "fallbackPort", s.config.FallbackPort)
+	fallback := *s.config
+	fallback.TCPPort = s.config.FallbackPort
+	inner := s.newInnerServer(&fallback)
+	if ferr := inner.Start(); ferr != nil {
+		return fmt.Errorf("%w (fallback port %d: %v)", err, s.config.FallbackPort, ferr)
+	}
+	s.innerServer = inner
+	return nil
+}
@@ -0,0 +1,77 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestClassifyStartError(t *testing.T) {
+	denied := &net.OpError{Op: "listen", Net: "tcp", Err: os.NewSyscallError("bind", syscall.EACCES)}
+
+	err := classifyStartError(502, denied)
+	if !errors.Is(err, ErrPrivilegedPort) {
+		t.Fatalf("classifyStartError(502, EACCES) = %v, want ErrPrivilegedPort", err)
+	}
+	if !strings.Contains(err.Error(), "1502") {
+		t.Errorf("message does not suggest an unprivileged port: %v", err)
+	}
+
+	// 特権ポート以外・権限以外のエラーはそのまま
+	if err := classifyStartError(1502, denied); errors.Is(err, ErrPrivilegedPort) {
+		t.Errorf("classifyStartError(1502, EACCES) = %v", err)
+	}
+	inUse := &net.OpError{Op: "listen", Net: "tcp", Err: os.NewSyscallError("bind", syscall.EADDRINUSE)}
+	if err := classifyStartError(502, inUse); err != inUse {
+		t.Errorf("classifyStartError(502, EADDRINUSE) = %v", err)
+	}
+	if err := classifyStartError(502, nil); err != nil {
+		t.Errorf("classifyStartError(502, nil) = %v", err)
+	}
+}
+
+func TestModbusServer_Start_PrivilegedPort(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("root 以外の Unix でのみ特権ポートのバインドが拒否される")
+	}
+
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	err := srv.Start(context.Background())
+	if err == nil {
+		srv.Stop()
+		t.Skip("この環境では特権ポートにバインドできる")
+	}
+	if !errors.Is(err, ErrPrivilegedPort) {
+		t.Fatalf("Start error = %v, want ErrPrivilegedPort", err)
+	}
+	if srv.Status() != protocol.StatusError || !errors.Is(srv.LastError(), ErrPrivilegedPort) {
+		t.Errorf("status = %s, LastError = %v", srv.Status(), srv.LastError())
+	}
+
+	// 代替ポートが設定されていればそのポートで起動する
+	cfg.FallbackPort = testutil.FreeTCPPort(t)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start with fallback port: %v", err)
+	}
+	defer srv.Stop()
+	if srv.Status() != protocol.StatusRunning {
+		t.Errorf("status = %s, want Running", srv.Status())
+	}
+	client := testutil.DialModbusTCP(t, cfg.FallbackPort, time.Second)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Errorf("read via fallback port: %v", err)
+	}
+}
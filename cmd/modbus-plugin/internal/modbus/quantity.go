@@ -0,0 +1,177 @@
+package modbus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+)
+
+// specMaxReadQuantity は Modbus 仕様上の読み取り最大数（ファンクションコード別）
+var specMaxReadQuantity = map[uint8]int{
+	rtu.FuncReadCoils:            2000,
+	rtu.FuncReadDiscreteInputs:   2000,
+	rtu.FuncReadHoldingRegisters: 125,
+	rtu.FuncReadInputRegisters:   125,
+}
+
+// ReadQuantityLimit は最大読み取り数の設定1件（UnitID が 0 の場合は全 UnitID 共通）
+type ReadQuantityLimit struct {
+	UnitID   uint8 `json:"unitId,omitempty"`
+	Function uint8 `json:"function"`
+	Limit    int   `json:"limit"`
+}
+
+// parseReadQuantityLimits は maxReadQuantities 設定を解析する。
+// 形式は "FC=上限" または "UnitID:FC=上限" のカンマ区切り（例: "3=64,2:3=16"）。
+func parseReadQuantityLimits(v interface{}) ([]ReadQuantityLimit, error) {
+	if v == nil {
+		return nil, nil
+	}
+	text, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("maxReadQuantities: unsupported type %T", v)
+	}
+	var limits []ReadQuantityLimit
+	for _, entry := range strings.Split(text, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("maxReadQuantities: invalid entry %q", entry)
+		}
+		var l ReadQuantityLimit
+		fc := key
+		if unit, f, ok := strings.Cut(key, ":"); ok {
+			id, err := strconv.ParseUint(strings.TrimSpace(unit), 0, 8)
+			if err != nil {
+				return nil, fmt.Errorf("maxReadQuantities: invalid unit ID in %q", entry)
+			}
+			l.UnitID = uint8(id)
+			fc = f
+		}
+		code, err := strconv.ParseUint(strings.TrimSpace(fc), 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("maxReadQuantities: invalid function code in %q", entry)
+		}
+		if _, ok := specMaxReadQuantity[uint8(code)]; !ok {
+			return nil, fmt.Errorf("maxReadQuantities: unsupported function code for read limit: 0x%02X", code)
+		}
+		l.Function = uint8(code)
+		l.Limit, err = strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || l.Limit <= 0 {
+			return nil, fmt.Errorf("maxReadQuantities: invalid limit in %q", entry)
+		}
+		limits = append(limits, l)
+	}
+	return limits, nil
+}
+
+// formatReadQuantityLimits は limits を maxReadQuantities 設定の形式に変換する
+func formatReadQuantityLimits(limits []ReadQuantityLimit) string {
+	entries := make([]string, len(limits))
+	for i, l := range limits {
+		if l.UnitID == 0 {
+			entries[i] = fmt.Sprintf("%d=%d", l.Function, l.Limit)
+		} else {
+			entries[i] = fmt.Sprintf("%d:%d=%d", l.UnitID, l.Function, l.Limit)
+		}
+	}
+	return strings.Join(entries, ",")
+}
+
+// QuantityLimits は読み取りリクエストの最大数を管理する。
+// デフォルトは仕様上の最大値で、非準拠機器のシミュレーション用に全体または UnitID ごとに変更できる。
+// なお Modbus TCP はライブラリ側でも仕様上の最大値を検査するため、仕様値を超える設定は RTU/ASCII でのみ有効。
+type QuantityLimits struct {
+	mu         sync.RWMutex
+	limits     map[uint8]int
+	unitLimits map[uint8]map[uint8]int
+}
+
+// NewQuantityLimits は仕様上の最大値で初期化された QuantityLimits を作成する
+func NewQuantityLimits() *QuantityLimits {
+	return &QuantityLimits{
+		limits:     make(map[uint8]int),
+		unitLimits: make(map[uint8]map[uint8]int),
+	}
+}
+
+// SetMaxReadQuantity は全 UnitID 共通の最大読み取り数を設定する（0以下で仕様値に戻す）
+func (q *QuantityLimits) SetMaxReadQuantity(fc uint8, limit int) error {
+	if _, ok := specMaxReadQuantity[fc]; !ok {
+		return fmt.Errorf("unsupported function code for read limit: 0x%02X", fc)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if limit <= 0 {
+		delete(q.limits, fc)
+	} else {
+		q.limits[fc] = limit
+	}
+	return nil
+}
+
+// SetUnitMaxReadQuantity は指定 UnitID の最大読み取り数を設定する（0以下で共通設定に戻す）
+func (q *QuantityLimits) SetUnitMaxReadQuantity(unitId, fc uint8, limit int) error {
+	if _, ok := specMaxReadQuantity[fc]; !ok {
+		return fmt.Errorf("unsupported function code for read limit: 0x%02X", fc)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if limit <= 0 {
+		delete(q.unitLimits[unitId], fc)
+		return nil
+	}
+	if q.unitLimits[unitId] == nil {
+		q.unitLimits[unitId] = make(map[uint8]int)
+	}
+	q.unitLimits[unitId][fc] = limit
+	return nil
+}
+
+// Replace は全ての設定を limits で置き換える（limits に含まれないものは仕様値に戻る）
+func (q *QuantityLimits) Replace(limits []ReadQuantityLimit) error {
+	global := make(map[uint8]int)
+	units := make(map[uint8]map[uint8]int)
+	for _, l := range limits {
+		if _, ok := specMaxReadQuantity[l.Function]; !ok {
+			return fmt.Errorf("unsupported function code for read limit: 0x%02X", l.Function)
+		}
+		if l.UnitID == 0 {
+			global[l.Function] = l.Limit
+			continue
+		}
+		if units[l.UnitID] == nil {
+			units[l.UnitID] = make(map[uint8]int)
+		}
+		units[l.UnitID][l.Function] = l.Limit
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.limits = global
+	q.unitLimits = units
+	return nil
+}
+
+// MaxReadQuantity は UnitID とファンクションコードに適用される最大読み取り数を返す
+func (q *QuantityLimits) MaxReadQuantity(unitId, fc uint8) int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if limit, ok := q.unitLimits[unitId][fc]; ok {
+		return limit
+	}
+	if limit, ok := q.limits[fc]; ok {
+		return limit
+	}
+	return specMaxReadQuantity[fc]
+}
+
+// Allowed は読み取り数が上限以内かどうかを返す（0 件の読み取りは常に不正）
+func (q *QuantityLimits) Allowed(unitId, fc uint8, quantity uint16) bool {
+	return quantity > 0 && int(quantity) <= q.MaxReadQuantity(unitId, fc)
+}
@@ -0,0 +1,165 @@
+package modbus
+
+import (
+	"errors"
+	"testing"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestQuantityLimits_Defaults(t *testing.T) {
+	q := NewQuantityLimits()
+
+	tests := []struct {
+		fc  uint8
+		max int
+	}{
+		{rtu.FuncReadCoils, 2000},
+		{rtu.FuncReadDiscreteInputs, 2000},
+		{rtu.FuncReadHoldingRegisters, 125},
+		{rtu.FuncReadInputRegisters, 125},
+	}
+	for _, tt := range tests {
+		if got := q.MaxReadQuantity(1, tt.fc); got != tt.max {
+			t.Errorf("FC 0x%02X: expected %d, got %d", tt.fc, tt.max, got)
+		}
+		if !q.Allowed(1, tt.fc, uint16(tt.max)) {
+			t.Errorf("FC 0x%02X: expected %d to be allowed", tt.fc, tt.max)
+		}
+		if q.Allowed(1, tt.fc, uint16(tt.max+1)) {
+			t.Errorf("FC 0x%02X: expected %d to be rejected", tt.fc, tt.max+1)
+		}
+	}
+	if q.Allowed(1, rtu.FuncReadCoils, 0) {
+		t.Error("expected zero quantity to be rejected")
+	}
+}
+
+func TestQuantityLimits_UnitOverride(t *testing.T) {
+	q := NewQuantityLimits()
+	if err := q.SetMaxReadQuantity(rtu.FuncReadHoldingRegisters, 64); err != nil {
+		t.Fatalf("SetMaxReadQuantity failed: %v", err)
+	}
+	if err := q.SetUnitMaxReadQuantity(2, rtu.FuncReadHoldingRegisters, 16); err != nil {
+		t.Fatalf("SetUnitMaxReadQuantity failed: %v", err)
+	}
+
+	if got := q.MaxReadQuantity(1, rtu.FuncReadHoldingRegisters); got != 64 {
+		t.Errorf("unit 1: expected 64, got %d", got)
+	}
+	if got := q.MaxReadQuantity(2, rtu.FuncReadHoldingRegisters); got != 16 {
+		t.Errorf("unit 2: expected 16, got %d", got)
+	}
+
+	// 0 以下で既定値に戻る
+	q.SetUnitMaxReadQuantity(2, rtu.FuncReadHoldingRegisters, 0)
+	q.SetMaxReadQuantity(rtu.FuncReadHoldingRegisters, 0)
+	if got := q.MaxReadQuantity(2, rtu.FuncReadHoldingRegisters); got != 125 {
+		t.Errorf("expected reset to 125, got %d", got)
+	}
+
+	if err := q.SetMaxReadQuantity(0x10, 10); err == nil {
+		t.Error("expected error for non-read function code")
+	}
+}
+
+func TestDataStoreRequestHandler_OverLimitRead(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(3000, 3000, 3000, 3000))
+	req := NewDataStoreRequestHandler(handler)
+
+	_, err := req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 0, Quantity: 126})
+	if !errors.Is(err, modbus.ErrIllegalDataValue) {
+		t.Errorf("expected ErrIllegalDataValue for 126 registers, got %v", err)
+	}
+	if _, err := req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 0, Quantity: 125}); err != nil {
+		t.Errorf("expected 125 registers to succeed, got %v", err)
+	}
+
+	handler.quantityLimits.SetMaxReadQuantity(rtu.FuncReadCoils, 8)
+	_, err = req.HandleCoils(&modbus.CoilsRequest{UnitId: 1, Addr: 0, Quantity: 9})
+	if !errors.Is(err, modbus.ErrIllegalDataValue) {
+		t.Errorf("expected ErrIllegalDataValue for 9 coils, got %v", err)
+	}
+
+	// 書き込みは読み取り上限の対象外
+	args := make([]bool, 9)
+	if _, err := req.HandleCoils(&modbus.CoilsRequest{UnitId: 1, Addr: 0, Quantity: 9, IsWrite: true, Args: args}); err != nil {
+		t.Errorf("expected write to be unaffected, got %v", err)
+	}
+}
+
+func TestRTUDataStoreAdapter_OverLimitRead(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(3000, 3000, 3000, 3000))
+	adapter := NewRTUDataStoreAdapter(handler)
+
+	if _, err := adapter.HandleReadInputRegisters(1, 0, 126); !errors.Is(err, rtu.ErrIllegalDataValue) {
+		t.Errorf("expected ErrIllegalDataValue, got %v", err)
+	}
+
+	// 非準拠機器のシミュレーションとして UnitID 3 のみ上限を緩める
+	handler.quantityLimits.SetUnitMaxReadQuantity(3, rtu.FuncReadInputRegisters, 200)
+	if _, err := adapter.HandleReadInputRegisters(3, 0, 200); err != nil {
+		t.Errorf("expected unit 3 read of 200 to succeed, got %v", err)
+	}
+	if _, err := adapter.HandleReadInputRegisters(1, 0, 200); !errors.Is(err, rtu.ErrIllegalDataValue) {
+		t.Errorf("expected unit 1 to keep the spec limit, got %v", err)
+	}
+}
+
+func TestRTUDataStoreAdapter_AbsurdReadQuantity(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(65536, 65536, 65536, 65536))
+	adapter := NewRTUDataStoreAdapter(handler)
+
+	// 不正なクライアントが最大の Quantity を送っても、ストアを読む前に拒否される
+	if _, err := adapter.HandleReadCoils(1, 0, 0xFFFF); !errors.Is(err, rtu.ErrIllegalDataValue) {
+		t.Errorf("FC01: expected ErrIllegalDataValue, got %v", err)
+	}
+	if _, err := adapter.HandleReadDiscreteInputs(1, 0, 0xFFFF); !errors.Is(err, rtu.ErrIllegalDataValue) {
+		t.Errorf("FC02: expected ErrIllegalDataValue, got %v", err)
+	}
+	if _, err := adapter.HandleReadHoldingRegisters(1, 0, 0xFFFF); !errors.Is(err, rtu.ErrIllegalDataValue) {
+		t.Errorf("FC03: expected ErrIllegalDataValue, got %v", err)
+	}
+	if _, err := adapter.HandleReadInputRegisters(1, 0, 0xFFFF); !errors.Is(err, rtu.ErrIllegalDataValue) {
+		t.Errorf("FC04: expected ErrIllegalDataValue, got %v", err)
+	}
+}
+
+func TestModbusServerFactory_MaxReadQuantitiesSettings(t *testing.T) {
+	factory := NewModbusRTUServerFactory()
+	cfg, err := factory.MapToConfig("", map[string]interface{}{"maxReadQuantities": " 3=64, 2:0x04=200 "})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if got := factory.ConfigToMap(cfg)["maxReadQuantities"]; got != "3=64,2:4=200" {
+		t.Errorf("ConfigToMap maxReadQuantities = %v, want 3=64,2:4=200", got)
+	}
+	srv, err := factory.CreateServer(cfg, NewModbusDataStore(300, 300, 300, 300))
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	ms := srv.(*ModbusServer)
+	adapter := NewRTUDataStoreAdapter(ms.handler)
+	if _, err := adapter.HandleReadHoldingRegisters(1, 0, 65); !errors.Is(err, rtu.ErrIllegalDataValue) {
+		t.Errorf("expected ErrIllegalDataValue for 65 registers, got %v", err)
+	}
+	if _, err := adapter.HandleReadInputRegisters(2, 0, 200); err != nil {
+		t.Errorf("expected unit 2 read of 200 to succeed, got %v", err)
+	}
+
+	// 設定の更新で仕様値に戻る
+	if err := ms.UpdateConfig(DefaultRTUConfig()); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if got := ms.handler.quantityLimits.MaxReadQuantity(1, rtu.FuncReadHoldingRegisters); got != 125 {
+		t.Errorf("MaxReadQuantity after UpdateConfig = %d, want 125", got)
+	}
+
+	for _, bad := range []string{"16=10", "3=0", "3", "300:3=10"} {
+		if _, err := factory.MapToConfig("", map[string]interface{}{"maxReadQuantities": bad}); err == nil {
+			t.Errorf("MapToConfig(%q): expected error", bad)
+		}
+	}
+}
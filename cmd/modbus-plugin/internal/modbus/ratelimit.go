@@ -0,0 +1,90 @@
+package modbus
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited はレート制限超過時（busy モード）のエラー
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimiter は接続ごとのトークンバケットでリクエスト処理を制限する。
+// 低速なゲートウェイを模擬するためのもので、バースト幅は1リクエスト（均等間隔）とする。
+type RateLimiter struct {
+	mu      sync.Mutex
+	rps     int
+	busy    bool // true: 超過時にエラーを返す / false: 処理を遅延させる
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	// next は次のリクエストを処理できる時刻
+	next time.Time
+}
+
+// NewRateLimiter は制限なしの RateLimiter を作成する
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// SetLimit は1接続あたりの秒間リクエスト数を設定する（0以下で無制限）。
+// busy=true の場合、超過したリクエストは待たせずに ErrRateLimited を返す。
+func (r *RateLimiter) SetLimit(rps int, busy bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rps = rps
+	r.busy = busy
+	r.buckets = make(map[string]*tokenBucket)
+}
+
+// Limit は現在の設定を返す
+func (r *RateLimiter) Limit() (rps int, busy bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rps, r.busy
+}
+
+// Wait は接続 key のリクエストを処理してよくなるまで待機する。
+// busy モードで制限を超えている場合は待機せず ErrRateLimited を返す。
+func (r *RateLimiter) Wait(key string) error {
+	r.mu.Lock()
+	if r.rps <= 0 {
+		r.mu.Unlock()
+		return nil
+	}
+
+	interval := time.Second / time.Duration(r.rps)
+	now := time.Now()
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &tokenBucket{next: now}
+		r.buckets[key] = b
+	}
+
+	if b.next.Before(now) {
+		b.next = now
+	}
+	wait := b.next.Sub(now)
+	if wait > 0 && r.busy {
+		r.mu.Unlock()
+		return ErrRateLimited
+	}
+	// 枠を予約してからロック外で待機する
+	b.next = b.next.Add(interval)
+	r.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return nil
+}
+
+// Forget は切断された接続のバケットを破棄する
+func (r *RateLimiter) Forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.buckets, key)
+}
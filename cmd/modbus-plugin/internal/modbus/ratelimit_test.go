@@ -0,0 +1,100 @@
+package modbus
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	limiter := NewRateLimiter()
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := limiter.Wait("client"); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected no delay without limit, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_BurstIsPaced(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.SetLimit(100, false)
+
+	// 100 req/s で 21 リクエスト → 最初の1件を除き 10ms 間隔で処理され約 200ms かかる
+	start := time.Now()
+	for i := 0; i < 21; i++ {
+		if err := limiter.Wait("client"); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+	if elapsed < 180*time.Millisecond {
+		t.Errorf("expected burst to be paced out (>=180ms), took %v", elapsed)
+	}
+	if elapsed > time.Second {
+		t.Errorf("pacing took too long: %v", elapsed)
+	}
+}
+
+func TestRateLimiter_PerConnection(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.SetLimit(1, true)
+
+	// 接続ごとに独立したバケットを持つ
+	if err := limiter.Wait("client-a"); err != nil {
+		t.Fatalf("client-a first request failed: %v", err)
+	}
+	if err := limiter.Wait("client-b"); err != nil {
+		t.Fatalf("client-b first request failed: %v", err)
+	}
+}
+
+func TestRateLimiter_BusyMode(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.SetLimit(1, true)
+
+	if err := limiter.Wait("client"); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	// 制限超過は待機せずにエラーを返す
+	start := time.Now()
+	if err := limiter.Wait("client"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("busy mode should not delay, took %v", elapsed)
+	}
+}
+
+func TestModbusServerFactory_RateLimitSettings(t *testing.T) {
+	factory := NewModbusTCPServerFactory()
+	cfg, err := factory.MapToConfig("", map[string]interface{}{"rateLimit": float64(20), "rateLimitBusy": "true"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	srv, err := factory.CreateServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	ms := srv.(*ModbusServer)
+	if rps, busy := ms.handler.rateLimiter.Limit(); rps != 20 || !busy {
+		t.Errorf("Limit() = (%d, %v), want (20, true)", rps, busy)
+	}
+
+	settings := factory.ConfigToMap(cfg)
+	if settings["rateLimit"] != 20 || settings["rateLimitBusy"] != "true" {
+		t.Errorf("ConfigToMap = %v, want rateLimit=20 rateLimitBusy=true", settings)
+	}
+
+	// 設定の更新で解除できる
+	if err := ms.UpdateConfig(DefaultTCPConfig()); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if rps, _ := ms.handler.rateLimiter.Limit(); rps != 0 {
+		t.Errorf("Limit() after UpdateConfig = %d, want 0", rps)
+	}
+}
@@ -0,0 +1,55 @@
+package modbus
+
+import (
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// SetReadOnlyAreas は読み取り専用として扱うエリアを設定する。
+// 読み取り専用のエリアは GetAreas で ReadOnly=true として報告され、
+// Modbus クライアントからの書き込みは不正データアドレス例外で拒否される。
+// ホスト（UI・スクリプト）からの書き込みは制限しない。
+func (s *ModbusDataStore) SetReadOnlyAreas(ids []string) error {
+	areas, err := normalizeVisibleAreas(ids)
+	if err != nil {
+		return err
+	}
+	readOnly := make(map[string]bool, len(areas))
+	for _, id := range areas {
+		readOnly[id] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readOnlyAreas = readOnly
+	return nil
+}
+
+// IsReadOnly は指定エリアが読み取り専用かどうかを返す
+func (s *ModbusDataStore) IsReadOnly(area string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.readOnlyAreas[area]
+}
+
+// applyReadOnlyAreas は設定の readOnlyAreas を共有データストアに反映する
+func (s *ModbusServer) applyReadOnlyAreas() error {
+	store := s.store
+	if u, ok := store.(interface{ Unwrap() protocol.DataStore }); ok {
+		store = u.Unwrap()
+	}
+	ms, ok := store.(*ModbusDataStore)
+	if !ok {
+		return nil
+	}
+	return ms.SetReadOnlyAreas(s.config.ReadOnlyAreas)
+}
+
+// isReadOnly は Modbus クライアントからの書き込みを拒否するエリアかどうかを返す。
+// UnitID 別ストアも共有データストアの設定に従う。
+func (h *DataStoreHandler) isReadOnly(area string) bool {
+	store := h.store
+	if u, ok := store.(interface{ Unwrap() protocol.DataStore }); ok {
+		store = u.Unwrap()
+	}
+	ms, ok := store.(*ModbusDataStore)
+	return ok && ms.IsReadOnly(area)
+}
@@ -0,0 +1,105 @@
+package modbus
+
+import (
+	"context"
+	"testing"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+func areaReadOnly(s *ModbusDataStore, id string) bool {
+	for _, a := range s.GetAreas() {
+		if a.ID == id {
+			return a.ReadOnly
+		}
+	}
+	return false
+}
+
+func TestModbusServerFactory_ReadOnlyAreasConfig(t *testing.T) {
+	f := NewModbusTCPServerFactory()
+	cfg, err := f.MapToConfig("", map[string]interface{}{"tcpPort": 1502, "readOnlyAreas": "holdingRegisters"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if got := f.ConfigToMap(cfg)["readOnlyAreas"]; got != "holdingRegisters" {
+		t.Errorf("ConfigToMap readOnlyAreas = %v", got)
+	}
+
+	store := NewModbusDataStore(10, 10, 10, 10)
+	srv, err := f.CreateServer(cfg, store)
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	if !areaReadOnly(store, AreaHoldingRegs) {
+		t.Error("holdingRegisters should be reported as read-only")
+	}
+	if areaReadOnly(store, AreaCoils) {
+		t.Error("coils should not be read-only")
+	}
+
+	// Modbus クライアントからの書き込みは拒否される
+	handler := srv.(*ModbusServer).handler
+	req := NewDataStoreRequestHandler(handler)
+	_, err = req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 0, Quantity: 1, IsWrite: true, Args: []uint16{1}})
+	if err != modbus.ErrIllegalDataAddress {
+		t.Errorf("TCP write to read-only area: got %v, want ErrIllegalDataAddress", err)
+	}
+	if err := NewRTUDataStoreAdapter(handler).HandleWriteSingleRegister(1, 0, 1); err != rtu.ErrIllegalDataAddress {
+		t.Errorf("RTU write to read-only area: got %v, want ErrIllegalDataAddress", err)
+	}
+
+	// ホストからの書き込みは可能
+	if err := store.WriteWord(AreaHoldingRegs, 0, 5); err != nil {
+		t.Errorf("host write: %v", err)
+	}
+
+	// 設定を外すと書き込み可能に戻る
+	cfg, _ = f.MapToConfig("", map[string]interface{}{"tcpPort": 1502})
+	if err := srv.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if areaReadOnly(store, AreaHoldingRegs) {
+		t.Error("holdingRegisters still read-only after UpdateConfig")
+	}
+
+	if _, err := f.MapToConfig("", map[string]interface{}{"readOnlyAreas": "bogus"}); err == nil {
+		t.Error("expected error for unknown area in config")
+	}
+}
+
+func TestModbusServer_UpdateConfig_WhileRunning(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	store := NewModbusDataStore(10, 10, 10, 10)
+	srv := NewModbusServer(cfg, store)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+	handler := srv.handler
+
+	// 待ち受けに関わらない設定は実行中でも反映し、ハンドラーを引き継ぐ
+	updated := cfg.Clone().(*ModbusConfig)
+	updated.ReadOnlyAreas = []string{AreaCoils}
+	if err := srv.UpdateConfig(updated); err != nil {
+		t.Fatalf("UpdateConfig(readOnlyAreas): %v", err)
+	}
+	if !areaReadOnly(store, AreaCoils) {
+		t.Error("coils should be read-only after UpdateConfig while running")
+	}
+	if srv.handler != handler {
+		t.Error("handler should be kept while running")
+	}
+
+	// 待ち受けの設定は実行中に変更できない
+	moved := updated.Clone().(*ModbusConfig)
+	moved.TCPPort = cfg.TCPPort + 1
+	if err := srv.UpdateConfig(moved); err == nil {
+		t.Error("port change while running should fail")
+	}
+}
@@ -0,0 +1,159 @@
+package modbus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"modbus_simulator/internal/domain/datastore"
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// MaxAreaSize は1エリアの最大点数（Modbus のアドレスは16ビット）
+const MaxAreaSize = 65536
+
+// Resize はエリアの点数を変更する。
+// 拡張した部分はゼロ（false）で埋め、縮小した場合は末尾の値を破棄する。
+func (s *ModbusDataStore) Resize(area string, newSize int) error {
+	if newSize < 0 || newSize > MaxAreaSize {
+		return fmt.Errorf("%w: size %d (0-%d)", datastore.ErrInvalidData, newSize, MaxAreaSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch area {
+	case AreaCoils:
+		s.coils = resizeSlice(s.coils, newSize)
+	case AreaDiscreteInputs:
+		s.discreteInputs = resizeSlice(s.discreteInputs, newSize)
+	case AreaHoldingRegs:
+		s.holdingRegs = resizeSlice(s.holdingRegs, newSize)
+	case AreaInputRegs:
+		s.inputRegs = resizeSlice(s.inputRegs, newSize)
+	default:
+		return datastore.ErrAreaNotFound
+	}
+	return nil
+}
+
+// resizeSlice は長さ n の新しいスライスに既存の値をコピーする
+// （縮小時も元の配列を共有しないようにコピーする）
+func resizeSlice[T any](src []T, n int) []T {
+	dst := make([]T, n)
+	copy(dst, src)
+	return dst
+}
+
+// AreaSizes は全エリア（非表示のエリアを含む）の点数を返す
+func (s *ModbusDataStore) AreaSizes() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return map[string]int{
+		AreaCoils:          len(s.coils),
+		AreaDiscreteInputs: len(s.discreteInputs),
+		AreaHoldingRegs:    len(s.holdingRegs),
+		AreaInputRegs:      len(s.inputRegs),
+	}
+}
+
+// areaResizer は Resize をサポートするデータストア
+type areaResizer interface {
+	Resize(area string, newSize int) error
+	AreaSizes() map[string]int
+}
+
+// parseAreaSizes は areaSizes 設定（"holdingRegisters=20000,coils=1000" 形式）を解析する
+func parseAreaSizes(v interface{}) (map[string]int, error) {
+	if v == nil {
+		return nil, nil
+	}
+	text, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("areaSizes: unsupported type %T", v)
+	}
+	var sizes map[string]int
+	for _, entry := range strings.Split(text, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		area, value, ok := strings.Cut(entry, "=")
+		area = strings.TrimSpace(area)
+		if !ok || !isModbusArea(area) {
+			return nil, fmt.Errorf("areaSizes: invalid entry %q", entry)
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || size < 0 || size > MaxAreaSize {
+			return nil, fmt.Errorf("areaSizes: invalid size in %q (0-%d)", entry, MaxAreaSize)
+		}
+		if sizes == nil {
+			sizes = make(map[string]int)
+		}
+		sizes[area] = size
+	}
+	return sizes, nil
+}
+
+// formatAreaSizes は sizes を areaSizes 設定の形式（AreaOrder の順）に変換する
+func formatAreaSizes(sizes map[string]int) string {
+	var entries []string
+	for _, area := range AreaOrder {
+		if size, ok := sizes[area]; ok {
+			entries = append(entries, fmt.Sprintf("%s=%d", area, size))
+		}
+	}
+	return strings.Join(entries, ",")
+}
+
+// sameAreaSizes は2つの areaSizes 設定が同じかどうかを返す（nil と空は同じ）
+func sameAreaSizes(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for area, size := range a {
+		if other, ok := b[area]; !ok || other != size {
+			return false
+		}
+	}
+	return true
+}
+
+// applyAreaSizes は設定の areaSizes を共有データストアと作成済みの UnitID 別データストアに反映する。
+// 設定にないエリアはサーバー作成時の点数に戻す（拡張部分はゼロ埋め、縮小時は末尾を破棄）。
+func (s *ModbusServer) applyAreaSizes() error {
+	store := s.store
+	if u, ok := store.(interface{ Unwrap() protocol.DataStore }); ok {
+		store = u.Unwrap()
+	}
+	r, ok := store.(areaResizer)
+	if !ok {
+		// メモリマップトストアの点数は開くときに決まるため、次にストアを開いたとき（プラグインではサーバーの起動時）に反映される
+		return nil
+	}
+	current := r.AreaSizes()
+	if s.baseAreaSizes == nil {
+		s.baseAreaSizes = current
+	}
+
+	s.handler.unitMu.RLock()
+	defer s.handler.unitMu.RUnlock()
+	for _, area := range AreaOrder {
+		size, ok := s.config.AreaSizes[area]
+		if !ok {
+			size = s.baseAreaSizes[area]
+		}
+		if size == current[area] {
+			continue
+		}
+		if err := r.Resize(area, size); err != nil {
+			return err
+		}
+		for _, us := range s.handler.unitStores {
+			if err := us.Resize(area, size); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package modbus
+
+import (
+	"errors"
+	"testing"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+func areaSize(t *testing.T, s *ModbusDataStore, area string) uint32 {
+	t.Helper()
+	for _, a := range s.GetAreas() {
+		if a.ID == area {
+			return a.Size
+		}
+	}
+	t.Fatalf("area %s not found", area)
+	return 0
+}
+
+func TestModbusDataStore_ResizeGrow(t *testing.T) {
+	s := NewModbusDataStore(8, 8, 10, 10)
+	_ = s.WriteWord(AreaHoldingRegs, 9, 0x1234)
+
+	if err := s.Resize(AreaHoldingRegs, 20); err != nil {
+		t.Fatalf("Resize failed: %v", err)
+	}
+	if got := areaSize(t, s, AreaHoldingRegs); got != 20 {
+		t.Errorf("size = %d, want 20", got)
+	}
+
+	// 既存値は保持され、拡張部分はゼロ
+	vals, err := s.ReadWords(AreaHoldingRegs, 9, 11)
+	if err != nil {
+		t.Fatalf("ReadWords after grow: %v", err)
+	}
+	if vals[0] != 0x1234 || vals[10] != 0 {
+		t.Errorf("values = %v", vals)
+	}
+	if _, err := s.ReadWord(AreaHoldingRegs, 20); !errors.Is(err, datastore.ErrAddressOutOfRange) {
+		t.Errorf("ReadWord(20) err = %v, want ErrAddressOutOfRange", err)
+	}
+}
+
+func TestModbusDataStore_ResizeShrink(t *testing.T) {
+	s := NewModbusDataStore(16, 8, 10, 10)
+	_ = s.WriteBit(AreaCoils, 3, true)
+	_ = s.WriteBit(AreaCoils, 12, true)
+
+	if err := s.Resize(AreaCoils, 4); err != nil {
+		t.Fatalf("Resize failed: %v", err)
+	}
+	if got := areaSize(t, s, AreaCoils); got != 4 {
+		t.Errorf("size = %d, want 4", got)
+	}
+	if v, _ := s.ReadBit(AreaCoils, 3); !v {
+		t.Error("coil 3 should be kept")
+	}
+	if _, err := s.ReadBits(AreaCoils, 2, 3); !errors.Is(err, datastore.ErrAddressOutOfRange) {
+		t.Errorf("ReadBits past end err = %v, want ErrAddressOutOfRange", err)
+	}
+	if err := s.WriteBit(AreaCoils, 12, false); !errors.Is(err, datastore.ErrAddressOutOfRange) {
+		t.Errorf("WriteBit(12) err = %v, want ErrAddressOutOfRange", err)
+	}
+
+	// 再拡張しても縮小で破棄した値は戻らない
+	_ = s.Resize(AreaCoils, 16)
+	if v, _ := s.ReadBit(AreaCoils, 12); v {
+		t.Error("coil 12 should be cleared after shrink and regrow")
+	}
+}
+
+func TestModbusDataStore_ResizeInvalid(t *testing.T) {
+	s := NewModbusDataStore(8, 8, 8, 8)
+	if err := s.Resize("unknown", 10); !errors.Is(err, datastore.ErrAreaNotFound) {
+		t.Errorf("unknown area err = %v", err)
+	}
+	if err := s.Resize(AreaInputRegs, MaxAreaSize+1); !errors.Is(err, datastore.ErrInvalidData) {
+		t.Errorf("oversize err = %v", err)
+	}
+	if err := s.Resize(AreaInputRegs, -1); !errors.Is(err, datastore.ErrInvalidData) {
+		t.Errorf("negative size err = %v", err)
+	}
+}
+
+func TestModbusServer_AreaSizesSetting(t *testing.T) {
+	factory := NewModbusTCPServerFactory()
+	store := NewModbusDataStore(8, 8, 8, 8)
+	cfg, err := factory.MapToConfig("", map[string]interface{}{"areaSizes": "holdingRegisters=32", "perUnitStore": "true"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	created, err := factory.CreateServer(cfg, store)
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	srv := created.(*ModbusServer)
+	if got := areaSize(t, store, AreaHoldingRegs); got != 32 {
+		t.Errorf("shared size = %d, want 32", got)
+	}
+	if got := factory.ConfigToMap(cfg)["areaSizes"]; got != "holdingRegisters=32" {
+		t.Errorf("ConfigToMap areaSizes = %v", got)
+	}
+
+	// 作成済みの UnitID 別ストアも設定の変更に追従する
+	unit := srv.UnitDataStore(5).(*ModbusDataStore)
+	cfg, err = factory.MapToConfig("", map[string]interface{}{"areaSizes": "holdingRegisters=64,coils=4", "perUnitStore": "true"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if err := srv.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if got := areaSize(t, unit, AreaHoldingRegs); got != 64 {
+		t.Errorf("unit store size = %d, want 64", got)
+	}
+	if got := areaSize(t, store, AreaCoils); got != 4 {
+		t.Errorf("coils size = %d, want 4", got)
+	}
+
+	// 設定から外したエリアは作成時の点数に戻る
+	cfg, _ = factory.MapToConfig("", map[string]interface{}{"areaSizes": "", "perUnitStore": "true"})
+	if err := srv.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if got := areaSize(t, store, AreaHoldingRegs); got != 8 {
+		t.Errorf("restored size = %d, want 8", got)
+	}
+	if got := areaSize(t, unit, AreaCoils); got != 8 {
+		t.Errorf("restored unit coils size = %d, want 8", got)
+	}
+}
+
+func TestParseAreaSizes_Invalid(t *testing.T) {
+	for _, v := range []string{"unknown=10", "coils", "coils=-1", "coils=65537", "coils=x"} {
+		if _, err := parseAreaSizes(v); err == nil {
+			t.Errorf("parseAreaSizes(%q) should fail", v)
+		}
+	}
+}
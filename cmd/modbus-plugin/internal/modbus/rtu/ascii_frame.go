@@ -3,6 +3,7 @@ package rtu
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -62,7 +63,8 @@ func ParseASCIIFrame(frame []byte) ([]byte, error) {
 	return dataWithoutLRC, nil
 }
 
-// ParseASCIIRequest はASCIIフレームからリクエストを解析する
+// ParseASCIIRequest はASCIIフレームからリクエストを解析する。
+// ErrIllegalDataValue の場合は UnitID と FunctionCode のみを設定した Request も返す。
 func ParseASCIIRequest(frame []byte) (*Request, error) {
 	// ASCIIフレームをバイナリに変換
 	data, err := ParseASCIIFrame(frame)
@@ -70,8 +72,8 @@ func ParseASCIIRequest(frame []byte) (*Request, error) {
 		return nil, err
 	}
 
-	// 最小データ長チェック（UnitID + FC + データ2バイト = 4バイト）
-	if len(data) < 4 {
+	// 最小データ長チェック（UnitID + FC = 2バイト。データなしは FC 0x0B のみ）
+	if len(data) < 2 {
 		return nil, ErrFrameTooShort
 	}
 
@@ -98,31 +100,33 @@ func ParseASCIIRequest(frame []byte) (*Request, error) {
 		req.Quantity = 1
 		req.Data = data[4:6]
 
-	case FuncWriteMultipleCoils:
-		// 複数コイル書き込み: Address(2) + Quantity(2) + ByteCount(1) + Data(N)
-		if len(data) < 7 {
+	case FuncReadFIFOQueue:
+		// FIFO読み取り: FIFO Pointer Address(2)
+		if len(data) < 4 {
 			return nil, ErrFrameTooShort
 		}
 		req.Address = binary.BigEndian.Uint16(data[2:4])
-		req.Quantity = binary.BigEndian.Uint16(data[4:6])
-		byteCount := int(data[6])
-		if len(data) < 7+byteCount {
-			return nil, ErrFrameTooShort
-		}
-		req.Data = data[7 : 7+byteCount]
 
-	case FuncWriteMultipleRegisters:
-		// 複数レジスタ書き込み: Address(2) + Quantity(2) + ByteCount(1) + Data(N)
-		if len(data) < 7 {
+	case FuncDiagnostics:
+		// 診断: SubFunction(2) + Data(2)。SubFunction は Address に格納する
+		if len(data) < 6 {
 			return nil, ErrFrameTooShort
 		}
 		req.Address = binary.BigEndian.Uint16(data[2:4])
-		req.Quantity = binary.BigEndian.Uint16(data[4:6])
-		byteCount := int(data[6])
-		if len(data) < 7+byteCount {
-			return nil, ErrFrameTooShort
+		req.Data = data[4:6]
+
+	case FuncGetCommEventCounter:
+		// イベントカウンタ取得: データなし
+
+	case FuncWriteMultipleCoils, FuncWriteMultipleRegisters:
+		// 複数書き込み: Address(2) + Quantity(2) + ByteCount(1) + Data(N)
+		if err := parseWriteMultiple(req, data); err != nil {
+			if errors.Is(err, ErrIllegalDataValue) {
+				// 例外応答を返せるよう UnitID と FunctionCode を設定した req も返す
+				return req, err
+			}
+			return nil, err
 		}
-		req.Data = data[7 : 7+byteCount]
 
 	default:
 		return nil, fmt.Errorf("unsupported function code: 0x%02X", req.FunctionCode)
@@ -185,6 +189,11 @@ func BuildASCIIReadRegistersResponse(unitID, funcCode byte, values []uint16) []b
 	return BuildASCIIFrame(data)
 }
 
+// BuildASCIIReadFIFOResponse は FIFO 読み取りレスポンスを構築する (FC 0x18)
+func BuildASCIIReadFIFOResponse(unitID byte, values []uint16) []byte {
+	return BuildASCIIFrame(fifoResponseData(unitID, values))
+}
+
 // BuildASCIIWriteSingleResponse は単一書き込みレスポンスを構築する
 func BuildASCIIWriteSingleResponse(unitID, funcCode byte, address uint16, value uint16) []byte {
 	data := make([]byte, 6)
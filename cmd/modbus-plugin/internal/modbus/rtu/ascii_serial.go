@@ -65,7 +65,7 @@ func (sm *ASCIISerialManager) Open() error {
 		StopBits: stopBits,
 	}
 
-	port, err := serial.Open(sm.config.Port, mode)
+	port, err := openSerialPort(sm.config.Port, mode)
 	if err != nil {
 		return fmt.Errorf("failed to open serial port: %w", err)
 	}
@@ -74,6 +74,21 @@ func (sm *ASCIISerialManager) Open() error {
 	return nil
 }
 
+// Reopen は失われたポートを閉じて開き直す（Close 済みの場合はエラー）
+func (sm *ASCIISerialManager) Reopen() error {
+	sm.mu.Lock()
+	if sm.closed {
+		sm.mu.Unlock()
+		return fmt.Errorf("serial port closed")
+	}
+	if sm.port != nil {
+		sm.port.Close()
+		sm.port = nil
+	}
+	sm.mu.Unlock()
+	return sm.Open()
+}
+
 // Close はシリアルポートを閉じる
 func (sm *ASCIISerialManager) Close() error {
 	sm.mu.Lock()
@@ -139,7 +154,10 @@ func (sm *ASCIISerialManager) ReadFrame() ([]byte, error) {
 				return nil, fmt.Errorf("serial port closed")
 			}
 			sm.mu.Unlock()
-			continue
+
+			// タイムアウトは err == nil, n == 0 で返るため、ここに来るのは
+			// USB 抜去などでポートが失われた場合
+			return nil, fmt.Errorf("%w: %v", ErrPortLost, err)
 		}
 
 		if n == 0 {
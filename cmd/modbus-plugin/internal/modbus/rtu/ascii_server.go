@@ -3,30 +3,53 @@ package rtu
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
+
+	"modbus_simulator/pkg/logging"
 )
 
 // ASCIIServer はModbus ASCIIサーバーを表す
 type ASCIIServer struct {
-	mu        sync.Mutex
-	serial    *ASCIISerialManager
-	handler   RequestHandler
-	running   bool
-	ctx       context.Context
-	cancel    context.CancelFunc
-	wg        sync.WaitGroup
+	mu       sync.Mutex
+	serial   *ASCIISerialManager
+	handler  RequestHandler
+	running  bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	logger   *slog.Logger
+	watchdog *portWatchdog
 }
 
 // NewASCIIServer は新しいASCIIServerを作成する
 func NewASCIIServer(config SerialConfig, handler RequestHandler) *ASCIIServer {
 	return &ASCIIServer{
-		serial:  NewASCIISerialManager(config),
-		handler: handler,
+		serial:   NewASCIISerialManager(config),
+		handler:  handler,
+		logger:   logging.Default().With("protocol", "modbus-ascii", "port", config.Port),
+		watchdog: newPortWatchdog(),
 	}
 }
 
+// SetLogger はロガーを設定する（nil の場合は出力しない）
+func (s *ASCIIServer) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = logging.Discard()
+	}
+	s.mu.Lock()
+	s.logger = logger.With("protocol", "modbus-ascii", "port", s.serial.config.Port)
+	s.mu.Unlock()
+}
+
+func (s *ASCIIServer) log() *slog.Logger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logger
+}
+
 // Start はサーバーを起動する
 func (s *ASCIIServer) Start() error {
 	s.mu.Lock()
@@ -46,6 +69,7 @@ func (s *ASCIIServer) Start() error {
 	s.wg.Add(1)
 	go s.mainLoop()
 
+	s.watchdog.linkChanged(true)
 	return nil
 }
 
@@ -66,6 +90,7 @@ func (s *ASCIIServer) Stop() error {
 	// ゴルーチンの終了を待つ
 	s.wg.Wait()
 
+	s.watchdog.linkChanged(false)
 	return nil
 }
 
@@ -76,6 +101,22 @@ func (s *ASCIIServer) IsRunning() bool {
 	return s.running
 }
 
+// SetAutoReconnect はポート喪失時に自動で再接続を試みるかどうかを設定する。
+// 無効の場合、ポート喪失を検出するとサーバーは停止する。
+func (s *ASCIIServer) SetAutoReconnect(enabled bool) {
+	s.watchdog.setAutoReconnect(enabled)
+}
+
+// SetOnPortStateChanged はポート喪失（err != nil）・復旧（err == nil）時のコールバックを設定する
+func (s *ASCIIServer) SetOnPortStateChanged(cb func(err error)) {
+	s.watchdog.setOnStateChanged(cb)
+}
+
+// SetConnectionEmitter はシリアル回線の接続状態（接続数 0/1）の通知先を設定する
+func (s *ASCIIServer) SetConnectionEmitter(emitter ConnectionEmitter) {
+	s.watchdog.setConnectionEmitter(emitter)
+}
+
 func (s *ASCIIServer) mainLoop() {
 	defer s.wg.Done()
 
@@ -84,48 +125,108 @@ func (s *ASCIIServer) mainLoop() {
 		case <-s.ctx.Done():
 			return
 		default:
-			s.processNextRequest()
+		}
+
+		err := s.processNextRequest()
+		if err == nil {
+			s.watchdog.readSucceeded()
+			continue
+		}
+		if !s.watchdog.readFailed() {
+			sleepCtx(s.ctx, readFailureBackoff)
+			continue
+		}
+		if !s.watchdog.recover(s.ctx, err, s.serial.Reopen, s.log()) {
+			s.stopFromLoop()
+			return
 		}
 	}
 }
 
-func (s *ASCIIServer) processNextRequest() {
+// stopFromLoop はポート喪失で復旧できない場合に mainLoop 側からサーバーを停止する
+func (s *ASCIIServer) stopFromLoop() {
+	s.mu.Lock()
+	if s.running {
+		s.cancel()
+		s.running = false
+	}
+	s.mu.Unlock()
+	s.serial.Close()
+}
+
+// processNextRequest は1フレームを受信して処理する。
+// ポートが失われた場合のみエラーを返す（タイムアウトやフレーム異常は nil）。
+func (s *ASCIIServer) processNextRequest() error {
 	// フレームを読み取る
 	frame, err := s.serial.ReadFrame()
 	if err != nil {
+		if errors.Is(err, ErrPortLost) {
+			return err
+		}
 		// タイムアウトは正常なので無視
-		return
+		return nil
 	}
 
 	if len(frame) == 0 {
-		return
+		return nil
 	}
 
+	response := s.handleFrame(frame)
+	if response == nil {
+		return nil
+	}
+
+	// レスポンスを送信
+	if err := s.serial.Write(response); err != nil {
+		s.log().Warn("failed to write response", "error", err)
+	}
+	return nil
+}
+
+// handleFrame は受信フレーム1つを処理し、送信すべきレスポンスを返す（応答しない場合は nil）
+func (s *ASCIIServer) handleFrame(frame []byte) []byte {
+	logger := s.log()
+
 	// リクエストを解析
 	req, err := ParseASCIIRequest(frame)
 	if err != nil {
-		log.Printf("ASCII: failed to parse request: %v", err)
-		return
+		logger.Warn("failed to parse request", "error", err, "frameLen", len(frame))
+		// Quantity や ByteCount の不正には例外応答を返す
+		if req != nil && errors.Is(err, ErrIllegalDataValue) && s.handler.IsUnitIDEnabled(req.UnitID) {
+			return BuildASCIIExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalDataValue)
+		}
+		return nil
 	}
 
 	// UnitIDが無効な場合は応答しない
 	if !s.handler.IsUnitIDEnabled(req.UnitID) {
-		return
+		logger.Debug("request ignored", "unitId", req.UnitID, "funcCode", req.FunctionCode)
+		return nil
 	}
 
 	// リクエストを処理
 	response := s.processRequest(req)
 	if response == nil {
-		return
+		return nil
 	}
 
-	// レスポンスを送信
-	if err := s.serial.Write(response); err != nil {
-		log.Printf("ASCII: failed to write response: %v", err)
-	}
+	logger.Debug("request handled",
+		"unitId", req.UnitID,
+		"funcCode", req.FunctionCode,
+		"address", req.Address,
+		"quantity", req.Quantity,
+	)
+	return response
 }
 
 func (s *ASCIIServer) processRequest(req *Request) []byte {
+	response := s.dispatch(req)
+	data, err := ParseASCIIFrame(response)
+	recordCommEvent(s.handler, req, err != nil || isExceptionResponse(data))
+	return response
+}
+
+func (s *ASCIIServer) dispatch(req *Request) []byte {
 	switch req.FunctionCode {
 	case FuncReadCoils:
 		return s.processReadCoils(req)
@@ -143,6 +244,12 @@ func (s *ASCIIServer) processRequest(req *Request) []byte {
 		return s.processWriteMultipleCoils(req)
 	case FuncWriteMultipleRegisters:
 		return s.processWriteMultipleRegisters(req)
+	case FuncReadFIFOQueue:
+		return s.processReadFIFOQueue(req)
+	case FuncDiagnostics:
+		return s.processDiagnostics(req)
+	case FuncGetCommEventCounter:
+		return s.processGetCommEventCounter(req)
 	default:
 		return BuildASCIIExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
 	}
@@ -216,6 +323,9 @@ func (s *ASCIIServer) processWriteSingleRegister(req *Request) []byte {
 }
 
 func (s *ASCIIServer) processWriteMultipleCoils(req *Request) []byte {
+	if !coilDataValid(req) {
+		return BuildASCIIExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalDataValue)
+	}
 	values := unpackBools(req.Data, int(req.Quantity))
 	if err := s.handler.HandleWriteMultipleCoils(req.UnitID, req.Address, values); err != nil {
 		return s.buildExceptionFromError(req.UnitID, req.FunctionCode, err)
@@ -231,21 +341,37 @@ func (s *ASCIIServer) processWriteMultipleRegisters(req *Request) []byte {
 	return BuildASCIIWriteMultipleResponse(req.UnitID, req.FunctionCode, req.Address, req.Quantity)
 }
 
-func (s *ASCIIServer) buildExceptionFromError(unitID, funcCode byte, err error) []byte {
-	var exCode byte
-	switch err {
-	case ErrIllegalFunction:
-		exCode = ExceptionIllegalFunction
-	case ErrIllegalDataAddress:
-		exCode = ExceptionIllegalDataAddress
-	case ErrIllegalDataValue:
-		exCode = ExceptionIllegalDataValue
-	default:
-		if me, ok := err.(*ModbusException); ok {
-			exCode = me.Code
-		} else {
-			exCode = ExceptionSlaveDeviceFailure
-		}
+func (s *ASCIIServer) processReadFIFOQueue(req *Request) []byte {
+	fifo, ok := s.handler.(FIFOHandler)
+	if !ok {
+		return BuildASCIIExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
 	}
-	return BuildASCIIExceptionResponse(unitID, funcCode, exCode)
+	values, err := fifo.HandleReadFIFOQueue(req.UnitID, req.Address)
+	if err != nil {
+		return s.buildExceptionFromError(req.UnitID, req.FunctionCode, err)
+	}
+	return BuildASCIIReadFIFOResponse(req.UnitID, values)
+}
+
+func (s *ASCIIServer) processDiagnostics(req *Request) []byte {
+	counter, ok := s.handler.(CommEventHandler)
+	if !ok || req.Address != DiagClearCounters {
+		return BuildASCIIExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
+	}
+	counter.ClearCommEventCounter()
+	// 正常応答はリクエストのエコー
+	return BuildASCIIWriteSingleResponse(req.UnitID, req.FunctionCode, req.Address, binary.BigEndian.Uint16(req.Data))
+}
+
+func (s *ASCIIServer) processGetCommEventCounter(req *Request) []byte {
+	counter, ok := s.handler.(CommEventHandler)
+	if !ok {
+		return BuildASCIIExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
+	}
+	status, count := counter.CommEventCounter()
+	return BuildASCIIWriteSingleResponse(req.UnitID, FuncGetCommEventCounter, status, count)
+}
+
+func (s *ASCIIServer) buildExceptionFromError(unitID, funcCode byte, err error) []byte {
+	return BuildASCIIExceptionResponse(unitID, funcCode, MapErrorToException(err))
 }
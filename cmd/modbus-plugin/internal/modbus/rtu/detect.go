@@ -0,0 +1,23 @@
+package rtu
+
+import "bytes"
+
+// シリアルフレームの判定結果
+const (
+	ModeASCII   = "ascii"
+	ModeRTU     = "rtu"
+	ModeUnknown = "unknown"
+)
+
+// DetectMode は受信したフレームから RTU と ASCII のどちらで通信しているかを推定する。
+// ':' で始まり CR LF で終わる場合は "ascii"、末尾2バイトの CRC が一致する場合は "rtu"、
+// どちらでもない場合は "unknown" を返す。
+func DetectMode(sample []byte) string {
+	if len(sample) >= 3 && sample[0] == ASCIIFrameStart && bytes.HasSuffix(sample, []byte{ASCIIFrameCR, ASCIIFrameLF}) {
+		return ModeASCII
+	}
+	if len(sample) >= 4 && CheckCRC(sample) {
+		return ModeRTU
+	}
+	return ModeUnknown
+}
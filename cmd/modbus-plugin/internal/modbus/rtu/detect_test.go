@@ -0,0 +1,31 @@
+package rtu
+
+import "testing"
+
+func TestDetectMode(t *testing.T) {
+	readHolding := AppendCRC([]byte{0x01, FuncReadHoldingRegisters, 0x00, 0x00, 0x00, 0x02})
+	badCRC := append([]byte{}, readHolding...)
+	badCRC[len(badCRC)-1] ^= 0xFF
+
+	tests := []struct {
+		name   string
+		sample []byte
+		want   string
+	}{
+		{"ascii request", []byte(":010300000002FA\r\n"), ModeASCII},
+		{"ascii response frame", BuildASCIIReadRegistersResponse(1, FuncReadHoldingRegisters, []uint16{1}), ModeASCII},
+		{"rtu request", readHolding, ModeRTU},
+		{"rtu comm event counter", AppendCRC([]byte{0x01, FuncGetCommEventCounter}), ModeRTU},
+		{"rtu bad crc", badCRC, ModeUnknown},
+		{"ascii without crlf", []byte(":010300000002FA"), ModeUnknown},
+		{"too short", []byte{0x01}, ModeUnknown},
+		{"empty", nil, ModeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectMode(tt.sample); got != tt.want {
+				t.Errorf("DetectMode(% X) = %q, want %q", tt.sample, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package rtu
 
-import "errors"
+import (
+	"errors"
+
+	"modbus_simulator/internal/domain/datastore"
+)
 
 // Modbus例外コード
 const (
@@ -24,6 +28,7 @@ var (
 	ErrInvalidCRC         = errors.New("invalid CRC")
 	ErrFrameTooShort      = errors.New("frame too short")
 	ErrTimeout            = errors.New("timeout")
+	ErrPortLost           = errors.New("serial port lost")
 )
 
 // ModbusException はModbus例外を表す
@@ -60,3 +65,34 @@ func (e *ModbusException) Error() string {
 func NewModbusException(code byte) *ModbusException {
 	return &ModbusException{Code: code}
 }
+
+// MapErrorToException はハンドラーが返したエラーを Modbus 例外コードに変換する。
+// 未知のエラーは Slave Device Failure として扱う。
+func MapErrorToException(err error) byte {
+	var me *ModbusException
+	switch {
+	case errors.As(err, &me):
+		return me.Code
+	case errors.Is(err, ErrIllegalFunction):
+		return ExceptionIllegalFunction
+	case errors.Is(err, ErrIllegalDataAddress),
+		errors.Is(err, datastore.ErrAddressOutOfRange),
+		errors.Is(err, datastore.ErrAreaNotFound),
+		errors.Is(err, datastore.ErrReadOnly):
+		return ExceptionIllegalDataAddress
+	case errors.Is(err, ErrIllegalDataValue),
+		errors.Is(err, datastore.ErrInvalidData):
+		return ExceptionIllegalDataValue
+	default:
+		return ExceptionSlaveDeviceFailure
+	}
+}
+
+// DecodeExceptionResponse は例外応答のフレーム本体（CRC/LRC を除く）から例外を取り出す。
+// 例外応答でない場合は ok=false を返す。
+func DecodeExceptionResponse(data []byte) (funcCode byte, ex *ModbusException, ok bool) {
+	if len(data) < 3 || data[1]&0x80 == 0 {
+		return 0, nil, false
+	}
+	return data[1] &^ 0x80, NewModbusException(data[2]), true
+}
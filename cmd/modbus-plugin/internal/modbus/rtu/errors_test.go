@@ -0,0 +1,174 @@
+package rtu
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+func TestMapErrorToException(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want byte
+	}{
+		{"illegal function", ErrIllegalFunction, ExceptionIllegalFunction},
+		{"illegal data address", ErrIllegalDataAddress, ExceptionIllegalDataAddress},
+		{"illegal data value", ErrIllegalDataValue, ExceptionIllegalDataValue},
+		{"slave device failure", ErrSlaveDeviceFailure, ExceptionSlaveDeviceFailure},
+		{"modbus exception", NewModbusException(ExceptionSlaveDeviceBusy), ExceptionSlaveDeviceBusy},
+		{"wrapped modbus exception", fmt.Errorf("handler: %w", NewModbusException(ExceptionAcknowledge)), ExceptionAcknowledge},
+		{"address out of range", datastore.ErrAddressOutOfRange, ExceptionIllegalDataAddress},
+		{"wrapped address out of range", fmt.Errorf("holding: %w", datastore.ErrAddressOutOfRange), ExceptionIllegalDataAddress},
+		{"area not found", datastore.ErrAreaNotFound, ExceptionIllegalDataAddress},
+		{"read only", datastore.ErrReadOnly, ExceptionIllegalDataAddress},
+		{"invalid data", datastore.ErrInvalidData, ExceptionIllegalDataValue},
+		{"unknown", errors.New("boom"), ExceptionSlaveDeviceFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MapErrorToException(tt.err); got != tt.want {
+				t.Errorf("MapErrorToException(%v) = 0x%02X, want 0x%02X", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeExceptionResponse(t *testing.T) {
+	resp := BuildExceptionResponse(0x01, FuncReadHoldingRegisters, ExceptionIllegalDataAddress)
+	fc, ex, ok := DecodeExceptionResponse(resp[:len(resp)-2])
+	if !ok {
+		t.Fatal("expected exception response")
+	}
+	if fc != FuncReadHoldingRegisters || ex.Code != ExceptionIllegalDataAddress {
+		t.Errorf("got fc=0x%02X code=0x%02X", fc, ex.Code)
+	}
+
+	normal := BuildWriteSingleResponse(0x01, FuncWriteSingleRegister, 0, 1)
+	if _, _, ok := DecodeExceptionResponse(normal[:len(normal)-2]); ok {
+		t.Error("normal response decoded as exception")
+	}
+	if _, _, ok := DecodeExceptionResponse([]byte{0x01}); ok {
+		t.Error("short frame decoded as exception")
+	}
+}
+
+// errorHandler は全ての要求に固定のエラーを返すテスト用の RequestHandler
+type errorHandler struct {
+	stubHandler
+	err error
+}
+
+func (h errorHandler) HandleReadHoldingRegisters(byte, uint16, uint16) ([]uint16, error) {
+	return nil, h.err
+}
+
+func TestBuildExceptionFromError_RTUAndASCII(t *testing.T) {
+	req := []byte{0x01, FuncReadHoldingRegisters, 0x00, 0x00, 0x00, 0x01}
+	tests := []struct {
+		err  error
+		want byte
+	}{
+		{datastore.ErrAddressOutOfRange, ExceptionIllegalDataAddress},
+		{datastore.ErrReadOnly, ExceptionIllegalDataAddress},
+		{errors.New("boom"), ExceptionSlaveDeviceFailure},
+	}
+
+	for _, tt := range tests {
+		h := errorHandler{err: tt.err}
+
+		parsed, err := ParseRequest(AppendCRC(append([]byte(nil), req...)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := NewProcessor(h).Process(parsed)
+		if _, ex, ok := DecodeExceptionResponse(resp[:len(resp)-2]); !ok || ex.Code != tt.want {
+			t.Errorf("RTU %v: response % X, want exception 0x%02X", tt.err, resp, tt.want)
+		}
+
+		asciiResp := NewASCIIServer(testSerialConfig, h).handleFrame(BuildASCIIFrame(req))
+		data, err := ParseASCIIFrame(asciiResp)
+		if err != nil {
+			t.Fatalf("ParseASCIIFrame: %v", err)
+		}
+		if _, ex, ok := DecodeExceptionResponse(data); !ok || ex.Code != tt.want {
+			t.Errorf("ASCII %v: response %q, want exception 0x%02X", tt.err, asciiResp, tt.want)
+		}
+	}
+}
+
+// coilWriteRecorder は FC15 の書き込みが行われたかを記録する
+type coilWriteRecorder struct {
+	stubHandler
+	written *bool
+}
+
+func (h coilWriteRecorder) HandleWriteMultipleCoils(byte, uint16, []bool) error {
+	*h.written = true
+	return nil
+}
+
+func TestWriteMultipleCoils_ByteCountMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"byte count too small", writeMultipleFrame(FuncWriteMultipleCoils, 10, 1, []byte{0xFF, 0x03})},
+		{"byte count too large", writeMultipleFrame(FuncWriteMultipleCoils, 8, 2, []byte{0xFF, 0x03})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written := false
+			h := coilWriteRecorder{written: &written}
+
+			resp := NewRTUServer(testSerialConfig, h).handleFrame(AppendCRC(append([]byte(nil), tt.data...)))
+			if len(resp) < 2 {
+				t.Fatalf("RTU: no response")
+			}
+			if _, ex, ok := DecodeExceptionResponse(resp[:len(resp)-2]); !ok || ex.Code != ExceptionIllegalDataValue {
+				t.Errorf("RTU: response % X, want illegal data value exception", resp)
+			}
+
+			asciiResp := NewASCIIServer(testSerialConfig, h).handleFrame(BuildASCIIFrame(tt.data))
+			data, err := ParseASCIIFrame(asciiResp)
+			if err != nil {
+				t.Fatalf("ParseASCIIFrame: %v", err)
+			}
+			if _, ex, ok := DecodeExceptionResponse(data); !ok || ex.Code != ExceptionIllegalDataValue {
+				t.Errorf("ASCII: response %q, want illegal data value exception", asciiResp)
+			}
+
+			if written {
+				t.Error("coils were written from an inconsistent frame")
+			}
+		})
+	}
+}
+
+func TestWriteMultipleCoils_ShortData(t *testing.T) {
+	// 解析を経ずに組み立てられた、Quantity に対してデータが足りないリクエスト
+	req := &Request{UnitID: 1, FunctionCode: FuncWriteMultipleCoils, Quantity: 16, Data: []byte{0xFF}}
+	written := false
+	h := coilWriteRecorder{written: &written}
+
+	resp := NewProcessor(h).Process(req)
+	if _, ex, ok := DecodeExceptionResponse(resp[:len(resp)-2]); !ok || ex.Code != ExceptionIllegalDataValue {
+		t.Errorf("RTU: response % X, want illegal data value exception", resp)
+	}
+
+	data, err := ParseASCIIFrame(NewASCIIServer(testSerialConfig, h).processRequest(req))
+	if err != nil {
+		t.Fatalf("ParseASCIIFrame: %v", err)
+	}
+	if _, ex, ok := DecodeExceptionResponse(data); !ok || ex.Code != ExceptionIllegalDataValue {
+		t.Errorf("ASCII: response % X, want illegal data value exception", data)
+	}
+
+	if written {
+		t.Error("coils were written from short data")
+	}
+}
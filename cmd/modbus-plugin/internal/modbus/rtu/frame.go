@@ -2,6 +2,7 @@ package rtu
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 )
 
@@ -13,10 +14,19 @@ const (
 	FuncReadInputRegisters     byte = 0x04
 	FuncWriteSingleCoil        byte = 0x05
 	FuncWriteSingleRegister    byte = 0x06
+	FuncDiagnostics            byte = 0x08
+	FuncGetCommEventCounter    byte = 0x0B
 	FuncWriteMultipleCoils     byte = 0x0F
 	FuncWriteMultipleRegisters byte = 0x10
+	FuncReadFIFOQueue          byte = 0x18
 )
 
+// MaxFIFOCount は FC 0x18 で1回に返せるキューの最大件数（Modbus仕様）
+const MaxFIFOCount = 31
+
+// DiagClearCounters は FC 0x08 のサブファンクション「カウンタと診断レジスタのクリア」
+const DiagClearCounters uint16 = 0x000A
+
 // Request はModbus RTUリクエストを表す
 type Request struct {
 	UnitID       byte
@@ -33,10 +43,11 @@ type Response struct {
 	Data         []byte
 }
 
-// ParseRequest はバイト列からリクエストを解析する
+// ParseRequest はバイト列からリクエストを解析する。
+// ErrIllegalDataValue の場合は UnitID と FunctionCode のみを設定した Request も返す。
 func ParseRequest(frame []byte) (*Request, error) {
-	// 最小フレーム長: UnitID(1) + FunctionCode(1) + Data(2) + CRC(2) = 6
-	if len(frame) < 6 {
+	// 最小フレーム長: UnitID(1) + FunctionCode(1) + CRC(2) = 4（データなしは FC 0x0B のみ）
+	if len(frame) < 4 {
 		return nil, ErrFrameTooShort
 	}
 
@@ -71,31 +82,33 @@ func ParseRequest(frame []byte) (*Request, error) {
 		req.Quantity = 1
 		req.Data = data[4:6]
 
-	case FuncWriteMultipleCoils:
-		// 複数コイル書き込み: Address(2) + Quantity(2) + ByteCount(1) + Data(N)
-		if len(data) < 7 {
+	case FuncReadFIFOQueue:
+		// FIFO読み取り: FIFO Pointer Address(2)
+		if len(data) < 4 {
 			return nil, ErrFrameTooShort
 		}
 		req.Address = binary.BigEndian.Uint16(data[2:4])
-		req.Quantity = binary.BigEndian.Uint16(data[4:6])
-		byteCount := int(data[6])
-		if len(data) < 7+byteCount {
-			return nil, ErrFrameTooShort
-		}
-		req.Data = data[7 : 7+byteCount]
 
-	case FuncWriteMultipleRegisters:
-		// 複数レジスタ書き込み: Address(2) + Quantity(2) + ByteCount(1) + Data(N)
-		if len(data) < 7 {
+	case FuncDiagnostics:
+		// 診断: SubFunction(2) + Data(2)。SubFunction は Address に格納する
+		if len(data) < 6 {
 			return nil, ErrFrameTooShort
 		}
 		req.Address = binary.BigEndian.Uint16(data[2:4])
-		req.Quantity = binary.BigEndian.Uint16(data[4:6])
-		byteCount := int(data[6])
-		if len(data) < 7+byteCount {
-			return nil, ErrFrameTooShort
+		req.Data = data[4:6]
+
+	case FuncGetCommEventCounter:
+		// イベントカウンタ取得: データなし
+
+	case FuncWriteMultipleCoils, FuncWriteMultipleRegisters:
+		// 複数書き込み: Address(2) + Quantity(2) + ByteCount(1) + Data(N)
+		if err := parseWriteMultiple(req, data); err != nil {
+			if errors.Is(err, ErrIllegalDataValue) {
+				// 例外応答を返せるよう UnitID と FunctionCode を設定した req も返す
+				return req, err
+			}
+			return nil, err
 		}
-		req.Data = data[7 : 7+byteCount]
 
 	default:
 		return nil, fmt.Errorf("unsupported function code: 0x%02X", req.FunctionCode)
@@ -139,6 +152,30 @@ func BuildReadRegistersResponse(unitID, funcCode byte, values []uint16) []byte {
 	return AppendCRC(data)
 }
 
+// BuildCommEventCounterResponse はイベントカウンタ取得レスポンスを構築する (FC 0x0B)
+func BuildCommEventCounterResponse(unitID byte, status, count uint16) []byte {
+	return BuildWriteSingleResponse(unitID, FuncGetCommEventCounter, status, count)
+}
+
+// BuildReadFIFOResponse は FIFO 読み取りレスポンスを構築する (FC 0x18)
+func BuildReadFIFOResponse(unitID byte, values []uint16) []byte {
+	return AppendCRC(fifoResponseData(unitID, values))
+}
+
+// fifoResponseData は FC 0x18 のレスポンス（CRC/LRC なし）を組み立てる。
+// ByteCount(2) + FIFOCount(2) + 値(2×N) の形式で、ByteCount は FIFOCount 以降のバイト数。
+func fifoResponseData(unitID byte, values []uint16) []byte {
+	data := make([]byte, 6+len(values)*2)
+	data[0] = unitID
+	data[1] = FuncReadFIFOQueue
+	binary.BigEndian.PutUint16(data[2:4], uint16(2+len(values)*2))
+	binary.BigEndian.PutUint16(data[4:6], uint16(len(values)))
+	for i, v := range values {
+		binary.BigEndian.PutUint16(data[6+i*2:], v)
+	}
+	return data
+}
+
 // BuildWriteSingleResponse は単一書き込みレスポンスを構築する
 func BuildWriteSingleResponse(unitID, funcCode byte, address uint16, value uint16) []byte {
 	data := make([]byte, 6)
@@ -170,3 +207,39 @@ func BuildExceptionResponse(unitID, funcCode, exceptionCode byte) []byte {
 
 	return AppendCRC(data)
 }
+
+// 書き込み数量の上限（Modbus仕様: FC15 は 1968 点、FC16 は 123 レジスタ）
+const (
+	maxWriteCoilsQuantity     = 1968
+	maxWriteRegistersQuantity = 123
+)
+
+// parseWriteMultiple は FC15/FC16 の Address・Quantity・ByteCount・Data を解析する。
+// data は CRC/LRC を除いた UnitID 以降のバイト列。
+// ByteCount が実データ長を超える場合は ErrFrameTooShort、
+// Quantity が仕様上限を超える場合や ByteCount と一致しない場合は ErrIllegalDataValue を返す。
+func parseWriteMultiple(req *Request, data []byte) error {
+	if len(data) < 7 {
+		return ErrFrameTooShort
+	}
+	req.Address = binary.BigEndian.Uint16(data[2:4])
+	req.Quantity = binary.BigEndian.Uint16(data[4:6])
+	byteCount := int(data[6])
+	if len(data) < 7+byteCount {
+		return ErrFrameTooShort
+	}
+
+	var maxQuantity, expected int
+	q := int(req.Quantity)
+	if req.FunctionCode == FuncWriteMultipleCoils {
+		maxQuantity, expected = maxWriteCoilsQuantity, (q+7)/8
+	} else {
+		maxQuantity, expected = maxWriteRegistersQuantity, q*2
+	}
+	if q == 0 || q > maxQuantity || byteCount != expected {
+		return ErrIllegalDataValue
+	}
+
+	req.Data = data[7 : 7+byteCount]
+	return nil
+}
@@ -0,0 +1,86 @@
+package rtu
+
+import (
+	"errors"
+	"testing"
+)
+
+// writeMultipleFrame は FC15/FC16 のフレーム本体（CRC/LRC なし）を組み立てる
+func writeMultipleFrame(fc byte, quantity uint16, byteCount byte, payload []byte) []byte {
+	data := []byte{0x01, fc, 0x00, 0x00, byte(quantity >> 8), byte(quantity), byteCount}
+	return append(data, payload...)
+}
+
+func TestParseRequest_WriteMultipleValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"coils ok", writeMultipleFrame(FuncWriteMultipleCoils, 10, 2, []byte{0xFF, 0x03}), nil},
+		{"registers ok", writeMultipleFrame(FuncWriteMultipleRegisters, 2, 4, []byte{0, 1, 0, 2}), nil},
+		{"coils byte count exceeds data", writeMultipleFrame(FuncWriteMultipleCoils, 10, 200, []byte{0xFF, 0x03}), ErrFrameTooShort},
+		{"registers byte count exceeds data", writeMultipleFrame(FuncWriteMultipleRegisters, 2, 255, []byte{0, 1, 0, 2}), ErrFrameTooShort},
+		{"coils byte count mismatch", writeMultipleFrame(FuncWriteMultipleCoils, 10, 1, []byte{0xFF, 0x03}), ErrIllegalDataValue},
+		{"registers byte count mismatch", writeMultipleFrame(FuncWriteMultipleRegisters, 3, 4, []byte{0, 1, 0, 2}), ErrIllegalDataValue},
+		{"coils zero quantity", writeMultipleFrame(FuncWriteMultipleCoils, 0, 0, nil), ErrIllegalDataValue},
+		{"registers zero quantity", writeMultipleFrame(FuncWriteMultipleRegisters, 0, 0, nil), ErrIllegalDataValue},
+		{"coils quantity over max", writeMultipleFrame(FuncWriteMultipleCoils, 1969, 247, make([]byte, 247)), ErrIllegalDataValue},
+		{"registers quantity over max", writeMultipleFrame(FuncWriteMultipleRegisters, 124, 248, make([]byte, 248)), ErrIllegalDataValue},
+		{"truncated header", []byte{0x01, FuncWriteMultipleRegisters, 0x00, 0x00, 0x00}, ErrFrameTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRequest(AppendCRC(append([]byte(nil), tt.data...)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RTU: err = %v, want %v", err, tt.wantErr)
+			}
+			_, err = ParseASCIIRequest(BuildASCIIFrame(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ASCII: err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRequest_WriteMultipleData(t *testing.T) {
+	req, err := ParseRequest(AppendCRC(writeMultipleFrame(FuncWriteMultipleRegisters, 2, 4, []byte{0, 1, 0, 2})))
+	if err != nil {
+		t.Fatalf("ParseRequest: %v", err)
+	}
+	if req.Quantity != 2 || len(req.Data) != 4 {
+		t.Errorf("Quantity = %d, len(Data) = %d, want 2, 4", req.Quantity, len(req.Data))
+	}
+}
+
+// FuzzParseRequest は任意のフレームでパニックせず、
+// 成功時は Data 長が Quantity と整合していることを確認する
+func FuzzParseRequest(f *testing.F) {
+	f.Add(writeMultipleFrame(FuncWriteMultipleCoils, 10, 2, []byte{0xFF, 0x03}))
+	f.Add(writeMultipleFrame(FuncWriteMultipleRegisters, 2, 4, []byte{0, 1, 0, 2}))
+	f.Add(writeMultipleFrame(FuncWriteMultipleRegisters, 2, 255, []byte{0, 1}))
+	f.Add([]byte{0x01, FuncReadHoldingRegisters, 0x00, 0x00, 0x00, 0x0A})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, parse := range []func() (*Request, error){
+			func() (*Request, error) { return ParseRequest(AppendCRC(append([]byte(nil), data...))) },
+			func() (*Request, error) { return ParseASCIIRequest(BuildASCIIFrame(data)) },
+		} {
+			req, err := parse()
+			if err != nil {
+				continue
+			}
+			switch req.FunctionCode {
+			case FuncWriteMultipleCoils:
+				if len(req.Data) != (int(req.Quantity)+7)/8 {
+					t.Fatalf("coil data length %d does not match quantity %d", len(req.Data), req.Quantity)
+				}
+			case FuncWriteMultipleRegisters:
+				if len(req.Data) != int(req.Quantity)*2 {
+					t.Fatalf("register data length %d does not match quantity %d", len(req.Data), req.Quantity)
+				}
+			}
+		}
+	})
+}
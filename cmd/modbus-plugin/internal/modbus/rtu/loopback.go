@@ -0,0 +1,51 @@
+package rtu
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Loopback はシリアルポートを介さずに RTU フレームを処理し、応答フレームを返す。
+// frame は CRC 付きの要求フレーム。応答しない場合（無効な UnitID など）は nil を返す。
+// テストや診断で要求から応答までの経路をそのまま確認するために使用する。
+func Loopback(handler RequestHandler, frame []byte) ([]byte, error) {
+	req, err := ParseRequest(frame)
+	if err != nil {
+		return nil, err
+	}
+	return NewProcessor(handler).Process(req), nil
+}
+
+// BuildReadRequest は読み取り要求（FC 01〜04）の CRC 付きフレームを作成する
+func BuildReadRequest(unitID, funcCode byte, address, quantity uint16) []byte {
+	frame := make([]byte, 6, 8)
+	frame[0] = unitID
+	frame[1] = funcCode
+	binary.BigEndian.PutUint16(frame[2:4], address)
+	binary.BigEndian.PutUint16(frame[4:6], quantity)
+	return AppendCRC(frame)
+}
+
+// ParseReadRegistersResponse はレジスタ読み取り応答（FC 03/04）の CRC 付きフレームから値を取り出す。
+// 例外応答の場合は *ModbusException を返す。
+func ParseReadRegistersResponse(frame []byte) ([]uint16, error) {
+	if len(frame) < 5 {
+		return nil, ErrFrameTooShort
+	}
+	if !CheckCRC(frame) {
+		return nil, ErrInvalidCRC
+	}
+	data := frame[:len(frame)-2]
+	if _, ex, ok := DecodeExceptionResponse(data); ok {
+		return nil, ex
+	}
+	byteCount := int(data[2])
+	if byteCount%2 != 0 || len(data) != 3+byteCount {
+		return nil, fmt.Errorf("invalid byte count %d for %d-byte response", byteCount, len(data))
+	}
+	values := make([]uint16, byteCount/2)
+	for i := range values {
+		values[i] = binary.BigEndian.Uint16(data[3+i*2:])
+	}
+	return values, nil
+}
@@ -0,0 +1,34 @@
+package rtu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoopback_ReadHoldingRegisters(t *testing.T) {
+	resp, err := Loopback(stubHandler{}, BuildReadRequest(1, FuncReadHoldingRegisters, 0, 3))
+	if err != nil {
+		t.Fatalf("Loopback: %v", err)
+	}
+	values, err := ParseReadRegistersResponse(resp)
+	if err != nil {
+		t.Fatalf("ParseReadRegistersResponse: %v", err)
+	}
+	if len(values) != 3 {
+		t.Errorf("got %d values, want 3", len(values))
+	}
+
+	// 例外応答は *ModbusException として返る
+	resp, err = Loopback(errorHandler{err: ErrIllegalDataAddress}, BuildReadRequest(1, FuncReadHoldingRegisters, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ex *ModbusException
+	if _, err := ParseReadRegistersResponse(resp); !errors.As(err, &ex) || ex.Code != ExceptionIllegalDataAddress {
+		t.Errorf("expected illegal data address exception, got %v", err)
+	}
+
+	if _, err := Loopback(stubHandler{}, []byte{0x01, 0x03, 0x00}); err == nil {
+		t.Error("expected error for short frame")
+	}
+}
@@ -0,0 +1,165 @@
+package rtu
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Modbus TCP の MBAP ヘッダー
+//
+//	Transaction ID(2) + Protocol ID(2) + Length(2) + Unit ID(1) + PDU(N)
+//
+// Length は Unit ID と PDU を合わせたバイト数。
+// PDU は RTU フレームから Unit ID と CRC を除いた部分と同じ形式のため、
+// RTU フレームへ変換して Processor で処理する。
+const (
+	// MBAPHeaderLen は MBAP ヘッダー（Unit ID を含む）のバイト数
+	MBAPHeaderLen = 7
+	// maxMBAPLength は Length フィールドの最大値（Unit ID 1 + PDU 最大 253）
+	maxMBAPLength = 254
+	// mbapProtocolID は Modbus プロトコルを表す Protocol ID
+	mbapProtocolID = 0
+)
+
+// MBAP ヘッダーのエラー定義
+var (
+	ErrInvalidProtocolID = errors.New("invalid MBAP protocol ID")
+	ErrInvalidMBAPLength = errors.New("invalid MBAP length")
+	ErrMBAPMismatch      = errors.New("MBAP response does not match request")
+)
+
+// MBAPHeader は Modbus TCP フレームの MBAP ヘッダー
+type MBAPHeader struct {
+	TransactionID uint16
+	ProtocolID    uint16
+	Length        uint16
+	UnitID        byte
+}
+
+// ParseMBAPHeader はフレーム先頭の MBAP ヘッダーを解析する
+func ParseMBAPHeader(frame []byte) (MBAPHeader, error) {
+	if len(frame) < MBAPHeaderLen {
+		return MBAPHeader{}, ErrFrameTooShort
+	}
+	header := MBAPHeader{
+		TransactionID: binary.BigEndian.Uint16(frame[0:2]),
+		ProtocolID:    binary.BigEndian.Uint16(frame[2:4]),
+		Length:        binary.BigEndian.Uint16(frame[4:6]),
+		UnitID:        frame[6],
+	}
+	if header.ProtocolID != mbapProtocolID {
+		return header, fmt.Errorf("%w: %d", ErrInvalidProtocolID, header.ProtocolID)
+	}
+	// Unit ID + Function Code が最低限必要
+	if header.Length < 2 || header.Length > maxMBAPLength {
+		return header, fmt.Errorf("%w: %d", ErrInvalidMBAPLength, header.Length)
+	}
+	return header, nil
+}
+
+// ParseMBAPFrame は Modbus TCP フレーム1つを解析する。
+// PDU の解析に ErrIllegalDataValue で失敗した場合は、例外応答を返せるよう req も返す（ParseRequest と同じ）。
+func ParseMBAPFrame(frame []byte) (MBAPHeader, *Request, error) {
+	header, err := ParseMBAPHeader(frame)
+	if err != nil {
+		return header, nil, err
+	}
+	if len(frame) != MBAPHeaderLen-1+int(header.Length) {
+		return header, nil, fmt.Errorf("%w: header says %d bytes, frame has %d", ErrInvalidMBAPLength, header.Length, len(frame)-(MBAPHeaderLen-1))
+	}
+
+	// Unit ID + PDU に CRC を付けて RTU フレームとして解析する
+	req, err := ParseRequest(AppendCRC(append([]byte(nil), frame[MBAPHeaderLen-1:]...)))
+	return header, req, err
+}
+
+// ReadMBAPFrame は r から Modbus TCP フレームを1つ読み取る。
+// ヘッダーが不正な場合は以降の境界が分からないため、エラーを返す（呼び出し側で接続を閉じること）。
+func ReadMBAPFrame(r io.Reader) ([]byte, error) {
+	frame := make([]byte, MBAPHeaderLen-1+maxMBAPLength)
+	if _, err := io.ReadFull(r, frame[:MBAPHeaderLen]); err != nil {
+		return nil, err
+	}
+	header, err := ParseMBAPHeader(frame)
+	if err != nil {
+		return nil, err
+	}
+	end := MBAPHeaderLen - 1 + int(header.Length)
+	if _, err := io.ReadFull(r, frame[MBAPHeaderLen:end]); err != nil {
+		return nil, err
+	}
+	return frame[:end], nil
+}
+
+// MBAPAccumulator は TCP から受信したバイト列を Modbus TCP フレーム単位に区切る。
+// 1回の受信に複数のフレームが含まれる場合（パイプライン）や、フレームが複数回に分かれて届く場合に使う。
+type MBAPAccumulator struct {
+	buf []byte
+}
+
+// Feed は受信したデータを追加し、揃ったフレームを受信順に返す。
+// 不完全なフレームは次の Feed まで保持する。ヘッダーが不正な場合は以降の境界が分からないため、
+// それまでに揃ったフレームとエラーを返す（呼び出し側で接続を閉じること）。
+func (a *MBAPAccumulator) Feed(data []byte) ([][]byte, error) {
+	a.buf = append(a.buf, data...)
+
+	var frames [][]byte
+	for len(a.buf) >= MBAPHeaderLen {
+		header, err := ParseMBAPHeader(a.buf)
+		if err != nil {
+			a.buf = nil
+			return frames, err
+		}
+		end := MBAPHeaderLen - 1 + int(header.Length)
+		if len(a.buf) < end {
+			break
+		}
+		frames = append(frames, append([]byte(nil), a.buf[:end]...))
+		a.buf = a.buf[end:]
+	}
+	if len(a.buf) == 0 {
+		a.buf = nil // 消費済みの領域を解放する
+	}
+	return frames, nil
+}
+
+// Buffered は保持している未完成フレームのバイト数を返す
+func (a *MBAPAccumulator) Buffered() int {
+	return len(a.buf)
+}
+
+// BuildMBAPResponse は Processor が返した RTU 形式のレスポンス（CRC 付き）を
+// リクエストの Transaction ID・Protocol ID を持つ Modbus TCP フレームに変換する。
+// Length には Unit ID と PDU を合わせたバイト数を設定する。
+func BuildMBAPResponse(header MBAPHeader, rtuResponse []byte) []byte {
+	if len(rtuResponse) < 2 {
+		return nil
+	}
+	adu := rtuResponse[:len(rtuResponse)-2] // CRC を除いた Unit ID + PDU
+	frame := make([]byte, MBAPHeaderLen-1+len(adu))
+	binary.BigEndian.PutUint16(frame[0:2], header.TransactionID)
+	binary.BigEndian.PutUint16(frame[2:4], header.ProtocolID)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(len(adu)))
+	copy(frame[6:], adu)
+	return frame
+}
+
+// VerifyMBAPResponse はレスポンスがリクエストの Transaction ID・Protocol ID・Unit ID を返し、
+// Length がフレーム長と一致しているかを検証する
+func VerifyMBAPResponse(request MBAPHeader, response []byte) error {
+	header, err := ParseMBAPHeader(response)
+	if err != nil {
+		return err
+	}
+	if len(response) != MBAPHeaderLen-1+int(header.Length) {
+		return fmt.Errorf("%w: header says %d bytes, frame has %d", ErrInvalidMBAPLength, header.Length, len(response)-(MBAPHeaderLen-1))
+	}
+	if header.TransactionID != request.TransactionID || header.ProtocolID != request.ProtocolID || header.UnitID != request.UnitID {
+		return fmt.Errorf("%w: request %d/%d/%d, response %d/%d/%d", ErrMBAPMismatch,
+			request.TransactionID, request.ProtocolID, request.UnitID,
+			header.TransactionID, header.ProtocolID, header.UnitID)
+	}
+	return nil
+}
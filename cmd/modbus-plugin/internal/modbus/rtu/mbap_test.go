@@ -0,0 +1,263 @@
+package rtu
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// registerHandler は保持レジスタとして address+i を返すテスト用の RequestHandler
+type registerHandler struct {
+	stubHandler
+}
+
+func (registerHandler) HandleReadHoldingRegisters(_ byte, address, quantity uint16) ([]uint16, error) {
+	values := make([]uint16, quantity)
+	for i := range values {
+		values[i] = address + uint16(i)
+	}
+	return values, nil
+}
+
+func TestParseMBAPFrame_ReadHoldingRegisters(t *testing.T) {
+	// TID=0x1234, PID=0, Length=6, Unit=0x11, FC03 Address=0x0010 Quantity=2
+	frame := []byte{0x12, 0x34, 0x00, 0x00, 0x00, 0x06, 0x11, 0x03, 0x00, 0x10, 0x00, 0x02}
+
+	header, req, err := ParseMBAPFrame(frame)
+	if err != nil {
+		t.Fatalf("ParseMBAPFrame: %v", err)
+	}
+	if header != (MBAPHeader{TransactionID: 0x1234, ProtocolID: 0, Length: 6, UnitID: 0x11}) {
+		t.Errorf("header = %+v", header)
+	}
+	if req.UnitID != 0x11 || req.FunctionCode != FuncReadHoldingRegisters || req.Address != 0x10 || req.Quantity != 2 {
+		t.Errorf("request = %+v", req)
+	}
+}
+
+func TestParseMBAPFrame_InvalidHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+		want  error
+	}{
+		{"too short", []byte{0x00, 0x01, 0x00, 0x00, 0x00}, ErrFrameTooShort},
+		{"protocol ID", []byte{0x00, 0x01, 0x00, 0x01, 0x00, 0x06, 0x01, 0x03, 0x00, 0x00, 0x00, 0x01}, ErrInvalidProtocolID},
+		{"length mismatch", []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x08, 0x01, 0x03, 0x00, 0x00, 0x00, 0x01}, ErrInvalidMBAPLength},
+		{"length too large", []byte{0x00, 0x01, 0x00, 0x00, 0x01, 0x00, 0x01, 0x03}, ErrInvalidMBAPLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseMBAPFrame(tt.frame); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTCPServer_HandleFrame_Response(t *testing.T) {
+	var buf bytes.Buffer
+	srv := NewTCPServer("127.0.0.1:0", registerHandler{})
+	srv.SetLogger(newBufferLogger(&buf, slog.LevelDebug))
+
+	frame := []byte{0x12, 0x34, 0x00, 0x00, 0x00, 0x06, 0x11, 0x03, 0x00, 0x10, 0x00, 0x02}
+	got := srv.handleFrame(srv.processor, frame, srv.log())
+	want := []byte{0x12, 0x34, 0x00, 0x00, 0x00, 0x07, 0x11, 0x03, 0x04, 0x00, 0x10, 0x00, 0x11}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response = % X, want % X", got, want)
+	}
+	if log := buf.String(); !strings.Contains(log, "transactionId=4660") || !strings.Contains(log, "unitId=17") {
+		t.Errorf("log should record transaction ID and unit ID: %s", log)
+	}
+
+	// 例外応答も同じ Transaction ID で返す
+	frame = []byte{0x00, 0x07, 0x00, 0x00, 0x00, 0x02, 0x01, 0x0B}
+	got = srv.handleFrame(NewProcessor(registerHandler{}), frame, srv.log())
+	want = []byte{0x00, 0x07, 0x00, 0x00, 0x00, 0x03, 0x01, 0x8B, ExceptionIllegalFunction}
+	if !bytes.Equal(got, want) {
+		t.Errorf("exception response = % X, want % X", got, want)
+	}
+}
+
+func TestTCPServer_ServesOverTCP(t *testing.T) {
+	srv := NewTCPServer("127.0.0.1:0", registerHandler{})
+	srv.SetLogger(nil)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := conn.Write([]byte{0x00, 0x2A, 0x00, 0x00, 0x00, 0x06, 0x01, 0x03, 0x00, 0x05, 0x00, 0x01}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ReadMBAPFrame(conn)
+	if err != nil {
+		t.Fatalf("ReadMBAPFrame: %v", err)
+	}
+	want := []byte{0x00, 0x2A, 0x00, 0x00, 0x00, 0x05, 0x01, 0x03, 0x02, 0x00, 0x05}
+	if !bytes.Equal(resp, want) {
+		t.Errorf("response = % X, want % X", resp, want)
+	}
+
+	if err := srv.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if srv.IsRunning() {
+		t.Error("server should be stopped")
+	}
+}
+
+// clientHandler は ForClient に渡された接続元のアドレスを記録するハンドラー（ClientHandlerProvider のテスト用）
+type clientHandler struct {
+	registerHandler
+	addrs chan string
+}
+
+func (h clientHandler) ForClient(clientAddr string) RequestHandler {
+	h.addrs <- clientAddr
+	return registerHandler{}
+}
+
+func TestTCPServer_ForClient(t *testing.T) {
+	h := clientHandler{addrs: make(chan string, 1)}
+	srv := NewTCPServer("127.0.0.1:0", h)
+	srv.SetLogger(nil)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x06, 0x01, 0x03, 0x00, 0x05, 0x00, 0x01}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadMBAPFrame(conn); err != nil {
+		t.Fatalf("ReadMBAPFrame: %v", err)
+	}
+
+	// 接続ごとに接続元のアドレス（host:port）でハンドラーを作る
+	select {
+	case addr := <-h.addrs:
+		if addr != conn.LocalAddr().String() {
+			t.Errorf("ForClient address = %q, want %q", addr, conn.LocalAddr().String())
+		}
+	default:
+		t.Error("ForClient should be called for the connection")
+	}
+}
+
+func TestMBAPAccumulator_SplitAndPipelined(t *testing.T) {
+	first := []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x06, 0x01, 0x03, 0x00, 0x00, 0x00, 0x01}
+	second := []byte{0x00, 0x02, 0x00, 0x00, 0x00, 0x06, 0x01, 0x04, 0x00, 0x01, 0x00, 0x02}
+	stream := append(append([]byte(nil), first...), second...)
+
+	var acc MBAPAccumulator
+	// 1つ目のフレームの途中まで
+	frames, err := acc.Feed(stream[:5])
+	if err != nil || len(frames) != 0 {
+		t.Fatalf("partial header: frames=%d err=%v", len(frames), err)
+	}
+	// 1つ目の残りと2つ目の途中まで
+	frames, err = acc.Feed(stream[5:16])
+	if err != nil || len(frames) != 1 || !bytes.Equal(frames[0], first) {
+		t.Fatalf("first frame: frames=% X err=%v", frames, err)
+	}
+	if acc.Buffered() != 4 {
+		t.Errorf("buffered = %d, want 4", acc.Buffered())
+	}
+	frames, err = acc.Feed(stream[16:])
+	if err != nil || len(frames) != 1 || !bytes.Equal(frames[0], second) {
+		t.Fatalf("second frame: frames=% X err=%v", frames, err)
+	}
+
+	// 2フレームを1回で受信
+	frames, err = acc.Feed(stream)
+	if err != nil || len(frames) != 2 {
+		t.Fatalf("pipelined: frames=%d err=%v", len(frames), err)
+	}
+	if acc.Buffered() != 0 {
+		t.Errorf("buffered = %d, want 0", acc.Buffered())
+	}
+
+	// 不正なヘッダーはそれまでのフレームとエラーを返す
+	bad := append(append([]byte(nil), first...), 0x00, 0x03, 0x00, 0x05, 0x00, 0x06, 0x01)
+	frames, err = acc.Feed(bad)
+	if !errors.Is(err, ErrInvalidProtocolID) || len(frames) != 1 {
+		t.Errorf("invalid header: frames=%d err=%v", len(frames), err)
+	}
+}
+
+func TestVerifyMBAPResponse(t *testing.T) {
+	req := MBAPHeader{TransactionID: 7, ProtocolID: 0, Length: 6, UnitID: 1}
+	resp := []byte{0x00, 0x07, 0x00, 0x00, 0x00, 0x05, 0x01, 0x03, 0x02, 0x00, 0x05}
+	if err := VerifyMBAPResponse(req, resp); err != nil {
+		t.Errorf("matching response: %v", err)
+	}
+	if err := VerifyMBAPResponse(MBAPHeader{TransactionID: 8, UnitID: 1}, resp); !errors.Is(err, ErrMBAPMismatch) {
+		t.Errorf("transaction ID mismatch: err = %v", err)
+	}
+	if err := VerifyMBAPResponse(req, resp[:len(resp)-1]); !errors.Is(err, ErrInvalidMBAPLength) {
+		t.Errorf("length mismatch: err = %v", err)
+	}
+}
+
+func TestTCPServer_PipelinedRequests(t *testing.T) {
+	srv := NewTCPServer("127.0.0.1:0", registerHandler{})
+	srv.SetLogger(nil)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// 2つのリクエストを1回の送信で送る
+	requests := []byte{
+		0xAB, 0x01, 0x00, 0x00, 0x00, 0x06, 0x01, 0x03, 0x00, 0x0A, 0x00, 0x01,
+		0xAB, 0x02, 0x00, 0x00, 0x00, 0x06, 0x02, 0x03, 0x00, 0x14, 0x00, 0x02,
+	}
+	if _, err := conn.Write(requests); err != nil {
+		t.Fatal(err)
+	}
+
+	wants := []struct {
+		header MBAPHeader
+		values []byte
+	}{
+		{MBAPHeader{TransactionID: 0xAB01, UnitID: 1}, []byte{0x00, 0x0A}},
+		{MBAPHeader{TransactionID: 0xAB02, UnitID: 2}, []byte{0x00, 0x14, 0x00, 0x15}},
+	}
+	for _, want := range wants {
+		resp, err := ReadMBAPFrame(conn)
+		if err != nil {
+			t.Fatalf("ReadMBAPFrame: %v", err)
+		}
+		if err := VerifyMBAPResponse(want.header, resp); err != nil {
+			t.Errorf("response % X: %v", resp, err)
+		}
+		if pdu := resp[MBAPHeaderLen:]; pdu[0] != FuncReadHoldingRegisters || !bytes.Equal(pdu[2:], want.values) {
+			t.Errorf("transaction %04X PDU = % X", want.header.TransactionID, pdu)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package rtu
 
 import (
 	"encoding/binary"
+	"errors"
 )
 
 // RequestHandler はリクエストを処理するためのインターフェース
@@ -26,6 +27,36 @@ type RequestHandler interface {
 	IsUnitIDEnabled(unitID byte) bool
 }
 
+// FIFOHandler は FC 0x18 (Read FIFO Queue) に対応する RequestHandler が実装するインターフェース。
+// 実装していないハンドラーでは FC 0x18 に Illegal Function を返す。
+type FIFOHandler interface {
+	// HandleReadFIFOQueue は pointer のFIFOキューの内容を古い順に返す (FC 0x18)
+	HandleReadFIFOQueue(unitID byte, pointer uint16) ([]uint16, error)
+}
+
+// CommEventHandler は FC 0x0B (Get Comm Event Counter) と FC 0x08 のカウンタクリアに対応する
+// RequestHandler が実装するインターフェース。実装していないハンドラーではどちらも Illegal Function を返す。
+type CommEventHandler interface {
+	// CommEventCounter はステータスワードとイベントカウンタを返す (FC 0x0B)
+	CommEventCounter() (status, count uint16)
+	// ClearCommEventCounter はイベントカウンタをクリアする (FC 0x08 サブファンクション 0x0A)
+	ClearCommEventCounter()
+}
+
+// CommEventRecorder は正常に応答したリクエストを Processor に数えさせる RequestHandler が実装するインターフェース。
+// 各リクエストの処理の中で自分で数えるハンドラーは実装しない。
+type CommEventRecorder interface {
+	// RecordCommEvent は正常に応答したリクエストを1件数える
+	RecordCommEvent()
+}
+
+// RequestRecorder は応答したリクエストのファンクションコードと例外応答の有無を Processor から受け取る
+// RequestHandler が実装するインターフェース（通信統計用）。
+type RequestRecorder interface {
+	// RecordRequest は応答したリクエストを1件記録する
+	RecordRequest(functionCode byte, exception bool)
+}
+
 // Processor はModbus RTUリクエストを処理する
 type Processor struct {
 	handler RequestHandler
@@ -43,6 +74,16 @@ func (p *Processor) Process(req *Request) []byte {
 		return nil
 	}
 
+	response := p.dispatch(req)
+	exception := isExceptionResponse(response)
+	recordCommEvent(p.handler, req, exception)
+	if recorder, ok := p.handler.(RequestRecorder); ok {
+		recorder.RecordRequest(req.FunctionCode, exception)
+	}
+	return response
+}
+
+func (p *Processor) dispatch(req *Request) []byte {
 	switch req.FunctionCode {
 	case FuncReadCoils:
 		return p.processReadCoils(req)
@@ -60,6 +101,12 @@ func (p *Processor) Process(req *Request) []byte {
 		return p.processWriteMultipleCoils(req)
 	case FuncWriteMultipleRegisters:
 		return p.processWriteMultipleRegisters(req)
+	case FuncReadFIFOQueue:
+		return p.processReadFIFOQueue(req)
+	case FuncDiagnostics:
+		return p.processDiagnostics(req)
+	case FuncGetCommEventCounter:
+		return p.processGetCommEventCounter(req)
 	default:
 		return BuildExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
 	}
@@ -133,6 +180,9 @@ func (p *Processor) processWriteSingleRegister(req *Request) []byte {
 }
 
 func (p *Processor) processWriteMultipleCoils(req *Request) []byte {
+	if !coilDataValid(req) {
+		return BuildExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalDataValue)
+	}
 	values := unpackBools(req.Data, int(req.Quantity))
 	if err := p.handler.HandleWriteMultipleCoils(req.UnitID, req.Address, values); err != nil {
 		return p.buildExceptionFromError(req.UnitID, req.FunctionCode, err)
@@ -148,23 +198,68 @@ func (p *Processor) processWriteMultipleRegisters(req *Request) []byte {
 	return BuildWriteMultipleResponse(req.UnitID, req.FunctionCode, req.Address, req.Quantity)
 }
 
-func (p *Processor) buildExceptionFromError(unitID, funcCode byte, err error) []byte {
-	var exCode byte
-	switch err {
-	case ErrIllegalFunction:
-		exCode = ExceptionIllegalFunction
-	case ErrIllegalDataAddress:
-		exCode = ExceptionIllegalDataAddress
-	case ErrIllegalDataValue:
-		exCode = ExceptionIllegalDataValue
-	default:
-		if me, ok := err.(*ModbusException); ok {
-			exCode = me.Code
-		} else {
-			exCode = ExceptionSlaveDeviceFailure
-		}
+func (p *Processor) processReadFIFOQueue(req *Request) []byte {
+	fifo, ok := p.handler.(FIFOHandler)
+	if !ok {
+		return BuildExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
 	}
-	return BuildExceptionResponse(unitID, funcCode, exCode)
+	values, err := fifo.HandleReadFIFOQueue(req.UnitID, req.Address)
+	if err != nil {
+		return p.buildExceptionFromError(req.UnitID, req.FunctionCode, err)
+	}
+	return BuildReadFIFOResponse(req.UnitID, values)
+}
+
+func (p *Processor) processDiagnostics(req *Request) []byte {
+	counter, ok := p.handler.(CommEventHandler)
+	if !ok || req.Address != DiagClearCounters {
+		return BuildExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
+	}
+	counter.ClearCommEventCounter()
+	// 正常応答はリクエストのエコー
+	return BuildWriteSingleResponse(req.UnitID, req.FunctionCode, req.Address, binary.BigEndian.Uint16(req.Data))
+}
+
+func (p *Processor) processGetCommEventCounter(req *Request) []byte {
+	counter, ok := p.handler.(CommEventHandler)
+	if !ok {
+		return BuildExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalFunction)
+	}
+	status, count := counter.CommEventCounter()
+	return BuildCommEventCounterResponse(req.UnitID, status, count)
+}
+
+// recordCommEvent は正常に応答したリクエストをイベントカウンタに数える。
+// 仕様に従い例外応答・ブロードキャスト（UnitID 0）・FC 0x0B 自身は数えない。
+// クリア直後に 0 を返せるよう、カウンタをクリアする FC 0x08 も数えない。
+func recordCommEvent(handler RequestHandler, req *Request, exception bool) {
+	counter, ok := handler.(CommEventRecorder)
+	if !ok || exception || req.UnitID == 0 {
+		return
+	}
+	if req.FunctionCode == FuncGetCommEventCounter || req.FunctionCode == FuncDiagnostics {
+		return
+	}
+	counter.RecordCommEvent()
+}
+
+// ProcessParseError は解析に失敗したリクエストへの応答を返す。
+// ErrIllegalDataValue で UnitID が有効な場合のみ例外応答を返し、それ以外は応答しない（nil）。
+func (p *Processor) ProcessParseError(req *Request, err error) []byte {
+	if req == nil || !errors.Is(err, ErrIllegalDataValue) || !p.handler.IsUnitIDEnabled(req.UnitID) {
+		return nil
+	}
+	return BuildExceptionResponse(req.UnitID, req.FunctionCode, ExceptionIllegalDataValue)
+}
+
+func (p *Processor) buildExceptionFromError(unitID, funcCode byte, err error) []byte {
+	return BuildExceptionResponse(unitID, funcCode, MapErrorToException(err))
+}
+
+// coilDataValid は FC15 のデータ長が ceil(Quantity/8) バイトと一致するかを返す。
+// 短いデータを unpackBools に渡すと不足分が false として書き込まれるため、書き込み前に確認する。
+func coilDataValid(req *Request) bool {
+	return req.Quantity > 0 && len(req.Data) == (int(req.Quantity)+7)/8
 }
 
 // unpackBools はバイト列をbool配列に展開する
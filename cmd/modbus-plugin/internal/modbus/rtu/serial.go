@@ -17,6 +17,9 @@ type SerialConfig struct {
 	Parity   string
 }
 
+// openSerialPort はシリアルポートを開く（テストで差し替え可能）
+var openSerialPort = serial.Open
+
 // SerialManager はシリアルポートの管理を行う
 type SerialManager struct {
 	mu           sync.Mutex
@@ -92,7 +95,7 @@ func (sm *SerialManager) Open() error {
 		StopBits: stopBits,
 	}
 
-	port, err := serial.Open(sm.config.Port, mode)
+	port, err := openSerialPort(sm.config.Port, mode)
 	if err != nil {
 		return fmt.Errorf("failed to open serial port: %w", err)
 	}
@@ -101,6 +104,21 @@ func (sm *SerialManager) Open() error {
 	return nil
 }
 
+// Reopen は失われたポートを閉じて開き直す（Close 済みの場合はエラー）
+func (sm *SerialManager) Reopen() error {
+	sm.mu.Lock()
+	if sm.closed {
+		sm.mu.Unlock()
+		return fmt.Errorf("serial port closed")
+	}
+	if sm.port != nil {
+		sm.port.Close()
+		sm.port = nil
+	}
+	sm.mu.Unlock()
+	return sm.Open()
+}
+
 // Close はシリアルポートを閉じる
 func (sm *SerialManager) Close() error {
 	sm.mu.Lock()
@@ -158,14 +176,9 @@ func (sm *SerialManager) ReadFrame() ([]byte, error) {
 			}
 			sm.mu.Unlock()
 
-			// タイムアウトの場合
-			if len(frame) > 0 {
-				// 既にデータがある場合は、3.5文字時間経過でフレーム完了とみなす
-				if time.Since(lastReadTime) >= silenceTime {
-					return frame, nil
-				}
-			}
-			continue
+			// タイムアウトは err == nil, n == 0 で返るため、ここに来るのは
+			// USB 抜去などでポートが失われた場合
+			return nil, fmt.Errorf("%w: %v", ErrPortLost, err)
 		}
 
 		if n > 0 {
@@ -2,10 +2,13 @@ package rtu
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
+
+	"modbus_simulator/pkg/logging"
 )
 
 // RTUServer はModbus RTUサーバーを表す
@@ -17,6 +20,8 @@ type RTUServer struct {
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
+	logger    *slog.Logger
+	watchdog  *portWatchdog
 }
 
 // NewRTUServer は新しいRTUServerを作成する
@@ -24,9 +29,27 @@ func NewRTUServer(config SerialConfig, handler RequestHandler) *RTUServer {
 	return &RTUServer{
 		serial:    NewSerialManager(config),
 		processor: NewProcessor(handler),
+		logger:    logging.Default().With("protocol", "modbus-rtu", "port", config.Port),
+		watchdog:  newPortWatchdog(),
 	}
 }
 
+// SetLogger はロガーを設定する（nil の場合は出力しない）
+func (s *RTUServer) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = logging.Discard()
+	}
+	s.mu.Lock()
+	s.logger = logger.With("protocol", "modbus-rtu", "port", s.serial.config.Port)
+	s.mu.Unlock()
+}
+
+func (s *RTUServer) log() *slog.Logger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logger
+}
+
 // Start はサーバーを起動する
 func (s *RTUServer) Start() error {
 	s.mu.Lock()
@@ -46,6 +69,7 @@ func (s *RTUServer) Start() error {
 	s.wg.Add(1)
 	go s.mainLoop()
 
+	s.watchdog.linkChanged(true)
 	return nil
 }
 
@@ -66,6 +90,7 @@ func (s *RTUServer) Stop() error {
 	// ゴルーチンの終了を待つ
 	s.wg.Wait()
 
+	s.watchdog.linkChanged(false)
 	return nil
 }
 
@@ -76,6 +101,22 @@ func (s *RTUServer) IsRunning() bool {
 	return s.running
 }
 
+// SetAutoReconnect はポート喪失時に自動で再接続を試みるかどうかを設定する。
+// 無効の場合、ポート喪失を検出するとサーバーは停止する。
+func (s *RTUServer) SetAutoReconnect(enabled bool) {
+	s.watchdog.setAutoReconnect(enabled)
+}
+
+// SetOnPortStateChanged はポート喪失（err != nil）・復旧（err == nil）時のコールバックを設定する
+func (s *RTUServer) SetOnPortStateChanged(cb func(err error)) {
+	s.watchdog.setOnStateChanged(cb)
+}
+
+// SetConnectionEmitter はシリアル回線の接続状態（接続数 0/1）の通知先を設定する
+func (s *RTUServer) SetConnectionEmitter(emitter ConnectionEmitter) {
+	s.watchdog.setConnectionEmitter(emitter)
+}
+
 func (s *RTUServer) mainLoop() {
 	defer s.wg.Done()
 
@@ -84,42 +125,98 @@ func (s *RTUServer) mainLoop() {
 		case <-s.ctx.Done():
 			return
 		default:
-			s.processNextRequest()
 		}
+
+		err := s.processNextRequest()
+		if err == nil {
+			s.watchdog.readSucceeded()
+			continue
+		}
+		if !s.watchdog.readFailed() {
+			sleepCtx(s.ctx, readFailureBackoff)
+			continue
+		}
+		if !s.watchdog.recover(s.ctx, err, s.serial.Reopen, s.log()) {
+			s.stopFromLoop()
+			return
+		}
+	}
+}
+
+// stopFromLoop はポート喪失で復旧できない場合に mainLoop 側からサーバーを停止する
+func (s *RTUServer) stopFromLoop() {
+	s.mu.Lock()
+	if s.running {
+		s.cancel()
+		s.running = false
 	}
+	s.mu.Unlock()
+	s.serial.Close()
 }
 
-func (s *RTUServer) processNextRequest() {
+// processNextRequest は1フレームを受信して処理する。
+// ポートが失われた場合のみエラーを返す（タイムアウトやフレーム異常は nil）。
+func (s *RTUServer) processNextRequest() error {
 	// フレームを読み取る
 	frame, err := s.serial.ReadFrame()
 	if err != nil {
+		if errors.Is(err, ErrPortLost) {
+			return err
+		}
 		// タイムアウトは正常なので無視
-		return
+		return nil
 	}
 
 	if len(frame) == 0 {
-		return
+		return nil
+	}
+
+	response := s.handleFrame(frame)
+	if response == nil {
+		return nil
+	}
+
+	// 応答前に3.5文字時間待機
+	time.Sleep(s.serial.SilenceTime())
+
+	// レスポンスを送信
+	if err := s.serial.Write(response); err != nil {
+		s.log().Warn("failed to write response", "error", err)
 	}
+	return nil
+}
+
+// handleFrame は受信フレーム1つを処理し、送信すべきレスポンスを返す（応答しない場合は nil）
+func (s *RTUServer) handleFrame(frame []byte) []byte {
+	logger := s.log()
 
 	// リクエストを解析
 	req, err := ParseRequest(frame)
 	if err != nil {
-		log.Printf("RTU: failed to parse request: %v", err)
-		return
+		logger.Warn("failed to parse request", "error", err, "frameLen", len(frame))
+		// Quantity や ByteCount の不正には例外応答を返す
+		return s.processor.ProcessParseError(req, err)
 	}
 
 	// リクエストを処理
 	response := s.processor.Process(req)
 	if response == nil {
 		// UnitIDが無効な場合は応答しない
-		return
+		logger.Debug("request ignored", "unitId", req.UnitID, "funcCode", req.FunctionCode)
+		return nil
 	}
 
-	// 応答前に3.5文字時間待機
-	time.Sleep(s.serial.SilenceTime())
+	logger.Debug("request handled",
+		"unitId", req.UnitID,
+		"funcCode", req.FunctionCode,
+		"address", req.Address,
+		"quantity", req.Quantity,
+		"exception", isExceptionResponse(response),
+	)
+	return response
+}
 
-	// レスポンスを送信
-	if err := s.serial.Write(response); err != nil {
-		log.Printf("RTU: failed to write response: %v", err)
-	}
+// isExceptionResponse はレスポンスが例外応答（FC の最上位ビットが立っている）かどうかを返す
+func isExceptionResponse(response []byte) bool {
+	return len(response) >= 2 && response[1]&0x80 != 0
 }
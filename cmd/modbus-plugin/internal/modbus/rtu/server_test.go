@@ -0,0 +1,99 @@
+package rtu
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// stubHandler は固定値を返すテスト用の RequestHandler
+type stubHandler struct{}
+
+func (stubHandler) HandleReadCoils(_ byte, _, quantity uint16) ([]bool, error) {
+	return make([]bool, quantity), nil
+}
+func (stubHandler) HandleReadDiscreteInputs(_ byte, _, quantity uint16) ([]bool, error) {
+	return make([]bool, quantity), nil
+}
+func (stubHandler) HandleReadHoldingRegisters(_ byte, _, quantity uint16) ([]uint16, error) {
+	return make([]uint16, quantity), nil
+}
+func (stubHandler) HandleReadInputRegisters(_ byte, _, quantity uint16) ([]uint16, error) {
+	return make([]uint16, quantity), nil
+}
+func (stubHandler) HandleWriteSingleCoil(byte, uint16, bool) error            { return nil }
+func (stubHandler) HandleWriteSingleRegister(byte, uint16, uint16) error      { return nil }
+func (stubHandler) HandleWriteMultipleCoils(byte, uint16, []bool) error       { return nil }
+func (stubHandler) HandleWriteMultipleRegisters(byte, uint16, []uint16) error { return nil }
+func (stubHandler) IsUnitIDEnabled(byte) bool                                 { return true }
+
+var testSerialConfig = SerialConfig{Port: "COM9", BaudRate: 9600, DataBits: 8, StopBits: 1, Parity: "N"}
+
+func newBufferLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: level}))
+}
+
+func TestRTUServer_LogsHandledRead(t *testing.T) {
+	var buf bytes.Buffer
+	srv := NewRTUServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(newBufferLogger(&buf, slog.LevelDebug))
+
+	// UnitID=1, FC=03, Address=0, Quantity=2
+	frame := AppendCRC([]byte{0x01, FuncReadHoldingRegisters, 0x00, 0x00, 0x00, 0x02})
+	if resp := srv.handleFrame(frame); resp == nil {
+		t.Fatal("expected response")
+	}
+
+	out := buf.String()
+	for _, want := range []string{"request handled", "protocol=modbus-rtu", "port=COM9", "unitId=1", "funcCode=3", "quantity=2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected log to contain %q, got %q", want, out)
+		}
+	}
+}
+
+func TestRTUServer_LogsParseError(t *testing.T) {
+	var buf bytes.Buffer
+	srv := NewRTUServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(newBufferLogger(&buf, slog.LevelWarn))
+
+	// CRC 不正
+	if resp := srv.handleFrame([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00}); resp != nil {
+		t.Fatal("expected no response for bad CRC")
+	}
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "failed to parse request") {
+		t.Errorf("expected parse warning, got %q", out)
+	}
+}
+
+func TestRTUServer_LevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+	srv := NewRTUServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(newBufferLogger(&buf, slog.LevelInfo))
+
+	frame := AppendCRC([]byte{0x01, FuncReadCoils, 0x00, 0x00, 0x00, 0x08})
+	srv.handleFrame(frame)
+	if buf.Len() != 0 {
+		t.Errorf("expected debug records to be filtered at info level, got %q", buf.String())
+	}
+}
+
+func TestASCIIServer_LogsHandledRead(t *testing.T) {
+	var buf bytes.Buffer
+	srv := NewASCIIServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(newBufferLogger(&buf, slog.LevelDebug))
+
+	frame := BuildASCIIFrame([]byte{0x02, FuncReadInputRegisters, 0x00, 0x0A, 0x00, 0x01})
+	if resp := srv.handleFrame(frame); resp == nil {
+		t.Fatal("expected response")
+	}
+
+	out := buf.String()
+	for _, want := range []string{"request handled", "protocol=modbus-ascii", "unitId=2", "funcCode=4", "address=10"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected log to contain %q, got %q", want, out)
+		}
+	}
+}
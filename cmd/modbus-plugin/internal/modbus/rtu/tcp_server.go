@@ -0,0 +1,272 @@
+package rtu
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"modbus_simulator/pkg/logging"
+)
+
+// tcpReadBufferSize は1回の受信で読み取る最大バイト数
+const tcpReadBufferSize = 4096
+
+// ClientHandlerProvider は接続元ごとに別のハンドラーで処理したい RequestHandler が実装するインターフェース。
+// TCPServer は接続を受け付けるたびに接続元のアドレス（host:port）を渡し、返されたハンドラーでその接続のリクエストを処理する。
+type ClientHandlerProvider interface {
+	ForClient(clientAddr string) RequestHandler
+}
+
+// TCPServer は MBAP ヘッダーを自前で解析する Modbus TCP サーバー。
+// RTU/ASCII と同じ RequestHandler で処理し、Transaction ID と Unit ID をログに残す。
+type TCPServer struct {
+	mu          sync.Mutex
+	address     string
+	handler     RequestHandler
+	processor   *Processor
+	listener    net.Listener
+	conns       map[net.Conn]struct{}
+	running     bool
+	wg          sync.WaitGroup
+	logger      *slog.Logger
+	idleTimeout time.Duration
+	connEmitter ConnectionEmitter
+}
+
+// NewTCPServer は address（host:port）で待ち受ける TCPServer を作成する
+func NewTCPServer(address string, handler RequestHandler) *TCPServer {
+	return &TCPServer{
+		address:   address,
+		handler:   handler,
+		processor: NewProcessor(handler),
+		conns:     make(map[net.Conn]struct{}),
+		logger:    logging.Default().With("protocol", "modbus-tcp", "address", address),
+	}
+}
+
+// SetLogger はロガーを設定する（nil の場合は出力しない）
+func (s *TCPServer) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = logging.Discard()
+	}
+	s.mu.Lock()
+	s.logger = logger.With("protocol", "modbus-tcp", "address", s.address)
+	s.mu.Unlock()
+}
+
+func (s *TCPServer) log() *slog.Logger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logger
+}
+
+// SetIdleTimeout は無通信の接続を切断するまでの時間を設定する（0 の場合は切断しない）。Start 前に呼ぶこと。
+func (s *TCPServer) SetIdleTimeout(d time.Duration) {
+	s.mu.Lock()
+	s.idleTimeout = d
+	s.mu.Unlock()
+}
+
+// SetConnectionEmitter は接続数の変化の通知先を設定する
+func (s *TCPServer) SetConnectionEmitter(emitter ConnectionEmitter) {
+	s.mu.Lock()
+	s.connEmitter = emitter
+	s.mu.Unlock()
+}
+
+// Start は待ち受けを開始する
+func (s *TCPServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return fmt.Errorf("server is already running")
+	}
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	s.running = true
+
+	s.wg.Add(1)
+	go s.acceptLoop(listener)
+	return nil
+}
+
+// Stop は待ち受けを停止し、全ての接続を閉じる
+func (s *TCPServer) Stop() error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = false
+	s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// IsRunning はサーバーが実行中かどうかを返す
+func (s *TCPServer) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Addr は待ち受けているアドレスを返す（起動していない場合は nil）。
+// ポート 0 で起動した場合に実際のポートを知るために使う。
+func (s *TCPServer) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *TCPServer) acceptLoop(listener net.Listener) {
+	defer s.wg.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.log().Warn("failed to accept connection", "error", err)
+			}
+			return
+		}
+		if !s.trackConn(conn) {
+			conn.Close()
+			return
+		}
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+// trackConn は接続を登録する。停止済みの場合は false を返す。
+func (s *TCPServer) trackConn(conn net.Conn) bool {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	count, emitter := len(s.conns), s.connEmitter
+	s.mu.Unlock()
+
+	if emitter != nil {
+		emitter.EmitConnection(count)
+	}
+	return true
+}
+
+func (s *TCPServer) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	count, emitter := len(s.conns), s.connEmitter
+	s.mu.Unlock()
+
+	if emitter != nil {
+		emitter.EmitConnection(count)
+	}
+}
+
+// serveConn は1接続のリクエストを受信順に処理する。
+// 1回の受信に複数のリクエストが含まれる場合（パイプライン）は、それぞれの応答を同じ順序で返す。
+func (s *TCPServer) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer s.untrackConn(conn)
+	defer conn.Close()
+
+	s.mu.Lock()
+	idleTimeout := s.idleTimeout
+	s.mu.Unlock()
+	clientAddr := conn.RemoteAddr().String()
+	logger := s.log().With("client", clientAddr)
+	processor := s.processor
+	if provider, ok := s.handler.(ClientHandlerProvider); ok {
+		processor = NewProcessor(provider.ForClient(clientAddr))
+	}
+
+	var acc MBAPAccumulator
+	buf := make([]byte, tcpReadBufferSize)
+	for {
+		if idleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		}
+		n, err := conn.Read(buf)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				logger.Debug("connection closed", "error", err)
+			}
+			return
+		}
+
+		frames, ferr := acc.Feed(buf[:n])
+		var out []byte
+		for _, frame := range frames {
+			out = append(out, s.handleFrame(processor, frame, logger)...)
+		}
+		if len(out) > 0 {
+			if _, err := conn.Write(out); err != nil {
+				logger.Warn("failed to write response", "error", err)
+				return
+			}
+		}
+		if ferr != nil {
+			logger.Warn("invalid MBAP header, closing connection", "error", ferr)
+			return
+		}
+	}
+}
+
+// handleFrame は受信した Modbus TCP フレーム1つを処理し、送信すべきレスポンスを返す（応答しない場合は nil）
+func (s *TCPServer) handleFrame(processor *Processor, frame []byte, logger *slog.Logger) []byte {
+	header, req, err := ParseMBAPFrame(frame)
+	if err != nil {
+		logger.Warn("failed to parse request", "error", err, "transactionId", header.TransactionID, "unitId", header.UnitID)
+		return s.buildResponse(header, processor.ProcessParseError(req, err), logger)
+	}
+
+	response := processor.Process(req)
+	if response == nil {
+		logger.Debug("request ignored", "transactionId", header.TransactionID, "unitId", req.UnitID, "funcCode", req.FunctionCode)
+		return nil
+	}
+
+	logger.Debug("request handled",
+		"transactionId", header.TransactionID,
+		"unitId", req.UnitID,
+		"funcCode", req.FunctionCode,
+		"address", req.Address,
+		"quantity", req.Quantity,
+		"exception", isExceptionResponse(response),
+	)
+	return s.buildResponse(header, response, logger)
+}
+
+// buildResponse は RTU 形式のレスポンスを Modbus TCP フレームに変換し、
+// リクエストの Transaction ID・Protocol ID・Unit ID を返しているかを検証する（不一致の場合は応答しない）
+func (s *TCPServer) buildResponse(header MBAPHeader, response []byte, logger *slog.Logger) []byte {
+	frame := BuildMBAPResponse(header, response)
+	if frame == nil {
+		return nil
+	}
+	if err := VerifyMBAPResponse(header, frame); err != nil {
+		logger.Error("response dropped", "error", err, "transactionId", header.TransactionID)
+		return nil
+	}
+	return frame
+}
@@ -0,0 +1,140 @@
+package rtu
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// maxConsecutiveReadFailures はポート喪失と判定するまでの連続読み取り失敗回数
+	maxConsecutiveReadFailures = 3
+	// readFailureBackoff は読み取り失敗後、次の読み取りまでの待機時間
+	readFailureBackoff = 100 * time.Millisecond
+	// defaultReconnectInterval は自動再接続を試みる間隔
+	defaultReconnectInterval = 2 * time.Second
+)
+
+// ConnectionEmitter は接続数の変化を通知する（protocol.CommunicationEventEmitter の一部）
+type ConnectionEmitter interface {
+	EmitConnection(count int)
+}
+
+// portWatchdog はシリアルポートの喪失を検出し、自動再接続を行う。
+// RTUServer / ASCIIServer の mainLoop から使用する。
+// シリアル回線は1本の仮想的な接続として扱い、ポートを開いている間は接続数1を通知する。
+type portWatchdog struct {
+	mu             sync.Mutex
+	autoReconnect  bool
+	interval       time.Duration
+	failures       int
+	onStateChanged func(err error)
+	connEmitter    ConnectionEmitter
+}
+
+func newPortWatchdog() *portWatchdog {
+	return &portWatchdog{interval: defaultReconnectInterval}
+}
+
+func (w *portWatchdog) setAutoReconnect(enabled bool) {
+	w.mu.Lock()
+	w.autoReconnect = enabled
+	w.mu.Unlock()
+}
+
+func (w *portWatchdog) setOnStateChanged(cb func(err error)) {
+	w.mu.Lock()
+	w.onStateChanged = cb
+	w.mu.Unlock()
+}
+
+func (w *portWatchdog) setConnectionEmitter(emitter ConnectionEmitter) {
+	w.mu.Lock()
+	w.connEmitter = emitter
+	w.mu.Unlock()
+}
+
+// linkChanged はシリアル回線の接続（1）・切断（0）を通知する
+func (w *portWatchdog) linkChanged(up bool) {
+	w.mu.Lock()
+	emitter := w.connEmitter
+	w.mu.Unlock()
+	if emitter == nil {
+		return
+	}
+	if up {
+		emitter.EmitConnection(1)
+	} else {
+		emitter.EmitConnection(0)
+	}
+}
+
+// notify はポート状態の変化を通知する（err == nil は復旧）
+func (w *portWatchdog) notify(err error) {
+	w.mu.Lock()
+	cb := w.onStateChanged
+	w.mu.Unlock()
+	w.linkChanged(err == nil)
+	if cb != nil {
+		cb(err)
+	}
+}
+
+// readSucceeded は読み取り成功（タイムアウトを含む）を記録する
+func (w *portWatchdog) readSucceeded() {
+	w.mu.Lock()
+	w.failures = 0
+	w.mu.Unlock()
+}
+
+// readFailed は読み取り失敗を記録し、ポート喪失と判定すべきかを返す
+func (w *portWatchdog) readFailed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.failures++
+	if w.failures >= maxConsecutiveReadFailures {
+		w.failures = 0
+		return true
+	}
+	return false
+}
+
+// recover はポート喪失時の処理を行う。
+// 自動再接続が有効な場合は ctx がキャンセルされるまで reopen を interval ごとに試み、
+// 復旧した場合に true を返す。無効な場合やキャンセルされた場合は false を返す。
+func (w *portWatchdog) recover(ctx context.Context, cause error, reopen func() error, logger *slog.Logger) bool {
+	logger.Error("serial port lost", "error", cause)
+	w.notify(cause)
+
+	w.mu.Lock()
+	auto := w.autoReconnect
+	interval := w.interval
+	w.mu.Unlock()
+	if !auto {
+		return false
+	}
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+		if err := reopen(); err != nil {
+			logger.Debug("reconnect failed", "attempt", attempt, "error", err)
+			continue
+		}
+		logger.Info("serial port reconnected", "attempt", attempt)
+		w.notify(nil)
+		return true
+	}
+}
+
+// sleepCtx は ctx がキャンセルされるまで最大 d 待機する
+func sleepCtx(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
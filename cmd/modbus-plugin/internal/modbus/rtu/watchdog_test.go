@@ -0,0 +1,316 @@
+package rtu
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// fakePort はテスト用の serial.Port。lost が true の間は読み取りがエラーになる。
+type fakePort struct {
+	mu      sync.Mutex
+	lost    bool
+	closed  bool
+	timeout time.Duration
+}
+
+func (p *fakePort) setLost(lost bool) {
+	p.mu.Lock()
+	p.lost = lost
+	p.mu.Unlock()
+}
+
+func (p *fakePort) Read(_ []byte) (int, error) {
+	p.mu.Lock()
+	lost, closed, timeout := p.lost, p.closed, p.timeout
+	p.mu.Unlock()
+	if lost || closed {
+		return 0, errors.New("device not configured")
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Millisecond
+	}
+	time.Sleep(timeout)
+	return 0, nil
+}
+
+func (p *fakePort) SetReadTimeout(t time.Duration) error {
+	p.mu.Lock()
+	p.timeout = t
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *fakePort) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *fakePort) Write(b []byte) (int, error) { return len(b), nil }
+func (p *fakePort) SetMode(*serial.Mode) error  { return nil }
+func (p *fakePort) Drain() error                { return nil }
+func (p *fakePort) ResetInputBuffer() error     { return nil }
+func (p *fakePort) ResetOutputBuffer() error    { return nil }
+func (p *fakePort) SetDTR(bool) error           { return nil }
+func (p *fakePort) SetRTS(bool) error           { return nil }
+func (p *fakePort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{}, nil
+}
+func (p *fakePort) Break(time.Duration) error { return nil }
+
+// fakeOpener は指定回数だけ失敗した後に新しい fakePort を返す openSerialPort の代替
+type fakeOpener struct {
+	mu       sync.Mutex
+	failures int
+	opened   []*fakePort
+}
+
+func (o *fakeOpener) open(_ string, _ *serial.Mode) (serial.Port, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.failures > 0 {
+		o.failures--
+		return nil, errors.New("no such file or directory")
+	}
+	p := &fakePort{}
+	o.opened = append(o.opened, p)
+	return p, nil
+}
+
+func (o *fakeOpener) port(i int) *fakePort {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.opened[i]
+}
+
+func (o *fakeOpener) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.opened)
+}
+
+func useFakeOpener(t *testing.T, o *fakeOpener) {
+	t.Helper()
+	prev := openSerialPort
+	openSerialPort = o.open
+	t.Cleanup(func() { openSerialPort = prev })
+}
+
+// stateRecorder はポート状態コールバックの呼び出しを記録する
+type stateRecorder struct {
+	ch chan error
+}
+
+func newStateRecorder() *stateRecorder {
+	return &stateRecorder{ch: make(chan error, 10)}
+}
+
+func (r *stateRecorder) record(err error) { r.ch <- err }
+
+func (r *stateRecorder) next(t *testing.T) error {
+	t.Helper()
+	select {
+	case err := <-r.ch:
+		return err
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for port state change")
+		return nil
+	}
+}
+
+func TestRTUServer_AutoReconnect(t *testing.T) {
+	opener := &fakeOpener{}
+	useFakeOpener(t, opener)
+
+	srv := NewRTUServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(nil)
+	srv.watchdog.interval = 10 * time.Millisecond
+	srv.SetAutoReconnect(true)
+	states := newStateRecorder()
+	srv.SetOnPortStateChanged(states.record)
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer srv.Stop()
+
+	// USB 抜去: 読み取りエラー、かつ2回は開き直しにも失敗する
+	opener.mu.Lock()
+	opener.failures = 2
+	opener.mu.Unlock()
+	opener.port(0).setLost(true)
+
+	if err := states.next(t); !errors.Is(err, ErrPortLost) {
+		t.Fatalf("expected ErrPortLost, got %v", err)
+	}
+	if err := states.next(t); err != nil {
+		t.Fatalf("expected recovery (nil), got %v", err)
+	}
+
+	if !srv.IsRunning() {
+		t.Error("expected server to keep running after reconnect")
+	}
+	if opener.count() != 2 {
+		t.Errorf("expected port to be reopened once, got %d opens", opener.count())
+	}
+}
+
+func TestRTUServer_PortLostWithoutAutoReconnect(t *testing.T) {
+	opener := &fakeOpener{}
+	useFakeOpener(t, opener)
+
+	srv := NewRTUServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(nil)
+	states := newStateRecorder()
+	srv.SetOnPortStateChanged(states.record)
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	opener.port(0).setLost(true)
+
+	if err := states.next(t); !errors.Is(err, ErrPortLost) {
+		t.Fatalf("expected ErrPortLost, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for srv.IsRunning() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if srv.IsRunning() {
+		t.Error("expected server to stop when auto reconnect is disabled")
+	}
+	if err := srv.Stop(); err != nil {
+		t.Errorf("Stop after port loss failed: %v", err)
+	}
+}
+
+func TestASCIIServer_AutoReconnect(t *testing.T) {
+	opener := &fakeOpener{}
+	useFakeOpener(t, opener)
+
+	srv := NewASCIIServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(nil)
+	srv.serial.SetReadTimeout(20 * time.Millisecond)
+	srv.watchdog.interval = 10 * time.Millisecond
+	srv.SetAutoReconnect(true)
+	states := newStateRecorder()
+	srv.SetOnPortStateChanged(states.record)
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer srv.Stop()
+
+	opener.mu.Lock()
+	opener.failures = 1
+	opener.mu.Unlock()
+	opener.port(0).setLost(true)
+
+	if err := states.next(t); !errors.Is(err, ErrPortLost) {
+		t.Fatalf("expected ErrPortLost, got %v", err)
+	}
+	if err := states.next(t); err != nil {
+		t.Fatalf("expected recovery (nil), got %v", err)
+	}
+	if !srv.IsRunning() {
+		t.Error("expected server to keep running after reconnect")
+	}
+}
+
+// connRecorder は EmitConnection の呼び出しを記録する
+type connRecorder struct {
+	ch chan int
+}
+
+func newConnRecorder() *connRecorder {
+	return &connRecorder{ch: make(chan int, 10)}
+}
+
+func (r *connRecorder) EmitConnection(count int) { r.ch <- count }
+
+func (r *connRecorder) next(t *testing.T) int {
+	t.Helper()
+	select {
+	case n := <-r.ch:
+		return n
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for connection event")
+		return -1
+	}
+}
+
+func TestSerialServers_ConnectionEvents(t *testing.T) {
+	type serialServer interface {
+		Start() error
+		Stop() error
+		SetConnectionEmitter(ConnectionEmitter)
+	}
+	newServers := map[string]func() serialServer{
+		"rtu": func() serialServer {
+			srv := NewRTUServer(testSerialConfig, stubHandler{})
+			srv.SetLogger(nil)
+			return srv
+		},
+		"ascii": func() serialServer {
+			srv := NewASCIIServer(testSerialConfig, stubHandler{})
+			srv.SetLogger(nil)
+			return srv
+		},
+	}
+
+	for name, newServer := range newServers {
+		t.Run(name, func(t *testing.T) {
+			useFakeOpener(t, &fakeOpener{})
+			srv := newServer()
+			conns := newConnRecorder()
+			srv.SetConnectionEmitter(conns)
+
+			if err := srv.Start(); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if n := conns.next(t); n != 1 {
+				t.Errorf("connection count after start = %d, want 1", n)
+			}
+			if err := srv.Stop(); err != nil {
+				t.Fatalf("Stop failed: %v", err)
+			}
+			if n := conns.next(t); n != 0 {
+				t.Errorf("connection count after stop = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestRTUServer_ConnectionEventsOnPortLoss(t *testing.T) {
+	opener := &fakeOpener{}
+	useFakeOpener(t, opener)
+
+	srv := NewRTUServer(testSerialConfig, stubHandler{})
+	srv.SetLogger(nil)
+	srv.watchdog.interval = 10 * time.Millisecond
+	srv.SetAutoReconnect(true)
+	conns := newConnRecorder()
+	srv.SetConnectionEmitter(conns)
+
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer srv.Stop()
+	if n := conns.next(t); n != 1 {
+		t.Fatalf("connection count after start = %d, want 1", n)
+	}
+
+	opener.port(0).setLost(true)
+	if n := conns.next(t); n != 0 {
+		t.Errorf("connection count after port loss = %d, want 0", n)
+	}
+	if n := conns.next(t); n != 1 {
+		t.Errorf("connection count after reconnect = %d, want 1", n)
+	}
+}
@@ -2,6 +2,8 @@ package modbus
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"sync"
 
 	"modbus_simulator/internal/domain/protocol"
@@ -31,11 +33,16 @@ type Server struct {
 	server         *modbus.ModbusServer
 	rtuServer      *rtu.RTUServer
 	asciiServer    *rtu.ASCIIServer
+	nativeTCP      *rtu.TCPServer
 	status         server.ServerStatus
 	lastErr        error
 	useDataStore   bool
-	eventEmitter   protocol.CommunicationEventEmitter
+	emitter        emitterRef // 起動したハンドラーと共有するイベントエミッターの参照
 	sessionManager *protocol.SessionManager
+
+	// シリアルポート喪失時の動作（RTU/ASCII のみ）
+	autoReconnect bool
+	onPortState   func(err error)
 }
 
 // NewServer は新しいModbusサーバーを作成する
@@ -103,21 +110,26 @@ func (s *Server) Start() error {
 
 // startTCPServer はTCPサーバーを起動する（simonvetter/modbusを使用）
 func (s *Server) startTCPServer() error {
+	if s.useDataStore && s.dsHandler != nil && s.modbusConfig != nil && s.modbusConfig.NativeTCP {
+		return s.startNativeTCPServer()
+	}
+
 	url := fmt.Sprintf("tcp://%s:%d", s.config.TCPAddress, s.config.TCPPort)
 
 	// 使用するハンドラーを決定
 	var handler modbus.RequestHandler
 	if s.useDataStore && s.dsHandler != nil {
 		reqHandler := NewDataStoreRequestHandler(s.dsHandler)
-		reqHandler.SetEventEmitter(s.eventEmitter)
+		reqHandler.emitter = &s.emitter
 		reqHandler.SetSessionManager(s.sessionManager)
-		handler = reqHandler
+		handler = recordingRequestHandler{reqHandler}
 	} else {
 		handler = s.handler
 	}
 
 	srv, err := modbus.NewServer(&modbus.ServerConfiguration{
-		URL: url,
+		URL:     url,
+		Timeout: clientIdleTimeout,
 	}, handler)
 	if err != nil {
 		s.status = server.StatusError
@@ -137,6 +149,30 @@ func (s *Server) startTCPServer() error {
 	return nil
 }
 
+// startNativeTCPServer はMBAPヘッダーを自前で解析するTCPサーバーを起動する（自作実装）。
+// リクエストは既定の待ち受けと同じ DataStoreRequestHandler で処理する。
+func (s *Server) startNativeTCPServer() error {
+	address := net.JoinHostPort(s.config.TCPAddress, strconv.Itoa(s.config.TCPPort))
+
+	reqHandler := NewDataStoreRequestHandler(s.dsHandler)
+	reqHandler.emitter = &s.emitter
+	reqHandler.SetSessionManager(s.sessionManager)
+	tcpSrv := rtu.NewTCPServer(address, NewTCPDataStoreAdapter(reqHandler))
+	tcpSrv.SetIdleTimeout(clientIdleTimeout)
+	tcpSrv.SetConnectionEmitter(&s.emitter)
+
+	if err := tcpSrv.Start(); err != nil {
+		s.status = server.StatusError
+		s.lastErr = err
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+
+	s.nativeTCP = tcpSrv
+	s.status = server.StatusRunning
+	s.lastErr = nil
+	return nil
+}
+
 // startRTUServer はRTUサーバーを起動する（自作実装）
 func (s *Server) startRTUServer() error {
 	config := rtu.SerialConfig{
@@ -150,12 +186,17 @@ func (s *Server) startRTUServer() error {
 	var adapter rtu.RequestHandler
 	if s.useDataStore && s.dsHandler != nil {
 		rtuAdapter := NewRTUDataStoreAdapter(s.dsHandler)
-		rtuAdapter.SetEventEmitter(s.eventEmitter)
+		rtuAdapter.emitter = &s.emitter
 		adapter = rtuAdapter
 	} else {
 		adapter = NewRTUHandlerAdapter(s.handler)
 	}
 	rtuSrv := rtu.NewRTUServer(config, adapter)
+	rtuSrv.SetAutoReconnect(s.autoReconnect)
+	if s.onPortState != nil {
+		rtuSrv.SetOnPortStateChanged(s.onPortState)
+	}
+	rtuSrv.SetConnectionEmitter(&s.emitter)
 
 	if err := rtuSrv.Start(); err != nil {
 		s.status = server.StatusError
@@ -182,12 +223,17 @@ func (s *Server) startASCIIServer() error {
 	var adapter rtu.RequestHandler
 	if s.useDataStore && s.dsHandler != nil {
 		rtuAdapter := NewRTUDataStoreAdapter(s.dsHandler)
-		rtuAdapter.SetEventEmitter(s.eventEmitter)
+		rtuAdapter.emitter = &s.emitter
 		adapter = rtuAdapter
 	} else {
 		adapter = NewRTUHandlerAdapter(s.handler)
 	}
 	asciiSrv := rtu.NewASCIIServer(config, adapter)
+	asciiSrv.SetAutoReconnect(s.autoReconnect)
+	if s.onPortState != nil {
+		asciiSrv.SetOnPortStateChanged(s.onPortState)
+	}
+	asciiSrv.SetConnectionEmitter(&s.emitter)
 
 	if err := asciiSrv.Start(); err != nil {
 		s.status = server.StatusError
@@ -226,6 +272,16 @@ func (s *Server) Stop() error {
 		return nil
 	}
 
+	// 自作TCPサーバーの停止
+	if s.nativeTCP != nil {
+		if err := s.nativeTCP.Stop(); err != nil {
+			return fmt.Errorf("failed to stop server: %w", err)
+		}
+		s.nativeTCP = nil
+		s.status = server.StatusStopped
+		return nil
+	}
+
 	// TCPサーバーの停止
 	if s.server == nil {
 		return nil
@@ -329,11 +385,31 @@ func (s *Server) SetDisabledUnitIDs(ids []uint8) {
 	}
 }
 
-// SetEventEmitter はイベントエミッターを設定する
+// SetEventEmitter はイベントエミッターを設定する。
+// 起動したサーバーは通知のたびに参照を読むため、起動後に呼んだ場合も以降のリクエストと接続状態の通知に反映される。
 func (s *Server) SetEventEmitter(emitter protocol.CommunicationEventEmitter) {
+	s.emitter.Store(emitter)
+}
+
+// SetAutoReconnect はシリアルポート喪失時に自動再接続するかどうかを設定する（RTU/ASCII のみ）
+func (s *Server) SetAutoReconnect(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.autoReconnect = enabled
+	if s.rtuServer != nil {
+		s.rtuServer.SetAutoReconnect(enabled)
+	}
+	if s.asciiServer != nil {
+		s.asciiServer.SetAutoReconnect(enabled)
+	}
+}
+
+// SetOnPortStateChanged はシリアルポート喪失・復旧時のコールバックを設定する（Start 前に呼ぶこと）。
+// コールバックは受信ループから呼ばれるため、Server のロックを取得してはならない。
+func (s *Server) SetOnPortStateChanged(cb func(err error)) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.eventEmitter = emitter
+	s.onPortState = cb
 }
 
 // SetSessionManager はセッションマネージャーを設定する
@@ -0,0 +1,151 @@
+package modbus
+
+import (
+	"errors"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+
+	"github.com/simonvetter/modbus"
+)
+
+// TCPDataStoreAdapter は自前の Modbus TCP サーバー（nativeTCP）のリクエストを
+// 既定の待ち受けと同じ DataStoreRequestHandler で処理する rtu.RequestHandler。
+// ワードスワップ・レート制限・クライアント一覧・セッションの記録・イベントカウンタの数え方を既定の待ち受けと揃える。
+// rtu.TCPServer は接続ごとに ForClient で接続元のアドレスを持つアダプターを作る。
+type TCPDataStoreAdapter struct {
+	req        *DataStoreRequestHandler
+	clientAddr string
+}
+
+// NewTCPDataStoreAdapter は新しいTCPDataStoreAdapterを作成する
+func NewTCPDataStoreAdapter(req *DataStoreRequestHandler) *TCPDataStoreAdapter {
+	return &TCPDataStoreAdapter{req: req}
+}
+
+// ForClient は接続元 clientAddr のリクエストを処理するアダプターを返す
+func (a *TCPDataStoreAdapter) ForClient(clientAddr string) rtu.RequestHandler {
+	return &TCPDataStoreAdapter{req: a.req, clientAddr: clientAddr}
+}
+
+// HandleReadCoils はコイル読み取りを処理する (FC 01)
+func (a *TCPDataStoreAdapter) HandleReadCoils(unitID byte, address, quantity uint16) ([]bool, error) {
+	values, err := a.req.HandleCoils(&modbus.CoilsRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: quantity,
+	})
+	return values, rtuError(err)
+}
+
+// HandleReadDiscreteInputs はディスクリート入力読み取りを処理する (FC 02)
+func (a *TCPDataStoreAdapter) HandleReadDiscreteInputs(unitID byte, address, quantity uint16) ([]bool, error) {
+	values, err := a.req.HandleDiscreteInputs(&modbus.DiscreteInputsRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: quantity,
+	})
+	return values, rtuError(err)
+}
+
+// HandleReadHoldingRegisters は保持レジスタ読み取りを処理する (FC 03)
+func (a *TCPDataStoreAdapter) HandleReadHoldingRegisters(unitID byte, address, quantity uint16) ([]uint16, error) {
+	values, err := a.req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: quantity,
+	})
+	return values, rtuError(err)
+}
+
+// HandleReadInputRegisters は入力レジスタ読み取りを処理する (FC 04)
+func (a *TCPDataStoreAdapter) HandleReadInputRegisters(unitID byte, address, quantity uint16) ([]uint16, error) {
+	values, err := a.req.HandleInputRegisters(&modbus.InputRegistersRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: quantity,
+	})
+	return values, rtuError(err)
+}
+
+// HandleWriteSingleCoil は単一コイル書き込みを処理する (FC 05)
+func (a *TCPDataStoreAdapter) HandleWriteSingleCoil(unitID byte, address uint16, value bool) error {
+	return rtuError(a.req.HandleWriteSingleCoil(&modbus.CoilsRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: 1, IsWrite: true, Args: []bool{value},
+	}))
+}
+
+// HandleWriteSingleRegister は単一レジスタ書き込みを処理する (FC 06)
+func (a *TCPDataStoreAdapter) HandleWriteSingleRegister(unitID byte, address, value uint16) error {
+	_, err := a.req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: 1, IsWrite: true, Args: []uint16{value},
+	})
+	return rtuError(err)
+}
+
+// HandleWriteMultipleCoils は複数コイル書き込みを処理する (FC 15)
+func (a *TCPDataStoreAdapter) HandleWriteMultipleCoils(unitID byte, address uint16, values []bool) error {
+	return rtuError(a.req.HandleWriteMultipleCoils(&modbus.CoilsRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: uint16(len(values)), IsWrite: true, Args: values,
+	}))
+}
+
+// HandleWriteMultipleRegisters は複数レジスタ書き込みを処理する (FC 16)
+func (a *TCPDataStoreAdapter) HandleWriteMultipleRegisters(unitID byte, address uint16, values []uint16) error {
+	_, err := a.req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{
+		ClientAddr: a.clientAddr, UnitId: unitID, Addr: address, Quantity: uint16(len(values)), IsWrite: true, Args: values,
+	})
+	return rtuError(err)
+}
+
+// IsUnitIDEnabled は常に true を返す。
+// 既定の待ち受けと同じく、無効な UnitID へのリクエストにも応答し、各処理で Illegal Function 例外を返す。
+func (a *TCPDataStoreAdapter) IsUnitIDEnabled(unitID byte) bool {
+	return true
+}
+
+// HandleReadFIFOQueue は FIFO キュー読み取りを処理する (FC 0x18)。
+// 既定の待ち受けの各処理と同じく、正常に応答した場合はイベントカウンタに数える。
+func (a *TCPDataStoreAdapter) HandleReadFIFOQueue(unitID byte, pointer uint16) ([]uint16, error) {
+	h := a.req.handler
+	a.req.emitRxTx(unitID, a.clientAddr)
+	if !h.IsUnitIdEnabled(unitID) {
+		return nil, rtu.ErrIllegalFunction
+	}
+	if err := a.req.throttle(a.clientAddr); err != nil {
+		return nil, rtuError(err)
+	}
+	values, ok := h.fifo.Read(pointer)
+	if !ok {
+		return nil, rtu.ErrIllegalDataAddress
+	}
+	h.RecordCommEvent()
+	return values, nil
+}
+
+// CommEventCounter はステータスワード（ビジー期間中は 0xFFFF、それ以外は 0x0000）とイベントカウンタを返す (FC 0x0B)
+func (a *TCPDataStoreAdapter) CommEventCounter() (status, count uint16) {
+	if a.req.handler.IsBusy() {
+		status = 0xFFFF
+	}
+	return status, a.req.handler.CommEventCount()
+}
+
+// ClearCommEventCounter はイベントカウンタをクリアする (FC 0x08 サブファンクション 0x0A)
+func (a *TCPDataStoreAdapter) ClearCommEventCounter() {
+	a.req.handler.ClearCommEventCounter()
+}
+
+// RecordRequest は応答したリクエストを通信統計に記録する（rtu.RequestRecorder）
+func (a *TCPDataStoreAdapter) RecordRequest(functionCode byte, exception bool) {
+	a.req.emitter.recordRequest(functionCode, exception)
+}
+
+// rtuError は DataStoreRequestHandler が返した simonvetter/modbus の例外を rtu の例外に変換する。
+// データストアのエラーはそのまま返し、rtu.MapErrorToException で例外コードに変換させる。
+func rtuError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, modbus.ErrIllegalFunction):
+		return rtu.ErrIllegalFunction
+	case errors.Is(err, modbus.ErrIllegalDataAddress):
+		return rtu.ErrIllegalDataAddress
+	case errors.Is(err, modbus.ErrIllegalDataValue):
+		return rtu.ErrIllegalDataValue
+	case errors.Is(err, modbus.ErrServerDeviceBusy):
+		return rtu.NewModbusException(rtu.ExceptionSlaveDeviceBusy)
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+// startNativeTCP は nativeTCP を有効にした cfg でサーバーを起動する（ファクトリーと同じく設定を反映してから起動する）
+func startNativeTCP(t *testing.T, cfg *ModbusConfig, store *ModbusDataStore) *ModbusServer {
+	t.Helper()
+	cfg.TCPAddress = "127.0.0.1"
+	cfg.TCPPort = testutil.FreeTCPPort(t)
+	cfg.NativeTCP = true
+	srv := NewModbusServer(cfg, store)
+	if err := srv.applyConfig(); err != nil {
+		t.Fatalf("applyConfig: %v", err)
+	}
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { srv.Stop() })
+	if srv.innerServer.nativeTCP == nil {
+		t.Fatal("native TCP server should be used")
+	}
+	return srv
+}
+
+func TestNativeTCP_WordSwap(t *testing.T) {
+	store := NewModbusDataStore(10, 10, 10, 10)
+	_ = store.WriteWords(AreaHoldingRegs, 0, []uint16{0x1111, 0x2222})
+	cfg := DefaultTCPConfig()
+	cfg.WordSwapAreas = []string{AreaHoldingRegs}
+	startNativeTCP(t, cfg, store)
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	vals, err := client.ReadRegisters(0, 2, modbus.HOLDING_REGISTER)
+	if err != nil || !reflect.DeepEqual(vals, []uint16{0x2222, 0x1111}) {
+		t.Errorf("swapped read = %04X, %v", vals, err)
+	}
+	if err := client.WriteRegisters(4, []uint16{0x0001, 0x0002}); err != nil {
+		t.Fatalf("WriteRegisters: %v", err)
+	}
+	if got, _ := store.ReadWords(AreaHoldingRegs, 4, 2); !reflect.DeepEqual(got, []uint16{0x0002, 0x0001}) {
+		t.Errorf("stored after swapped write = %04X", got)
+	}
+}
+
+func TestNativeTCP_RateLimitBusy(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.RateLimit = 1
+	cfg.RateLimitBusy = true
+	startNativeTCP(t, cfg, NewModbusDataStore(10, 10, 10, 10))
+
+	client := testutil.DialModbusTCP(t, cfg.TCPPort, 5*time.Second)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); !errors.Is(err, modbus.ErrServerDeviceBusy) {
+		t.Errorf("second request: err = %v, want ErrServerDeviceBusy", err)
+	}
+}
+
+func TestNativeTCP_GetClients(t *testing.T) {
+	srv := startNativeTCP(t, DefaultTCPConfig(), NewModbusDataStore(10, 10, 10, 10))
+
+	client := testutil.DialModbusTCP(t, srv.config.TCPPort, 5*time.Second)
+	client.SetUnitId(7)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("ReadRegister: %v", err)
+	}
+	clients := srv.GetClients()
+	if len(clients) != 1 || !strings.HasPrefix(clients[0].RemoteAddr, "127.0.0.1:") || clients[0].UnitID != 7 {
+		t.Errorf("clients = %+v, want one client 127.0.0.1:<port> with unit 7", clients)
+	}
+}
+
+func TestNativeTCP_DisabledUnitID(t *testing.T) {
+	srv := startNativeTCP(t, DefaultTCPConfig(), NewModbusDataStore(10, 10, 10, 10))
+	srv.SetUnitIdEnabled(3, false)
+
+	// 既定の待ち受けと同じく、無効な UnitID には Illegal Function 例外で応答する
+	client := testutil.DialModbusTCP(t, srv.config.TCPPort, 5*time.Second)
+	client.SetUnitId(3)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); !errors.Is(err, modbus.ErrIllegalFunction) {
+		t.Errorf("err = %v, want ErrIllegalFunction", err)
+	}
+}
@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"time"
+
+	"modbus_simulator/cmd/modbus-plugin/internal/modbus/rtu"
+	"modbus_simulator/internal/domain/protocol"
+
+	"github.com/simonvetter/modbus"
+)
+
+// defaultConnectionTimeout は無通信のユニットをセッション（接続数）から外すまでの既定の時間
+const defaultConnectionTimeout = 5 * time.Second
+
+// connectionTimeout 設定の範囲
+const (
+	minConnectionTimeout = 100 * time.Millisecond
+	maxConnectionTimeout = time.Hour
+)
+
+// connectionTimeout はセッションのタイムアウトを返す（未設定の場合は defaultConnectionTimeout）
+func (c *ModbusConfig) connectionTimeout() time.Duration {
+	if c.ConnectionTimeout <= 0 {
+		return defaultConnectionTimeout
+	}
+	return time.Duration(c.ConnectionTimeout) * time.Millisecond
+}
+
+// recordingRequestHandler は既定の TCP 待ち受け（simonvetter/modbus）で処理したリクエストの
+// ファンクションコードと例外応答の有無を通信統計に記録する。
+// RTU/ASCII と自作 TCP 実装では rtu.Processor がアダプターの RecordRequest を呼ぶため使わない。
+type recordingRequestHandler struct {
+	*DataStoreRequestHandler
+}
+
+// HandleCoils はコイルの読み取り・書き込みを処理して記録する (FC 01, 05, 15)
+func (h recordingRequestHandler) HandleCoils(req *modbus.CoilsRequest) ([]bool, error) {
+	values, err := h.DataStoreRequestHandler.HandleCoils(req)
+	fc := rtu.FuncReadCoils
+	if req.IsWrite {
+		fc = writeFunctionCode(req.Quantity, rtu.FuncWriteSingleCoil, rtu.FuncWriteMultipleCoils)
+	}
+	h.emitter.recordRequest(fc, err != nil)
+	return values, err
+}
+
+// HandleDiscreteInputs はディスクリート入力読み取りを処理して記録する (FC 02)
+func (h recordingRequestHandler) HandleDiscreteInputs(req *modbus.DiscreteInputsRequest) ([]bool, error) {
+	values, err := h.DataStoreRequestHandler.HandleDiscreteInputs(req)
+	h.emitter.recordRequest(rtu.FuncReadDiscreteInputs, err != nil)
+	return values, err
+}
+
+// HandleHoldingRegisters は保持レジスタの読み取り・書き込みを処理して記録する (FC 03, 06, 16)
+func (h recordingRequestHandler) HandleHoldingRegisters(req *modbus.HoldingRegistersRequest) ([]uint16, error) {
+	values, err := h.DataStoreRequestHandler.HandleHoldingRegisters(req)
+	fc := rtu.FuncReadHoldingRegisters
+	if req.IsWrite {
+		fc = writeFunctionCode(req.Quantity, rtu.FuncWriteSingleRegister, rtu.FuncWriteMultipleRegisters)
+	}
+	h.emitter.recordRequest(fc, err != nil)
+	return values, err
+}
+
+// HandleInputRegisters は入力レジスタ読み取りを処理して記録する (FC 04)
+func (h recordingRequestHandler) HandleInputRegisters(req *modbus.InputRegistersRequest) ([]uint16, error) {
+	values, err := h.DataStoreRequestHandler.HandleInputRegisters(req)
+	h.emitter.recordRequest(rtu.FuncReadInputRegisters, err != nil)
+	return values, err
+}
+
+// writeFunctionCode は書き込みリクエストのファンクションコードを点数から推定する。
+// simonvetter/modbus はファンクションコードを渡さないため、1点の書き込みは単一書き込みとみなす。
+func writeFunctionCode(quantity uint16, single, multiple byte) byte {
+	if quantity == 1 {
+		return single
+	}
+	return multiple
+}
+
+// TrafficStats はサーバーを作成してから処理したリクエスト数・例外応答数・最後のファンクションコード・接続数を返す
+func (s *ModbusServer) TrafficStats() (protocol.TrafficSnapshot, error) {
+	return s.traffic.Snapshot(), nil
+}
+
+// UnitActivity はセッションがタイムアウトしていない UnitID ごとの最終ポーリング時刻を返す
+func (s *ModbusServer) UnitActivity() (map[uint8]time.Time, error) {
+	return s.sessionManager.Snapshot(), nil
+}
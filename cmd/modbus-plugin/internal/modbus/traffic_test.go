@@ -0,0 +1,33 @@
+package modbus
+
+import (
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestNativeTCP_TrafficStats(t *testing.T) {
+	srv := startNativeTCP(t, DefaultTCPConfig(), NewModbusDataStore(10, 10, 10, 10))
+
+	client := testutil.DialModbusTCP(t, srv.config.TCPPort, 5*time.Second)
+	if _, err := client.ReadRegister(100, modbus.HOLDING_REGISTER); err == nil {
+		t.Fatal("read beyond the area should fail")
+	}
+	if err := client.WriteRegisters(0, []uint16{1, 2}); err != nil {
+		t.Fatalf("WriteRegisters: %v", err)
+	}
+
+	snap, err := srv.TrafficStats()
+	if err != nil {
+		t.Fatalf("TrafficStats: %v", err)
+	}
+	if snap.Requests != 2 || snap.Exceptions != 1 || snap.LastFunctionCode != 0x10 || snap.LastRequestAt.IsZero() {
+		t.Errorf("TrafficStats = %+v, want 2 requests, 1 exception, last FC 0x10", snap)
+	}
+	if snap.Connections == 0 {
+		t.Errorf("Connections = 0, want the connected client to be counted")
+	}
+}
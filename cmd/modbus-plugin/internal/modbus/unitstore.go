@@ -0,0 +1,154 @@
+package modbus
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// DefaultUnitID は共有（デフォルト）データストアに割り当てられる UnitID
+const DefaultUnitID uint8 = 1
+
+// SetPerUnitStore は UnitID ごとのデータストアを有効/無効にする。
+// 有効な場合、DefaultUnitID（とブロードキャストの 0）は共有ストアを、
+// それ以外の UnitID は初回アクセス時に作成される個別のストアを使用する。
+// 無効化しても作成済みのストアは保持され、再度有効にすると値が復元される。
+func (h *DataStoreHandler) SetPerUnitStore(enabled bool) {
+	h.unitMu.Lock()
+	defer h.unitMu.Unlock()
+	h.perUnit = enabled
+}
+
+// PerUnitStore は UnitID ごとのデータストアが有効かどうかを返す
+func (h *DataStoreHandler) PerUnitStore() bool {
+	h.unitMu.RLock()
+	defer h.unitMu.RUnlock()
+	return h.perUnit
+}
+
+// SetUnitIDRemap は UnitID の読み替え表（要求された UnitID → 応答に使う UnitID）を設定する。
+// ゲートウェイ経由で UnitID が付け替えられる構成を再現するためのもので、
+// 例えば {5: 1} の場合、UnitID 5 へのリクエストは UnitID 1 のデータストアで処理する。
+// UnitID ごとのデータストアが無効な場合は全 UnitID が共有ストアを使うため影響しない。nil で解除する。
+func (h *DataStoreHandler) SetUnitIDRemap(remap map[uint8]uint8) {
+	h.unitMu.Lock()
+	defer h.unitMu.Unlock()
+	h.unitRemap = make(map[uint8]uint8, len(remap))
+	for from, to := range remap {
+		if from != to {
+			h.unitRemap[from] = to
+		}
+	}
+}
+
+// UnitIDRemap は UnitID の読み替え表のコピーを返す
+func (h *DataStoreHandler) UnitIDRemap() map[uint8]uint8 {
+	h.unitMu.RLock()
+	defer h.unitMu.RUnlock()
+	remap := make(map[uint8]uint8, len(h.unitRemap))
+	for from, to := range h.unitRemap {
+		remap[from] = to
+	}
+	return remap
+}
+
+// parseUnitIDRemap は unitIdRemap 設定を解析する。
+// 形式は "要求された UnitID=応答に使う UnitID" のカンマ区切り（例: "5=1,6=1"）。
+func parseUnitIDRemap(v interface{}) (map[uint8]uint8, error) {
+	if v == nil {
+		return nil, nil
+	}
+	text, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("unitIdRemap: unsupported type %T", v)
+	}
+	var remap map[uint8]uint8
+	for _, entry := range strings.Split(text, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("unitIdRemap: invalid entry %q", entry)
+		}
+		from, err := strconv.ParseUint(strings.TrimSpace(key), 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("unitIdRemap: invalid unit ID in %q", entry)
+		}
+		to, err := strconv.ParseUint(strings.TrimSpace(value), 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("unitIdRemap: invalid unit ID in %q", entry)
+		}
+		if remap == nil {
+			remap = make(map[uint8]uint8)
+		}
+		remap[uint8(from)] = uint8(to)
+	}
+	return remap, nil
+}
+
+// formatUnitIDRemap は remap を unitIdRemap 設定の形式に変換する（要求された UnitID 順）
+func formatUnitIDRemap(remap map[uint8]uint8) string {
+	froms := make([]int, 0, len(remap))
+	for from := range remap {
+		froms = append(froms, int(from))
+	}
+	sort.Ints(froms)
+	entries := make([]string, len(froms))
+	for i, from := range froms {
+		entries[i] = fmt.Sprintf("%d=%d", from, remap[uint8(from)])
+	}
+	return strings.Join(entries, ",")
+}
+
+// StoreForUnit は指定 UnitID のリクエストに使用するデータストアを返す（読み替え表を適用する）
+func (h *DataStoreHandler) StoreForUnit(unitId uint8) protocol.DataStore {
+	h.unitMu.RLock()
+	if to, ok := h.unitRemap[unitId]; ok {
+		unitId = to
+	}
+	if !h.perUnit || unitId == DefaultUnitID || unitId == 0 {
+		h.unitMu.RUnlock()
+		return h.store
+	}
+	store, ok := h.unitStores[unitId]
+	h.unitMu.RUnlock()
+	if ok {
+		return store
+	}
+
+	h.unitMu.Lock()
+	defer h.unitMu.Unlock()
+	if store, ok := h.unitStores[unitId]; ok {
+		return store
+	}
+	store = newStoreLike(h.store)
+	store.SetOutOfRangePolicy(h.rangePolicy)
+	h.unitStores[unitId] = store
+	return store
+}
+
+// newStoreLike は共有ストアと同じエリアサイズの空のデータストアを作成する
+func newStoreLike(base protocol.DataStore) *ModbusDataStore {
+	if u, ok := base.(interface{ Unwrap() protocol.DataStore }); ok {
+		base = u.Unwrap()
+	}
+	if ms, ok := base.(*ModbusDataStore); ok {
+		// 非表示のエリアも同じサイズで作成する
+		ms.mu.RLock()
+		defer ms.mu.RUnlock()
+		store := NewModbusDataStore(len(ms.coils), len(ms.discreteInputs), len(ms.holdingRegs), len(ms.inputRegs))
+		store.visibleAreas = append([]string(nil), ms.visibleAreas...)
+		return store
+	}
+
+	sizes := make(map[string]int)
+	for _, area := range base.GetAreas() {
+		sizes[area.ID] = int(area.Size)
+	}
+	return NewModbusDataStore(sizes[AreaCoils], sizes[AreaDiscreteInputs], sizes[AreaHoldingRegs], sizes[AreaInputRegs])
+}
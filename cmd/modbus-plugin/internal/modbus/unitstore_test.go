@@ -0,0 +1,181 @@
+package modbus
+
+import (
+	"testing"
+
+	"github.com/simonvetter/modbus"
+)
+
+func writeHolding(t *testing.T, h *DataStoreRequestHandler, unitID uint8, addr, value uint16) {
+	t.Helper()
+	_, err := h.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{
+		UnitId: unitID, Addr: addr, Quantity: 1, IsWrite: true, Args: []uint16{value},
+	})
+	if err != nil {
+		t.Fatalf("write unit %d failed: %v", unitID, err)
+	}
+}
+
+func readHolding(t *testing.T, h *DataStoreRequestHandler, unitID uint8, addr uint16) uint16 {
+	t.Helper()
+	values, err := h.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{
+		UnitId: unitID, Addr: addr, Quantity: 1,
+	})
+	if err != nil {
+		t.Fatalf("read unit %d failed: %v", unitID, err)
+	}
+	return values[0]
+}
+
+func TestDataStoreHandler_SharedStoreByDefault(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(10, 10, 10, 10))
+	req := NewDataStoreRequestHandler(handler)
+
+	writeHolding(t, req, 1, 0, 100)
+	if got := readHolding(t, req, 2, 0); got != 100 {
+		t.Errorf("expected unit 2 to share unit 1 value 100, got %d", got)
+	}
+}
+
+func TestDataStoreHandler_PerUnitStoreIsolation(t *testing.T) {
+	shared := NewModbusDataStore(10, 10, 10, 10)
+	handler := NewDataStoreHandler(shared)
+	handler.SetPerUnitStore(true)
+	req := NewDataStoreRequestHandler(handler)
+
+	writeHolding(t, req, 1, 0, 111)
+	writeHolding(t, req, 2, 0, 222)
+
+	if got := readHolding(t, req, 1, 0); got != 111 {
+		t.Errorf("unit 1: expected 111, got %d", got)
+	}
+	if got := readHolding(t, req, 2, 0); got != 222 {
+		t.Errorf("unit 2: expected 222, got %d", got)
+	}
+	if got := readHolding(t, req, 3, 0); got != 0 {
+		t.Errorf("unit 3: expected fresh store, got %d", got)
+	}
+
+	// UnitID 1 は共有ストアに対応する
+	if v, _ := shared.ReadWord(AreaHoldingRegs, 0); v != 111 {
+		t.Errorf("shared store: expected 111, got %d", v)
+	}
+
+	// 個別ストアは共有ストアと同じサイズで作成される
+	for _, area := range handler.StoreForUnit(2).GetAreas() {
+		if area.Size != 10 {
+			t.Errorf("area %s: expected size 10, got %d", area.ID, area.Size)
+		}
+	}
+}
+
+func TestDataStoreHandler_PerUnitStore_Bits(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(10, 10, 10, 10))
+	handler.SetPerUnitStore(true)
+	req := NewDataStoreRequestHandler(handler)
+
+	if err := req.HandleWriteSingleCoil(&modbus.CoilsRequest{UnitId: 5, Addr: 3, Quantity: 1, IsWrite: true, Args: []bool{true}}); err != nil {
+		t.Fatalf("write coil failed: %v", err)
+	}
+	unit5, _ := req.HandleCoils(&modbus.CoilsRequest{UnitId: 5, Addr: 3, Quantity: 1})
+	unit1, _ := req.HandleCoils(&modbus.CoilsRequest{UnitId: 1, Addr: 3, Quantity: 1})
+	if !unit5[0] || unit1[0] {
+		t.Errorf("expected coil only on unit 5, got unit5=%v unit1=%v", unit5[0], unit1[0])
+	}
+}
+
+func TestModbusServer_PerUnitStore_KeptAcrossUpdateConfig(t *testing.T) {
+	cfg := DefaultTCPConfig()
+	cfg.PerUnitStore = true
+	srv := NewModbusServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err := srv.applyConfig(); err != nil {
+		t.Fatal(err)
+	}
+	srv.UnitDataStore(2).WriteWord(AreaHoldingRegs, 0, 42)
+
+	if err := srv.UpdateConfig(cfg.Clone()); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+	if !srv.handler.PerUnitStore() {
+		t.Error("expected per-unit mode to survive UpdateConfig")
+	}
+	if v, _ := srv.UnitDataStore(2).ReadWord(AreaHoldingRegs, 0); v != 42 {
+		t.Errorf("expected unit 2 value 42 after UpdateConfig, got %d", v)
+	}
+
+	// 設定で無効化すると全 UnitID が共有ストアに戻る
+	if err := srv.UpdateConfig(DefaultTCPConfig()); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+	if v, _ := srv.UnitDataStore(2).ReadWord(AreaHoldingRegs, 0); v != 0 {
+		t.Errorf("expected shared store value 0, got %d", v)
+	}
+
+	// 再度有効にすると値が復元される
+	if err := srv.UpdateConfig(cfg.Clone()); err != nil {
+		t.Fatalf("UpdateConfig failed: %v", err)
+	}
+	if v, _ := srv.UnitDataStore(2).ReadWord(AreaHoldingRegs, 0); v != 42 {
+		t.Errorf("expected unit 2 value 42 after re-enabling, got %d", v)
+	}
+}
+
+func TestModbusServerFactory_PerUnitStoreSetting(t *testing.T) {
+	f := NewModbusRTUServerFactory()
+	cfg, err := f.MapToConfig("", map[string]interface{}{"perUnitStore": "true"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	srv, err := f.CreateServer(cfg, NewModbusDataStore(10, 10, 10, 10))
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	if !srv.(*ModbusServer).handler.PerUnitStore() {
+		t.Error("expected perUnitStore setting to enable per-unit stores")
+	}
+	if got := f.ConfigToMap(cfg)["perUnitStore"]; got != "true" {
+		t.Errorf("ConfigToMap perUnitStore = %v, want true", got)
+	}
+}
+
+func TestDataStoreHandler_UnitIDRemap(t *testing.T) {
+	handler := NewDataStoreHandler(NewModbusDataStore(10, 10, 10, 10))
+	handler.SetPerUnitStore(true)
+	req := NewDataStoreRequestHandler(handler)
+
+	writeHolding(t, req, 1, 0, 111)
+	writeHolding(t, req, 5, 0, 555)
+
+	handler.SetUnitIDRemap(map[uint8]uint8{5: 1})
+	if got := readHolding(t, req, 5, 0); got != 111 {
+		t.Errorf("unit 5 remapped to 1: expected 111, got %d", got)
+	}
+	rtuVals, err := NewRTUDataStoreAdapter(handler).HandleReadHoldingRegisters(5, 0, 1)
+	if err != nil || rtuVals[0] != 111 {
+		t.Errorf("RTU unit 5 remapped to 1: got %v, %v", rtuVals, err)
+	}
+	if got := handler.UnitIDRemap(); len(got) != 1 || got[5] != 1 {
+		t.Errorf("UnitIDRemap = %v", got)
+	}
+
+	// 解除すると UnitID 5 自身のストアに戻る
+	handler.SetUnitIDRemap(nil)
+	if got := readHolding(t, req, 5, 0); got != 555 {
+		t.Errorf("unit 5 after clearing remap: expected 555, got %d", got)
+	}
+}
+
+func TestParseUnitIDRemap(t *testing.T) {
+	remap, err := parseUnitIDRemap(" 6=1, 5=1 ")
+	if err != nil {
+		t.Fatalf("parseUnitIDRemap failed: %v", err)
+	}
+	if got := formatUnitIDRemap(remap); got != "5=1,6=1" {
+		t.Errorf("formatUnitIDRemap = %q, want 5=1,6=1", got)
+	}
+	for _, v := range []string{"5", "5=256", "x=1", "5=-1"} {
+		if _, err := parseUnitIDRemap(v); err == nil {
+			t.Errorf("parseUnitIDRemap(%q) should fail", v)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package modbus
+
+import (
+	"fmt"
+	"sync"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+// wordSwapSet は読み書き時にワード（レジスタ）ペアを入れ替えるエリアの集合。
+// 32ビット値の上位/下位ワードの並びが逆のデバイスを模擬するために使用する。
+type wordSwapSet struct {
+	mu    sync.RWMutex
+	areas map[string]bool
+}
+
+func (w *wordSwapSet) set(areas []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.areas = make(map[string]bool, len(areas))
+	for _, a := range areas {
+		w.areas[a] = true
+	}
+}
+
+func (w *wordSwapSet) enabled(area string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.areas[area]
+}
+
+// apply は area のワード入れ替えが有効な場合に values を入れ替えたコピーを返す
+func (w *wordSwapSet) apply(area string, values []uint16) []uint16 {
+	if !w.enabled(area) {
+		return values
+	}
+	return swapWordPairs(values)
+}
+
+// swapWordPairs はリクエスト先頭から2ワードずつ組にして入れ替える。
+// 奇数長の場合、最後のワードはそのまま残す。
+func swapWordPairs(values []uint16) []uint16 {
+	result := make([]uint16, len(values))
+	copy(result, values)
+	for i := 0; i+1 < len(result); i += 2 {
+		result[i], result[i+1] = result[i+1], result[i]
+	}
+	return result
+}
+
+// parseWordSwapAreas は設定値をワード入れ替え対象のエリア一覧に変換する（ワードエリアのみ指定可能）
+func parseWordSwapAreas(v interface{}) ([]string, error) {
+	areas, err := parseVisibleAreas(v)
+	if err != nil {
+		return nil, fmt.Errorf("wordSwapAreas: %w", err)
+	}
+	if err := validateWordSwapAreas(areas); err != nil {
+		return nil, err
+	}
+	return areas, nil
+}
+
+func validateWordSwapAreas(areas []string) error {
+	for _, a := range areas {
+		if a != AreaHoldingRegs && a != AreaInputRegs {
+			return fmt.Errorf("%w: word swap is only supported for register areas: %s", datastore.ErrTypeMismatch, a)
+		}
+	}
+	return nil
+}
+
+// SetWordSwapAreas は Modbus TCP のレジスタ読み書きでワードペアを入れ替えるエリアを設定する
+func (h *DataStoreHandler) SetWordSwapAreas(areas []string) error {
+	normalized, err := normalizeVisibleAreas(areas)
+	if err != nil {
+		return err
+	}
+	if err := validateWordSwapAreas(normalized); err != nil {
+		return err
+	}
+	h.wordSwap.set(normalized)
+	return nil
+}
@@ -0,0 +1,96 @@
+package modbus
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/simonvetter/modbus"
+)
+
+func TestSwapWordPairs(t *testing.T) {
+	tests := []struct {
+		in, want []uint16
+	}{
+		{[]uint16{1}, []uint16{1}},
+		{[]uint16{1, 2, 3, 4}, []uint16{2, 1, 4, 3}},
+		{[]uint16{1, 2, 3}, []uint16{2, 1, 3}},
+	}
+	for _, tt := range tests {
+		in := append([]uint16(nil), tt.in...)
+		if got := swapWordPairs(in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("swapWordPairs(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+		if !reflect.DeepEqual(in, tt.in) {
+			t.Errorf("swapWordPairs modified input: %v", in)
+		}
+	}
+}
+
+func TestDataStoreRequestHandler_WordSwap(t *testing.T) {
+	store := NewModbusDataStore(10, 10, 10, 10)
+	_ = store.WriteWords(AreaHoldingRegs, 0, []uint16{0x1111, 0x2222, 0x3333, 0x4444, 0x5555})
+	_ = store.WriteWords(AreaInputRegs, 0, []uint16{0xAAAA, 0xBBBB})
+
+	srv := NewModbusServer(DefaultTCPConfig(), store)
+	req := NewDataStoreRequestHandler(srv.handler)
+	if err := srv.handler.SetWordSwapAreas([]string{AreaHoldingRegs}); err != nil {
+		t.Fatalf("SetWordSwapAreas: %v", err)
+	}
+
+	// 偶数長の読み取りはペアごとに入れ替わる
+	vals, err := req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 0, Quantity: 4})
+	if err != nil || !reflect.DeepEqual(vals, []uint16{0x2222, 0x1111, 0x4444, 0x3333}) {
+		t.Errorf("swapped read = %04X, %v", vals, err)
+	}
+
+	// 奇数長の読み取りは最後のワードがそのまま残る
+	vals, err = req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 2, Quantity: 3})
+	if err != nil || !reflect.DeepEqual(vals, []uint16{0x4444, 0x3333, 0x5555}) {
+		t.Errorf("odd-length read = %04X, %v", vals, err)
+	}
+
+	// 書き込みも入れ替えてから格納される
+	if _, err := req.HandleHoldingRegisters(&modbus.HoldingRegistersRequest{UnitId: 1, Addr: 6, Quantity: 2, IsWrite: true, Args: []uint16{0x0001, 0x0002}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got, _ := store.ReadWords(AreaHoldingRegs, 6, 2); !reflect.DeepEqual(got, []uint16{0x0002, 0x0001}) {
+		t.Errorf("stored after swapped write = %04X", got)
+	}
+
+	// 対象外のエリアは入れ替えない
+	vals, err = req.HandleInputRegisters(&modbus.InputRegistersRequest{UnitId: 1, Addr: 0, Quantity: 2})
+	if err != nil || !reflect.DeepEqual(vals, []uint16{0xAAAA, 0xBBBB}) {
+		t.Errorf("input registers = %04X, %v", vals, err)
+	}
+
+	if err := srv.handler.SetWordSwapAreas([]string{AreaCoils}); err == nil {
+		t.Error("expected error for bit area")
+	}
+}
+
+func TestModbusServerFactory_WordSwapAreasConfig(t *testing.T) {
+	f := NewModbusTCPServerFactory()
+	cfg, err := f.MapToConfig("", map[string]interface{}{"tcpPort": 1502, "wordSwapAreas": "inputRegisters"})
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if got := f.ConfigToMap(cfg)["wordSwapAreas"]; got != "inputRegisters" {
+		t.Errorf("ConfigToMap wordSwapAreas = %v", got)
+	}
+
+	store := NewModbusDataStore(10, 10, 10, 10)
+	_ = store.WriteWords(AreaInputRegs, 0, []uint16{1, 2})
+	srv, err := f.CreateServer(cfg, store)
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	req := NewDataStoreRequestHandler(srv.(*ModbusServer).handler)
+	vals, err := req.HandleInputRegisters(&modbus.InputRegistersRequest{UnitId: 1, Addr: 0, Quantity: 2})
+	if err != nil || !reflect.DeepEqual(vals, []uint16{2, 1}) {
+		t.Errorf("input registers = %v, %v", vals, err)
+	}
+
+	if _, err := f.MapToConfig("", map[string]interface{}{"wordSwapAreas": "coils"}); err == nil {
+		t.Error("expected error for bit area in config")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"google.golang.org/grpc"
 
 	"modbus_simulator/cmd/modbus-plugin/server"
+	"modbus_simulator/pkg/logging"
 )
 
 func main() {
@@ -18,6 +19,9 @@ func main() {
 	_ = flag.String("host-grpc-addr", "", "ホスト側 gRPC サーバーアドレス（Modbus プラグインでは未使用）")
 	flag.Parse()
 
+	// ホストが stderr を診断ログとして取り込めるよう JSON 形式で出力する
+	logging.UseJSON(os.Stderr)
+
 	fmt.Fprintln(os.Stderr, "Modbus Plugin starting... protocol-type="+*protocolType)
 	// ランダムな空きポートで gRPC サーバーを起動
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -12,6 +14,7 @@ import (
 
 	"modbus_simulator/internal/domain/protocol"
 	"modbus_simulator/cmd/modbus-plugin/internal/modbus"
+	"modbus_simulator/pkg/logging"
 )
 
 // PluginServer は Modbus プラグインの gRPC サーバー実装
@@ -22,8 +25,9 @@ type PluginServer struct {
 
 	mu           sync.Mutex
 	protocolType string // "modbus-tcp", "modbus-rtu", "modbus-ascii"
-	factory      protocol.ServerFactory
-	store        *modbus.ModbusDataStore
+	factory      *modbus.ModbusServerFactory
+	store        pluginStore
+	storeOptions string // store を作成した設定（storeOptionsOf の値）
 	server       protocol.ProtocolServer
 
 	// SubscribeChanges ストリームの購読者チャンネル
@@ -34,10 +38,16 @@ type PluginServer struct {
 	hostWriting bool
 }
 
+// pluginStore は PluginServer が公開するデータストア（ModbusDataStore またはメモリマップトストア）
+type pluginStore interface {
+	protocol.DataStore
+	SetChangeHook(hook modbus.DataChangeHook)
+}
+
 // NewPluginServer は PluginServer を作成する。
 // protocolType は "modbus-tcp", "modbus-rtu", "modbus-ascii" のいずれかを指定する。
 func NewPluginServer(protocolType string) *PluginServer {
-	var factory protocol.ServerFactory
+	var factory *modbus.ModbusServerFactory
 	switch protocolType {
 	case "modbus-rtu":
 		factory = modbus.NewModbusRTUServerFactory()
@@ -71,6 +81,7 @@ func (s *PluginServer) GetMetadata(ctx context.Context, _ *pb.Empty) (*pb.Plugin
 			UnitIdMax:              247,
 			SupportsNodePublishing: false,
 		},
+		TransportFields: s.factory.TransportFields(""),
 	}, nil
 }
 
@@ -195,13 +206,23 @@ func (s *PluginServer) CreateAndStart(ctx context.Context, req *pb.CreateAndStar
 		config = factory.CreateConfigFromVariant(variantID)
 	}
 
-	// DataStore を作成
-	innerStore := factory.CreateDataStore()
-	modbusStore, ok := innerStore.(*modbus.ModbusDataStore)
-	if !ok {
-		return nil, fmt.Errorf("DataStore の型が不正: %T", innerStore)
+	// DataStore を作成（mmapPath が設定されている場合はメモリマップトファイル）。
+	// データストアに関わる設定が変わらない場合は、サーバー追加後にホストが書き込んだ値（初期値・共有メモリ）を残すため
+	// 既存のデータストアを使い続ける。
+	if options := storeOptionsOf(config); s.store == nil || options != s.storeOptions {
+		innerStore, ok := factory.CreateDataStoreForConfig(config).(pluginStore)
+		if !ok {
+			return nil, fmt.Errorf("DataStore の型が不正: %T", innerStore)
+		}
+		if closer, ok := s.store.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				logging.Default().Warn("failed to close previous data store", "error", err)
+			}
+		}
+		s.store = innerStore
+		s.storeOptions = options
 	}
-	s.store = modbusStore
+	innerStore := s.store
 
 	// 変更フックを設定（Modbus クライアントの書き込みを SubscribeChanges ストリームに転送）
 	s.store.SetChangeHook(s.onDataChange)
@@ -221,6 +242,20 @@ func (s *PluginServer) CreateAndStart(ctx context.Context, req *pb.CreateAndStar
 	return &pb.Empty{}, nil
 }
 
+// storeOptionsOf は CreateDataStoreForConfig が作るデータストアを決める設定（メモリマップトファイルのパスとエリアの点数）を返す。
+// ヒープ上のデータストアはエリアの点数をサーバーが反映するため、mmapPath が空の場合は常に空文字列を返す。
+func storeOptionsOf(config protocol.ProtocolConfig) string {
+	mc, ok := config.(*modbus.ModbusConfig)
+	if !ok || mc.MmapPath == "" {
+		return ""
+	}
+	b, _ := json.Marshal(struct {
+		Path  string         `json:"path"`
+		Sizes map[string]int `json:"sizes,omitempty"`
+	}{mc.MmapPath, mc.AreaSizes})
+	return string(b)
+}
+
 func (s *PluginServer) Stop(ctx context.Context, _ *pb.Empty) (*pb.Empty, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -244,7 +279,13 @@ func (s *PluginServer) GetStatus(ctx context.Context, _ *pb.Empty) (*pb.StatusRe
 	case protocol.StatusStopped:
 		return &pb.StatusResponse{Status: "Stopped"}, nil
 	default:
-		return &pb.StatusResponse{Status: "Error"}, nil
+		resp := &pb.StatusResponse{Status: "Error"}
+		if les, ok := srv.(protocol.ErrorReporter); ok {
+			if err := les.LastError(); err != nil {
+				resp.ErrorMessage = err.Error()
+			}
+		}
+		return resp, nil
 	}
 }
 
@@ -253,7 +294,8 @@ func (s *PluginServer) UpdateConfig(ctx context.Context, req *pb.UpdateConfigReq
 	defer s.mu.Unlock()
 
 	if s.server == nil {
-		return nil, fmt.Errorf("サーバーが未起動")
+		// 未起動の場合は次の CreateAndStart で設定を受け取る
+		return &pb.Empty{}, nil
 	}
 
 	var settings map[string]interface{}
@@ -334,8 +376,206 @@ func (s *PluginServer) SetDisabledUnitIDs(ctx context.Context, req *pb.SetDisabl
 	return &pb.Empty{}, nil
 }
 
+// GetConnections は接続中のクライアント一覧を返す（サーバー未起動の場合は空）
+func (s *PluginServer) GetConnections(ctx context.Context, _ *pb.Empty) (*pb.GetConnectionsResponse, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	resp := &pb.GetConnectionsResponse{}
+	lister, ok := srv.(protocol.ClientLister)
+	if !ok {
+		return resp, nil
+	}
+	for _, c := range lister.GetClients() {
+		resp.Clients = append(resp.Clients, &pb.ClientConnection{
+			RemoteAddr:     c.RemoteAddr,
+			UnitId:         int32(c.UnitID),
+			LastActivityMs: c.LastActivity.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
+
+// GetAccessCounts は area のアドレスごとのマスターからの読み書き回数を返す（サーバー未起動の場合は空）
+func (s *PluginServer) GetAccessCounts(ctx context.Context, req *pb.GetAccessCountsRequest) (*pb.GetAccessCountsResponse, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	resp := &pb.GetAccessCountsResponse{}
+	counter, ok := srv.(protocol.AccessCounter)
+	if !ok {
+		return resp, nil
+	}
+	for _, c := range counter.GetAccessCounts(req.Area) {
+		resp.Counts = append(resp.Counts, &pb.AccessCount{Address: c.Address, Reads: c.Reads, Writes: c.Writes})
+	}
+	return resp, nil
+}
+
+// ResetAccessCounts はマスターからの読み書き回数を破棄する
+func (s *PluginServer) ResetAccessCounts(ctx context.Context, _ *pb.Empty) (*pb.Empty, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	if counter, ok := srv.(protocol.AccessCounter); ok {
+		counter.ResetAccessCounts()
+	}
+	return &pb.Empty{}, nil
+}
+
+// EnqueueFIFO は FC 0x18 で返すキューに値を追加する（キューはサーバーが持つため起動中のみ）
+func (s *PluginServer) EnqueueFIFO(ctx context.Context, req *pb.EnqueueFIFORequest) (*pb.Empty, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	if srv == nil {
+		return nil, fmt.Errorf("サーバーが未起動のため FIFO キューに追加できません")
+	}
+	queuer, ok := srv.(protocol.FIFOQueuer)
+	if !ok {
+		return nil, fmt.Errorf("FIFO キューに未対応")
+	}
+	if err := queuer.EnqueueFIFO(uint16(req.Pointer), uint16(req.Value)); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// commEventCounter は起動中のサーバーのイベントカウンタを返す
+func (s *PluginServer) commEventCounter() (protocol.CommEventCounter, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	if srv == nil {
+		return nil, fmt.Errorf("サーバーが未起動のためイベントカウンタにアクセスできません")
+	}
+	counter, ok := srv.(protocol.CommEventCounter)
+	if !ok {
+		return nil, fmt.Errorf("イベントカウンタに未対応")
+	}
+	return counter, nil
+}
+
+// GetCommEventCount は正常に応答したリクエスト数（FC 0x0B のイベントカウンタ）を返す
+func (s *PluginServer) GetCommEventCount(ctx context.Context, _ *pb.Empty) (*pb.GetCommEventCountResponse, error) {
+	counter, err := s.commEventCounter()
+	if err != nil {
+		return nil, err
+	}
+	n, err := counter.CommEventCount()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetCommEventCountResponse{Count: uint32(n)}, nil
+}
+
+// ClearCommEventCounter はイベントカウンタを 0 に戻す
+func (s *PluginServer) ClearCommEventCounter(ctx context.Context, _ *pb.Empty) (*pb.Empty, error) {
+	counter, err := s.commEventCounter()
+	if err != nil {
+		return nil, err
+	}
+	if err := counter.ClearCommEventCounter(); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// SetBusy は duration_ms ミリ秒の間、全リクエストに Slave Device Busy 例外を返すようにする（0 で解除）
+func (s *PluginServer) SetBusy(ctx context.Context, req *pb.SetBusyRequest) (*pb.Empty, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	if srv == nil {
+		return nil, fmt.Errorf("サーバーが未起動のためビジー応答を設定できません")
+	}
+	busy, ok := srv.(protocol.BusySimulator)
+	if !ok {
+		return nil, fmt.Errorf("ビジー応答に未対応")
+	}
+	if err := busy.SetBusy(time.Duration(req.DurationMs) * time.Millisecond); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// GetTrafficStats はサーバーが処理したリクエストの通信統計を返す（未起動・未対応の場合は 0）
+func (s *PluginServer) GetTrafficStats(ctx context.Context, _ *pb.Empty) (*pb.GetTrafficStatsResponse, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	reporter, ok := srv.(protocol.TrafficReporter)
+	if !ok {
+		return &pb.GetTrafficStatsResponse{}, nil
+	}
+	snap, err := reporter.TrafficStats()
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetTrafficStatsResponse{
+		Requests:         snap.Requests,
+		Exceptions:       snap.Exceptions,
+		LastFunctionCode: uint32(snap.LastFunctionCode),
+		Connections:      int32(snap.Connections),
+	}
+	if !snap.LastRequestAt.IsZero() {
+		resp.LastRequestAtMs = snap.LastRequestAt.UnixMilli()
+	}
+	return resp, nil
+}
+
+// GetUnitActivity は UnitID ごとの最終ポーリング時刻を返す（未起動・未対応の場合は空）
+func (s *PluginServer) GetUnitActivity(ctx context.Context, _ *pb.Empty) (*pb.GetUnitActivityResponse, error) {
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+
+	reporter, ok := srv.(protocol.UnitActivityReporter)
+	if !ok {
+		return &pb.GetUnitActivityResponse{}, nil
+	}
+	activity, err := reporter.UnitActivity()
+	if err != nil {
+		return nil, err
+	}
+	units := make([]*pb.UnitActivity, 0, len(activity))
+	for unitID, at := range activity {
+		units = append(units, &pb.UnitActivity{UnitId: uint32(unitID), LastActivityMs: at.UnixMilli()})
+	}
+	return &pb.GetUnitActivityResponse{Units: units}, nil
+}
+
 // ===== DataStoreService =====
 
+// storeForUnit は unitID のメモリを読み書きするデータストアを返す（0 の場合は共有のデータストア）
+func (s *PluginServer) storeForUnit(unitID uint32) (protocol.DataStore, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("DataStore 未初期化")
+	}
+	if unitID == 0 {
+		return s.store, nil
+	}
+
+	s.mu.Lock()
+	srv := s.server
+	s.mu.Unlock()
+	if srv == nil {
+		return nil, fmt.Errorf("サーバーが未起動のため UnitID %d のメモリにアクセスできません", unitID)
+	}
+	us, ok := srv.(protocol.UnitMemoryAccessor)
+	if !ok {
+		return nil, fmt.Errorf("UnitID 別メモリに未対応")
+	}
+	return us.UnitDataStore(uint8(unitID)), nil
+}
+
 func (s *PluginServer) GetAreas(ctx context.Context, _ *pb.Empty) (*pb.GetAreasResponse, error) {
 	if s.store == nil {
 		return &pb.GetAreasResponse{}, nil
@@ -357,10 +597,11 @@ func (s *PluginServer) GetAreas(ctx context.Context, _ *pb.Empty) (*pb.GetAreasR
 }
 
 func (s *PluginServer) ReadBit(ctx context.Context, req *pb.ReadBitRequest) (*pb.ReadBitResponse, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
-	v, err := s.store.ReadBit(req.Area, req.Address)
+	v, err := store.ReadBit(req.Area, req.Address)
 	if err != nil {
 		return nil, err
 	}
@@ -368,21 +609,23 @@ func (s *PluginServer) ReadBit(ctx context.Context, req *pb.ReadBitRequest) (*pb
 }
 
 func (s *PluginServer) WriteBit(ctx context.Context, req *pb.WriteBitRequest) (*pb.Empty, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
 	// ホストからの書き込みフラグを立てて循環通知を防止
 	s.setHostWriting(true)
-	err := s.store.WriteBit(req.Area, req.Address, req.Value)
+	err = store.WriteBit(req.Area, req.Address, req.Value)
 	s.setHostWriting(false)
 	return &pb.Empty{}, err
 }
 
 func (s *PluginServer) ReadBits(ctx context.Context, req *pb.ReadBitsRequest) (*pb.ReadBitsResponse, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
-	vals, err := s.store.ReadBits(req.Area, req.Address, uint16(req.Count))
+	vals, err := store.ReadBits(req.Area, req.Address, uint16(req.Count))
 	if err != nil {
 		return nil, err
 	}
@@ -390,20 +633,22 @@ func (s *PluginServer) ReadBits(ctx context.Context, req *pb.ReadBitsRequest) (*
 }
 
 func (s *PluginServer) WriteBits(ctx context.Context, req *pb.WriteBitsRequest) (*pb.Empty, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
 	s.setHostWriting(true)
-	err := s.store.WriteBits(req.Area, req.Address, req.Values)
+	err = store.WriteBits(req.Area, req.Address, req.Values)
 	s.setHostWriting(false)
 	return &pb.Empty{}, err
 }
 
 func (s *PluginServer) ReadWord(ctx context.Context, req *pb.ReadWordRequest) (*pb.ReadWordResponse, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
-	v, err := s.store.ReadWord(req.Area, req.Address)
+	v, err := store.ReadWord(req.Area, req.Address)
 	if err != nil {
 		return nil, err
 	}
@@ -411,20 +656,22 @@ func (s *PluginServer) ReadWord(ctx context.Context, req *pb.ReadWordRequest) (*
 }
 
 func (s *PluginServer) WriteWord(ctx context.Context, req *pb.WriteWordRequest) (*pb.Empty, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
 	s.setHostWriting(true)
-	err := s.store.WriteWord(req.Area, req.Address, uint16(req.Value))
+	err = store.WriteWord(req.Area, req.Address, uint16(req.Value))
 	s.setHostWriting(false)
 	return &pb.Empty{}, err
 }
 
 func (s *PluginServer) ReadWords(ctx context.Context, req *pb.ReadWordsRequest) (*pb.ReadWordsResponse, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
-	vals, err := s.store.ReadWords(req.Area, req.Address, uint16(req.Count))
+	vals, err := store.ReadWords(req.Area, req.Address, uint16(req.Count))
 	if err != nil {
 		return nil, err
 	}
@@ -436,15 +683,16 @@ func (s *PluginServer) ReadWords(ctx context.Context, req *pb.ReadWordsRequest)
 }
 
 func (s *PluginServer) WriteWords(ctx context.Context, req *pb.WriteWordsRequest) (*pb.Empty, error) {
-	if s.store == nil {
-		return nil, fmt.Errorf("DataStore 未初期化")
+	store, err := s.storeForUnit(req.UnitId)
+	if err != nil {
+		return nil, err
 	}
 	vals := make([]uint16, len(req.Values))
 	for i, v := range req.Values {
 		vals[i] = uint16(v)
 	}
 	s.setHostWriting(true)
-	err := s.store.WriteWords(req.Area, req.Address, vals)
+	err = store.WriteWords(req.Area, req.Address, vals)
 	s.setHostWriting(false)
 	return &pb.Empty{}, err
 }
@@ -0,0 +1,630 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/simonvetter/modbus"
+	"google.golang.org/grpc"
+
+	"modbus_simulator/internal/application"
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/infrastructure/plugin"
+	"modbus_simulator/internal/testutil"
+)
+
+// newRemoteFactory はテストプロセス内で PluginServer の gRPC サーバーを起動し、
+// ホストと同じ LazyRemoteServerFactory（debug_port で既存プロセスに接続）を返す
+func newRemoteFactory(t *testing.T, protocolType string) *plugin.LazyRemoteServerFactory {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	pluginSrv := NewPluginServer(protocolType)
+	pluginSrv.Register(grpcServer)
+	go grpcServer.Serve(lis)
+
+	factory := plugin.NewLazyRemoteServerFactory(&plugin.PluginManifestEntry{
+		Manifest: &plugin.PluginManifest{
+			Name:         protocolType,
+			ProtocolType: protocolType,
+			DebugPort:    lis.Addr().(*net.TCPAddr).Port,
+		},
+	}, plugin.NewPluginProcessManager(""))
+	if err := factory.EnsureStarted(); err != nil {
+		t.Fatalf("EnsureStarted: %v", err)
+	}
+	t.Cleanup(func() {
+		pluginSrv.Stop(context.Background(), nil)
+		factory.StopProcess()
+		grpcServer.Stop()
+	})
+	return factory
+}
+
+// startRemoteServer は settings で Modbus TCP サーバーをプラグイン側で起動する
+func startRemoteServer(t *testing.T, factory *plugin.LazyRemoteServerFactory, settings map[string]interface{}) (protocol.ProtocolServer, protocol.DataStore) {
+	t.Helper()
+	if _, ok := settings["tcpAddress"]; !ok {
+		settings["tcpAddress"] = "127.0.0.1"
+	}
+	if _, ok := settings["tcpPort"]; !ok {
+		settings["tcpPort"] = testutil.FreeTCPPort(t)
+	}
+	cfg, err := factory.MapToConfig("", settings)
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	store := factory.CreateDataStore()
+	srv, err := factory.CreateServer(cfg, store)
+	if err != nil {
+		t.Fatalf("CreateServer: %v", err)
+	}
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return srv, store
+}
+
+func TestRemoteProtocolServer_UnitDataStore(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+	srv, shared := startRemoteServer(t, factory, map[string]interface{}{"perUnitStore": "true"})
+
+	us, ok := srv.(protocol.UnitMemoryAccessor)
+	if !ok {
+		t.Fatal("remote server should implement UnitMemoryAccessor")
+	}
+	if err := us.UnitDataStore(5).WriteWord("holdingRegisters", 3, 42); err != nil {
+		t.Fatalf("WriteWord(unit 5): %v", err)
+	}
+	if v, err := us.UnitDataStore(5).ReadWord("holdingRegisters", 3); err != nil || v != 42 {
+		t.Errorf("unit 5 ReadWord = %d, %v; want 42", v, err)
+	}
+	if v, _ := shared.ReadWord("holdingRegisters", 3); v != 0 {
+		t.Errorf("shared ReadWord = %d, want 0 (unit 5 has its own memory)", v)
+	}
+
+	// UnitID 1 は共有メモリ
+	if err := us.UnitDataStore(1).WriteBit("coils", 2, true); err != nil {
+		t.Fatalf("WriteBit(unit 1): %v", err)
+	}
+	if v, _ := shared.ReadBit("coils", 2); !v {
+		t.Error("unit 1 should write to the shared memory")
+	}
+}
+
+func TestRemoteProtocolServer_GetClients(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+	port := testutil.FreeTCPPort(t)
+	srv, _ := startRemoteServer(t, factory, map[string]interface{}{"tcpPort": port})
+
+	lister, ok := srv.(protocol.ClientLister)
+	if !ok {
+		t.Fatal("remote server should implement ClientLister")
+	}
+	if got := lister.GetClients(); len(got) != 0 {
+		t.Errorf("GetClients before connecting = %+v", got)
+	}
+
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	client.SetUnitId(3)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatal(err)
+	}
+	got := lister.GetClients()
+	if len(got) != 1 || got[0].UnitID != 3 || got[0].RemoteAddr == "" || got[0].LastActivity.IsZero() {
+		t.Errorf("GetClients = %+v, want one client with unit 3", got)
+	}
+}
+
+func TestRemoteProtocolServer_AccessCounts(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+	port := testutil.FreeTCPPort(t)
+	srv, _ := startRemoteServer(t, factory, map[string]interface{}{"tcpPort": port})
+
+	counter, ok := srv.(protocol.AccessCounter)
+	if !ok {
+		t.Fatal("remote server should implement AccessCounter")
+	}
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	for i := 0; i < 2; i++ {
+		if _, err := client.ReadRegister(4, modbus.HOLDING_REGISTER); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.WriteRegister(4, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	got := counter.GetAccessCounts("holdingRegisters")
+	if len(got) != 1 || got[0] != (protocol.AccessCount{Address: 4, Reads: 2, Writes: 1}) {
+		t.Errorf("GetAccessCounts = %+v, want address 4 with 2 reads and 1 write", got)
+	}
+	counter.ResetAccessCounts()
+	if got := counter.GetAccessCounts("holdingRegisters"); len(got) != 0 {
+		t.Errorf("GetAccessCounts after reset = %+v", got)
+	}
+}
+
+func TestRemoteProtocolServer_EnqueueFIFO(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+
+	// キューはプラグイン側のサーバーが持つため、起動前は追加できない
+	stopped, err := factory.CreateServer(factory.CreateConfigFromVariant(""), factory.CreateDataStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stopped.(protocol.FIFOQueuer).EnqueueFIFO(100, 1); err == nil {
+		t.Error("EnqueueFIFO before start should fail")
+	}
+
+	port := testutil.FreeTCPPort(t)
+	srv, _ := startRemoteServer(t, factory, map[string]interface{}{"tcpPort": port, "nativeTCP": "true"})
+	queuer, ok := srv.(protocol.FIFOQueuer)
+	if !ok {
+		t.Fatal("remote server should implement FIFOQueuer")
+	}
+	if err := queuer.EnqueueFIFO(100, 0x1234); err != nil {
+		t.Fatalf("EnqueueFIFO: %v", err)
+	}
+
+	// FC 0x18 (Read FIFO Queue) で追加した値が返る
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Write([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x04, 0x01, 0x18, 0x00, 0x64}); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 14)
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x08, 0x01, 0x18, 0x00, 0x04, 0x00, 0x01, 0x12, 0x34}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response = % X, want % X", got, want)
+	}
+}
+
+func TestRemoteProtocolServer_CommEventCounter(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+	port := testutil.FreeTCPPort(t)
+	srv, _ := startRemoteServer(t, factory, map[string]interface{}{"tcpPort": port})
+
+	counter, ok := srv.(protocol.CommEventCounter)
+	if !ok {
+		t.Fatal("remote server should implement CommEventCounter")
+	}
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	for i := 0; i < 2; i++ {
+		if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := counter.CommEventCount(); err != nil || n != 2 {
+		t.Errorf("CommEventCount = %d, %v, want 2", n, err)
+	}
+	if err := counter.ClearCommEventCounter(); err != nil {
+		t.Fatalf("ClearCommEventCounter: %v", err)
+	}
+	if n, err := counter.CommEventCount(); err != nil || n != 0 {
+		t.Errorf("CommEventCount after clear = %d, %v", n, err)
+	}
+}
+
+func TestRemoteProtocolServer_SetBusy(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+	port := testutil.FreeTCPPort(t)
+	srv, _ := startRemoteServer(t, factory, map[string]interface{}{"tcpPort": port})
+
+	busy, ok := srv.(protocol.BusySimulator)
+	if !ok {
+		t.Fatal("remote server should implement BusySimulator")
+	}
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	if err := busy.SetBusy(time.Hour); err != nil {
+		t.Fatalf("SetBusy: %v", err)
+	}
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); !errors.Is(err, modbus.ErrServerDeviceBusy) {
+		t.Fatalf("read during busy window: err = %v, want ErrServerDeviceBusy", err)
+	}
+	if err := busy.SetBusy(0); err != nil {
+		t.Fatalf("SetBusy(0): %v", err)
+	}
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Errorf("read after clearing busy: %v", err)
+	}
+}
+
+func TestLazyRemoteServerFactory_TransportFieldsFromMetadata(t *testing.T) {
+	tests := []struct {
+		protocolType string
+		want         []string
+	}{
+		{"modbus-tcp", []string{"tcpAddress", "tcpPort", "fallbackPort", "nativeTCP", "mmapPath"}},
+		{"modbus-rtu", []string{"serialPort", "baudRate", "dataBits", "stopBits", "parity", "mmapPath"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocolType, func(t *testing.T) {
+			// plugin.json には一覧を書かず、プラグインの GetMetadata から取得する
+			factory := newRemoteFactory(t, tt.protocolType)
+			if got := factory.TransportFields(""); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TransportFields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteProtocolServer_AreaSizesWhileStopped(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+	srv, store := startRemoteServer(t, factory, map[string]interface{}{})
+	if err := srv.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	settings := factory.ConfigToMap(srv.Config())
+	settings["areaSizes"] = "holdingRegisters=100"
+	cfg, err := factory.MapToConfig("", settings)
+	if err != nil {
+		t.Fatalf("MapToConfig: %v", err)
+	}
+	if err := srv.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	for _, a := range store.GetAreas() {
+		if a.ID == "holdingRegisters" && a.Size != 100 {
+			t.Errorf("holdingRegisters size = %d, want 100", a.Size)
+		}
+	}
+
+	// 再起動後も設定の点数が使われる
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := store.ReadWord("holdingRegisters", 100); err == nil {
+		t.Error("address 100 should be out of range after restart")
+	}
+}
+
+func TestRemoteProtocolServer_MmapStoreKeepsValuesAcrossRestart(t *testing.T) {
+	factory := newRemoteFactory(t, "modbus-tcp")
+	srv, store := startRemoteServer(t, factory, map[string]interface{}{
+		"mmapPath": filepath.Join(t.TempDir(), "modbus.mem"),
+	})
+	if err := store.WriteWord("holdingRegisters", 7, 1234); err != nil {
+		t.Fatalf("WriteWord: %v", err)
+	}
+
+	// 再起動（CreateAndStart）でデータストアを作り直してもファイルの値が残る
+	if err := srv.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if v, err := store.ReadWord("holdingRegisters", 7); err != nil || v != 1234 {
+		t.Errorf("ReadWord after restart = %d, %v; want 1234", v, err)
+	}
+}
+
+// TestPLCService_StartServer_PrivilegedPortFallback はホストの StartServer から
+// プラグインの代替ポート起動までを通しで確認する（ホスト側のポート確認で止まらないこと）
+func TestPLCService_StartServer_PrivilegedPortFallback(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("root 以外の Unix でのみ特権ポートのバインドが拒否される")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:502")
+	if err == nil {
+		ln.Close()
+		t.Skip("この環境では特権ポートにバインドできる")
+	}
+	if !errors.Is(err, os.ErrPermission) {
+		t.Skipf("ポート 502 を確認できない: %v", err)
+	}
+
+	svc := application.NewPLCServiceWithConfigDir(t.TempDir())
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-tcp"))
+	if err := svc.AddServer("modbus-tcp", "tcp"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = svc.StopServer("modbus-tcp") })
+
+	fallback := testutil.FreeTCPPort(t)
+	cfg := svc.GetServerConfig("modbus-tcp")
+	cfg.Settings["tcpAddress"] = "127.0.0.1"
+	cfg.Settings["tcpPort"] = 502
+	cfg.Settings["fallbackPort"] = fallback
+	if err := svc.UpdateServerConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatalf("StartServer: %v", err)
+	}
+	if got := svc.GetServerStatus("modbus-tcp"); got != "Running" {
+		t.Errorf("status = %s, want Running", got)
+	}
+	client := testutil.DialModbusTCP(t, fallback, time.Second)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Errorf("read via fallback port: %v", err)
+	}
+}
+
+// TestPLCService_SelfTest_KeepsPluginMemory はプラグインのサーバーで自己診断をしても
+// サーバーの状態と設定、メモリがそのまま残ることを確認する（停止中・実行中の両方）
+func TestPLCService_SelfTest_KeepsPluginMemory(t *testing.T) {
+	svc := application.NewPLCServiceWithConfigDir(t.TempDir())
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-tcp"))
+	if err := svc.AddServer("modbus-tcp", "tcp"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = svc.StopServer("modbus-tcp") })
+
+	cfg := svc.GetServerConfig("modbus-tcp")
+	cfg.Settings["tcpAddress"] = "127.0.0.1"
+	cfg.Settings["tcpPort"] = testutil.FreeTCPPort(t)
+	if err := svc.UpdateServerConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	check := func(wantStatus string) {
+		t.Helper()
+		if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 9998, 1234); err != nil {
+			t.Fatal(err)
+		}
+		if report := svc.SelfTest(); !report.Passed {
+			t.Fatalf("self-test failed: %+v", report.Checks)
+		}
+		if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 9998, 2); words[0] != 1234 || words[1] != 0 {
+			t.Errorf("memory after self-test = %v, want [1234 0]", words)
+		}
+		if got := svc.GetServerStatus("modbus-tcp"); got != wantStatus {
+			t.Errorf("server status = %s, want %s", got, wantStatus)
+		}
+		if got := svc.GetServerConfig("modbus-tcp").Settings["tcpPort"]; fmt.Sprint(got) != fmt.Sprint(cfg.Settings["tcpPort"]) {
+			t.Errorf("tcpPort after self-test = %v, want %v", got, cfg.Settings["tcpPort"])
+		}
+	}
+	check("Stopped")
+
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	check("Running")
+}
+
+// startPluginService はプラグインの Modbus TCP サーバーを持つ PLCService を作成し、
+// settings を反映して 127.0.0.1 の空きポートで起動する。起動したポートを返す。
+func startPluginService(t *testing.T, settings map[string]interface{}) (*application.PLCService, int) {
+	t.Helper()
+	svc := application.NewPLCServiceWithConfigDir(t.TempDir())
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-tcp"))
+	if err := svc.AddServer("modbus-tcp", "tcp"); err != nil {
+		t.Fatal(err)
+	}
+	return svc, startAddedPluginServer(t, svc, settings)
+}
+
+// startAddedPluginServer は追加済みの modbus-tcp を settings と空いているポートで起動し、ポート番号を返す
+func startAddedPluginServer(t *testing.T, svc *application.PLCService, settings map[string]interface{}) int {
+	t.Helper()
+	t.Cleanup(func() { _ = svc.StopServer("modbus-tcp") })
+
+	port := testutil.FreeTCPPort(t)
+	cfg := svc.GetServerConfig("modbus-tcp")
+	for k, v := range settings {
+		cfg.Settings[k] = v
+	}
+	cfg.Settings["tcpAddress"] = "127.0.0.1"
+	cfg.Settings["tcpPort"] = port
+	if err := svc.UpdateServerConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	return port
+}
+
+func TestPLCService_SetUnitIDRemap_ThroughPlugin(t *testing.T) {
+	svc, port := startPluginService(t, nil)
+	if err := svc.SetPerUnitStore("modbus-tcp", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetUnitIDRemap("modbus-tcp", map[int]int{5: 1}); err != nil {
+		t.Fatalf("SetUnitIDRemap: %v", err)
+	}
+	if err := svc.WriteWordForUnit("modbus-tcp", 1, "holdingRegisters", 0, 111); err != nil {
+		t.Fatal(err)
+	}
+
+	// UnitID 5 へのリクエストは UnitID 1 のメモリで処理される
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	client.SetUnitId(5)
+	if v, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil || v != 111 {
+		t.Errorf("unit 5 read = %d, %v, want 111", v, err)
+	}
+	client.SetUnitId(6)
+	if v, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil || v != 0 {
+		t.Errorf("unit 6 read = %d, %v, want 0", v, err)
+	}
+	if got := svc.GetUnitIDRemap("modbus-tcp"); len(got) != 1 || got[5] != 1 {
+		t.Errorf("GetUnitIDRemap = %v", got)
+	}
+}
+
+func TestPLCService_ScriptStats_ThroughPlugin(t *testing.T) {
+	svc, port := startPluginService(t, map[string]interface{}{"areaSizes": "holdingRegisters=10"})
+
+	// 正常応答1件と、範囲外アドレスへの例外応答1件
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatalf("ReadRegister: %v", err)
+	}
+	if _, err := client.ReadRegister(100, modbus.HOLDING_REGISTER); err == nil {
+		t.Fatal("read beyond the area should fail")
+	}
+
+	result, err := svc.RunScriptOnce(`var s = plc.stats(); [s.requests, s.exceptions, s.lastFunctionCode, s.connections, s.lastRequestAt > 0]`)
+	if err != nil {
+		t.Fatalf("RunScriptOnce: %v", err)
+	}
+	got, ok := result.([]interface{})
+	if !ok || len(got) != 5 {
+		t.Fatalf("stats = %v (%T)", result, result)
+	}
+	want := []interface{}{int64(2), int64(1), int64(3), int64(1), true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stats[%d] = %v (%T), want %v", i, got[i], got[i], want[i])
+		}
+	}
+}
+
+func TestPLCService_GetUnitActivity_ThroughPlugin(t *testing.T) {
+	svc, port := startPluginService(t, nil)
+	if got := svc.GetUnitActivity(); len(got) != 0 {
+		t.Fatalf("GetUnitActivity before polling = %v", got)
+	}
+
+	before := time.Now().Add(-time.Second) // プラグインからはミリ秒単位で返る
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	for _, unit := range []uint8{3, 7} {
+		client.SetUnitId(unit)
+		if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+			t.Fatalf("unit %d read: %v", unit, err)
+		}
+	}
+
+	activity := svc.GetUnitActivity()
+	if len(activity) != 2 {
+		t.Fatalf("GetUnitActivity = %v, want units 3 and 7", activity)
+	}
+	for _, unit := range []int{3, 7} {
+		if at, ok := activity[unit]; !ok || at.Before(before) {
+			t.Errorf("unit %d activity = %v (ok=%v)", unit, at, ok)
+		}
+	}
+}
+
+func TestPLCService_SetConnectionTimeout_ThroughPlugin(t *testing.T) {
+	svc, port := startPluginService(t, nil)
+	if err := svc.SetConnectionTimeout(100); err != nil {
+		t.Fatal(err)
+	}
+
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	client.SetUnitId(3)
+	if _, err := client.ReadRegister(0, modbus.HOLDING_REGISTER); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.GetUnitActivity(); len(got) != 1 {
+		t.Fatalf("GetUnitActivity after polling = %v, want unit 3", got)
+	}
+
+	// プラグインのセッションも短くしたタイムアウトで外れる
+	deadline := time.Now().Add(2 * time.Second)
+	for len(svc.GetUnitActivity()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("unit still counted after the timeout: %v", svc.GetUnitActivity())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestPLCService_StartAfterAdd_KeepsInitialState_ThroughPlugin(t *testing.T) {
+	initial := filepath.Join(t.TempDir(), "initial.txt")
+	if err := os.WriteFile(initial, []byte("modbus-tcp holdingRegisters 0 1234 0056\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	svc := application.NewPLCServiceWithConfigDir(t.TempDir())
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-tcp"))
+	if err := svc.SetInitialStateFile(initial); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddServer("modbus-tcp", "tcp"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 追加時に書き込んだ初期値が起動後も残っている
+	port := startAddedPluginServer(t, svc, nil)
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	if got, err := client.ReadRegisters(0, 2, modbus.HOLDING_REGISTER); err != nil || !reflect.DeepEqual(got, []uint16{0x1234, 0x56}) {
+		t.Errorf("holdingRegisters[0:2] after start = %v (err=%v)", got, err)
+	}
+
+	// 停止・再起動でもメモリの内容を残す
+	if err := svc.StopServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	if words, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 2); err != nil || !reflect.DeepEqual(words, []int{0x1234, 0x56}) {
+		t.Errorf("holdingRegisters[0:2] after restart = %v (err=%v)", words, err)
+	}
+}
+
+func TestPLCService_StartAfterAdd_KeepsSharedMemoryCopy_ThroughPlugin(t *testing.T) {
+	svc := application.NewPLCServiceWithConfigDir(t.TempDir())
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-rtu"))
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-tcp"))
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-rtu", "holdingRegisters", 2, 77); err != nil {
+		t.Fatal(err)
+	}
+
+	// 共有メモリで先に追加したサーバーの値が、後から追加したサーバーへコピーされ、起動後も残っている
+	svc.SetSharedMemory(true)
+	if err := svc.AddServer("modbus-tcp", "tcp"); err != nil {
+		t.Fatal(err)
+	}
+	port := startAddedPluginServer(t, svc, nil)
+	client := testutil.DialModbusTCP(t, port, time.Second)
+	if v, err := client.ReadRegister(2, modbus.HOLDING_REGISTER); err != nil || v != 77 {
+		t.Errorf("holdingRegisters[2] after start = %d (err=%v), want 77", v, err)
+	}
+}
+
+func TestPLCService_WriteForUnit_MirrorsSharedMemory_ThroughPlugin(t *testing.T) {
+	svc := application.NewPLCServiceWithConfigDir(t.TempDir())
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-rtu"))
+	svc.RegisterPluginFactory(newRemoteFactory(t, "modbus-tcp"))
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddServer("modbus-tcp", "tcp"); err != nil {
+		t.Fatal(err)
+	}
+	svc.SetSharedMemory(true)
+
+	// UnitID 1 のメモリは共有のデータストアなので、他のサーバーへも反映される
+	if err := svc.WriteWordForUnit("modbus-tcp", 1, "holdingRegisters", 3, 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteBitForUnit("modbus-tcp", 1, "coils", 5, true); err != nil {
+		t.Fatal(err)
+	}
+	if words, err := svc.ReadWords("modbus-rtu", "holdingRegisters", 3, 1); err != nil || words[0] != 42 {
+		t.Errorf("modbus-rtu holdingRegisters[3] = %v (err=%v), want 42", words, err)
+	}
+	if bits, err := svc.ReadBits("modbus-rtu", "coils", 5, 1); err != nil || !bits[0] {
+		t.Errorf("modbus-rtu coils[5] = %v (err=%v), want true", bits, err)
+	}
+}
@@ -11,6 +11,9 @@ func newOpcuaDataStore() *OpcuaDataStore {
 }
 
 func (d *OpcuaDataStore) GetAreas() []protocol.MemoryArea        { return nil }
+func (d *OpcuaDataStore) GetArea(id string) (protocol.MemoryArea, bool) {
+	return protocol.MemoryArea{}, false
+}
 func (d *OpcuaDataStore) ReadBit(area string, address uint32) (bool, error) {
 	return false, nil
 }
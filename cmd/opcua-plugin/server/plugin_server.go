@@ -255,7 +255,8 @@ func (s *PluginServer) UpdateConfig(ctx context.Context, req *pb.UpdateConfigReq
 	defer s.mu.Unlock()
 
 	if s.server == nil {
-		return nil, fmt.Errorf("サーバーが未起動")
+		// 未起動の場合は次の CreateAndStart で設定を受け取る
+		return &pb.Empty{}, nil
 	}
 
 	var settings map[string]interface{}
@@ -0,0 +1,195 @@
+// plcsim は GUI なしでシミュレータを起動するヘッドレス CLI。
+// プロジェクト JSON を読み込み、サーバーとスクリプトを起動して SIGINT/SIGTERM まで待機する。
+//
+//	plcsim -project project.json [-plugins DIR] [-protocol modbus-tcp] [-port 1502]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"modbus_simulator/internal/application"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "plcsim: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// options はコマンドライン引数
+type options struct {
+	projectPath string
+	pluginsDir  string
+	protocol    string
+	port        int
+	noScripts   bool
+	skipCheck   bool
+}
+
+func parseFlags(args []string, output io.Writer) (*options, error) {
+	fs := flag.NewFlagSet("plcsim", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	opts := &options{}
+	fs.StringVar(&opts.projectPath, "project", "", "プロジェクト JSON ファイルのパス（必須）")
+	fs.StringVar(&opts.pluginsDir, "plugins", "", "プラグインディレクトリ（省略時は実行ファイル横またはカレントの plugins）")
+	fs.StringVar(&opts.protocol, "protocol", "", "起動するプロトコル（省略時はプロジェクト内の全サーバー）")
+	fs.IntVar(&opts.port, "port", 0, "TCP ポートの上書き（-protocol で指定したサーバー、または唯一のサーバーに適用）")
+	fs.BoolVar(&opts.noScripts, "no-scripts", false, "有効なスクリプトを自動開始しない")
+	fs.BoolVar(&opts.skipCheck, "skip-checksum", false, "プロジェクトファイルのチェックサムを検証しない")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if opts.projectPath == "" {
+		fs.Usage()
+		return nil, fmt.Errorf("-project を指定してください")
+	}
+	if opts.port < 0 || opts.port > 65535 {
+		return nil, fmt.Errorf("ポート番号が範囲外です: %d", opts.port)
+	}
+	if opts.pluginsDir == "" {
+		opts.pluginsDir = pluginsDirectory()
+	}
+	return opts, nil
+}
+
+// run はシミュレータを起動し、ctx がキャンセルされるまでブロックする
+func run(ctx context.Context, args []string, output io.Writer) error {
+	opts, err := parseFlags(args, output)
+	if err != nil {
+		return err
+	}
+
+	project, err := loadProject(opts.projectPath)
+	if err != nil {
+		return err
+	}
+
+	// ヘッドレス実行ではユーザー設定ディレクトリのモニタリング設定・レシピを読み書きしない
+	svc := application.NewPLCServiceWithConfigDir("")
+	defer svc.Shutdown()
+	logger := svc.Logger()
+
+	if _, err := svc.StartHostGrpcServer(); err != nil {
+		return err
+	}
+	if err := svc.InitPlugins(opts.pluginsDir); err != nil {
+		return err
+	}
+	if err := svc.ImportProjectJSON(project, application.ImportOptions{
+		SkipChecksum: opts.skipCheck,
+		SkipScripts:  opts.noScripts,
+	}); err != nil {
+		return fmt.Errorf("プロジェクトの読み込みに失敗しました: %w", err)
+	}
+
+	targets, err := selectServers(svc, opts.protocol)
+	if err != nil {
+		return err
+	}
+	if opts.port != 0 {
+		if len(targets) != 1 {
+			return fmt.Errorf("-port を使う場合は -protocol でサーバーを1つに絞ってください")
+		}
+		if err := overridePort(svc, targets[0], opts.port); err != nil {
+			return err
+		}
+	}
+
+	for _, pt := range targets {
+		if err := svc.StartServer(pt); err != nil {
+			return fmt.Errorf("%s: %w", pt, err)
+		}
+		logger.Info("server started", "protocol", pt)
+	}
+
+	<-ctx.Done()
+	logger.Info("shutting down")
+	return nil
+}
+
+// loadProject はプロジェクト JSON ファイルを読み込む
+// （チェックサムはファイルの内容のまま検証するため、デコードは ImportProjectJSON で行う）
+func loadProject(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var project application.ProjectDataDTO
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
+
+// selectServers は起動対象のプロトコル一覧を返す。
+// protocolType がプロジェクトに含まれない場合はデフォルト設定で追加する。
+func selectServers(svc *application.PLCService, protocolType string) ([]string, error) {
+	instances := svc.GetServerInstances()
+	if protocolType == "" {
+		if len(instances) == 0 {
+			return nil, fmt.Errorf("プロジェクトにサーバーがありません（-protocol で指定してください）")
+		}
+		targets := make([]string, len(instances))
+		for i, inst := range instances {
+			targets[i] = inst.ProtocolType
+		}
+		return targets, nil
+	}
+
+	for _, inst := range instances {
+		if inst.ProtocolType == protocolType {
+			return []string{protocolType}, nil
+		}
+	}
+	if err := svc.AddServer(protocolType, ""); err != nil {
+		return nil, err
+	}
+	return []string{protocolType}, nil
+}
+
+// overridePort はサーバー設定の tcpPort を上書きする
+func overridePort(svc *application.PLCService, protocolType string, port int) error {
+	cfg := svc.GetServerConfig(protocolType)
+	if cfg == nil {
+		return fmt.Errorf("server not found: %s", protocolType)
+	}
+	if _, ok := cfg.Settings["tcpPort"]; !ok {
+		return fmt.Errorf("%s は TCP ポートの設定を持ちません", protocolType)
+	}
+	cfg.Settings["tcpPort"] = port
+	return svc.UpdateServerConfig(cfg)
+}
+
+// pluginsDirectory は実行ファイルと同じディレクトリの plugins を優先し、
+// 存在しない場合はカレントディレクトリの plugins を返す
+func pluginsDirectory() string {
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Join(filepath.Dir(exe), "plugins")
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	abs, err := filepath.Abs("plugins")
+	if err != nil {
+		return "plugins"
+	}
+	return abs
+}
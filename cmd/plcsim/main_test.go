@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/application"
+	"modbus_simulator/internal/testutil"
+
+	"github.com/simonvetter/modbus"
+)
+
+// buildModbusPlugin は Modbus プラグインをビルドし、plugin.json 付きのプラグインディレクトリを返す
+func buildModbusPlugin(t *testing.T) string {
+	t.Helper()
+	pluginsDir := t.TempDir()
+	dir := filepath.Join(pluginsDir, "modbus-tcp-plugin")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	exe := "modbus-plugin"
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	cmd := exec.Command("go", "build", "-o", filepath.Join(dir, exe), "modbus_simulator/cmd/modbus-plugin")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build modbus-plugin: %v\n%s", err, out)
+	}
+	manifest := fmt.Sprintf(`{"name":"Modbus TCP Plugin","entrypoint":%q,"version":"0.0.1","protocol_type":"modbus-tcp","display_name":"Modbus TCP","variants":[]}`, exe)
+	if err := os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return pluginsDir
+}
+
+func writeProject(t *testing.T, project *application.ProjectDataDTO) string {
+	t.Helper()
+	data, err := json.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "project.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_ModbusTCPSmoke(t *testing.T) {
+	if testing.Short() {
+		t.Skip("プラグインのビルドが必要なため -short ではスキップ")
+	}
+	pluginsDir := buildModbusPlugin(t)
+	port := testutil.FreeTCPPort(t)
+
+	// スクリプトが変数に書き込み、変数は保持レジスタ0にマッピングされている
+	projectPath := writeProject(t, &application.ProjectDataDTO{
+		Servers: []application.ServerSnapshotDTO{{ProtocolType: "modbus-tcp"}},
+		Variables: []*application.VariableDTO{{
+			Name:     "Counter",
+			DataType: "INT",
+			Value:    0,
+			Mappings: []application.ProtocolMappingDTO{{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 0}},
+		}},
+		Scripts: []*application.ScriptDTO{{
+			ID:         "s1",
+			Name:       "set",
+			Code:       `plc.writeVariable("Counter", 1234)`,
+			IntervalMs: 50,
+			Enabled:    true,
+		}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, []string{
+			"-project", projectPath,
+			"-plugins", pluginsDir,
+			"-protocol", "modbus-tcp",
+			"-port", fmt.Sprint(port),
+		}, io.Discard)
+	}()
+
+	client, err := modbus.NewClient(&modbus.ClientConfiguration{
+		URL:     fmt.Sprintf("tcp://127.0.0.1:%d", port),
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got uint16
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-done:
+			t.Fatalf("run exited early: %v", err)
+		default:
+		}
+		if err := client.Open(); err == nil {
+			got, err = client.ReadRegister(0, modbus.HOLDING_REGISTER)
+			client.Close()
+			if err == nil && got == 1234 {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if got != 1234 {
+		t.Errorf("holding register 0 = %d, want 1234", got)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("run: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not return after cancel")
+	}
+}
+
+func TestParseFlags_RequiresProject(t *testing.T) {
+	if _, err := parseFlags(nil, io.Discard); err == nil {
+		t.Error("expected error without -project")
+	}
+	if _, err := parseFlags([]string{"-project", "p.json", "-port", "70000"}, io.Discard); err == nil {
+		t.Error("expected error for out-of-range port")
+	}
+}
@@ -13,31 +13,30 @@ export function CommunicationIndicator({ className = '' }: Props) {
   const txTimeoutRef = useRef<number | null>(null);
 
   useEffect(() => {
-    // 受信イベント
-    const cancelRx = EventsOn('comm:rx', () => {
-      setRxActive(true);
-      if (rxTimeoutRef.current) {
-        clearTimeout(rxTimeoutRef.current);
+    // 受信・送信イベント（100ms ごとに前回以降の回数がまとめて届く）
+    const cancelStats = EventsOn('comm:stats', (stats: { rx: number; tx: number }) => {
+      if (stats.rx > 0) {
+        setRxActive(true);
+        if (rxTimeoutRef.current) {
+          clearTimeout(rxTimeoutRef.current);
+        }
+        rxTimeoutRef.current = window.setTimeout(() => {
+          setRxActive(false);
+        }, 300);
       }
-      rxTimeoutRef.current = window.setTimeout(() => {
-        setRxActive(false);
-      }, 300);
-    });
-
-    // 送信イベント
-    const cancelTx = EventsOn('comm:tx', () => {
-      setTxActive(true);
-      if (txTimeoutRef.current) {
-        clearTimeout(txTimeoutRef.current);
+      if (stats.tx > 0) {
+        setTxActive(true);
+        if (txTimeoutRef.current) {
+          clearTimeout(txTimeoutRef.current);
+        }
+        txTimeoutRef.current = window.setTimeout(() => {
+          setTxActive(false);
+        }, 300);
       }
-      txTimeoutRef.current = window.setTimeout(() => {
-        setTxActive(false);
-      }, 300);
     });
 
     return () => {
-      cancelRx();
-      cancelTx();
+      cancelStats();
       if (rxTimeoutRef.current) clearTimeout(rxTimeoutRef.current);
       if (txTimeoutRef.current) clearTimeout(txTimeoutRef.current);
     };
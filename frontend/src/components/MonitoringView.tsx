@@ -20,14 +20,16 @@ import { CSS } from '@dnd-kit/utilities';
 import {
   GetMonitoringItems,
   GetMemoryAreas,
-  AddMonitoringItem,
+  AddMonitoringItemWithOptions,
   UpdateMonitoringItem,
   DeleteMonitoringItem,
   ReorderMonitoringItem,
   ReadWords,
   ReadBits,
   WriteBit,
-  WriteWord
+  WriteWord,
+  WriteMonitoringValue,
+  SuggestMonitoringDefaults
 } from '../../wailsjs/go/main/App';
 import { application } from '../../wailsjs/go/models';
 
@@ -160,8 +162,20 @@ interface MonitoringItemWithValue {
   rawValues: number[];
   isBit: boolean;
   bitValue?: boolean;
+  engineeringValue?: number; // 換算を設定したワード項目の工学値
 }
 
+// 工学値への換算が設定されているか（Gain・Offset とも 0 なら換算しない）
+const isScaled = (item: application.MonitoringItemDTO): boolean => {
+  return !!item.gain || !!item.offset;
+};
+
+// 生値を工学値（raw × Gain + Offset、Gain 0 は 1 倍）に換算する
+const toEngineeringValue = (item: application.MonitoringItemDTO, raw: bigint): number => {
+  const value = Number(raw) * (item.gain || 1) + (item.offset || 0);
+  return Math.round(value * 1e6) / 1e6;
+};
+
 interface Props {
   serverInstances: application.ServerInstanceDTO[];
 }
@@ -362,7 +376,12 @@ export function MonitoringView({ serverInstances }: Props) {
               }
 
               let formattedValue: string;
-              if (bitWidth === 16) {
+              let engineeringValue: number | undefined;
+              if (isScaled(item)) {
+                // 換算を設定した項目は表示形式によらず工学値の10進で表示する
+                engineeringValue = toEngineeringValue(item, combineWords(words, endianness));
+                formattedValue = item.unit ? `${engineeringValue} ${item.unit}` : String(engineeringValue);
+              } else if (bitWidth === 16) {
                 formattedValue = formatSingleWord(words[0], displayFormat);
               } else {
                 const combined = combineWords(words, endianness);
@@ -373,7 +392,8 @@ export function MonitoringView({ serverInstances }: Props) {
                 item,
                 currentValue: formattedValue,
                 rawValues: words,
-                isBit: false
+                isBit: false,
+                engineeringValue
               };
             }
           } catch {
@@ -428,6 +448,21 @@ export function MonitoringView({ serverInstances }: Props) {
     setIsAddDialogOpen(true);
   };
 
+  // エリア選択時にエリアの種類に応じたビット幅・エンディアン・表示形式を設定する
+  useEffect(() => {
+    if (!isAddDialogOpen || !formProtocolType || !formArea) return;
+    SuggestMonitoringDefaults(formProtocolType, formArea, formAddress)
+      .then(suggested => {
+        // ビットエリアでは設定欄を表示しないため反映しない
+        if (suggested.displayFormat === 'boolean') return;
+        setFormBitWidth(suggested.bitWidth as BitWidth);
+        setFormEndianness(suggested.endianness as Endianness);
+        setFormDisplayFormat(suggested.displayFormat as DisplayFormat);
+      })
+      .catch(e => console.error('Failed to suggest monitoring defaults:', e));
+  // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [isAddDialogOpen, formProtocolType, formArea]);
+
   // フォームのプロトコル変更
   const handleFormProtocolChange = (protocolType: string) => {
     setFormProtocolType(protocolType);
@@ -455,7 +490,8 @@ export function MonitoringView({ serverInstances }: Props) {
           displayFormat: formDisplayFormat
         };
 
-        await AddMonitoringItem(itemData);
+        // 登録済みの項目は追加しない（一覧の重複を防ぐ）
+        await AddMonitoringItemWithOptions(itemData, { onDuplicate: 'existing' });
       }
 
       setIsAddDialogOpen(false);
@@ -523,7 +559,9 @@ export function MonitoringView({ serverInstances }: Props) {
   const handleValueClick = (itemWithValue: MonitoringItemWithValue) => {
     setWritingItem(itemWithValue);
     setWriteInputFormat((itemWithValue.item.displayFormat as DisplayFormat) || 'decimal');
-    setWriteValue(itemWithValue.currentValue);
+    setWriteValue(itemWithValue.engineeringValue !== undefined
+      ? String(itemWithValue.engineeringValue)
+      : itemWithValue.currentValue);
     setIsWriteDialogOpen(true);
   };
 
@@ -566,6 +604,14 @@ export function MonitoringView({ serverInstances }: Props) {
       if (isBit) {
         const newValue = writeValue === 'true' || writeValue === '1' || writeValue.toLowerCase() === 'on';
         await WriteBit(item.protocolType, item.memoryArea, item.address, newValue);
+      } else if (isScaled(item)) {
+        // 工学値で入力し、サーバー側で生値に戻して書き込む
+        const newValue = parseFloat(writeValue);
+        if (isNaN(newValue)) {
+          console.error('Invalid number format');
+          return;
+        }
+        await WriteMonitoringValue(item.id, newValue);
       } else {
         const bitWidth = (item.bitWidth as BitWidth) || 16;
         const endianness = (item.endianness as Endianness) || 'big';
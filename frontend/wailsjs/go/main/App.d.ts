@@ -2,62 +2,118 @@
 // This file is automatically generated. DO NOT EDIT
 import {application} from '../models';
 
+export function AddDriftBehavior(arg1:string,arg2:string,arg3:number,arg4:number,arg5:number,arg6:number,arg7:number):Promise<string>;
+
 export function AddMonitoringItem(arg1:application.MonitoringItemDTO):Promise<application.MonitoringItemDTO>;
 
+export function AddMonitoringItemWithOptions(arg1:application.MonitoringItemDTO,arg2:application.AddMonitoringOptions):Promise<application.MonitoringItemDTO>;
+
 export function AddServer(arg1:string,arg2:string):Promise<void>;
 
+export function ClearCommEventCounter(arg1:string):Promise<void>;
+
 export function ClearConsoleLogs():Promise<void>;
 
+export function ClearLogs():Promise<void>;
+
 export function ClearMonitoringItems():Promise<void>;
 
 export function ClearScriptError(arg1:string):Promise<void>;
 
 export function CreateScript(arg1:string,arg2:string,arg3:number):Promise<application.ScriptDTO>;
 
+export function CreateTrigger(arg1:string,arg2:string,arg3:number,arg4:string):Promise<application.TriggerDTO>;
+
 export function CreateVariable(arg1:string,arg2:string,arg3:any):Promise<application.VariableDTO>;
 
 export function DeleteMonitoringItem(arg1:string):Promise<void>;
 
+export function DeleteRecipe(arg1:string):Promise<void>;
+
 export function DeleteScript(arg1:string):Promise<void>;
 
 export function DeleteStructType(arg1:string):Promise<void>;
 
+export function DeleteTrigger(arg1:string):Promise<void>;
+
 export function DeleteVariable(arg1:string):Promise<void>;
 
+export function DetectSerialMode(arg1:string,arg2:number):Promise<string>;
+
 export function ExportProject():Promise<void>;
 
+export function ExportRangeHex(arg1:string,arg2:string,arg3:number,arg4:number):Promise<string>;
+
+export function GetAccessHeatmap(arg1:string,arg2:string):Promise<Array<application.AccessCountDTO>>;
+
 export function GetAvailableProtocols():Promise<Array<application.ProtocolInfoDTO>>;
 
+export function GetCommEventCounter(arg1:string):Promise<number>;
+
+export function GetConnectionTimeout():Promise<number>;
+
+export function GetConnections():Promise<Array<application.ConnectionDTO>>;
+
 export function GetConsoleLogs():Promise<Array<application.ConsoleLogDTO>>;
 
 export function GetDataTypes():Promise<application.DataTypesDTO>;
 
+export function GetDefaultByteOrder():Promise<string>;
+
 export function GetDisabledUnitIDs(arg1:string):Promise<Array<number>>;
 
+export function GetDriftBehaviors():Promise<Array<application.DriftBehaviorDTO>>;
+
+export function GetExcludedAreas(arg1:string):Promise<Array<string>>;
+
 export function GetHTTPAPIPort():Promise<number>;
 
+export function GetInitialStateFile():Promise<string>;
+
 export function GetIntervalPresets():Promise<Array<application.IntervalPresetDTO>>;
 
+export function GetLockedAreas(arg1:string):Promise<Array<string>>;
+
+export function GetLogLevel():Promise<string>;
+
+export function GetLogs(arg1:string,arg2:number):Promise<Array<application.LogEntryDTO>>;
+
 export function GetMemoryAreas(arg1:string):Promise<Array<application.MemoryAreaDTO>>;
 
+export function GetMemoryPage(arg1:string,arg2:string,arg3:number,arg4:number):Promise<application.MemoryPageDTO>;
+
 export function GetMonitoringItems():Promise<Array<application.MonitoringItemDTO>>;
 
+export function GetMonitoringItemsByGroup():Promise<{[key: string]: Array<application.MonitoringItemDTO>}>;
+
+export function GetOutOfRangePolicy(arg1:string):Promise<string>;
+
 export function GetProtocolSchema(arg1:string):Promise<application.ProtocolSchemaDTO>;
 
 export function GetScript(arg1:string):Promise<application.ScriptDTO>;
 
+export function GetScriptTimeout():Promise<number>;
+
 export function GetScripts():Promise<Array<application.ScriptDTO>>;
 
+export function GetSerialPortDetails():Promise<Array<application.SerialPortInfoDTO>>;
+
 export function GetSerialPorts():Promise<Array<string>>;
 
 export function GetServerConfig(arg1:string):Promise<application.ServerConfigDTO>;
 
+export function GetServerError(arg1:string):Promise<string>;
+
 export function GetServerInstances():Promise<Array<application.ServerInstanceDTO>>;
 
 export function GetServerStatus(arg1:string):Promise<string>;
 
 export function GetStructTypes():Promise<Array<application.StructTypeDTO>>;
 
+export function GetTriggers():Promise<Array<application.TriggerDTO>>;
+
+export function GetUnitActivity():Promise<{[key: number]: any}>;
+
 export function GetUnitIDSettings(arg1:string):Promise<application.UnitIDSettingsDTO>;
 
 export function GetVariableMappings(arg1:string):Promise<Array<application.ProtocolMappingDTO>>;
@@ -66,34 +122,120 @@ export function GetVariables():Promise<Array<application.VariableDTO>>;
 
 export function ImportProject():Promise<void>;
 
+export function ImportProjectSkipChecksum():Promise<void>;
+
+export function ImportRangeHex(arg1:string,arg2:string,arg3:number,arg4:string):Promise<void>;
+
+export function IsAutosaveRunning():Promise<boolean>;
+
+export function IsDemoModeRunning():Promise<boolean>;
+
+export function IsMonitoringCSVLogRunning():Promise<boolean>;
+
+export function IsSharedMemory():Promise<boolean>;
+
+export function ListRecipes():Promise<Array<application.RecipeDTO>>;
+
+export function LoadRecipe(arg1:string):Promise<void>;
+
+export function LockArea(arg1:string,arg2:string):Promise<void>;
+
 export function MoveMonitoringItem(arg1:string,arg2:string):Promise<void>;
 
+export function PauseScript(arg1:string):Promise<void>;
+
+export function ReadAllAreas(arg1:string):Promise<{[key: string]: any}>;
+
 export function ReadBits(arg1:string,arg2:string,arg3:number,arg4:number):Promise<Array<boolean>>;
 
+export function ReadBitsForUnit(arg1:string,arg2:number,arg3:string,arg4:number,arg5:number):Promise<Array<boolean>>;
+
+export function ReadMixed(arg1:Array<application.MixedReadDTO>):Promise<Array<application.MixedResultDTO>>;
+
 export function ReadWords(arg1:string,arg2:string,arg3:number,arg4:number):Promise<Array<number>>;
 
+export function ReadWordsForUnit(arg1:string,arg2:number,arg3:string,arg4:number,arg5:number):Promise<Array<number>>;
+
 export function RegisterStructType(arg1:application.StructTypeDTO):Promise<application.StructTypeDTO>;
 
+export function RemoveDriftBehavior(arg1:string):Promise<void>;
+
 export function RemoveServer(arg1:string):Promise<void>;
 
 export function ReorderMonitoringItem(arg1:string,arg2:number):Promise<void>;
 
+export function ResetHeatmap():Promise<void>;
+
+export function ResizeArea(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function RestoreAutosave(arg1:string):Promise<void>;
+
+export function ResumeScript(arg1:string):Promise<void>;
+
 export function RunScriptOnce(arg1:string):Promise<any>;
 
+export function SaveRecipe(arg1:string):Promise<void>;
+
+export function SelfTest():Promise<application.SelfTestReportDTO>;
+
+export function SetAreaPersist(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
+export function SetBusy(arg1:number):Promise<void>;
+
+export function SetConnectionTimeout(arg1:number):Promise<void>;
+
+export function SetDefaultByteOrder(arg1:string):Promise<void>;
+
 export function SetDisabledUnitIDs(arg1:string,arg2:Array<number>):Promise<void>;
 
 export function SetHTTPAPIPort(arg1:number):Promise<void>;
 
+export function SetInitialStateFile(arg1:string):Promise<void>;
+
+export function SetLogLevel(arg1:string):Promise<void>;
+
+export function SetMonitoringItems(arg1:Array<application.MonitoringItemDTO>):Promise<void>;
+
+export function SetOutOfRangePolicy(arg1:string,arg2:string):Promise<void>;
+
+export function SetScriptLibrary(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetScriptTimeout(arg1:number):Promise<void>;
+
+export function SetSharedMemory(arg1:boolean):Promise<void>;
+
 export function SetUnitIDEnabled(arg1:string,arg2:number,arg3:boolean):Promise<void>;
 
+export function StartAllServers():Promise<void>;
+
+export function StartAutosave(arg1:string,arg2:number):Promise<void>;
+
+export function StartDemoMode(arg1:string,arg2:string,arg3:number,arg4:number,arg5:number):Promise<void>;
+
+export function StartMonitoringCSVLog(arg1:number,arg2:number):Promise<void>;
+
 export function StartScript(arg1:string):Promise<void>;
 
 export function StartServer(arg1:string):Promise<void>;
 
+export function StepScript(arg1:string):Promise<void>;
+
+export function StopAllServers():Promise<void>;
+
+export function StopAutosave():Promise<void>;
+
+export function StopDemoMode():Promise<void>;
+
+export function StopMonitoringCSVLog():Promise<void>;
+
 export function StopScript(arg1:string):Promise<void>;
 
 export function StopServer(arg1:string):Promise<void>;
 
+export function SuggestMonitoringDefaults(arg1:string,arg2:string,arg3:number):Promise<application.MonitoringItemDTO>;
+
+export function UnlockArea(arg1:string,arg2:string):Promise<void>;
+
 export function UpdateMonitoringItem(arg1:application.MonitoringItemDTO):Promise<void>;
 
 export function UpdateScript(arg1:string,arg2:string,arg3:string,arg4:number):Promise<void>;
@@ -112,4 +254,10 @@ export function UpdateVariableValue(arg1:string,arg2:any):Promise<void>;
 
 export function WriteBit(arg1:string,arg2:string,arg3:number,arg4:boolean):Promise<void>;
 
+export function WriteBitForUnit(arg1:string,arg2:number,arg3:string,arg4:number,arg5:boolean):Promise<void>;
+
+export function WriteMonitoringValue(arg1:string,arg2:number):Promise<void>;
+
 export function WriteWord(arg1:string,arg2:string,arg3:number,arg4:number):Promise<void>;
+
+export function WriteWordForUnit(arg1:string,arg2:number,arg3:string,arg4:number,arg5:number):Promise<void>;
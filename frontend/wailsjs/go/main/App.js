@@ -2,18 +2,34 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddDriftBehavior(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['AddDriftBehavior'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
 export function AddMonitoringItem(arg1) {
   return window['go']['main']['App']['AddMonitoringItem'](arg1);
 }
 
+export function AddMonitoringItemWithOptions(arg1, arg2) {
+  return window['go']['main']['App']['AddMonitoringItemWithOptions'](arg1, arg2);
+}
+
 export function AddServer(arg1, arg2) {
   return window['go']['main']['App']['AddServer'](arg1, arg2);
 }
 
+export function ClearCommEventCounter(arg1) {
+  return window['go']['main']['App']['ClearCommEventCounter'](arg1);
+}
+
 export function ClearConsoleLogs() {
   return window['go']['main']['App']['ClearConsoleLogs']();
 }
 
+export function ClearLogs() {
+  return window['go']['main']['App']['ClearLogs']();
+}
+
 export function ClearMonitoringItems() {
   return window['go']['main']['App']['ClearMonitoringItems']();
 }
@@ -26,6 +42,10 @@ export function CreateScript(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreateScript'](arg1, arg2, arg3);
 }
 
+export function CreateTrigger(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreateTrigger'](arg1, arg2, arg3, arg4);
+}
+
 export function CreateVariable(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreateVariable'](arg1, arg2, arg3);
 }
@@ -34,6 +54,10 @@ export function DeleteMonitoringItem(arg1) {
   return window['go']['main']['App']['DeleteMonitoringItem'](arg1);
 }
 
+export function DeleteRecipe(arg1) {
+  return window['go']['main']['App']['DeleteRecipe'](arg1);
+}
+
 export function DeleteScript(arg1) {
   return window['go']['main']['App']['DeleteScript'](arg1);
 }
@@ -42,18 +66,46 @@ export function DeleteStructType(arg1) {
   return window['go']['main']['App']['DeleteStructType'](arg1);
 }
 
+export function DeleteTrigger(arg1) {
+  return window['go']['main']['App']['DeleteTrigger'](arg1);
+}
+
 export function DeleteVariable(arg1) {
   return window['go']['main']['App']['DeleteVariable'](arg1);
 }
 
+export function DetectSerialMode(arg1, arg2) {
+  return window['go']['main']['App']['DetectSerialMode'](arg1, arg2);
+}
+
 export function ExportProject() {
   return window['go']['main']['App']['ExportProject']();
 }
 
+export function ExportRangeHex(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportRangeHex'](arg1, arg2, arg3, arg4);
+}
+
+export function GetAccessHeatmap(arg1, arg2) {
+  return window['go']['main']['App']['GetAccessHeatmap'](arg1, arg2);
+}
+
 export function GetAvailableProtocols() {
   return window['go']['main']['App']['GetAvailableProtocols']();
 }
 
+export function GetCommEventCounter(arg1) {
+  return window['go']['main']['App']['GetCommEventCounter'](arg1);
+}
+
+export function GetConnectionTimeout() {
+  return window['go']['main']['App']['GetConnectionTimeout']();
+}
+
+export function GetConnections() {
+  return window['go']['main']['App']['GetConnections']();
+}
+
 export function GetConsoleLogs() {
   return window['go']['main']['App']['GetConsoleLogs']();
 }
@@ -62,26 +114,66 @@ export function GetDataTypes() {
   return window['go']['main']['App']['GetDataTypes']();
 }
 
+export function GetDefaultByteOrder() {
+  return window['go']['main']['App']['GetDefaultByteOrder']();
+}
+
 export function GetDisabledUnitIDs(arg1) {
   return window['go']['main']['App']['GetDisabledUnitIDs'](arg1);
 }
 
+export function GetDriftBehaviors() {
+  return window['go']['main']['App']['GetDriftBehaviors']();
+}
+
+export function GetExcludedAreas(arg1) {
+  return window['go']['main']['App']['GetExcludedAreas'](arg1);
+}
+
 export function GetHTTPAPIPort() {
   return window['go']['main']['App']['GetHTTPAPIPort']();
 }
 
+export function GetInitialStateFile() {
+  return window['go']['main']['App']['GetInitialStateFile']();
+}
+
 export function GetIntervalPresets() {
   return window['go']['main']['App']['GetIntervalPresets']();
 }
 
+export function GetLockedAreas(arg1) {
+  return window['go']['main']['App']['GetLockedAreas'](arg1);
+}
+
+export function GetLogLevel() {
+  return window['go']['main']['App']['GetLogLevel']();
+}
+
+export function GetLogs(arg1, arg2) {
+  return window['go']['main']['App']['GetLogs'](arg1, arg2);
+}
+
 export function GetMemoryAreas(arg1) {
   return window['go']['main']['App']['GetMemoryAreas'](arg1);
 }
 
+export function GetMemoryPage(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetMemoryPage'](arg1, arg2, arg3, arg4);
+}
+
 export function GetMonitoringItems() {
   return window['go']['main']['App']['GetMonitoringItems']();
 }
 
+export function GetMonitoringItemsByGroup() {
+  return window['go']['main']['App']['GetMonitoringItemsByGroup']();
+}
+
+export function GetOutOfRangePolicy(arg1) {
+  return window['go']['main']['App']['GetOutOfRangePolicy'](arg1);
+}
+
 export function GetProtocolSchema(arg1) {
   return window['go']['main']['App']['GetProtocolSchema'](arg1);
 }
@@ -90,10 +182,18 @@ export function GetScript(arg1) {
   return window['go']['main']['App']['GetScript'](arg1);
 }
 
+export function GetScriptTimeout() {
+  return window['go']['main']['App']['GetScriptTimeout']();
+}
+
 export function GetScripts() {
   return window['go']['main']['App']['GetScripts']();
 }
 
+export function GetSerialPortDetails() {
+  return window['go']['main']['App']['GetSerialPortDetails']();
+}
+
 export function GetSerialPorts() {
   return window['go']['main']['App']['GetSerialPorts']();
 }
@@ -102,6 +202,10 @@ export function GetServerConfig(arg1) {
   return window['go']['main']['App']['GetServerConfig'](arg1);
 }
 
+export function GetServerError(arg1) {
+  return window['go']['main']['App']['GetServerError'](arg1);
+}
+
 export function GetServerInstances() {
   return window['go']['main']['App']['GetServerInstances']();
 }
@@ -114,6 +218,14 @@ export function GetStructTypes() {
   return window['go']['main']['App']['GetStructTypes']();
 }
 
+export function GetTriggers() {
+  return window['go']['main']['App']['GetTriggers']();
+}
+
+export function GetUnitActivity() {
+  return window['go']['main']['App']['GetUnitActivity']();
+}
+
 export function GetUnitIDSettings(arg1) {
   return window['go']['main']['App']['GetUnitIDSettings'](arg1);
 }
@@ -130,22 +242,82 @@ export function ImportProject() {
   return window['go']['main']['App']['ImportProject']();
 }
 
+export function ImportProjectSkipChecksum() {
+  return window['go']['main']['App']['ImportProjectSkipChecksum']();
+}
+
+export function ImportRangeHex(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportRangeHex'](arg1, arg2, arg3, arg4);
+}
+
+export function IsAutosaveRunning() {
+  return window['go']['main']['App']['IsAutosaveRunning']();
+}
+
+export function IsDemoModeRunning() {
+  return window['go']['main']['App']['IsDemoModeRunning']();
+}
+
+export function IsMonitoringCSVLogRunning() {
+  return window['go']['main']['App']['IsMonitoringCSVLogRunning']();
+}
+
+export function IsSharedMemory() {
+  return window['go']['main']['App']['IsSharedMemory']();
+}
+
+export function ListRecipes() {
+  return window['go']['main']['App']['ListRecipes']();
+}
+
+export function LoadRecipe(arg1) {
+  return window['go']['main']['App']['LoadRecipe'](arg1);
+}
+
+export function LockArea(arg1, arg2) {
+  return window['go']['main']['App']['LockArea'](arg1, arg2);
+}
+
 export function MoveMonitoringItem(arg1, arg2) {
   return window['go']['main']['App']['MoveMonitoringItem'](arg1, arg2);
 }
 
+export function PauseScript(arg1) {
+  return window['go']['main']['App']['PauseScript'](arg1);
+}
+
+export function ReadAllAreas(arg1) {
+  return window['go']['main']['App']['ReadAllAreas'](arg1);
+}
+
 export function ReadBits(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['ReadBits'](arg1, arg2, arg3, arg4);
 }
 
+export function ReadBitsForUnit(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ReadBitsForUnit'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function ReadMixed(arg1) {
+  return window['go']['main']['App']['ReadMixed'](arg1);
+}
+
 export function ReadWords(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['ReadWords'](arg1, arg2, arg3, arg4);
 }
 
+export function ReadWordsForUnit(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ReadWordsForUnit'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function RegisterStructType(arg1) {
   return window['go']['main']['App']['RegisterStructType'](arg1);
 }
 
+export function RemoveDriftBehavior(arg1) {
+  return window['go']['main']['App']['RemoveDriftBehavior'](arg1);
+}
+
 export function RemoveServer(arg1) {
   return window['go']['main']['App']['RemoveServer'](arg1);
 }
@@ -154,10 +326,50 @@ export function ReorderMonitoringItem(arg1, arg2) {
   return window['go']['main']['App']['ReorderMonitoringItem'](arg1, arg2);
 }
 
+export function ResetHeatmap() {
+  return window['go']['main']['App']['ResetHeatmap']();
+}
+
+export function ResizeArea(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ResizeArea'](arg1, arg2, arg3);
+}
+
+export function RestoreAutosave(arg1) {
+  return window['go']['main']['App']['RestoreAutosave'](arg1);
+}
+
+export function ResumeScript(arg1) {
+  return window['go']['main']['App']['ResumeScript'](arg1);
+}
+
 export function RunScriptOnce(arg1) {
   return window['go']['main']['App']['RunScriptOnce'](arg1);
 }
 
+export function SaveRecipe(arg1) {
+  return window['go']['main']['App']['SaveRecipe'](arg1);
+}
+
+export function SelfTest() {
+  return window['go']['main']['App']['SelfTest']();
+}
+
+export function SetAreaPersist(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetAreaPersist'](arg1, arg2, arg3);
+}
+
+export function SetBusy(arg1) {
+  return window['go']['main']['App']['SetBusy'](arg1);
+}
+
+export function SetConnectionTimeout(arg1) {
+  return window['go']['main']['App']['SetConnectionTimeout'](arg1);
+}
+
+export function SetDefaultByteOrder(arg1) {
+  return window['go']['main']['App']['SetDefaultByteOrder'](arg1);
+}
+
 export function SetDisabledUnitIDs(arg1, arg2) {
   return window['go']['main']['App']['SetDisabledUnitIDs'](arg1, arg2);
 }
@@ -166,10 +378,54 @@ export function SetHTTPAPIPort(arg1) {
   return window['go']['main']['App']['SetHTTPAPIPort'](arg1);
 }
 
+export function SetInitialStateFile(arg1) {
+  return window['go']['main']['App']['SetInitialStateFile'](arg1);
+}
+
+export function SetLogLevel(arg1) {
+  return window['go']['main']['App']['SetLogLevel'](arg1);
+}
+
+export function SetMonitoringItems(arg1) {
+  return window['go']['main']['App']['SetMonitoringItems'](arg1);
+}
+
+export function SetOutOfRangePolicy(arg1, arg2) {
+  return window['go']['main']['App']['SetOutOfRangePolicy'](arg1, arg2);
+}
+
+export function SetScriptLibrary(arg1, arg2) {
+  return window['go']['main']['App']['SetScriptLibrary'](arg1, arg2);
+}
+
+export function SetScriptTimeout(arg1) {
+  return window['go']['main']['App']['SetScriptTimeout'](arg1);
+}
+
+export function SetSharedMemory(arg1) {
+  return window['go']['main']['App']['SetSharedMemory'](arg1);
+}
+
 export function SetUnitIDEnabled(arg1, arg2, arg3) {
   return window['go']['main']['App']['SetUnitIDEnabled'](arg1, arg2, arg3);
 }
 
+export function StartAllServers() {
+  return window['go']['main']['App']['StartAllServers']();
+}
+
+export function StartAutosave(arg1, arg2) {
+  return window['go']['main']['App']['StartAutosave'](arg1, arg2);
+}
+
+export function StartDemoMode(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['StartDemoMode'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function StartMonitoringCSVLog(arg1, arg2) {
+  return window['go']['main']['App']['StartMonitoringCSVLog'](arg1, arg2);
+}
+
 export function StartScript(arg1) {
   return window['go']['main']['App']['StartScript'](arg1);
 }
@@ -178,6 +434,26 @@ export function StartServer(arg1) {
   return window['go']['main']['App']['StartServer'](arg1);
 }
 
+export function StepScript(arg1) {
+  return window['go']['main']['App']['StepScript'](arg1);
+}
+
+export function StopAllServers() {
+  return window['go']['main']['App']['StopAllServers']();
+}
+
+export function StopAutosave() {
+  return window['go']['main']['App']['StopAutosave']();
+}
+
+export function StopDemoMode() {
+  return window['go']['main']['App']['StopDemoMode']();
+}
+
+export function StopMonitoringCSVLog() {
+  return window['go']['main']['App']['StopMonitoringCSVLog']();
+}
+
 export function StopScript(arg1) {
   return window['go']['main']['App']['StopScript'](arg1);
 }
@@ -186,6 +462,14 @@ export function StopServer(arg1) {
   return window['go']['main']['App']['StopServer'](arg1);
 }
 
+export function SuggestMonitoringDefaults(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SuggestMonitoringDefaults'](arg1, arg2, arg3);
+}
+
+export function UnlockArea(arg1, arg2) {
+  return window['go']['main']['App']['UnlockArea'](arg1, arg2);
+}
+
 export function UpdateMonitoringItem(arg1) {
   return window['go']['main']['App']['UpdateMonitoringItem'](arg1);
 }
@@ -222,6 +506,18 @@ export function WriteBit(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['WriteBit'](arg1, arg2, arg3, arg4);
 }
 
+export function WriteBitForUnit(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['WriteBitForUnit'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function WriteMonitoringValue(arg1, arg2) {
+  return window['go']['main']['App']['WriteMonitoringValue'](arg1, arg2);
+}
+
 export function WriteWord(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['WriteWord'](arg1, arg2, arg3, arg4);
 }
+
+export function WriteWordForUnit(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['WriteWordForUnit'](arg1, arg2, arg3, arg4, arg5);
+}
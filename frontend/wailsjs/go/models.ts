@@ -1,5 +1,33 @@
 export namespace application {
 	
+	export class AccessCountDTO {
+	    address: number;
+	    reads: number;
+	    writes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AccessCountDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.address = source["address"];
+	        this.reads = source["reads"];
+	        this.writes = source["writes"];
+	    }
+	}
+	export class AddMonitoringOptions {
+	    onDuplicate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AddMonitoringOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.onDuplicate = source["onDuplicate"];
+	    }
+	}
 	export class CapabilitiesDTO {
 	    supportsUnitId: boolean;
 	    unitIdMin?: number;
@@ -44,6 +72,24 @@ export namespace application {
 	        this.displayName = source["displayName"];
 	    }
 	}
+	export class ConnectionDTO {
+	    protocolType: string;
+	    remoteAddress: string;
+	    unitId: number;
+	    lastActivity: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocolType = source["protocolType"];
+	        this.remoteAddress = source["remoteAddress"];
+	        this.unitId = source["unitId"];
+	        this.lastActivity = source["lastActivity"];
+	    }
+	}
 	export class ConsoleLogDTO {
 	    scriptId: string;
 	    scriptName: string;
@@ -162,6 +208,32 @@ export namespace application {
 		    return a;
 		}
 	}
+	export class DriftBehaviorDTO {
+	    id: string;
+	    protocolType: string;
+	    memoryArea: string;
+	    address: number;
+	    min: number;
+	    max: number;
+	    stepMax: number;
+	    intervalMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DriftBehaviorDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.protocolType = source["protocolType"];
+	        this.memoryArea = source["memoryArea"];
+	        this.address = source["address"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.stepMax = source["stepMax"];
+	        this.intervalMs = source["intervalMs"];
+	    }
+	}
 	export class OptionDTO {
 	    value: string;
 	    label: string;
@@ -240,6 +312,24 @@ export namespace application {
 	        this.ms = source["ms"];
 	    }
 	}
+	export class LogEntryDTO {
+	    at: number;
+	    level: string;
+	    message: string;
+	    attrs?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogEntryDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.at = source["at"];
+	        this.level = source["level"];
+	        this.message = source["message"];
+	        this.attrs = source["attrs"];
+	    }
+	}
 	export class MemoryAreaDTO {
 	    id: string;
 	    displayName: string;
@@ -264,6 +354,66 @@ export namespace application {
 	        this.oneOrigin = source["oneOrigin"];
 	    }
 	}
+	export class MemoryPageDTO {
+	    area: string;
+	    offset: number;
+	    length: number;
+	    totalSize: number;
+	    isBit: boolean;
+	    bits?: boolean[];
+	    words?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryPageDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.area = source["area"];
+	        this.offset = source["offset"];
+	        this.length = source["length"];
+	        this.totalSize = source["totalSize"];
+	        this.isBit = source["isBit"];
+	        this.bits = source["bits"];
+	        this.words = source["words"];
+	    }
+	}
+	export class MixedReadDTO {
+	    protocolType: string;
+	    area: string;
+	    address: number;
+	    count: number;
+	    type: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MixedReadDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocolType = source["protocolType"];
+	        this.area = source["area"];
+	        this.address = source["address"];
+	        this.count = source["count"];
+	        this.type = source["type"];
+	    }
+	}
+	export class MixedResultDTO {
+	    bits?: boolean[];
+	    words?: number[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MixedResultDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bits = source["bits"];
+	        this.words = source["words"];
+	        this.error = source["error"];
+	    }
+	}
 	export class MonitoringItemDTO {
 	    id: string;
 	    order: number;
@@ -273,6 +423,13 @@ export namespace application {
 	    bitWidth: number;
 	    endianness: string;
 	    displayFormat: string;
+	    group?: string;
+	    alarmEnabled?: boolean;
+	    alarmHigh?: number;
+	    alarmLow?: number;
+	    gain?: number;
+	    offset?: number;
+	    unit?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new MonitoringItemDTO(source);
@@ -288,6 +445,13 @@ export namespace application {
 	        this.bitWidth = source["bitWidth"];
 	        this.endianness = source["endianness"];
 	        this.displayFormat = source["displayFormat"];
+	        this.group = source["group"];
+	        this.alarmEnabled = source["alarmEnabled"];
+	        this.alarmHigh = source["alarmHigh"];
+	        this.alarmLow = source["alarmLow"];
+	        this.gain = source["gain"];
+	        this.offset = source["offset"];
+	        this.unit = source["unit"];
 	    }
 	}
 	export class NodePublishingDTO {
@@ -429,12 +593,65 @@ export namespace application {
 		    return a;
 		}
 	}
+	export class RecipeValueDTO {
+	    protocolType: string;
+	    memoryArea: string;
+	    address: number;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecipeValueDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocolType = source["protocolType"];
+	        this.memoryArea = source["memoryArea"];
+	        this.address = source["address"];
+	        this.value = source["value"];
+	    }
+	}
+	export class RecipeDTO {
+	    name: string;
+	    values: RecipeValueDTO[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RecipeDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.values = this.convertValues(source["values"], RecipeValueDTO);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class ScriptDTO {
 	    id: string;
 	    name: string;
 	    code: string;
 	    intervalMs: number;
+	    enabled: boolean;
+	    isLibrary: boolean;
 	    isRunning: boolean;
+	    isPaused: boolean;
 	    lastError: string;
 	    errorAt: number;
 	
@@ -448,11 +665,86 @@ export namespace application {
 	        this.name = source["name"];
 	        this.code = source["code"];
 	        this.intervalMs = source["intervalMs"];
+	        this.enabled = source["enabled"];
+	        this.isLibrary = source["isLibrary"];
 	        this.isRunning = source["isRunning"];
+	        this.isPaused = source["isPaused"];
 	        this.lastError = source["lastError"];
 	        this.errorAt = source["errorAt"];
 	    }
 	}
+	export class SelfTestCheckDTO {
+	    name: string;
+	    passed: boolean;
+	    skipped?: boolean;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelfTestCheckDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.passed = source["passed"];
+	        this.skipped = source["skipped"];
+	        this.message = source["message"];
+	    }
+	}
+	export class SelfTestReportDTO {
+	    passed: boolean;
+	    checks: SelfTestCheckDTO[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SelfTestReportDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.passed = source["passed"];
+	        this.checks = this.convertValues(source["checks"], SelfTestCheckDTO);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SerialPortInfoDTO {
+	    name: string;
+	    isUsb: boolean;
+	    vid?: string;
+	    pid?: string;
+	    serialNumber?: string;
+	    product?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SerialPortInfoDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.isUsb = source["isUsb"];
+	        this.vid = source["vid"];
+	        this.pid = source["pid"];
+	        this.serialNumber = source["serialNumber"];
+	        this.product = source["product"];
+	    }
+	}
 	export class ServerConfigDTO {
 	    protocolType: string;
 	    variant: string;
@@ -489,6 +781,26 @@ export namespace application {
 	        this.supportsNodePublishing = source["supportsNodePublishing"];
 	    }
 	}
+	export class TriggerDTO {
+	    id: string;
+	    protocolType: string;
+	    memoryArea: string;
+	    address: number;
+	    scriptId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TriggerDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.protocolType = source["protocolType"];
+	        this.memoryArea = source["memoryArea"];
+	        this.address = source["address"];
+	        this.scriptId = source["scriptId"];
+	    }
+	}
 	
 	
 	export class UnitIDSettingsDTO {
@@ -16,6 +16,10 @@ type AppStateEmitter interface {
 	EmitVariablesChanged(variables []*VariableDTO)
 	EmitScriptsChanged(scripts []*ScriptDTO)
 	EmitConsoleLogAdded(entry ConsoleLogDTO)
+	EmitLogEntry(entry LogEntryDTO)
+	EmitAlarmTriggered(event MonitoringAlarmEventDTO)
+	EmitAlarmCleared(event MonitoringAlarmEventDTO)
+	EmitServerStatus(event ServerStatusEventDTO)
 }
 
 // WailsAppStateEmitter はWailsランタイムを使用したAppStateEmitter実装
@@ -61,6 +65,38 @@ func (e *WailsAppStateEmitter) EmitConsoleLogAdded(entry ConsoleLogDTO) {
 	runtime.EventsEmit(e.ctx, "plc:console-log-added", entry)
 }
 
+// EmitLogEntry は診断ログ追加イベントを発行する
+func (e *WailsAppStateEmitter) EmitLogEntry(entry LogEntryDTO) {
+	if e.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(e.ctx, "log:entry", entry)
+}
+
+// EmitAlarmTriggered はモニタリング項目のアラーム発報イベントを発行する
+func (e *WailsAppStateEmitter) EmitAlarmTriggered(event MonitoringAlarmEventDTO) {
+	if e.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(e.ctx, "alarm:triggered", event)
+}
+
+// EmitAlarmCleared はモニタリング項目のアラーム解除イベントを発行する
+func (e *WailsAppStateEmitter) EmitAlarmCleared(event MonitoringAlarmEventDTO) {
+	if e.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(e.ctx, "alarm:cleared", event)
+}
+
+// EmitServerStatus はサーバーの状態変化イベントを発行する
+func (e *WailsAppStateEmitter) EmitServerStatus(event ServerStatusEventDTO) {
+	if e.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(e.ctx, "server:status", event)
+}
+
 // variableChangeListener は VariableStore の変更を受け取りスロットルしてイベント発行するリスナー。
 //
 // 動作: leading fire + 定間隔 trailing fire
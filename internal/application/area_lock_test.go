@@ -0,0 +1,152 @@
+package application
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPLCService_LockArea_BlocksUIWrites(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.LockArea("modbus-tcp", "holdingRegisters"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.LockArea("modbus-tcp", "coils"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 1); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("WriteWord: expected ErrAreaLocked, got %v", err)
+	}
+	if err := svc.WriteWordForUnit("modbus-tcp", 1, "holdingRegisters", 0, 1); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("WriteWordForUnit: expected ErrAreaLocked, got %v", err)
+	}
+	if err := svc.WriteBit("modbus-tcp", "coils", 0, true); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("WriteBit: expected ErrAreaLocked, got %v", err)
+	}
+	if err := svc.WriteBitForUnit("modbus-tcp", 1, "coils", 0, true); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("WriteBitForUnit: expected ErrAreaLocked, got %v", err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); words[0] != 0 {
+		t.Errorf("locked register was written: %d", words[0])
+	}
+
+	// ロックしていないエリアには書き込める
+	if err := svc.WriteWord("modbus-tcp", "inputRegisters", 0, 5); err != nil {
+		t.Errorf("unlocked area: %v", err)
+	}
+	// スクリプトからの塗りつぶしは制限しない
+	script := &scriptMemoryAccessor{service: svc}
+	if err := script.FillArea("modbus-tcp", "holdingRegisters", 3); err != nil {
+		t.Errorf("script FillArea on locked area: %v", err)
+	}
+
+	if got, _ := svc.GetLockedAreas("modbus-tcp"); !reflect.DeepEqual(got, []string{"coils", "holdingRegisters"}) {
+		t.Errorf("GetLockedAreas = %v", got)
+	}
+
+	if err := svc.UnlockArea("modbus-tcp", "holdingRegisters"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 1234); err != nil {
+		t.Errorf("WriteWord after unlock: %v", err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); words[0] != 1234 {
+		t.Errorf("holding register 0 = %d, want 1234", words[0])
+	}
+	if err := svc.WriteBit("modbus-tcp", "coils", 0, true); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("coils should still be locked, got %v", err)
+	}
+}
+
+func TestPLCService_LockArea_BlocksBulkUIWrites(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 4, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SaveRecipe("r1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 4, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.LockArea("modbus-tcp", "holdingRegisters"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.ImportRangeHex("modbus-tcp", "holdingRegisters", 0, "1234 ABCD"); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("ImportRangeHex: expected ErrAreaLocked, got %v", err)
+	}
+	if err := svc.LoadRecipe("r1"); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("LoadRecipe: expected ErrAreaLocked, got %v", err)
+	}
+	if err := svc.StartDemoMode("modbus-tcp", "holdingRegisters", 0, 4, 100); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("StartDemoMode: expected ErrAreaLocked, got %v", err)
+	}
+	if err := svc.FillArea("modbus-tcp", "holdingRegisters", 1); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("FillArea: expected ErrAreaLocked, got %v", err)
+	}
+	if err := svc.FillAreaPattern("modbus-tcp", "holdingRegisters", []int{1, 2}); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("FillAreaPattern: expected ErrAreaLocked, got %v", err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 5); !reflect.DeepEqual(words, []int{0, 0, 0, 0, 0}) {
+		t.Errorf("locked registers were written: %v", words)
+	}
+
+	// スクリプトからの書き込みは制限しない
+	script := &scriptMemoryAccessor{service: svc}
+	if err := script.WriteHex("modbus-tcp", "holdingRegisters", 0, "1234"); err != nil {
+		t.Errorf("script WriteHex on locked area: %v", err)
+	}
+	if err := script.LoadRecipe("r1"); err != nil {
+		t.Errorf("script LoadRecipe on locked area: %v", err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 5); !reflect.DeepEqual(words, []int{0x1234, 0, 0, 0, 7}) {
+		t.Errorf("holding registers = %v after script writes", words)
+	}
+	if err := script.FillArea("modbus-tcp", "holdingRegisters", 9); err != nil {
+		t.Errorf("script FillArea on locked area: %v", err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 3); !reflect.DeepEqual(words, []int{9, 9, 9}) {
+		t.Errorf("holding registers = %v after script fill", words)
+	}
+}
+
+func TestPLCService_LockArea_Errors(t *testing.T) {
+	svc := newTestService(t)
+	tests := []struct {
+		name         string
+		protocolType string
+		area         string
+	}{
+		{"unknown server", "modbus-rtu", "coils"},
+		{"unknown area", "modbus-tcp", "noSuchArea"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.LockArea(tt.protocolType, tt.area); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestPLCService_LockArea_PersistedInProject(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.LockArea("modbus-tcp", "holdingRegisters"); err != nil {
+		t.Fatal(err)
+	}
+
+	project := svc.ExportProject()
+	if got := project.Servers[0].LockedAreas; !reflect.DeepEqual(got, []string{"holdingRegisters"}) {
+		t.Fatalf("exported LockedAreas = %v", got)
+	}
+
+	restored := newTestService(t)
+	if err := restored.ImportProject(project); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.WriteWord("modbus-tcp", "holdingRegisters", 0, 1); !errors.Is(err, ErrAreaLocked) {
+		t.Errorf("expected ErrAreaLocked after import, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package application
+
+import "sort"
+
+// SetAreaPersist は ExportProject でエリアのメモリ内容を保存するかを設定する（既定は保存する）。
+// 値をほとんど変更しない大きなエリアを除外してプロジェクトファイルを小さくするために使う。
+// 除外したエリアはインポート時に既定（ゼロ）の状態のままになる。設定自体はプロジェクトに保存される。
+func (s *PLCService) SetAreaPersist(protocolType, area string, persist bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if _, err := findMemoryArea(inst.dataStore, area); err != nil {
+		return err
+	}
+	if persist {
+		delete(inst.excludedAreas, area)
+		return nil
+	}
+	if inst.excludedAreas == nil {
+		inst.excludedAreas = make(map[string]bool)
+	}
+	inst.excludedAreas[area] = true
+	return nil
+}
+
+// GetExcludedAreas は指定サーバーでメモリ内容を保存しないエリアIDを名前順で返す
+func (s *PLCService) GetExcludedAreas(protocolType string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	return sortedExcludedAreas(inst), nil
+}
+
+// sortedExcludedAreas はメモリ内容を保存しないエリアIDを名前順で返す（なければ nil）
+func sortedExcludedAreas(inst *serverInstance) []string {
+	if len(inst.excludedAreas) == 0 {
+		return nil
+	}
+	areas := make([]string, 0, len(inst.excludedAreas))
+	for area := range inst.excludedAreas {
+		areas = append(areas, area)
+	}
+	sort.Strings(areas)
+	return areas
+}
+
+// persistedSnapshot はサーバーのメモリ内容のうち、保存対象のエリアのみのスナップショットを返す（ロック取得済みであること）
+func persistedSnapshot(inst *serverInstance) map[string]interface{} {
+	snap := inst.dataStore.Snapshot()
+	for area := range inst.excludedAreas {
+		delete(snap, area)
+	}
+	return snap
+}
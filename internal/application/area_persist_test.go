@@ -0,0 +1,65 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+func TestPLCService_SetAreaPersist_ExcludesAreaFromExport(t *testing.T) {
+	src := newTestService(t)
+	if err := src.WriteWord("modbus-tcp", "holdingRegisters", 100, 1234); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.WriteWord("modbus-tcp", "inputRegisters", 5, 55); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SetAreaPersist("modbus-tcp", "holdingRegisters", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SetAreaPersist("modbus-tcp", "unknownArea", false); err == nil {
+		t.Error("expected error for unknown area")
+	}
+
+	project := src.ExportProject()
+	snap, err := datastore.DecodeSnapshotBinary(project.Servers[0].MemoryBinary)
+	if err != nil {
+		t.Fatalf("DecodeSnapshotBinary: %v", err)
+	}
+	if _, ok := snap["holdingRegisters"]; ok {
+		t.Error("excluded area holdingRegisters should be absent from the snapshot")
+	}
+	for _, area := range []string{"coils", "discreteInputs", "inputRegisters"} {
+		if _, ok := snap[area]; !ok {
+			t.Errorf("area %s should be persisted", area)
+		}
+	}
+	if !reflect.DeepEqual(project.Servers[0].ExcludedAreas, []string{"holdingRegisters"}) {
+		t.Errorf("ExcludedAreas = %v", project.Servers[0].ExcludedAreas)
+	}
+
+	decoded, _ := projectRoundTrip(t, project)
+	dst := newTestService(t)
+	if err := dst.ImportProject(decoded); err != nil {
+		t.Fatalf("ImportProject: %v", err)
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 100, 1); v[0] != 0 {
+		t.Errorf("excluded holding register 100 = %d, want 0", v[0])
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "inputRegisters", 5, 1); v[0] != 55 {
+		t.Errorf("input register 5 = %d, want 55", v[0])
+	}
+	if got, _ := dst.GetExcludedAreas("modbus-tcp"); !reflect.DeepEqual(got, []string{"holdingRegisters"}) {
+		t.Errorf("GetExcludedAreas after import = %v", got)
+	}
+
+	// 再び保存対象にするとエクスポートに含まれる
+	if err := src.SetAreaPersist("modbus-tcp", "holdingRegisters", true); err != nil {
+		t.Fatal(err)
+	}
+	snap, _ = datastore.DecodeSnapshotBinary(src.ExportProject().Servers[0].MemoryBinary)
+	if _, ok := snap["holdingRegisters"]; !ok {
+		t.Error("holdingRegisters should be persisted again")
+	}
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic は data を一時ファイルに書き込んでから path へリネームする。
+// 書き込み途中でプロセスが終了しても path には以前の内容か新しい内容のどちらかが残り、壊れたファイルにならない。
+// 一時ファイルは同じディレクトリに作成し、失敗した場合は削除する。
+func writeFileAtomic(path string, data []byte) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp は 0600 で作成するため、os.WriteFile と同じ権限に揃える
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ExportProjectToFile はプロジェクトを JSON にして path へ保存する（書き込みはアトミック）。
+// path が既に存在する場合は、上書き前の内容を SetProjectBackupDepth の世代数だけバックアップとして残す。
+func (s *PLCService) ExportProjectToFile(path string) error {
+	data, err := json.MarshalIndent(s.ExportProject(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := rotateProjectBackups(path, s.GetProjectBackupDepth()); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	return writeFileAtomic(path, data)
+}
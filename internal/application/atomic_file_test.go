@@ -0,0 +1,69 @@
+package application
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic_ReplacesWithoutLeftovers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "project.json")
+	if err := os.WriteFile(path, []byte("old content that is longer"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(path, []byte("new")); err != nil {
+		t.Fatalf("writeFileAtomic: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new" {
+		t.Errorf("content = %q, want %q", got, "new")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("directory should only contain the target file, got %v", names)
+	}
+}
+
+func TestWriteFileAtomic_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "project.json")
+	if err := writeFileAtomic(path, []byte("x")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestPLCService_ExportProjectToFile(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 3, 33); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "project.json")
+	if err := svc.ExportProjectToFile(path); err != nil {
+		t.Fatalf("ExportProjectToFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var project ProjectDataDTO
+	if err := json.Unmarshal(data, &project); err != nil {
+		t.Fatalf("saved file is not a project: %v", err)
+	}
+	other := newTestService(t)
+	if err := other.ImportProject(&project); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := other.ReadWords("modbus-tcp", "holdingRegisters", 3, 1); words[0] != 33 {
+		t.Errorf("holding register 3 = %d, want 33", words[0])
+	}
+}
@@ -0,0 +1,314 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"modbus_simulator/internal/domain/datastore"
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// minAutosaveInterval はオートセーブの最短間隔
+const minAutosaveInterval = 100 * time.Millisecond
+
+// autosaveCompactEvery は差分ファイルをこの数だけ書いたらベースファイルにまとめる
+const autosaveCompactEvery = 30
+
+// オートセーブのファイル名
+const (
+	autosaveBaseFile    = "memory-base.json"
+	autosaveDeltaPrefix = "memory-delta-"
+)
+
+// autosaveBaseFileDTO はベースファイルの内容（プロトコルごとのスパーススナップショット）。
+type autosaveBaseFileDTO struct {
+	Sparse map[string]datastore.SparseSnapshot `json:"sparse,omitempty"`
+
+	// Generation はベースファイルを書くたびに変わる値。差分ファイルは書いた時点のベースの値を持ち、
+	// 一致しない差分（まとめた後に削除しきれなかった古い差分）は復元時に無視する。
+	Generation int64 `json:"generation,omitempty"`
+}
+
+// autosaveDeltaFileDTO は差分ファイルの内容（プロトコルごとの datastore.SnapshotDelta の出力）
+type autosaveDeltaFileDTO struct {
+	Servers    map[string]map[string]interface{} `json:"servers"`
+	Generation int64                             `json:"generation,omitempty"`
+}
+
+// autosaver は実行中のオートセーブ
+type autosaver struct {
+	dir    string
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// 以下はオートセーブのゴルーチンのみが触る
+	baseline   map[string]map[string]interface{}
+	seq        int
+	generation int64
+}
+
+// deltaStore は差分を直接計算できる DataStore
+type deltaStore interface {
+	DeltaSince(baseline map[string]interface{}) map[string]interface{}
+}
+
+// StartAutosave は intervalMs ごとにメモリ内容を dir に保存するオートセーブを開始する。
+// 開始時に全体をベースファイルに書き、以降は変更されたアドレスのみを差分ファイルに書く。
+// 差分ファイルが autosaveCompactEvery 個たまるとベースファイルにまとめる。既に実行中の場合は置き換える。
+func (s *PLCService) StartAutosave(dir string, intervalMs int) error {
+	if dir == "" {
+		return fmt.Errorf("autosave directory is empty")
+	}
+	interval := time.Duration(intervalMs) * time.Millisecond
+	if interval < minAutosaveInterval {
+		return fmt.Errorf("interval must be at least %dms", minAutosaveInterval.Milliseconds())
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// 前のオートセーブが同じディレクトリに書き込まないよう先に止める
+	s.StopAutosave()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	as := &autosaver{dir: dir, cancel: cancel, done: make(chan struct{})}
+	if err := s.compactAutosave(as); err != nil {
+		cancel()
+		return err
+	}
+
+	go func() {
+		defer close(as.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// 停止時は最後の変更まで保存する
+				if err := s.autosaveTick(as); err != nil {
+					s.logger.Warn("autosave failed", "dir", as.dir, "error", err)
+				}
+				return
+			case <-ticker.C:
+				if err := s.autosaveTick(as); err != nil {
+					s.logger.Warn("autosave failed", "dir", as.dir, "error", err)
+				}
+			}
+		}
+	}()
+
+	s.autosaveMu.Lock()
+	s.autosave = as
+	s.autosaveMu.Unlock()
+
+	s.logger.Info("autosave started", "dir", dir, "intervalMs", intervalMs)
+	return nil
+}
+
+// StopAutosave はオートセーブを停止する（実行中でなければ何もしない）
+func (s *PLCService) StopAutosave() {
+	s.autosaveMu.Lock()
+	as := s.autosave
+	s.autosave = nil
+	s.autosaveMu.Unlock()
+	if as == nil {
+		return
+	}
+	as.cancel()
+	<-as.done
+}
+
+// IsAutosaveRunning はオートセーブが実行中かどうかを返す
+func (s *PLCService) IsAutosaveRunning() bool {
+	s.autosaveMu.Lock()
+	defer s.autosaveMu.Unlock()
+	return s.autosave != nil
+}
+
+// collectAutosaveDeltas は各サーバーの前回保存時からの差分を返す
+func (s *PLCService) collectAutosaveDeltas(baseline map[string]map[string]interface{}) (map[string]map[string]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deltas := make(map[string]map[string]interface{})
+	for _, inst := range s.sortedServerInstances() {
+		pt := string(inst.protocolType)
+		delta, err := storeDelta(inst.dataStore, baseline[pt])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pt, err)
+		}
+		if len(delta) > 0 {
+			deltas[pt] = delta
+		}
+	}
+	return deltas, nil
+}
+
+// storeDelta は DataStore の baseline からの差分を返す
+func storeDelta(ds protocol.DataStore, baseline map[string]interface{}) (map[string]interface{}, error) {
+	if d, ok := ds.(deltaStore); ok {
+		return d.DeltaSince(baseline), nil
+	}
+	return datastore.SnapshotDelta(baseline, ds.Snapshot())
+}
+
+// autosaveTick は変更があれば差分ファイルを書き、必要に応じてベースファイルにまとめる
+func (s *PLCService) autosaveTick(as *autosaver) error {
+	deltas, err := s.collectAutosaveDeltas(as.baseline)
+	if err != nil {
+		return err
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(autosaveDeltaFileDTO{Servers: deltas, Generation: as.generation})
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(as.dir, fmt.Sprintf("%s%06d.json", autosaveDeltaPrefix, as.seq+1))
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	as.seq++
+
+	for pt, delta := range deltas {
+		next, err := datastore.ApplySnapshotDelta(as.baseline[pt], delta)
+		if err != nil {
+			return err
+		}
+		as.baseline[pt] = next
+	}
+
+	if as.seq >= autosaveCompactEvery {
+		return s.compactAutosave(as)
+	}
+	return nil
+}
+
+// compactAutosave は現在のメモリ内容をベースファイルに書き、差分ファイルを削除する。
+// ベースファイルはアトミックに置き換え、置き換えた後で差分ファイルを削除する
+// （途中で終了しても、残った古い差分は世代が異なるため復元時に無視される）。
+func (s *PLCService) compactAutosave(as *autosaver) error {
+	s.mu.RLock()
+	baseline := make(map[string]map[string]interface{}, len(s.servers))
+	for _, inst := range s.servers {
+		baseline[string(inst.protocolType)] = inst.dataStore.Snapshot()
+	}
+	s.mu.RUnlock()
+
+	generation := time.Now().UnixNano()
+	if generation <= as.generation {
+		generation = as.generation + 1
+	}
+	base := autosaveBaseFileDTO{Sparse: make(map[string]datastore.SparseSnapshot, len(baseline)), Generation: generation}
+	for pt, snap := range baseline {
+		sparse, err := datastore.SparseFromSnapshot(snap)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pt, err)
+		}
+		base.Sparse[pt] = sparse
+	}
+	data, err := json.Marshal(base)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(as.dir, autosaveBaseFile), data); err != nil {
+		return err
+	}
+	as.generation = generation
+
+	deltaFiles, err := autosaveDeltaFiles(as.dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range deltaFiles {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	as.baseline = baseline
+	as.seq = 0
+	return nil
+}
+
+// autosaveDeltaFiles は dir 内の差分ファイルを書き込み順に返す
+func autosaveDeltaFiles(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, autosaveDeltaPrefix+"*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// RestoreAutosave は dir のベースファイルと差分ファイルからメモリ内容を復元する。
+// プロジェクトに存在しないプロトコルのデータは無視する。ベースファイルがなければ何もしない。
+func (s *PLCService) RestoreAutosave(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, autosaveBaseFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var base autosaveBaseFileDTO
+	if err := json.Unmarshal(data, &base); err != nil {
+		return fmt.Errorf("%s: %w", autosaveBaseFile, err)
+	}
+
+	memory := make(map[string]map[string]interface{}, len(base.Sparse))
+	for pt, sparse := range base.Sparse {
+		snap, err := sparse.Expand()
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", autosaveBaseFile, pt, err)
+		}
+		memory[pt] = snap
+	}
+
+	deltaFiles, err := autosaveDeltaFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range deltaFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var delta autosaveDeltaFileDTO
+		if err := json.Unmarshal(data, &delta); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if delta.Generation != base.Generation {
+			s.logger.Warn("stale autosave delta ignored", "file", filepath.Base(path))
+			continue
+		}
+		for pt, d := range delta.Servers {
+			next, err := datastore.ApplySnapshotDelta(memory[pt], d)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", filepath.Base(path), pt, err)
+			}
+			memory[pt] = next
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for pt, snap := range memory {
+		inst, ok := s.servers[protocol.ProtocolType(pt)]
+		if !ok {
+			s.logger.Warn("autosave data for unknown server ignored", "protocol", pt)
+			continue
+		}
+		if err := inst.dataStore.Restore(snap); err != nil {
+			return fmt.Errorf("%s: %w", pt, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package application
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPLCService_AutosaveDelta(t *testing.T) {
+	svc := newTestService(t)
+	dir := t.TempDir()
+	as := &autosaver{dir: dir}
+	if err := svc.compactAutosave(as); err != nil {
+		t.Fatal(err)
+	}
+
+	// 変更がなければ差分ファイルを書かない
+	if err := svc.autosaveTick(as); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := autosaveDeltaFiles(dir); len(files) != 0 {
+		t.Fatalf("delta files written without changes: %v", files)
+	}
+
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 42, 4242); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.autosaveTick(as); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := autosaveDeltaFiles(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 delta file, got %v", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var delta autosaveDeltaFileDTO
+	if err := json.Unmarshal(data, &delta); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]interface{}{
+		"modbus-tcp": {"holdingRegisters": map[string]interface{}{"42": 4242.0}},
+	}
+	got, _ := json.Marshal(delta.Servers)
+	wantJSON, _ := json.Marshal(want)
+	if string(got) != string(wantJSON) {
+		t.Errorf("delta = %s, want %s", got, wantJSON)
+	}
+
+	if err := svc.WriteBit("modbus-tcp", "coils", 3, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.autosaveTick(as); err != nil {
+		t.Fatal(err)
+	}
+
+	// ベースと差分から現在の状態を再現できる
+	dst := newTestService(t)
+	if err := dst.RestoreAutosave(dir); err != nil {
+		t.Fatalf("RestoreAutosave: %v", err)
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 42, 1); v[0] != 4242 {
+		t.Errorf("holding register 42 = %d, want 4242", v[0])
+	}
+	if v, _ := dst.ReadBits("modbus-tcp", "coils", 3, 1); !v[0] {
+		t.Error("coil 3 not restored")
+	}
+}
+
+func TestPLCService_AutosaveCompaction(t *testing.T) {
+	svc := newTestService(t)
+	dir := t.TempDir()
+	as := &autosaver{dir: dir}
+	if err := svc.compactAutosave(as); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= autosaveCompactEvery; i++ {
+		if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, i); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.autosaveTick(as); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if files, _ := autosaveDeltaFiles(dir); len(files) != 0 {
+		t.Errorf("delta files remain after compaction: %d", len(files))
+	}
+	dst := newTestService(t)
+	if err := dst.RestoreAutosave(dir); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); v[0] != autosaveCompactEvery {
+		t.Errorf("holding register 0 = %d, want %d", v[0], autosaveCompactEvery)
+	}
+}
+
+func TestPLCService_RestoreAutosave_IgnoresStaleDeltas(t *testing.T) {
+	svc := newTestService(t)
+	dir := t.TempDir()
+	as := &autosaver{dir: dir}
+	if err := svc.compactAutosave(as); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.autosaveTick(as); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := autosaveDeltaFiles(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 delta file, got %v", files)
+	}
+	stale, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.compactAutosave(as); err != nil {
+		t.Fatal(err)
+	}
+	// ベースファイルを置き換えた後、差分ファイルを削除する前に終了した状態を再現する
+	if err := os.WriteFile(files[0], stale, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newTestService(t)
+	if err := dst.RestoreAutosave(dir); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); v[0] != 2 {
+		t.Errorf("holding register 0 = %d, want 2 (stale delta must be ignored)", v[0])
+	}
+}
+
+func TestPLCService_StartStopAutosave(t *testing.T) {
+	svc := newTestService(t)
+	dir := filepath.Join(t.TempDir(), "autosave")
+
+	if err := svc.StartAutosave(dir, 10); err == nil {
+		t.Error("expected error for too short interval")
+	}
+	if err := svc.StartAutosave(dir, 60000); err != nil {
+		t.Fatalf("StartAutosave: %v", err)
+	}
+	if !svc.IsAutosaveRunning() {
+		t.Error("IsAutosaveRunning = false after start")
+	}
+	if _, err := os.Stat(filepath.Join(dir, autosaveBaseFile)); err != nil {
+		t.Errorf("base file not written on start: %v", err)
+	}
+
+	// 停止時に最後の変更が保存される
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 7, 77); err != nil {
+		t.Fatal(err)
+	}
+	svc.StopAutosave()
+	if svc.IsAutosaveRunning() {
+		t.Error("IsAutosaveRunning = true after stop")
+	}
+
+	dst := newTestService(t)
+	if err := dst.RestoreAutosave(dir); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 7, 1); v[0] != 77 {
+		t.Errorf("holding register 7 = %d, want 77", v[0])
+	}
+
+	if err := dst.RestoreAutosave(t.TempDir()); err != nil {
+		t.Errorf("RestoreAutosave on empty dir: %v", err)
+	}
+}
+
+func TestPLCService_AutosaveBaseFileIsSparse(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 9000, 0x1234); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := svc.compactAutosave(&autosaver{dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, autosaveBaseFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var base autosaveBaseFileDTO
+	if err := json.Unmarshal(data, &base); err != nil {
+		t.Fatal(err)
+	}
+	runs := base.Sparse["modbus-tcp"]["holdingRegisters"].Runs
+	if len(runs) != 1 || runs[0].Start != 9000 {
+		t.Errorf("holding register runs = %+v", runs)
+	}
+
+	dst := newTestService(t)
+	if err := dst.WriteWord("modbus-tcp", "holdingRegisters", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.RestoreAutosave(dir); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 0, 2); v[1] != 0 {
+		t.Errorf("holding register 1 = %d, want 0 (not in the saved runs)", v[1])
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 9000, 1); v[0] != 0x1234 {
+		t.Errorf("holding register 9000 = %#x, want 0x1234", v[0])
+	}
+}
@@ -0,0 +1,41 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// SetBusy は durationMs ミリ秒の間、全サーバーがマスターからのリクエストに
+// Slave Device Busy (0x06) 例外を返すようにする（0 で解除）。スキャン中などで一時的に応答できない機器の再現用。
+// ビジー応答に対応したサーバーが1つもない場合はエラーを返す。
+func (s *PLCService) SetBusy(durationMs int) error {
+	if durationMs < 0 {
+		return fmt.Errorf("invalid busy duration: %d", durationMs)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	applied := false
+	var lastErr error
+	for _, inst := range s.servers {
+		busy, ok := inst.server.(protocol.BusySimulator)
+		if !ok {
+			continue
+		}
+		if err := busy.SetBusy(time.Duration(durationMs) * time.Millisecond); err != nil {
+			lastErr = err
+			continue
+		}
+		applied = true
+	}
+	if !applied {
+		if lastErr != nil {
+			return lastErr
+		}
+		return fmt.Errorf("busy simulation not supported by any server")
+	}
+	return nil
+}
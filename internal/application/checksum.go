@@ -0,0 +1,91 @@
+package application
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrChecksumMismatch はプロジェクトファイルのチェックサムが内容と一致しない場合のエラー
+var ErrChecksumMismatch = errors.New("project checksum mismatch")
+
+// ImportOptions はプロジェクトインポート時のオプション
+type ImportOptions struct {
+	// SkipChecksum が true の場合はチェックサムを検証しない（手で編集したファイル向け）
+	SkipChecksum bool
+
+	// SkipScripts が true の場合は有効なスクリプトも自動開始しない（有効フラグはそのまま保持する）
+	SkipScripts bool
+}
+
+// ComputeProjectChecksum は Checksum フィールドを除いたプロジェクトデータの SHA-256 を返す。
+// JSON に変換した内容を正規形（projectChecksumJSON）にして計算するため、インデントやキーの順序には影響されない。
+func ComputeProjectChecksum(data *ProjectDataDTO) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return projectChecksumJSON(b)
+}
+
+// projectChecksumJSON はプロジェクトファイルの JSON から checksum を除いた正規形の SHA-256 を返す。
+// 数値は UseNumber で元の表記のまま扱い、LINT/ULINT の 2^53 を超える値も丸めない。
+// ファイルに書かれている内容だけから計算するため、後から DTO に追加したフィールドの影響も受けない。
+func projectChecksumJSON(raw []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return "", err
+	}
+	delete(doc, "checksum")
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyProjectChecksum はチェックサムが設定されている場合に内容と一致するか検証する。
+// チェックサムのない（古い形式の）ファイルは検証せずに受け入れる。
+// ファイルから読み込んだ場合は、書かれている内容のまま検証する VerifyProjectChecksumJSON を使う。
+func VerifyProjectChecksum(data *ProjectDataDTO) error {
+	if data.Checksum == "" {
+		return nil
+	}
+	sum, err := ComputeProjectChecksum(data)
+	if err != nil {
+		return err
+	}
+	return checkProjectChecksum(data.Checksum, sum)
+}
+
+// VerifyProjectChecksumJSON はプロジェクトファイルの JSON のチェックサムを検証する。
+// チェックサムのない（古い形式の）ファイルは検証せずに受け入れる。
+func VerifyProjectChecksumJSON(raw []byte) error {
+	var header struct {
+		Checksum string `json:"checksum"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return err
+	}
+	if header.Checksum == "" {
+		return nil
+	}
+	sum, err := projectChecksumJSON(raw)
+	if err != nil {
+		return err
+	}
+	return checkProjectChecksum(header.Checksum, sum)
+}
+
+func checkProjectChecksum(want, got string) error {
+	if want != got {
+		return fmt.Errorf("%w: ファイルが破損しているか編集されています（検証をスキップしてインポートできます）", ErrChecksumMismatch)
+	}
+	return nil
+}
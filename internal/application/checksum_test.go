@@ -0,0 +1,152 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// exportedProjectJSON はスクリプトと変数を持つプロジェクトをエクスポートし、ファイルと同じ形式の JSON を返す
+func exportedProjectJSON(t *testing.T) []byte {
+	t.Helper()
+	svc := newTestService(t)
+	if _, err := svc.CreateScript("counter", "var x = 1;", 1000); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateVariable("Speed", "INT", 42); err != nil {
+		t.Fatal(err)
+	}
+
+	project := svc.ExportProject()
+	if !strings.HasPrefix(project.Checksum, "sha256:") {
+		t.Fatalf("expected sha256 checksum, got %q", project.Checksum)
+	}
+	data, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestPLCService_ImportProject_ChecksumOK(t *testing.T) {
+	data := exportedProjectJSON(t)
+
+	var project ProjectDataDTO
+	if err := json.Unmarshal(data, &project); err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestService(t)
+	if err := svc.ImportProject(&project); err != nil {
+		t.Fatalf("ImportProject of unmodified export failed: %v", err)
+	}
+	if len(svc.GetScripts()) != 1 {
+		t.Errorf("expected 1 script after import, got %d", len(svc.GetScripts()))
+	}
+}
+
+func TestPLCService_ImportProject_ChecksumMismatch(t *testing.T) {
+	data := exportedProjectJSON(t)
+	tampered := strings.Replace(string(data), "var x = 1;", "var x = 2;", 1)
+
+	var project ProjectDataDTO
+	if err := json.Unmarshal([]byte(tampered), &project); err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestService(t)
+	if err := svc.ImportProject(&project); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if len(svc.GetScripts()) != 0 {
+		t.Error("tampered project must not be imported")
+	}
+
+	// 検証をスキップすれば編集後の内容でインポートできる
+	if err := svc.ImportProjectWithOptions(&project, ImportOptions{SkipChecksum: true}); err != nil {
+		t.Fatalf("ImportProjectWithOptions(SkipChecksum) failed: %v", err)
+	}
+	if scripts := svc.GetScripts(); len(scripts) != 1 || scripts[0].Code != "var x = 2;" {
+		t.Errorf("unexpected scripts after skip-checksum import: %+v", scripts)
+	}
+}
+
+func TestVerifyProjectChecksum_NoChecksum(t *testing.T) {
+	if err := VerifyProjectChecksum(&ProjectDataDTO{Scripts: []*ScriptDTO{}}); err != nil {
+		t.Errorf("project without checksum should be accepted, got %v", err)
+	}
+}
+
+func TestExportProject_ChecksumCompatibleWithOlderFiles(t *testing.T) {
+	// 後から追加した項目が既定値の場合は出力しないことで、
+	// その項目がなかった頃に保存したファイルのチェックサムも一致する
+	data := exportedProjectJSON(t)
+	if strings.Contains(string(data), `"isLibrary"`) {
+		t.Errorf("isLibrary must be omitted for plain scripts:\n%s", data)
+	}
+}
+
+func TestPLCService_ImportProjectJSON_LargeIntegers(t *testing.T) {
+	const ulint uint64 = 1<<53 + 1
+	const lint int64 = -(1<<53 + 1)
+
+	svc := newTestService(t)
+	if _, err := svc.CreateVariable("Big", "ULINT", ulint); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateVariable("Negative", "LINT", lint); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.MarshalIndent(svc.ExportProject(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "9007199254740993") {
+		t.Fatalf("export should contain the exact ULINT value:\n%s", data)
+	}
+
+	imported := newTestService(t)
+	if err := imported.ImportProjectJSON(data, ImportOptions{}); err != nil {
+		t.Fatalf("ImportProjectJSON: %v", err)
+	}
+	values := map[string]interface{}{}
+	for _, v := range imported.GetVariables() {
+		values[v.Name] = v.Value
+	}
+	// GetVariables は LINT/ULINT を精度を保つため文字列で返す
+	if values["Big"] != "9007199254740993" {
+		t.Errorf("ULINT value = %v, want %d", values["Big"], ulint)
+	}
+	if values["Negative"] != "-9007199254740993" {
+		t.Errorf("LINT value = %v, want %d", values["Negative"], lint)
+	}
+
+	// DTO にデコードしてからのインポートでもチェックサムが一致する
+	var project ProjectDataDTO
+	if err := json.Unmarshal(data, &project); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyProjectChecksum(&project); err != nil {
+		t.Errorf("VerifyProjectChecksum after decode: %v", err)
+	}
+}
+
+func TestVerifyProjectChecksumJSON_IgnoresFormattingAndKeyOrder(t *testing.T) {
+	data := exportedProjectJSON(t)
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	compact, err := json.Marshal(doc) // キーの順序と書式が変わる
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyProjectChecksumJSON(compact); err != nil {
+		t.Errorf("reformatted project should verify: %v", err)
+	}
+
+	tampered := strings.Replace(string(data), `"Speed"`, `"Speed2"`, 1)
+	if err := VerifyProjectChecksumJSON([]byte(tampered)); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch, got %v", err)
+	}
+}
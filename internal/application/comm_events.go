@@ -0,0 +1,49 @@
+package application
+
+import (
+	"fmt"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// GetCommEventCounter はマスターからのリクエストのうち正常に応答した数（Modbus FC 0x0B のイベントカウンタ）を返す。
+// イベントカウンタを持たないサーバーの場合はエラーを返す。
+func (s *PLCService) GetCommEventCounter(protocolType string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counter, err := s.commEventCounter(protocolType)
+	if err != nil {
+		return 0, err
+	}
+	n, err := counter.CommEventCount()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// ClearCommEventCounter はイベントカウンタを 0 に戻す（FC 0x08 サブファンクション 0x0A と同じ）
+func (s *PLCService) ClearCommEventCounter(protocolType string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counter, err := s.commEventCounter(protocolType)
+	if err != nil {
+		return err
+	}
+	return counter.ClearCommEventCounter()
+}
+
+// commEventCounter はサーバーのイベントカウンタを返す。呼び出し元で s.mu をロックしていること。
+func (s *PLCService) commEventCounter(protocolType string) (protocol.CommEventCounter, error) {
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	counter, ok := inst.server.(protocol.CommEventCounter)
+	if !ok {
+		return nil, fmt.Errorf("comm event counter not supported: %s", protocolType)
+	}
+	return counter, nil
+}
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// jsonSchemaDraft は生成する JSON Schema のバージョン
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// buildConfigJSONSchema は ConfigField の定義から1バリアント分の JSON Schema を組み立てる
+func buildConfigJSONSchema(title string, fields []protocol.ConfigField) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	required := []string{}
+	var conditionals []interface{}
+
+	for _, f := range fields {
+		properties[f.Name] = configFieldToJSONSchema(f)
+		if !f.Required {
+			continue
+		}
+		if f.Condition == nil {
+			required = append(required, f.Name)
+			continue
+		}
+		// 表示条件付きのフィールドは条件が成立した場合のみ必須とする
+		conditionals = append(conditionals, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{
+					f.Condition.Field: map[string]interface{}{"const": f.Condition.Value},
+				},
+				"required": []string{f.Condition.Field},
+			},
+			"then": map[string]interface{}{
+				"required": []string{f.Name},
+			},
+		})
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if title != "" {
+		schema["title"] = title
+	}
+	if len(conditionals) > 0 {
+		schema["allOf"] = conditionals
+	}
+	return schema
+}
+
+// configFieldToJSONSchema は1フィールド分のプロパティ定義を作成する
+func configFieldToJSONSchema(f protocol.ConfigField) map[string]interface{} {
+	prop := map[string]interface{}{}
+	if f.Label != "" {
+		prop["title"] = f.Label
+	}
+	if f.Description != "" {
+		prop["description"] = f.Description
+	}
+	if f.Category != "" {
+		prop["x-category"] = f.Category
+	}
+
+	switch f.Type {
+	case "number":
+		prop["type"] = "integer"
+		if f.Min != nil {
+			prop["minimum"] = *f.Min
+		}
+		if f.Max != nil {
+			prop["maximum"] = *f.Max
+		}
+	case "checkbox", "boolean":
+		prop["type"] = "boolean"
+	case "select":
+		// 選択肢の値は文字列で定義されているが、デフォルト値が数値の場合は数値として扱う
+		if isNumericDefault(f.Default) {
+			if enum, ok := optionsAsIntegers(f.Options); ok {
+				prop["type"] = "integer"
+				prop["enum"] = enum
+				break
+			}
+		}
+		prop["type"] = "string"
+		enum := make([]string, len(f.Options))
+		for i, o := range f.Options {
+			enum[i] = o.Value
+		}
+		prop["enum"] = enum
+	default:
+		// text, serialport など
+		prop["type"] = "string"
+	}
+
+	if f.Default != nil {
+		prop["default"] = f.Default
+	}
+	return prop
+}
+
+func isNumericDefault(v interface{}) bool {
+	switch v.(type) {
+	case int, int32, int64, float64:
+		return true
+	}
+	return false
+}
+
+func optionsAsIntegers(options []protocol.FieldOption) ([]int, bool) {
+	result := make([]int, len(options))
+	for i, o := range options {
+		n, err := strconv.Atoi(o.Value)
+		if err != nil {
+			return nil, false
+		}
+		result[i] = n
+	}
+	return result, true
+}
+
+// marshalConfigJSONSchema はプロトコル全体の JSON Schema を生成する。
+// バリアントが1つの場合はそのスキーマを、複数の場合は oneOf で列挙したスキーマを返す。
+func marshalConfigJSONSchema(factory protocol.ServerFactory) (json.RawMessage, error) {
+	variants := factory.ConfigVariants()
+	// バリアントがない場合（OPC UA 等）は空 ID で1つ生成してフィールドを取得する
+	if len(variants) == 0 {
+		variants = []protocol.ConfigVariant{{ID: "", DisplayName: factory.DisplayName()}}
+	}
+
+	var schema map[string]interface{}
+	if len(variants) == 1 {
+		schema = buildConfigJSONSchema(factory.DisplayName(), factory.GetConfigFields(variants[0].ID))
+	} else {
+		oneOf := make([]interface{}, len(variants))
+		for i, v := range variants {
+			vs := buildConfigJSONSchema(v.DisplayName, factory.GetConfigFields(v.ID))
+			vs["x-variant"] = v.ID
+			oneOf[i] = vs
+		}
+		schema = map[string]interface{}{
+			"title": factory.DisplayName(),
+			"oneOf": oneOf,
+		}
+	}
+	schema["$schema"] = jsonSchemaDraft
+	schema["$id"] = "urn:plcsimulator:config:" + string(factory.ProtocolType())
+
+	return json.Marshal(schema)
+}
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// transportChanged は設定の変更で待ち受け（通信路）の設定が変わるかどうかを返す。
+// ファクトリーが TransportFieldsProvider を実装していないか一覧を返さない場合は、いずれかのフィールドが変われば true を返す。
+func transportChanged(factory protocol.ServerFactory, variant string, oldConfig, newConfig protocol.ProtocolConfig) bool {
+	oldSettings := factory.ConfigToMap(oldConfig)
+	newSettings := factory.ConfigToMap(newConfig)
+
+	var fields []string
+	if provider, ok := factory.(protocol.TransportFieldsProvider); ok {
+		fields = provider.TransportFields(variant)
+	}
+	if fields == nil {
+		return !reflect.DeepEqual(oldSettings, newSettings)
+	}
+	for _, name := range fields {
+		if !reflect.DeepEqual(oldSettings[name], newSettings[name]) {
+			return true
+		}
+	}
+	return false
+}
+
+// recreateServerLocked は config でサーバーを作り直す（データストアはそのまま使う）。
+// 古いサーバーが実行中だった場合は停止し、新しいサーバーを起動する（s.mu のロック必須）。
+func (s *PLCService) recreateServerLocked(inst *serverInstance, config protocol.ProtocolConfig) error {
+	server, err := inst.factory.CreateServer(config, inst.dataStore)
+	if err != nil {
+		return err
+	}
+
+	running := inst.server != nil && inst.server.Status() == protocol.StatusRunning
+	if running {
+		if err := inst.server.Stop(); err != nil {
+			return fmt.Errorf("failed to stop server: %w", err)
+		}
+		s.notifyServerStatusLocked(inst)
+	}
+
+	if s.hostGrpcServer != nil {
+		type hostAddrSetter interface{ SetHostGrpcAddr(addr string) }
+		if setter, ok := server.(hostAddrSetter); ok {
+			setter.SetHostGrpcAddr(s.hostGrpcServer.Addr())
+		}
+	}
+	inst.server = server
+	inst.config = config
+	defer s.notifyServerStatusLocked(inst)
+	if s.eventEmitter != nil {
+		s.setEmitterToServerInstance(inst)
+	}
+	if !running {
+		return nil
+	}
+
+	s.logger.Info("restarting server for transport settings change", "protocol", inst.protocolType)
+	if err := s.checkServerPort(inst); err != nil {
+		return err
+	}
+	if err := server.Start(context.Background()); err != nil {
+		return fmt.Errorf("サーバーの起動に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// applyServerConfigLocked は newConfig をサーバーに反映する。
+// 通信路の設定が変わる場合はサーバーを作り直し、それ以外は実行中のまま UpdateConfig で反映する（s.mu のロック必須）。
+func (s *PLCService) applyServerConfigLocked(inst *serverInstance, newConfig protocol.ProtocolConfig) error {
+	if transportChanged(inst.factory, inst.variant, inst.config, newConfig) {
+		if err := s.recreateServerLocked(inst, newConfig); err != nil {
+			return err
+		}
+	} else if err := inst.server.UpdateConfig(newConfig); err != nil {
+		return err
+	}
+
+	inst.config = newConfig
+	go s.emitServerChanged()
+	return nil
+}
+
+// updateServerSettingsLocked は設定の一部のフィールドを書き換えてサーバーに反映する。
+// 変更はサーバー設定としてプロジェクトに保存される。
+// フィールドがプロトコルの設定に含まれない場合は feature をサポートしないものとしてエラーを返す（s.mu のロック必須）。
+func (s *PLCService) updateServerSettingsLocked(inst *serverInstance, feature string, fields map[string]interface{}) error {
+	settings := inst.factory.ConfigToMap(inst.config)
+	for name, value := range fields {
+		if _, ok := settings[name]; !ok {
+			return fmt.Errorf("protocol does not support %s", feature)
+		}
+		settings[name] = value
+	}
+
+	newConfig, err := inst.factory.MapToConfig(inst.variant, settings)
+	if err != nil {
+		return err
+	}
+	return s.applyServerConfigLocked(inst, newConfig)
+}
+
+// updateServerSettingPairLocked は "key=value" のカンマ区切りで表す設定フィールドのうち key の値を書き換える。
+// value が空の場合は key を削除する。変更はサーバー設定としてプロジェクトに保存される（s.mu のロック必須）。
+func (s *PLCService) updateServerSettingPairLocked(inst *serverInstance, feature, field, key, value string) error {
+	current, ok := inst.factory.ConfigToMap(inst.config)[field]
+	if !ok {
+		return fmt.Errorf("protocol does not support %s", feature)
+	}
+	list, _ := current.(string)
+
+	var pairs []string
+	found := false
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		if k, _, _ := strings.Cut(pair, "="); strings.TrimSpace(k) != key {
+			pairs = append(pairs, pair)
+			continue
+		}
+		found = true
+		if value != "" {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	if !found && value != "" {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	return s.updateServerSettingsLocked(inst, feature, map[string]interface{}{field: strings.Join(pairs, ",")})
+}
+
+// settingEnabled は select フィールドの真偽値（"true"/"false" または bool）を返す
+func settingEnabled(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
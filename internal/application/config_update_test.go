@@ -0,0 +1,151 @@
+package application
+
+import (
+	"testing"
+
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+)
+
+// currentServer はサーバーインスタンスのサーバーとデータストアを返す
+func currentServer(svc *PLCService, protocolType string) (protocol.ProtocolServer, protocol.DataStore) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	inst := svc.servers[protocol.ProtocolType(protocolType)]
+	return inst.server, inst.dataStore
+}
+
+func TestPLCService_UpdateServerConfig_RestartsOnlyOnTransportChange(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	server, store := currentServer(svc, "modbus-tcp")
+
+	// 待ち受けに関わらない設定は実行中のまま反映し、サーバーを作り直さない
+	cfg := svc.GetServerConfig("modbus-tcp")
+	cfg.Settings["readOnlyAreas"] = "coils"
+	if err := svc.UpdateServerConfig(cfg); err != nil {
+		t.Fatalf("UpdateServerConfig(readOnlyAreas): %v", err)
+	}
+	gotServer, gotStore := currentServer(svc, "modbus-tcp")
+	if gotServer != server || gotStore != store {
+		t.Error("non-transport change should keep the server instance and data store")
+	}
+	if gotServer.Status() != protocol.StatusRunning {
+		t.Errorf("status = %v, want running", gotServer.Status())
+	}
+	if area, _ := store.GetArea("coils"); !area.ReadOnly {
+		t.Error("readOnlyAreas should be applied while running")
+	}
+
+	// ポートの変更はサーバーを作り直し、新しい設定で起動し直す
+	cfg = svc.GetServerConfig("modbus-tcp")
+	cfg.Settings["tcpPort"] = testutil.FreeTCPPort(t)
+	if err := svc.UpdateServerConfig(cfg); err != nil {
+		t.Fatalf("UpdateServerConfig(tcpPort): %v", err)
+	}
+	gotServer, gotStore = currentServer(svc, "modbus-tcp")
+	if gotServer == server {
+		t.Error("port change should recreate the server")
+	}
+	if gotStore != store {
+		t.Error("port change should keep the data store")
+	}
+	if server.Status() != protocol.StatusStopped {
+		t.Errorf("old server status = %v, want stopped", server.Status())
+	}
+	if gotServer.Status() != protocol.StatusRunning {
+		t.Errorf("new server status = %v, want running", gotServer.Status())
+	}
+	if got := svc.GetServerConfig("modbus-tcp").Settings["tcpPort"]; got != cfg.Settings["tcpPort"] {
+		t.Errorf("tcpPort = %v, want %v", got, cfg.Settings["tcpPort"])
+	}
+}
+
+func TestPLCService_UpdateServerConfig_VariantChangeWhileRunning(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	err := svc.UpdateServerConfig(&ServerConfigDTO{ProtocolType: "modbus-tcp", Variant: "rtu", Settings: map[string]interface{}{}})
+	if err == nil {
+		t.Error("variant change while running should fail")
+	}
+}
+
+func TestPLCService_SetRateLimit_StoredInServerSettings(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	server, _ := currentServer(svc, "modbus-tcp")
+
+	if err := svc.SetRateLimit("modbus-tcp", 20, true); err != nil {
+		t.Fatalf("SetRateLimit: %v", err)
+	}
+	settings := svc.GetServerConfig("modbus-tcp").Settings
+	if settings["rateLimit"] != 20 || settings["rateLimitBusy"] != "true" {
+		t.Errorf("settings = %v, want rateLimit=20 rateLimitBusy=true", settings)
+	}
+	// 待ち受けに関わらない設定なので実行中のサーバーに反映される
+	gotServer, _ := currentServer(svc, "modbus-tcp")
+	if gotServer != server {
+		t.Error("SetRateLimit should not recreate the server")
+	}
+	if cfg := gotServer.Config().(*fakeConfig); cfg.settings["rateLimit"] != 20 {
+		t.Errorf("server config rateLimit = %v, want 20", cfg.settings["rateLimit"])
+	}
+
+	// 設定項目を持たないプロトコルは未対応としてエラーにする
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetRateLimit("modbus-rtu", 20, false); err == nil {
+		t.Error("expected error for protocol without rate limit settings")
+	}
+}
+
+func TestPLCService_SetMaxReadQuantity_StoredInServerSettings(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.SetMaxReadQuantity("modbus-tcp", 3, 64); err != nil {
+		t.Fatalf("SetMaxReadQuantity: %v", err)
+	}
+	if err := svc.SetUnitMaxReadQuantity("modbus-tcp", 2, 3, 16); err != nil {
+		t.Fatalf("SetUnitMaxReadQuantity: %v", err)
+	}
+	if err := svc.SetMaxReadQuantity("modbus-tcp", 3, 100); err != nil {
+		t.Fatalf("SetMaxReadQuantity: %v", err)
+	}
+	if got := svc.GetServerConfig("modbus-tcp").Settings["maxReadQuantities"]; got != "3=100,2:3=16" {
+		t.Errorf("maxReadQuantities = %v, want 3=100,2:3=16", got)
+	}
+
+	// 0 以下で設定を削除する
+	if err := svc.SetMaxReadQuantity("modbus-tcp", 3, 0); err != nil {
+		t.Fatalf("SetMaxReadQuantity: %v", err)
+	}
+	if got := svc.GetServerConfig("modbus-tcp").Settings["maxReadQuantities"]; got != "2:3=16" {
+		t.Errorf("maxReadQuantities = %v, want 2:3=16", got)
+	}
+}
+
+func TestPLCService_SetAutoReconnect_StoredInServerSettings(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.SetAutoReconnect("modbus-rtu", true); err != nil {
+		t.Fatalf("SetAutoReconnect: %v", err)
+	}
+	if got := svc.GetServerConfig("modbus-rtu").Settings["autoReconnect"]; got != "true" {
+		t.Errorf("autoReconnect = %v, want true", got)
+	}
+
+	// TCP にはシリアルポートがないので未対応としてエラーにする
+	if err := svc.SetAutoReconnect("modbus-tcp", true); err == nil {
+		t.Error("expected error for protocol without auto reconnect")
+	}
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// minDemoInterval はデモモードの最短更新間隔
+const minDemoInterval = 50 * time.Millisecond
+
+// demoMode は実行中のデモモード（ランダム値の定期書き込み）
+type demoMode struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartDemoMode は指定範囲のメモリに intervalMs ごとにランダムな値を書き込むデモモードを開始する。
+// UI の表示を動かすためのもので、スクリプトとは独立して動作する。既に実行中の場合は置き換える。
+func (s *PLCService) StartDemoMode(protocolType, area string, start, count, intervalMs int) error {
+	interval := time.Duration(intervalMs) * time.Millisecond
+	if interval < minDemoInterval {
+		return fmt.Errorf("interval must be at least %dms", minDemoInterval.Milliseconds())
+	}
+	if count <= 0 || count > memoryReadChunk {
+		return fmt.Errorf("count must be between 1 and %d", memoryReadChunk)
+	}
+
+	s.mu.RLock()
+	inst, err := s.getServerInstance(protocolType)
+	if err == nil {
+		err = checkDemoRange(inst, area, start, count)
+	}
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	demo := &demoMode{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(demo.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.randomizeRange(protocolType, area, start, count); err != nil {
+					s.logger.Warn("demo mode stopped", "protocol", protocolType, "area", area, "error", err)
+					return
+				}
+			}
+		}
+	}()
+
+	s.demoMu.Lock()
+	prev := s.demo
+	s.demo = demo
+	s.demoMu.Unlock()
+	prev.stop()
+
+	s.logger.Info("demo mode started", "protocol", protocolType, "area", area, "start", start, "count", count, "intervalMs", intervalMs)
+	return nil
+}
+
+// StopDemoMode はデモモードを停止する（実行中でなければ何もしない）
+func (s *PLCService) StopDemoMode() {
+	s.demoMu.Lock()
+	demo := s.demo
+	s.demo = nil
+	s.demoMu.Unlock()
+	demo.stop()
+}
+
+// stop はデモモードのゴルーチンを停止して終了を待つ（nil の場合は何もしない）
+func (d *demoMode) stop() {
+	if d == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+}
+
+// IsDemoModeRunning はデモモードが実行中かどうかを返す
+func (s *PLCService) IsDemoModeRunning() bool {
+	s.demoMu.Lock()
+	defer s.demoMu.Unlock()
+	if s.demo == nil {
+		return false
+	}
+	select {
+	case <-s.demo.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// checkDemoRange はエリアと範囲を検証する（ロック取得済みであること）。
+// エリアがロックされた場合も書き込みを止めるため、書き込みのたびに呼ぶ。
+func checkDemoRange(inst *serverInstance, area string, start, count int) error {
+	areaInfo, err := findMemoryArea(inst.dataStore, area)
+	if err != nil {
+		return err
+	}
+	if err := checkAreaUnlocked(inst, area); err != nil {
+		return err
+	}
+	if start < 0 || start+count > int(areaInfo.Size) {
+		return fmt.Errorf("range %d-%d is out of area %s (size %d)", start, start+count-1, area, areaInfo.Size)
+	}
+	return nil
+}
+
+// randomizeRange は指定範囲にランダムな値を書き込む
+func (s *PLCService) randomizeRange(protocolType, area string, start, count int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if err := checkDemoRange(inst, area, start, count); err != nil {
+		return err
+	}
+	areaInfo, _ := findMemoryArea(inst.dataStore, area)
+
+	if areaInfo.IsBit {
+		values := make([]bool, count)
+		for i := range values {
+			values[i] = rand.IntN(2) == 1
+		}
+		err = inst.dataStore.WriteBits(area, uint32(start), values)
+	} else {
+		values := make([]uint16, count)
+		for i := range values {
+			values[i] = uint16(rand.UintN(1 << 16))
+		}
+		err = inst.dataStore.WriteWords(area, uint32(start), values)
+	}
+	if err != nil {
+		return err
+	}
+
+	if inst.changeListener != nil {
+		listener := inst.changeListener
+		go func() {
+			for addr := uint32(start); addr < uint32(start+count); addr++ {
+				if areaInfo.IsBit {
+					listener.SyncHostBitWriteToVariable(area, addr)
+				} else {
+					listener.SyncHostWordWriteToVariable(area, addr)
+				}
+			}
+		}()
+	}
+	s.notifyStoreWrite(inst.protocolType, area, uint32(start), count)
+	return nil
+}
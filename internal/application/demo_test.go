@@ -0,0 +1,84 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPLCService_DemoMode(t *testing.T) {
+	svc := newTestService(t)
+	t.Cleanup(svc.StopDemoMode)
+
+	read := func() []int {
+		t.Helper()
+		vals, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 10, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vals
+	}
+	waitChange := func(prev []int) []int {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if cur := read(); !reflect.DeepEqual(cur, prev) {
+				return cur
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("values did not change from %v", prev)
+		return nil
+	}
+
+	if err := svc.StartDemoMode("modbus-tcp", "holdingRegisters", 10, 8, 50); err != nil {
+		t.Fatalf("StartDemoMode: %v", err)
+	}
+	if !svc.IsDemoModeRunning() {
+		t.Error("IsDemoModeRunning = false after start")
+	}
+
+	// 2 周期分、値が変化し続けることを確認
+	first := waitChange(make([]int, 8))
+	waitChange(first)
+
+	// 範囲外には書き込まない
+	if v, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 18, 1); v[0] != 0 {
+		t.Errorf("register outside range changed: %d", v[0])
+	}
+
+	svc.StopDemoMode()
+	if svc.IsDemoModeRunning() {
+		t.Error("IsDemoModeRunning = true after stop")
+	}
+	stopped := read()
+	time.Sleep(150 * time.Millisecond)
+	if cur := read(); !reflect.DeepEqual(cur, stopped) {
+		t.Errorf("values changed after StopDemoMode: %v -> %v", stopped, cur)
+	}
+}
+
+func TestPLCService_StartDemoMode_Validation(t *testing.T) {
+	svc := newTestService(t)
+	t.Cleanup(svc.StopDemoMode)
+
+	tests := []struct {
+		name                     string
+		protocolType, area       string
+		start, count, intervalMs int
+	}{
+		{"unknown server", "modbus-rtu", "holdingRegisters", 0, 1, 100},
+		{"unknown area", "modbus-tcp", "bogus", 0, 1, 100},
+		{"out of range", "modbus-tcp", "holdingRegisters", 9998, 2, 100},
+		{"zero count", "modbus-tcp", "holdingRegisters", 0, 0, 100},
+		{"interval too short", "modbus-tcp", "holdingRegisters", 0, 1, 1},
+	}
+	for _, tt := range tests {
+		if err := svc.StartDemoMode(tt.protocolType, tt.area, tt.start, tt.count, tt.intervalMs); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+	if svc.IsDemoModeRunning() {
+		t.Error("demo mode started despite validation errors")
+	}
+}
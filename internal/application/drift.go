@@ -0,0 +1,198 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"modbus_simulator/internal/domain/datastore"
+
+	"github.com/google/uuid"
+)
+
+// minDriftInterval はドリフト動作の最短更新間隔
+const minDriftInterval = 50 * time.Millisecond
+
+// driftBehavior は実行中のドリフト動作（ワードを範囲内でランダムウォークさせる）
+type driftBehavior struct {
+	DriftBehaviorDTO
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// AddDriftBehavior は area[address] のワードを intervalMs ごとに ±stepMax 以内で変化させ、
+// [min, max] に収めるドリフト動作（センサー値の揺らぎの模擬）を追加し、その ID を返す。
+// スクリプトなしで動作し、プロジェクトのエクスポート/インポートで保存・復元される。
+func (s *PLCService) AddDriftBehavior(protocolType, area string, address, min, max, stepMax, intervalMs int) (string, error) {
+	dto := DriftBehaviorDTO{
+		ID:           uuid.New().String(),
+		ProtocolType: protocolType,
+		MemoryArea:   area,
+		Address:      address,
+		Min:          min,
+		Max:          max,
+		StepMax:      stepMax,
+		IntervalMs:   intervalMs,
+	}
+
+	s.mu.RLock()
+	err := s.checkDriftBehavior(dto)
+	s.mu.RUnlock()
+	if err != nil {
+		return "", err
+	}
+
+	s.startDriftBehavior(dto)
+	s.logger.Info("drift behavior added", "id", dto.ID, "protocol", protocolType, "area", area, "address", address,
+		"min", min, "max", max, "stepMax", stepMax, "intervalMs", intervalMs)
+	return dto.ID, nil
+}
+
+// RemoveDriftBehavior はドリフト動作を停止して削除する
+func (s *PLCService) RemoveDriftBehavior(id string) error {
+	s.driftMu.Lock()
+	var removed *driftBehavior
+	for i, d := range s.drifts {
+		if d.ID == id {
+			removed = d
+			s.drifts = append(s.drifts[:i], s.drifts[i+1:]...)
+			break
+		}
+	}
+	s.driftMu.Unlock()
+
+	if removed == nil {
+		return fmt.Errorf("drift behavior not found: %s", id)
+	}
+	removed.stop()
+	return nil
+}
+
+// GetDriftBehaviors はドリフト動作の一覧を追加順で返す
+func (s *PLCService) GetDriftBehaviors() []DriftBehaviorDTO {
+	s.driftMu.Lock()
+	defer s.driftMu.Unlock()
+
+	result := make([]DriftBehaviorDTO, len(s.drifts))
+	for i, d := range s.drifts {
+		result[i] = d.DriftBehaviorDTO
+	}
+	return result
+}
+
+// clearDriftBehaviors は全てのドリフト動作を停止して削除する。
+// ドリフト動作は s.mu を取得するため、s.mu を保持したまま呼んではならない。
+func (s *PLCService) clearDriftBehaviors() {
+	s.driftMu.Lock()
+	drifts := s.drifts
+	s.drifts = nil
+	s.driftMu.Unlock()
+
+	for _, d := range drifts {
+		d.stop()
+	}
+}
+
+// checkDriftBehavior はドリフト動作の設定と対象アドレスを検証する（ロック取得済みであること）
+func (s *PLCService) checkDriftBehavior(dto DriftBehaviorDTO) error {
+	if time.Duration(dto.IntervalMs)*time.Millisecond < minDriftInterval {
+		return fmt.Errorf("interval must be at least %dms", minDriftInterval.Milliseconds())
+	}
+	if dto.Min < 0 || dto.Max > 0xFFFF || dto.Min > dto.Max {
+		return fmt.Errorf("invalid range: min=%d max=%d (must satisfy 0 <= min <= max <= 65535)", dto.Min, dto.Max)
+	}
+	if dto.StepMax < 1 {
+		return fmt.Errorf("stepMax must be at least 1")
+	}
+
+	inst, err := s.getServerInstance(dto.ProtocolType)
+	if err != nil {
+		return err
+	}
+	areaInfo, err := findMemoryArea(inst.dataStore, dto.MemoryArea)
+	if err != nil {
+		return err
+	}
+	if areaInfo.IsBit {
+		return fmt.Errorf("%w: drift requires a word area", datastore.ErrTypeMismatch)
+	}
+	if dto.Address < 0 || dto.Address >= int(areaInfo.Size) {
+		return fmt.Errorf("%w: address %d for area %s (size %d)", datastore.ErrAddressOutOfRange, dto.Address, dto.MemoryArea, areaInfo.Size)
+	}
+	return nil
+}
+
+// startDriftBehavior は検証済みのドリフト動作のゴルーチンを開始して一覧に加える
+func (s *PLCService) startDriftBehavior(dto DriftBehaviorDTO) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &driftBehavior{DriftBehaviorDTO: dto, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(time.Duration(dto.IntervalMs) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.driftStep(dto); err != nil {
+					s.logger.Warn("drift behavior stopped", "id", dto.ID, "protocol", dto.ProtocolType, "area", dto.MemoryArea, "error", err)
+					return
+				}
+			}
+		}
+	}()
+
+	s.driftMu.Lock()
+	s.drifts = append(s.drifts, d)
+	s.driftMu.Unlock()
+}
+
+// stop はドリフト動作のゴルーチンを停止して終了を待つ
+func (d *driftBehavior) stop() {
+	d.cancel()
+	<-d.done
+}
+
+// driftStep は現在値を ±StepMax 以内でランダムに変化させ、[Min, Max] に収めて書き込む
+func (s *PLCService) driftStep(dto DriftBehaviorDTO) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(dto.ProtocolType)
+	if err != nil {
+		return err
+	}
+	current, err := inst.dataStore.ReadWord(dto.MemoryArea, uint32(dto.Address))
+	if err != nil {
+		return err
+	}
+	next := nextDriftValue(int(current), dto.Min, dto.Max, dto.StepMax)
+	if err := inst.dataStore.WriteWord(dto.MemoryArea, uint32(dto.Address), uint16(next)); err != nil {
+		return err
+	}
+	// リモートプラグイン DataStore の場合は自分で変数を同期する（WriteWord と同様）
+	if inst.changeListener != nil {
+		go inst.changeListener.SyncHostWordWriteToVariable(dto.MemoryArea, uint32(dto.Address))
+		s.notifyStoreWrite(inst.protocolType, dto.MemoryArea, uint32(dto.Address), 1)
+	}
+	return nil
+}
+
+// nextDriftValue はランダムウォークの次の値を返す。
+// 現在値が範囲外（開始直後など）の場合は範囲内に収めてから変化させる。
+func nextDriftValue(current, min, max, stepMax int) int {
+	current = clampInt(current, min, max)
+	return clampInt(current+rand.IntN(2*stepMax+1)-stepMax, min, max)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPLCService_DriftBehavior_StaysInBounds(t *testing.T) {
+	svc := newTestService(t)
+	t.Cleanup(svc.clearDriftBehaviors)
+
+	// 開始値は範囲外（0）でも最初の更新で範囲内に入る
+	dto := DriftBehaviorDTO{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 3, Min: 100, Max: 110, StepMax: 4, IntervalMs: 50}
+	seen := map[int]bool{}
+	for i := 0; i < 2000; i++ {
+		if err := svc.driftStep(dto); err != nil {
+			t.Fatalf("driftStep: %v", err)
+		}
+		v, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 3, 1)
+		if v[0] < 100 || v[0] > 110 {
+			t.Fatalf("tick %d: value %d out of [100, 110]", i, v[0])
+		}
+		seen[v[0]] = true
+	}
+	if !seen[100] || !seen[110] || len(seen) < 5 {
+		t.Errorf("random walk did not explore the range: %v", seen)
+	}
+
+	// ゴルーチンでの周期実行
+	id, err := svc.AddDriftBehavior("modbus-tcp", "holdingRegisters", 5, 1000, 1002, 1, 50)
+	if err != nil {
+		t.Fatalf("AddDriftBehavior: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		v, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 5, 1)
+		if v[0] >= 1000 && v[0] <= 1002 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("drift did not write, value = %d", v[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := svc.GetDriftBehaviors(); len(got) != 1 || got[0].ID != id || got[0].Address != 5 {
+		t.Errorf("GetDriftBehaviors = %+v", got)
+	}
+	if err := svc.RemoveDriftBehavior(id); err != nil {
+		t.Fatalf("RemoveDriftBehavior: %v", err)
+	}
+	if err := svc.RemoveDriftBehavior(id); err == nil {
+		t.Error("expected error removing unknown drift behavior")
+	}
+	if got := svc.GetDriftBehaviors(); len(got) != 0 {
+		t.Errorf("GetDriftBehaviors after remove = %+v", got)
+	}
+}
+
+func TestPLCService_AddDriftBehavior_Validation(t *testing.T) {
+	svc := newTestService(t)
+	t.Cleanup(svc.clearDriftBehaviors)
+
+	cases := []struct {
+		name                                string
+		area                                string
+		address, min, max, step, intervalMs int
+	}{
+		{"bit area", "coils", 0, 0, 10, 1, 100},
+		{"unknown area", "nope", 0, 0, 10, 1, 100},
+		{"address out of range", "holdingRegisters", 99999, 0, 10, 1, 100},
+		{"min > max", "holdingRegisters", 0, 10, 0, 1, 100},
+		{"max > 65535", "holdingRegisters", 0, 0, 70000, 1, 100},
+		{"zero step", "holdingRegisters", 0, 0, 10, 0, 100},
+		{"interval too short", "holdingRegisters", 0, 0, 10, 1, 10},
+	}
+	for _, c := range cases {
+		if _, err := svc.AddDriftBehavior("modbus-tcp", c.area, c.address, c.min, c.max, c.step, c.intervalMs); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+	if got := svc.GetDriftBehaviors(); len(got) != 0 {
+		t.Errorf("invalid drift behaviors were added: %+v", got)
+	}
+}
+
+func TestPLCService_DriftBehavior_ExportImport(t *testing.T) {
+	svc := newTestService(t)
+	t.Cleanup(svc.clearDriftBehaviors)
+
+	id, err := svc.AddDriftBehavior("modbus-tcp", "holdingRegisters", 7, 10, 20, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := svc.ExportProject()
+	if len(project.DriftBehaviors) != 1 || project.DriftBehaviors[0].ID != id {
+		t.Fatalf("exported drift behaviors = %+v", project.DriftBehaviors)
+	}
+
+	other := newTestService(t)
+	t.Cleanup(other.clearDriftBehaviors)
+	if err := other.ImportProject(project); err != nil {
+		t.Fatalf("ImportProject: %v", err)
+	}
+	got := other.GetDriftBehaviors()
+	if len(got) != 1 || got[0] != project.DriftBehaviors[0] {
+		t.Errorf("imported drift behaviors = %+v, want %+v", got, project.DriftBehaviors)
+	}
+
+	// 再インポートで置き換わる（重複して動かない）
+	if err := other.ImportProject(project); err != nil {
+		t.Fatal(err)
+	}
+	if got := other.GetDriftBehaviors(); len(got) != 1 {
+		t.Errorf("drift behaviors after re-import = %d, want 1", len(got))
+	}
+}
@@ -1,5 +1,10 @@
 package application
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // === プロトコルスキーマDTO ===
 
 // ProtocolSchemaDTO はプロトコル設定スキーマ
@@ -88,6 +93,46 @@ type MemoryAreaDTO struct {
 	OneOrigin      bool   `json:"oneOrigin"`
 }
 
+// MemoryPageDTO は GetMemoryPage で読み取ったエリアの一部分のDTO
+// ビットエリアの場合は Bits、ワードエリアの場合は Words に値が入る
+type MemoryPageDTO struct {
+	Area      string `json:"area"`
+	Offset    int    `json:"offset"`
+	Length    int    `json:"length"`
+	TotalSize int    `json:"totalSize"`
+	IsBit     bool   `json:"isBit"`
+	Bits      []bool `json:"bits,omitempty"`
+	Words     []int  `json:"words,omitempty"`
+}
+
+// MixedReadDTO は ReadMixed の1件分の読み取り要求のDTO
+// Type は "bit" または "word"（空の場合はエリアの種類に従う）
+type MixedReadDTO struct {
+	ProtocolType string `json:"protocolType"`
+	Area         string `json:"area"`
+	Address      int    `json:"address"`
+	Count        int    `json:"count"`
+	Type         string `json:"type"`
+}
+
+// MixedResultDTO は ReadMixed の1件分の結果のDTO
+// 読み取りに失敗した場合は Error にメッセージが入り、値は空になる
+type MixedResultDTO struct {
+	Bits  []bool `json:"bits,omitempty"`
+	Words []int  `json:"words,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// MemoryDiffDTO はスナップショット間の1アドレス分の差分のDTO
+// ビットエリアの場合 Old/New は bool、ワードエリアの場合は数値
+type MemoryDiffDTO struct {
+	Area    string      `json:"area"`
+	Address int         `json:"address"`
+	IsBit   bool        `json:"isBit"`
+	Old     interface{} `json:"old"`
+	New     interface{} `json:"new"`
+}
+
 // === UnitID設定DTO ===
 
 // UnitIDSettingsDTO はUnitID設定のDTO
@@ -97,6 +142,47 @@ type UnitIDSettingsDTO struct {
 	DisabledIDs []int `json:"disabledIds"`
 }
 
+// SerialPortInfoDTO はシリアルポートの情報のDTO（USB 情報は取得できた場合のみ）
+type SerialPortInfoDTO struct {
+	Name         string `json:"name"`
+	IsUSB        bool   `json:"isUsb"`
+	VID          string `json:"vid,omitempty"`
+	PID          string `json:"pid,omitempty"`
+	SerialNumber string `json:"serialNumber,omitempty"`
+	Product      string `json:"product,omitempty"`
+}
+
+// ConnectionDTO は接続中のクライアントのDTO。
+// RemoteAddress が空のエントリはアドレスを追跡できないサーバー（プラグイン経由など）の
+// UnitID 単位のセッションで、ProtocolType も空になる。
+type ConnectionDTO struct {
+	ProtocolType  string `json:"protocolType"`
+	RemoteAddress string `json:"remoteAddress"`
+	UnitID        int    `json:"unitId"`
+	LastActivity  int64  `json:"lastActivity"` // Unix ミリ秒
+}
+
+// AccessCountDTO はあるアドレスへのマスターからの読み取り・書き込み回数のDTO
+type AccessCountDTO struct {
+	Address int    `json:"address"`
+	Reads   uint64 `json:"reads"`
+	Writes  uint64 `json:"writes"`
+}
+
+// SelfTestCheckDTO は自己診断の1項目の結果
+type SelfTestCheckDTO struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"` // 実行できる条件になく確認を省略した（Passed は true）
+	Message string `json:"message,omitempty"`
+}
+
+// SelfTestReportDTO は自己診断の結果
+type SelfTestReportDTO struct {
+	Passed bool               `json:"passed"` // 全項目が成功した場合に true
+	Checks []SelfTestCheckDTO `json:"checks"`
+}
+
 // === スクリプトDTO ===
 
 // ConsoleLogDTO はconsole.logの1エントリのDTO
@@ -107,17 +193,63 @@ type ConsoleLogDTO struct {
 	At         int64  `json:"at"` // Unix ミリ秒
 }
 
+// LogEntryDTO は診断ログ1件のDTO
+type LogEntryDTO struct {
+	At      int64             `json:"at"`    // Unix ミリ秒
+	Level   string            `json:"level"` // "debug" / "info" / "warn" / "error"
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
 // ScriptDTO はスクリプトのDTO
 type ScriptDTO struct {
 	ID         string `json:"id"`
 	Name       string `json:"name"`
 	Code       string `json:"code"`
 	IntervalMs int    `json:"intervalMs"`
+	Enabled    bool   `json:"enabled"`             // 有効（プロジェクト読み込み時に自動開始する）
+	IsLibrary  bool   `json:"isLibrary,omitempty"` // ライブラリ（require(name) で読み込まれ、単体では実行しない）
 	IsRunning  bool   `json:"isRunning"`
+	IsPaused   bool   `json:"isPaused"`
 	LastError  string `json:"lastError"`
 	ErrorAt    int64  `json:"errorAt"`
 }
 
+// TriggerDTO はメモリ書き込みでスクリプトを実行するトリガーのDTO
+type TriggerDTO struct {
+	ID           string `json:"id"`
+	ProtocolType string `json:"protocolType"`
+	MemoryArea   string `json:"memoryArea"`
+	Address      int    `json:"address"`
+	ScriptID     string `json:"scriptId"`
+}
+
+// DriftBehaviorDTO はワードを範囲内でランダムウォークさせるドリフト動作のDTO
+type DriftBehaviorDTO struct {
+	ID           string `json:"id"`
+	ProtocolType string `json:"protocolType"`
+	MemoryArea   string `json:"memoryArea"`
+	Address      int    `json:"address"`
+	Min          int    `json:"min"`
+	Max          int    `json:"max"`
+	StepMax      int    `json:"stepMax"`
+	IntervalMs   int    `json:"intervalMs"`
+}
+
+// RecipeDTO は名前付きのメモリ値セット（レシピ）のDTO
+type RecipeDTO struct {
+	Name   string           `json:"name"`
+	Values []RecipeValueDTO `json:"values"`
+}
+
+// RecipeValueDTO はレシピの1アドレス分の値（ビットエリアは 0/1）
+type RecipeValueDTO struct {
+	ProtocolType string `json:"protocolType"`
+	MemoryArea   string `json:"memoryArea"`
+	Address      int    `json:"address"`
+	Value        int    `json:"value"`
+}
+
 // IntervalPresetDTO は周期プリセットのDTO
 type IntervalPresetDTO struct {
 	Label string `json:"label"`
@@ -148,6 +280,10 @@ type ServerSnapshotDTO struct {
 	Variant        string                 `json:"variant"`
 	Settings       map[string]interface{} `json:"settings"`
 	UnitIDSettings *UnitIDSettingsDTO     `json:"unitIdSettings,omitempty"`
+	LockedAreas    []string               `json:"lockedAreas,omitempty"`   // UI からの書き込みを禁止しているエリアID
+	ExcludedAreas  []string               `json:"excludedAreas,omitempty"` // メモリ内容を保存しないエリアID
+	Memory         map[string]interface{} `json:"memory,omitempty"`        // memoryFormat が json の場合のメモリ内容
+	MemoryBinary   []byte                 `json:"memoryBinary,omitempty"`  // memoryFormat が binary の場合のメモリ内容（gzip 圧縮）
 }
 
 // === モニタリングDTO ===
@@ -162,6 +298,51 @@ type MonitoringItemDTO struct {
 	BitWidth      int    `json:"bitWidth"`
 	Endianness    string `json:"endianness"`
 	DisplayFormat string `json:"displayFormat"`
+	Group         string `json:"group,omitempty"` // グループ名（空文字列はグループなし）
+
+	// アラーム（値が AlarmHigh を上回るか AlarmLow を下回ると発報。nil の上下限は判定しない）
+	AlarmEnabled bool     `json:"alarmEnabled,omitempty"`
+	AlarmHigh    *float64 `json:"alarmHigh,omitempty"`
+	AlarmLow     *float64 `json:"alarmLow,omitempty"`
+
+	// 工学値への換算（工学値 = 生値 × Gain + Offset。Gain 0 は 1 倍として扱い、Gain・Offset とも 0 なら換算しない）
+	Gain   float64 `json:"gain,omitempty"`
+	Offset float64 `json:"offset,omitempty"`
+	Unit   string  `json:"unit,omitempty"`
+}
+
+// MonitoringValueDTO はモニタリング項目の現在値のDTO
+// ビットエリアの場合 Value は bool、ワードエリアの場合は BitWidth 分のワードを
+// Endianness に従って結合した符号なし整数
+type MonitoringValueDTO struct {
+	ID           string      `json:"id"`
+	ProtocolType string      `json:"protocolType"`
+	MemoryArea   string      `json:"memoryArea"`
+	Address      int         `json:"address"`
+	IsBit        bool        `json:"isBit"`
+	Words        []int       `json:"words,omitempty"`
+	Value        interface{} `json:"value"`
+	// EngineeringValue は換算を設定したワード項目の工学値（Value × Gain + Offset）
+	EngineeringValue *float64 `json:"engineeringValue,omitempty"`
+	Unit             string   `json:"unit,omitempty"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// MonitoringAlarmEventDTO はモニタリング項目のアラーム発報・解除イベントのDTO
+type MonitoringAlarmEventDTO struct {
+	ID           string  `json:"id"`
+	ProtocolType string  `json:"protocolType"`
+	MemoryArea   string  `json:"memoryArea"`
+	Address      int     `json:"address"`
+	Value        float64 `json:"value"`
+	Limit        string  `json:"limit,omitempty"` // 発報時に超えた上下限（"high" / "low"）
+}
+
+// ServerStatusEventDTO はサーバーの状態が変わったときに発行するイベントのDTO
+type ServerStatusEventDTO struct {
+	ProtocolType string `json:"protocolType"`
+	Status       string `json:"status"`          // "Running" | "Stopped" | "Error"
+	Error        string `json:"error,omitempty"` // Error 状態の原因
 }
 
 // MonitoringConfigDTO はモニタリング設定全体のDTO
@@ -189,6 +370,26 @@ type VariableDTO struct {
 	NodePublishings []NodePublishingDTO  `json:"nodePublishings,omitempty"`
 }
 
+// UnmarshalJSON は Value の数値を json.Number のままデコードする。
+// float64 を経由すると LINT/ULINT の 2^53 を超える値の精度が落ちるため（変換は variable.ConvertValue が行う）。
+func (d *VariableDTO) UnmarshalJSON(b []byte) error {
+	type plain VariableDTO
+	aux := struct {
+		*plain
+		Value json.RawMessage `json:"value"`
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	d.Value = nil
+	if len(aux.Value) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(aux.Value))
+	dec.UseNumber()
+	return dec.Decode(&d.Value)
+}
+
 // ProtocolMappingDTO はプロトコルマッピングのDTO
 type ProtocolMappingDTO struct {
 	ProtocolType string `json:"protocolType"`
@@ -229,9 +430,15 @@ type DataTypeInfoDTO struct {
 
 // ProjectDataDTO はプロジェクト全体のエクスポート/インポート用DTO
 type ProjectDataDTO struct {
-	Servers         []ServerSnapshotDTO  `json:"servers,omitempty"`
-	Scripts         []*ScriptDTO         `json:"scripts"`
-	MonitoringItems []*MonitoringItemDTO `json:"monitoringItems,omitempty"`
-	Variables       []*VariableDTO       `json:"variables,omitempty"`
-	StructTypes     []StructTypeDTO      `json:"structTypes,omitempty"`
+	Servers             []ServerSnapshotDTO  `json:"servers,omitempty"`
+	Scripts             []*ScriptDTO         `json:"scripts"`
+	MonitoringItems     []*MonitoringItemDTO `json:"monitoringItems,omitempty"`
+	Variables           []*VariableDTO       `json:"variables,omitempty"`
+	StructTypes         []StructTypeDTO      `json:"structTypes,omitempty"`
+	Recipes             []*RecipeDTO         `json:"recipes,omitempty"`
+	DriftBehaviors      []DriftBehaviorDTO   `json:"driftBehaviors,omitempty"`
+	MemoryFormat        string               `json:"memoryFormat,omitempty"`        // サーバーのメモリ内容の形式（json / binary）
+	ConnectionTimeoutMs int                  `json:"connectionTimeoutMs,omitempty"` // セッションのタイムアウト（0 は既定値）
+	SharedMemory        bool                 `json:"sharedMemory,omitempty"`        // 全サーバーでメモリ内容を共有する
+	Checksum            string               `json:"checksum,omitempty"`            // 内容の SHA-256（ExportProject が設定）
 }
@@ -2,6 +2,8 @@ package application
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"sync"
 
 	"modbus_simulator/internal/domain/protocol"
@@ -10,8 +12,11 @@ import (
 // ===== fakeConfig =====
 
 type fakeConfig struct {
-	protocolType protocol.ProtocolType
-	variant      string
+	protocolType  protocol.ProtocolType
+	variant       string
+	readOnlyAreas []string
+	tcpPort       int
+	settings      map[string]interface{} // fakeSettingDefaults のキーに対応する設定値
 }
 
 func (c *fakeConfig) ProtocolType() protocol.ProtocolType { return c.protocolType }
@@ -19,9 +24,34 @@ func (c *fakeConfig) Variant() string                     { return c.variant }
 func (c *fakeConfig) Validate() error                     { return nil }
 func (c *fakeConfig) Clone() protocol.ProtocolConfig {
 	cp := *c
+	cp.settings = make(map[string]interface{}, len(c.settings))
+	for k, v := range c.settings {
+		cp.settings[k] = v
+	}
 	return &cp
 }
 
+// fakeSettingDefaults はフェイクファクトリーが設定としてそのまま保持するフィールドとデフォルト値を返す
+func fakeSettingDefaults(variantID string) map[string]interface{} {
+	defaults := map[string]interface{}{
+		"perUnitStore":      "false",
+		"unitIdRemap":       "",
+		"maxReadQuantities": "",
+		"areaSizes":         "",
+		"outOfRangePolicy":  "error",
+		"connectionTimeout": 5000,
+	}
+	switch variantID {
+	case "tcp":
+		defaults["tcpAddress"] = "0.0.0.0"
+		defaults["rateLimit"] = 0
+		defaults["rateLimitBusy"] = "false"
+	case "rtu", "ascii":
+		defaults["autoReconnect"] = "false"
+	}
+	return defaults
+}
+
 // ===== fakeDataStore =====
 
 // Modbus 互換のメモリエリア定義
@@ -36,6 +66,9 @@ type fakeDataStore struct {
 	mu    sync.Mutex
 	bits  map[string]map[uint32]bool
 	words map[string]map[uint32]uint16
+	sizes map[string]uint32 // areaSizes 設定で変更されたエリアサイズ
+
+	readOnly map[string]bool // readOnlyAreas 設定で読み取り専用にしたエリア
 }
 
 func newFakeDataStore() *fakeDataStore {
@@ -45,7 +78,52 @@ func newFakeDataStore() *fakeDataStore {
 	}
 }
 
-func (d *fakeDataStore) GetAreas() []protocol.MemoryArea { return fakeModbusAreas }
+func (d *fakeDataStore) GetAreas() []protocol.MemoryArea {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	areas := make([]protocol.MemoryArea, len(fakeModbusAreas))
+	copy(areas, fakeModbusAreas)
+	for i := range areas {
+		if size, ok := d.sizes[areas[i].ID]; ok {
+			areas[i].Size = size
+		}
+		if d.readOnly[areas[i].ID] {
+			areas[i].ReadOnly = true
+		}
+	}
+	return areas
+}
+
+func (d *fakeDataStore) GetArea(id string) (protocol.MemoryArea, bool) {
+	for _, a := range d.GetAreas() {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return protocol.MemoryArea{}, false
+}
+
+// setSizes は areaSizes 設定（"area=size" のカンマ区切り）でエリアサイズを置き換える
+func (d *fakeDataStore) setSizes(list string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sizes = make(map[string]uint32)
+	for _, pair := range strings.Split(list, ",") {
+		area, value, ok := strings.Cut(pair, "=")
+		if size, err := strconv.Atoi(value); ok && err == nil {
+			d.sizes[area] = uint32(size)
+		}
+	}
+}
+
+func (d *fakeDataStore) setReadOnly(areas []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.readOnly = make(map[string]bool)
+	for _, a := range areas {
+		d.readOnly[a] = true
+	}
+}
 
 func (d *fakeDataStore) getBit(area string, address uint32) bool {
 	if d.bits[area] == nil {
@@ -142,10 +220,48 @@ func (d *fakeDataStore) WriteWords(area string, address uint32, values []uint16)
 func (d *fakeDataStore) Snapshot() map[string]interface{} {
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	return map[string]interface{}{}
+	result := make(map[string]interface{}, len(fakeModbusAreas))
+	for _, area := range fakeModbusAreas {
+		if area.IsBit {
+			bits := make([]bool, area.Size)
+			for addr, v := range d.bits[area.ID] {
+				if addr < area.Size {
+					bits[addr] = v
+				}
+			}
+			result[area.ID] = bits
+		} else {
+			words := make([]uint16, area.Size)
+			for addr, v := range d.words[area.ID] {
+				if addr < area.Size {
+					words[addr] = v
+				}
+			}
+			result[area.ID] = words
+		}
+	}
+	return result
 }
 
-func (d *fakeDataStore) Restore(data map[string]interface{}) error { return nil }
+func (d *fakeDataStore) Restore(data map[string]interface{}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for areaID, values := range data {
+		switch vals := values.(type) {
+		case []bool:
+			d.bits[areaID] = make(map[uint32]bool)
+			for i, v := range vals {
+				d.bits[areaID][uint32(i)] = v
+			}
+		case []uint16:
+			d.words[areaID] = make(map[uint32]uint16)
+			for i, v := range vals {
+				d.words[areaID][uint32(i)] = v
+			}
+		}
+	}
+	return nil
+}
 
 func (d *fakeDataStore) ClearAll() {
 	d.mu.Lock()
@@ -157,8 +273,12 @@ func (d *fakeDataStore) ClearAll() {
 // ===== fakeServer =====
 
 type fakeServer struct {
-	cfg    protocol.ProtocolConfig
-	status protocol.ServerStatus
+	cfg        protocol.ProtocolConfig
+	status     protocol.ServerStatus
+	store      protocol.DataStore
+	perUnit    bool
+	unitStores map[uint8]protocol.DataStore
+	disabled   []uint8
 }
 
 func (s *fakeServer) Start(_ context.Context) error {
@@ -176,9 +296,79 @@ func (s *fakeServer) ProtocolType() protocol.ProtocolType { return s.cfg.Protoco
 func (s *fakeServer) Config() protocol.ProtocolConfig     { return s.cfg }
 func (s *fakeServer) UpdateConfig(cfg protocol.ProtocolConfig) error {
 	s.cfg = cfg
+	s.applyConfig()
 	return nil
 }
 
+// applyConfig は設定の readOnlyAreas を共有ストアに、perUnitStore をサーバーに反映する
+func (s *fakeServer) applyConfig() {
+	fc, ok := s.cfg.(*fakeConfig)
+	if !ok {
+		return
+	}
+	s.perUnit = fc.settings["perUnitStore"] == "true"
+	store := s.store
+	if u, ok := store.(interface{ Unwrap() protocol.DataStore }); ok {
+		store = u.Unwrap()
+	}
+	if ds, ok := store.(*fakeDataStore); ok {
+		ds.setReadOnly(fc.readOnlyAreas)
+		sizes, _ := fc.settings["areaSizes"].(string)
+		ds.setSizes(sizes)
+	}
+}
+
+func (s *fakeServer) SetUnitIdEnabled(unitId uint8, enabled bool) {
+	kept := s.disabled[:0]
+	for _, id := range s.disabled {
+		if id != unitId {
+			kept = append(kept, id)
+		}
+	}
+	if !enabled {
+		kept = append(kept, unitId)
+	}
+	s.disabled = kept
+}
+
+func (s *fakeServer) SetDisabledUnitIDs(ids []uint8) { s.disabled = append([]uint8(nil), ids...) }
+func (s *fakeServer) GetDisabledUnitIDs() []uint8    { return append([]uint8(nil), s.disabled...) }
+
+func (s *fakeServer) UnitDataStore(unitId uint8) protocol.DataStore {
+	if !s.perUnit || unitId == 1 {
+		return s.store
+	}
+	if s.unitStores == nil {
+		s.unitStores = make(map[uint8]protocol.DataStore)
+	}
+	if _, ok := s.unitStores[unitId]; !ok {
+		s.unitStores[unitId] = newFakeDataStore()
+	}
+	return s.unitStores[unitId]
+}
+
+// failingServer は startErr が設定されている間、起動に失敗して Error 状態になるサーバー
+type failingServer struct {
+	*fakeServer
+	startErr error
+}
+
+func (s *failingServer) Start(ctx context.Context) error {
+	if s.startErr != nil {
+		s.status = protocol.StatusError
+		return s.startErr
+	}
+	return s.fakeServer.Start(ctx)
+}
+
+// LastError は Error 状態の原因を返す
+func (s *failingServer) LastError() error {
+	if s.status != protocol.StatusError {
+		return nil
+	}
+	return s.startErr
+}
+
 // ===== fakeServerFactory =====
 
 type fakeServerFactory struct {
@@ -201,8 +391,24 @@ func newFakeModbusFactory(protocolType protocol.ProtocolType, variantID, display
 func (f *fakeServerFactory) ProtocolType() protocol.ProtocolType { return f.protocolType }
 func (f *fakeServerFactory) DisplayName() string                  { return f.displayName }
 
-func (f *fakeServerFactory) CreateServer(config protocol.ProtocolConfig, _ protocol.DataStore) (protocol.ProtocolServer, error) {
-	return &fakeServer{cfg: config}, nil
+func (f *fakeServerFactory) CreateServer(config protocol.ProtocolConfig, store protocol.DataStore) (protocol.ProtocolServer, error) {
+	srv := &fakeServer{cfg: config, store: store}
+	srv.applyConfig()
+	return srv, nil
+}
+
+// serverWrappingFactory は fakeServerFactory が作るサーバーを wrap で包むファクトリー
+type serverWrappingFactory struct {
+	*fakeServerFactory
+	wrap func(*fakeServer) protocol.ProtocolServer
+}
+
+func (f *serverWrappingFactory) CreateServer(config protocol.ProtocolConfig, store protocol.DataStore) (protocol.ProtocolServer, error) {
+	srv, err := f.fakeServerFactory.CreateServer(config, store)
+	if err != nil {
+		return nil, err
+	}
+	return f.wrap(srv.(*fakeServer)), nil
 }
 
 func (f *fakeServerFactory) CreateDataStore() protocol.DataStore {
@@ -223,7 +429,40 @@ func (f *fakeServerFactory) CreateConfigFromVariant(variantID string) protocol.P
 	return &fakeConfig{protocolType: f.protocolType, variant: variantID}
 }
 
-func (f *fakeServerFactory) GetConfigFields(_ string) []protocol.ConfigField { return nil }
+func (f *fakeServerFactory) GetConfigFields(variantID string) []protocol.ConfigField {
+	min, max := 1, 65535
+	switch variantID {
+	case "tcp":
+		return []protocol.ConfigField{
+			{Name: "tcpAddress", Label: "アドレス", Type: "text", Required: true, Default: "0.0.0.0"},
+			{Name: "tcpPort", Label: "ポート", Type: "number", Required: true, Default: 502, Min: &min, Max: &max},
+		}
+	case "rtu", "ascii":
+		return []protocol.ConfigField{
+			{Name: "serialPort", Label: "シリアルポート", Type: "serialport", Required: true, Default: "COM1"},
+			{Name: "baudRate", Label: "ボーレート", Type: "select", Required: true, Default: 9600, Options: []protocol.FieldOption{
+				{Value: "9600", Label: "9600"},
+				{Value: "19200", Label: "19200"},
+			}},
+			{Name: "parity", Label: "パリティ", Type: "select", Required: true, Default: "N", Options: []protocol.FieldOption{
+				{Value: "N", Label: "None"},
+				{Value: "E", Label: "Even"},
+			}},
+		}
+	}
+	return nil
+}
+
+// TransportFields は待ち受けに関わる設定フィールドを返す（readOnlyAreas は実行中でも反映できる）
+func (f *fakeServerFactory) TransportFields(variantID string) []string {
+	switch variantID {
+	case "tcp":
+		return []string{"tcpAddress", "tcpPort"}
+	case "rtu", "ascii":
+		return []string{"serialPort", "baudRate", "parity"}
+	}
+	return nil
+}
 
 func (f *fakeServerFactory) GetProtocolCapabilities() protocol.ProtocolCapabilities {
 	return protocol.ProtocolCapabilities{
@@ -233,10 +472,37 @@ func (f *fakeServerFactory) GetProtocolCapabilities() protocol.ProtocolCapabilit
 	}
 }
 
-func (f *fakeServerFactory) ConfigToMap(_ protocol.ProtocolConfig) map[string]interface{} {
-	return map[string]interface{}{}
+func (f *fakeServerFactory) ConfigToMap(config protocol.ProtocolConfig) map[string]interface{} {
+	fc, ok := config.(*fakeConfig)
+	if !ok {
+		return map[string]interface{}{}
+	}
+	result := fakeSettingDefaults(fc.variant)
+	for k, v := range fc.settings {
+		result[k] = v
+	}
+	if len(fc.readOnlyAreas) > 0 {
+		result["readOnlyAreas"] = strings.Join(fc.readOnlyAreas, ",")
+	}
+	if fc.tcpPort != 0 {
+		result["tcpPort"] = fc.tcpPort
+	}
+	return result
 }
 
-func (f *fakeServerFactory) MapToConfig(variantID string, _ map[string]interface{}) (protocol.ProtocolConfig, error) {
-	return &fakeConfig{protocolType: f.protocolType, variant: variantID}, nil
+func (f *fakeServerFactory) MapToConfig(variantID string, settings map[string]interface{}) (protocol.ProtocolConfig, error) {
+	cfg := &fakeConfig{protocolType: f.protocolType, variant: variantID}
+	if v, ok := settings["readOnlyAreas"].(string); ok && v != "" {
+		cfg.readOnlyAreas = strings.Split(v, ",")
+	}
+	if v, ok := settings["tcpPort"].(int); ok {
+		cfg.tcpPort = v
+	}
+	cfg.settings = make(map[string]interface{})
+	for k := range fakeSettingDefaults(variantID) {
+		if v, ok := settings[k]; ok {
+			cfg.settings[k] = v
+		}
+	}
+	return cfg, nil
 }
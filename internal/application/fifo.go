@@ -0,0 +1,31 @@
+package application
+
+import (
+	"fmt"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// EnqueueFIFO は Modbus FC 0x18 (Read FIFO Queue) で返す pointer のキューに value を追加する。
+// キューは最新の31件まで保持する。FIFO キューを持たないサーバーの場合はエラーを返す。
+func (s *PLCService) EnqueueFIFO(protocolType string, pointer, value int) error {
+	if pointer < 0 || pointer > 0xFFFF {
+		return fmt.Errorf("FIFO pointer out of range: %d", pointer)
+	}
+	if value < 0 || value > 0xFFFF {
+		return fmt.Errorf("FIFO value out of range: %d", value)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	queuer, ok := inst.server.(protocol.FIFOQueuer)
+	if !ok {
+		return fmt.Errorf("FIFO queue not supported: %s", protocolType)
+	}
+	return queuer.EnqueueFIFO(uint16(pointer), uint16(value))
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"fmt"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// GetAccessHeatmap は指定エリアでマスターからアクセスされたアドレスの読み書き回数をアドレス順で返す。
+// 一度もアクセスされていないアドレスは含めない。回数を数えられないサーバーの場合はエラーを返す。
+func (s *PLCService) GetAccessHeatmap(protocolType, area string) ([]AccessCountDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findMemoryArea(inst.dataStore, area); err != nil {
+		return nil, err
+	}
+	counter, ok := inst.server.(protocol.AccessCounter)
+	if !ok {
+		return nil, fmt.Errorf("access counts not supported: %s", protocolType)
+	}
+
+	counts := counter.GetAccessCounts(area)
+	result := make([]AccessCountDTO, len(counts))
+	for i, c := range counts {
+		result[i] = AccessCountDTO{Address: int(c.Address), Reads: c.Reads, Writes: c.Writes}
+	}
+	return result, nil
+}
+
+// ResetHeatmap は全サーバーのアクセス回数を破棄する
+func (s *PLCService) ResetHeatmap() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, inst := range s.servers {
+		if counter, ok := inst.server.(protocol.AccessCounter); ok {
+			counter.ResetAccessCounts()
+		}
+	}
+}
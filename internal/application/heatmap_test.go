@@ -0,0 +1,50 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// accessCountingServer はエリアごとのアクセス回数を返すフェイクサーバー
+type accessCountingServer struct {
+	*fakeServer
+	access map[string][]protocol.AccessCount
+}
+
+func (s *accessCountingServer) GetAccessCounts(area string) []protocol.AccessCount {
+	return append([]protocol.AccessCount(nil), s.access[area]...)
+}
+func (s *accessCountingServer) ResetAccessCounts() { s.access = nil }
+
+func TestPLCService_GetAccessHeatmap(t *testing.T) {
+	svc := newTestServiceWithServer(t, func(fs *fakeServer) protocol.ProtocolServer {
+		return &accessCountingServer{fakeServer: fs, access: map[string][]protocol.AccessCount{
+			"holdingRegisters": {{Address: 0, Reads: 3}, {Address: 7, Reads: 1, Writes: 2}},
+		}}
+	})
+
+	got, err := svc.GetAccessHeatmap("modbus-tcp", "holdingRegisters")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AccessCountDTO{{Address: 0, Reads: 3}, {Address: 7, Reads: 1, Writes: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAccessHeatmap = %+v, want %+v", got, want)
+	}
+	if got, err := svc.GetAccessHeatmap("modbus-tcp", "coils"); err != nil || len(got) != 0 {
+		t.Errorf("coils = %+v, %v; want empty", got, err)
+	}
+	if _, err := svc.GetAccessHeatmap("modbus-tcp", "noSuchArea"); err == nil {
+		t.Error("expected error for unknown area")
+	}
+	if _, err := svc.GetAccessHeatmap("modbus-rtu", "coils"); err == nil {
+		t.Error("expected error for unknown server")
+	}
+
+	svc.ResetHeatmap()
+	if got, _ := svc.GetAccessHeatmap("modbus-tcp", "holdingRegisters"); len(got) != 0 {
+		t.Errorf("after ResetHeatmap = %+v, want empty", got)
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"modbus_simulator/internal/domain/datastore"
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// initialStateEntry は初期値ファイルの1行（protocolType の area[start] 以降に values を書き込む）
+type initialStateEntry struct {
+	line         int
+	protocolType string
+	area         string
+	start        int
+	values       []uint16
+}
+
+// SetInitialStateFile はサーバー追加時に読み込む初期値ファイルを設定する（空文字列で解除）。
+// ファイルは1行に "プロトコル エリア 開始アドレス 値..." を書く形式で、値は ImportRangeHex と同じ16進表記
+// （ビットエリアは 0 以外を ON とする）。空行と "#" で始まる行は無視する。
+// 設定時に形式を検証し、AddServer のたびに読み直して該当プロトコルの行を書き込む。
+func (s *PLCService) SetInitialStateFile(path string) error {
+	if path != "" {
+		if _, err := readInitialStateFile(path); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initialStateFile = path
+	return nil
+}
+
+// GetInitialStateFile は設定中の初期値ファイルのパスを返す（未設定なら空文字列）
+func (s *PLCService) GetInitialStateFile() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.initialStateFile
+}
+
+// applyInitialState は初期値ファイルのうち inst のプロトコルの行を DataStore に書き込む。
+// 呼び出し元で s.mu をロックしていること。
+func (s *PLCService) applyInitialState(inst *serverInstance) error {
+	if s.initialStateFile == "" {
+		return nil
+	}
+	entries, err := readInitialStateFile(s.initialStateFile)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.protocolType != string(inst.protocolType) {
+			continue
+		}
+		if err := writeInitialStateEntry(inst.dataStore, e); err != nil {
+			return fmt.Errorf("%s:%d: %w", s.initialStateFile, e.line, err)
+		}
+	}
+	return nil
+}
+
+// writeInitialStateEntry は1行分の値を書き込む
+func writeInitialStateEntry(ds protocol.DataStore, e initialStateEntry) error {
+	areaInfo, err := findMemoryArea(ds, e.area)
+	if err != nil {
+		return err
+	}
+	if e.start < 0 || e.start+len(e.values) > int(areaInfo.Size) {
+		return fmt.Errorf("%w: start=%d count=%d", datastore.ErrAddressOutOfRange, e.start, len(e.values))
+	}
+	if !areaInfo.IsBit {
+		return ds.WriteWords(e.area, uint32(e.start), e.values)
+	}
+	bits := make([]bool, len(e.values))
+	for i, v := range e.values {
+		bits[i] = v != 0
+	}
+	return ds.WriteBits(e.area, uint32(e.start), bits)
+}
+
+// readInitialStateFile は初期値ファイルを読み込んで行ごとに解析する
+func readInitialStateFile(path string) ([]initialStateEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []initialStateEntry
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("%s:%d: %w: expected \"protocol area start values...\"", path, lineNo, datastore.ErrInvalidData)
+		}
+		start, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w: invalid start address %q", path, lineNo, datastore.ErrInvalidData, fields[2])
+		}
+		values, err := parseHexWords(strings.Join(fields[3:], " "))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		entries = append(entries, initialStateEntry{
+			line:         lineNo,
+			protocolType: fields[0],
+			area:         fields[1],
+			start:        start,
+			values:       values,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeInitialStateFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "initial.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPLCService_InitialStateFile_AppliedOnAddServer(t *testing.T) {
+	svc := newTestService(t)
+	path := writeInitialStateFile(t, `# 初期値
+modbus-rtu holdingRegisters 10 1234 0xABCD
+modbus-rtu coils 0 1 0 1
+
+modbus-tcp holdingRegisters 0 FFFF
+`)
+	if err := svc.SetInitialStateFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+
+	words, err := svc.ReadWords("modbus-rtu", "holdingRegisters", 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(words, []int{0x1234, 0xABCD}) {
+		t.Errorf("holdingRegisters[10:12] = %v", words)
+	}
+	bits, err := svc.ReadBits("modbus-rtu", "coils", 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bits, []bool{true, false, true}) {
+		t.Errorf("coils[0:3] = %v", bits)
+	}
+
+	// 追加済みのサーバーには適用しない
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); words[0] != 0 {
+		t.Errorf("existing server was modified: %v", words)
+	}
+
+	// 削除して追加し直すと再び初期値から始まる
+	if err := svc.WriteWord("modbus-rtu", "holdingRegisters", 10, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RemoveServer("modbus-rtu"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := svc.ReadWords("modbus-rtu", "holdingRegisters", 10, 1); words[0] != 0x1234 {
+		t.Errorf("after re-adding: holdingRegisters[10] = %#x, want 0x1234", words[0])
+	}
+}
+
+func TestPLCService_SetInitialStateFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing values", "modbus-tcp holdingRegisters 0\n"},
+		{"bad start", "modbus-tcp holdingRegisters x 1234\n"},
+		{"bad hex", "modbus-tcp holdingRegisters 0 XYZ\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+			if err := svc.SetInitialStateFile(writeInitialStateFile(t, tt.content)); err == nil {
+				t.Error("expected error")
+			}
+			if got := svc.GetInitialStateFile(); got != "" {
+				t.Errorf("invalid file was set: %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"fmt"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+// maxMixedReadRequests は ReadMixed 1回あたりの最大要求数
+const maxMixedReadRequests = 256
+
+// MixedReadDTO.Type の値
+const (
+	mixedReadBit  = "bit"
+	mixedReadWord = "word"
+)
+
+// ReadMixed はビット・ワードの複数の読み取り要求をまとめて実行する。
+// ダッシュボードが必要な値を1回の呼び出しで取得するためのもので、
+// 要求ごとの失敗は結果の Error に入れ、他の要求の読み取りは続ける。
+// 要求数が上限を超える場合のみエラーを返す。
+func (s *PLCService) ReadMixed(requests []MixedReadDTO) ([]MixedResultDTO, error) {
+	if len(requests) > maxMixedReadRequests {
+		return nil, fmt.Errorf("too many read requests: %d (max %d)", len(requests), maxMixedReadRequests)
+	}
+
+	results := make([]MixedResultDTO, len(requests))
+	for i, req := range requests {
+		isBit, err := s.checkMixedRead(req)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		if isBit {
+			results[i].Bits, err = s.ReadBits(req.ProtocolType, req.Area, req.Address, req.Count)
+		} else {
+			results[i].Words, err = s.ReadWords(req.ProtocolType, req.Area, req.Address, req.Count)
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+	return results, nil
+}
+
+// checkMixedRead は要求のエリア・範囲・種類を検証し、ビットエリアかどうかを返す
+func (s *PLCService) checkMixedRead(req MixedReadDTO) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(req.ProtocolType)
+	if err != nil {
+		return false, err
+	}
+	area, err := findMemoryArea(inst.dataStore, req.Area)
+	if err != nil {
+		return false, err
+	}
+	switch req.Type {
+	case "":
+	case mixedReadBit, mixedReadWord:
+		if (req.Type == mixedReadBit) != area.IsBit {
+			return false, fmt.Errorf("%w: %s is not a %s area", datastore.ErrTypeMismatch, req.Area, req.Type)
+		}
+	default:
+		return false, fmt.Errorf("unknown read type: %s", req.Type)
+	}
+	if req.Count <= 0 || req.Count > memoryReadChunk {
+		return false, fmt.Errorf("count must be between 1 and %d", memoryReadChunk)
+	}
+	if req.Address < 0 || req.Address+req.Count > int(area.Size) {
+		return false, fmt.Errorf("%w: %s %d-%d (size %d)", datastore.ErrAddressOutOfRange, req.Area, req.Address, req.Address+req.Count-1, area.Size)
+	}
+	return area.IsBit, nil
+}
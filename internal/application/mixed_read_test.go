@@ -0,0 +1,56 @@
+package application
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPLCService_ReadMixed(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.WriteBit("modbus-tcp", "coils", 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 100, 1234); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := svc.ReadMixed([]MixedReadDTO{
+		{ProtocolType: "modbus-tcp", Area: "coils", Address: 0, Count: 3, Type: "bit"},
+		{ProtocolType: "modbus-tcp", Area: "holdingRegisters", Address: 99, Count: 2, Type: "word"},
+		{ProtocolType: "modbus-tcp", Area: "holdingRegisters", Address: 9998, Count: 5, Type: "word"},
+		{ProtocolType: "modbus-tcp", Area: "holdingRegisters", Address: 100, Count: 1},
+		{ProtocolType: "modbus-tcp", Area: "coils", Address: 0, Count: 1, Type: "word"},
+		{ProtocolType: "unknown", Area: "coils", Address: 0, Count: 1},
+	})
+	if err != nil {
+		t.Fatalf("ReadMixed: %v", err)
+	}
+	if len(results) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(results))
+	}
+
+	if !reflect.DeepEqual(results[0].Bits, []bool{false, true, false}) || results[0].Error != "" {
+		t.Errorf("coils result = %+v", results[0])
+	}
+	if !reflect.DeepEqual(results[1].Words, []int{0, 1234}) || results[1].Error != "" {
+		t.Errorf("holding registers result = %+v", results[1])
+	}
+	if !strings.Contains(results[2].Error, "out of range") || results[2].Words != nil {
+		t.Errorf("out-of-range result = %+v", results[2])
+	}
+	// Type 省略時はエリアの種類に従う
+	if !reflect.DeepEqual(results[3].Words, []int{1234}) {
+		t.Errorf("untyped result = %+v", results[3])
+	}
+	if !strings.Contains(results[4].Error, "type mismatch") {
+		t.Errorf("type mismatch result = %+v", results[4])
+	}
+	if results[5].Error == "" {
+		t.Errorf("unknown server result = %+v", results[5])
+	}
+
+	if _, err := svc.ReadMixed(make([]MixedReadDTO, maxMixedReadRequests+1)); err == nil {
+		t.Error("expected error for too many requests")
+	}
+}
@@ -0,0 +1,102 @@
+package application
+
+// アラームの上下限の種類
+const (
+	alarmLimitHigh = "high"
+	alarmLimitLow  = "low"
+)
+
+// evaluateMonitoringAlarms は読み取った値でアラームを判定し、発報・解除したときにイベントを発行する。
+// 発報中の項目は解除されるまで再発報しない。values は items と同じ順序であること。
+func (s *PLCService) evaluateMonitoringAlarms(items []*MonitoringItemDTO, values []MonitoringValueDTO) {
+	s.mu.RLock()
+	emitter := s.appEmitter
+	s.mu.RUnlock()
+
+	// 同時に呼ばれてもイベントの順序が状態の変化と一致するよう、発行までロックを保持する
+	s.alarmMu.Lock()
+	defer s.alarmMu.Unlock()
+
+	present := make(map[string]bool, len(items))
+	for i, item := range items {
+		present[item.ID] = true
+		v := values[i]
+		if v.Error != "" {
+			// 読み取れない間は状態を維持する
+			continue
+		}
+		num, ok := monitoringNumericValue(v)
+		if !ok {
+			continue
+		}
+
+		limit := ""
+		if item.AlarmEnabled {
+			limit = exceededAlarmLimit(item, num)
+		}
+		active := limit != ""
+		if active == s.alarmActive[item.ID] {
+			continue
+		}
+
+		event := MonitoringAlarmEventDTO{
+			ID:           item.ID,
+			ProtocolType: item.ProtocolType,
+			MemoryArea:   item.MemoryArea,
+			Address:      item.Address,
+			Value:        num,
+			Limit:        limit,
+		}
+		if active {
+			if s.alarmActive == nil {
+				s.alarmActive = make(map[string]bool)
+			}
+			s.alarmActive[item.ID] = true
+			s.logger.Info("monitoring alarm triggered", "id", item.ID, "value", num, "limit", limit)
+			if emitter != nil {
+				emitter.EmitAlarmTriggered(event)
+			}
+		} else {
+			delete(s.alarmActive, item.ID)
+			if emitter != nil {
+				emitter.EmitAlarmCleared(event)
+			}
+		}
+	}
+
+	// 削除された項目の状態は破棄する
+	for id := range s.alarmActive {
+		if !present[id] {
+			delete(s.alarmActive, id)
+		}
+	}
+}
+
+// exceededAlarmLimit は value が上限を上回っていれば "high"、下限を下回っていれば "low"、範囲内なら空文字列を返す
+func exceededAlarmLimit(item *MonitoringItemDTO, value float64) string {
+	if item.AlarmHigh != nil && value > *item.AlarmHigh {
+		return alarmLimitHigh
+	}
+	if item.AlarmLow != nil && value < *item.AlarmLow {
+		return alarmLimitLow
+	}
+	return ""
+}
+
+// monitoringNumericValue はモニタリング値を数値にする（ビットは 1/0、換算を設定した項目は工学値）
+func monitoringNumericValue(v MonitoringValueDTO) (float64, bool) {
+	if v.EngineeringValue != nil {
+		return *v.EngineeringValue, true
+	}
+	switch value := v.Value.(type) {
+	case bool:
+		if value {
+			return 1, true
+		}
+		return 0, true
+	case uint64:
+		return float64(value), true
+	default:
+		return 0, false
+	}
+}
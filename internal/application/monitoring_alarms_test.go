@@ -0,0 +1,149 @@
+package application
+
+import (
+	"sync"
+	"testing"
+)
+
+// recordingAppEmitter はアラームイベントを記録する AppStateEmitter
+type recordingAppEmitter struct {
+	mu        sync.Mutex
+	triggered []MonitoringAlarmEventDTO
+	cleared   []MonitoringAlarmEventDTO
+	statuses  []ServerStatusEventDTO
+}
+
+func (e *recordingAppEmitter) EmitServerChanged([]ServerInstanceDTO, []ProtocolInfoDTO) {}
+func (e *recordingAppEmitter) EmitVariablesChanged([]*VariableDTO)                      {}
+func (e *recordingAppEmitter) EmitScriptsChanged([]*ScriptDTO)                          {}
+func (e *recordingAppEmitter) EmitConsoleLogAdded(ConsoleLogDTO)                        {}
+func (e *recordingAppEmitter) EmitLogEntry(LogEntryDTO)                                 {}
+
+func (e *recordingAppEmitter) EmitAlarmTriggered(event MonitoringAlarmEventDTO) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.triggered = append(e.triggered, event)
+}
+
+func (e *recordingAppEmitter) EmitAlarmCleared(event MonitoringAlarmEventDTO) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cleared = append(e.cleared, event)
+}
+
+func (e *recordingAppEmitter) EmitServerStatus(event ServerStatusEventDTO) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.statuses = append(e.statuses, event)
+}
+
+func (e *recordingAppEmitter) counts() (triggered, cleared int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.triggered), len(e.cleared)
+}
+
+// statusEvents は記録した server:status イベントを記録順に返す
+func (e *recordingAppEmitter) statusEvents() []ServerStatusEventDTO {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]ServerStatusEventDTO(nil), e.statuses...)
+}
+
+func TestPLCService_MonitoringAlarm_TriggersOnce(t *testing.T) {
+	svc := newTestService(t)
+	emitter := &recordingAppEmitter{}
+	svc.SetAppStateEmitter(emitter)
+
+	high, low := 100.0, 10.0
+	item, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+		ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 0, BitWidth: 16,
+		AlarmEnabled: true, AlarmHigh: &high, AlarmLow: &low,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 50); err != nil {
+		t.Fatal(err)
+	}
+	svc.ReadMonitoringValues()
+	if tr, cl := emitter.counts(); tr != 0 || cl != 0 {
+		t.Fatalf("value in range: triggered=%d cleared=%d, want 0/0", tr, cl)
+	}
+
+	// 上限を超えた状態で何度ポーリングしても発報は1回
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 150); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		svc.ReadMonitoringValues()
+	}
+	if tr, _ := emitter.counts(); tr != 1 {
+		t.Fatalf("triggered = %d, want 1", tr)
+	}
+	if got := emitter.triggered[0]; got.ID != item.ID || got.Value != 150 || got.Limit != "high" {
+		t.Errorf("triggered event = %+v", got)
+	}
+
+	// 範囲内に戻ると解除
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 50); err != nil {
+		t.Fatal(err)
+	}
+	svc.ReadMonitoringValues()
+	if tr, cl := emitter.counts(); tr != 1 || cl != 1 {
+		t.Fatalf("after clear: triggered=%d cleared=%d, want 1/1", tr, cl)
+	}
+	if got := emitter.cleared[0]; got.ID != item.ID || got.Value != 50 {
+		t.Errorf("cleared event = %+v", got)
+	}
+
+	// 下限を下回ると再び発報
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 5); err != nil {
+		t.Fatal(err)
+	}
+	svc.ReadMonitoringValues()
+	if tr, _ := emitter.counts(); tr != 2 || emitter.triggered[1].Limit != "low" {
+		t.Errorf("low limit: triggered = %+v", emitter.triggered)
+	}
+}
+
+func TestPLCService_MonitoringAlarm_Disabled(t *testing.T) {
+	svc := newTestService(t)
+	emitter := &recordingAppEmitter{}
+	svc.SetAppStateEmitter(emitter)
+
+	high := 0.0
+	item, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+		ProtocolType: "modbus-tcp", MemoryArea: "coils", Address: 0, AlarmHigh: &high,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteBit("modbus-tcp", "coils", 0, true); err != nil {
+		t.Fatal(err)
+	}
+	svc.ReadMonitoringValues()
+	if tr, _ := emitter.counts(); tr != 0 {
+		t.Fatalf("disabled alarm triggered %d times", tr)
+	}
+
+	item.AlarmEnabled = true
+	if err := svc.UpdateMonitoringItem(item); err != nil {
+		t.Fatal(err)
+	}
+	svc.ReadMonitoringValues()
+	if tr, _ := emitter.counts(); tr != 1 {
+		t.Fatalf("enabled alarm: triggered = %d, want 1", tr)
+	}
+
+	// 発報中に無効化すると解除イベントを出す
+	updated := *item
+	updated.AlarmEnabled = false
+	if err := svc.UpdateMonitoringItem(&updated); err != nil {
+		t.Fatal(err)
+	}
+	svc.ReadMonitoringValues()
+	if _, cl := emitter.counts(); cl != 1 {
+		t.Errorf("disabling an active alarm: cleared = %d, want 1", cl)
+	}
+}
@@ -0,0 +1,233 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minMonitoringLogInterval はモニタリング値の CSV ログの最短サンプリング間隔
+const minMonitoringLogInterval = 10 * time.Millisecond
+
+// monitoringLogTimeFormat は CSV ログのタイムスタンプの形式
+const monitoringLogTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// MonitoringCSVLog は実行中のモニタリング値の CSV ログ
+type MonitoringCSVLog struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu  sync.Mutex
+	err error // 書き込みに失敗した場合のエラー（終了後に確定する）
+}
+
+// Stop はログを途中で終了し、ファイルを閉じるまで待つ。書き込みエラーがあれば返す
+func (l *MonitoringCSVLog) Stop() error {
+	l.cancel()
+	<-l.done
+	return l.Err()
+}
+
+// Done はログの終了（期間経過・Stop・書き込みエラー）時に閉じられるチャネルを返す
+func (l *MonitoringCSVLog) Done() <-chan struct{} {
+	return l.done
+}
+
+// Err は書き込みに失敗した場合のエラーを返す
+func (l *MonitoringCSVLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+func (l *MonitoringCSVLog) setErr(err error) {
+	l.mu.Lock()
+	if l.err == nil {
+		l.err = err
+	}
+	l.mu.Unlock()
+}
+
+// LogMonitoringToCSV は intervalMs ごとに全モニタリング項目の値を読み取り、タイムスタンプ付きの行を path に書き込む。
+// durationMs が経過するか Stop されるまでバックグラウンドで動作する（durationMs が 0 以下の場合は Stop まで）。
+// 列は開始時点のモニタリング項目で固定し、値は各項目の表示形式で出力する。既に実行中の場合は置き換える。
+func (s *PLCService) LogMonitoringToCSV(path string, durationMs, intervalMs int) (*MonitoringCSVLog, error) {
+	interval := time.Duration(intervalMs) * time.Millisecond
+	if interval < minMonitoringLogInterval {
+		return nil, fmt.Errorf("interval must be at least %dms", minMonitoringLogInterval.Milliseconds())
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.startMonitoringCSVLog(f, f.Close, time.Duration(durationMs)*time.Millisecond, interval), nil
+}
+
+// StopMonitoringCSVLog は実行中の CSV ログを停止する（実行中でなければ何もしない）
+func (s *PLCService) StopMonitoringCSVLog() error {
+	s.csvLogMu.Lock()
+	l := s.csvLog
+	s.csvLog = nil
+	s.csvLogMu.Unlock()
+	if l == nil {
+		return nil
+	}
+	return l.Stop()
+}
+
+// IsMonitoringCSVLogRunning は CSV ログが実行中かどうかを返す
+func (s *PLCService) IsMonitoringCSVLogRunning() bool {
+	s.csvLogMu.Lock()
+	l := s.csvLog
+	s.csvLogMu.Unlock()
+	if l == nil {
+		return false
+	}
+	select {
+	case <-l.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// startMonitoringCSVLog は w への CSV ログを開始する。終了時に closeFn を呼ぶ（nil の場合は呼ばない）
+func (s *PLCService) startMonitoringCSVLog(w io.Writer, closeFn func() error, duration, interval time.Duration) *MonitoringCSVLog {
+	items := s.GetMonitoringItems()
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if duration > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), duration)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	l := &MonitoringCSVLog{cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(l.done)
+		defer cancel()
+		if closeFn != nil {
+			defer func() {
+				if err := closeFn(); err != nil {
+					l.setErr(err)
+				}
+			}()
+		}
+
+		cw := csv.NewWriter(w)
+		header := make([]string, 0, len(items)+1)
+		header = append(header, "timestamp")
+		for _, item := range items {
+			header = append(header, monitoringColumnName(item))
+		}
+		if err := writeCSVRow(cw, header); err != nil {
+			l.setErr(err)
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := writeCSVRow(cw, s.monitoringCSVRow(items, time.Now())); err != nil {
+				s.logger.Warn("monitoring CSV log stopped", "error", err)
+				l.setErr(err)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	s.csvLogMu.Lock()
+	prev := s.csvLog
+	s.csvLog = l
+	s.csvLogMu.Unlock()
+	if prev != nil {
+		prev.Stop() //nolint:errcheck // 置き換えた古いログのエラーは報告先がない
+	}
+	return l
+}
+
+// writeCSVRow は1行を書き込み、途中で止まってもファイルに残るようすぐにフラッシュする
+func writeCSVRow(cw *csv.Writer, row []string) error {
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// monitoringCSVRow は items の現在値を表示形式で並べた1行を返す（読み取れない項目は空欄）
+func (s *PLCService) monitoringCSVRow(items []*MonitoringItemDTO, now time.Time) []string {
+	values := make(map[string]MonitoringValueDTO)
+	for _, v := range s.ReadMonitoringValues() {
+		values[v.ID] = v
+	}
+
+	row := make([]string, 0, len(items)+1)
+	row = append(row, now.Format(monitoringLogTimeFormat))
+	for _, item := range items {
+		v, ok := values[item.ID]
+		if !ok || v.Error != "" {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, formatMonitoringValue(item, v))
+	}
+	return row
+}
+
+// monitoringColumnName は CSV の列名（"プロトコル:エリア:アドレス"、グループがあれば先頭に、単位があれば末尾に付ける）を返す
+func monitoringColumnName(item *MonitoringItemDTO) string {
+	name := fmt.Sprintf("%s:%s:%d", item.ProtocolType, item.MemoryArea, item.Address)
+	if item.Group != "" {
+		name = item.Group + "/" + name
+	}
+	if item.Unit != "" {
+		name += " [" + item.Unit + "]"
+	}
+	return name
+}
+
+// formatMonitoringValue はモニタリング値を項目の表示形式（decimal / hex / octal / binary）の文字列にする。
+// ビットは 1/0、換算を設定した項目は表示形式によらず工学値の10進で表す。形式はモニタリング画面の表示に合わせている。
+func formatMonitoringValue(item *MonitoringItemDTO, v MonitoringValueDTO) string {
+	if v.EngineeringValue != nil {
+		return formatEngineeringValue(*v.EngineeringValue)
+	}
+	switch value := v.Value.(type) {
+	case bool:
+		if value {
+			return "1"
+		}
+		return "0"
+	case uint64:
+		bitWidth := len(v.Words) * 16
+		switch item.DisplayFormat {
+		case "hex":
+			return fmt.Sprintf("0x%0*X", bitWidth/4, value)
+		case "octal":
+			return "0o" + strconv.FormatUint(value, 8)
+		case "binary":
+			s := strconv.FormatUint(value, 2)
+			if len(s) < bitWidth {
+				s = strings.Repeat("0", bitWidth-len(s)) + s
+			}
+			return s
+		default:
+			return strconv.FormatUint(value, 10)
+		}
+	default:
+		return fmt.Sprint(value)
+	}
+}
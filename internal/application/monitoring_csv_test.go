@@ -0,0 +1,125 @@
+package application
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPLCService_MonitoringCSVLog(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 0xBEEF); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteBit("modbus-tcp", "coils", 3, true); err != nil {
+		t.Fatal(err)
+	}
+	err := svc.SetMonitoringItems([]*MonitoringItemDTO{
+		{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 0, BitWidth: 16, DisplayFormat: "hex"},
+		{ProtocolType: "modbus-tcp", MemoryArea: "coils", Address: 3, Group: "io"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	l := svc.startMonitoringCSVLog(&buf, nil, 50*time.Millisecond, 10*time.Millisecond)
+	select {
+	case <-l.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("log did not finish after its duration")
+	}
+	if err := l.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) < 3 {
+		t.Fatalf("expected header and at least 2 samples, got %d rows", len(rows))
+	}
+	if want := []string{"timestamp", "modbus-tcp:holdingRegisters:0", "io/modbus-tcp:coils:3"}; !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("header = %v, want %v", rows[0], want)
+	}
+	for _, row := range rows[1:] {
+		if _, err := time.Parse(monitoringLogTimeFormat, row[0]); err != nil {
+			t.Errorf("bad timestamp %q: %v", row[0], err)
+		}
+		if row[1] != "0xBEEF" || row[2] != "1" {
+			t.Errorf("row = %v, want values 0xBEEF and 1", row)
+		}
+	}
+	if svc.IsMonitoringCSVLogRunning() {
+		t.Error("log should not be running after its duration")
+	}
+}
+
+func TestPLCService_LogMonitoringToCSV_StopEarly(t *testing.T) {
+	svc := newTestService(t)
+	if _, err := svc.AddMonitoringItem(&MonitoringItemDTO{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", BitWidth: 16}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.LogMonitoringToCSV(filepath.Join(t.TempDir(), "x.csv"), 0, 1); err == nil {
+		t.Error("expected error for too short interval")
+	}
+
+	path := filepath.Join(t.TempDir(), "monitoring.csv")
+	if _, err := svc.LogMonitoringToCSV(path, 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if !svc.IsMonitoringCSVLogRunning() {
+		t.Fatal("expected log to be running")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err := svc.StopMonitoringCSVLog(); err != nil {
+		t.Fatal(err)
+	}
+	if svc.IsMonitoringCSVLogRunning() {
+		t.Error("expected log to be stopped")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) < 2 {
+		t.Errorf("expected header and samples, got %d rows", len(rows))
+	}
+}
+
+func TestFormatMonitoringValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		value  MonitoringValueDTO
+		want   string
+	}{
+		{"bit on", "", MonitoringValueDTO{IsBit: true, Value: true}, "1"},
+		{"bit off", "hex", MonitoringValueDTO{IsBit: true, Value: false}, "0"},
+		{"decimal", "decimal", MonitoringValueDTO{Words: []int{0, 1}, Value: uint64(65536)}, "65536"},
+		{"hex 16bit", "hex", MonitoringValueDTO{Words: []int{10}, Value: uint64(10)}, "0x000A"},
+		{"hex 32bit", "hex", MonitoringValueDTO{Words: []int{0, 10}, Value: uint64(10)}, "0x0000000A"},
+		{"octal", "octal", MonitoringValueDTO{Words: []int{8}, Value: uint64(8)}, "0o10"},
+		{"binary", "binary", MonitoringValueDTO{Words: []int{5}, Value: uint64(5)}, "0000000000000101"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatMonitoringValue(&MonitoringItemDTO{DisplayFormat: tt.format}, tt.value)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
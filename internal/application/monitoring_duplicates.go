@@ -0,0 +1,78 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMonitoringItemExists は同じモニタリング項目が既に登録されている場合のエラー
+var ErrMonitoringItemExists = errors.New("monitoring item already exists")
+
+// MonitoringItemExistsError は重複した項目の追加を拒否した場合のエラー。
+// errors.Is で ErrMonitoringItemExists と一致し、ExistingID で既存項目を参照できる。
+type MonitoringItemExistsError struct {
+	ExistingID string
+}
+
+func (e *MonitoringItemExistsError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrMonitoringItemExists, e.ExistingID)
+}
+
+func (e *MonitoringItemExistsError) Is(target error) bool {
+	return target == ErrMonitoringItemExists
+}
+
+// MonitoringDuplicatePolicy は同じ項目（プロトコル・エリア・アドレス・ビット幅が一致）が既にある場合の動作
+type MonitoringDuplicatePolicy string
+
+const (
+	// MonitoringDuplicateAllow は重複を気にせず追加する（デフォルト）
+	MonitoringDuplicateAllow MonitoringDuplicatePolicy = ""
+	// MonitoringDuplicateReject は追加せずに *MonitoringItemExistsError を返す
+	MonitoringDuplicateReject MonitoringDuplicatePolicy = "reject"
+	// MonitoringDuplicateReturnExisting は追加せずに既存の項目を返す
+	MonitoringDuplicateReturnExisting MonitoringDuplicatePolicy = "existing"
+)
+
+// AddMonitoringOptions は AddMonitoringItemWithOptions のオプション
+type AddMonitoringOptions struct {
+	OnDuplicate MonitoringDuplicatePolicy `json:"onDuplicate"`
+}
+
+// AddMonitoringItemWithOptions はオプションを指定してモニタリング項目を追加する
+func (s *PLCService) AddMonitoringItemWithOptions(item *MonitoringItemDTO, opts AddMonitoringOptions) (*MonitoringItemDTO, error) {
+	switch opts.OnDuplicate {
+	case MonitoringDuplicateAllow, MonitoringDuplicateReject, MonitoringDuplicateReturnExisting:
+	default:
+		return nil, fmt.Errorf("unknown duplicate policy: %s", opts.OnDuplicate)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if opts.OnDuplicate != MonitoringDuplicateAllow {
+		if existing := s.findDuplicateMonitoringItem(item); existing != nil {
+			if opts.OnDuplicate == MonitoringDuplicateReturnExisting {
+				return existing, nil
+			}
+			return nil, &MonitoringItemExistsError{ExistingID: existing.ID}
+		}
+	}
+	return s.addMonitoringItemLocked(item), nil
+}
+
+// findDuplicateMonitoringItem は item と同じ対象の登録済み項目を返す（なければ nil）。
+// 複数ある場合は Order が最小のものを返す。呼び出し元で s.mu をロックしていること。
+func (s *PLCService) findDuplicateMonitoringItem(item *MonitoringItemDTO) *MonitoringItemDTO {
+	var found *MonitoringItemDTO
+	for _, m := range s.monitoringItems {
+		if m.ProtocolType != item.ProtocolType || m.MemoryArea != item.MemoryArea ||
+			m.Address != item.Address || m.BitWidth != item.BitWidth {
+			continue
+		}
+		if found == nil || m.Order < found.Order {
+			found = m
+		}
+	}
+	return found
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPLCService_AddMonitoringItemWithOptions_Duplicates(t *testing.T) {
+	newItem := func() *MonitoringItemDTO {
+		return &MonitoringItemDTO{
+			ProtocolType:  "modbus-tcp",
+			MemoryArea:    "holdingRegisters",
+			Address:       10,
+			BitWidth:      32,
+			Endianness:    "big",
+			DisplayFormat: "decimal",
+		}
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		svc := newTestService(t)
+		first, err := svc.AddMonitoringItemWithOptions(newItem(), AddMonitoringOptions{OnDuplicate: MonitoringDuplicateReject})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = svc.AddMonitoringItemWithOptions(newItem(), AddMonitoringOptions{OnDuplicate: MonitoringDuplicateReject})
+		if !errors.Is(err, ErrMonitoringItemExists) {
+			t.Fatalf("expected ErrMonitoringItemExists, got %v", err)
+		}
+		var exists *MonitoringItemExistsError
+		if !errors.As(err, &exists) || exists.ExistingID != first.ID {
+			t.Errorf("expected existing ID %s, got %v", first.ID, err)
+		}
+		if n := len(svc.GetMonitoringItems()); n != 1 {
+			t.Errorf("expected 1 item, got %d", n)
+		}
+	})
+
+	t.Run("return existing", func(t *testing.T) {
+		svc := newTestService(t)
+		first, err := svc.AddMonitoringItemWithOptions(newItem(), AddMonitoringOptions{OnDuplicate: MonitoringDuplicateReturnExisting})
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := svc.AddMonitoringItemWithOptions(newItem(), AddMonitoringOptions{OnDuplicate: MonitoringDuplicateReturnExisting})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second.ID != first.ID {
+			t.Errorf("expected existing item %s, got %s", first.ID, second.ID)
+		}
+		if n := len(svc.GetMonitoringItems()); n != 1 {
+			t.Errorf("expected 1 item, got %d", n)
+		}
+	})
+
+	t.Run("different width is not a duplicate", func(t *testing.T) {
+		svc := newTestService(t)
+		if _, err := svc.AddMonitoringItem(newItem()); err != nil {
+			t.Fatal(err)
+		}
+		other := newItem()
+		other.BitWidth = 16
+		if _, err := svc.AddMonitoringItemWithOptions(other, AddMonitoringOptions{OnDuplicate: MonitoringDuplicateReject}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("allow by default", func(t *testing.T) {
+		svc := newTestService(t)
+		for i := 0; i < 2; i++ {
+			if _, err := svc.AddMonitoringItem(newItem()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if n := len(svc.GetMonitoringItems()); n != 2 {
+			t.Errorf("expected 2 items, got %d", n)
+		}
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		svc := newTestService(t)
+		if _, err := svc.AddMonitoringItemWithOptions(newItem(), AddMonitoringOptions{OnDuplicate: "merge"}); err == nil {
+			t.Error("expected error for unknown policy")
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// monitoringSaveDelay はモニタリング項目の変更から自動保存までの待ち時間。
+// この間に続けて変更された場合は最後の変更から数え直し、まとめて1回だけ書き込む。
+const monitoringSaveDelay = 500 * time.Millisecond
+
+// monitoringSaver はモニタリング設定の自動保存をまとめて直列化する。
+// 保存内容は呼び出し元が s.mu を保持している間に作成したスナップショットを使い、
+// 書き込みは s.mu を保持せずに行う。複数の保存が前後した場合も、古いスナップショットで上書きしない。
+type monitoringSaver struct {
+	mu      sync.Mutex    // 書き込みと written・writes を保護する
+	seq     atomic.Uint64 // 最後に作成したスナップショットの番号
+	written uint64        // 最後に書き込んだスナップショットの番号
+	writes  int           // ファイルへ書き込んだ回数
+
+	// 保存待ちのスナップショット
+	pendingMu  sync.Mutex
+	pending    []*MonitoringItemDTO
+	pendingSeq uint64
+	hasPending bool
+	timer      *time.Timer
+	delay      time.Duration // 0 の場合は monitoringSaveDelay
+}
+
+// scheduleMonitoringSaveLocked は現在のモニタリング項目のスナップショットを保存待ちにし、
+// 待ち時間の経過後にまとめて保存する（s.mu のロック必須）
+func (s *PLCService) scheduleMonitoringSaveLocked() {
+	seq := s.monitorSave.seq.Add(1)
+	items := s.monitoringSnapshotLocked()
+
+	ms := &s.monitorSave
+	ms.pendingMu.Lock()
+	defer ms.pendingMu.Unlock()
+	ms.pending, ms.pendingSeq, ms.hasPending = items, seq, true
+
+	delay := ms.delay
+	if delay <= 0 {
+		delay = monitoringSaveDelay
+	}
+	if ms.timer == nil {
+		ms.timer = time.AfterFunc(delay, s.flushMonitoringSave)
+	} else {
+		ms.timer.Reset(delay)
+	}
+}
+
+// flushMonitoringSave は保存待ちのスナップショットがあれば書き込む。
+// 書き込み中の保存があれば完了を待つため、戻った時点で保存待ちの変更はファイルに反映されている。
+// Shutdown から呼ばれる。
+func (s *PLCService) flushMonitoringSave() {
+	ms := &s.monitorSave
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.pendingMu.Lock()
+	if ms.timer != nil {
+		ms.timer.Stop()
+	}
+	items, seq, ok := ms.pending, ms.pendingSeq, ms.hasPending
+	ms.pending, ms.hasPending = nil, false
+	ms.pendingMu.Unlock()
+
+	if !ok {
+		return
+	}
+	if err := s.writeMonitoringSnapshotLocked(seq, items); err != nil {
+		s.logger.Warn("monitoring config not saved", "error", err)
+	}
+}
+
+// monitoringSnapshotLocked はモニタリング項目のコピーを返す（s.mu のロック必須）。
+// 保存中に Order などを書き換えられても影響しないよう、項目自体もコピーする。
+func (s *PLCService) monitoringSnapshotLocked() []*MonitoringItemDTO {
+	items := make([]*MonitoringItemDTO, 0, len(s.monitoringItems))
+	for _, item := range s.monitoringItems {
+		copied := *item
+		items = append(items, &copied)
+	}
+	return items
+}
+
+// writeMonitoringSnapshot は seq 番目のスナップショットを設定ファイルに書き込む
+func (s *PLCService) writeMonitoringSnapshot(seq uint64, items []*MonitoringItemDTO) error {
+	s.monitorSave.mu.Lock()
+	defer s.monitorSave.mu.Unlock()
+	return s.writeMonitoringSnapshotLocked(seq, items)
+}
+
+// writeMonitoringSnapshotLocked は seq 番目のスナップショットを設定ファイルに書き込む（monitorSave.mu のロック必須）。
+// より新しいスナップショットが書き込み済みの場合は何もしない。
+func (s *PLCService) writeMonitoringSnapshotLocked(seq uint64, items []*MonitoringItemDTO) error {
+	if seq < s.monitorSave.written {
+		return nil
+	}
+
+	configPath, err := s.configFilePath("monitoring_config.json")
+	if err != nil {
+		return err
+	}
+
+	config := &MonitoringConfigDTO{
+		Version: 1,
+		Items:   items,
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(configPath, data); err != nil {
+		return err
+	}
+	s.monitorSave.written = seq
+	s.monitorSave.writes++
+	return nil
+}
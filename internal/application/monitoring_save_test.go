@@ -0,0 +1,134 @@
+package application
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readSavedMonitoringConfig(t *testing.T, svc *PLCService) MonitoringConfigDTO {
+	t.Helper()
+	path, err := svc.configFilePath("monitoring_config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config MonitoringConfigDTO
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+// go test -race で自動保存とモニタリング項目の変更が競合しないことを確認する
+func TestPLCService_MonitoringAutosave_ConcurrentMutations(t *testing.T) {
+	svc := newTestService(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				item, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+					ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: i*10 + j, BitWidth: 16,
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				svc.MoveMonitoringItem(item.ID, "up") //nolint:errcheck
+				if err := svc.SaveMonitoringConfig(); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	svc.flushMonitoringSave()
+
+	// 最後の変更を反映した内容が保存されている
+	if got := len(readSavedMonitoringConfig(t, svc).Items); got != 80 {
+		t.Errorf("saved items = %d, want 80", got)
+	}
+}
+
+func TestPLCService_MonitoringAutosave_LatestSnapshotWins(t *testing.T) {
+	svc := newTestService(t)
+	for i := 0; i < 3; i++ {
+		if _, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+			ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: i, BitWidth: 16,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc.ClearMonitoringItems()
+	svc.flushMonitoringSave()
+
+	if got := len(readSavedMonitoringConfig(t, svc).Items); got != 0 {
+		t.Errorf("saved items = %d after clear, want 0", got)
+	}
+}
+
+func TestPLCService_MonitoringAutosave_Debounced(t *testing.T) {
+	svc := newTestService(t)
+	svc.monitorSave.delay = 100 * time.Millisecond
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		item, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+			ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: i, BitWidth: 16,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, item.ID)
+	}
+	for i := 0; i < 5; i++ {
+		if err := svc.ReorderMonitoringItem(ids[i%3], 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writes := func() int {
+		svc.monitorSave.mu.Lock()
+		defer svc.monitorSave.mu.Unlock()
+		return svc.monitorSave.writes
+	}
+	if got := writes(); got != 0 {
+		t.Fatalf("writes before the debounce window = %d, want 0", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for writes() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := writes(); got != 1 {
+		t.Errorf("writes after the debounce window = %d, want 1", got)
+	}
+	if got := len(readSavedMonitoringConfig(t, svc).Items); got != 3 {
+		t.Errorf("saved items = %d, want 3", got)
+	}
+}
+
+func TestPLCService_MonitoringAutosave_FlushedOnShutdown(t *testing.T) {
+	svc := newTestService(t)
+	svc.monitorSave.delay = time.Hour
+
+	if _, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+		ProtocolType: "modbus-tcp", MemoryArea: "coils", Address: 1, BitWidth: 1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc.Shutdown()
+
+	if got := len(readSavedMonitoringConfig(t, svc).Items); got != 1 {
+		t.Errorf("saved items after Shutdown = %d, want 1", got)
+	}
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPLCService_MonitoringScaling_DisplayAndWrite(t *testing.T) {
+	svc := newTestService(t)
+	item, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+		ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 0, BitWidth: 16,
+		DisplayFormat: "hex", Gain: 0.1, Unit: "℃",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 123); err != nil {
+		t.Fatal(err)
+	}
+
+	v := svc.ReadMonitoringValues()[0]
+	if v.EngineeringValue == nil || v.Unit != "℃" {
+		t.Fatalf("value = %+v, want engineering value with unit", v)
+	}
+	if got := formatMonitoringValue(item, v); got != "12.3" {
+		t.Errorf("formatted = %q, want 12.3", got)
+	}
+	if got := monitoringColumnName(item); got != "modbus-tcp:holdingRegisters:0 [℃]" {
+		t.Errorf("column name = %q", got)
+	}
+
+	// 工学値で書き込むと生値に戻る
+	if err := svc.WriteMonitoringValue(item.ID, 45.6); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); words[0] != 456 {
+		t.Errorf("raw after write = %d, want 456", words[0])
+	}
+	if err := svc.WriteMonitoringValue(item.ID, -1); err == nil {
+		t.Error("expected error for value below raw range")
+	}
+}
+
+func TestPLCService_WriteMonitoringValue_32BitWithOffset(t *testing.T) {
+	svc := newTestService(t)
+	item, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+		ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 10, BitWidth: 32,
+		Endianness: "little", Gain: 0.5, Offset: -100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (100000 + 100) / 0.5 = 200200 = 0x00030E08
+	if err := svc.WriteMonitoringValue(item.ID, 100000); err != nil {
+		t.Fatal(err)
+	}
+	words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 10, 2)
+	if !reflect.DeepEqual(words, []int{0x0E08, 0x0003}) {
+		t.Errorf("words = %#v", words)
+	}
+	if v := svc.ReadMonitoringValues()[0]; v.EngineeringValue == nil || *v.EngineeringValue != 100000 {
+		t.Errorf("engineering value = %v", v.EngineeringValue)
+	}
+	if err := svc.WriteMonitoringValue("no-such-item", 1); err == nil {
+		t.Error("expected error for unknown item")
+	}
+}
@@ -4,18 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
+	"modbus_simulator/internal/domain/datastore"
 	"modbus_simulator/internal/domain/protocol"
 	"modbus_simulator/internal/domain/script"
 	"modbus_simulator/internal/domain/variable"
 	"modbus_simulator/internal/infrastructure/adapter"
 	plugininfra "modbus_simulator/internal/infrastructure/plugin"
 	"modbus_simulator/internal/infrastructure/scripting"
+	"modbus_simulator/pkg/logging"
 
 	"github.com/google/uuid"
 )
@@ -30,7 +36,9 @@ type serverInstance struct {
 	server         protocol.ProtocolServer
 	changeListener *plugininfra.RemoteVariableChangeListener
 	cancelChange   context.CancelFunc
-	addedOrder     int // サーバー登録順（表示順の固定化に使用）
+	addedOrder     int             // サーバー登録順（表示順の固定化に使用）
+	lockedAreas    map[string]bool // UI からの書き込みを禁止しているエリア（LockArea）
+	excludedAreas  map[string]bool // プロジェクトにメモリ内容を保存しないエリア（SetAreaPersist）
 }
 
 // PLCService はPLCシミュレーターのメインサービス
@@ -50,6 +58,9 @@ type PLCService struct {
 	servers      map[protocol.ProtocolType]*serverInstance
 	serverSeq    int // AddServer 呼び出しごとに増加する登録順カウンター
 
+	// AddServer 時に読み込む初期値ファイル（空文字列は未設定）
+	initialStateFile string
+
 	// ホスト側 gRPC サーバー（OPC UA 等のプラグインから変数アクセスに使用）
 	hostGrpcServer *plugininfra.HostGrpcServer
 
@@ -60,12 +71,77 @@ type PLCService struct {
 	scriptEngine *scripting.ScriptEngine
 	scripts      map[string]*script.Script
 
+	// スクリプトの1回の実行（共有ロック）とエクスポート（排他ロック）を排他する。
+	// スクリプトは1回の実行で s.mu を何度も取得し直すため、s.mu だけでは実行途中の状態がエクスポートされうる。
+	// ロック順は exportMu → s.mu
+	exportMu sync.RWMutex
+
+	// 書き込みトリガー（DataStore の書き込み経路から参照するため s.mu とは別のロックで保護）
+	triggerMu sync.RWMutex
+	triggers  []*scriptTrigger
+
+	// 共有メモリ（書き込みトリガーと同じ理由で s.mu とは別のロックで保護）
+	shareMu      sync.RWMutex
+	sharedMemory bool
+	sharedStores []sharedStore
+
 	// モニタリング
 	monitoringItems map[string]*MonitoringItemDTO
 
+	// ベースライン（protocolType → CaptureBaseline 時点のスナップショット）
+	baselines map[protocol.ProtocolType]map[string]interface{}
+
+	// レシピ（名前 → メモリ値セット）
+	recipes map[string]*RecipeDTO
+	// インポートしたプロジェクトのレシピを使用中（変更はプロジェクトとして保存し、recipes.json には書き込まない）
+	recipesInProject bool
+
+	// monitoring_config.json・recipes.json の保存先（空の場合は保存しない）
+	configDir string
+
+	// スクリプトの readFloat32/writeFloat32 でバイト順を省略した場合の順序
+	defaultByteOrder datastore.ByteOrder
+
+	// モニタリング設定の自動保存
+	monitorSave monitoringSaver
+
+	// プロジェクトファイル上書き時のバックアップ世代数
+	projectBackupDepth int
+
+	// server:status イベントで最後に通知したサーバーの状態
+	statusTracker serverStatusTracker
+
+	// デモモード（s.mu を保持したまま停止を待たないよう別のロックで保護）
+	demoMu sync.Mutex
+	demo   *demoMode
+
+	// オートセーブ（デモモードと同じ理由で別のロックで保護）
+	autosaveMu sync.Mutex
+	autosave   *autosaver
+
+	// ドリフト動作（デモモードと同じ理由で別のロックで保護）
+	driftMu sync.Mutex
+	drifts  []*driftBehavior
+
+	// モニタリング値の CSV ログ（デモモードと同じ理由で別のロックで保護）
+	csvLogMu sync.Mutex
+	csvLog   *MonitoringCSVLog
+
+	// モニタリング項目ごとのアラーム発報状態（ID → 発報中）
+	alarmMu     sync.Mutex
+	alarmActive map[string]bool
+
+	// 診断ログ（標準エラー出力とメモリシンクの両方へ出力）
+	logger  *slog.Logger
+	logSink *logging.Sink
+
 	// 通信イベント
 	eventEmitter   protocol.CommunicationEventEmitter
 	sessionManager *protocol.SessionManager
+	trafficStats   *protocol.TrafficStats
+
+	// セッションのタイムアウト（0 の場合は defaultConnectionTimeout）
+	connectionTimeout time.Duration
 
 	// アプリケーション状態イベント
 	appEmitter        AppStateEmitter
@@ -74,21 +150,43 @@ type PLCService struct {
 
 // NewPLCService は新しいPLCServiceを作成する
 func NewPLCService() *PLCService {
+	return NewPLCServiceWithConfigDir(defaultConfigDir())
+}
+
+// NewPLCServiceWithConfigDir は設定ファイル（モニタリング設定・レシピ）を configDir に保存する PLCService を作成する
+func NewPLCServiceWithConfigDir(configDir string) *PLCService {
 	varStore := variable.NewVariableStore()
 
 	service := &PLCService{
-		factories:       make(map[protocol.ProtocolType]protocol.ServerFactory),
-		variableStore:   varStore,
-		vsAccessor:      adapter.NewVariableStoreAccessor(varStore),
-		servers:         make(map[protocol.ProtocolType]*serverInstance),
-		scriptEngine:    scripting.NewScriptEngine(varStore),
-		scripts:         make(map[string]*script.Script),
-		monitoringItems: make(map[string]*MonitoringItemDTO),
-	}
+		factories:          make(map[protocol.ProtocolType]protocol.ServerFactory),
+		variableStore:      varStore,
+		vsAccessor:         adapter.NewVariableStoreAccessor(varStore),
+		servers:            make(map[protocol.ProtocolType]*serverInstance),
+		scriptEngine:       scripting.NewScriptEngine(varStore),
+		scripts:            make(map[string]*script.Script),
+		monitoringItems:    make(map[string]*MonitoringItemDTO),
+		baselines:          make(map[protocol.ProtocolType]map[string]interface{}),
+		recipes:            make(map[string]*RecipeDTO),
+		defaultByteOrder:   datastore.ByteOrderABCD,
+		trafficStats:       protocol.NewTrafficStats(),
+		logSink:            logging.NewSink(maxLogEntries),
+		projectBackupDepth: defaultProjectBackupDepth,
+		configDir:          configDir,
+	}
+	service.logger = slog.New(logging.NewMultiHandler(logging.NewTextHandler(os.Stderr), service.logSink))
+	service.scriptEngine.SetLogger(service.logger)
+
+	// スクリプトからのメモリ直接操作
+	service.scriptEngine.SetMemoryAccessor(&scriptMemoryAccessor{service: service})
+	service.scriptEngine.SetStatsProvider(service)
+	service.scriptEngine.SetRunLock(service.exportMu.RLocker())
 
 	// モニタリング設定を読み込み
 	_ = service.LoadMonitoringConfig()
 
+	// レシピを読み込み
+	_ = service.LoadRecipes()
+
 	return service
 }
 
@@ -129,6 +227,7 @@ func (s *PLCService) InitPlugins(pluginsDir string) error {
 	hostAddr := s.GetHostGrpcAddr()
 
 	mgr := plugininfra.NewPluginProcessManager(hostAddr)
+	mgr.SetLogger(s.logger)
 	entries, err := mgr.DiscoverManifests(pluginsDir)
 	if err != nil {
 		return err
@@ -222,12 +321,15 @@ func (s *PLCService) AddServer(protocolType string, variantID string) error {
 	if remoteDS, isRemote := innerDataStore.(*plugininfra.RemoteDataStore); isRemote {
 		dataStore = innerDataStore
 		changeListener = plugininfra.NewRemoteVariableChangeListener(remoteDS, s.variableStore, protocolType)
+		changeListener.SetWriteHook(s.writeHook(protocol.ProtocolType(protocolType)))
 		ctx, cancel := context.WithCancel(context.Background())
 		cancelChange = cancel
 		go changeListener.StartChangeSubscription(ctx)
 	} else {
 		// インプロセス DataStore は VariableBackedDataStore でラップして双方向同期
-		dataStore = adapter.NewVariableBackedDataStore(innerDataStore, s.variableStore, protocolType)
+		backed := adapter.NewVariableBackedDataStore(innerDataStore, s.variableStore, protocolType)
+		backed.SetWriteHook(s.writeHook(pt))
+		dataStore = backed
 	}
 
 	server, err := factory.CreateServer(config, dataStore)
@@ -258,7 +360,20 @@ func (s *PLCService) AddServer(protocolType string, variantID string) error {
 		addedOrder:     s.serverSeq,
 	}
 
+	// 初期値ファイルを適用（失敗してもサーバーの追加は続ける）
+	if err := s.applyInitialState(inst); err != nil {
+		s.logger.Warn("initial state not applied", "protocol", protocolType, "error", err)
+	}
+
 	s.servers[pt] = inst
+	s.shareNewServerLocked(inst)
+
+	// セッションのタイムアウトを変更済みの場合はサーバー設定に反映する
+	if s.connectionTimeout != 0 {
+		if err := s.applyConnectionTimeoutLocked(inst); err != nil {
+			s.logger.Warn("connection timeout not applied", "protocol", protocolType, "error", err)
+		}
+	}
 
 	// イベントエミッターをサーバーに設定
 	if s.eventEmitter != nil {
@@ -307,7 +422,12 @@ func (s *PLCService) RemoveServer(protocolType string) error {
 		stopper.StopProcess()
 	}
 
+	s.notifyServerStatusLocked(inst)
+	s.forgetServerStatus(pt)
+
 	delete(s.servers, pt)
+	delete(s.baselines, pt)
+	s.refreshSharedStoresLocked()
 
 	go s.emitServerChanged()
 
@@ -338,6 +458,15 @@ func (s *PLCService) StartServer(protocolType string) error {
 	if inst.server == nil {
 		return fmt.Errorf("server not initialized")
 	}
+	defer s.notifyServerStatusLocked(inst)
+
+	// バインドエラーより分かりやすいメッセージを返すため、起動前にポートを確認する
+	if inst.server.Status() != protocol.StatusRunning {
+		if err := s.checkServerPort(inst); err != nil {
+			s.logger.Error("failed to start server", "protocol", protocolType, "error", err)
+			return err
+		}
+	}
 
 	startErr := inst.server.Start(context.Background())
 	if startErr == nil {
@@ -350,10 +479,13 @@ func (s *PLCService) StartServer(protocolType string) error {
 	type pluginReconnector interface{ ForceReconnect() error }
 	reconnector, ok := inst.factory.(pluginReconnector)
 	if !ok {
+		// Error 状態を UI に反映させる
+		s.logger.Error("failed to start server", "protocol", protocolType, "error", startErr)
+		go s.emitServerChanged()
 		return fmt.Errorf("サーバーの起動に失敗しました: %w", startErr)
 	}
 
-	fmt.Fprintf(os.Stderr, "[INFO] StartServer 失敗。再接続を試みます (protocol=%s)\n", protocolType)
+	s.logger.Info("start failed, reconnecting plugin", "protocol", protocolType, "error", startErr)
 	if rerr := reconnector.ForceReconnect(); rerr != nil {
 		return fmt.Errorf("再接続失敗 (start=%v, reconnect=%w)", startErr, rerr)
 	}
@@ -394,11 +526,14 @@ func (s *PLCService) rebuildServerInstance(inst *serverInstance) error {
 	if remoteDS, isRemote := innerDataStore.(*plugininfra.RemoteDataStore); isRemote {
 		dataStore = innerDataStore
 		changeListener = plugininfra.NewRemoteVariableChangeListener(remoteDS, s.variableStore, protocolType)
+		changeListener.SetWriteHook(s.writeHook(protocol.ProtocolType(protocolType)))
 		ctx, cancel := context.WithCancel(context.Background())
 		cancelChange = cancel
 		go changeListener.StartChangeSubscription(ctx)
 	} else {
-		dataStore = adapter.NewVariableBackedDataStore(innerDataStore, s.variableStore, protocolType)
+		backed := adapter.NewVariableBackedDataStore(innerDataStore, s.variableStore, protocolType)
+		backed.SetWriteHook(s.writeHook(inst.protocolType))
+		dataStore = backed
 	}
 
 	server, err := factory.CreateServer(inst.config, dataStore)
@@ -417,6 +552,7 @@ func (s *PLCService) rebuildServerInstance(inst *serverInstance) error {
 	inst.server = server
 	inst.changeListener = changeListener
 	inst.cancelChange = cancelChange
+	s.refreshSharedStoresLocked()
 
 	if s.eventEmitter != nil {
 		s.setEmitterToServerInstance(inst)
@@ -435,6 +571,7 @@ func (s *PLCService) StopServer(protocolType string) error {
 		return err
 	}
 	if inst.server != nil {
+		defer s.notifyServerStatusLocked(inst)
 		if err := inst.server.Stop(); err != nil {
 			return err
 		}
@@ -459,6 +596,24 @@ func (s *PLCService) GetServerStatus(protocolType string) string {
 	return "Stopped"
 }
 
+// GetServerError はサーバーがエラー状態の場合にその原因を返す（エラーがなければ空文字）。
+// 特権ポートへのバインド失敗などの案内メッセージを UI に表示するために使う。
+func (s *PLCService) GetServerError(protocolType string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil || inst.server == nil {
+		return ""
+	}
+	if r, ok := inst.server.(protocol.ErrorReporter); ok {
+		if err := r.LastError(); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
 // === プロトコル管理API ===
 
 // GetAvailableProtocols は利用可能なプロトコル一覧を返す
@@ -548,6 +703,17 @@ func (s *PLCService) GetProtocolSchema(protocolType string) (*ProtocolSchemaDTO,
 	}, nil
 }
 
+// GetConfigJSONSchema はプロトコル設定の JSON Schema を返す（ConfigField 定義から生成）
+func (s *PLCService) GetConfigJSONSchema(protocolType string) (json.RawMessage, error) {
+	s.mu.RLock()
+	factory, ok := s.factories[protocol.ProtocolType(protocolType)]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("protocol not found: %s", protocolType)
+	}
+	return marshalConfigJSONSchema(factory)
+}
+
 // GetServerConfig は指定サーバーの現在の設定を返す
 func (s *PLCService) GetServerConfig(protocolType string) *ServerConfigDTO {
 	s.mu.RLock()
@@ -565,7 +731,9 @@ func (s *PLCService) GetServerConfig(protocolType string) *ServerConfigDTO {
 	}
 }
 
-// UpdateServerConfig はサーバーの設定を更新する
+// UpdateServerConfig はサーバーの設定を更新する。
+// 同じバリアントで待ち受けに関わらない設定だけが変わった場合は、サーバーとデータストアをそのまま使い、実行中でも反映する。
+// 待ち受けの設定が変わった場合はサーバーを作り直し、実行中だった場合は新しい設定で起動し直す。
 func (s *PLCService) UpdateServerConfig(dto *ServerConfigDTO) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -575,12 +743,12 @@ func (s *PLCService) UpdateServerConfig(dto *ServerConfigDTO) error {
 		return err
 	}
 
-	if inst.server != nil && inst.server.Status() == protocol.StatusRunning {
-		return fmt.Errorf("cannot update config while server is running")
-	}
-
 	// バリアントが変わる場合は新しいサーバーを作成
-	if dto.Variant != inst.variant {
+	variantChanged := dto.Variant != inst.variant
+	if variantChanged {
+		if inst.server != nil && inst.server.Status() == protocol.StatusRunning {
+			return fmt.Errorf("cannot update config while server is running")
+		}
 		config := inst.factory.CreateConfigFromVariant(dto.Variant)
 		server, err := inst.factory.CreateServer(config, inst.dataStore)
 		if err != nil {
@@ -600,11 +768,13 @@ func (s *PLCService) UpdateServerConfig(dto *ServerConfigDTO) error {
 	if err != nil {
 		return err
 	}
+	if !variantChanged {
+		return s.applyServerConfigLocked(inst, newConfig)
+	}
 
 	if err := inst.server.UpdateConfig(newConfig); err != nil {
 		return err
 	}
-
 	inst.config = newConfig
 	go s.emitServerChanged()
 	return nil
@@ -657,6 +827,10 @@ func (s *PLCService) SetUnitIDEnabled(protocolType string, unitId int, enabled b
 		return err
 	}
 
+	if err := checkUnitIDRange(inst, unitId); err != nil {
+		return err
+	}
+
 	type unitIDSupporter interface {
 		SetUnitIdEnabled(unitId uint8, enabled bool)
 	}
@@ -704,6 +878,12 @@ func (s *PLCService) SetDisabledUnitIDs(protocolType string, ids []int) error {
 		return err
 	}
 
+	for _, id := range ids {
+		if err := checkUnitIDRange(inst, id); err != nil {
+			return err
+		}
+	}
+
 	type unitIDSupporter interface {
 		SetDisabledUnitIDs(ids []uint8)
 	}
@@ -720,6 +900,242 @@ func (s *PLCService) SetDisabledUnitIDs(protocolType string, ids []int) error {
 	return fmt.Errorf("protocol does not support unit ID")
 }
 
+// checkUnitIDRange は UnitID がプロトコルの機能情報（UnitIDMin〜UnitIDMax）の範囲内か検証する。
+// UnitID をサポートしないプロトコルは呼び出し側の判定に任せる。
+func checkUnitIDRange(inst *serverInstance, unitId int) error {
+	caps := inst.factory.GetProtocolCapabilities()
+	if caps.SupportsUnitID && (unitId < caps.UnitIDMin || unitId > caps.UnitIDMax) {
+		return fmt.Errorf("unit ID %d out of range (%d-%d)", unitId, caps.UnitIDMin, caps.UnitIDMax)
+	}
+	return nil
+}
+
+// === 通信シミュレーション設定 ===
+
+// SetRateLimit は1接続あたりの秒間リクエスト数を設定する（0以下で無制限）。
+// busy=true の場合は超過時に busy 例外を返し、false の場合は処理を遅延させる。
+// 設定はサーバー設定（rateLimit / rateLimitBusy）としてプロジェクトに保存される。
+func (s *PLCService) SetRateLimit(protocolType string, rps int, busy bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if rps < 0 {
+		rps = 0
+	}
+
+	return s.updateServerSettingsLocked(inst, "rate limiting", map[string]interface{}{
+		"rateLimit":     rps,
+		"rateLimitBusy": strconv.FormatBool(busy),
+	})
+}
+
+// SetMaxReadQuantity は読み取り系ファンクションコードの最大読み取り数を設定する（0以下で仕様値に戻す）。
+// 上限を超える読み取りには Illegal Data Value (0x03) 例外を返す。
+// 設定はサーバー設定（maxReadQuantities）としてプロジェクトに保存される。
+func (s *PLCService) SetMaxReadQuantity(protocolType string, fc, limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if fc < 0 || fc > 255 {
+		return fmt.Errorf("invalid function code: %d", fc)
+	}
+
+	return s.updateServerSettingPairLocked(inst, "read quantity limits", "maxReadQuantities", strconv.Itoa(fc), readQuantityValue(limit))
+}
+
+// SetUnitMaxReadQuantity は指定 UnitID のみに適用する最大読み取り数を設定する（0以下で共通設定に戻す）
+func (s *PLCService) SetUnitMaxReadQuantity(protocolType string, unitId, fc, limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if unitId < 0 || unitId > 255 {
+		return fmt.Errorf("invalid unit ID: %d", unitId)
+	}
+	if fc < 0 || fc > 255 {
+		return fmt.Errorf("invalid function code: %d", fc)
+	}
+
+	key := fmt.Sprintf("%d:%d", unitId, fc)
+	return s.updateServerSettingPairLocked(inst, "read quantity limits", "maxReadQuantities", key, readQuantityValue(limit))
+}
+
+// readQuantityValue は最大読み取り数を設定値に変換する（0以下は設定の削除を表す空文字）
+func readQuantityValue(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return strconv.Itoa(limit)
+}
+
+// SetAutoReconnect はシリアルポート喪失時（USB 抜去等）に自動で再接続を試みるかどうかを設定する。
+// 無効の場合、ポート喪失を検出するとサーバーは Error 状態で停止する。
+// 設定はサーバー設定（autoReconnect）としてプロジェクトに保存される。
+func (s *PLCService) SetAutoReconnect(protocolType string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+
+	return s.updateServerSettingsLocked(inst, "auto reconnect", map[string]interface{}{
+		"autoReconnect": strconv.FormatBool(enabled),
+	})
+}
+
+// SetPerUnitStore は UnitID ごとに別々のメモリを持たせるかどうかを設定する。
+// false（デフォルト）の場合は全 UnitID が同じメモリに応答する。
+// 設定はサーバー設定（perUnitStore）としてプロジェクトに保存される。
+func (s *PLCService) SetPerUnitStore(protocolType string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+
+	return s.updateServerSettingsLocked(inst, "per-unit memory", map[string]interface{}{
+		"perUnitStore": strconv.FormatBool(enabled),
+	})
+}
+
+// IsPerUnitStore は UnitID ごとのメモリが有効かどうかを返す
+func (s *PLCService) IsPerUnitStore(protocolType string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return false
+	}
+	return settingEnabled(inst.factory.ConfigToMap(inst.config)["perUnitStore"])
+}
+
+// SetUnitIDRemap は UnitID の読み替え表（要求された UnitID → 応答に使う UnitID）を設定する。
+// 例えば {5: 1} の場合、UnitID 5 へのリクエストを UnitID 1 のメモリで処理する（SetPerUnitStore が有効な場合のみ意味を持つ）。
+// nil または空の表で解除する。設定はサーバー設定（unitIdRemap）としてプロジェクトに保存される。
+func (s *PLCService) SetUnitIDRemap(protocolType string, remap map[int]int) error {
+	froms := make([]int, 0, len(remap))
+	for from, to := range remap {
+		if from < 0 || from > 255 || to < 0 || to > 255 {
+			return fmt.Errorf("invalid unit ID remap: %d -> %d", from, to)
+		}
+		froms = append(froms, from)
+	}
+	sort.Ints(froms)
+	pairs := make([]string, len(froms))
+	for i, from := range froms {
+		pairs[i] = fmt.Sprintf("%d=%d", from, remap[from])
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+
+	return s.updateServerSettingsLocked(inst, "unit ID remap", map[string]interface{}{
+		"unitIdRemap": strings.Join(pairs, ","),
+	})
+}
+
+// GetUnitIDRemap は UnitID の読み替え表を返す（未設定・未対応の場合は空）
+func (s *PLCService) GetUnitIDRemap(protocolType string) map[int]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int]int)
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return result
+	}
+
+	list, _ := inst.factory.ConfigToMap(inst.config)["unitIdRemap"].(string)
+	for _, pair := range strings.Split(list, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		from, err1 := strconv.Atoi(strings.TrimSpace(k))
+		to, err2 := strconv.Atoi(strings.TrimSpace(v))
+		if err1 == nil && err2 == nil {
+			result[from] = to
+		}
+	}
+	return result
+}
+
+// ResizeArea はメモリエリアの点数を変更する（拡張部分はゼロ埋め、縮小時は末尾を破棄）。
+// 通信処理との競合を避けるため、サーバー停止中のみ変更できる。
+// 点数はサーバー設定（areaSizes）としてプロジェクトに保存される。
+func (s *PLCService) ResizeArea(protocolType, area string, size int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if inst.server.Status() == protocol.StatusRunning {
+		return fmt.Errorf("cannot resize area while server is running")
+	}
+	if _, err := findMemoryArea(inst.dataStore, area); err != nil {
+		return err
+	}
+
+	return s.updateServerSettingPairLocked(inst, "resizing memory areas", "areaSizes", area, strconv.Itoa(size))
+}
+
+// SetOutOfRangePolicy は範囲外アドレスを読み取った場合の動作を設定する。
+// "error"（デフォルト・例外応答）、"zero"（ゼロを返す）、"wrap"（先頭に折り返す）のいずれか。
+// 設定はサーバー設定（outOfRangePolicy）としてプロジェクトに保存される。
+func (s *PLCService) SetOutOfRangePolicy(protocolType, policy string) error {
+	p, err := datastore.ParseOutOfRangePolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	return s.updateServerSettingsLocked(inst, "out-of-range policy", map[string]interface{}{"outOfRangePolicy": string(p)})
+}
+
+// GetOutOfRangePolicy は範囲外読み取り時の動作を返す
+func (s *PLCService) GetOutOfRangePolicy(protocolType string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return string(datastore.OutOfRangeError)
+	}
+	if p, ok := inst.factory.ConfigToMap(inst.config)["outOfRangePolicy"].(string); ok && p != "" {
+		return p
+	}
+	return string(datastore.OutOfRangeError)
+}
+
 // === 汎用メモリ操作API ===
 
 // GetMemoryAreas は利用可能なメモリエリアの一覧を返す
@@ -769,6 +1185,9 @@ func (s *PLCService) WriteBit(protocolType, area string, address int, value bool
 	if err != nil {
 		return err
 	}
+	if err := checkAreaUnlocked(inst, area); err != nil {
+		return err
+	}
 	if err := inst.dataStore.WriteBit(area, uint32(address), value); err != nil {
 		return err
 	}
@@ -776,6 +1195,7 @@ func (s *PLCService) WriteBit(protocolType, area string, address int, value bool
 	// 自分で変数を同期する（VariableBackedDataStore の場合は WriteWord 内で自動的に同期済み）
 	if inst.changeListener != nil {
 		go inst.changeListener.SyncHostBitWriteToVariable(area, uint32(address))
+		s.notifyStoreWrite(inst.protocolType, area, uint32(address), 1)
 	}
 	return nil
 }
@@ -810,6 +1230,9 @@ func (s *PLCService) WriteWord(protocolType, area string, address int, value int
 	if err != nil {
 		return err
 	}
+	if err := checkAreaUnlocked(inst, area); err != nil {
+		return err
+	}
 	if err := inst.dataStore.WriteWord(area, uint32(address), uint16(value)); err != nil {
 		return err
 	}
@@ -817,49 +1240,597 @@ func (s *PLCService) WriteWord(protocolType, area string, address int, value int
 	// 自分で変数を同期する（VariableBackedDataStore の場合は WriteWord 内で自動的に同期済み）
 	if inst.changeListener != nil {
 		go inst.changeListener.SyncHostWordWriteToVariable(area, uint32(address))
+		s.notifyStoreWrite(inst.protocolType, area, uint32(address), 1)
 	}
 	return nil
 }
 
-// === スクリプト管理 ===
-
-// CreateScript は新しいスクリプトを作成する
-func (s *PLCService) CreateScript(name, code string, intervalMs int) (*ScriptDTO, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// === UnitID 指定メモリ操作API ===
+// UnitID ごとのメモリ（SetPerUnitStore）が有効な場合に、各スレーブのメモリを個別に読み書きする。
+// UnitID 1 および UnitID 別メモリが無効な場合は、UnitID なしの API と同じメモリを操作する。
 
-	id := uuid.New().String()
-	sc := script.NewScript(id, name, code, time.Duration(intervalMs)*time.Millisecond)
-	s.scripts[id] = sc
+// defaultUnitID は UnitID なしの API が操作するメモリに対応する UnitID
+const defaultUnitID = 1
 
-	go s.emitScriptsChanged()
+// unitDataStore は UnitID に対応する DataStore を返す。
+// shared=true の場合は inst.dataStore（変数同期の対象）そのものを返している。
+// 呼び出し元で s.mu をロックしていること。
+func (s *PLCService) unitDataStore(inst *serverInstance, unitId int) (store protocol.DataStore, shared bool, err error) {
+	if unitId < 0 || unitId > 255 {
+		return nil, false, fmt.Errorf("invalid unit ID: %d", unitId)
+	}
 
-	return scriptToDTO(sc, false, "", 0), nil
+	perUnit := settingEnabled(inst.factory.ConfigToMap(inst.config)["perUnitStore"])
+	if !perUnit || unitId == defaultUnitID {
+		return inst.dataStore, true, nil
+	}
+	us, ok := inst.server.(protocol.UnitMemoryAccessor)
+	if !ok {
+		return nil, false, fmt.Errorf("protocol does not support per-unit memory")
+	}
+	return us.UnitDataStore(uint8(unitId)), false, nil
 }
 
-// UpdateScript はスクリプトを更新する
-func (s *PLCService) UpdateScript(id, name, code string, intervalMs int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// ReadWordsForUnit は指定 UnitID のエリアから複数ワード値を読み込む
+func (s *PLCService) ReadWordsForUnit(protocolType string, unitId int, area string, address, count int) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	sc, ok := s.scripts[id]
-	if !ok {
-		return fmt.Errorf("script not found: %s", id)
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
 	}
-
-	// 実行中なら一旦停止
-	wasRunning := s.scriptEngine.IsRunning(id)
-	if wasRunning {
-		s.scriptEngine.StopScript(id)
+	store, _, err := s.unitDataStore(inst, unitId)
+	if err != nil {
+		return nil, err
 	}
 
-	sc.Name = name
-	sc.Code = code
-	sc.Interval = time.Duration(intervalMs) * time.Millisecond
-
-	// 実行中だった場合は再開
-	if wasRunning {
-		s.scriptEngine.StartScript(sc)
+	vals, err := store.ReadWords(area, uint32(address), uint16(count))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int, len(vals))
+	for i, v := range vals {
+		result[i] = int(v)
+	}
+	return result, nil
+}
+
+// WriteWordForUnit は指定 UnitID のエリアにワード値を書き込む
+func (s *PLCService) WriteWordForUnit(protocolType string, unitId int, area string, address int, value int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if err := checkAreaUnlocked(inst, area); err != nil {
+		return err
+	}
+	store, shared, err := s.unitDataStore(inst, unitId)
+	if err != nil {
+		return err
+	}
+	if err := store.WriteWord(area, uint32(address), uint16(value)); err != nil {
+		return err
+	}
+	// 変数とマッピング・トリガー・共有メモリの対象は共有のデータストアのみ（WriteWord と同様）
+	if shared && inst.changeListener != nil {
+		go inst.changeListener.SyncHostWordWriteToVariable(area, uint32(address))
+		s.notifyStoreWrite(inst.protocolType, area, uint32(address), 1)
+	}
+	return nil
+}
+
+// ReadBitsForUnit は指定 UnitID のエリアから複数ビット値を読み込む
+func (s *PLCService) ReadBitsForUnit(protocolType string, unitId int, area string, address, count int) ([]bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	store, _, err := s.unitDataStore(inst, unitId)
+	if err != nil {
+		return nil, err
+	}
+	return store.ReadBits(area, uint32(address), uint16(count))
+}
+
+// WriteBitForUnit は指定 UnitID のエリアにビット値を書き込む
+func (s *PLCService) WriteBitForUnit(protocolType string, unitId int, area string, address int, value bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	if err := checkAreaUnlocked(inst, area); err != nil {
+		return err
+	}
+	store, shared, err := s.unitDataStore(inst, unitId)
+	if err != nil {
+		return err
+	}
+	if err := store.WriteBit(area, uint32(address), value); err != nil {
+		return err
+	}
+	if shared && inst.changeListener != nil {
+		go inst.changeListener.SyncHostBitWriteToVariable(area, uint32(address))
+		s.notifyStoreWrite(inst.protocolType, area, uint32(address), 1)
+	}
+	return nil
+}
+
+// FillArea は指定エリア全体を同じ値で埋める（ビットエリアの場合は 0 以外を true とする）。
+// ロック中のエリアには書き込まない（スクリプトからの書き込みは fillAreaPattern でロックを無視する）。
+func (s *PLCService) FillArea(protocolType, area string, value int) error {
+	return s.fillAreaPattern(protocolType, area, []int{value}, true)
+}
+
+// FillAreaPattern は指定エリア全体をパターンの繰り返しで埋める
+// 例: pattern=[1,2,3] の場合 1,2,3,1,2,3,... となる。ロック中のエリアには書き込まない。
+func (s *PLCService) FillAreaPattern(protocolType, area string, pattern []int) error {
+	return s.fillAreaPattern(protocolType, area, pattern, true)
+}
+
+// fillAreaPattern は FillAreaPattern の本体。
+// checkLock=true（UI からの書き込み）の場合、ロック中のエリアには書き込まない。
+func (s *PLCService) fillAreaPattern(protocolType, area string, pattern []int, checkLock bool) error {
+	if len(pattern) == 0 {
+		return fmt.Errorf("pattern is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	areaInfo, err := findMemoryArea(inst.dataStore, area)
+	if err != nil {
+		return err
+	}
+	if checkLock {
+		if err := checkAreaUnlocked(inst, area); err != nil {
+			return err
+		}
+	}
+
+	if areaInfo.IsBit {
+		values := make([]bool, areaInfo.Size)
+		for i := range values {
+			values[i] = pattern[i%len(pattern)] != 0
+		}
+		if err := inst.dataStore.WriteBits(area, 0, values); err != nil {
+			return err
+		}
+	} else {
+		values := make([]uint16, areaInfo.Size)
+		for i := range values {
+			values[i] = uint16(pattern[i%len(pattern)])
+		}
+		if err := inst.dataStore.WriteWords(area, 0, values); err != nil {
+			return err
+		}
+	}
+
+	// リモートプラグイン DataStore の場合、ホスト書き込みはプラグインから通知が来ないため
+	// 自分で変数を同期する（VariableBackedDataStore の場合は WriteWords 内で自動的に同期済み）
+	if inst.changeListener != nil {
+		listener := inst.changeListener
+		go func() {
+			for addr := uint32(0); addr < areaInfo.Size; addr++ {
+				if areaInfo.IsBit {
+					listener.SyncHostBitWriteToVariable(area, addr)
+				} else {
+					listener.SyncHostWordWriteToVariable(area, addr)
+				}
+			}
+		}()
+	}
+	s.notifyStoreWrite(inst.protocolType, area, 0, int(areaInfo.Size))
+	return nil
+}
+
+// findMemoryArea は DataStore から指定IDのエリア定義を探す
+func findMemoryArea(ds protocol.DataStore, area string) (protocol.MemoryArea, error) {
+	if a, ok := ds.GetArea(area); ok {
+		return a, nil
+	}
+	return protocol.MemoryArea{}, fmt.Errorf("%w: %s", datastore.ErrAreaNotFound, area)
+}
+
+// memoryReadChunk は ReadBits/ReadWords 1回あたりの最大読み取り点数（count は uint16 のため）
+const memoryReadChunk = 4096
+
+// readAreaRange は指定範囲の値を memoryReadChunk ごとに分けて読み取る。
+// ビットエリアは []bool、ワードエリアは []int を返す。
+func readAreaRange(ds protocol.DataStore, area protocol.MemoryArea, offset, length int) (interface{}, error) {
+	if area.IsBit {
+		result := make([]bool, 0, length)
+		for pos := offset; pos < offset+length; pos += memoryReadChunk {
+			n := min(memoryReadChunk, offset+length-pos)
+			vals, err := ds.ReadBits(area.ID, uint32(pos), uint16(n))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, vals...)
+		}
+		return result, nil
+	}
+
+	result := make([]int, 0, length)
+	for pos := offset; pos < offset+length; pos += memoryReadChunk {
+		n := min(memoryReadChunk, offset+length-pos)
+		vals, err := ds.ReadWords(area.ID, uint32(pos), uint16(n))
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vals {
+			result = append(result, int(v))
+		}
+	}
+	return result, nil
+}
+
+// ReadAllAreas は全エリアの現在値をまとめて返す（UI の全メモリ表示を1回の呼び出しで更新するため）。
+// キーはエリアID、値はビットエリアなら []bool、ワードエリアなら []int。
+func (s *PLCService) ReadAllAreas(protocolType string) map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil
+	}
+
+	result := make(map[string]interface{})
+	for _, area := range inst.dataStore.GetAreas() {
+		values, err := readAreaRange(inst.dataStore, area, 0, int(area.Size))
+		if err != nil {
+			s.logger.Warn("failed to read area", "protocol", protocolType, "area", area.ID, "error", err)
+			continue
+		}
+		result[area.ID] = values
+	}
+	return result
+}
+
+// GetMemoryPage は大きなエリアをページ単位で読み取る。
+// offset+length がエリアサイズを超える場合は末尾までを返す。
+func (s *PLCService) GetMemoryPage(protocolType, area string, offset, length int) (*MemoryPageDTO, error) {
+	if offset < 0 || length < 0 {
+		return nil, fmt.Errorf("%w: offset=%d length=%d", datastore.ErrAddressOutOfRange, offset, length)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	areaInfo, err := findMemoryArea(inst.dataStore, area)
+	if err != nil {
+		return nil, err
+	}
+	size := int(areaInfo.Size)
+	if offset > size {
+		return nil, fmt.Errorf("%w: offset %d (size %d)", datastore.ErrAddressOutOfRange, offset, size)
+	}
+	length = min(length, size-offset)
+
+	values, err := readAreaRange(inst.dataStore, areaInfo, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	page := &MemoryPageDTO{
+		Area:      area,
+		Offset:    offset,
+		Length:    length,
+		TotalSize: size,
+		IsBit:     areaInfo.IsBit,
+	}
+	if areaInfo.IsBit {
+		page.Bits = values.([]bool)
+	} else {
+		page.Words = values.([]int)
+	}
+	return page, nil
+}
+
+// ExportRangeHex はワードエリアの範囲を "1234 ABCD" 形式（空白区切りの4桁16進）で返す
+func (s *PLCService) ExportRangeHex(protocolType, area string, start, count int) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return "", err
+	}
+	areaInfo, err := findMemoryArea(inst.dataStore, area)
+	if err != nil {
+		return "", err
+	}
+	if areaInfo.IsBit {
+		return "", fmt.Errorf("%w: hex export requires a word area", datastore.ErrTypeMismatch)
+	}
+	if start < 0 || count < 0 || start+count > int(areaInfo.Size) {
+		return "", fmt.Errorf("%w: start=%d count=%d", datastore.ErrAddressOutOfRange, start, count)
+	}
+
+	values, err := readAreaRange(inst.dataStore, areaInfo, start, count)
+	if err != nil {
+		return "", err
+	}
+	return formatHexWords(values.([]int)), nil
+}
+
+// ImportRangeHex は16進文字列をワードエリアの start から書き込む。
+// "1234 ABCD" と "0x1234,0xABCD" の両方の形式を受け付ける。
+// ロック中のエリアには書き込まない（スクリプトからの書き込みは importRangeHex でロックを無視する）。
+func (s *PLCService) ImportRangeHex(protocolType, area string, start int, hex string) error {
+	return s.importRangeHex(protocolType, area, start, hex, true)
+}
+
+func (s *PLCService) importRangeHex(protocolType, area string, start int, hex string, checkLock bool) error {
+	values, err := parseHexWords(hex)
+	if err != nil {
+		return err
+	}
+	return s.writeWordRange(protocolType, area, start, values, checkLock)
+}
+
+// writeWordRange はワードエリアの start から values を書き込む。
+// 範囲全体を検証してから書き込み、リモートプラグインの場合は変数の同期とトリガーの発火を行う。
+// checkLock=true（UI からの書き込み）の場合、ロック中のエリアには書き込まない。
+func (s *PLCService) writeWordRange(protocolType, area string, start int, values []uint16, checkLock bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	areaInfo, err := findMemoryArea(inst.dataStore, area)
+	if err != nil {
+		return err
+	}
+	if areaInfo.IsBit {
+		return fmt.Errorf("%w: word write requires a word area", datastore.ErrTypeMismatch)
+	}
+	if checkLock {
+		if err := checkAreaUnlocked(inst, area); err != nil {
+			return err
+		}
+	}
+	if start < 0 || start+len(values) > int(areaInfo.Size) {
+		return fmt.Errorf("%w: start=%d count=%d", datastore.ErrAddressOutOfRange, start, len(values))
+	}
+	if err := inst.dataStore.WriteWords(area, uint32(start), values); err != nil {
+		return err
+	}
+
+	// リモートプラグイン DataStore の場合は自分で変数を同期する（WriteWord と同様）
+	if inst.changeListener != nil {
+		listener := inst.changeListener
+		go func() {
+			for i := range values {
+				listener.SyncHostWordWriteToVariable(area, uint32(start+i))
+			}
+		}()
+		s.notifyStoreWrite(inst.protocolType, area, uint32(start), len(values))
+	}
+	return nil
+}
+
+// formatHexWords はワード値を空白区切りの4桁16進（大文字）に変換する
+func formatHexWords(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%04X", v)
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseHexWords は空白・カンマ区切りの16進文字列をワード値に変換する（"0x" 接頭辞は省略可）
+func parseHexWords(hex string) ([]uint16, error) {
+	fields := strings.FieldsFunc(hex, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty hex string", datastore.ErrInvalidData)
+	}
+	values := make([]uint16, len(fields))
+	for i, f := range fields {
+		digits := strings.TrimPrefix(strings.TrimPrefix(f, "0x"), "0X")
+		v, err := strconv.ParseUint(digits, 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid hex word %q", datastore.ErrInvalidData, f)
+		}
+		values[i] = uint16(v)
+	}
+	return values, nil
+}
+
+// GetMemorySnapshot は指定プロトコルの現在のメモリ内容のスナップショットを返す
+func (s *PLCService) GetMemorySnapshot(protocolType string) (map[string]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	return inst.dataStore.Snapshot(), nil
+}
+
+// DiffSnapshots は2つのスナップショットを比較し、値が異なるアドレスの一覧を返す
+func (s *PLCService) DiffSnapshots(a, b map[string]interface{}) ([]MemoryDiffDTO, error) {
+	changes, err := datastore.DiffSnapshots(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return changesToDTO(changes), nil
+}
+
+// DiffWithSnapshot は指定したスナップショットと現在のメモリ内容を比較する
+// （old がスナップショット側、new が現在値）
+func (s *PLCService) DiffWithSnapshot(protocolType string, snapshot map[string]interface{}) ([]MemoryDiffDTO, error) {
+	current, err := s.GetMemorySnapshot(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	return s.DiffSnapshots(snapshot, current)
+}
+
+// CaptureBaseline は現在のメモリ内容をベースラインとしてメモリ上に保存する
+func (s *PLCService) CaptureBaseline(protocolType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	s.baselines[inst.protocolType] = inst.dataStore.Snapshot()
+	return nil
+}
+
+// HasBaseline はベースラインが保存されているかを返す
+func (s *PLCService) HasBaseline(protocolType string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.baselines[protocol.ProtocolType(protocolType)]
+	return ok
+}
+
+// RevertToBaseline はメモリ内容を保存済みのベースラインに戻す
+func (s *PLCService) RevertToBaseline(protocolType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return err
+	}
+	baseline, ok := s.baselines[inst.protocolType]
+	if !ok {
+		return fmt.Errorf("baseline not captured: %s", protocolType)
+	}
+
+	// リモートプラグインの変数同期のため、復元前に変化するアドレスを求めておく
+	var changes []datastore.Change
+	if inst.changeListener != nil {
+		changes, _ = datastore.DiffSnapshots(inst.dataStore.Snapshot(), baseline)
+	}
+
+	if err := inst.dataStore.Restore(baseline); err != nil {
+		return fmt.Errorf("ベースラインの復元に失敗: %w", err)
+	}
+
+	// リモートプラグイン DataStore の場合、ホスト書き込みはプラグインから通知が来ないため
+	// 自分で変数を同期する（VariableBackedDataStore の場合は Restore 内で自動的に同期済み）
+	if inst.changeListener != nil && len(changes) > 0 {
+		listener := inst.changeListener
+		go func() {
+			for _, c := range changes {
+				if c.IsBit {
+					listener.SyncHostBitWriteToVariable(c.Area, c.Address)
+				} else {
+					listener.SyncHostWordWriteToVariable(c.Area, c.Address)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// DiffWithBaseline は保存済みのベースラインと現在のメモリ内容を比較する
+func (s *PLCService) DiffWithBaseline(protocolType string) ([]MemoryDiffDTO, error) {
+	s.mu.RLock()
+	baseline, ok := s.baselines[protocol.ProtocolType(protocolType)]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("baseline not captured: %s", protocolType)
+	}
+	return s.DiffWithSnapshot(protocolType, baseline)
+}
+
+func changesToDTO(changes []datastore.Change) []MemoryDiffDTO {
+	result := make([]MemoryDiffDTO, len(changes))
+	for i, c := range changes {
+		dto := MemoryDiffDTO{
+			Area:    c.Area,
+			Address: int(c.Address),
+			IsBit:   c.IsBit,
+			Old:     c.Old,
+			New:     c.New,
+		}
+		if !c.IsBit {
+			dto.Old = int(c.Old.(uint16))
+			dto.New = int(c.New.(uint16))
+		}
+		result[i] = dto
+	}
+	return result
+}
+
+// === スクリプト管理 ===
+
+// CreateScript は新しいスクリプトを作成する
+func (s *PLCService) CreateScript(name, code string, intervalMs int) (*ScriptDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.New().String()
+	sc := script.NewScript(id, name, code, time.Duration(intervalMs)*time.Millisecond)
+	s.scripts[id] = sc
+
+	go s.emitScriptsChanged()
+
+	return scriptToDTO(sc, false, "", 0), nil
+}
+
+// UpdateScript はスクリプトを更新する
+func (s *PLCService) UpdateScript(id, name, code string, intervalMs int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc, ok := s.scripts[id]
+	if !ok {
+		return fmt.Errorf("script not found: %s", id)
+	}
+
+	// ライブラリはコンパイルできることを確認してから置き換える
+	if sc.Library {
+		if err := s.scriptEngine.SetLibrary(name, code); err != nil {
+			return err
+		}
+		if name != sc.Name {
+			s.scriptEngine.RemoveLibrary(sc.Name)
+		}
+	}
+
+	// 実行中なら一旦停止（一時停止中のコンパイル済みプログラムも破棄する）
+	wasRunning := s.scriptEngine.IsRunning(id)
+	if s.scriptEngine.IsLoaded(id) {
+		s.scriptEngine.StopScript(id)
+	}
+
+	sc.Name = name
+	sc.Code = code
+	sc.Interval = time.Duration(intervalMs) * time.Millisecond
+
+	// 実行中だった場合は再開
+	if wasRunning {
+		s.scriptEngine.StartScript(sc)
 	}
 
 	go s.emitScriptsChanged()
@@ -872,12 +1843,17 @@ func (s *PLCService) DeleteScript(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.scripts[id]; !ok {
+	sc, ok := s.scripts[id]
+	if !ok {
 		return fmt.Errorf("script not found: %s", id)
 	}
 
 	s.scriptEngine.StopScript(id)
+	if sc.Library {
+		s.scriptEngine.RemoveLibrary(sc.Name)
+	}
 	delete(s.scripts, id)
+	s.removeTriggersForScript(id)
 	go s.emitScriptsChanged()
 	return nil
 }
@@ -892,14 +1868,16 @@ func (s *PLCService) GetScripts() []*ScriptDTO {
 		isRunning := s.scriptEngine.IsRunning(sc.ID)
 		var lastError string
 		var errorAtMs int64
-		if isRunning {
+		if s.scriptEngine.IsLoaded(sc.ID) {
 			errMsg, errAt := s.scriptEngine.GetLastError(sc.ID)
 			lastError = errMsg
 			if !errAt.IsZero() {
 				errorAtMs = errAt.UnixMilli()
 			}
 		}
-		result = append(result, scriptToDTO(sc, isRunning, lastError, errorAtMs))
+		dto := scriptToDTO(sc, isRunning, lastError, errorAtMs)
+		dto.IsPaused = s.scriptEngine.IsPaused(sc.ID)
+		result = append(result, dto)
 	}
 	return result
 }
@@ -914,21 +1892,109 @@ func (s *PLCService) GetScript(id string) (*ScriptDTO, error) {
 		return nil, fmt.Errorf("script not found: %s", id)
 	}
 
-	isRunning := s.scriptEngine.IsRunning(id)
-	var lastError string
-	var errorAtMs int64
-	if isRunning {
-		errMsg, errAt := s.scriptEngine.GetLastError(id)
-		lastError = errMsg
-		if !errAt.IsZero() {
-			errorAtMs = errAt.UnixMilli()
-		}
+	isRunning := s.scriptEngine.IsRunning(id)
+	var lastError string
+	var errorAtMs int64
+	if s.scriptEngine.IsLoaded(id) {
+		errMsg, errAt := s.scriptEngine.GetLastError(id)
+		lastError = errMsg
+		if !errAt.IsZero() {
+			errorAtMs = errAt.UnixMilli()
+		}
+	}
+	dto := scriptToDTO(sc, isRunning, lastError, errorAtMs)
+	dto.IsPaused = s.scriptEngine.IsPaused(id)
+	return dto, nil
+}
+
+// StartScript はスクリプトを開始する
+func (s *PLCService) StartScript(id string) error {
+	s.mu.RLock()
+	sc, ok := s.scripts[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("script not found: %s", id)
+	}
+	if sc.Library {
+		return fmt.Errorf("library script cannot be started: %s", sc.Name)
+	}
+
+	if err := s.scriptEngine.StartScript(sc); err != nil {
+		return err
+	}
+	s.setScriptEnabled(id, true)
+	go s.emitScriptsChanged()
+	return nil
+}
+
+// StopScript はスクリプトを停止する
+func (s *PLCService) StopScript(id string) error {
+	if err := s.scriptEngine.StopScript(id); err != nil {
+		return err
+	}
+	s.setScriptEnabled(id, false)
+	go s.emitScriptsChanged()
+	return nil
+}
+
+// setScriptEnabled はスクリプトの有効フラグ（エクスポート時に保存される開始状態）を更新する
+func (s *PLCService) setScriptEnabled(id string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sc, ok := s.scripts[id]; ok {
+		sc.Enabled = enabled
+	}
+}
+
+// SetScriptLibrary はスクリプトをライブラリにする（library=false で通常のスクリプトに戻す）。
+// ライブラリは停止され、他のスクリプトから require(スクリプト名) で読み込めるようになる。
+func (s *PLCService) SetScriptLibrary(id string, library bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc, ok := s.scripts[id]
+	if !ok {
+		return fmt.Errorf("script not found: %s", id)
+	}
+	if library {
+		if err := s.scriptEngine.SetLibrary(sc.Name, sc.Code); err != nil {
+			return err
+		}
+		if s.scriptEngine.IsLoaded(id) {
+			s.scriptEngine.StopScript(id)
+		}
+		sc.Enabled = false
+	} else if sc.Library {
+		s.scriptEngine.RemoveLibrary(sc.Name)
+	}
+	sc.Library = library
+
+	go s.emitScriptsChanged()
+	return nil
+}
+
+// PauseScript はスクリプトの周期実行を一時停止する（JSの状態は保持される）
+func (s *PLCService) PauseScript(id string) error {
+	if err := s.scriptEngine.PauseScript(id); err != nil {
+		return err
+	}
+	go s.emitScriptsChanged()
+	return nil
+}
+
+// ResumeScript は一時停止中のスクリプトを再開する
+func (s *PLCService) ResumeScript(id string) error {
+	if err := s.scriptEngine.ResumeScript(id); err != nil {
+		return err
 	}
-	return scriptToDTO(sc, isRunning, lastError, errorAtMs), nil
+	go s.emitScriptsChanged()
+	return nil
 }
 
-// StartScript はスクリプトを開始する
-func (s *PLCService) StartScript(id string) error {
+// StepScript はスクリプトを1周期分だけ実行する。
+// 未起動の場合は一時停止状態で読み込み、以降のステップ実行でJSの状態を引き継ぐ。
+func (s *PLCService) StepScript(id string) error {
 	s.mu.RLock()
 	sc, ok := s.scripts[id]
 	s.mu.RUnlock()
@@ -937,25 +2003,36 @@ func (s *PLCService) StartScript(id string) error {
 		return fmt.Errorf("script not found: %s", id)
 	}
 
-	if err := s.scriptEngine.StartScript(sc); err != nil {
+	if err := s.scriptEngine.LoadScript(sc); err != nil {
 		return err
 	}
+	err := s.scriptEngine.StepScript(id)
 	go s.emitScriptsChanged()
-	return nil
+	return err
 }
 
-// StopScript はスクリプトを停止する
-func (s *PLCService) StopScript(id string) error {
-	if err := s.scriptEngine.StopScript(id); err != nil {
-		return err
+// RunScriptOnce はスクリプトを1回だけ実行する
+func (s *PLCService) RunScriptOnce(code string) (interface{}, error) {
+	return s.scriptEngine.RunOnce(code)
+}
+
+// minScriptTimeout はスクリプトの最大実行時間として設定できる最小値
+const minScriptTimeout = 100 * time.Millisecond
+
+// SetScriptTimeout はスクリプト1回の実行の最大実行時間をミリ秒で設定する（0 で無制限）。
+// 超過した実行は中断され、スクリプトのエラーとして記録される。
+func (s *PLCService) SetScriptTimeout(ms int) error {
+	d := time.Duration(ms) * time.Millisecond
+	if ms < 0 || (ms > 0 && d < minScriptTimeout) {
+		return fmt.Errorf("script timeout must be 0 (unlimited) or at least %dms", minScriptTimeout.Milliseconds())
 	}
-	go s.emitScriptsChanged()
+	s.scriptEngine.SetMaxRuntime(d)
 	return nil
 }
 
-// RunScriptOnce はスクリプトを1回だけ実行する
-func (s *PLCService) RunScriptOnce(code string) (interface{}, error) {
-	return s.scriptEngine.RunOnce(code)
+// GetScriptTimeout はスクリプト1回の実行の最大実行時間をミリ秒で返す（0 は無制限）
+func (s *PLCService) GetScriptTimeout() int {
+	return int(s.scriptEngine.MaxRuntime().Milliseconds())
 }
 
 // ClearScriptError はスクリプトのエラー情報をクリアする
@@ -996,13 +2073,91 @@ func (s *PLCService) SetConsoleLogCallback(cb func(ConsoleLogDTO)) {
 	})
 }
 
+// === 診断ログ ===
+
+// maxLogEntries はメモリに保持する診断ログの最大件数
+const maxLogEntries = 1000
+
+// GetLogs は level 以上の診断ログを古い順に返す（limit > 0 の場合は新しいものから最大 limit 件）。
+// level は "debug" / "info" / "warn" / "error"（空文字は全件）。
+func (s *PLCService) GetLogs(level string, limit int) ([]LogEntryDTO, error) {
+	minLevel := slog.LevelDebug
+	if level != "" {
+		l, err := logging.ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		minLevel = l
+	}
+
+	entries := s.logSink.Entries(minLevel, limit)
+	result := make([]LogEntryDTO, len(entries))
+	for i, e := range entries {
+		result[i] = logEntryToDTO(e)
+	}
+	return result, nil
+}
+
+// ClearLogs は保持している診断ログを破棄する
+func (s *PLCService) ClearLogs() {
+	s.logSink.Clear()
+}
+
+// SetLogLevel は診断ログの出力レベルを設定する（標準エラー出力・メモリの両方に適用）
+func (s *PLCService) SetLogLevel(level string) error {
+	l, err := logging.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logging.SetLevel(l)
+	return nil
+}
+
+// GetLogLevel は現在の診断ログの出力レベルを返す
+func (s *PLCService) GetLogLevel() string {
+	return strings.ToLower(logging.Level().String())
+}
+
+// SetLogCallback は診断ログ追加時のコールバックを設定する（ライブ表示用）
+func (s *PLCService) SetLogCallback(cb func(LogEntryDTO)) {
+	s.logSink.SetOnEntry(func(e logging.Entry) {
+		cb(logEntryToDTO(e))
+	})
+}
+
+// Logger は診断ログ用のロガーを返す
+func (s *PLCService) Logger() *slog.Logger {
+	return s.logger
+}
+
+func logEntryToDTO(e logging.Entry) LogEntryDTO {
+	return LogEntryDTO{
+		At:      e.Time.UnixMilli(),
+		Level:   strings.ToLower(e.Level.String()),
+		Message: e.Message,
+		Attrs:   e.Attrs,
+	}
+}
+
 // Shutdown はサービスをシャットダウンする
 func (s *PLCService) Shutdown() {
+	// デモモード・オートセーブ・ドリフト動作・スクリプトは s.mu を取得するため、ロック前に停止を待つ。
+	// サーバーより先に止めることで、停止処理中のストアに書き込むゴルーチンを残さない。
+	s.StopDemoMode()
+	s.StopAutosave()
+	s.clearDriftBehaviors()
+	s.StopMonitoringCSVLog() //nolint:errcheck // 終了時の書き込みエラーは報告先がない
+	if s.scriptEngine != nil {
+		s.scriptEngine.StopAll()
+	}
+	s.flushMonitoringSave()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.scriptEngine != nil {
-		s.scriptEngine.StopAll()
+	// 停止処理中の接続数変更などのイベントは UI に送らない
+	if c, ok := s.eventEmitter.(interface{ Close() }); ok {
+		c.Close()
 	}
 	for _, inst := range s.servers {
 		if inst.cancelChange != nil {
@@ -1034,10 +2189,12 @@ func (s *PLCService) SetEventEmitter(emitter protocol.CommunicationEventEmitter)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// スクリプトの plc.stats() 用に通信統計も集計する
+	emitter = protocol.NewMultiEventEmitter(emitter, s.trafficStats)
 	s.eventEmitter = emitter
 
 	// セッションマネージャーを作成
-	s.sessionManager = protocol.NewSessionManager(5*time.Second, emitter)
+	s.sessionManager = protocol.NewSessionManager(s.sessionTimeout(), emitter)
 	s.sessionManager.Start()
 
 	// 全サーバーにエミッターを設定
@@ -1116,6 +2273,61 @@ func (s *PLCService) GetSessionManager() *protocol.SessionManager {
 	return s.sessionManager
 }
 
+// GetUnitActivity は UnitID ごとの最終ポーリング時刻を返す。
+// セッションタイムアウトで無通信と判定されたユニットは含まない。
+// ホストのセッションマネージャーの値に、自身で追跡するサーバー（プラグイン）の値を加える（同じ UnitID は新しい方）。
+func (s *PLCService) GetUnitActivity() map[int]time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int]time.Time)
+	merge := func(activity map[uint8]time.Time) {
+		for unitID, at := range activity {
+			if prev, ok := result[int(unitID)]; !ok || at.After(prev) {
+				result[int(unitID)] = at
+			}
+		}
+	}
+	if s.sessionManager != nil {
+		merge(s.sessionManager.Snapshot())
+	}
+	for _, inst := range s.servers {
+		reporter, ok := inst.server.(protocol.UnitActivityReporter)
+		if !ok {
+			continue
+		}
+		activity, err := reporter.UnitActivity()
+		if err != nil {
+			s.logger.Warn("failed to get unit activity", "protocol", inst.protocolType, "error", err)
+			continue
+		}
+		merge(activity)
+	}
+	return result
+}
+
+// TrafficStats は通信統計を返す。
+// SetEventEmitter 以降にホストのエミッターで集計した値に、自身で集計するサーバー（プラグイン）の値を加える。
+func (s *PLCService) TrafficStats() protocol.TrafficSnapshot {
+	snap := s.trafficStats.Snapshot()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, inst := range s.servers {
+		reporter, ok := inst.server.(protocol.TrafficReporter)
+		if !ok {
+			continue
+		}
+		stats, err := reporter.TrafficStats()
+		if err != nil {
+			s.logger.Warn("failed to get traffic stats", "protocol", inst.protocolType, "error", err)
+			continue
+		}
+		snap = snap.Add(stats)
+	}
+	return snap
+}
+
 // setEmitterToServerInstance はサーバーインスタンスにイベントエミッターを設定する（ロック済み前提）
 func (s *PLCService) setEmitterToServerInstance(inst *serverInstance) {
 	type eventAware interface {
@@ -1152,6 +2364,8 @@ func scriptToDTO(sc *script.Script, isRunning bool, lastError string, errorAtMs
 		Name:       sc.Name,
 		Code:       sc.Code,
 		IntervalMs: int(sc.Interval.Milliseconds()),
+		Enabled:    sc.Enabled,
+		IsLibrary:  sc.Library,
 		IsRunning:  isRunning,
 		LastError:  lastError,
 		ErrorAt:    errorAtMs,
@@ -1162,11 +2376,15 @@ func scriptToDTO(sc *script.Script, isRunning bool, lastError string, errorAtMs
 
 // ExportProject はプロジェクト全体のデータをエクスポートする
 func (s *PLCService) ExportProject() *ProjectDataDTO {
+	// 実行中のスクリプトが終わるのを待ち、設定・メモリ・スクリプト・モニタリングを同じ時点で取得する
+	s.exportMu.Lock()
+	defer s.exportMu.Unlock()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	// 全サーバーのスナップショットを取得
 	servers := make([]ServerSnapshotDTO, 0, len(s.servers))
+	memory := make([]map[string]interface{}, 0, len(s.servers))
 	for _, inst := range s.servers {
 		var settings map[string]interface{}
 		if inst.config != nil {
@@ -1198,7 +2416,10 @@ func (s *PLCService) ExportProject() *ProjectDataDTO {
 			Variant:        inst.variant,
 			Settings:       settings,
 			UnitIDSettings: unitIDSettings,
+			LockedAreas:    sortedLockedAreas(inst),
+			ExcludedAreas:  sortedExcludedAreas(inst),
 		})
+		memory = append(memory, persistedSnapshot(inst))
 	}
 
 	// スクリプトを取得
@@ -1209,6 +2430,8 @@ func (s *PLCService) ExportProject() *ProjectDataDTO {
 			Name:       sc.Name,
 			Code:       sc.Code,
 			IntervalMs: int(sc.Interval.Milliseconds()),
+			Enabled:    sc.Enabled,
+			IsLibrary:  sc.Library,
 			IsRunning:  false,
 		})
 	}
@@ -1270,24 +2493,68 @@ func (s *PLCService) ExportProject() *ProjectDataDTO {
 		})
 	}
 
-	return &ProjectDataDTO{
+	project := &ProjectDataDTO{
 		Servers:         servers,
 		Scripts:         scripts,
 		MonitoringItems: monitoringItems,
 		StructTypes:     structTypeDTOs,
 		Variables:       variableDTOs,
+		Recipes:         s.sortedRecipes(),
+		DriftBehaviors:  s.GetDriftBehaviors(),
+	}
+	if s.connectionTimeout != 0 {
+		project.ConnectionTimeoutMs = int(s.connectionTimeout.Milliseconds())
+	}
+	project.SharedMemory = s.IsSharedMemory()
+	attachServerMemory(project, memory)
+	if sum, err := ComputeProjectChecksum(project); err == nil {
+		project.Checksum = sum
+	} else {
+		s.logger.Warn("failed to compute project checksum", "error", err)
 	}
+	return project
 }
 
-// ImportProject はプロジェクト全体のデータをインポートする
+// ImportProject はプロジェクト全体のデータをインポートする。
+// Checksum が設定されている場合は内容と一致することを検証する。
 func (s *PLCService) ImportProject(data *ProjectDataDTO) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return s.ImportProjectWithOptions(data, ImportOptions{})
+}
+
+// ImportProjectWithOptions はオプションを指定してプロジェクトをインポートする
+func (s *PLCService) ImportProjectWithOptions(data *ProjectDataDTO, opts ImportOptions) error {
+	if !opts.SkipChecksum {
+		if err := VerifyProjectChecksum(data); err != nil {
+			return err
+		}
+	}
+	return s.importProject(data, opts)
+}
+
+// ImportProjectJSON はプロジェクトファイルの JSON をインポートする。
+// チェックサムはファイルに書かれている内容のまま検証する（VerifyProjectChecksumJSON）。
+func (s *PLCService) ImportProjectJSON(raw []byte, opts ImportOptions) error {
+	if !opts.SkipChecksum {
+		if err := VerifyProjectChecksumJSON(raw); err != nil {
+			return err
+		}
+	}
+	var data ProjectDataDTO
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	return s.importProject(&data, opts)
+}
 
-	// 実行中のスクリプトを全て停止
+func (s *PLCService) importProject(data *ProjectDataDTO, opts ImportOptions) error {
+	// 実行中のスクリプトとドリフト動作を全て停止（どちらも s.mu を取得するため、ロック前に終了を待つ）
 	if s.scriptEngine != nil {
 		s.scriptEngine.StopAll()
 	}
+	s.clearDriftBehaviors()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	// 全サーバーを停止・削除
 	for _, inst := range s.servers {
@@ -1303,8 +2570,27 @@ func (s *PLCService) ImportProject(data *ProjectDataDTO) error {
 		if inst.server != nil {
 			inst.server.Stop()
 		}
+		s.notifyServerStatusLocked(inst)
 	}
 	s.servers = make(map[protocol.ProtocolType]*serverInstance)
+	s.refreshSharedStoresLocked()
+
+	// 共有メモリの設定を復元（サーバーの追加前に設定し、追加したサーバー同士を同期する）
+	s.shareMu.Lock()
+	s.sharedMemory = data.SharedMemory
+	s.shareMu.Unlock()
+
+	// セッションのタイムアウトを復元（未設定の場合は既定値に戻す）
+	if data.ConnectionTimeoutMs > 0 {
+		if err := s.setConnectionTimeoutLocked(data.ConnectionTimeoutMs); err != nil {
+			return err
+		}
+	} else {
+		s.connectionTimeout = 0
+		if s.sessionManager != nil {
+			s.sessionManager.SetTimeout(defaultConnectionTimeout)
+		}
+	}
 
 	// 変数ストアをクリア（構造体型・変数・マッピング・公開設定をリセット）
 	s.variableStore.ClearAll()
@@ -1375,6 +2661,13 @@ func (s *PLCService) ImportProject(data *ProjectDataDTO) error {
 			}
 		}
 
+		// connectionTimeout を含まない古いプロジェクトではプロジェクトのセッションのタイムアウトを反映する
+		if _, ok := snap.Settings["connectionTimeout"]; !ok && s.connectionTimeout != 0 {
+			if err := s.applyConnectionTimeoutLocked(inst); err != nil {
+				s.logger.Warn("connection timeout not applied", "protocol", snap.ProtocolType, "error", err)
+			}
+		}
+
 		// UnitID設定を復元
 		if snap.UnitIDSettings != nil {
 			type unitIDSupporter interface {
@@ -1388,10 +2681,43 @@ func (s *PLCService) ImportProject(data *ProjectDataDTO) error {
 				us.SetDisabledUnitIDs(uint8Ids)
 			}
 		}
+
+		// エリアのロックを復元（存在しないエリアは無視する）
+		for _, area := range snap.LockedAreas {
+			if _, err := findMemoryArea(inst.dataStore, area); err != nil {
+				continue
+			}
+			if inst.lockedAreas == nil {
+				inst.lockedAreas = make(map[string]bool)
+			}
+			inst.lockedAreas[area] = true
+		}
+
+		// メモリ内容を保存しないエリアを復元（存在しないエリアは無視する）
+		for _, area := range snap.ExcludedAreas {
+			if _, err := findMemoryArea(inst.dataStore, area); err != nil {
+				continue
+			}
+			if inst.excludedAreas == nil {
+				inst.excludedAreas = make(map[string]bool)
+			}
+			inst.excludedAreas[area] = true
+		}
+
+		// メモリ内容を復元
+		if err := restoreServerMemory(inst, snap, data.MemoryFormat); err != nil {
+			return err
+		}
 	}
 
-	// スクリプトを設定
+	// スクリプトを設定（旧スクリプトを参照するトリガーは破棄する）
 	if data.Scripts != nil {
+		s.clearTriggers()
+		for _, sc := range s.scripts {
+			if sc.Library {
+				s.scriptEngine.RemoveLibrary(sc.Name)
+			}
+		}
 		s.scripts = make(map[string]*script.Script)
 		for _, dto := range data.Scripts {
 			sc := script.NewScript(
@@ -1400,7 +2726,27 @@ func (s *PLCService) ImportProject(data *ProjectDataDTO) error {
 				dto.Code,
 				time.Duration(dto.IntervalMs)*time.Millisecond,
 			)
+			sc.Enabled = dto.Enabled
 			s.scripts[dto.ID] = sc
+			if dto.IsLibrary {
+				if err := s.scriptEngine.SetLibrary(sc.Name, sc.Code); err != nil {
+					s.logger.Warn("failed to load library script", "script", sc.Name, "error", err)
+					continue
+				}
+				sc.Library = true
+				sc.Enabled = false
+			}
+		}
+
+		// 有効なスクリプトを自動開始（ライブラリを先に登録しておく）
+		for _, sc := range s.scripts {
+			if !sc.Enabled || opts.SkipScripts {
+				continue
+			}
+			if err := s.scriptEngine.StartScript(sc); err != nil {
+				s.logger.Warn("failed to auto-start script", "script", sc.Name, "error", err)
+				sc.Enabled = false
+			}
 		}
 	}
 
@@ -1412,6 +2758,21 @@ func (s *PLCService) ImportProject(data *ProjectDataDTO) error {
 		}
 	}
 
+	// レシピを設定（プロジェクトのレシピは recipes.json に書き込まない）
+	if data.Recipes != nil {
+		s.setRecipes(data.Recipes)
+		s.recipesInProject = true
+	}
+
+	// ドリフト動作を再開（対象のサーバーやエリアがないものは読み飛ばす）
+	for _, dto := range data.DriftBehaviors {
+		if err := s.checkDriftBehavior(dto); err != nil {
+			s.logger.Warn("drift behavior ignored", "id", dto.ID, "error", err)
+			continue
+		}
+		s.startDriftBehavior(dto)
+	}
+
 	go s.emitServerChanged()
 	go s.emitVariablesChanged()
 	go s.emitScriptsChanged()
@@ -1439,6 +2800,109 @@ func (s *PLCService) GetMonitoringItems() []*MonitoringItemDTO {
 	return result
 }
 
+// GetMonitoringItemsByGroup はモニタリング項目をグループ名ごとにまとめて返す。
+// グループ内は Order 順で、グループなしの項目は空文字列のキーにまとめる。
+func (s *PLCService) GetMonitoringItemsByGroup() map[string][]*MonitoringItemDTO {
+	result := make(map[string][]*MonitoringItemDTO)
+	for _, item := range s.GetMonitoringItems() {
+		result[item.Group] = append(result[item.Group], item)
+	}
+	return result
+}
+
+// ReadMonitoringValues は全モニタリング項目の現在値をOrder順で読み取る。
+// 読み取った値でアラームを判定し、状態が変わった項目のイベントを発行する。
+func (s *PLCService) ReadMonitoringValues() []MonitoringValueDTO {
+	items := s.GetMonitoringItems()
+	result := s.readMonitoringValues(items)
+	s.evaluateMonitoringAlarms(items, result)
+	return result
+}
+
+// readMonitoringValues は items の現在値を読み取る
+func (s *PLCService) readMonitoringValues(items []*MonitoringItemDTO) []MonitoringValueDTO {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	areaCache := make(map[string][]protocol.MemoryArea)
+	result := make([]MonitoringValueDTO, 0, len(items))
+	for _, item := range items {
+		val := MonitoringValueDTO{
+			ID:           item.ID,
+			ProtocolType: item.ProtocolType,
+			MemoryArea:   item.MemoryArea,
+			Address:      item.Address,
+		}
+
+		inst, err := s.getServerInstance(item.ProtocolType)
+		if err != nil {
+			val.Error = err.Error()
+			result = append(result, val)
+			continue
+		}
+		areas, ok := areaCache[item.ProtocolType]
+		if !ok {
+			areas = inst.dataStore.GetAreas()
+			areaCache[item.ProtocolType] = areas
+		}
+		for _, a := range areas {
+			if a.ID == item.MemoryArea {
+				val.IsBit = a.IsBit
+				break
+			}
+		}
+
+		if val.IsBit {
+			bit, err := inst.dataStore.ReadBit(item.MemoryArea, uint32(item.Address))
+			if err != nil {
+				val.Error = err.Error()
+			} else {
+				val.Value = bit
+			}
+			result = append(result, val)
+			continue
+		}
+
+		wordCount := item.BitWidth / 16
+		if wordCount < 1 {
+			wordCount = 1
+		}
+		words, err := inst.dataStore.ReadWords(item.MemoryArea, uint32(item.Address), uint16(wordCount))
+		if err != nil {
+			val.Error = err.Error()
+			result = append(result, val)
+			continue
+		}
+		val.Words = make([]int, len(words))
+		for i, w := range words {
+			val.Words[i] = int(w)
+		}
+		raw := combineWords(words, item.Endianness)
+		val.Value = raw
+		val.Unit = item.Unit
+		if monitoringScaled(item) {
+			eng := toEngineeringValue(item, float64(raw))
+			val.EngineeringValue = &eng
+		}
+		result = append(result, val)
+	}
+	return result
+}
+
+// combineWords はワード列を1つの符号なし整数に結合する
+// big: 先頭ワードが上位、little: 先頭ワードが下位
+func combineWords(words []uint16, endianness string) uint64 {
+	var v uint64
+	for i := range words {
+		w := words[i]
+		if endianness == "little" {
+			w = words[len(words)-1-i]
+		}
+		v = v<<16 | uint64(w)
+	}
+	return v
+}
+
 // getNextOrder は次のOrder値を返す（ロック済み前提）
 func (s *PLCService) getNextOrder() int {
 	maxOrder := 0
@@ -1452,9 +2916,11 @@ func (s *PLCService) getNextOrder() int {
 
 // AddMonitoringItem はモニタリング項目を追加する
 func (s *PLCService) AddMonitoringItem(item *MonitoringItemDTO) (*MonitoringItemDTO, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return s.AddMonitoringItemWithOptions(item, AddMonitoringOptions{})
+}
 
+// addMonitoringItemLocked は ID と Order を設定して項目を登録する。呼び出し元で s.mu をロックしていること。
+func (s *PLCService) addMonitoringItemLocked(item *MonitoringItemDTO) *MonitoringItemDTO {
 	// IDを生成
 	item.ID = uuid.New().String()
 	// Orderを設定
@@ -1462,8 +2928,40 @@ func (s *PLCService) AddMonitoringItem(item *MonitoringItemDTO) (*MonitoringItem
 	s.monitoringItems[item.ID] = item
 
 	// 自動保存
-	go s.saveMonitoringConfigInternal()
+	s.scheduleMonitoringSaveLocked()
+
+	return item
+}
+
+// SuggestMonitoringDefaults はモニタリング項目追加時の初期値をエリアの種類から提案する。
+// ビットエリアは boolean 表示、ワードエリアは 16bit・ビッグエンディアンの hex 表示とする。
+// 返す項目は登録しない（ID と Order は空のまま）。
+func (s *PLCService) SuggestMonitoringDefaults(protocolType, area string, address int) (*MonitoringItemDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	areaInfo, err := findMemoryArea(inst.dataStore, area)
+	if err != nil {
+		return nil, err
+	}
 
+	item := &MonitoringItemDTO{
+		ProtocolType: protocolType,
+		MemoryArea:   area,
+		Address:      address,
+	}
+	if areaInfo.IsBit {
+		item.BitWidth = 1
+		item.DisplayFormat = "boolean"
+	} else {
+		item.BitWidth = 16
+		item.Endianness = "big"
+		item.DisplayFormat = "hex"
+	}
 	return item, nil
 }
 
@@ -1520,7 +3018,7 @@ func (s *PLCService) MoveMonitoringItem(id string, direction string) error {
 	target.Order, items[swapIndex].Order = items[swapIndex].Order, target.Order
 
 	// 自動保存
-	go s.saveMonitoringConfigInternal()
+	s.scheduleMonitoringSaveLocked()
 
 	return nil
 }
@@ -1583,7 +3081,7 @@ func (s *PLCService) ReorderMonitoringItem(id string, newIndex int) error {
 	}
 
 	// 自動保存
-	go s.saveMonitoringConfigInternal()
+	s.scheduleMonitoringSaveLocked()
 
 	return nil
 }
@@ -1600,7 +3098,7 @@ func (s *PLCService) UpdateMonitoringItem(item *MonitoringItemDTO) error {
 	s.monitoringItems[item.ID] = item
 
 	// 自動保存
-	go s.saveMonitoringConfigInternal()
+	s.scheduleMonitoringSaveLocked()
 
 	return nil
 }
@@ -1617,7 +3115,7 @@ func (s *PLCService) DeleteMonitoringItem(id string) error {
 	delete(s.monitoringItems, id)
 
 	// 自動保存
-	go s.saveMonitoringConfigInternal()
+	s.scheduleMonitoringSaveLocked()
 
 	return nil
 }
@@ -1630,59 +3128,84 @@ func (s *PLCService) ClearMonitoringItems() {
 	s.monitoringItems = make(map[string]*MonitoringItemDTO)
 
 	// 自動保存
-	go s.saveMonitoringConfigInternal()
+	s.scheduleMonitoringSaveLocked()
 }
 
-// getMonitoringConfigPath はモニタリング設定ファイルのパスを返す
-func getMonitoringConfigPath() (string, error) {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		return "", err
+// SetMonitoringItems はモニタリング項目一覧を items で置き換える。
+// Order は items の並び順で振り直し、ID が空の項目には新しい ID を割り当てる。
+// 存在しないサーバー・エリアや重複した ID を含む場合は何も変更せずにエラーを返す。
+func (s *PLCService) SetMonitoringItems(items []*MonitoringItemDTO) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		if item == nil {
+			return fmt.Errorf("item %d is nil", i)
+		}
+		inst, err := s.getServerInstance(item.ProtocolType)
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		if _, err := findMemoryArea(inst.dataStore, item.MemoryArea); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		if item.ID != "" {
+			if seen[item.ID] {
+				return fmt.Errorf("item %d: duplicate ID %s", i, item.ID)
+			}
+			seen[item.ID] = true
+		}
 	}
 
-	dir := filepath.Join(configDir, "PLCSimulator")
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return "", err
+	monitoringItems := make(map[string]*MonitoringItemDTO, len(items))
+	for i, item := range items {
+		if item.ID == "" {
+			item.ID = uuid.New().String()
+		}
+		item.Order = i + 1
+		monitoringItems[item.ID] = item
 	}
+	s.monitoringItems = monitoringItems
 
-	return filepath.Join(dir, "monitoring_config.json"), nil
-}
+	// 自動保存
+	s.scheduleMonitoringSaveLocked()
 
-// SaveMonitoringConfig はモニタリング設定をファイルに保存する
-func (s *PLCService) SaveMonitoringConfig() error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.saveMonitoringConfigInternal()
+	return nil
 }
 
-// saveMonitoringConfigInternal は内部保存処理（ロック不要）
-func (s *PLCService) saveMonitoringConfigInternal() error {
-	configPath, err := getMonitoringConfigPath()
+// defaultConfigDir はユーザー設定ディレクトリ内の保存先を返す（取得できない場合は空）
+func defaultConfigDir() string {
+	configDir, err := os.UserConfigDir()
 	if err != nil {
-		return err
-	}
-
-	items := make([]*MonitoringItemDTO, 0, len(s.monitoringItems))
-	for _, item := range s.monitoringItems {
-		items = append(items, item)
+		return ""
 	}
+	return filepath.Join(configDir, "PLCSimulator")
+}
 
-	config := &MonitoringConfigDTO{
-		Version: 1,
-		Items:   items,
+// configFilePath は設定ディレクトリ内の name のパスを返す（ディレクトリがなければ作成する）
+func (s *PLCService) configFilePath(name string) (string, error) {
+	if s.configDir == "" {
+		return "", fmt.Errorf("config directory is not available")
 	}
-
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return err
+	if err := os.MkdirAll(s.configDir, 0755); err != nil {
+		return "", err
 	}
+	return filepath.Join(s.configDir, name), nil
+}
 
-	return os.WriteFile(configPath, data, 0644)
+// SaveMonitoringConfig はモニタリング設定をファイルに保存する
+func (s *PLCService) SaveMonitoringConfig() error {
+	s.mu.RLock()
+	seq := s.monitorSave.seq.Add(1)
+	items := s.monitoringSnapshotLocked()
+	s.mu.RUnlock()
+	return s.writeMonitoringSnapshot(seq, items)
 }
 
 // LoadMonitoringConfig はモニタリング設定をファイルから読み込む
 func (s *PLCService) LoadMonitoringConfig() error {
-	configPath, err := getMonitoringConfigPath()
+	configPath, err := s.configFilePath("monitoring_config.json")
 	if err != nil {
 		return err
 	}
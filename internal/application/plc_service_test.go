@@ -1,18 +1,48 @@
 package application
 
 import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
+
+	"modbus_simulator/internal/domain/datastore"
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/infrastructure/scripting"
 )
 
 // newTestService はテスト用のクリーンな PLCService を作成する。
 // フェイクファクトリーをインプロセスで登録し、デフォルトで modbus-tcp を追加する。
 func newTestService(t *testing.T) *PLCService {
 	t.Helper()
-	svc := NewPLCService()
+	return newTestServiceWithConfigDir(t, t.TempDir())
+}
+
+// newTestServiceWithConfigDir は設定ファイル（モニタリング設定・レシピ）を configDir に保存するテスト用サービスを作成する
+func newTestServiceWithConfigDir(t *testing.T, configDir string) *PLCService {
+	t.Helper()
+	return newTestServiceWithTCPFactory(t, configDir, newFakeModbusFactory("modbus-tcp", "tcp", "Modbus TCP"))
+}
+
+// newTestServiceWithServer は modbus-tcp のフェイクサーバーを wrap で包んだテスト用サービスを作成する。
+// テストで必要な機能だけを持つ小さなフェイクを使うためのもの。
+func newTestServiceWithServer(t *testing.T, wrap func(*fakeServer) protocol.ProtocolServer) *PLCService {
+	t.Helper()
+	return newTestServiceWithTCPFactory(t, t.TempDir(), &serverWrappingFactory{
+		fakeServerFactory: newFakeModbusFactory("modbus-tcp", "tcp", "Modbus TCP"),
+		wrap:              wrap,
+	})
+}
+
+// newTestServiceWithTCPFactory は modbus-tcp を tcpFactory で作るテスト用サービスを作成する
+func newTestServiceWithTCPFactory(t *testing.T, configDir string, tcpFactory protocol.ServerFactory) *PLCService {
+	t.Helper()
+	svc := NewPLCServiceWithConfigDir(configDir)
 
 	// Modbus 互換フェイクファクトリーを登録（プロトコル固有実装に依存しない）
-	svc.RegisterPluginFactory(newFakeModbusFactory("modbus-tcp", "tcp", "Modbus TCP"))
+	svc.RegisterPluginFactory(tcpFactory)
 	svc.RegisterPluginFactory(newFakeModbusFactory("modbus-rtu", "rtu", "Modbus RTU"))
 	svc.RegisterPluginFactory(newFakeModbusFactory("modbus-ascii", "ascii", "Modbus ASCII"))
 
@@ -26,6 +56,8 @@ func newTestService(t *testing.T) *PLCService {
 		for _, inst := range svc.GetServerInstances() {
 			_ = svc.StopServer(inst.ProtocolType)
 		}
+		// 保存待ちのモニタリング設定を書き出し、後続のテスト中に書き込まれないようにする
+		svc.flushMonitoringSave()
 	})
 	return svc
 }
@@ -203,6 +235,104 @@ func TestPLCService_GetProtocolSchema_Unknown(t *testing.T) {
 	}
 }
 
+func TestPLCService_GetConfigJSONSchema_ModbusTCP(t *testing.T) {
+	svc := newTestService(t)
+
+	raw, err := svc.GetConfigJSONSchema("modbus-tcp")
+	if err != nil {
+		t.Fatalf("GetConfigJSONSchema failed: %v", err)
+	}
+
+	var schema struct {
+		Schema     string                            `json:"$schema"`
+		Type       string                            `json:"type"`
+		Required   []string                          `json:"required"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if schema.Schema == "" || schema.Type != "object" {
+		t.Errorf("unexpected schema header: $schema=%q type=%q", schema.Schema, schema.Type)
+	}
+
+	port, ok := schema.Properties["tcpPort"]
+	if !ok {
+		t.Fatal("expected tcpPort property")
+	}
+	if port["type"] != "integer" || port["minimum"] != float64(1) || port["maximum"] != float64(65535) {
+		t.Errorf("unexpected tcpPort schema: %v", port)
+	}
+	if port["default"] != float64(502) {
+		t.Errorf("expected default 502, got %v", port["default"])
+	}
+	if addr := schema.Properties["tcpAddress"]; addr["type"] != "string" {
+		t.Errorf("unexpected tcpAddress schema: %v", addr)
+	}
+
+	required := make(map[string]bool)
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+	if !required["tcpAddress"] || !required["tcpPort"] {
+		t.Errorf("expected tcpAddress and tcpPort to be required, got %v", schema.Required)
+	}
+}
+
+func TestPLCService_GetConfigJSONSchema_SelectOptions(t *testing.T) {
+	svc := newTestService(t)
+
+	raw, err := svc.GetConfigJSONSchema("modbus-rtu")
+	if err != nil {
+		t.Fatalf("GetConfigJSONSchema failed: %v", err)
+	}
+	var schema struct {
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	// 数値デフォルトの select は integer の enum になる
+	baud := schema.Properties["baudRate"]
+	if baud["type"] != "integer" {
+		t.Errorf("expected integer baudRate, got %v", baud)
+	}
+	if enum, _ := baud["enum"].([]interface{}); len(enum) != 2 || enum[0] != float64(9600) {
+		t.Errorf("unexpected baudRate enum: %v", baud["enum"])
+	}
+	parity := schema.Properties["parity"]
+	if parity["type"] != "string" {
+		t.Errorf("expected string parity, got %v", parity)
+	}
+}
+
+func TestBuildConfigJSONSchema_Condition(t *testing.T) {
+	fields := []protocol.ConfigField{
+		{Name: "mode", Type: "select", Required: true, Default: "a", Options: []protocol.FieldOption{{Value: "a"}, {Value: "b"}}},
+		{Name: "extra", Type: "text", Required: true, Condition: &protocol.FieldCondition{Field: "mode", Value: "b"}},
+	}
+
+	schema := buildConfigJSONSchema("test", fields)
+
+	required := schema["required"].([]string)
+	if len(required) != 1 || required[0] != "mode" {
+		t.Errorf("expected only 'mode' to be unconditionally required, got %v", required)
+	}
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok || len(allOf) != 1 {
+		t.Fatalf("expected 1 conditional, got %v", schema["allOf"])
+	}
+}
+
+func TestPLCService_GetConfigJSONSchema_Unknown(t *testing.T) {
+	svc := newTestService(t)
+
+	if _, err := svc.GetConfigJSONSchema("unknown_protocol"); err == nil {
+		t.Fatal("expected error for unknown protocol")
+	}
+}
+
 // ===== メモリ操作テスト =====
 
 func TestPLCService_GetMemoryAreas_Modbus(t *testing.T) {
@@ -234,6 +364,176 @@ func TestPLCService_GetMemoryAreas_NotFound(t *testing.T) {
 	}
 }
 
+func TestPLCService_ResizeArea(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ResizeArea("modbus-tcp", "holdingRegisters", 20000); err == nil {
+		t.Error("expected error while server is running")
+	}
+	if err := svc.StopServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.ResizeArea("modbus-tcp", "holdingRegisters", 20000); err != nil {
+		t.Fatalf("ResizeArea: %v", err)
+	}
+	for _, a := range svc.GetMemoryAreas("modbus-tcp") {
+		if a.ID == "holdingRegisters" && a.Size != 20000 {
+			t.Errorf("holdingRegisters size = %d, want 20000", a.Size)
+		}
+	}
+	// 点数はサーバー設定としてプロジェクトに保存される
+	if got := svc.GetServerConfig("modbus-tcp").Settings["areaSizes"]; got != "holdingRegisters=20000" {
+		t.Errorf("areaSizes setting = %v, want holdingRegisters=20000", got)
+	}
+
+	if err := svc.ResizeArea("modbus-tcp", "unknown", 10); err == nil {
+		t.Error("expected error for unknown area")
+	}
+}
+
+// TestPLCService_ConcurrentVariantChange はバリアント（プロトコル）変更と起動・停止を
+// 複数 goroutine から同時に呼び出しても、サーバーインスタンスが一貫した状態になることを確認する。
+// 変更処理は s.mu を保持したまま新しいサーバーの作成と差し替えを行うため直列化される。
+func TestPLCService_ConcurrentVariantChange(t *testing.T) {
+	svc := newTestService(t)
+	variants := []string{"tcp", "udp", "tls"}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			switch i % 3 {
+			case 0:
+				_ = svc.StartServer("modbus-tcp")
+			case 1:
+				_ = svc.StopServer("modbus-tcp")
+			}
+			err := svc.UpdateServerConfig(&ServerConfigDTO{
+				ProtocolType: "modbus-tcp",
+				Variant:      variants[i%len(variants)],
+				Settings:     map[string]interface{}{},
+			})
+			// 実行中の場合は明確なエラーで拒否される
+			if err != nil && err.Error() != "cannot update config while server is running" {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("UpdateServerConfig: %v", err)
+	}
+
+	instances := svc.GetServerInstances()
+	if len(instances) != 1 || instances[0].ProtocolType != "modbus-tcp" {
+		t.Fatalf("instances = %+v, want single modbus-tcp", instances)
+	}
+
+	svc.mu.RLock()
+	inst := svc.servers["modbus-tcp"]
+	variant := inst.variant
+	serverVariant := inst.server.Config().Variant()
+	configVariant := inst.config.Variant()
+	svc.mu.RUnlock()
+	if variant != serverVariant || variant != configVariant {
+		t.Errorf("inconsistent variant: inst=%q server=%q config=%q", variant, serverVariant, configVariant)
+	}
+	if got := svc.GetServerConfig("modbus-tcp").Variant; got != variant {
+		t.Errorf("GetServerConfig variant = %q, want %q", got, variant)
+	}
+}
+
+func TestPLCService_GetMemoryAreas_ReadOnly(t *testing.T) {
+	svc := newTestService(t)
+
+	readOnly := func(id string) bool {
+		for _, a := range svc.GetMemoryAreas("modbus-tcp") {
+			if a.ID == id {
+				return a.ReadOnly
+			}
+		}
+		t.Fatalf("area %s not found", id)
+		return false
+	}
+
+	if readOnly("holdingRegisters") {
+		t.Fatal("holdingRegisters should be writable by default")
+	}
+
+	cfg := svc.GetServerConfig("modbus-tcp")
+	cfg.Settings["readOnlyAreas"] = "holdingRegisters"
+	if err := svc.UpdateServerConfig(cfg); err != nil {
+		t.Fatalf("UpdateServerConfig: %v", err)
+	}
+	if !readOnly("holdingRegisters") {
+		t.Error("holdingRegisters should be reported as read-only")
+	}
+	if readOnly("coils") {
+		t.Error("coils should remain writable")
+	}
+}
+
+func TestPLCService_SetOutOfRangePolicy(t *testing.T) {
+	svc := newTestService(t)
+
+	if got := svc.GetOutOfRangePolicy("modbus-tcp"); got != "error" {
+		t.Errorf("default policy = %q, want error", got)
+	}
+	for _, p := range []string{"zero", "wrap", "error"} {
+		if err := svc.SetOutOfRangePolicy("modbus-tcp", p); err != nil {
+			t.Fatalf("SetOutOfRangePolicy(%q): %v", p, err)
+		}
+		if got := svc.GetOutOfRangePolicy("modbus-tcp"); got != p {
+			t.Errorf("policy = %q, want %q", got, p)
+		}
+		// サーバー設定としてプロジェクトに保存される
+		if got := svc.GetServerConfig("modbus-tcp").Settings["outOfRangePolicy"]; got != p {
+			t.Errorf("outOfRangePolicy setting = %v, want %q", got, p)
+		}
+	}
+	if err := svc.SetOutOfRangePolicy("modbus-tcp", "bogus"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+	if err := svc.SetOutOfRangePolicy("unknown-protocol", "zero"); err == nil {
+		t.Error("expected error for unknown server")
+	}
+}
+
+func TestPLCService_UnitIDRangeValidation(t *testing.T) {
+	svc := newTestService(t)
+
+	// Modbus の有効範囲は 1〜247
+	for _, id := range []int{0, 248, 300} {
+		if err := svc.SetUnitIDEnabled("modbus-tcp", id, false); err == nil {
+			t.Errorf("SetUnitIDEnabled(%d): expected error", id)
+		}
+		if err := svc.SetDisabledUnitIDs("modbus-tcp", []int{5, id}); err == nil {
+			t.Errorf("SetDisabledUnitIDs([5 %d]): expected error", id)
+		}
+	}
+	// 範囲外を含む場合は何も変更しない
+	if got := svc.GetDisabledUnitIDs("modbus-tcp"); len(got) != 0 {
+		t.Errorf("disabled IDs after rejected calls = %v, want none", got)
+	}
+
+	if err := svc.SetUnitIDEnabled("modbus-tcp", 247, false); err != nil {
+		t.Errorf("SetUnitIDEnabled(247): %v", err)
+	}
+	if err := svc.SetDisabledUnitIDs("modbus-tcp", []int{1, 247}); err != nil {
+		t.Errorf("SetDisabledUnitIDs([1 247]): %v", err)
+	}
+	if got := svc.GetDisabledUnitIDs("modbus-tcp"); len(got) != 2 || got[0] != 1 || got[1] != 247 {
+		t.Errorf("disabled IDs = %v, want [1 247]", got)
+	}
+}
+
 func TestPLCService_ReadWriteWord_Modbus(t *testing.T) {
 	svc := newTestService(t)
 
@@ -281,6 +581,77 @@ func TestPLCService_ReadWords_Multiple(t *testing.T) {
 	}
 }
 
+func TestPLCService_ReadAllAreas_MatchesIndividualReads(t *testing.T) {
+	svc := newTestService(t)
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 5000, 1234)
+	_ = svc.WriteBit("modbus-tcp", "coils", 9998, true)
+
+	all := svc.ReadAllAreas("modbus-tcp")
+	for _, area := range svc.GetMemoryAreas("modbus-tcp") {
+		got, ok := all[area.ID]
+		if !ok {
+			t.Errorf("area %s missing from ReadAllAreas", area.ID)
+			continue
+		}
+		if area.IsBit {
+			want, err := svc.ReadBits("modbus-tcp", area.ID, 0, area.Size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("area %s: ReadAllAreas differs from ReadBits", area.ID)
+			}
+		} else {
+			want, err := svc.ReadWords("modbus-tcp", area.ID, 0, area.Size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("area %s: ReadAllAreas differs from ReadWords", area.ID)
+			}
+		}
+	}
+	if words := all["holdingRegisters"].([]int); words[5000] != 1234 {
+		t.Errorf("holdingRegisters[5000] = %d, want 1234", words[5000])
+	}
+
+	if svc.ReadAllAreas("unknown-protocol") != nil {
+		t.Error("expected nil for unknown server")
+	}
+}
+
+func TestPLCService_GetMemoryPage(t *testing.T) {
+	svc := newTestService(t)
+	for i := 0; i < 10; i++ {
+		_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 100+i, i+1)
+	}
+
+	page, err := svc.GetMemoryPage("modbus-tcp", "holdingRegisters", 100, 10)
+	if err != nil {
+		t.Fatalf("GetMemoryPage: %v", err)
+	}
+	want, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 100, 10)
+	if page.IsBit || page.Offset != 100 || page.Length != 10 || !reflect.DeepEqual(page.Words, want) {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	// 末尾を超える長さは切り詰める
+	page, err = svc.GetMemoryPage("modbus-tcp", "coils", 9990, 100)
+	if err != nil {
+		t.Fatalf("GetMemoryPage tail: %v", err)
+	}
+	if !page.IsBit || page.Length != 9 || len(page.Bits) != 9 || page.TotalSize != 9999 {
+		t.Errorf("unexpected tail page: length=%d bits=%d total=%d", page.Length, len(page.Bits), page.TotalSize)
+	}
+
+	if _, err := svc.GetMemoryPage("modbus-tcp", "coils", 10000, 1); err == nil {
+		t.Error("expected error for offset beyond area")
+	}
+	if _, err := svc.GetMemoryPage("modbus-tcp", "unknown", 0, 1); err == nil {
+		t.Error("expected error for unknown area")
+	}
+}
+
 func TestPLCService_ReadWriteWord_NotFound(t *testing.T) {
 	svc := newTestService(t)
 
@@ -324,45 +695,470 @@ func TestPLCService_ReadWriteBit_NotFound(t *testing.T) {
 		t.Fatal("expected error for non-existent server (WriteBit)")
 	}
 
-	_, err = svc.ReadBits("unknown-protocol", "coils", 0, 1)
-	if err == nil {
-		t.Fatal("expected error for non-existent server (ReadBits)")
+	_, err = svc.ReadBits("unknown-protocol", "coils", 0, 1)
+	if err == nil {
+		t.Fatal("expected error for non-existent server (ReadBits)")
+	}
+}
+
+func TestPLCService_MultiServer_IndependentMemory(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatalf("AddServer modbus-rtu failed: %v", err)
+	}
+
+	// Modbus TCP に書き込み
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 1111); err != nil {
+		t.Fatalf("WriteWord modbus-tcp failed: %v", err)
+	}
+
+	// Modbus RTU に書き込み
+	if err := svc.WriteWord("modbus-rtu", "holdingRegisters", 0, 2222); err != nil {
+		t.Fatalf("WriteWord modbus-rtu failed: %v", err)
+	}
+
+	// Modbus TCP の値が Modbus RTU に影響していないことを確認
+	modbusWords, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1)
+	if err != nil {
+		t.Fatalf("ReadWords modbus-tcp failed: %v", err)
+	}
+	if modbusWords[0] != 1111 {
+		t.Errorf("modbus-tcp expected 1111, got %d", modbusWords[0])
+	}
+
+	// Modbus RTU の値が Modbus TCP に影響していないことを確認
+	rtuWords, err := svc.ReadWords("modbus-rtu", "holdingRegisters", 0, 1)
+	if err != nil {
+		t.Fatalf("ReadWords modbus-rtu failed: %v", err)
+	}
+	if rtuWords[0] != 2222 {
+		t.Errorf("modbus-rtu expected 2222, got %d", rtuWords[0])
+	}
+}
+
+func TestPLCService_FillArea(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.FillArea("modbus-tcp", "holdingRegisters", 0xFFFF); err != nil {
+		t.Fatalf("FillArea failed: %v", err)
+	}
+
+	areas := svc.GetMemoryAreas("modbus-tcp")
+	var size int
+	for _, a := range areas {
+		if a.ID == "holdingRegisters" {
+			size = a.Size
+		}
+	}
+	for _, addr := range []int{0, 1, size / 2, size - 1} {
+		words, err := svc.ReadWords("modbus-tcp", "holdingRegisters", addr, 1)
+		if err != nil {
+			t.Fatalf("ReadWords failed: %v", err)
+		}
+		if words[0] != 0xFFFF {
+			t.Errorf("address %d: expected 0xFFFF, got 0x%04X", addr, words[0])
+		}
+	}
+}
+
+func TestPLCService_FillAreaPattern(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.FillAreaPattern("modbus-tcp", "holdingRegisters", []int{1, 2, 3}); err != nil {
+		t.Fatalf("FillAreaPattern failed: %v", err)
+	}
+
+	words, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 7)
+	if err != nil {
+		t.Fatalf("ReadWords failed: %v", err)
+	}
+	expected := []int{1, 2, 3, 1, 2, 3, 1}
+	for i, v := range expected {
+		if words[i] != v {
+			t.Errorf("address %d: expected %d, got %d", i, v, words[i])
+		}
+	}
+}
+
+func TestPLCService_FillArea_Bits(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.FillAreaPattern("modbus-tcp", "coils", []int{1, 0}); err != nil {
+		t.Fatalf("FillAreaPattern failed: %v", err)
+	}
+
+	bits, _ := svc.ReadBits("modbus-tcp", "coils", 0, 4)
+	if !bits[0] || bits[1] || !bits[2] || bits[3] {
+		t.Errorf("expected [true false true false], got %v", bits)
+	}
+}
+
+func TestPLCService_FillArea_Errors(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.FillArea("modbus-tcp", "unknownArea", 1); err == nil {
+		t.Error("expected error for unknown area")
+	}
+	if err := svc.FillAreaPattern("modbus-tcp", "holdingRegisters", nil); err == nil {
+		t.Error("expected error for empty pattern")
+	}
+}
+
+func TestPLCService_ScriptFill(t *testing.T) {
+	svc := newTestService(t)
+
+	if _, err := svc.RunScriptOnce(`plc.fill("holdingRegisters", 42)`); err != nil {
+		t.Fatalf("RunScriptOnce failed: %v", err)
+	}
+
+	words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 100, 1)
+	if words[0] != 42 {
+		t.Errorf("expected 42, got %d", words[0])
+	}
+
+	// 存在しないエリアは例外になる
+	if _, err := svc.RunScriptOnce(`plc.fill("unknownArea", 1)`); err == nil {
+		t.Error("expected error for unknown area")
+	}
+}
+
+func TestPLCService_ScriptLibrary(t *testing.T) {
+	svc := newTestService(t)
+
+	lib, _ := svc.CreateScript("regs", `
+		exports.setAll = function(v) { plc.fill("holdingRegisters", v); };
+	`, 1000)
+	if err := svc.SetScriptLibrary(lib.ID, true); err != nil {
+		t.Fatalf("SetScriptLibrary: %v", err)
+	}
+	if err := svc.StartScript(lib.ID); err == nil {
+		t.Error("expected error when starting a library script")
+	}
+
+	consumer, _ := svc.CreateScript("consumer", `require("regs").setAll(7);`, 1000)
+	if err := svc.StepScript(consumer.ID); err != nil {
+		t.Fatalf("StepScript: %v", err)
+	}
+	words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 5, 1)
+	if words[0] != 7 {
+		t.Errorf("holding register 5 = %d, want 7", words[0])
+	}
+
+	// ライブラリの更新は新しい require に反映され、フラグはエクスポートされる
+	if err := svc.UpdateScript(lib.ID, "regs", `exports.setAll = function(v) { plc.fill("holdingRegisters", v + 1); };`, 1000); err != nil {
+		t.Fatalf("UpdateScript: %v", err)
+	}
+	if _, err := svc.RunScriptOnce(`require("regs").setAll(7)`); err != nil {
+		t.Fatalf("RunScriptOnce: %v", err)
+	}
+	words, _ = svc.ReadWords("modbus-tcp", "holdingRegisters", 5, 1)
+	if words[0] != 8 {
+		t.Errorf("holding register 5 after update = %d, want 8", words[0])
+	}
+
+	project := svc.ExportProject()
+	dst := newTestService(t)
+	if err := dst.ImportProject(project); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := dst.GetScript(lib.ID); !got.IsLibrary {
+		t.Error("imported script lost its library flag")
+	}
+	if _, err := dst.RunScriptOnce(`require("regs").setAll(1)`); err != nil {
+		t.Errorf("require after import: %v", err)
+	}
+
+	if err := svc.DeleteScript(lib.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RunScriptOnce(`require("regs")`); err == nil {
+		t.Error("expected error after deleting the library")
+	}
+}
+
+func TestPLCService_RangeHex_RoundTrip(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.ImportRangeHex("modbus-tcp", "holdingRegisters", 10, "1234 abcd\n0000 FFFF"); err != nil {
+		t.Fatalf("ImportRangeHex: %v", err)
+	}
+	hex, err := svc.ExportRangeHex("modbus-tcp", "holdingRegisters", 10, 4)
+	if err != nil {
+		t.Fatalf("ExportRangeHex: %v", err)
+	}
+	if hex != "1234 ABCD 0000 FFFF" {
+		t.Errorf("ExportRangeHex = %q", hex)
+	}
+
+	// 0x 接頭辞・カンマ区切りも受け付け、エクスポート結果はそのまま再インポートできる
+	if err := svc.ImportRangeHex("modbus-tcp", "holdingRegisters", 20, "0x0001,0X00ff, 0x8000"); err != nil {
+		t.Fatalf("ImportRangeHex 0x format: %v", err)
+	}
+	words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 20, 3)
+	if !reflect.DeepEqual(words, []int{1, 0xFF, 0x8000}) {
+		t.Errorf("words = %v", words)
+	}
+	if err := svc.ImportRangeHex("modbus-tcp", "inputRegisters", 0, hex); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := svc.ExportRangeHex("modbus-tcp", "inputRegisters", 0, 4); got != hex {
+		t.Errorf("round trip = %q, want %q", got, hex)
+	}
+}
+
+func TestPLCService_RangeHex_Errors(t *testing.T) {
+	svc := newTestService(t)
+
+	for _, in := range []string{"", "12345", "xyz", "0x"} {
+		if err := svc.ImportRangeHex("modbus-tcp", "holdingRegisters", 0, in); err == nil {
+			t.Errorf("ImportRangeHex(%q): expected error", in)
+		}
+	}
+	if err := svc.ImportRangeHex("modbus-tcp", "holdingRegisters", 9998, "0001 0002"); err == nil {
+		t.Error("expected error when writing past the end of the area")
+	}
+	if _, err := svc.ExportRangeHex("modbus-tcp", "coils", 0, 1); err == nil {
+		t.Error("expected error for bit area")
+	}
+}
+
+func TestPLCService_ScriptHex(t *testing.T) {
+	svc := newTestService(t)
+
+	result, err := svc.RunScriptOnce(`plc.writeHex("holdingRegisters", 0, "0x00AA,0x00BB"); plc.readHex("holdingRegisters", 0, 2)`)
+	if err != nil {
+		t.Fatalf("RunScriptOnce failed: %v", err)
+	}
+	if result != "00AA 00BB" {
+		t.Errorf("readHex = %v, want \"00AA 00BB\"", result)
+	}
+}
+
+func TestPLCService_ScriptFloat32(t *testing.T) {
+	svc := newTestService(t)
+
+	// 1.5 = 0x3FC00000 をワードスワップ (CDAB) で格納
+	if err := svc.ImportRangeHex("modbus-tcp", "holdingRegisters", 0, "0000 3FC0"); err != nil {
+		t.Fatal(err)
+	}
+	result, err := svc.RunScriptOnce(`plc.readFloat32("holdingRegisters", 0, "CDAB")`)
+	if err != nil {
+		t.Fatalf("RunScriptOnce failed: %v", err)
+	}
+	if result != 1.5 {
+		t.Errorf("readFloat32 CDAB = %v, want 1.5", result)
+	}
+
+	// 省略時はサービスの既定値（初期値 ABCD）
+	if got := svc.GetDefaultByteOrder(); got != "ABCD" {
+		t.Errorf("GetDefaultByteOrder = %q, want ABCD", got)
+	}
+	if result, _ := svc.RunScriptOnce(`plc.readFloat32("holdingRegisters", 0)`); result == 1.5 {
+		t.Error("readFloat32 without byte order should use ABCD")
+	}
+	if err := svc.SetDefaultByteOrder("cdab"); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := svc.RunScriptOnce(`plc.readFloat32("holdingRegisters", 0)`); result != 1.5 {
+		t.Errorf("readFloat32 with default CDAB = %v, want 1.5", result)
+	}
+
+	// 書き込みも既定のバイト順に従う
+	if _, err := svc.RunScriptOnce(`plc.writeFloat32("holdingRegisters", 10, -2.5)`); err != nil {
+		t.Fatalf("writeFloat32: %v", err)
+	}
+	if hex, _ := svc.ExportRangeHex("modbus-tcp", "holdingRegisters", 10, 2); hex != "0000 C020" {
+		t.Errorf("writeFloat32 CDAB = %q, want \"0000 C020\"", hex)
+	}
+	if _, err := svc.RunScriptOnce(`plc.writeFloat32("holdingRegisters", 20, -2.5, "ABCD")`); err != nil {
+		t.Fatal(err)
+	}
+	if hex, _ := svc.ExportRangeHex("modbus-tcp", "holdingRegisters", 20, 2); hex != "C020 0000" {
+		t.Errorf("writeFloat32 ABCD = %q, want \"C020 0000\"", hex)
+	}
+
+	if err := svc.SetDefaultByteOrder("XYZW"); err == nil {
+		t.Error("expected error for unknown byte order")
+	}
+	if _, err := svc.RunScriptOnce(`plc.readFloat32("holdingRegisters", 0, "XYZW")`); err == nil {
+		t.Error("expected script error for unknown byte order")
+	}
+}
+
+func TestPLCService_ScriptEdge(t *testing.T) {
+	svc := newTestService(t)
+
+	steps := []struct {
+		coil bool
+		want string
+	}{
+		{false, "none"}, // 初回は前回値がないため none
+		{true, "rising"},
+		{true, "none"},
+		{false, "falling"},
+		{false, "none"},
+		{true, "rising"},
+	}
+	for i, step := range steps {
+		if err := svc.WriteBit("modbus-tcp", "coils", 7, step.coil); err != nil {
+			t.Fatal(err)
+		}
+		got, err := svc.RunScriptOnce(`plc.edge("coils", 7)`)
+		if err != nil {
+			t.Fatalf("step %d: RunScriptOnce failed: %v", i, err)
+		}
+		if got != step.want {
+			t.Errorf("step %d (coil=%v): edge = %v, want %s", i, step.coil, got, step.want)
+		}
+	}
+
+	// アドレスごとに独立して前回値を保持する
+	if got, _ := svc.RunScriptOnce(`plc.edge("coils", 8)`); got != "none" {
+		t.Errorf("other address: edge = %v, want none", got)
+	}
+}
+
+func TestPLCService_Baseline_CaptureAndRevert(t *testing.T) {
+	svc := newTestService(t)
+
+	if svc.HasBaseline("modbus-tcp") {
+		t.Fatal("expected no baseline before capture")
+	}
+
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 5, 100)
+	_ = svc.WriteBit("modbus-tcp", "coils", 2, true)
+
+	if err := svc.CaptureBaseline("modbus-tcp"); err != nil {
+		t.Fatalf("CaptureBaseline failed: %v", err)
+	}
+	if !svc.HasBaseline("modbus-tcp") {
+		t.Fatal("expected baseline after capture")
+	}
+
+	// メモリを変更
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 5, 999)
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 6, 1)
+	_ = svc.WriteBit("modbus-tcp", "coils", 2, false)
+
+	diffs, err := svc.DiffWithBaseline("modbus-tcp")
+	if err != nil {
+		t.Fatalf("DiffWithBaseline failed: %v", err)
+	}
+	if len(diffs) != 3 {
+		t.Errorf("expected 3 diffs, got %+v", diffs)
+	}
+
+	if err := svc.RevertToBaseline("modbus-tcp"); err != nil {
+		t.Fatalf("RevertToBaseline failed: %v", err)
+	}
+
+	words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 5, 2)
+	if words[0] != 100 || words[1] != 0 {
+		t.Errorf("expected [100 0] after revert, got %v", words)
+	}
+	bits, _ := svc.ReadBits("modbus-tcp", "coils", 2, 1)
+	if !bits[0] {
+		t.Error("expected coil 2 to be true after revert")
+	}
+}
+
+func TestPLCService_RevertToBaseline_NotCaptured(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.RevertToBaseline("modbus-tcp"); err == nil {
+		t.Error("expected error when baseline is not captured")
+	}
+}
+
+func TestPLCService_WordsForUnit_Isolated(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.SetPerUnitStore("modbus-tcp", true); err != nil {
+		t.Fatalf("SetPerUnitStore failed: %v", err)
+	}
+
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 100); err != nil {
+		t.Fatalf("WriteWord failed: %v", err)
+	}
+	if err := svc.WriteWordForUnit("modbus-tcp", 2, "holdingRegisters", 0, 200); err != nil {
+		t.Fatalf("WriteWordForUnit failed: %v", err)
+	}
+
+	unit1, err := svc.ReadWordsForUnit("modbus-tcp", 1, "holdingRegisters", 0, 1)
+	if err != nil {
+		t.Fatalf("ReadWordsForUnit failed: %v", err)
+	}
+	if unit1[0] != 100 {
+		t.Errorf("unit 1: expected 100, got %d", unit1[0])
+	}
+	unit2, _ := svc.ReadWordsForUnit("modbus-tcp", 2, "holdingRegisters", 0, 1)
+	if unit2[0] != 200 {
+		t.Errorf("unit 2: expected 200, got %d", unit2[0])
+	}
+
+	// UnitID なしの API は UnitID 1 のメモリを操作する
+	plain, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1)
+	if plain[0] != 100 {
+		t.Errorf("ReadWords: expected unit 1 value 100, got %d", plain[0])
+	}
+}
+
+// plainServer は ProtocolServer 以外のメソッドを隠したサーバー
+type plainServer struct{ protocol.ProtocolServer }
+
+func TestPLCService_WordsForUnit_UnsupportedServer(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.SetPerUnitStore("modbus-tcp", true); err != nil {
+		t.Fatalf("SetPerUnitStore failed: %v", err)
+	}
+	svc.mu.Lock()
+	inst := svc.servers["modbus-tcp"]
+	inst.server = plainServer{inst.server}
+	svc.mu.Unlock()
+
+	// UnitID 別メモリを読み書きできないサーバーでは共有メモリに黙って書き込まずエラーにする
+	if err := svc.WriteWordForUnit("modbus-tcp", 2, "holdingRegisters", 0, 200); err == nil {
+		t.Error("expected error for server without per-unit memory access")
+	}
+	if v, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); v[0] != 0 {
+		t.Errorf("shared memory was written: %d", v[0])
+	}
+	if _, err := svc.ReadWordsForUnit("modbus-tcp", 2, "holdingRegisters", 0, 1); err == nil {
+		t.Error("expected error reading unit 2 from server without per-unit memory access")
+	}
+	// UnitID 1 は共有メモリなので読み書きできる
+	if err := svc.WriteWordForUnit("modbus-tcp", 1, "holdingRegisters", 0, 100); err != nil {
+		t.Errorf("WriteWordForUnit(unit 1): %v", err)
 	}
 }
 
-func TestPLCService_MultiServer_IndependentMemory(t *testing.T) {
+func TestPLCService_BitsForUnit_Isolated(t *testing.T) {
 	svc := newTestService(t)
+	svc.SetPerUnitStore("modbus-tcp", true)
 
-	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
-		t.Fatalf("AddServer modbus-rtu failed: %v", err)
+	if err := svc.WriteBitForUnit("modbus-tcp", 2, "coils", 5, true); err != nil {
+		t.Fatalf("WriteBitForUnit failed: %v", err)
 	}
-
-	// Modbus TCP に書き込み
-	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 1111); err != nil {
-		t.Fatalf("WriteWord modbus-tcp failed: %v", err)
+	unit2, _ := svc.ReadBitsForUnit("modbus-tcp", 2, "coils", 5, 1)
+	unit1, _ := svc.ReadBitsForUnit("modbus-tcp", 1, "coils", 5, 1)
+	if !unit2[0] || unit1[0] {
+		t.Errorf("expected coil only on unit 2, got unit1=%v unit2=%v", unit1[0], unit2[0])
 	}
+}
 
-	// Modbus RTU に書き込み
-	if err := svc.WriteWord("modbus-rtu", "holdingRegisters", 0, 2222); err != nil {
-		t.Fatalf("WriteWord modbus-rtu failed: %v", err)
-	}
+func TestPLCService_WordsForUnit_SharedWhenDisabled(t *testing.T) {
+	svc := newTestService(t)
 
-	// Modbus TCP の値が Modbus RTU に影響していないことを確認
-	modbusWords, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1)
-	if err != nil {
-		t.Fatalf("ReadWords modbus-tcp failed: %v", err)
+	if err := svc.WriteWordForUnit("modbus-tcp", 2, "holdingRegisters", 0, 300); err != nil {
+		t.Fatalf("WriteWordForUnit failed: %v", err)
 	}
-	if modbusWords[0] != 1111 {
-		t.Errorf("modbus-tcp expected 1111, got %d", modbusWords[0])
+	vals, _ := svc.ReadWordsForUnit("modbus-tcp", 1, "holdingRegisters", 0, 1)
+	if vals[0] != 300 {
+		t.Errorf("expected shared value 300, got %d", vals[0])
 	}
 
-	// Modbus RTU の値が Modbus TCP に影響していないことを確認
-	rtuWords, err := svc.ReadWords("modbus-rtu", "holdingRegisters", 0, 1)
-	if err != nil {
-		t.Fatalf("ReadWords modbus-rtu failed: %v", err)
-	}
-	if rtuWords[0] != 2222 {
-		t.Errorf("modbus-rtu expected 2222, got %d", rtuWords[0])
+	if _, err := svc.ReadWordsForUnit("modbus-tcp", 256, "holdingRegisters", 0, 1); err == nil {
+		t.Error("expected error for out-of-range unit ID")
 	}
 }
 
@@ -395,6 +1191,55 @@ func TestPLCService_GetServerConfig_NotFound(t *testing.T) {
 	}
 }
 
+func TestPLCService_SetPerUnitStore(t *testing.T) {
+	svc := newTestService(t)
+
+	if svc.IsPerUnitStore("modbus-tcp") {
+		t.Fatal("expected per-unit store to be disabled by default")
+	}
+	if err := svc.SetPerUnitStore("modbus-tcp", true); err != nil {
+		t.Fatalf("SetPerUnitStore failed: %v", err)
+	}
+	if !svc.IsPerUnitStore("modbus-tcp") {
+		t.Error("expected per-unit store to be enabled")
+	}
+	// サーバー設定として保存される
+	if got := svc.GetServerConfig("modbus-tcp").Settings["perUnitStore"]; got != "true" {
+		t.Errorf("perUnitStore setting = %v, want true", got)
+	}
+	if err := svc.SetPerUnitStore("unknown-protocol", true); err == nil {
+		t.Error("expected error for unknown protocol")
+	}
+}
+
+func TestPLCService_SetUnitIDRemap(t *testing.T) {
+	svc := newTestService(t)
+
+	if err := svc.SetUnitIDRemap("modbus-tcp", map[int]int{6: 1, 5: 1}); err != nil {
+		t.Fatalf("SetUnitIDRemap failed: %v", err)
+	}
+	// サーバー設定として保存される
+	if got := svc.GetServerConfig("modbus-tcp").Settings["unitIdRemap"]; got != "5=1,6=1" {
+		t.Errorf("unitIdRemap setting = %v, want 5=1,6=1", got)
+	}
+	if got := svc.GetUnitIDRemap("modbus-tcp"); len(got) != 2 || got[5] != 1 || got[6] != 1 {
+		t.Errorf("GetUnitIDRemap = %v", got)
+	}
+	if err := svc.SetUnitIDRemap("modbus-tcp", nil); err != nil {
+		t.Fatalf("SetUnitIDRemap(nil) failed: %v", err)
+	}
+	if got := svc.GetUnitIDRemap("modbus-tcp"); len(got) != 0 {
+		t.Errorf("GetUnitIDRemap after clearing = %v", got)
+	}
+
+	if err := svc.SetUnitIDRemap("modbus-tcp", map[int]int{5: 256}); err == nil {
+		t.Error("expected error for out-of-range unit ID")
+	}
+	if err := svc.SetUnitIDRemap("unknown-protocol", nil); err == nil {
+		t.Error("expected error for unknown protocol")
+	}
+}
+
 // ===== モニタリング管理テスト =====
 
 func TestPLCService_AddMonitoringItem_GeneratesIDAndOrder(t *testing.T) {
@@ -424,6 +1269,46 @@ func TestPLCService_AddMonitoringItem_GeneratesIDAndOrder(t *testing.T) {
 	}
 }
 
+func TestPLCService_SuggestMonitoringDefaults(t *testing.T) {
+	svc := newTestService(t)
+
+	tests := []struct {
+		name       string
+		area       string
+		wantWidth  int
+		wantEndian string
+		wantFormat string
+	}{
+		{"coil is boolean", "coils", 1, "", "boolean"},
+		{"discrete input is boolean", "discreteInputs", 1, "", "boolean"},
+		{"holding register is hex word", "holdingRegisters", 16, "big", "hex"},
+		{"input register is hex word", "inputRegisters", 16, "big", "hex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.SuggestMonitoringDefaults("modbus-tcp", tt.area, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ProtocolType != "modbus-tcp" || got.MemoryArea != tt.area || got.Address != 5 {
+				t.Errorf("unexpected target: %+v", got)
+			}
+			if got.BitWidth != tt.wantWidth || got.Endianness != tt.wantEndian || got.DisplayFormat != tt.wantFormat {
+				t.Errorf("got bitWidth=%d endianness=%q format=%q, want %d %q %q",
+					got.BitWidth, got.Endianness, got.DisplayFormat, tt.wantWidth, tt.wantEndian, tt.wantFormat)
+			}
+		})
+	}
+
+	if _, err := svc.SuggestMonitoringDefaults("modbus-tcp", "noSuchArea", 0); err == nil {
+		t.Error("expected error for unknown area")
+	}
+	// 提案だけでは項目は登録されない
+	if items := svc.GetMonitoringItems(); len(items) != 0 {
+		t.Errorf("expected no monitoring items, got %d", len(items))
+	}
+}
+
 func TestPLCService_AddMonitoringItem_OrderIncrementsSequentially(t *testing.T) {
 	svc := newTestService(t)
 
@@ -608,6 +1493,135 @@ func TestPLCService_ClearMonitoringItems(t *testing.T) {
 	}
 }
 
+func TestPLCService_SetMonitoringItems(t *testing.T) {
+	svc := newTestService(t)
+	if _, err := svc.AddMonitoringItem(&MonitoringItemDTO{ProtocolType: "modbus-tcp", MemoryArea: "coils", Address: 99}); err != nil {
+		t.Fatal(err)
+	}
+
+	kept := &MonitoringItemDTO{ID: "kept", ProtocolType: "modbus-tcp", MemoryArea: "inputRegisters", Address: 1, BitWidth: 16}
+	err := svc.SetMonitoringItems([]*MonitoringItemDTO{
+		{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 5, BitWidth: 32},
+		kept,
+		{ProtocolType: "modbus-tcp", MemoryArea: "coils", Address: 0, Order: 100},
+	})
+	if err != nil {
+		t.Fatalf("SetMonitoringItems failed: %v", err)
+	}
+
+	items := svc.GetMonitoringItems()
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	wantAreas := []string{"holdingRegisters", "inputRegisters", "coils"}
+	for i, item := range items {
+		if item.MemoryArea != wantAreas[i] || item.Order != i+1 {
+			t.Errorf("items[%d] = %s order %d, want %s order %d", i, item.MemoryArea, item.Order, wantAreas[i], i+1)
+		}
+		if item.ID == "" {
+			t.Errorf("items[%d] has no ID", i)
+		}
+	}
+	if items[1].ID != "kept" {
+		t.Errorf("expected given ID to be kept, got %s", items[1].ID)
+	}
+}
+
+func TestPLCService_GetMonitoringItemsByGroup(t *testing.T) {
+	svc := newTestService(t)
+	add := func(group string, address int) {
+		t.Helper()
+		if _, err := svc.AddMonitoringItem(&MonitoringItemDTO{
+			ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: address, BitWidth: 16, Group: group,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("pump", 0)
+	add("valve", 1)
+	add("pump", 2)
+	add("", 3)
+	add("valve", 4)
+	add("pump", 5)
+
+	wantAddresses := map[string][]int{
+		"pump":  {0, 2, 5},
+		"valve": {1, 4},
+		"":      {3},
+	}
+	check := func(svc *PLCService) {
+		t.Helper()
+		groups := svc.GetMonitoringItemsByGroup()
+		if len(groups) != len(wantAddresses) {
+			t.Fatalf("expected %d groups, got %d", len(wantAddresses), len(groups))
+		}
+		for group, want := range wantAddresses {
+			items := groups[group]
+			got := make([]int, len(items))
+			for i, item := range items {
+				got[i] = item.Address
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("group %q addresses = %v, want %v", group, got, want)
+			}
+		}
+	}
+	check(svc)
+
+	// 順序を変えてもグループ内は Order 順
+	items := svc.GetMonitoringItems()
+	if err := svc.ReorderMonitoringItem(items[5].ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	wantAddresses["pump"] = []int{5, 0, 2}
+	check(svc)
+
+	// プロジェクト経由でグループが保存される
+	data, err := json.Marshal(svc.ExportProject())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var project ProjectDataDTO
+	if err := json.Unmarshal(data, &project); err != nil {
+		t.Fatal(err)
+	}
+	restored := newTestService(t)
+	if err := restored.ImportProject(&project); err != nil {
+		t.Fatal(err)
+	}
+	check(restored)
+}
+
+func TestPLCService_SetMonitoringItems_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []*MonitoringItemDTO
+	}{
+		{"unknown area", []*MonitoringItemDTO{{ProtocolType: "modbus-tcp", MemoryArea: "noSuchArea"}}},
+		{"unknown server", []*MonitoringItemDTO{{ProtocolType: "modbus-rtu", MemoryArea: "coils"}}},
+		{"duplicate ID", []*MonitoringItemDTO{
+			{ID: "a", ProtocolType: "modbus-tcp", MemoryArea: "coils"},
+			{ID: "a", ProtocolType: "modbus-tcp", MemoryArea: "coils", Address: 1},
+		}},
+		{"nil item", []*MonitoringItemDTO{nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+			if _, err := svc.AddMonitoringItem(&MonitoringItemDTO{ProtocolType: "modbus-tcp", MemoryArea: "coils"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := svc.SetMonitoringItems(tt.items); err == nil {
+				t.Error("expected error")
+			}
+			// 失敗時は元の一覧のまま
+			if n := len(svc.GetMonitoringItems()); n != 1 {
+				t.Errorf("expected list to be unchanged, got %d items", n)
+			}
+		})
+	}
+}
+
 func TestPLCService_MonitoringItem_ProtocolType_MultiServer(t *testing.T) {
 	svc := newTestService(t)
 
@@ -642,6 +1656,105 @@ func TestPLCService_MonitoringItem_ProtocolType_MultiServer(t *testing.T) {
 	}
 }
 
+func TestPLCService_ReadMonitoringValues(t *testing.T) {
+	svc := newTestService(t)
+
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 0x1234)
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 1, 0x5678)
+	_ = svc.WriteBit("modbus-tcp", "coils", 3, true)
+
+	items := []*MonitoringItemDTO{
+		{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 0, BitWidth: 32, Endianness: "big"},
+		{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 0, BitWidth: 32, Endianness: "little"},
+		{ProtocolType: "modbus-tcp", MemoryArea: "coils", Address: 3},
+		{ProtocolType: "unknown", MemoryArea: "holdingRegisters", Address: 0, BitWidth: 16},
+	}
+	for _, item := range items {
+		if _, err := svc.AddMonitoringItem(item); err != nil {
+			t.Fatalf("AddMonitoringItem failed: %v", err)
+		}
+	}
+
+	values := svc.ReadMonitoringValues()
+	if len(values) != 4 {
+		t.Fatalf("expected 4 values, got %d", len(values))
+	}
+	if values[0].Value != uint64(0x12345678) {
+		t.Errorf("big endian: expected 0x12345678, got %v", values[0].Value)
+	}
+	if values[1].Value != uint64(0x56781234) {
+		t.Errorf("little endian: expected 0x56781234, got %v", values[1].Value)
+	}
+	if !values[2].IsBit || values[2].Value != true {
+		t.Errorf("expected bit value true, got %+v", values[2])
+	}
+	if values[3].Error == "" {
+		t.Error("expected error for unknown protocol")
+	}
+}
+
+// ===== 診断ログテスト =====
+
+func TestPLCService_GetLogs_LevelFilter(t *testing.T) {
+	svc := newTestService(t)
+	prev := svc.GetLogLevel()
+	t.Cleanup(func() { svc.SetLogLevel(prev) })
+
+	if err := svc.SetLogLevel("debug"); err != nil {
+		t.Fatalf("SetLogLevel failed: %v", err)
+	}
+	svc.ClearLogs()
+	svc.Logger().Debug("debug message")
+	svc.Logger().Info("info message")
+	svc.Logger().Error("error message", "protocol", "modbus-tcp")
+
+	all, err := svc.GetLogs("", 0)
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 logs, got %d", len(all))
+	}
+
+	errs, _ := svc.GetLogs("error", 0)
+	if len(errs) != 1 || errs[0].Message != "error message" || errs[0].Level != "error" {
+		t.Errorf("unexpected error logs: %+v", errs)
+	}
+	if errs[0].Attrs["protocol"] != "modbus-tcp" {
+		t.Errorf("expected protocol attr, got %v", errs[0].Attrs)
+	}
+
+	// 出力レベルを上げると以降の低レベルログは保持されない
+	svc.SetLogLevel("warn")
+	svc.Logger().Info("suppressed")
+	if logs, _ := svc.GetLogs("", 0); len(logs) != 3 {
+		t.Errorf("expected info log to be suppressed at warn level, got %d logs", len(logs))
+	}
+
+	if _, err := svc.GetLogs("verbose", 0); err == nil {
+		t.Error("expected error for unknown level")
+	}
+	if err := svc.SetLogLevel("verbose"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestPLCService_GetLogs_Bounded(t *testing.T) {
+	svc := newTestService(t)
+	svc.ClearLogs()
+
+	for i := 0; i < maxLogEntries+10; i++ {
+		svc.Logger().Error("overflow")
+	}
+	logs, _ := svc.GetLogs("", 0)
+	if len(logs) != maxLogEntries {
+		t.Errorf("expected %d logs, got %d", maxLogEntries, len(logs))
+	}
+	if limited, _ := svc.GetLogs("", 5); len(limited) != 5 {
+		t.Errorf("expected limit 5, got %d", len(limited))
+	}
+}
+
 // ===== スクリプト管理テスト =====
 
 func TestPLCService_CreateScript(t *testing.T) {
@@ -685,6 +1798,80 @@ func TestPLCService_GetScript(t *testing.T) {
 	}
 }
 
+func TestPLCService_StepScript(t *testing.T) {
+	svc := newTestService(t)
+
+	created, _ := svc.CreateScript("step", `throw new Error("boom")`, 1000)
+
+	if err := svc.StepScript(created.ID); err == nil {
+		t.Fatal("expected step error to be returned")
+	}
+	got, _ := svc.GetScript(created.ID)
+	if got.IsRunning {
+		t.Error("stepped script should not be running")
+	}
+	if got.LastError == "" {
+		t.Error("expected lastError to be recorded")
+	}
+
+	if err := svc.StepScript("nonexistent-id"); err == nil {
+		t.Error("expected error for non-existent script")
+	}
+}
+
+func TestPLCService_PauseResumeScript(t *testing.T) {
+	svc := newTestService(t)
+
+	created, _ := svc.CreateScript("loop", "1 + 1", 1000)
+	if err := svc.StartScript(created.ID); err != nil {
+		t.Fatalf("StartScript failed: %v", err)
+	}
+	defer svc.StopScript(created.ID)
+
+	if err := svc.PauseScript(created.ID); err != nil {
+		t.Fatalf("PauseScript failed: %v", err)
+	}
+	got, _ := svc.GetScript(created.ID)
+	if got.IsRunning || !got.IsPaused {
+		t.Errorf("after pause: isRunning=%v isPaused=%v, want false/true", got.IsRunning, got.IsPaused)
+	}
+
+	if err := svc.ResumeScript(created.ID); err != nil {
+		t.Fatalf("ResumeScript failed: %v", err)
+	}
+	got, _ = svc.GetScript(created.ID)
+	if !got.IsRunning || got.IsPaused {
+		t.Errorf("after resume: isRunning=%v isPaused=%v, want true/false", got.IsRunning, got.IsPaused)
+	}
+}
+
+func TestPLCService_ScriptTimeout(t *testing.T) {
+	svc := newTestService(t)
+	if got := svc.GetScriptTimeout(); got != 10000 {
+		t.Errorf("default script timeout = %d, want 10000", got)
+	}
+	for _, ms := range []int{-1, 50} {
+		if err := svc.SetScriptTimeout(ms); err == nil {
+			t.Errorf("SetScriptTimeout(%d): expected error", ms)
+		}
+	}
+
+	if err := svc.SetScriptTimeout(100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RunScriptOnce("while (true) {}"); !errors.Is(err, scripting.ErrScriptTimeout) {
+		t.Errorf("RunScriptOnce = %v, want timeout error", err)
+	}
+
+	// 0 は無制限
+	if err := svc.SetScriptTimeout(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.GetScriptTimeout(); got != 0 {
+		t.Errorf("script timeout = %d, want 0", got)
+	}
+}
+
 func TestPLCService_GetScript_NotFound(t *testing.T) {
 	svc := newTestService(t)
 
@@ -854,6 +2041,45 @@ func TestPLCService_ExportProject_ContainsMonitoringItems(t *testing.T) {
 	}
 }
 
+func TestPLCService_ExportProject_ConsistentWithRunningScript(t *testing.T) {
+	svc := newTestService(t)
+
+	// 1回の実行でレジスタ0と1に同じ値を書く（間に待ちを入れて途中の状態を長くする）
+	code := `
+var n = (parseInt(plc.readHex("holdingRegisters", 0, 1), 16) + 1) & 0xFFFF;
+var h = ("0000" + n.toString(16)).slice(-4);
+plc.writeHex("holdingRegisters", 0, h);
+plc.sleep(2);
+plc.writeHex("holdingRegisters", 1, h);
+`
+	sc, err := svc.CreateScript("pair", code, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.StartScript(sc.ID); err != nil {
+		t.Fatal(err)
+	}
+	defer svc.scriptEngine.StopAll()
+
+	var last uint16
+	for i := 0; i < 20; i++ {
+		project := svc.ExportProject()
+		memory, err := datastore.DecodeSnapshotBinary(project.Servers[0].MemoryBinary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hr := memory["holdingRegisters"].([]uint16)
+		if hr[0] != hr[1] {
+			t.Fatalf("export %d is torn: holdingRegisters[0]=%d, [1]=%d", i, hr[0], hr[1])
+		}
+		last = hr[0]
+		time.Sleep(3 * time.Millisecond)
+	}
+	if last == 0 {
+		t.Error("script did not run during export")
+	}
+}
+
 func TestPLCService_ImportProject_RestoresServers(t *testing.T) {
 	svc := newTestService(t)
 
@@ -910,6 +2136,66 @@ func TestPLCService_ImportProject_RestoresScripts(t *testing.T) {
 	}
 }
 
+func TestPLCService_ImportProject_StartsEnabledScripts(t *testing.T) {
+	svc := newTestService(t)
+	t.Cleanup(func() { svc.scriptEngine.StopAll() })
+
+	data := &ProjectDataDTO{
+		Servers: []ServerSnapshotDTO{
+			{ProtocolType: "modbus-tcp", Variant: "tcp"},
+		},
+		Scripts: []*ScriptDTO{
+			{ID: "enabled", Name: "auto", Code: "1+1", IntervalMs: 1000, Enabled: true},
+			{ID: "disabled", Name: "manual", Code: "2+2", IntervalMs: 1000},
+		},
+	}
+	if err := svc.ImportProject(data); err != nil {
+		t.Fatalf("ImportProject failed: %v", err)
+	}
+
+	if got, _ := svc.GetScript("enabled"); !got.IsRunning || !got.Enabled {
+		t.Errorf("enabled script: isRunning=%v enabled=%v, want true/true", got.IsRunning, got.Enabled)
+	}
+	if got, _ := svc.GetScript("disabled"); got.IsRunning {
+		t.Error("disabled script should not be running")
+	}
+
+	// 停止すると無効になり、エクスポートにも反映される
+	if err := svc.StopScript("enabled"); err != nil {
+		t.Fatalf("StopScript failed: %v", err)
+	}
+	_ = svc.StartScript("disabled")
+	exported := map[string]bool{}
+	for _, sc := range svc.ExportProject().Scripts {
+		exported[sc.ID] = sc.Enabled
+	}
+	if exported["enabled"] || !exported["disabled"] {
+		t.Errorf("exported enabled flags = %v, want enabled=false disabled=true", exported)
+	}
+}
+
+func TestPLCService_ImportProject_SkipScripts(t *testing.T) {
+	svc := newTestService(t)
+	t.Cleanup(func() { svc.scriptEngine.StopAll() })
+
+	data := &ProjectDataDTO{
+		Servers: []ServerSnapshotDTO{
+			{ProtocolType: "modbus-tcp", Variant: "tcp"},
+		},
+		Scripts: []*ScriptDTO{
+			{ID: "enabled", Name: "auto", Code: "1+1", IntervalMs: 1000, Enabled: true},
+		},
+	}
+	if err := svc.ImportProjectWithOptions(data, ImportOptions{SkipChecksum: true, SkipScripts: true}); err != nil {
+		t.Fatalf("ImportProjectWithOptions failed: %v", err)
+	}
+
+	// 起動はしないが、有効フラグはエクスポートのために保持する
+	if got, _ := svc.GetScript("enabled"); got.IsRunning || !got.Enabled {
+		t.Errorf("enabled script: isRunning=%v enabled=%v, want false/true", got.IsRunning, got.Enabled)
+	}
+}
+
 func TestPLCService_ImportProject_RestoresMonitoringItems(t *testing.T) {
 	svc := newTestService(t)
 
@@ -963,6 +2249,56 @@ func TestPLCService_ImportProject_ClearsExistingServers(t *testing.T) {
 	}
 }
 
+func TestPLCService_ImportProject_StopsRunningScripts(t *testing.T) {
+	svc := newTestService(t)
+
+	sc, err := svc.CreateScript("writer", `
+for (let i = 1; i <= 10; i++) {
+  plc.fill("holdingRegisters", i);
+}`, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.StartScript(sc.ID); err != nil {
+		t.Fatal(err)
+	}
+	svc.mu.RLock()
+	oldStore := svc.servers["modbus-tcp"].dataStore
+	svc.mu.RUnlock()
+
+	// スクリプトが旧ストアに書き込み始めるまで待つ
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if v, _ := oldStore.ReadWord("holdingRegisters", 0); v != 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("script did not write")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	data := &ProjectDataDTO{
+		Servers: []ServerSnapshotDTO{{ProtocolType: "modbus-tcp", Variant: "tcp"}},
+	}
+	if err := svc.ImportProject(data); err != nil {
+		t.Fatalf("ImportProject failed: %v", err)
+	}
+
+	if svc.scriptEngine.IsLoaded(sc.ID) {
+		t.Error("previous script is still loaded after import")
+	}
+	// インポート後は旧ストアにも新ストアにも書き込まない
+	before, _ := oldStore.ReadWord("holdingRegisters", 0)
+	time.Sleep(250 * time.Millisecond)
+	if after, _ := oldStore.ReadWord("holdingRegisters", 0); after != before {
+		t.Errorf("old store written after import: %d -> %d", before, after)
+	}
+	if v, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1); v[0] != 0 {
+		t.Errorf("new store holdingRegisters[0] = %d, want 0", v[0])
+	}
+}
+
 func TestPLCService_ExportImport_RoundTrip(t *testing.T) {
 	svc := newTestService(t)
 
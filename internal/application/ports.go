@@ -0,0 +1,69 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// ErrPortInUse は設定された TCP ポートが既に使用されている場合のエラー
+var ErrPortInUse = errors.New("already in use")
+
+// privilegedPortLimit は Unix で root 権限が必要なポート番号の上限（この値未満）
+const privilegedPortLimit = 1024
+
+// IsPortAvailable は TCP ポートが他のプロセスに使用されていないかどうかを返す
+func (s *PLCService) IsPortAvailable(port int) bool {
+	return !tcpPortInUse("", port)
+}
+
+// tcpPortInUse は address:port が既に使用中（EADDRINUSE）かどうかを返す。
+// 権限不足やアドレス解決の失敗などそれ以外のエラーは使用中とみなさず、実際の起動処理に任せる。
+func tcpPortInUse(address string, port int) bool {
+	ln, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return isAddrInUse(err)
+	}
+	ln.Close()
+	return false
+}
+
+// settingsTCPAddress はサーバー設定の tcpAddress を返す（未設定の場合は空文字列）
+func settingsTCPAddress(settings map[string]interface{}) string {
+	address, _ := settings["tcpAddress"].(string)
+	return address
+}
+
+// settingsTCPPort はサーバー設定の tcpPort を返す（TCP ポートを持たない場合は 0）
+func settingsTCPPort(settings map[string]interface{}) int {
+	switch v := settings["tcpPort"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// checkServerPort はサーバー起動前に TCP ポートを確認する（ロック取得済みであること）。
+// 設定されたアドレスで既に使用中の場合のみ ErrPortInUse を返し、Unix で特権ポートを root 以外で使う場合は警告を出す。
+func (s *PLCService) checkServerPort(inst *serverInstance) error {
+	if inst.factory == nil {
+		return nil
+	}
+	settings := inst.factory.ConfigToMap(inst.config)
+	port := settingsTCPPort(settings)
+	if port <= 0 {
+		return nil
+	}
+	if tcpPortInUse(settingsTCPAddress(settings), port) {
+		return fmt.Errorf("port %d %w", port, ErrPortInUse)
+	}
+	if runtime.GOOS != "windows" && port < privilegedPortLimit && os.Geteuid() != 0 {
+		s.logger.Warn("binding to a privileged port may require root", "protocol", inst.protocolType, "port", port)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package application
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isAddrInUse は listen のエラーがアドレス使用中（EADDRINUSE）によるものかどうかを返す
+func isAddrInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE)
+}
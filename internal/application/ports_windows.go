@@ -0,0 +1,14 @@
+//go:build windows
+
+package application
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isAddrInUse は listen のエラーがアドレス使用中（WSAEADDRINUSE）によるものかどうかを返す
+func isAddrInUse(err error) bool {
+	return errors.Is(err, windows.WSAEADDRINUSE)
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// defaultProjectBackupDepth はプロジェクトファイルを上書きする際に残すバックアップの世代数の初期値
+const defaultProjectBackupDepth = 2
+
+// maxProjectBackupDepth は SetProjectBackupDepth で指定できる世代数の上限
+const maxProjectBackupDepth = 10
+
+// SetProjectBackupDepth は既存のプロジェクトファイルへ保存する際に残すバックアップの世代数を設定する。
+// 上書き前の内容を <path>.bak1 に保存し、それまでのバックアップは .bak2, .bak3 … へずらす（depth を超えた分は削除）。
+// 0 でバックアップしない。
+func (s *PLCService) SetProjectBackupDepth(depth int) error {
+	if depth < 0 || depth > maxProjectBackupDepth {
+		return fmt.Errorf("backup depth must be between 0 and %d: %d", maxProjectBackupDepth, depth)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.projectBackupDepth = depth
+	return nil
+}
+
+// GetProjectBackupDepth はプロジェクトファイルのバックアップ世代数を返す
+func (s *PLCService) GetProjectBackupDepth() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.projectBackupDepth
+}
+
+// projectBackupPath は n 世代前のバックアップのパスを返す
+func projectBackupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak%d", path, n)
+}
+
+// rotateProjectBackups は path の現在の内容を .bak1 に保存し、既存のバックアップを1世代ずつずらす。
+// path が存在しない場合や depth が 0 の場合は何もしない。
+func rotateProjectBackups(path string, depth int) error {
+	if depth <= 0 {
+		return nil
+	}
+	current, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// 最も古い世代を消してから順にずらす
+	if err := os.Remove(projectBackupPath(path, depth)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for n := depth - 1; n >= 1; n-- {
+		if err := os.Rename(projectBackupPath(path, n), projectBackupPath(path, n+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	// 元のファイルは残したまま（保存に失敗しても消えないよう）内容をコピーする
+	return writeFileAtomic(projectBackupPath(path, 1), current)
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readProjectHoldingRegister は保存されたプロジェクトファイルの保持レジスタの値を返す
+func readProjectHoldingRegister(t *testing.T, path string, address int) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	var project ProjectDataDTO
+	if err := json.Unmarshal(data, &project); err != nil {
+		t.Fatalf("%s is not a project: %v", path, err)
+	}
+	other := newTestService(t)
+	if err := other.ImportProject(&project); err != nil {
+		t.Fatal(err)
+	}
+	words, err := other.ReadWords("modbus-tcp", "holdingRegisters", address, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return words[0]
+}
+
+func TestPLCService_ExportProjectToFile_KeepsBackups(t *testing.T) {
+	svc := newTestService(t)
+	path := filepath.Join(t.TempDir(), "project.json")
+
+	for _, value := range []int{1, 2, 3, 4} {
+		if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, value); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ExportProjectToFile(path); err != nil {
+			t.Fatalf("ExportProjectToFile(%d): %v", value, err)
+		}
+	}
+
+	if got := readProjectHoldingRegister(t, path, 0); got != 4 {
+		t.Errorf("project = %d, want 4", got)
+	}
+	if got := readProjectHoldingRegister(t, projectBackupPath(path, 1), 0); got != 3 {
+		t.Errorf(".bak1 = %d, want 3", got)
+	}
+	if got := readProjectHoldingRegister(t, projectBackupPath(path, 2), 0); got != 2 {
+		t.Errorf(".bak2 = %d, want 2", got)
+	}
+	// 既定の世代数（2）を超えたバックアップは残さない
+	if _, err := os.Stat(projectBackupPath(path, 3)); !os.IsNotExist(err) {
+		t.Errorf(".bak3 should not exist: %v", err)
+	}
+}
+
+func TestPLCService_ExportProjectToFile_BackupDisabled(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.SetProjectBackupDepth(0); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "project.json")
+	for i := 0; i < 2; i++ {
+		if err := svc.ExportProjectToFile(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(projectBackupPath(path, 1)); !os.IsNotExist(err) {
+		t.Errorf(".bak1 should not exist when backups are disabled: %v", err)
+	}
+}
+
+func TestPLCService_SetProjectBackupDepth_OutOfRange(t *testing.T) {
+	svc := newTestService(t)
+	for _, depth := range []int{-1, maxProjectBackupDepth + 1} {
+		if err := svc.SetProjectBackupDepth(depth); err == nil {
+			t.Errorf("SetProjectBackupDepth(%d) should fail", depth)
+		}
+	}
+	if got := svc.GetProjectBackupDepth(); got != defaultProjectBackupDepth {
+		t.Errorf("depth = %d, want %d", got, defaultProjectBackupDepth)
+	}
+}
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"fmt"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+// ProjectDataDTO.MemoryFormat の値
+const (
+	MemoryFormatJSON   = "json"
+	MemoryFormatBinary = "binary"
+)
+
+// binaryMemoryThreshold はプロジェクトのメモリ内容をバイナリ形式で保存する合計点数のしきい値
+const binaryMemoryThreshold = 4096
+
+// snapshotPoints はスナップショットの合計点数を返す
+func snapshotPoints(snap map[string]interface{}) int {
+	total := 0
+	for _, v := range snap {
+		if bits, ok := datastore.SnapshotBits(v); ok {
+			total += len(bits)
+		} else if words, ok := datastore.SnapshotWords(v); ok {
+			total += len(words)
+		}
+	}
+	return total
+}
+
+// attachServerMemory は各サーバーのメモリ内容をプロジェクトに格納する。
+// 合計点数が binaryMemoryThreshold を超える場合は gzip 圧縮したバイナリ形式、それ以外は JSON で格納する。
+// snapshots は servers と同じ順序であること。
+func attachServerMemory(project *ProjectDataDTO, snapshots []map[string]interface{}) {
+	total := 0
+	for _, snap := range snapshots {
+		total += snapshotPoints(snap)
+	}
+	if total == 0 {
+		return
+	}
+
+	project.MemoryFormat = MemoryFormatJSON
+	if total > binaryMemoryThreshold {
+		project.MemoryFormat = MemoryFormatBinary
+	}
+	for i, snap := range snapshots {
+		if project.MemoryFormat == MemoryFormatBinary {
+			project.Servers[i].MemoryBinary = datastore.EncodeSnapshotBinary(snap)
+		} else {
+			project.Servers[i].Memory = snap
+		}
+	}
+}
+
+// restoreServerMemory はプロジェクトに保存されたメモリ内容をサーバーに復元する（ロック取得済みであること）
+func restoreServerMemory(inst *serverInstance, snap ServerSnapshotDTO, format string) error {
+	var memory map[string]interface{}
+	switch format {
+	case MemoryFormatBinary:
+		if len(snap.MemoryBinary) == 0 {
+			return nil
+		}
+		decoded, err := datastore.DecodeSnapshotBinary(snap.MemoryBinary)
+		if err != nil {
+			return fmt.Errorf("memory of %s: %w", snap.ProtocolType, err)
+		}
+		memory = decoded
+	case "", MemoryFormatJSON:
+		// JSON から読み込んだ値は []interface{} になるため型付きスライスに揃える
+		memory = make(map[string]interface{}, len(snap.Memory))
+		for areaID, v := range snap.Memory {
+			if bits, ok := datastore.SnapshotBits(v); ok {
+				memory[areaID] = bits
+			} else if words, ok := datastore.SnapshotWords(v); ok {
+				memory[areaID] = words
+			} else {
+				return fmt.Errorf("memory of %s: invalid values for %s", snap.ProtocolType, areaID)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown memory format: %s", format)
+	}
+	if len(memory) == 0 {
+		return nil
+	}
+	return inst.dataStore.Restore(memory)
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// projectRoundTrip はプロジェクトをファイルと同じ JSON 形式に変換して読み戻す
+func projectRoundTrip(t *testing.T, project *ProjectDataDTO) (*ProjectDataDTO, int) {
+	t.Helper()
+	data, err := json.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ProjectDataDTO
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return &decoded, len(data)
+}
+
+func TestPLCService_ExportProject_BinaryMemory(t *testing.T) {
+	src := newTestService(t)
+	if err := src.WriteWord("modbus-tcp", "holdingRegisters", 10, 1234); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.WriteBit("modbus-tcp", "coils", 9000, true); err != nil {
+		t.Fatal(err)
+	}
+
+	project := src.ExportProject()
+	if project.MemoryFormat != MemoryFormatBinary {
+		t.Fatalf("MemoryFormat = %q, want binary", project.MemoryFormat)
+	}
+	if len(project.Servers) != 1 || len(project.Servers[0].MemoryBinary) == 0 || project.Servers[0].Memory != nil {
+		t.Fatalf("unexpected server memory: %+v", project.Servers)
+	}
+
+	decoded, binarySize := projectRoundTrip(t, project)
+
+	// 同じ内容を JSON 形式で保存した場合と比べて小さいこと
+	jsonProject := *project
+	jsonProject.Servers = []ServerSnapshotDTO{project.Servers[0]}
+	jsonProject.Servers[0].MemoryBinary = nil
+	jsonProject.Servers[0].Memory = src.inspectMemory(t)
+	_, jsonSize := projectRoundTrip(t, &jsonProject)
+	if binarySize*10 > jsonSize {
+		t.Errorf("binary project is %d bytes, JSON project is %d bytes", binarySize, jsonSize)
+	}
+
+	dst := newTestService(t)
+	if err := dst.ImportProject(decoded); err != nil {
+		t.Fatalf("ImportProject: %v", err)
+	}
+	if v, _ := dst.ReadWords("modbus-tcp", "holdingRegisters", 10, 1); v[0] != 1234 {
+		t.Errorf("holding register 10 = %d, want 1234", v[0])
+	}
+	if v, _ := dst.ReadBits("modbus-tcp", "coils", 9000, 1); !v[0] {
+		t.Error("coil 9000 not restored")
+	}
+}
+
+// inspectMemory は modbus-tcp の現在のメモリ内容を返す
+func (s *PLCService) inspectMemory(t *testing.T) map[string]interface{} {
+	t.Helper()
+	snap, err := s.GetMemorySnapshot("modbus-tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snap
+}
+
+func TestAttachServerMemory_SmallUsesJSON(t *testing.T) {
+	project := &ProjectDataDTO{Servers: []ServerSnapshotDTO{{ProtocolType: "modbus-tcp"}}}
+	attachServerMemory(project, []map[string]interface{}{
+		{"holdingRegisters": []uint16{1, 2, 3}, "coils": []bool{true}},
+	})
+	if project.MemoryFormat != MemoryFormatJSON {
+		t.Errorf("MemoryFormat = %q, want json", project.MemoryFormat)
+	}
+	if project.Servers[0].Memory == nil || project.Servers[0].MemoryBinary != nil {
+		t.Errorf("unexpected server memory: %+v", project.Servers[0])
+	}
+
+	// JSON 形式のプロジェクトも読み込める
+	decoded, _ := projectRoundTrip(t, project)
+	svc := newTestService(t)
+	if err := svc.ImportProjectWithOptions(decoded, ImportOptions{SkipChecksum: true}); err != nil {
+		t.Fatalf("ImportProject: %v", err)
+	}
+	if v, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 3); v[2] != 3 {
+		t.Errorf("holding registers = %v, want [1 2 3]", v)
+	}
+}
+
+func TestPLCService_ImportProject_InvalidBinaryMemory(t *testing.T) {
+	svc := newTestService(t)
+	project := &ProjectDataDTO{
+		Servers:      []ServerSnapshotDTO{{ProtocolType: "modbus-tcp", Variant: "tcp", MemoryBinary: []byte("garbage")}},
+		MemoryFormat: MemoryFormatBinary,
+	}
+	if err := svc.ImportProject(project); err == nil {
+		t.Error("expected error for invalid binary memory")
+	}
+}
@@ -0,0 +1,208 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// SaveRecipe は全サーバーの現在のメモリ内容をレシピとして保存する（同名のレシピは上書き）。
+// 0 以外の値を持つアドレスのみを記録する。
+func (s *PLCService) SaveRecipe(name string) error {
+	if name == "" {
+		return fmt.Errorf("recipe name is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipe := &RecipeDTO{Name: name, Values: []RecipeValueDTO{}}
+	for _, inst := range s.sortedServerInstances() {
+		for _, area := range inst.dataStore.GetAreas() {
+			values, err := readAreaRange(inst.dataStore, area, 0, int(area.Size))
+			if err != nil {
+				return fmt.Errorf("%s/%s: %w", inst.protocolType, area.ID, err)
+			}
+			recipe.Values = append(recipe.Values, nonZeroRecipeValues(string(inst.protocolType), area.ID, values)...)
+		}
+	}
+
+	s.recipes[name] = recipe
+	s.saveRecipesInternal()
+	return nil
+}
+
+// nonZeroRecipeValues は readAreaRange の結果から 0 以外の値を取り出す
+func nonZeroRecipeValues(protocolType, area string, values interface{}) []RecipeValueDTO {
+	var result []RecipeValueDTO
+	switch vals := values.(type) {
+	case []bool:
+		for addr, v := range vals {
+			if v {
+				result = append(result, RecipeValueDTO{ProtocolType: protocolType, MemoryArea: area, Address: addr, Value: 1})
+			}
+		}
+	case []int:
+		for addr, v := range vals {
+			if v != 0 {
+				result = append(result, RecipeValueDTO{ProtocolType: protocolType, MemoryArea: area, Address: addr, Value: v})
+			}
+		}
+	}
+	return result
+}
+
+// LoadRecipe はレシピの値をメモリに書き込む。
+// 書き込み前に全ての値の書き込み先を検証し、1つでも不正な場合やロック中のエリアを含む場合は何も書き込まない。
+func (s *PLCService) LoadRecipe(name string) error {
+	return s.loadRecipe(name, true)
+}
+
+// loadRecipe は LoadRecipe の本体。スクリプトからの呼び出しは checkLock=false でエリアのロックを無視する。
+func (s *PLCService) loadRecipe(name string, checkLock bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipe, ok := s.recipes[name]
+	if !ok {
+		return fmt.Errorf("recipe not found: %s", name)
+	}
+
+	type target struct {
+		inst *serverInstance
+		area protocol.MemoryArea
+	}
+	targets := make([]target, len(recipe.Values))
+	for i, v := range recipe.Values {
+		inst, err := s.getServerInstance(v.ProtocolType)
+		if err != nil {
+			return err
+		}
+		area, err := findMemoryArea(inst.dataStore, v.MemoryArea)
+		if err != nil {
+			return err
+		}
+		if v.Address < 0 || uint32(v.Address) >= area.Size {
+			return fmt.Errorf("address %d out of range for area %s (size %d)", v.Address, area.ID, area.Size)
+		}
+		if checkLock {
+			if err := checkAreaUnlocked(inst, v.MemoryArea); err != nil {
+				return err
+			}
+		}
+		targets[i] = target{inst: inst, area: area}
+	}
+
+	for i, v := range recipe.Values {
+		t := targets[i]
+		addr := uint32(v.Address)
+		if t.area.IsBit {
+			if err := t.inst.dataStore.WriteBit(v.MemoryArea, addr, v.Value != 0); err != nil {
+				return err
+			}
+		} else {
+			if err := t.inst.dataStore.WriteWord(v.MemoryArea, addr, uint16(v.Value)); err != nil {
+				return err
+			}
+		}
+		// リモートプラグイン DataStore の場合は自分で変数を同期する（WriteWord と同様）
+		if t.inst.changeListener != nil {
+			if t.area.IsBit {
+				go t.inst.changeListener.SyncHostBitWriteToVariable(v.MemoryArea, addr)
+			} else {
+				go t.inst.changeListener.SyncHostWordWriteToVariable(v.MemoryArea, addr)
+			}
+			s.notifyStoreWrite(t.inst.protocolType, v.MemoryArea, addr, 1)
+		}
+	}
+	return nil
+}
+
+// ListRecipes は保存済みのレシピを名前順に返す
+func (s *PLCService) ListRecipes() []*RecipeDTO {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sortedRecipes()
+}
+
+// DeleteRecipe はレシピを削除する
+func (s *PLCService) DeleteRecipe(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[name]; !ok {
+		return fmt.Errorf("recipe not found: %s", name)
+	}
+	delete(s.recipes, name)
+	s.saveRecipesInternal()
+	return nil
+}
+
+// sortedRecipes はレシピを名前順に返す（ロック取得済みであること）
+func (s *PLCService) sortedRecipes() []*RecipeDTO {
+	result := make([]*RecipeDTO, 0, len(s.recipes))
+	for _, r := range s.recipes {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// setRecipes はレシピを全て置き換える（ロック取得済みであること）
+func (s *PLCService) setRecipes(recipes []*RecipeDTO) {
+	s.recipes = make(map[string]*RecipeDTO, len(recipes))
+	for _, r := range recipes {
+		if r != nil && r.Name != "" {
+			s.recipes[r.Name] = r
+		}
+	}
+}
+
+// saveRecipesInternal はレシピを設定ディレクトリの recipes.json に保存する（ロック取得済みであること）。
+// インポートしたプロジェクトのレシピはプロジェクトとして保存するため書き込まない。
+// 保存に失敗してもメモリ上のレシピは有効なため、警告ログのみ出力する。
+func (s *PLCService) saveRecipesInternal() {
+	if s.recipesInProject {
+		return
+	}
+	path, err := s.configFilePath("recipes.json")
+	if err == nil {
+		var data []byte
+		data, err = json.MarshalIndent(s.sortedRecipes(), "", "  ")
+		if err == nil {
+			err = writeFileAtomic(path, data)
+		}
+	}
+	if err != nil {
+		s.logger.Warn("failed to save recipes", "error", err)
+	}
+}
+
+// LoadRecipes はレシピをファイルから読み込む
+func (s *PLCService) LoadRecipes() error {
+	path, err := s.configFilePath("recipes.json")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // ファイルがなければ無視
+		}
+		return err
+	}
+
+	var recipes []*RecipeDTO
+	if err := json.Unmarshal(data, &recipes); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setRecipes(recipes)
+	return nil
+}
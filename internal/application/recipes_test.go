@@ -0,0 +1,144 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPLCService_Recipe_SaveMutateLoad(t *testing.T) {
+	svc := newTestService(t)
+
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 100)
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 5000, 0xBEEF)
+	_ = svc.WriteBit("modbus-tcp", "coils", 3, true)
+	if err := svc.SaveRecipe("productA"); err != nil {
+		t.Fatalf("SaveRecipe: %v", err)
+	}
+
+	// 値を変更してからレシピを読み込むと保存時の値に戻る
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 5)
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 5000, 1)
+	_ = svc.WriteBit("modbus-tcp", "coils", 3, false)
+	if err := svc.LoadRecipe("productA"); err != nil {
+		t.Fatalf("LoadRecipe: %v", err)
+	}
+
+	words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1)
+	if words[0] != 100 {
+		t.Errorf("holdingRegisters[0] = %d, want 100", words[0])
+	}
+	words, _ = svc.ReadWords("modbus-tcp", "holdingRegisters", 5000, 1)
+	if words[0] != 0xBEEF {
+		t.Errorf("holdingRegisters[5000] = %#x, want 0xBEEF", words[0])
+	}
+	bits, _ := svc.ReadBits("modbus-tcp", "coils", 3, 1)
+	if !bits[0] {
+		t.Error("coils[3] should be restored to true")
+	}
+
+	recipes := svc.ListRecipes()
+	if len(recipes) != 1 || recipes[0].Name != "productA" || len(recipes[0].Values) != 3 {
+		t.Fatalf("unexpected recipes: %+v", recipes)
+	}
+}
+
+func TestPLCService_Recipe_ScriptLoad(t *testing.T) {
+	svc := newTestService(t)
+
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 10, 42)
+	if err := svc.SaveRecipe("r1"); err != nil {
+		t.Fatal(err)
+	}
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 10, 0)
+
+	if _, err := svc.RunScriptOnce(`plc.loadRecipe("r1")`); err != nil {
+		t.Fatalf("plc.loadRecipe failed: %v", err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 10, 1); words[0] != 42 {
+		t.Errorf("holdingRegisters[10] = %d, want 42", words[0])
+	}
+	if _, err := svc.RunScriptOnce(`plc.loadRecipe("missing")`); err == nil {
+		t.Error("expected error for missing recipe")
+	}
+}
+
+func TestPLCService_Recipe_DeleteAndPersist(t *testing.T) {
+	configDir := t.TempDir()
+	svc := newTestServiceWithConfigDir(t, configDir)
+
+	_ = svc.WriteWord("modbus-tcp", "holdingRegisters", 1, 7)
+	if err := svc.SaveRecipe("keep"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SaveRecipe("drop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteRecipe("drop"); err != nil {
+		t.Fatalf("DeleteRecipe: %v", err)
+	}
+	if err := svc.DeleteRecipe("drop"); err == nil {
+		t.Error("expected error deleting a missing recipe")
+	}
+
+	path := filepath.Join(configDir, "recipes.json")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("recipes file not written: %v", err)
+	}
+
+	// 新しいサービスは設定ディレクトリからレシピを読み込む
+	reloaded := newTestServiceWithConfigDir(t, configDir)
+	recipes := reloaded.ListRecipes()
+	if len(recipes) != 1 || recipes[0].Name != "keep" {
+		t.Fatalf("reloaded recipes = %+v", recipes)
+	}
+
+	// プロジェクトのエクスポート/インポートにも含まれる
+	project := svc.ExportProject()
+	if len(project.Recipes) != 1 {
+		t.Fatalf("exported recipes = %d, want 1", len(project.Recipes))
+	}
+	other := newTestService(t)
+	if err := other.ImportProject(project); err != nil {
+		t.Fatalf("ImportProject: %v", err)
+	}
+	if len(other.ListRecipes()) != 1 {
+		t.Error("recipes not restored from project")
+	}
+}
+
+func TestPLCService_Recipe_ImportKeepsRecipesInProject(t *testing.T) {
+	configDir := t.TempDir()
+	svc := newTestServiceWithConfigDir(t, configDir)
+	if err := svc.SaveRecipe("global"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(configDir, "recipes.json")
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project := &ProjectDataDTO{Recipes: []*RecipeDTO{{Name: "fromProject", Values: []RecipeValueDTO{}}}}
+	if err := svc.ImportProjectWithOptions(project, ImportOptions{SkipChecksum: true}); err != nil {
+		t.Fatalf("ImportProject: %v", err)
+	}
+	if recipes := svc.ListRecipes(); len(recipes) != 1 || recipes[0].Name != "fromProject" {
+		t.Fatalf("recipes after import = %+v", recipes)
+	}
+
+	// プロジェクトのレシピへの変更はプロジェクトに保存され、recipes.json は変わらない
+	if err := svc.SaveRecipe("added"); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("recipes.json was overwritten by the imported project:\n%s", after)
+	}
+	if got := len(svc.ExportProject().Recipes); got != 2 {
+		t.Errorf("exported recipes = %d, want 2", got)
+	}
+}
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"fmt"
+
+	"modbus_simulator/internal/domain/datastore"
+)
+
+// SetDefaultByteOrder はスクリプトの readFloat32/writeFloat32 でバイト順を省略した場合の順序を設定する。
+// "ABCD"（ビッグエンディアン）、"CDAB"、"BADC"、"DCBA" のいずれか。
+func (s *PLCService) SetDefaultByteOrder(order string) error {
+	parsed, err := datastore.ParseByteOrder(order)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.defaultByteOrder = parsed
+	s.mu.Unlock()
+	return nil
+}
+
+// GetDefaultByteOrder はスクリプトの既定のバイト順を返す
+func (s *PLCService) GetDefaultByteOrder() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return string(s.defaultByteOrder)
+}
+
+// scriptMemoryAccessor はスクリプトエンジンに PLCService のメモリ操作を提供する。
+// プロトコル未指定の場合は登録順で最初のサーバーを対象とする。
+type scriptMemoryAccessor struct {
+	service *PLCService
+}
+
+func (a *scriptMemoryAccessor) resolveProtocol(protocolType string) (string, error) {
+	if protocolType != "" {
+		return protocolType, nil
+	}
+	a.service.mu.RLock()
+	defer a.service.mu.RUnlock()
+	instances := a.service.sortedServerInstances()
+	if len(instances) == 0 {
+		return "", fmt.Errorf("no server instance")
+	}
+	return string(instances[0].protocolType), nil
+}
+
+func (a *scriptMemoryAccessor) FillArea(protocolType, area string, value int) error {
+	pt, err := a.resolveProtocol(protocolType)
+	if err != nil {
+		return err
+	}
+	return a.service.fillAreaPattern(pt, area, []int{value}, false)
+}
+
+func (a *scriptMemoryAccessor) ReadBit(protocolType, area string, address int) (bool, error) {
+	pt, err := a.resolveProtocol(protocolType)
+	if err != nil {
+		return false, err
+	}
+	values, err := a.service.ReadBits(pt, area, address, 1)
+	if err != nil {
+		return false, err
+	}
+	return values[0], nil
+}
+
+func (a *scriptMemoryAccessor) ReadHex(protocolType, area string, start, count int) (string, error) {
+	pt, err := a.resolveProtocol(protocolType)
+	if err != nil {
+		return "", err
+	}
+	return a.service.ExportRangeHex(pt, area, start, count)
+}
+
+func (a *scriptMemoryAccessor) WriteHex(protocolType, area string, start int, hex string) error {
+	pt, err := a.resolveProtocol(protocolType)
+	if err != nil {
+		return err
+	}
+	return a.service.importRangeHex(pt, area, start, hex, false)
+}
+
+func (a *scriptMemoryAccessor) LoadRecipe(name string) error {
+	return a.service.loadRecipe(name, false)
+}
+
+func (a *scriptMemoryAccessor) ReadWords(protocolType, area string, address, count int) ([]uint16, error) {
+	pt, err := a.resolveProtocol(protocolType)
+	if err != nil {
+		return nil, err
+	}
+	values, err := a.service.ReadWords(pt, area, address, count)
+	if err != nil {
+		return nil, err
+	}
+	words := make([]uint16, len(values))
+	for i, v := range values {
+		words[i] = uint16(v)
+	}
+	return words, nil
+}
+
+func (a *scriptMemoryAccessor) WriteWords(protocolType, area string, address int, values []uint16) error {
+	pt, err := a.resolveProtocol(protocolType)
+	if err != nil {
+		return err
+	}
+	return a.service.writeWordRange(pt, area, address, values, false)
+}
+
+func (a *scriptMemoryAccessor) EnqueueFIFO(protocolType string, pointer, value int) error {
+	pt, err := a.resolveProtocol(protocolType)
+	if err != nil {
+		return err
+	}
+	return a.service.EnqueueFIFO(pt, pointer, value)
+}
+
+func (a *scriptMemoryAccessor) DefaultByteOrder() datastore.ByteOrder {
+	a.service.mu.RLock()
+	defer a.service.mu.RUnlock()
+	return a.service.defaultByteOrder
+}
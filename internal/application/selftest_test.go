@@ -0,0 +1,100 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"modbus_simulator/internal/domain/protocol"
+	"modbus_simulator/internal/testutil"
+)
+
+func TestPLCService_SelfTest_Healthy(t *testing.T) {
+	svc := newTestService(t)
+	err := svc.UpdateServerConfig(&ServerConfigDTO{
+		ProtocolType: "modbus-tcp", Variant: "tcp", Settings: map[string]interface{}{"tcpPort": 1502},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 9998, 1234); err != nil {
+		t.Fatal(err)
+	}
+
+	report := svc.SelfTest()
+	if !report.Passed {
+		t.Fatalf("self-test failed: %+v", report.Checks)
+	}
+	names := make(map[string]SelfTestCheckDTO)
+	for _, c := range report.Checks {
+		names[c.Name] = c
+	}
+	for _, name := range []string{"script", "datastore:modbus-tcp", "server:modbus-tcp"} {
+		c, ok := names[name]
+		if !ok {
+			t.Errorf("missing check %q in %+v", name, report.Checks)
+			continue
+		}
+		if c.Skipped {
+			t.Errorf("check %q was skipped: %s", name, c.Message)
+		}
+	}
+
+	// メモリと設定はそのまま
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 9998, 1); words[0] != 1234 {
+		t.Errorf("memory was modified: %v", words)
+	}
+	inst := svc.servers["modbus-tcp"]
+	if got := settingsTCPPort(inst.factory.ConfigToMap(inst.server.Config())); got != 1502 {
+		t.Errorf("server port after self-test = %d, want 1502", got)
+	}
+	if got := svc.GetServerStatus("modbus-tcp"); got != "Stopped" {
+		t.Errorf("server status = %s, want Stopped", got)
+	}
+}
+
+func TestPLCService_SelfTest_ReportsFailure(t *testing.T) {
+	// 自己診断は一時的なサーバーで起動を確認するので、ファクトリーが作る全サーバーを失敗させる
+	svc := newTestServiceWithServer(t, func(fs *fakeServer) protocol.ProtocolServer {
+		return &failingServer{fakeServer: fs, startErr: errors.New("bind failed")}
+	})
+	setTestTCPPort(t, svc, 1502)
+
+	report := svc.SelfTest()
+	if report.Passed {
+		t.Fatal("expected self-test to fail")
+	}
+	for _, c := range report.Checks {
+		if c.Name == "server:modbus-tcp" && c.Passed {
+			t.Errorf("server check passed despite start error: %+v", c)
+		}
+		if c.Name == "script" && !c.Passed {
+			t.Errorf("script check failed: %+v", c)
+		}
+	}
+}
+
+func TestPLCService_SelfTest_LeavesRunningServerAlone(t *testing.T) {
+	svc := newTestService(t)
+	setTestTCPPort(t, svc, testutil.FreeTCPPort(t))
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	live := svc.servers["modbus-tcp"].server
+	liveConfig := live.Config()
+
+	report := svc.SelfTest()
+	if !report.Passed {
+		t.Fatalf("self-test failed: %+v", report.Checks)
+	}
+	for _, c := range report.Checks {
+		if c.Name == "server:modbus-tcp" && c.Skipped {
+			t.Errorf("server check skipped while running: %s", c.Message)
+		}
+	}
+	if svc.servers["modbus-tcp"].server != live || live.Config() != liveConfig {
+		t.Error("self-test replaced or reconfigured the running server")
+	}
+	if got := svc.GetServerStatus("modbus-tcp"); got != "Running" {
+		t.Errorf("server status = %s, want Running", got)
+	}
+}
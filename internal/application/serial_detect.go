@@ -0,0 +1,114 @@
+package application
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// シリアルフレームの判定結果（プラグインの rtu.DetectMode と同じ値）
+const (
+	serialModeASCII   = "ascii"
+	serialModeRTU     = "rtu"
+	serialModeUnknown = "unknown"
+)
+
+// detectSerialBaudRate は DetectSerialMode がポートを開くときの通信速度
+const detectSerialBaudRate = 9600
+
+// detectSerialSilence はこの時間受信がなければ RTU フレームの終わりとみなす
+const detectSerialSilence = 50 * time.Millisecond
+
+// openDetectSerialPort はシリアルポートを開く（テストで差し替え可能）
+var openDetectSerialPort = serial.Open
+
+// DetectSerialMode は port を 9600bps・8N1 で開いて1フレーム受信し、RTU と ASCII のどちらで通信しているかを
+// "rtu" / "ascii" / "unknown" で返す。サーバーは起動せず、判定後すぐにポートを閉じる。
+// timeoutMs 以内に何も受信しなかった場合はエラーを返す。
+func (s *PLCService) DetectSerialMode(port string, timeoutMs int) (string, error) {
+	if timeoutMs <= 0 {
+		return "", fmt.Errorf("invalid timeout: %d", timeoutMs)
+	}
+	p, err := openDetectSerialPort(port, &serial.Mode{
+		BaudRate: detectSerialBaudRate,
+		DataBits: 8,
+		Parity:   serial.NoParity,
+		StopBits: serial.OneStopBit,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer p.Close()
+
+	frame, err := readDetectFrame(p, time.Duration(timeoutMs)*time.Millisecond)
+	if err != nil {
+		return "", err
+	}
+	if len(frame) == 0 {
+		return "", fmt.Errorf("no data received on %s within %dms", port, timeoutMs)
+	}
+	mode := detectSerialFrameMode(frame)
+	s.logger.Info("serial mode detected", "port", port, "mode", mode, "frameLen", len(frame))
+	return mode, nil
+}
+
+// readDetectFrame は1フレーム分を受信する。
+// ':' で始まる場合は CR LF まで、それ以外は detectSerialSilence の無受信までを1フレームとする。
+func readDetectFrame(p serial.Port, timeout time.Duration) ([]byte, error) {
+	if err := p.SetReadTimeout(detectSerialSilence); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, 256)
+	var frame []byte
+	for time.Now().Before(deadline) && len(frame) < 256 {
+		n, err := p.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			frame = append(frame, buf[:n]...)
+			if frame[0] == ':' && bytes.HasSuffix(frame, []byte("\r\n")) {
+				break
+			}
+			continue
+		}
+		if len(frame) > 0 && frame[0] != ':' {
+			break
+		}
+	}
+	return frame, nil
+}
+
+// detectSerialFrameMode はプラグインの rtu.DetectMode と同じ判定を行う
+// （ホストからはプラグインの internal パッケージを参照できないため）
+func detectSerialFrameMode(sample []byte) string {
+	if len(sample) >= 3 && sample[0] == ':' && bytes.HasSuffix(sample, []byte("\r\n")) {
+		return serialModeASCII
+	}
+	if len(sample) >= 4 {
+		n := len(sample) - 2
+		if modbusCRC16(sample[:n]) == uint16(sample[n])|uint16(sample[n+1])<<8 {
+			return serialModeRTU
+		}
+	}
+	return serialModeUnknown
+}
+
+// modbusCRC16 は Modbus RTU の CRC-16（多項式 0xA001、初期値 0xFFFF）を計算する
+func modbusCRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// scriptedPort は chunks を1回の Read ごとに返し、尽きたら受信なしを返す serial.Port
+type scriptedPort struct {
+	chunks [][]byte
+	closed bool
+}
+
+func (p *scriptedPort) Read(b []byte) (int, error) {
+	if len(p.chunks) == 0 {
+		time.Sleep(time.Millisecond)
+		return 0, nil
+	}
+	n := copy(b, p.chunks[0])
+	p.chunks = p.chunks[1:]
+	return n, nil
+}
+
+func (p *scriptedPort) Close() error                       { p.closed = true; return nil }
+func (p *scriptedPort) Write(b []byte) (int, error)        { return len(b), nil }
+func (p *scriptedPort) SetMode(*serial.Mode) error         { return nil }
+func (p *scriptedPort) SetReadTimeout(time.Duration) error { return nil }
+func (p *scriptedPort) Drain() error                       { return nil }
+func (p *scriptedPort) ResetInputBuffer() error            { return nil }
+func (p *scriptedPort) ResetOutputBuffer() error           { return nil }
+func (p *scriptedPort) SetDTR(bool) error                  { return nil }
+func (p *scriptedPort) SetRTS(bool) error                  { return nil }
+func (p *scriptedPort) Break(time.Duration) error          { return nil }
+func (p *scriptedPort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{}, nil
+}
+
+func withDetectPort(t *testing.T, port *scriptedPort, openErr error) {
+	t.Helper()
+	orig := openDetectSerialPort
+	openDetectSerialPort = func(string, *serial.Mode) (serial.Port, error) {
+		if openErr != nil {
+			return nil, openErr
+		}
+		return port, nil
+	}
+	t.Cleanup(func() { openDetectSerialPort = orig })
+}
+
+func TestPLCService_DetectSerialMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks [][]byte
+		want   string
+	}{
+		// FC 03 アドレス 0 から 2 ワード（CRC C4 0B）
+		{"rtu", [][]byte{{0x01, 0x03, 0x00, 0x00}, {0x00, 0x02, 0xC4, 0x0B}}, serialModeRTU},
+		{"ascii split across reads", [][]byte{[]byte(":0103"), []byte("00000002FA\r\n")}, serialModeASCII},
+		{"garbage", [][]byte{{0x55, 0xAA, 0x55, 0xAA}}, serialModeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+			port := &scriptedPort{chunks: tt.chunks}
+			withDetectPort(t, port, nil)
+
+			got, err := svc.DetectSerialMode("COM9", 500)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectSerialMode = %q, want %q", got, tt.want)
+			}
+			if !port.closed {
+				t.Error("port was not closed")
+			}
+		})
+	}
+}
+
+func TestPLCService_DetectSerialMode_Errors(t *testing.T) {
+	svc := newTestService(t)
+
+	withDetectPort(t, &scriptedPort{}, nil)
+	if _, err := svc.DetectSerialMode("COM9", 30); err == nil {
+		t.Error("expected error when nothing is received")
+	}
+
+	openErr := errors.New("port busy")
+	withDetectPort(t, nil, openErr)
+	if _, err := svc.DetectSerialMode("COM9", 30); !errors.Is(err, openErr) {
+		t.Errorf("err = %v, want %v", err, openErr)
+	}
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"sort"
+
+	"go.bug.st/serial"
+	"go.bug.st/serial/enumerator"
+)
+
+// ListSerialPorts はシステムで利用可能なシリアルポートを名前順で返す。
+// USB 変換器の VID/PID・製品名などは取得できた場合のみ設定する。
+// 詳細の取得に失敗した場合はポート名のみの一覧を返し、それも失敗した場合は空の一覧を返す。
+func ListSerialPorts() []SerialPortInfoDTO {
+	result := []SerialPortInfoDTO{}
+	details, err := enumerator.GetDetailedPortsList()
+	if err == nil {
+		for _, d := range details {
+			result = append(result, SerialPortInfoDTO{
+				Name:         d.Name,
+				IsUSB:        d.IsUSB,
+				VID:          d.VID,
+				PID:          d.PID,
+				SerialNumber: d.SerialNumber,
+				Product:      d.Product,
+			})
+		}
+	} else {
+		names, err := serial.GetPortsList()
+		if err != nil {
+			return result
+		}
+		for _, name := range names {
+			result = append(result, SerialPortInfoDTO{Name: name})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
@@ -0,0 +1,19 @@
+package application
+
+import "testing"
+
+func TestListSerialPorts(t *testing.T) {
+	// 実行環境にシリアルポートがなくても nil ではなく空の一覧を返す
+	ports := ListSerialPorts()
+	if ports == nil {
+		t.Fatal("ListSerialPorts returned nil")
+	}
+	for i, p := range ports {
+		if p.Name == "" {
+			t.Errorf("port %d has empty name: %+v", i, p)
+		}
+		if i > 0 && ports[i-1].Name > p.Name {
+			t.Errorf("ports not sorted: %q before %q", ports[i-1].Name, p.Name)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// defaultServerStatusPollInterval は実行中のサーバーの状態を確認する既定の間隔
+const defaultServerStatusPollInterval = time.Second
+
+// serverStatusTracker はサーバーごとに最後に通知した状態を保持する。
+// 状態の読み取りから通知までをまとめて直列化し、通知の順序が入れ替わらないようにする。
+type serverStatusTracker struct {
+	mu       sync.Mutex
+	last     map[protocol.ProtocolType]protocol.ServerStatus // 未登録のサーバーは Stopped とみなす
+	watching bool                                            // 状態を監視するゴルーチンが動いているか
+	interval time.Duration                                   // 状態を確認する間隔（0 の場合は defaultServerStatusPollInterval）
+}
+
+// notifyServerStatusLocked は inst の現在の状態が前回の通知から変わっていれば server:status イベントを発行し、現在の状態を返す（s.mu のロック必須。読み取りロックでもよい）。
+// 開始・停止の操作のほか、実行中または Error のサーバーを監視するゴルーチンからも呼ぶ。
+func (s *PLCService) notifyServerStatusLocked(inst *serverInstance) protocol.ServerStatus {
+	t := &s.statusTracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status := protocol.StatusStopped
+	if inst.server != nil {
+		status = inst.server.Status()
+	}
+	if watchesStatus(status) && !t.watching {
+		t.watching = true
+		interval := t.interval
+		if interval <= 0 {
+			interval = defaultServerStatusPollInterval
+		}
+		go s.watchServerStatus(interval)
+	}
+	if t.last[inst.protocolType] == status {
+		return status
+	}
+	if t.last == nil {
+		t.last = make(map[protocol.ProtocolType]protocol.ServerStatus)
+	}
+	t.last[inst.protocolType] = status
+
+	event := ServerStatusEventDTO{
+		ProtocolType: string(inst.protocolType),
+		Status:       status.String(),
+	}
+	if status == protocol.StatusError {
+		if r, ok := inst.server.(protocol.ErrorReporter); ok {
+			if err := r.LastError(); err != nil {
+				event.Error = err.Error()
+			}
+		}
+	}
+	s.logger.Debug("server status changed", "protocol", inst.protocolType, "status", event.Status, "error", event.Error)
+	if s.appEmitter != nil {
+		s.appEmitter.EmitServerStatus(event)
+	}
+	return status
+}
+
+// watchServerStatus は実行中または Error のサーバーがなくなるまで interval ごとに各サーバーの状態を確認する。
+// シリアルポートの喪失などサーバー内部で Error になった場合や、自動再接続で Running に戻った場合も、
+// 操作を待たずに server:status イベントを発行する。
+func (s *PLCService) watchServerStatus(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !s.pollServerStatus() {
+			return
+		}
+	}
+}
+
+// pollServerStatus は各サーバーの状態の変化を通知し、実行中または Error のサーバーが残っているかを返す。
+// 残っていなければ監視を終える（次に Running または Error になったサーバーの通知で再び開始する）。
+func (s *PLCService) pollServerStatus() bool {
+	s.mu.RLock()
+	for _, inst := range s.servers {
+		s.notifyServerStatusLocked(inst)
+	}
+	s.mu.RUnlock()
+
+	// 通知済みの状態で判定し、並行した通知が Running にした場合は監視を続ける
+	t := &s.statusTracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, status := range t.last {
+		if watchesStatus(status) {
+			return true
+		}
+	}
+	t.watching = false
+	return false
+}
+
+// watchesStatus は status のサーバーを監視するかを返す。
+// Error のサーバーも autoReconnect などで Running に戻ることがあるため監視する。
+func watchesStatus(status protocol.ServerStatus) bool {
+	return status == protocol.StatusRunning || status == protocol.StatusError
+}
+
+// forgetServerStatus は削除したサーバーの通知済みの状態を破棄する
+func (s *PLCService) forgetServerStatus(pt protocol.ProtocolType) {
+	s.statusTracker.mu.Lock()
+	delete(s.statusTracker.last, pt)
+	s.statusTracker.mu.Unlock()
+}
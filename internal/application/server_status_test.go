@@ -0,0 +1,159 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+func TestPLCService_ServerStatusEvents_StartStop(t *testing.T) {
+	svc := newTestService(t)
+	emitter := &recordingAppEmitter{}
+	svc.SetAppStateEmitter(emitter)
+
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	// 状態が変わらない操作では通知しない
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	svc.GetServerStatus("modbus-tcp")
+	if err := svc.StopServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ServerStatusEventDTO{
+		{ProtocolType: "modbus-tcp", Status: "Running"},
+		{ProtocolType: "modbus-tcp", Status: "Stopped"},
+	}
+	got := emitter.statusEvents()
+	if len(got) != len(want) {
+		t.Fatalf("events = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPLCService_ServerStatusEvents_Error(t *testing.T) {
+	var srv *failingServer
+	svc := newTestServiceWithServer(t, func(fs *fakeServer) protocol.ProtocolServer {
+		srv = &failingServer{fakeServer: fs}
+		return srv
+	})
+	emitter := &recordingAppEmitter{}
+	svc.SetAppStateEmitter(emitter)
+	setStatusPollInterval(svc, 10*time.Millisecond)
+
+	// 起動に失敗した場合は原因とともに Error を通知する
+	svc.mu.Lock()
+	srv.startErr = errors.New("serial port not found")
+	svc.mu.Unlock()
+	if err := svc.StartServer("modbus-tcp"); err == nil {
+		t.Fatal("StartServer should fail")
+	}
+	got := emitter.statusEvents()
+	if len(got) != 1 || got[0].Status != "Error" || got[0].Error != "serial port not found" {
+		t.Fatalf("events after failed start = %+v", got)
+	}
+
+	// 起動し直すと Running、その後サーバー内部で Error になった場合は状態を監視するゴルーチンが通知する
+	svc.mu.Lock()
+	srv.startErr = nil
+	svc.mu.Unlock()
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	svc.mu.Lock()
+	srv.status = protocol.StatusError
+	svc.mu.Unlock()
+
+	got = waitForStatusEvents(t, emitter, 3)
+	if len(got) != 3 || got[1].Status != "Running" || got[2].Status != "Error" {
+		t.Errorf("events = %+v, want Error, Running, Error", got)
+	}
+
+	// 停止して Running・Error のサーバーがなくなると監視を終える
+	if err := svc.StopServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		svc.statusTracker.mu.Lock()
+		watching := svc.statusTracker.watching
+		svc.statusTracker.mu.Unlock()
+		if !watching {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("status watcher should stop when no server is running or in error")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 状態の取得では通知しない
+	svc.GetServerStatus("modbus-tcp")
+	svc.GetServerInstances()
+	if got := emitter.statusEvents(); len(got) != 4 {
+		t.Errorf("events after getters = %+v, want no new events", got)
+	}
+}
+
+func TestPLCService_ServerStatusEvents_RecoverFromError(t *testing.T) {
+	svc := newTestService(t)
+	emitter := &recordingAppEmitter{}
+	svc.SetAppStateEmitter(emitter)
+	setStatusPollInterval(svc, 10*time.Millisecond)
+	if err := svc.StartServer("modbus-tcp"); err != nil {
+		t.Fatal(err)
+	}
+	inst := svc.servers["modbus-tcp"]
+	setStatus := func(status protocol.ServerStatus) {
+		svc.mu.Lock()
+		inst.server.(*fakeServer).status = status
+		svc.mu.Unlock()
+	}
+
+	// ポートを失って Error になったあと、自動再接続で Running に戻った場合も監視を続けて通知する
+	setStatus(protocol.StatusError)
+	waitForStatusEvents(t, emitter, 2)
+	setStatus(protocol.StatusRunning)
+	got := waitForStatusEvents(t, emitter, 3)
+	want := []string{"Running", "Error", "Running"}
+	if len(got) != len(want) {
+		t.Fatalf("events = %+v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Status != want[i] {
+			t.Errorf("event[%d] = %+v, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+// setStatusPollInterval はサーバーの状態を確認する間隔を変更する
+func setStatusPollInterval(svc *PLCService, interval time.Duration) {
+	svc.statusTracker.mu.Lock()
+	svc.statusTracker.interval = interval
+	svc.statusTracker.mu.Unlock()
+}
+
+// waitForStatusEvents は server:status イベントが n 件以上になるまで待ち、記録したイベントを返す
+func waitForStatusEvents(t *testing.T, emitter *recordingAppEmitter, n int) []ServerStatusEventDTO {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got := emitter.statusEvents()
+		if len(got) >= n {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("events = %+v, want at least %d", got, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
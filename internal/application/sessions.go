@@ -0,0 +1,123 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// defaultConnectionTimeout は無通信のユニットを接続数から外すまでの既定の時間
+const defaultConnectionTimeout = 5 * time.Second
+
+// SetConnectionTimeout の設定範囲
+const (
+	minConnectionTimeout = 100 * time.Millisecond
+	maxConnectionTimeout = time.Hour
+)
+
+// SetConnectionTimeout は Modbus TCP などのセッション方式の接続数で、
+// 無通信のユニットを接続数から外すまでの時間をミリ秒で設定する。
+// プラグインのサーバーにはサーバー設定（connectionTimeout）として反映する。設定はプロジェクトに保存される。
+func (s *PLCService) SetConnectionTimeout(ms int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.setConnectionTimeoutLocked(ms)
+}
+
+// setConnectionTimeoutLocked は SetConnectionTimeout の本体（ロック取得済みであること）
+func (s *PLCService) setConnectionTimeoutLocked(ms int) error {
+	d := time.Duration(ms) * time.Millisecond
+	if d < minConnectionTimeout || d > maxConnectionTimeout {
+		return fmt.Errorf("connection timeout must be between %dms and %dms", minConnectionTimeout.Milliseconds(), maxConnectionTimeout.Milliseconds())
+	}
+	s.connectionTimeout = d
+	if s.sessionManager != nil {
+		s.sessionManager.SetTimeout(d)
+	}
+	for _, inst := range s.sortedServerInstances() {
+		if err := s.applyConnectionTimeoutLocked(inst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyConnectionTimeoutLocked はセッションのタイムアウトをサーバー設定（connectionTimeout）に反映する。
+// 設定に connectionTimeout を持たないサーバーと、既に同じ値のサーバーは何もしない（s.mu のロック必須）。
+func (s *PLCService) applyConnectionTimeoutLocked(inst *serverInstance) error {
+	current, ok := inst.factory.ConfigToMap(inst.config)["connectionTimeout"]
+	if !ok {
+		return nil
+	}
+	ms := int(s.sessionTimeout().Milliseconds())
+	if fmt.Sprint(current) == strconv.Itoa(ms) {
+		return nil
+	}
+	return s.updateServerSettingsLocked(inst, "connection timeout", map[string]interface{}{
+		"connectionTimeout": ms,
+	})
+}
+
+// GetConnectionTimeout はセッションのタイムアウトをミリ秒で返す
+func (s *PLCService) GetConnectionTimeout() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return int(s.sessionTimeout().Milliseconds())
+}
+
+// sessionTimeout は現在のセッションのタイムアウトを返す（ロック取得済みであること）
+func (s *PLCService) sessionTimeout() time.Duration {
+	if s.connectionTimeout == 0 {
+		return defaultConnectionTimeout
+	}
+	return s.connectionTimeout
+}
+
+// GetConnections は接続中のクライアント一覧を返す。
+// クライアント単位で追跡できるサーバーはアドレス付きで返し、それ以外は
+// セッションマネージャーが把握している UnitID ごとのセッションをアドレスなしで返す
+// （既にアドレス付きで返した UnitID は含めない）。
+func (s *PLCService) GetConnections() []ConnectionDTO {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]ConnectionDTO, 0)
+	covered := make(map[uint8]bool)
+	for _, inst := range s.sortedServerInstances() {
+		lister, ok := inst.server.(protocol.ClientLister)
+		if !ok {
+			continue
+		}
+		for _, c := range lister.GetClients() {
+			result = append(result, ConnectionDTO{
+				ProtocolType:  string(inst.protocolType),
+				RemoteAddress: c.RemoteAddr,
+				UnitID:        int(c.UnitID),
+				LastActivity:  c.LastActivity.UnixMilli(),
+			})
+			covered[c.UnitID] = true
+		}
+	}
+
+	if s.sessionManager == nil {
+		return result
+	}
+	sessions := s.sessionManager.Snapshot()
+	unitIDs := make([]int, 0, len(sessions))
+	for unitID := range sessions {
+		if !covered[unitID] {
+			unitIDs = append(unitIDs, int(unitID))
+		}
+	}
+	sort.Ints(unitIDs)
+	for _, unitID := range unitIDs {
+		result = append(result, ConnectionDTO{
+			UnitID:       unitID,
+			LastActivity: sessions[uint8(unitID)].UnixMilli(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+func TestPLCService_SetConnectionTimeout(t *testing.T) {
+	svc := newTestService(t)
+	if got := svc.GetConnectionTimeout(); got != 5000 {
+		t.Errorf("default connection timeout = %d, want 5000", got)
+	}
+	if err := svc.SetConnectionTimeout(10); err == nil {
+		t.Error("expected error for too short timeout")
+	}
+
+	svc.SetEventEmitter(nil)
+	t.Cleanup(svc.GetSessionManager().Stop)
+	svc.GetSessionManager().RecordActivityWithUnitID(1)
+	time.Sleep(150 * time.Millisecond)
+
+	if err := svc.SetConnectionTimeout(100); err != nil {
+		t.Fatal(err)
+	}
+	if len(svc.GetUnitActivity()) != 0 {
+		t.Error("idle unit still counted after shortening the timeout")
+	}
+	// サーバー設定にも反映され、後から追加したサーバーも同じタイムアウトになる
+	if got := svc.GetServerConfig("modbus-tcp").Settings["connectionTimeout"]; got != 100 {
+		t.Errorf("modbus-tcp connectionTimeout = %v, want 100", got)
+	}
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.GetServerConfig("modbus-rtu").Settings["connectionTimeout"]; got != 100 {
+		t.Errorf("modbus-rtu connectionTimeout = %v, want 100", got)
+	}
+
+	// プロジェクトに保存され、インポートで復元される
+	project := svc.ExportProject()
+	if project.ConnectionTimeoutMs != 100 {
+		t.Errorf("exported ConnectionTimeoutMs = %d, want 100", project.ConnectionTimeoutMs)
+	}
+	dst := newTestService(t)
+	if err := dst.ImportProject(project); err != nil {
+		t.Fatal(err)
+	}
+	if got := dst.GetConnectionTimeout(); got != 100 {
+		t.Errorf("imported connection timeout = %d, want 100", got)
+	}
+	if got := dst.GetServerConfig("modbus-tcp").Settings["connectionTimeout"]; got != 100 {
+		t.Errorf("imported modbus-tcp connectionTimeout = %v, want 100", got)
+	}
+}
+
+// clientListingServer は接続中のクライアント一覧を返すフェイクサーバー
+type clientListingServer struct {
+	*fakeServer
+	clients []protocol.ClientInfo
+}
+
+func (s *clientListingServer) GetClients() []protocol.ClientInfo {
+	return append([]protocol.ClientInfo(nil), s.clients...)
+}
+
+func TestPLCService_GetConnections(t *testing.T) {
+	var lister *clientListingServer
+	svc := newTestServiceWithServer(t, func(fs *fakeServer) protocol.ProtocolServer {
+		lister = &clientListingServer{fakeServer: fs}
+		return lister
+	})
+	if got := svc.GetConnections(); len(got) != 0 {
+		t.Errorf("GetConnections without clients = %v", got)
+	}
+
+	at := time.UnixMilli(1700000000000)
+	lister.clients = []protocol.ClientInfo{
+		{RemoteAddr: "192.168.0.10:50123", UnitID: 3, LastActivity: at},
+	}
+	svc.SetEventEmitter(nil)
+	t.Cleanup(svc.GetSessionManager().Stop)
+	// UnitID 3 はクライアント一覧と重複するためアドレスなしのエントリにはならない
+	svc.GetSessionManager().RecordActivityWithUnitID(3)
+	svc.GetSessionManager().RecordActivityWithUnitID(5)
+
+	got := svc.GetConnections()
+	if len(got) != 2 {
+		t.Fatalf("GetConnections = %+v, want 2 entries", got)
+	}
+	want := ConnectionDTO{ProtocolType: "modbus-tcp", RemoteAddress: "192.168.0.10:50123", UnitID: 3, LastActivity: 1700000000000}
+	if got[0] != want {
+		t.Errorf("connections[0] = %+v, want %+v", got[0], want)
+	}
+	if got[1].RemoteAddress != "" || got[1].ProtocolType != "" || got[1].UnitID != 5 || got[1].LastActivity == 0 {
+		t.Errorf("connections[1] = %+v, want session entry for unit 5", got[1])
+	}
+}
@@ -0,0 +1,255 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"modbus_simulator/internal/domain/protocol"
+	plugininfra "modbus_simulator/internal/infrastructure/plugin"
+)
+
+// sharedCopyChunk は共有メモリの同期で1回に読み書きする最大点数
+const sharedCopyChunk = 1000
+
+// sharedStore は共有メモリで同期するサーバーの DataStore。
+// 書き込み経路（s.mu を保持しているとは限らない）から参照するため serverInstance とは別に保持する。
+type sharedStore struct {
+	protocolType   protocol.ProtocolType
+	dataStore      protocol.DataStore
+	changeListener *plugininfra.RemoteVariableChangeListener
+}
+
+// SetSharedMemory は全サーバーで同じメモリ内容を共有するかを設定する。
+// 有効にすると、いずれかのサーバーへの書き込み（通信相手・UI・スクリプト）が同じ ID のエリアを持つ
+// 他のサーバーへ反映される。有効にした時点で最初に追加したサーバーのメモリ内容を他のサーバーへコピーする。
+// プロトコルごとにプラグインプロセスが分かれていても動作するよう、DataStore 間の値の同期として実装している。
+func (s *PLCService) SetSharedMemory(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shareMu.Lock()
+	wasEnabled := s.sharedMemory
+	s.sharedMemory = enabled
+	s.shareMu.Unlock()
+	s.refreshSharedStoresLocked()
+
+	if enabled && !wasEnabled {
+		if insts := s.orderedServerInstances(); len(insts) > 1 {
+			for _, inst := range insts[1:] {
+				s.copySharedMemory(insts[0], inst)
+			}
+		}
+	}
+}
+
+// IsSharedMemory は全サーバーでメモリ内容を共有しているかを返す
+func (s *PLCService) IsSharedMemory() bool {
+	s.shareMu.RLock()
+	defer s.shareMu.RUnlock()
+	return s.sharedMemory
+}
+
+// StartAllServers は全サーバーを追加順に起動する。
+// 起動に失敗したサーバーがあっても残りのサーバーの起動を続け、失敗をまとめて返す。
+func (s *PLCService) StartAllServers() error {
+	var errs []error
+	for _, pt := range s.orderedProtocolTypes() {
+		if err := s.StartServer(pt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pt, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StopAllServers は全サーバーを追加順に停止する。
+// 停止に失敗したサーバーがあっても残りのサーバーの停止を続け、失敗をまとめて返す。
+func (s *PLCService) StopAllServers() error {
+	var errs []error
+	for _, pt := range s.orderedProtocolTypes() {
+		if err := s.StopServer(pt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pt, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// orderedProtocolTypes は登録済みサーバーのプロトコルタイプを追加順で返す
+func (s *PLCService) orderedProtocolTypes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	insts := s.orderedServerInstances()
+	result := make([]string, len(insts))
+	for i, inst := range insts {
+		result[i] = string(inst.protocolType)
+	}
+	return result
+}
+
+// orderedServerInstances はサーバーインスタンスを追加順で返す（ロック済み前提）
+func (s *PLCService) orderedServerInstances() []*serverInstance {
+	insts := make([]*serverInstance, 0, len(s.servers))
+	for _, inst := range s.servers {
+		insts = append(insts, inst)
+	}
+	sort.Slice(insts, func(i, j int) bool {
+		return insts[i].addedOrder < insts[j].addedOrder
+	})
+	return insts
+}
+
+// refreshSharedStoresLocked はサーバーの追加・削除・再構築を共有メモリの同期先へ反映する（s.mu 保持前提）
+func (s *PLCService) refreshSharedStoresLocked() {
+	insts := s.orderedServerInstances()
+	stores := make([]sharedStore, len(insts))
+	for i, inst := range insts {
+		stores[i] = sharedStore{
+			protocolType:   inst.protocolType,
+			dataStore:      inst.dataStore,
+			changeListener: inst.changeListener,
+		}
+	}
+
+	s.shareMu.Lock()
+	s.sharedStores = stores
+	s.shareMu.Unlock()
+}
+
+// shareNewServerLocked は共有メモリが有効な場合、追加したサーバーに既存サーバーのメモリ内容をコピーする（s.mu 保持前提）
+func (s *PLCService) shareNewServerLocked(inst *serverInstance) {
+	s.refreshSharedStoresLocked()
+	if !s.IsSharedMemory() {
+		return
+	}
+	for _, src := range s.orderedServerInstances() {
+		if src != inst {
+			s.copySharedMemory(src, inst)
+			return
+		}
+	}
+}
+
+// copySharedMemory は src の全エリアの内容を dst の同じ ID のエリアへコピーする
+func (s *PLCService) copySharedMemory(src, dst *serverInstance) {
+	from := sharedStore{protocolType: src.protocolType, dataStore: src.dataStore}
+	to := sharedStore{protocolType: dst.protocolType, dataStore: dst.dataStore, changeListener: dst.changeListener}
+	for _, area := range src.dataStore.GetAreas() {
+		if err := s.copySharedRange(from, to, area.ID, 0, int(area.Size)); err != nil {
+			s.logger.Warn("shared memory not copied", "from", src.protocolType, "to", dst.protocolType, "area", area.ID, "error", err)
+		}
+	}
+}
+
+// mirrorSharedWrite は共有メモリが有効な場合、pt の DataStore への書き込み範囲を他のサーバーへ反映する。
+// 反映先の値が既に同じ場合は書き込まないため、反映先からの書き込み通知で往復し続けることはない。
+// DataStore の書き込み経路から呼ばれるため s.mu は取得しない。
+func (s *PLCService) mirrorSharedWrite(pt protocol.ProtocolType, area string, address uint32, count int) {
+	s.shareMu.RLock()
+	if !s.sharedMemory {
+		s.shareMu.RUnlock()
+		return
+	}
+	stores := append([]sharedStore(nil), s.sharedStores...)
+	s.shareMu.RUnlock()
+
+	var src *sharedStore
+	for i := range stores {
+		if stores[i].protocolType == pt {
+			src = &stores[i]
+			break
+		}
+	}
+	if src == nil {
+		return
+	}
+	for _, dst := range stores {
+		if dst.protocolType == pt {
+			continue
+		}
+		if err := s.copySharedRange(*src, dst, area, address, count); err != nil {
+			s.logger.Warn("shared memory write not mirrored", "from", pt, "to", dst.protocolType, "area", area, "error", err)
+		}
+	}
+}
+
+// copySharedRange は src の area[address:address+count] を dst の同じエリアへコピーする。
+// dst に同じ ID・種類のエリアがない場合は何もしない。範囲は両方のエリアサイズに収める。
+func (s *PLCService) copySharedRange(src, dst sharedStore, area string, address uint32, count int) error {
+	srcArea, ok := src.dataStore.GetArea(area)
+	if !ok {
+		return nil
+	}
+	dstArea, ok := dst.dataStore.GetArea(area)
+	if !ok || dstArea.IsBit != srcArea.IsBit {
+		return nil
+	}
+	if count < 1 {
+		count = 1
+	}
+	end := uint64(address) + uint64(count)
+	end = min(end, uint64(srcArea.Size), uint64(dstArea.Size))
+
+	for start := uint64(address); start < end; start += sharedCopyChunk {
+		n := uint16(min(end-start, sharedCopyChunk))
+		changed, err := copySharedChunk(src.dataStore, dst.dataStore, area, srcArea.IsBit, uint32(start), n)
+		if err != nil {
+			return err
+		}
+		if changed {
+			s.notifySharedHostWrite(dst, area, srcArea.IsBit, uint32(start), int(n))
+		}
+	}
+	return nil
+}
+
+// copySharedChunk は1チャンク分の値を比較し、異なる場合のみ書き込む。書き込んだかどうかを返す。
+func copySharedChunk(src, dst protocol.DataStore, area string, isBit bool, address uint32, count uint16) (bool, error) {
+	if isBit {
+		values, err := src.ReadBits(area, address, count)
+		if err != nil {
+			return false, err
+		}
+		current, err := dst.ReadBits(area, address, count)
+		if err != nil {
+			return false, err
+		}
+		if slices.Equal(values, current) {
+			return false, nil
+		}
+		return true, dst.WriteBits(area, address, values)
+	}
+
+	values, err := src.ReadWords(area, address, count)
+	if err != nil {
+		return false, err
+	}
+	current, err := dst.ReadWords(area, address, count)
+	if err != nil {
+		return false, err
+	}
+	if slices.Equal(values, current) {
+		return false, nil
+	}
+	return true, dst.WriteWords(area, address, values)
+}
+
+// notifySharedHostWrite はリモートプラグイン DataStore へ反映した書き込みを変数・トリガーへ通知する。
+// インプロセス DataStore は書き込みフックで通知済みのため何もしない。
+func (s *PLCService) notifySharedHostWrite(dst sharedStore, area string, isBit bool, address uint32, count int) {
+	listener := dst.changeListener
+	if listener == nil {
+		return
+	}
+	go func() {
+		for addr := address; addr < address+uint32(count); addr++ {
+			if isBit {
+				listener.SyncHostBitWriteToVariable(area, addr)
+			} else {
+				listener.SyncHostWordWriteToVariable(area, addr)
+			}
+		}
+	}()
+	s.notifyStoreWrite(dst.protocolType, area, address, count)
+}
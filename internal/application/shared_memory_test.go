@@ -0,0 +1,121 @@
+package application
+
+import (
+	"testing"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+func TestPLCService_SharedMemory_TCPAndRTU(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 11); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	svc.SetSharedMemory(true)
+
+	// 有効にした時点で最初に追加したサーバーの内容がコピーされる
+	if words, _ := svc.ReadWords("modbus-rtu", "holdingRegisters", 0, 1); words[0] != 11 {
+		t.Errorf("rtu holding register 0 after enabling = %d, want 11", words[0])
+	}
+
+	if err := svc.StartAllServers(); err != nil {
+		t.Fatalf("StartAllServers: %v", err)
+	}
+	for _, pt := range []string{"modbus-tcp", "modbus-rtu"} {
+		if got := svc.GetServerStatus(pt); got != protocol.StatusRunning.String() {
+			t.Errorf("%s status = %s, want Running", pt, got)
+		}
+	}
+
+	// 通信相手（RTU マスター）からの書き込みが TCP 側から読める
+	rtuStore := svc.servers["modbus-rtu"].server.(*fakeServer).store
+	if err := rtuStore.WriteWords("holdingRegisters", 10, []uint16{100, 200}); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "holdingRegisters", 10, 2); words[0] != 100 || words[1] != 200 {
+		t.Errorf("tcp holding registers 10-11 = %v, want [100 200]", words)
+	}
+
+	// TCP マスター・UI からの書き込みが RTU 側から読める
+	tcpStore := svc.servers["modbus-tcp"].server.(*fakeServer).store
+	if err := tcpStore.WriteBit("coils", 3, true); err != nil {
+		t.Fatal(err)
+	}
+	if bits, _ := svc.ReadBits("modbus-rtu", "coils", 3, 1); !bits[0] {
+		t.Error("rtu coil 3 should be ON")
+	}
+	if err := svc.WriteWord("modbus-rtu", "inputRegisters", 5, 55); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := svc.ReadWords("modbus-tcp", "inputRegisters", 5, 1); words[0] != 55 {
+		t.Errorf("tcp input register 5 = %d, want 55", words[0])
+	}
+
+	// 後から追加したサーバーにも共有中の内容がコピーされる
+	if err := svc.AddServer("modbus-ascii", "ascii"); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := svc.ReadWords("modbus-ascii", "holdingRegisters", 10, 1); words[0] != 100 {
+		t.Errorf("ascii holding register 10 = %d, want 100", words[0])
+	}
+
+	if err := svc.StopAllServers(); err != nil {
+		t.Fatalf("StopAllServers: %v", err)
+	}
+	for _, pt := range []string{"modbus-tcp", "modbus-rtu", "modbus-ascii"} {
+		if got := svc.GetServerStatus(pt); got != protocol.StatusStopped.String() {
+			t.Errorf("%s status = %s, want Stopped", pt, got)
+		}
+	}
+}
+
+func TestPLCService_SharedMemory_Disabled(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 0, 7); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := svc.ReadWords("modbus-rtu", "holdingRegisters", 0, 1); words[0] != 0 {
+		t.Errorf("rtu holding register 0 = %d, want 0 (not shared)", words[0])
+	}
+
+	svc.SetSharedMemory(true)
+	svc.SetSharedMemory(false)
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 1, 9); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := svc.ReadWords("modbus-rtu", "holdingRegisters", 1, 1); words[0] != 0 {
+		t.Errorf("rtu holding register 1 = %d after disabling, want 0", words[0])
+	}
+}
+
+func TestPLCService_SharedMemory_ProjectRoundTrip(t *testing.T) {
+	svc := newTestService(t)
+	if err := svc.AddServer("modbus-rtu", "rtu"); err != nil {
+		t.Fatal(err)
+	}
+	svc.SetSharedMemory(true)
+	project := svc.ExportProject()
+	if !project.SharedMemory {
+		t.Fatal("exported project should have sharedMemory")
+	}
+
+	other := newTestService(t)
+	if err := other.ImportProject(project); err != nil {
+		t.Fatal(err)
+	}
+	if !other.IsSharedMemory() {
+		t.Fatal("imported service should share memory")
+	}
+	if err := other.WriteWord("modbus-tcp", "holdingRegisters", 2, 42); err != nil {
+		t.Fatal(err)
+	}
+	if words, _ := other.ReadWords("modbus-rtu", "holdingRegisters", 2, 1); words[0] != 42 {
+		t.Errorf("rtu holding register 2 = %d, want 42", words[0])
+	}
+}
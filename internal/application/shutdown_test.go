@@ -0,0 +1,73 @@
+package application
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+// go test -race で、スクリプトの書き込み中にシャットダウンしても競合しないことを確認する
+func TestPLCService_ShutdownWhileScriptWrites(t *testing.T) {
+	svc := newTestService(t)
+	recorder := protocol.NewRecordingEmitter()
+	svc.SetEventEmitter(recorder)
+	svc.GetSessionManager().RecordActivityWithUnitID(1)
+
+	sc, err := svc.CreateScript("writer", `
+for (let i = 1; i <= 10; i++) {
+  plc.fill("holdingRegisters", i);
+}`, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.StartScript(sc.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// スクリプトが書き込みを始めるまで待つ
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		words, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if words[0] != 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("script did not write")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.Shutdown()
+		}()
+	}
+	wg.Wait()
+
+	// シャットダウン後はスクリプトが書き込まない
+	before, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(250 * time.Millisecond)
+	after, err := svc.ReadWords("modbus-tcp", "holdingRegisters", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before[0] != after[0] {
+		t.Errorf("value changed after Shutdown: %d -> %d", before[0], after[0])
+	}
+
+	// 接続数リセット（0）はシャットダウン中に発行しない
+	if got := recorder.Snapshot().ConnectionCounts; len(got) != 1 || got[0] != 1 {
+		t.Errorf("connection events = %v, want [1]", got)
+	}
+}
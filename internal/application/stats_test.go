@@ -0,0 +1,83 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/domain/protocol"
+)
+
+func TestPLCService_ScriptStats(t *testing.T) {
+	svc := newTestService(t)
+	svc.SetEventEmitter(nil)
+	t.Cleanup(svc.GetSessionManager().Stop)
+
+	result, err := svc.RunScriptOnce(`plc.stats().requests`)
+	if err != nil {
+		t.Fatalf("RunScriptOnce failed: %v", err)
+	}
+	if result != int64(0) {
+		t.Errorf("requests before traffic = %v (%T), want 0", result, result)
+	}
+
+	// サーバーが要求を1件処理し、例外応答を返した場合を再現
+	emitter := svc.GetEventEmitter()
+	emitter.EmitRx()
+	emitter.EmitTx()
+	emitter.(protocol.RequestRecorder).RecordRequest(0x03, true)
+	svc.GetSessionManager().RecordActivityWithUnitID(1)
+
+	result, err = svc.RunScriptOnce(`var s = plc.stats(); [s.requests, s.exceptions, s.lastFunctionCode, s.connections, s.lastRequestAt > 0]`)
+	if err != nil {
+		t.Fatalf("RunScriptOnce failed: %v", err)
+	}
+	got, ok := result.([]interface{})
+	if !ok || len(got) != 5 {
+		t.Fatalf("stats = %v (%T)", result, result)
+	}
+	want := []interface{}{int64(1), int64(1), int64(3), int64(1), true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stats[%d] = %v (%T), want %v", i, got[i], got[i], want[i])
+		}
+	}
+}
+
+// activityServer は自身で追跡した UnitID ごとの最終ポーリング時刻を返すサーバー（プラグインのサーバーを想定）
+type activityServer struct {
+	*fakeServer
+	activity map[uint8]time.Time
+}
+
+func (s *activityServer) UnitActivity() (map[uint8]time.Time, error) {
+	return s.activity, nil
+}
+
+func TestPLCService_GetUnitActivity(t *testing.T) {
+	now := time.Now()
+	svc := newTestServiceWithServer(t, func(fs *fakeServer) protocol.ProtocolServer {
+		return &activityServer{fakeServer: fs, activity: map[uint8]time.Time{1: now.Add(-time.Second), 17: now}}
+	})
+
+	// セッションマネージャーがなくてもサーバーが追跡した値を返す
+	activity := svc.GetUnitActivity()
+	if len(activity) != 2 || !activity[1].Equal(now.Add(-time.Second)) || !activity[17].Equal(now) {
+		t.Fatalf("GetUnitActivity = %v, want units 1 and 17 from the server", activity)
+	}
+
+	// ホストのセッションマネージャーの値と合わせ、同じ UnitID は新しい方を返す
+	svc.SetEventEmitter(nil)
+	t.Cleanup(svc.GetSessionManager().Stop)
+	svc.GetSessionManager().RecordActivityWithUnitID(1)
+	svc.GetSessionManager().RecordActivityWithUnitID(2)
+	activity = svc.GetUnitActivity()
+	if len(activity) != 3 || !activity[1].After(now.Add(-time.Second)) || !activity[17].Equal(now) {
+		t.Errorf("GetUnitActivity = %v, want units 1 (host), 2 (host) and 17 (server)", activity)
+	}
+
+	// 返り値を変更してもサーバーの値には影響しない
+	delete(activity, 17)
+	if _, ok := svc.GetUnitActivity()[17]; !ok {
+		t.Error("GetUnitActivity returned a shared map")
+	}
+}
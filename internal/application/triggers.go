@@ -0,0 +1,161 @@
+package application
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"modbus_simulator/internal/domain/protocol"
+
+	"github.com/google/uuid"
+)
+
+// scriptTrigger は指定アドレスへの書き込みでスクリプトを1回実行するトリガー
+type scriptTrigger struct {
+	TriggerDTO
+
+	// firing はスクリプト実行中を表す。実行中の書き込み（自身の監視アドレスへの
+	// 書き込みを含む）では再発火しないため、無限ループにならない。
+	firing atomic.Bool
+}
+
+// CreateTrigger は「area[address] への書き込み時に scriptId を1回実行する」トリガーを作成する。
+// 通信相手（マスター）からの書き込み、UI/スクリプトからのメモリ・変数書き込みで発火する。
+func (s *PLCService) CreateTrigger(protocolType, area string, address int, scriptId string) (*TriggerDTO, error) {
+	s.mu.RLock()
+	inst, err := s.getServerInstance(protocolType)
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, err
+	}
+	areaInfo, err := findMemoryArea(inst.dataStore, area)
+	_, scriptExists := s.scripts[scriptId]
+	s.mu.RUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if address < 0 || uint32(address) >= areaInfo.Size {
+		return nil, fmt.Errorf("address %d out of range for area %s (size %d)", address, area, areaInfo.Size)
+	}
+	if !scriptExists {
+		return nil, fmt.Errorf("script not found: %s", scriptId)
+	}
+
+	t := &scriptTrigger{TriggerDTO: TriggerDTO{
+		ID:           uuid.New().String(),
+		ProtocolType: protocolType,
+		MemoryArea:   area,
+		Address:      address,
+		ScriptID:     scriptId,
+	}}
+
+	s.triggerMu.Lock()
+	s.triggers = append(s.triggers, t)
+	s.triggerMu.Unlock()
+
+	dto := t.TriggerDTO
+	return &dto, nil
+}
+
+// DeleteTrigger はトリガーを削除する
+func (s *PLCService) DeleteTrigger(id string) error {
+	s.triggerMu.Lock()
+	defer s.triggerMu.Unlock()
+
+	for i, t := range s.triggers {
+		if t.ID == id {
+			s.triggers = append(s.triggers[:i], s.triggers[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("trigger not found: %s", id)
+}
+
+// removeTriggersForScript は指定スクリプトを実行するトリガーを削除する
+func (s *PLCService) removeTriggersForScript(scriptId string) {
+	s.triggerMu.Lock()
+	defer s.triggerMu.Unlock()
+
+	kept := s.triggers[:0]
+	for _, t := range s.triggers {
+		if t.ScriptID != scriptId {
+			kept = append(kept, t)
+		}
+	}
+	s.triggers = kept
+}
+
+// clearTriggers は全トリガーを削除する
+func (s *PLCService) clearTriggers() {
+	s.triggerMu.Lock()
+	s.triggers = nil
+	s.triggerMu.Unlock()
+}
+
+// GetTriggers はトリガー一覧を作成順で返す
+func (s *PLCService) GetTriggers() []TriggerDTO {
+	s.triggerMu.RLock()
+	defer s.triggerMu.RUnlock()
+
+	result := make([]TriggerDTO, len(s.triggers))
+	for i, t := range s.triggers {
+		result[i] = t.TriggerDTO
+	}
+	return result
+}
+
+// writeHook はサーバーインスタンスの DataStore に設定する書き込み通知コールバックを返す
+func (s *PLCService) writeHook(pt protocol.ProtocolType) func(area string, address uint32, count int) {
+	return func(area string, address uint32, count int) {
+		s.notifyStoreWrite(pt, area, address, count)
+	}
+}
+
+// notifyStoreWrite は DataStore への書き込みを共有メモリの同期とトリガーへ通知する。
+// DataStore の書き込み経路から呼ばれるため s.mu は取得しない。
+func (s *PLCService) notifyStoreWrite(pt protocol.ProtocolType, area string, address uint32, count int) {
+	s.mirrorSharedWrite(pt, area, address, count)
+	s.fireTriggers(pt, area, address, count)
+}
+
+// fireTriggers は書き込み範囲に含まれるトリガーのスクリプトを非同期に実行する。
+// DataStore の書き込み経路から呼ばれるため s.mu は取得しない。
+func (s *PLCService) fireTriggers(pt protocol.ProtocolType, area string, address uint32, count int) {
+	if count < 1 {
+		count = 1
+	}
+	end := uint64(address) + uint64(count)
+
+	s.triggerMu.RLock()
+	defer s.triggerMu.RUnlock()
+
+	for _, t := range s.triggers {
+		if t.ProtocolType != string(pt) || t.MemoryArea != area {
+			continue
+		}
+		if a := uint64(t.Address); a < uint64(address) || a >= end {
+			continue
+		}
+		if t.firing.CompareAndSwap(false, true) {
+			go s.runTrigger(t)
+		}
+	}
+}
+
+// runTrigger はトリガーのスクリプトを1回実行する
+func (s *PLCService) runTrigger(t *scriptTrigger) {
+	defer t.firing.Store(false)
+
+	s.mu.RLock()
+	sc, ok := s.scripts[t.ScriptID]
+	s.mu.RUnlock()
+	if !ok {
+		s.logger.Warn("trigger script not found", "triggerId", t.ID, "scriptId", t.ScriptID)
+		return
+	}
+
+	if err := s.scriptEngine.ExecuteScript(sc); err != nil {
+		s.logger.Warn("trigger script failed", "triggerId", t.ID, "script", sc.Name, "error", err)
+		go s.emitScriptsChanged()
+	}
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+// waitForVariable は変数の値が want になるまで待つ
+func waitForVariable(t *testing.T, svc *PLCService, name string, want int16) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		v, err := svc.variableStore.ReadValue(name)
+		if err == nil && v == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s did not reach %d (last %v)", name, want, v)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPLCService_CreateTrigger_FiresOnMasterWrite(t *testing.T) {
+	svc := newTestService(t)
+
+	if _, err := svc.CreateVariable("Fired", "INT", 0); err != nil {
+		t.Fatalf("CreateVariable failed: %v", err)
+	}
+	sc, _ := svc.CreateScript("onWrite", `plc.writeVariable("Fired", plc.readVariable("Fired") + 1)`, 1000)
+
+	if _, err := svc.CreateTrigger("modbus-tcp", "holdingRegisters", 10, sc.ID); err != nil {
+		t.Fatalf("CreateTrigger failed: %v", err)
+	}
+
+	// マスターからの書き込み（サーバーが DataStore に書き込むのと同じ経路）
+	inst := svc.servers["modbus-tcp"]
+	if err := inst.dataStore.WriteWord("holdingRegisters", 10, 5); err != nil {
+		t.Fatalf("WriteWord failed: %v", err)
+	}
+	waitForVariable(t, svc, "Fired", 1)
+
+	// 監視外のアドレスでは発火しない
+	_ = inst.dataStore.WriteWords("holdingRegisters", 11, []uint16{1, 2})
+	time.Sleep(100 * time.Millisecond)
+	waitForVariable(t, svc, "Fired", 1)
+}
+
+func TestPLCService_CreateTrigger_SelfWriteDoesNotLoop(t *testing.T) {
+	svc := newTestService(t)
+
+	_, _ = svc.CreateVariable("Fired", "INT", 0)
+	self, _ := svc.CreateVariable("Self", "INT", 0)
+	if err := svc.UpdateVariableMappings(self.ID, []ProtocolMappingDTO{
+		{ProtocolType: "modbus-tcp", MemoryArea: "holdingRegisters", Address: 10},
+	}); err != nil {
+		t.Fatalf("UpdateVariableMappings failed: %v", err)
+	}
+
+	// トリガー自身が監視アドレス（Self → HR10）に書き込む
+	sc, _ := svc.CreateScript("selfWrite", `
+		plc.writeVariable("Self", plc.readVariable("Self") + 1);
+		plc.writeVariable("Fired", plc.readVariable("Fired") + 1);
+	`, 1000)
+	if _, err := svc.CreateTrigger("modbus-tcp", "holdingRegisters", 10, sc.ID); err != nil {
+		t.Fatalf("CreateTrigger failed: %v", err)
+	}
+
+	if err := svc.WriteWord("modbus-tcp", "holdingRegisters", 10, 100); err != nil {
+		t.Fatalf("WriteWord failed: %v", err)
+	}
+	waitForVariable(t, svc, "Fired", 1)
+	time.Sleep(200 * time.Millisecond)
+	waitForVariable(t, svc, "Fired", 1)
+}
+
+func TestPLCService_CreateTrigger_Validation(t *testing.T) {
+	svc := newTestService(t)
+	sc, _ := svc.CreateScript("s", "1", 1000)
+
+	if _, err := svc.CreateTrigger("modbus-tcp", "unknownArea", 0, sc.ID); err == nil {
+		t.Error("expected error for unknown area")
+	}
+	if _, err := svc.CreateTrigger("modbus-tcp", "holdingRegisters", 99999, sc.ID); err == nil {
+		t.Error("expected error for out-of-range address")
+	}
+	if _, err := svc.CreateTrigger("modbus-tcp", "holdingRegisters", 0, "missing"); err == nil {
+		t.Error("expected error for unknown script")
+	}
+
+	tr, err := svc.CreateTrigger("modbus-tcp", "holdingRegisters", 0, sc.ID)
+	if err != nil {
+		t.Fatalf("CreateTrigger failed: %v", err)
+	}
+	if got := svc.GetTriggers(); len(got) != 1 || got[0].ID != tr.ID {
+		t.Errorf("GetTriggers = %+v", got)
+	}
+	if err := svc.DeleteTrigger(tr.ID); err != nil {
+		t.Fatalf("DeleteTrigger failed: %v", err)
+	}
+	if err := svc.DeleteTrigger(tr.ID); err == nil {
+		t.Error("expected error deleting twice")
+	}
+}
@@ -0,0 +1,21 @@
+package datastore
+
+// AddrRange はメモリエリア内のアドレス範囲 [Address, Address+Count)
+type AddrRange struct {
+	Address uint32
+	Count   uint32
+}
+
+// Validate は範囲が点数 size のエリアに収まるかを検査し、収まらなければ ErrAddressOutOfRange を返す。
+// Count が 0 の場合は Address が size 以下であればよい（末尾ちょうどの空範囲は有効）。
+func (r AddrRange) Validate(size uint32) error {
+	if uint64(r.Address)+uint64(r.Count) > uint64(size) {
+		return ErrAddressOutOfRange
+	}
+	return nil
+}
+
+// End は範囲の終端（最後のアドレスの次）を返す
+func (r AddrRange) End() uint64 {
+	return uint64(r.Address) + uint64(r.Count)
+}
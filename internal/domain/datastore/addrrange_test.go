@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAddrRange_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       AddrRange
+		size    uint32
+		wantErr bool
+	}{
+		{"whole area", AddrRange{Address: 0, Count: 10}, 10, false},
+		{"last address", AddrRange{Address: 9, Count: 1}, 10, false},
+		{"one past end", AddrRange{Address: 9, Count: 2}, 10, true},
+		{"start at end", AddrRange{Address: 10, Count: 1}, 10, true},
+		{"empty at end", AddrRange{Address: 10, Count: 0}, 10, false},
+		{"empty past end", AddrRange{Address: 11, Count: 0}, 10, true},
+		{"count exceeds size", AddrRange{Address: 0, Count: 11}, 10, true},
+		{"empty area", AddrRange{Address: 0, Count: 1}, 0, true},
+		{"address near max uint32", AddrRange{Address: math.MaxUint32, Count: 1}, 10, true},
+		{"sum overflows uint32", AddrRange{Address: math.MaxUint32 - 1, Count: 10}, math.MaxUint32, true},
+		{"count near max uint32", AddrRange{Address: 1, Count: math.MaxUint32}, 10, true},
+		{"end of max-size area", AddrRange{Address: math.MaxUint32 - 1, Count: 1}, math.MaxUint32, false},
+	}
+	for _, tt := range tests {
+		err := tt.r.Validate(tt.size)
+		if tt.wantErr && !errors.Is(err, ErrAddressOutOfRange) {
+			t.Errorf("%s: expected ErrAddressOutOfRange, got %v", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
+
+func TestAddrRange_End(t *testing.T) {
+	if got := (AddrRange{Address: 5, Count: 3}).End(); got != 8 {
+		t.Errorf("End = %d, want 8", got)
+	}
+}
@@ -0,0 +1,138 @@
+package datastore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// バイナリスナップショットの形式（gzip 圧縮後の内容）:
+//
+//	magic "PLCSNAP1"
+//	エリア数 (uint32)
+//	エリアごとに: ID 長 (uint16) + ID、種類 (0=ビット, 1=ワード)、点数 (uint32)、データ
+//
+// ビットは1バイトに8点（LSB から）、ワードはビッグエンディアンの2バイトで格納する。
+const binarySnapshotMagic = "PLCSNAP1"
+
+const (
+	binaryAreaBits  byte = 0
+	binaryAreaWords byte = 1
+)
+
+// EncodeSnapshotBinary はスナップショットを gzip 圧縮したバイナリ形式に変換する。
+// エリアは ID 順に格納するため、同じ内容からは常に同じバイト列が得られる。
+// []bool / []uint16 として解釈できない値のエリアは含めない。
+func EncodeSnapshotBinary(snapshot map[string]interface{}) []byte {
+	ids := make([]string, 0, len(snapshot))
+	areas := make(map[string]areaValues, len(snapshot))
+	for id, v := range snapshot {
+		vals, err := normalizeAreaValues(v)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+		areas[id] = vals
+	}
+	sort.Strings(ids)
+
+	var raw bytes.Buffer
+	raw.WriteString(binarySnapshotMagic)
+	binary.Write(&raw, binary.BigEndian, uint32(len(ids)))
+	for _, id := range ids {
+		vals := areas[id]
+		binary.Write(&raw, binary.BigEndian, uint16(len(id)))
+		raw.WriteString(id)
+		if vals.isBit {
+			raw.WriteByte(binaryAreaBits)
+			binary.Write(&raw, binary.BigEndian, uint32(len(vals.bits)))
+			packed := make([]byte, (len(vals.bits)+7)/8)
+			for i, b := range vals.bits {
+				if b {
+					packed[i/8] |= 1 << (i % 8)
+				}
+			}
+			raw.Write(packed)
+		} else {
+			raw.WriteByte(binaryAreaWords)
+			binary.Write(&raw, binary.BigEndian, uint32(len(vals.words)))
+			binary.Write(&raw, binary.BigEndian, vals.words)
+		}
+	}
+
+	var out bytes.Buffer
+	zw := gzip.NewWriter(&out)
+	zw.Write(raw.Bytes())
+	zw.Close()
+	return out.Bytes()
+}
+
+// DecodeSnapshotBinary は EncodeSnapshotBinary の出力をスナップショットに戻す。
+// ビットエリアは []bool、ワードエリアは []uint16 になる。
+func DecodeSnapshotBinary(data []byte) (map[string]interface{}, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+
+	r := bytes.NewReader(raw)
+	magic := make([]byte, len(binarySnapshotMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != binarySnapshotMagic {
+		return nil, fmt.Errorf("%w: not a binary snapshot", ErrInvalidData)
+	}
+	var count uint32
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+
+	result := make(map[string]interface{}, count)
+	for i := uint32(0); i < count; i++ {
+		var idLen uint16
+		if err := binary.Read(r, binary.BigEndian, &idLen); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+		id := make([]byte, idLen)
+		if _, err := io.ReadFull(r, id); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+		kind, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+		var n uint32
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+
+		switch kind {
+		case binaryAreaBits:
+			if (int64(n)+7)/8 > int64(r.Len()) {
+				return nil, fmt.Errorf("%w: area %s is truncated", ErrInvalidData, id)
+			}
+			packed := make([]byte, (n+7)/8)
+			io.ReadFull(r, packed)
+			bits := make([]bool, n)
+			for j := range bits {
+				bits[j] = packed[j/8]&(1<<(j%8)) != 0
+			}
+			result[string(id)] = bits
+		case binaryAreaWords:
+			if int64(n)*2 > int64(r.Len()) {
+				return nil, fmt.Errorf("%w: area %s is truncated", ErrInvalidData, id)
+			}
+			words := make([]uint16, n)
+			binary.Read(r, binary.BigEndian, words)
+			result[string(id)] = words
+		default:
+			return nil, fmt.Errorf("%w: unknown area type %d", ErrInvalidData, kind)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,50 @@
+package datastore
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotBinary_RoundTrip(t *testing.T) {
+	snap := map[string]interface{}{
+		"coils":            []bool{true, false, false, true, false, false, false, false, true},
+		"holdingRegisters": []uint16{0, 0x1234, 0xFFFF},
+		"empty":            []uint16{},
+		// JSON デコード後の値も受け付ける
+		"inputRegisters": []interface{}{float64(1), float64(2)},
+	}
+
+	data := EncodeSnapshotBinary(snap)
+	got, err := DecodeSnapshotBinary(data)
+	if err != nil {
+		t.Fatalf("DecodeSnapshotBinary: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"coils":            snap["coils"],
+		"holdingRegisters": snap["holdingRegisters"],
+		"empty":            []uint16{},
+		"inputRegisters":   []uint16{1, 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %v, want %v", got, want)
+	}
+
+	// 同じ内容からは同じバイト列になる
+	if !bytes.Equal(data, EncodeSnapshotBinary(snap)) {
+		t.Error("encoding is not deterministic")
+	}
+}
+
+func TestDecodeSnapshotBinary_Invalid(t *testing.T) {
+	if _, err := DecodeSnapshotBinary([]byte("not gzip")); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("expected ErrInvalidData for non-gzip data, got %v", err)
+	}
+
+	data := EncodeSnapshotBinary(map[string]interface{}{"holdingRegisters": make([]uint16, 10)})
+	if _, err := DecodeSnapshotBinary(data[:len(data)/2]); err == nil {
+		t.Error("expected error for truncated data")
+	}
+}
@@ -0,0 +1,77 @@
+package datastore
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ByteOrder は2ワード（4バイト）の値をレジスタに格納する順序。
+// 値のバイトを上位から A B C D とし、レジスタ順に並べた表記で表す。
+type ByteOrder string
+
+const (
+	// ByteOrderABCD はビッグエンディアン（上位ワードが先）
+	ByteOrderABCD ByteOrder = "ABCD"
+	// ByteOrderCDAB はワードスワップ（下位ワードが先）
+	ByteOrderCDAB ByteOrder = "CDAB"
+	// ByteOrderBADC はワード内のバイトスワップ
+	ByteOrderBADC ByteOrder = "BADC"
+	// ByteOrderDCBA はリトルエンディアン
+	ByteOrderDCBA ByteOrder = "DCBA"
+)
+
+// ParseByteOrder はバイト順の表記（大文字小文字は区別しない）を解釈する
+func ParseByteOrder(s string) (ByteOrder, error) {
+	order := ByteOrder(strings.ToUpper(strings.TrimSpace(s)))
+	switch order {
+	case ByteOrderABCD, ByteOrderCDAB, ByteOrderBADC, ByteOrderDCBA:
+		return order, nil
+	}
+	return "", fmt.Errorf("%w: unknown byte order %q", ErrInvalidData, s)
+}
+
+// Uint32ToWords は32ビット値をバイト順に従って2ワードに変換する
+func Uint32ToWords(v uint32, order ByteOrder) [2]uint16 {
+	hi, lo := uint16(v>>16), uint16(v)
+	switch order {
+	case ByteOrderCDAB:
+		return [2]uint16{lo, hi}
+	case ByteOrderBADC:
+		return [2]uint16{swapBytes(hi), swapBytes(lo)}
+	case ByteOrderDCBA:
+		return [2]uint16{swapBytes(lo), swapBytes(hi)}
+	default:
+		return [2]uint16{hi, lo}
+	}
+}
+
+// WordsToUint32 は2ワードをバイト順に従って32ビット値に変換する
+func WordsToUint32(w [2]uint16, order ByteOrder) uint32 {
+	var hi, lo uint16
+	switch order {
+	case ByteOrderCDAB:
+		hi, lo = w[1], w[0]
+	case ByteOrderBADC:
+		hi, lo = swapBytes(w[0]), swapBytes(w[1])
+	case ByteOrderDCBA:
+		hi, lo = swapBytes(w[1]), swapBytes(w[0])
+	default:
+		hi, lo = w[0], w[1]
+	}
+	return uint32(hi)<<16 | uint32(lo)
+}
+
+// Float32ToWords は float32 をバイト順に従って2ワードに変換する
+func Float32ToWords(v float32, order ByteOrder) [2]uint16 {
+	return Uint32ToWords(math.Float32bits(v), order)
+}
+
+// WordsToFloat32 は2ワードをバイト順に従って float32 に変換する
+func WordsToFloat32(w [2]uint16, order ByteOrder) float32 {
+	return math.Float32frombits(WordsToUint32(w, order))
+}
+
+func swapBytes(w uint16) uint16 {
+	return w<<8 | w>>8
+}
@@ -0,0 +1,39 @@
+package datastore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFloat32ToWords(t *testing.T) {
+	// 1.5 = 0x3FC00000
+	tests := []struct {
+		order ByteOrder
+		want  [2]uint16
+	}{
+		{ByteOrderABCD, [2]uint16{0x3FC0, 0x0000}},
+		{ByteOrderCDAB, [2]uint16{0x0000, 0x3FC0}},
+		{ByteOrderBADC, [2]uint16{0xC03F, 0x0000}},
+		{ByteOrderDCBA, [2]uint16{0x0000, 0xC03F}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			got := Float32ToWords(1.5, tt.order)
+			if got != tt.want {
+				t.Errorf("Float32ToWords = %04X, want %04X", got, tt.want)
+			}
+			if v := WordsToFloat32(got, tt.order); v != 1.5 {
+				t.Errorf("WordsToFloat32 = %v, want 1.5", v)
+			}
+		})
+	}
+}
+
+func TestParseByteOrder(t *testing.T) {
+	if order, err := ParseByteOrder(" cdab "); err != nil || order != ByteOrderCDAB {
+		t.Errorf("ParseByteOrder(cdab) = %q, %v", order, err)
+	}
+	if _, err := ParseByteOrder("ABDC"); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("ParseByteOrder(ABDC) error = %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package datastore
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// スナップショットの差分（デルタ）の形式:
+//
+//	エリアID → map[uint32]bool（ビットエリア）または map[uint32]uint16（ワードエリア）
+//
+// 変更されたアドレスの値のみを持つ。エリアの点数が変わった場合や基準にないエリアは、
+// 差分の代わりにエリア全体の値（[]bool / []uint16）を持つ。
+// JSON でデコードした差分（map[string]interface{}）もそのまま適用できる。
+
+// SnapshotDelta は baseline から current への差分を返す。変更がない場合は空の map を返す。
+// current にないエリアは差分に含めない。
+func SnapshotDelta(baseline, current map[string]interface{}) (map[string]interface{}, error) {
+	delta := make(map[string]interface{})
+	for id, v := range current {
+		cur, err := normalizeAreaValues(v)
+		if err != nil {
+			return nil, fmt.Errorf("area %s: %w", id, err)
+		}
+		base, err := normalizeAreaValues(baseline[id])
+		if err != nil {
+			return nil, fmt.Errorf("area %s: %w", id, err)
+		}
+
+		if _, ok := baseline[id]; !ok || base.len() != cur.len() || (base.len() > 0 && base.isBit != cur.isBit) {
+			if cur.isBit {
+				delta[id] = append([]bool(nil), cur.bits...)
+			} else {
+				delta[id] = append([]uint16(nil), cur.words...)
+			}
+			continue
+		}
+
+		if cur.isBit {
+			changed := make(map[uint32]bool)
+			for i, b := range cur.bits {
+				if base.bits[i] != b {
+					changed[uint32(i)] = b
+				}
+			}
+			if len(changed) > 0 {
+				delta[id] = changed
+			}
+		} else {
+			changed := make(map[uint32]uint16)
+			for i, w := range cur.words {
+				if base.words[i] != w {
+					changed[uint32(i)] = w
+				}
+			}
+			if len(changed) > 0 {
+				delta[id] = changed
+			}
+		}
+	}
+	return delta, nil
+}
+
+// ApplySnapshotDelta は snapshot に delta を適用した新しいスナップショットを返す。
+// snapshot 自体は変更しない。
+func ApplySnapshotDelta(snapshot, delta map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(snapshot)+len(delta))
+	for id, v := range snapshot {
+		vals, err := normalizeAreaValues(v)
+		if err != nil {
+			return nil, fmt.Errorf("area %s: %w", id, err)
+		}
+		if vals.isBit {
+			result[id] = append([]bool(nil), vals.bits...)
+		} else {
+			result[id] = append([]uint16(nil), vals.words...)
+		}
+	}
+
+	ids := make([]string, 0, len(delta))
+	for id := range delta {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := applyAreaDelta(result, id, delta[id]); err != nil {
+			return nil, fmt.Errorf("area %s: %w", id, err)
+		}
+	}
+	return result, nil
+}
+
+// applyAreaDelta は1エリア分の差分を result に適用する
+func applyAreaDelta(result map[string]interface{}, id string, d interface{}) error {
+	switch changes := d.(type) {
+	case map[uint32]bool:
+		bits, ok := result[id].([]bool)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		for addr, b := range changes {
+			if uint64(addr) >= uint64(len(bits)) {
+				return ErrAddressOutOfRange
+			}
+			bits[addr] = b
+		}
+	case map[uint32]uint16:
+		words, ok := result[id].([]uint16)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		for addr, w := range changes {
+			if uint64(addr) >= uint64(len(words)) {
+				return ErrAddressOutOfRange
+			}
+			words[addr] = w
+		}
+	case map[string]interface{}:
+		// JSON デコード後の差分
+		for key, v := range changes {
+			addr, err := strconv.ParseUint(key, 10, 32)
+			if err != nil {
+				return ErrInvalidData
+			}
+			switch cur := result[id].(type) {
+			case []bool:
+				b, ok := v.(bool)
+				if !ok {
+					return ErrInvalidData
+				}
+				if addr >= uint64(len(cur)) {
+					return ErrAddressOutOfRange
+				}
+				cur[addr] = b
+			case []uint16:
+				n, ok := v.(float64)
+				if !ok || n < 0 || n > 0xFFFF {
+					return ErrInvalidData
+				}
+				if addr >= uint64(len(cur)) {
+					return ErrAddressOutOfRange
+				}
+				cur[addr] = uint16(n)
+			default:
+				return ErrAreaNotFound
+			}
+		}
+	default:
+		// エリア全体の置き換え
+		vals, err := normalizeAreaValues(d)
+		if err != nil {
+			return err
+		}
+		if vals.isBit {
+			result[id] = append([]bool(nil), vals.bits...)
+		} else {
+			result[id] = append([]uint16(nil), vals.words...)
+		}
+	}
+	return nil
+}
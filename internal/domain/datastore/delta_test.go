@@ -0,0 +1,69 @@
+package datastore
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotDelta(t *testing.T) {
+	base := map[string]interface{}{
+		"coils":            []bool{false, true, false},
+		"holdingRegisters": []uint16{0, 100, 200, 300},
+		"inputRegisters":   []uint16{1, 2},
+	}
+	cur := map[string]interface{}{
+		"coils":            []bool{true, true, false},
+		"holdingRegisters": []uint16{0, 101, 200, 300},
+		"inputRegisters":   []uint16{1, 2, 3},
+	}
+
+	delta, err := SnapshotDelta(base, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"coils":            map[uint32]bool{0: true},
+		"holdingRegisters": map[uint32]uint16{1: 101},
+		// 点数が変わったエリアは全体を持つ
+		"inputRegisters": []uint16{1, 2, 3},
+	}
+	if !reflect.DeepEqual(delta, want) {
+		t.Fatalf("SnapshotDelta = %v, want %v", delta, want)
+	}
+
+	applied, err := ApplySnapshotDelta(base, delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(applied, cur) {
+		t.Errorf("ApplySnapshotDelta = %v, want %v", applied, cur)
+	}
+	if base["holdingRegisters"].([]uint16)[1] != 100 {
+		t.Error("ApplySnapshotDelta modified the base snapshot")
+	}
+}
+
+func TestApplySnapshotDelta_Errors(t *testing.T) {
+	base := map[string]interface{}{"holdingRegisters": []uint16{0, 0}}
+	tests := []struct {
+		name  string
+		delta map[string]interface{}
+		want  error
+	}{
+		{"out of range", map[string]interface{}{"holdingRegisters": map[uint32]uint16{5: 1}}, ErrAddressOutOfRange},
+		{"address near max uint32", map[string]interface{}{"holdingRegisters": map[uint32]uint16{math.MaxUint32: 1}}, ErrAddressOutOfRange},
+		{"type mismatch", map[string]interface{}{"holdingRegisters": map[uint32]bool{0: true}}, ErrTypeMismatch},
+		{"bad key", map[string]interface{}{"holdingRegisters": map[string]interface{}{"x": 1.0}}, ErrInvalidData},
+		{"bad value", map[string]interface{}{"holdingRegisters": map[string]interface{}{"0": 70000.0}}, ErrInvalidData},
+		{"unknown area", map[string]interface{}{"coils": map[string]interface{}{"0": true}}, ErrAreaNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ApplySnapshotDelta(base, tt.delta); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package datastore
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Change はスナップショット間で値が異なる1アドレス分の差分
+// ビットエリアの場合 Old/New は bool、ワードエリアの場合は uint16
+type Change struct {
+	Area    string
+	Address uint32
+	IsBit   bool
+	Old     interface{}
+	New     interface{}
+}
+
+// areaValues はスナップショット1エリア分の正規化済みの値
+type areaValues struct {
+	isBit bool
+	bits  []bool
+	words []uint16
+}
+
+func (v areaValues) len() int {
+	if v.isBit {
+		return len(v.bits)
+	}
+	return len(v.words)
+}
+
+// DiffSnapshots は2つのスナップショットを比較し、値が異なるアドレスの一覧を返す。
+// 片方にしか存在しないエリアや長さが異なるエリアは、存在しない側をゼロ値として比較する。
+// 結果はエリアID、アドレスの昇順で並ぶ。
+func DiffSnapshots(a, b map[string]interface{}) ([]Change, error) {
+	areaSet := make(map[string]struct{}, len(a)+len(b))
+	for id := range a {
+		areaSet[id] = struct{}{}
+	}
+	for id := range b {
+		areaSet[id] = struct{}{}
+	}
+	areaIDs := make([]string, 0, len(areaSet))
+	for id := range areaSet {
+		areaIDs = append(areaIDs, id)
+	}
+	sort.Strings(areaIDs)
+
+	var changes []Change
+	for _, id := range areaIDs {
+		oldVals, err := normalizeAreaValues(a[id])
+		if err != nil {
+			return nil, fmt.Errorf("area %s: %w", id, err)
+		}
+		newVals, err := normalizeAreaValues(b[id])
+		if err != nil {
+			return nil, fmt.Errorf("area %s: %w", id, err)
+		}
+
+		// 片方が空の場合はもう片方の型に合わせる
+		isBit := oldVals.isBit
+		if oldVals.len() == 0 {
+			isBit = newVals.isBit
+		} else if newVals.len() > 0 && newVals.isBit != oldVals.isBit {
+			return nil, fmt.Errorf("area %s: %w", id, ErrTypeMismatch)
+		}
+
+		n := oldVals.len()
+		if newVals.len() > n {
+			n = newVals.len()
+		}
+		for i := 0; i < n; i++ {
+			if isBit {
+				o, nv := bitAt(oldVals.bits, i), bitAt(newVals.bits, i)
+				if o != nv {
+					changes = append(changes, Change{Area: id, Address: uint32(i), IsBit: true, Old: o, New: nv})
+				}
+			} else {
+				o, nv := wordAt(oldVals.words, i), wordAt(newVals.words, i)
+				if o != nv {
+					changes = append(changes, Change{Area: id, Address: uint32(i), Old: o, New: nv})
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+func bitAt(values []bool, i int) bool {
+	if i < len(values) {
+		return values[i]
+	}
+	return false
+}
+
+func wordAt(values []uint16, i int) uint16 {
+	if i < len(values) {
+		return values[i]
+	}
+	return 0
+}
+
+// normalizeAreaValues はスナップショットのエリア値を []bool または []uint16 に正規化する。
+// インプロセスの DataStore は []bool / []uint16 を返すが、
+// gRPC 経由（JSON デコード後）の場合は []interface{} になるため両方を受け付ける。
+func normalizeAreaValues(v interface{}) (areaValues, error) {
+	switch vals := v.(type) {
+	case nil:
+		return areaValues{}, nil
+	case []bool:
+		return areaValues{isBit: true, bits: vals}, nil
+	case []uint16:
+		return areaValues{words: vals}, nil
+	case []int:
+		words := make([]uint16, len(vals))
+		for i, w := range vals {
+			words[i] = uint16(w)
+		}
+		return areaValues{words: words}, nil
+	case []interface{}:
+		if len(vals) == 0 {
+			return areaValues{}, nil
+		}
+		if _, ok := vals[0].(bool); ok {
+			bits := make([]bool, len(vals))
+			for i, e := range vals {
+				b, ok := e.(bool)
+				if !ok {
+					return areaValues{}, ErrInvalidData
+				}
+				bits[i] = b
+			}
+			return areaValues{isBit: true, bits: bits}, nil
+		}
+		words := make([]uint16, len(vals))
+		for i, e := range vals {
+			switch n := e.(type) {
+			case float64:
+				words[i] = uint16(n)
+			case int:
+				words[i] = uint16(n)
+			case uint16:
+				words[i] = n
+			default:
+				return areaValues{}, ErrInvalidData
+			}
+		}
+		return areaValues{words: words}, nil
+	default:
+		return areaValues{}, ErrInvalidData
+	}
+}
@@ -0,0 +1,112 @@
+package datastore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	a := map[string]interface{}{
+		"coils":            []bool{false, true, false, false},
+		"holdingRegisters": []uint16{0, 100, 200, 300},
+		"inputRegisters":   []uint16{1, 2, 3},
+	}
+	b := map[string]interface{}{
+		"coils":            []bool{true, true, false, true},
+		"holdingRegisters": []uint16{0, 101, 200, 0},
+		"inputRegisters":   []uint16{1, 2, 3},
+	}
+
+	changes, err := DiffSnapshots(a, b)
+	if err != nil {
+		t.Fatalf("DiffSnapshots failed: %v", err)
+	}
+
+	expected := []Change{
+		{Area: "coils", Address: 0, IsBit: true, Old: false, New: true},
+		{Area: "coils", Address: 3, IsBit: true, Old: false, New: true},
+		{Area: "holdingRegisters", Address: 1, Old: uint16(100), New: uint16(101)},
+		{Area: "holdingRegisters", Address: 3, Old: uint16(300), New: uint16(0)},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i, c := range changes {
+		if c != expected[i] {
+			t.Errorf("change[%d]: expected %+v, got %+v", i, expected[i], c)
+		}
+	}
+}
+
+func TestDiffSnapshots_NoChanges(t *testing.T) {
+	a := map[string]interface{}{
+		"coils":            []bool{true, false},
+		"holdingRegisters": []uint16{1, 2},
+	}
+
+	changes, err := DiffSnapshots(a, a)
+	if err != nil {
+		t.Fatalf("DiffSnapshots failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffSnapshots_JSONDecodedValues(t *testing.T) {
+	// gRPC 経由のスナップショットは JSON デコード後の []interface{} になる
+	a := map[string]interface{}{
+		"coils":            []interface{}{false, false},
+		"holdingRegisters": []interface{}{float64(10), float64(20)},
+	}
+	b := map[string]interface{}{
+		"coils":            []bool{false, true},
+		"holdingRegisters": []uint16{10, 25},
+	}
+
+	changes, err := DiffSnapshots(a, b)
+	if err != nil {
+		t.Fatalf("DiffSnapshots failed: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Area != "coils" || changes[0].Address != 1 {
+		t.Errorf("unexpected change[0]: %+v", changes[0])
+	}
+	if changes[1].Old != uint16(20) || changes[1].New != uint16(25) {
+		t.Errorf("unexpected change[1]: %+v", changes[1])
+	}
+}
+
+func TestDiffSnapshots_MissingArea(t *testing.T) {
+	a := map[string]interface{}{}
+	b := map[string]interface{}{
+		"holdingRegisters": []uint16{0, 5},
+	}
+
+	changes, err := DiffSnapshots(a, b)
+	if err != nil {
+		t.Fatalf("DiffSnapshots failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Address != 1 || changes[0].New != uint16(5) {
+		t.Errorf("unexpected changes: %+v", changes)
+	}
+}
+
+func TestDiffSnapshots_TypeMismatch(t *testing.T) {
+	a := map[string]interface{}{"area": []bool{true}}
+	b := map[string]interface{}{"area": []uint16{1}}
+
+	if _, err := DiffSnapshots(a, b); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch, got %v", err)
+	}
+}
+
+func TestDiffSnapshots_InvalidData(t *testing.T) {
+	a := map[string]interface{}{"area": "invalid"}
+
+	if _, err := DiffSnapshots(a, nil); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("expected ErrInvalidData, got %v", err)
+	}
+}
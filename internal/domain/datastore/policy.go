@@ -0,0 +1,58 @@
+package datastore
+
+import "fmt"
+
+// OutOfRangePolicy は範囲外アドレスの読み取り時の動作
+type OutOfRangePolicy string
+
+const (
+	// OutOfRangeError は範囲外の読み取りをエラー（Modbus では例外応答）にする（デフォルト）
+	OutOfRangeError OutOfRangePolicy = "error"
+	// OutOfRangeZero は範囲外のアドレスをゼロ（false）として返す
+	OutOfRangeZero OutOfRangePolicy = "zero"
+	// OutOfRangeWrap は範囲外のアドレスをエリア先頭に折り返して返す
+	OutOfRangeWrap OutOfRangePolicy = "wrap"
+)
+
+// ParseOutOfRangePolicy は文字列をポリシーに変換する（空文字列は OutOfRangeError）
+func ParseOutOfRangePolicy(s string) (OutOfRangePolicy, error) {
+	switch p := OutOfRangePolicy(s); p {
+	case "":
+		return OutOfRangeError, nil
+	case OutOfRangeError, OutOfRangeZero, OutOfRangeWrap:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: unknown out-of-range policy %q", ErrInvalidData, s)
+	}
+}
+
+// ReadRange はポリシーに従って src[address:address+count] を読み取る
+func ReadRange[T any](src []T, address uint32, count uint16, policy OutOfRangePolicy) ([]T, error) {
+	size := uint64(len(src))
+	r := AddrRange{Address: address, Count: uint32(count)}
+	if r.Validate(uint32(size)) == nil {
+		result := make([]T, count)
+		copy(result, src[address:r.End()])
+		return result, nil
+	}
+
+	switch policy {
+	case OutOfRangeZero:
+		result := make([]T, count)
+		if uint64(address) < size {
+			copy(result, src[address:])
+		}
+		return result, nil
+	case OutOfRangeWrap:
+		result := make([]T, count)
+		if size == 0 {
+			return result, nil
+		}
+		for i := range result {
+			result[i] = src[(uint64(address)+uint64(i))%size]
+		}
+		return result, nil
+	default:
+		return nil, ErrAddressOutOfRange
+	}
+}
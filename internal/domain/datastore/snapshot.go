@@ -0,0 +1,21 @@
+package datastore
+
+// SnapshotBits はスナップショットのエリア値を []bool として取り出す。
+// JSON デコード後の []interface{} も受け付ける。ビットエリアでない場合は false を返す。
+func SnapshotBits(v interface{}) ([]bool, bool) {
+	vals, err := normalizeAreaValues(v)
+	if err != nil || (!vals.isBit && vals.len() > 0) {
+		return nil, false
+	}
+	return vals.bits, true
+}
+
+// SnapshotWords はスナップショットのエリア値を []uint16 として取り出す。
+// JSON デコード後の []interface{} も受け付ける。ワードエリアでない場合は false を返す。
+func SnapshotWords(v interface{}) ([]uint16, bool) {
+	vals, err := normalizeAreaValues(v)
+	if err != nil || vals.isBit {
+		return nil, false
+	}
+	return vals.words, true
+}
@@ -0,0 +1,116 @@
+package datastore
+
+import (
+	"fmt"
+	"sort"
+)
+
+// スパーススナップショットの形式:
+//
+//	エリアID → SparseArea{Size, IsBit, Runs: [{start, values}...]}
+//
+// 0 以外（ビットエリアは ON）の値が連続する範囲（ラン）のみを持ち、それ以外のアドレスは 0 とみなす。
+// ほとんどが 0 の大きなエリアを小さく保存するために使う。
+// エリアの点数と種類を持つため、全アドレスが 0 のエリアも正確に復元できる。
+
+// SparseRun は 0 以外の値が連続する範囲
+type SparseRun struct {
+	Start  uint32      `json:"start"`
+	Values interface{} `json:"values"` // []bool（ビットエリア）または []uint16（ワードエリア）
+}
+
+// SparseArea は1エリア分のスパーススナップショット
+type SparseArea struct {
+	Size  uint32      `json:"size"`
+	IsBit bool        `json:"isBit"`
+	Runs  []SparseRun `json:"runs"`
+}
+
+// SparseSnapshot はエリアIDごとのスパーススナップショット
+type SparseSnapshot map[string]SparseArea
+
+// SparseFromSnapshot はスナップショット（Snapshot の出力）をスパース形式に変換する
+func SparseFromSnapshot(snapshot map[string]interface{}) (SparseSnapshot, error) {
+	result := make(SparseSnapshot, len(snapshot))
+	for id, v := range snapshot {
+		vals, err := normalizeAreaValues(v)
+		if err != nil {
+			return nil, fmt.Errorf("area %s: %w", id, err)
+		}
+		area := SparseArea{Size: uint32(vals.len()), IsBit: vals.isBit, Runs: []SparseRun{}}
+		for start := 0; start < vals.len(); {
+			if vals.isZero(start) {
+				start++
+				continue
+			}
+			end := start + 1
+			for end < vals.len() && !vals.isZero(end) {
+				end++
+			}
+			run := SparseRun{Start: uint32(start)}
+			if vals.isBit {
+				run.Values = append([]bool(nil), vals.bits[start:end]...)
+			} else {
+				run.Values = append([]uint16(nil), vals.words[start:end]...)
+			}
+			area.Runs = append(area.Runs, run)
+			start = end
+		}
+		result[id] = area
+	}
+	return result, nil
+}
+
+// Expand はスパーススナップショットを通常のスナップショット（Restore の入力）に戻す。
+// JSON デコード後のラン（values が []interface{}）も受け付ける。
+func (sp SparseSnapshot) Expand() (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(sp))
+
+	ids := make([]string, 0, len(sp))
+	for id := range sp {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		area := sp[id]
+		var bits []bool
+		var words []uint16
+		if area.IsBit {
+			bits = make([]bool, area.Size)
+		} else {
+			words = make([]uint16, area.Size)
+		}
+		for _, run := range area.Runs {
+			vals, err := normalizeAreaValues(run.Values)
+			if err != nil {
+				return nil, fmt.Errorf("area %s: %w", id, err)
+			}
+			if vals.len() > 0 && vals.isBit != area.IsBit {
+				return nil, fmt.Errorf("area %s: %w", id, ErrTypeMismatch)
+			}
+			if uint64(run.Start)+uint64(vals.len()) > uint64(area.Size) {
+				return nil, fmt.Errorf("area %s: %w", id, ErrAddressOutOfRange)
+			}
+			if area.IsBit {
+				copy(bits[run.Start:], vals.bits)
+			} else {
+				copy(words[run.Start:], vals.words)
+			}
+		}
+		if area.IsBit {
+			result[id] = bits
+		} else {
+			result[id] = words
+		}
+	}
+	return result, nil
+}
+
+// isZero は i 番目の値が 0（ビットエリアは OFF）かを返す
+func (v areaValues) isZero(i int) bool {
+	if v.isBit {
+		return !v.bits[i]
+	}
+	return v.words[i] == 0
+}
@@ -0,0 +1,81 @@
+package datastore
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSparseSnapshot_MostlyZeroRoundTrip(t *testing.T) {
+	words := make([]uint16, 32768)
+	words[10], words[11], words[12] = 1, 2, 3
+	words[30000] = 0xFFFF
+	bits := make([]bool, 2000)
+	bits[5] = true
+	snap := map[string]interface{}{
+		"holdingRegisters": words,
+		"coils":            bits,
+		"inputRegisters":   make([]uint16, 100),
+	}
+
+	sparse, err := SparseFromSnapshot(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SparseArea{Size: 32768, Runs: []SparseRun{
+		{Start: 10, Values: []uint16{1, 2, 3}},
+		{Start: 30000, Values: []uint16{0xFFFF}},
+	}}
+	if !reflect.DeepEqual(sparse["holdingRegisters"], want) {
+		t.Errorf("holdingRegisters = %+v, want %+v", sparse["holdingRegisters"], want)
+	}
+	if got := sparse["inputRegisters"]; got.Size != 100 || len(got.Runs) != 0 {
+		t.Errorf("all-zero area = %+v", got)
+	}
+
+	sparseJSON, err := json.Marshal(sparse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullJSON, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sparseJSON)*100 > len(fullJSON) {
+		t.Errorf("sparse form is %d bytes, full snapshot is %d bytes", len(sparseJSON), len(fullJSON))
+	}
+
+	// JSON を経由しても元のスナップショットに戻る
+	var decoded SparseSnapshot
+	if err := json.Unmarshal(sparseJSON, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := decoded.Expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored, snap) {
+		t.Error("expanded snapshot differs from the original")
+	}
+}
+
+func TestSparseSnapshot_ExpandRejectsInvalidRuns(t *testing.T) {
+	tests := []struct {
+		name string
+		area SparseArea
+		want error
+	}{
+		{"out of range", SparseArea{Size: 4, Runs: []SparseRun{{Start: 3, Values: []uint16{1, 2}}}}, ErrAddressOutOfRange},
+		{"type mismatch", SparseArea{Size: 4, IsBit: true, Runs: []SparseRun{{Start: 0, Values: []uint16{1}}}}, ErrTypeMismatch},
+		{"invalid values", SparseArea{Size: 4, Runs: []SparseRun{{Start: 0, Values: "x"}}}, ErrInvalidData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SparseSnapshot{"area": tt.area}.Expand()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
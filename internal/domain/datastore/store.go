@@ -13,6 +13,8 @@ type MemoryArea struct {
 type DataStore interface {
 	// GetAreas は利用可能なメモリエリアの一覧を返す
 	GetAreas() []MemoryArea
+	// GetArea は指定IDのメモリエリアを返す（存在しなければ false）
+	GetArea(id string) (MemoryArea, bool)
 
 	// ビット操作
 	ReadBit(area string, address uint32) (bool, error)
@@ -3,6 +3,7 @@ package protocol
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -17,7 +18,8 @@ type CommunicationEventEmitter interface {
 
 // WailsEventEmitter はWailsランタイムを使用したイベントエミッター
 type WailsEventEmitter struct {
-	ctx context.Context
+	ctx    context.Context
+	closed atomic.Bool
 }
 
 // NewWailsEventEmitter は新しいWailsEventEmitterを作成する
@@ -25,27 +27,199 @@ func NewWailsEventEmitter(ctx context.Context) *WailsEventEmitter {
 	return &WailsEventEmitter{ctx: ctx}
 }
 
+// Close は以降のイベント発行を止める（シャットダウン時に使用）
+func (e *WailsEventEmitter) Close() {
+	e.closed.Store(true)
+}
+
+// active はイベントを発行できる状態かどうかを返す（ctx が無い・キャンセル済み・Close 済みなら false）
+func (e *WailsEventEmitter) active() bool {
+	return e.ctx != nil && e.ctx.Err() == nil && !e.closed.Load()
+}
+
 // EmitRx は受信イベントを発行する
 func (e *WailsEventEmitter) EmitRx() {
-	if e.ctx != nil {
+	if e.active() {
 		runtime.EventsEmit(e.ctx, "comm:rx", nil)
 	}
 }
 
 // EmitTx は送信イベントを発行する
 func (e *WailsEventEmitter) EmitTx() {
-	if e.ctx != nil {
+	if e.active() {
 		runtime.EventsEmit(e.ctx, "comm:tx", nil)
 	}
 }
 
 // EmitConnection は接続数変更イベントを発行する
 func (e *WailsEventEmitter) EmitConnection(count int) {
-	if e.ctx != nil {
+	if e.active() {
 		runtime.EventsEmit(e.ctx, "comm:connection", map[string]int{"count": count})
 	}
 }
 
+// EmitCommStats は前回以降の受信・送信回数をまとめたイベントを発行する
+func (e *WailsEventEmitter) EmitCommStats(rx, tx uint64) {
+	if e.active() {
+		runtime.EventsEmit(e.ctx, "comm:stats", map[string]uint64{"rx": rx, "tx": tx})
+	}
+}
+
+// CommStatsEmitter は受信・送信回数をまとめて受け取れる CommunicationEventEmitter
+type CommStatsEmitter interface {
+	CommunicationEventEmitter
+	EmitCommStats(rx, tx uint64)
+}
+
+// DefaultCoalesceInterval は CoalescingEventEmitter が回数をまとめて発行する既定の間隔
+const DefaultCoalesceInterval = 100 * time.Millisecond
+
+// CoalescingEventEmitter は EmitRx/EmitTx を数えておき、interval ごとに最大1回
+// target.EmitCommStats でまとめて発行するエミッター（高頻度ポーリングで UI があふれないようにする）。
+// 最初の EmitRx/EmitTx から interval 後に発行し、その間の呼び出しは回数のみ加算する。
+// EmitConnection はそのまま target に渡す。
+type CoalescingEventEmitter struct {
+	target   CommStatsEmitter
+	interval time.Duration
+
+	mu     sync.Mutex
+	rx, tx uint64
+	timer  *time.Timer
+	closed bool
+}
+
+// NewCoalescingEventEmitter は新しい CoalescingEventEmitter を作成する
+func NewCoalescingEventEmitter(target CommStatsEmitter, interval time.Duration) *CoalescingEventEmitter {
+	return &CoalescingEventEmitter{target: target, interval: interval}
+}
+
+// EmitRx は受信回数を加算する
+func (c *CoalescingEventEmitter) EmitRx() {
+	c.add(1, 0)
+}
+
+// EmitTx は送信回数を加算する
+func (c *CoalescingEventEmitter) EmitTx() {
+	c.add(0, 1)
+}
+
+// EmitConnection は接続数変更イベントをそのまま発行する
+func (c *CoalescingEventEmitter) EmitConnection(count int) {
+	c.target.EmitConnection(count)
+}
+
+func (c *CoalescingEventEmitter) add(rx, tx uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.rx += rx
+	c.tx += tx
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.interval, c.flush)
+	}
+}
+
+// flush はたまった回数を発行する
+func (c *CoalescingEventEmitter) flush() {
+	c.mu.Lock()
+	rx, tx := c.rx, c.tx
+	c.rx, c.tx = 0, 0
+	c.timer = nil
+	closed := c.closed
+	c.mu.Unlock()
+	if closed || (rx == 0 && tx == 0) {
+		return
+	}
+	c.target.EmitCommStats(rx, tx)
+}
+
+// Close は以降の発行を止め、未発行の回数を破棄する。target が Close を持つ場合は伝える。
+func (c *CoalescingEventEmitter) Close() {
+	c.mu.Lock()
+	c.closed = true
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mu.Unlock()
+	if closer, ok := c.target.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// MultiEventEmitter は複数のエミッターに同じイベントを配信する
+type MultiEventEmitter struct {
+	emitters []CommunicationEventEmitter
+	closed   atomic.Bool
+}
+
+// NewMultiEventEmitter は新しいMultiEventEmitterを作成する（nil は無視する）
+func NewMultiEventEmitter(emitters ...CommunicationEventEmitter) *MultiEventEmitter {
+	m := &MultiEventEmitter{}
+	for _, e := range emitters {
+		if e != nil {
+			m.emitters = append(m.emitters, e)
+		}
+	}
+	return m
+}
+
+// Close は以降のイベント配信を止め、Close を持つエミッターにも伝える（シャットダウン時に使用）
+func (m *MultiEventEmitter) Close() {
+	if m.closed.Swap(true) {
+		return
+	}
+	for _, e := range m.emitters {
+		if c, ok := e.(interface{ Close() }); ok {
+			c.Close()
+		}
+	}
+}
+
+// EmitRx は受信イベントを発行する
+func (m *MultiEventEmitter) EmitRx() {
+	if m.closed.Load() {
+		return
+	}
+	for _, e := range m.emitters {
+		e.EmitRx()
+	}
+}
+
+// EmitTx は送信イベントを発行する
+func (m *MultiEventEmitter) EmitTx() {
+	if m.closed.Load() {
+		return
+	}
+	for _, e := range m.emitters {
+		e.EmitTx()
+	}
+}
+
+// EmitConnection は接続数変更イベントを発行する
+func (m *MultiEventEmitter) EmitConnection(count int) {
+	if m.closed.Load() {
+		return
+	}
+	for _, e := range m.emitters {
+		e.EmitConnection(count)
+	}
+}
+
+// RecordRequest は RequestRecorder を実装するエミッターにのみ配信する
+func (m *MultiEventEmitter) RecordRequest(functionCode uint8, exception bool) {
+	if m.closed.Load() {
+		return
+	}
+	for _, e := range m.emitters {
+		if r, ok := e.(RequestRecorder); ok {
+			r.RecordRequest(functionCode, exception)
+		}
+	}
+}
+
 // SessionManager はアクティブセッション方式で接続数を管理する
 // Modbus TCPなど、正確な接続追跡ができないプロトコル向け
 // UnitIDごとにセッションを追跡し、複数クライアントを識別する
@@ -139,6 +313,22 @@ func (m *SessionManager) Stop() {
 	}
 }
 
+// SetTimeout はセッションのタイムアウトを変更する（監視中でも安全に変更できる）。
+// 短くした場合は、新しいタイムアウトを過ぎたセッションを直ちに削除する。
+func (m *SessionManager) SetTimeout(d time.Duration) {
+	m.mu.Lock()
+	m.timeout = d
+	m.mu.Unlock()
+	m.checkTimeout()
+}
+
+// Timeout は現在のセッションのタイムアウトを返す
+func (m *SessionManager) Timeout() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.timeout
+}
+
 // checkTimeout はタイムアウトをチェックし、期限切れのセッションを削除する
 func (m *SessionManager) checkTimeout() {
 	m.mu.Lock()
@@ -166,3 +356,14 @@ func (m *SessionManager) GetActiveCount() int {
 	defer m.mu.Unlock()
 	return len(m.sessions)
 }
+
+// Snapshot は UnitID ごとの最終アクティビティ時刻のコピーを返す（タイムアウトしたユニットは含まない）
+func (m *SessionManager) Snapshot() map[uint8]time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[uint8]time.Time, len(m.sessions))
+	for unitID, at := range m.sessions {
+		result[unitID] = at
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package protocol
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closableEmitter は Close の呼び出しを記録するテスト用エミッター
+type closableEmitter struct {
+	RecordingEmitter
+	closed bool
+}
+
+func (e *closableEmitter) Close() { e.closed = true }
+
+func TestMultiEventEmitter_Close(t *testing.T) {
+	counter := NewRecordingEmitter()
+	closable := &closableEmitter{}
+	m := NewMultiEventEmitter(counter, closable)
+
+	m.EmitConnection(3)
+	m.Close()
+	m.EmitConnection(0)
+
+	if got := counter.Snapshot().ConnectionCounts; len(got) != 1 || got[0] != 3 {
+		t.Errorf("connections = %v, want [3] (events after Close must be dropped)", got)
+	}
+	if !closable.closed {
+		t.Error("Close was not forwarded to child emitter")
+	}
+	m.Close() // 二重に呼んでもよい
+}
+
+func TestWailsEventEmitter_Active(t *testing.T) {
+	if NewWailsEventEmitter(nil).active() { //nolint:staticcheck // nil ctx のガードを確認する
+		t.Error("nil ctx should not be active")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := NewWailsEventEmitter(ctx)
+	if !e.active() {
+		t.Fatal("emitter should be active before cancel")
+	}
+	cancel()
+	if e.active() {
+		t.Error("canceled ctx should not be active")
+	}
+
+	e = NewWailsEventEmitter(context.Background())
+	e.Close()
+	if e.active() {
+		t.Error("closed emitter should not be active")
+	}
+	// キャンセル後の発行は何もしない（Wails ランタイムを呼ばない）
+	e.EmitRx()
+	e.EmitConnection(1)
+}
+
+// statsEmitter は EmitCommStats の呼び出しを記録するテスト用エミッター
+type statsEmitter struct {
+	RecordingEmitter
+	mu    sync.Mutex
+	stats [][2]uint64
+}
+
+func (e *statsEmitter) EmitCommStats(rx, tx uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stats = append(e.stats, [2]uint64{rx, tx})
+}
+
+func (e *statsEmitter) flushed() [][2]uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([][2]uint64(nil), e.stats...)
+}
+
+func TestCoalescingEventEmitter_BatchesWithinWindow(t *testing.T) {
+	target := &statsEmitter{}
+	c := NewCoalescingEventEmitter(target, 50*time.Millisecond)
+
+	for i := 0; i < 1000; i++ {
+		c.EmitRx()
+		if i%2 == 0 {
+			c.EmitTx()
+		}
+	}
+	c.EmitConnection(2) // 接続数はまとめずにすぐ渡す
+	if got := target.Snapshot().LastConnectionCount(); got != 2 {
+		t.Errorf("connections = %d, want 2", got)
+	}
+	if got := target.flushed(); len(got) != 0 {
+		t.Fatalf("flushed before the window elapsed: %v", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	got := target.flushed()
+	if len(got) != 1 || got[0] != [2]uint64{1000, 500} {
+		t.Fatalf("flushed = %v, want one event with rx=1000 tx=500", got)
+	}
+
+	// 次の窓は新しく数え直す
+	c.EmitRx()
+	time.Sleep(150 * time.Millisecond)
+	if got := target.flushed(); len(got) != 2 || got[1] != [2]uint64{1, 0} {
+		t.Errorf("second window = %v, want rx=1 tx=0", got)
+	}
+}
+
+func TestCoalescingEventEmitter_Close(t *testing.T) {
+	target := &statsEmitter{}
+	c := NewCoalescingEventEmitter(target, 20*time.Millisecond)
+
+	c.EmitRx()
+	c.Close()
+	c.EmitRx()
+	time.Sleep(60 * time.Millisecond)
+	if got := target.flushed(); len(got) != 0 {
+		t.Errorf("flushed after Close: %v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package protocol
+
+import "sync"
+
+// RecordedEvents は RecordingEmitter が記録したイベントの集計
+type RecordedEvents struct {
+	Rx               int   // EmitRx の呼び出し回数
+	Tx               int   // EmitTx の呼び出し回数
+	Connection       int   // EmitConnection の呼び出し回数
+	ConnectionCounts []int // EmitConnection に渡された接続数（呼び出し順）
+}
+
+// LastConnectionCount は最後に EmitConnection に渡された接続数を返す（呼び出されていなければ 0）
+func (r RecordedEvents) LastConnectionCount() int {
+	if len(r.ConnectionCounts) == 0 {
+		return 0
+	}
+	return r.ConnectionCounts[len(r.ConnectionCounts)-1]
+}
+
+// RecordingEmitter は呼び出しを記録するだけの CommunicationEventEmitter。
+// Wails ランタイムなしでハンドラーやサーバーのイベント発行をテストするために使う。
+// 複数のゴルーチンから同時に呼び出してよい。
+type RecordingEmitter struct {
+	mu     sync.Mutex
+	events RecordedEvents
+}
+
+var _ CommunicationEventEmitter = (*RecordingEmitter)(nil)
+
+// NewRecordingEmitter は新しい RecordingEmitter を作成する
+func NewRecordingEmitter() *RecordingEmitter {
+	return &RecordingEmitter{}
+}
+
+// EmitRx は受信イベントを記録する
+func (e *RecordingEmitter) EmitRx() {
+	e.mu.Lock()
+	e.events.Rx++
+	e.mu.Unlock()
+}
+
+// EmitTx は送信イベントを記録する
+func (e *RecordingEmitter) EmitTx() {
+	e.mu.Lock()
+	e.events.Tx++
+	e.mu.Unlock()
+}
+
+// EmitConnection は接続数イベントを記録する
+func (e *RecordingEmitter) EmitConnection(count int) {
+	e.mu.Lock()
+	e.events.Connection++
+	e.events.ConnectionCounts = append(e.events.ConnectionCounts, count)
+	e.mu.Unlock()
+}
+
+// Snapshot はこれまでに記録したイベントのコピーを返す
+func (e *RecordingEmitter) Snapshot() RecordedEvents {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	events := e.events
+	events.ConnectionCounts = append([]int(nil), e.events.ConnectionCounts...)
+	return events
+}
+
+// Reset は記録したイベントを破棄する
+func (e *RecordingEmitter) Reset() {
+	e.mu.Lock()
+	e.events = RecordedEvents{}
+	e.mu.Unlock()
+}
@@ -0,0 +1,43 @@
+package protocol
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRecordingEmitter_ConcurrentCounts(t *testing.T) {
+	e := NewRecordingEmitter()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				e.EmitRx()
+				e.EmitTx()
+			}
+		}()
+	}
+	wg.Wait()
+	e.EmitConnection(2)
+	e.EmitConnection(1)
+
+	got := e.Snapshot()
+	if got.Rx != 1000 || got.Tx != 1000 || got.Connection != 2 {
+		t.Errorf("snapshot = %+v", got)
+	}
+	if got.LastConnectionCount() != 1 {
+		t.Errorf("LastConnectionCount = %d, want 1", got.LastConnectionCount())
+	}
+
+	// Snapshot はコピーなので後の呼び出しの影響を受けない
+	e.EmitConnection(5)
+	if len(got.ConnectionCounts) != 2 {
+		t.Errorf("snapshot was modified: %v", got.ConnectionCounts)
+	}
+
+	e.Reset()
+	if got := e.Snapshot(); got.Rx != 0 || got.Connection != 0 || len(got.ConnectionCounts) != 0 {
+		t.Errorf("after Reset = %+v", got)
+	}
+}
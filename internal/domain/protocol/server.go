@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"context"
+	"time"
 )
 
 // ProtocolType はプロトコルの種類を表す
@@ -99,6 +100,7 @@ type ServerFactory interface {
 // 実際の定義は datastore パッケージにある
 type DataStore interface {
 	GetAreas() []MemoryArea
+	GetArea(id string) (MemoryArea, bool)
 	ReadBit(area string, address uint32) (bool, error)
 	WriteBit(area string, address uint32, value bool) error
 	ReadBits(area string, address uint32, count uint16) ([]bool, error)
@@ -192,7 +194,87 @@ type VariableStoreInjector interface {
 	InjectVariableStore(accessor VariableStoreAccessor)
 }
 
+// TransportFieldsProvider は待ち受け（通信路）に関わる設定フィールドを返せる ServerFactory 用インターフェース。
+// これ以外のフィールドだけが変わった場合、PLCService はサーバーを作り直さず実行中のまま設定を反映する。
+// 実装しないファクトリーや nil を返すバリアントは、全フィールドを通信路の設定として扱う。
+type TransportFieldsProvider interface {
+	TransportFields(variantID string) []string
+}
+
 // NodePublishingAware はノード公開設定変更通知を受け付ける ProtocolServer 用インターフェース
 type NodePublishingAware interface {
 	OnNodePublishingUpdated()
 }
+
+// ErrorReporter は Error 状態になった原因を返せる ProtocolServer 用インターフェース
+type ErrorReporter interface {
+	// LastError は Error 状態の原因を返す（Error 状態でなければ nil）
+	LastError() error
+}
+
+// ClientInfo は接続中のクライアントの情報
+type ClientInfo struct {
+	RemoteAddr   string    // クライアントのアドレス（"host:port"）
+	UnitID       uint8     // 最後のリクエストの UnitID
+	LastActivity time.Time // 最後にリクエストを受信した時刻
+}
+
+// ClientLister は接続中のクライアント一覧を返せる ProtocolServer 用インターフェース
+type ClientLister interface {
+	GetClients() []ClientInfo
+}
+
+// AccessCount はあるアドレスへのマスターからの読み取り・書き込み回数
+type AccessCount struct {
+	Address uint32
+	Reads   uint64
+	Writes  uint64
+}
+
+// AccessCounter はマスターからのアクセス回数をアドレスごとに返せる ProtocolServer 用インターフェース
+type AccessCounter interface {
+	// GetAccessCounts は area で一度でもアクセスされたアドレスの回数をアドレス順で返す
+	GetAccessCounts(area string) []AccessCount
+	// ResetAccessCounts は全エリアの回数を破棄する
+	ResetAccessCounts()
+}
+
+// FIFOQueuer は Modbus FC 0x18 (Read FIFO Queue) で返すキューを持つ ProtocolServer 用インターフェース
+type FIFOQueuer interface {
+	// EnqueueFIFO は FIFO ポインタアドレス pointer のキューに value を追加する
+	EnqueueFIFO(pointer, value uint16) error
+}
+
+// CommEventCounter は Modbus のイベントカウンタ（FC 0x0B）を持つ ProtocolServer 用インターフェース
+type CommEventCounter interface {
+	// CommEventCount は正常に応答したリクエスト数を返す
+	CommEventCount() (uint16, error)
+	// ClearCommEventCounter はカウンタを 0 に戻す
+	ClearCommEventCounter() error
+}
+
+// TrafficReporter は自身で集計した通信統計を返せる ProtocolServer 用インターフェース。
+// プラグインのサーバーはホストのイベントエミッターを受け取らないため、ホストはこの値を集計に加える。
+type TrafficReporter interface {
+	// TrafficStats は処理したリクエスト数・例外応答数・最後のファンクションコード・接続数を返す
+	TrafficStats() (TrafficSnapshot, error)
+}
+
+// UnitActivityReporter は UnitID ごとの最終アクティビティを自身で追跡する ProtocolServer 用インターフェース。
+// プラグインのサーバーはホストのセッションマネージャーを受け取らないため、ホストはこの値を加える。
+type UnitActivityReporter interface {
+	// UnitActivity は無通信でタイムアウトしていない UnitID ごとの最終ポーリング時刻を返す
+	UnitActivity() (map[uint8]time.Time, error)
+}
+
+// UnitMemoryAccessor は UnitID ごとに別々のメモリを持てる ProtocolServer 用インターフェース
+type UnitMemoryAccessor interface {
+	// UnitDataStore は指定 UnitID へのリクエストが読み書きするデータストアを返す
+	UnitDataStore(unitId uint8) DataStore
+}
+
+// BusySimulator は一定期間ビジー応答を返せる ProtocolServer 用インターフェース
+type BusySimulator interface {
+	// SetBusy は d の間、全リクエストにビジー例外を返すようにする（0以下で解除）
+	SetBusy(d time.Duration) error
+}
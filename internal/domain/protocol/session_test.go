@@ -0,0 +1,34 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionManager_SetTimeout(t *testing.T) {
+	emitter := NewRecordingEmitter()
+	m := NewSessionManager(time.Hour, emitter)
+	m.Start()
+	defer m.Stop()
+
+	m.RecordActivityWithUnitID(1)
+	time.Sleep(20 * time.Millisecond)
+
+	// 長いタイムアウトのままでは期限切れにならない
+	m.checkTimeout()
+	if got := m.GetActiveCount(); got != 1 {
+		t.Fatalf("active count = %d, want 1", got)
+	}
+
+	// 短くすると既に無通信時間を超えたセッションは直ちに外れる
+	m.SetTimeout(10 * time.Millisecond)
+	if got := m.GetActiveCount(); got != 0 {
+		t.Errorf("active count after shortening timeout = %d, want 0", got)
+	}
+	if got := emitter.Snapshot().LastConnectionCount(); got != 0 {
+		t.Errorf("emitted connections = %d, want 0", got)
+	}
+	if m.Timeout() != 10*time.Millisecond {
+		t.Errorf("Timeout = %v", m.Timeout())
+	}
+}
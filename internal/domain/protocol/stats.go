@@ -0,0 +1,85 @@
+package protocol
+
+import (
+	"sync"
+	"time"
+)
+
+// RequestRecorder は要求ごとのファンクションコードと例外応答の有無を受け取れるエミッターが実装する。
+// CommunicationEventEmitter に加えて任意で実装する。
+type RequestRecorder interface {
+	RecordRequest(functionCode uint8, exception bool)
+}
+
+// TrafficSnapshot は TrafficStats のある時点の値
+type TrafficSnapshot struct {
+	Requests         uint64
+	Exceptions       uint64
+	LastFunctionCode uint8
+	Connections      int
+	LastRequestAt    time.Time // 要求を受信していない場合はゼロ値
+}
+
+// Add は a と b を合算した値を返す（複数サーバーの集計用）。
+// 件数と接続数は合計し、最後のファンクションコードは後に要求を受信した側の値を使う。
+func (a TrafficSnapshot) Add(b TrafficSnapshot) TrafficSnapshot {
+	sum := TrafficSnapshot{
+		Requests:         a.Requests + b.Requests,
+		Exceptions:       a.Exceptions + b.Exceptions,
+		LastFunctionCode: a.LastFunctionCode,
+		Connections:      a.Connections + b.Connections,
+		LastRequestAt:    a.LastRequestAt,
+	}
+	if b.LastRequestAt.After(a.LastRequestAt) {
+		sum.LastFunctionCode = b.LastFunctionCode
+		sum.LastRequestAt = b.LastRequestAt
+	}
+	return sum
+}
+
+// TrafficStats は通信イベントから受信要求数・例外応答数・最後のファンクションコード・接続数を集計する。
+// CommunicationEventEmitter と RequestRecorder を実装する。
+type TrafficStats struct {
+	mu   sync.Mutex
+	snap TrafficSnapshot
+}
+
+// NewTrafficStats は新しい TrafficStats を作成する
+func NewTrafficStats() *TrafficStats {
+	return &TrafficStats{}
+}
+
+// EmitRx は受信要求数を数える
+func (t *TrafficStats) EmitRx() {
+	t.mu.Lock()
+	t.snap.Requests++
+	t.snap.LastRequestAt = time.Now()
+	t.mu.Unlock()
+}
+
+// EmitTx は何もしない（応答数は集計しない）
+func (t *TrafficStats) EmitTx() {}
+
+// EmitConnection は現在の接続数を記録する
+func (t *TrafficStats) EmitConnection(count int) {
+	t.mu.Lock()
+	t.snap.Connections = count
+	t.mu.Unlock()
+}
+
+// RecordRequest は最後のファンクションコードと例外応答数を記録する
+func (t *TrafficStats) RecordRequest(functionCode uint8, exception bool) {
+	t.mu.Lock()
+	t.snap.LastFunctionCode = functionCode
+	if exception {
+		t.snap.Exceptions++
+	}
+	t.mu.Unlock()
+}
+
+// Snapshot は現在の集計値を返す
+func (t *TrafficStats) Snapshot() TrafficSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snap
+}
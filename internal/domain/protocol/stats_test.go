@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrafficStats(t *testing.T) {
+	stats := NewTrafficStats()
+	var emitter CommunicationEventEmitter = NewMultiEventEmitter(stats)
+
+	emitter.EmitRx()
+	emitter.EmitTx()
+	emitter.EmitRx()
+	emitter.EmitConnection(2)
+	emitter.(RequestRecorder).RecordRequest(0x10, false)
+	emitter.(RequestRecorder).RecordRequest(0x06, true)
+
+	snap := stats.Snapshot()
+	if snap.Requests != 2 || snap.Exceptions != 1 || snap.LastFunctionCode != 0x06 || snap.Connections != 2 {
+		t.Errorf("snapshot = %+v", snap)
+	}
+	if snap.LastRequestAt.IsZero() {
+		t.Error("LastRequestAt not recorded")
+	}
+}
+
+func TestTrafficSnapshot_Add(t *testing.T) {
+	earlier := time.Now()
+	later := earlier.Add(time.Second)
+	a := TrafficSnapshot{Requests: 3, Exceptions: 1, LastFunctionCode: 0x03, Connections: 1, LastRequestAt: later}
+	b := TrafficSnapshot{Requests: 2, LastFunctionCode: 0x10, Connections: 2, LastRequestAt: earlier}
+
+	// 最後のファンクションコードは後に受信した側の値
+	want := TrafficSnapshot{Requests: 5, Exceptions: 1, LastFunctionCode: 0x03, Connections: 3, LastRequestAt: later}
+	if got := a.Add(b); got != want {
+		t.Errorf("a.Add(b) = %+v, want %+v", got, want)
+	}
+	if got := b.Add(a); got != want {
+		t.Errorf("b.Add(a) = %+v, want %+v", got, want)
+	}
+	if got := (TrafficSnapshot{}).Add(b); got != b {
+		t.Errorf("zero.Add(b) = %+v, want %+v", got, b)
+	}
+}
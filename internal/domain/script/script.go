@@ -11,6 +11,7 @@ type Script struct {
 	Code     string
 	Interval time.Duration
 	Enabled  bool
+	Library  bool // ライブラリ（周期実行せず、他のスクリプトから require(Name) で読み込まれる）
 }
 
 // NewScript は新しいスクリプトを作成する
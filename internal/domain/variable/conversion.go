@@ -2,6 +2,7 @@ package variable
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math"
 	"regexp"
@@ -12,6 +13,9 @@ import (
 
 // ConvertValue は値を指定されたデータ型に変換する
 func ConvertValue(value interface{}, dataType DataType) (interface{}, error) {
+	if n, ok := value.(json.Number); ok {
+		value = numberValue(n, dataType)
+	}
 	switch dataType {
 	case TypeBOOL:
 		switch v := value.(type) {
@@ -190,6 +194,20 @@ func ConvertValue(value interface{}, dataType DataType) (interface{}, error) {
 	return nil, fmt.Errorf("cannot convert %T to %s", value, dataType)
 }
 
+// numberValue は UseNumber でデコードした数値を ConvertValue が扱える型に変換する。
+// LINT/ULINT は float64 を経由すると 2^53 を超える値の精度が落ちるため文字列のまま渡す。
+func numberValue(n json.Number, dataType DataType) interface{} {
+	switch dataType {
+	case TypeLINT, TypeULINT:
+		return string(n)
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
 // ConvertValueWithResolver はresolver付きで値を再帰的に変換する
 // 構造体フィールドや配列要素の型変換を正しく行う
 func ConvertValueWithResolver(value interface{}, dataType DataType, resolver TypeResolver) (interface{}, error) {
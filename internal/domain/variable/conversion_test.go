@@ -1,6 +1,7 @@
 package variable
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 )
@@ -56,6 +57,11 @@ func TestConvertValue_IntegerTypes(t *testing.T) {
 		// LREAL
 		{float64(3.14), TypeLREAL, float64(3.14)},
 		{float32(3.14), TypeLREAL, float64(float32(3.14))},
+		// json.Number（UseNumber でデコードしたプロジェクトファイル）
+		{json.Number("-5"), TypeINT, int16(-5)},
+		{json.Number("1.5"), TypeREAL, float32(1.5)},
+		{json.Number("9007199254740993"), TypeULINT, uint64(9007199254740993)},
+		{json.Number("-9007199254740993"), TypeLINT, int64(-9007199254740993)},
 	}
 
 	for _, tc := range tests {
@@ -374,6 +374,19 @@ func (s *VariableStore) GetVariableByName(name string) (*Variable, error) {
 	return v, nil
 }
 
+// ReadValue は名前で変数の現在値を読む。
+// GetVariableByName で得た Variable の Value を直接読むと UpdateValue と競合するため、値だけが必要な場合はこちらを使う。
+func (s *VariableStore) ReadValue(name string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, exists := s.byName[name]
+	if !exists {
+		return nil, fmt.Errorf("variable %s not found", name)
+	}
+	return v.Value, nil
+}
+
 // GetAllVariables はすべての変数を取得する
 func (s *VariableStore) GetAllVariables() []*Variable {
 	s.mu.RLock()
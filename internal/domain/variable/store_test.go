@@ -269,6 +269,33 @@ func TestVariableStore_UpdateValueByName(t *testing.T) {
 	}
 }
 
+func TestVariableStore_ReadValue(t *testing.T) {
+	s := NewVariableStore()
+	_, _ = s.CreateVariable("z", TypeINT, int16(5))
+
+	// 書き込み中の goroutine と並行して読んでも競合しない（-race で確認）
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = s.UpdateValueByName("z", int16(i))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err := s.ReadValue("z"); err != nil {
+			t.Fatalf("ReadValue error: %v", err)
+		}
+	}
+	<-done
+
+	if got, _ := s.ReadValue("z"); got != int16(99) {
+		t.Errorf("ReadValue: got %v, want int16(99)", got)
+	}
+	if _, err := s.ReadValue("missing"); err == nil {
+		t.Error("ReadValue with unknown name should return error")
+	}
+}
+
 // =====================================================================
 // DeleteVariable
 // =====================================================================
@@ -15,6 +15,9 @@ type VariableBackedDataStore struct {
 	protocolType string
 	mu           sync.Mutex // 同期ループ防止用
 	syncing      bool
+
+	hookMu    sync.RWMutex
+	writeHook func(area string, address uint32, count int)
 }
 
 // NewVariableBackedDataStore は新しいVariableBackedDataStoreを作成する
@@ -45,6 +48,23 @@ func (a *VariableBackedDataStore) Detach() {
 	a.varStore.RemoveListener(a)
 }
 
+// SetWriteHook は書き込み後に呼ばれるコールバックを設定する。
+// 通信相手・ホストからの書き込みと、変数値の反映による書き込みの両方で呼ばれる。
+func (a *VariableBackedDataStore) SetWriteHook(hook func(area string, address uint32, count int)) {
+	a.hookMu.Lock()
+	a.writeHook = hook
+	a.hookMu.Unlock()
+}
+
+func (a *VariableBackedDataStore) notifyWrite(area string, address uint32, count int) {
+	a.hookMu.RLock()
+	hook := a.writeHook
+	a.hookMu.RUnlock()
+	if hook != nil {
+		hook(area, address, count)
+	}
+}
+
 // OnVariableChanged はVariableStoreからの変更通知を処理する
 // 変数値 → DataStoreへの書き込み
 func (a *VariableBackedDataStore) OnVariableChanged(v *variable.Variable, mappings []variable.ProtocolMapping, _ string, _ interface{}) {
@@ -75,6 +95,7 @@ func (a *VariableBackedDataStore) writeVariableToInner(v *variable.Variable, m *
 	if v.DataType.IsBitType() {
 		val := variable.ValueToBool(v.Value, v.DataType)
 		a.inner.WriteBit(m.MemoryArea, m.Address, val)
+		a.notifyWrite(m.MemoryArea, m.Address, 1)
 	} else if v.DataType.IsArrayType() {
 		elemType, size, err := variable.ParseArrayType(v.DataType)
 		if err != nil {
@@ -84,6 +105,7 @@ func (a *VariableBackedDataStore) writeVariableToInner(v *variable.Variable, m *
 		for i, w := range words {
 			a.inner.WriteWord(m.MemoryArea, m.Address+uint32(i), w)
 		}
+		a.notifyWrite(m.MemoryArea, m.Address, len(words))
 	} else if v.DataType.IsStructType() {
 		structDef, err := a.varStore.GetStructType(string(v.DataType))
 		if err != nil || structDef == nil {
@@ -93,11 +115,13 @@ func (a *VariableBackedDataStore) writeVariableToInner(v *variable.Variable, m *
 		for i, w := range words {
 			a.inner.WriteWord(m.MemoryArea, m.Address+uint32(i), w)
 		}
+		a.notifyWrite(m.MemoryArea, m.Address, len(words))
 	} else {
 		words := variable.ValueToWords(v.Value, v.DataType, m.Endianness)
 		for i, w := range words {
 			a.inner.WriteWord(m.MemoryArea, m.Address+uint32(i), w)
 		}
+		a.notifyWrite(m.MemoryArea, m.Address, len(words))
 	}
 }
 
@@ -203,6 +227,10 @@ func (a *VariableBackedDataStore) GetAreas() []protocol.MemoryArea {
 	return a.inner.GetAreas()
 }
 
+func (a *VariableBackedDataStore) GetArea(id string) (protocol.MemoryArea, bool) {
+	return a.inner.GetArea(id)
+}
+
 func (a *VariableBackedDataStore) ReadBit(area string, address uint32) (bool, error) {
 	return a.inner.ReadBit(area, address)
 }
@@ -224,6 +252,7 @@ func (a *VariableBackedDataStore) WriteBit(area string, address uint32, value bo
 		return err
 	}
 	go a.syncBitToVariable(area, address)
+	a.notifyWrite(area, address, 1)
 	return nil
 }
 
@@ -234,6 +263,7 @@ func (a *VariableBackedDataStore) WriteBits(area string, address uint32, values
 	for i := range values {
 		go a.syncBitToVariable(area, address+uint32(i))
 	}
+	a.notifyWrite(area, address, len(values))
 	return nil
 }
 
@@ -242,6 +272,7 @@ func (a *VariableBackedDataStore) WriteWord(area string, address uint32, value u
 		return err
 	}
 	go a.syncWordToVariable(area, address)
+	a.notifyWrite(area, address, 1)
 	return nil
 }
 
@@ -252,6 +283,7 @@ func (a *VariableBackedDataStore) WriteWords(area string, address uint32, values
 	for i := range values {
 		go a.syncWordToVariable(area, address+uint32(i))
 	}
+	a.notifyWrite(area, address, len(values))
 	return nil
 }
 
@@ -38,6 +38,15 @@ func newTestDataStore() *testDataStore {
 
 func (d *testDataStore) GetAreas() []protocol.MemoryArea { return d.areas }
 
+func (d *testDataStore) GetArea(id string) (protocol.MemoryArea, bool) {
+	for _, a := range d.areas {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return protocol.MemoryArea{}, false
+}
+
 func (d *testDataStore) ReadBit(area string, addr uint32) (bool, error) {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
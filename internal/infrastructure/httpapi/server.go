@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"strconv"
@@ -16,11 +17,12 @@ import (
 type Server struct {
 	svc    *application.PLCService
 	server *http.Server
+	stream *StreamHub
 }
 
 // NewServer は新しいHTTP APIサーバーを作成する
 func NewServer(svc *application.PLCService, port int) *Server {
-	s := &Server{svc: svc}
+	s := &Server{svc: svc, stream: NewStreamHub(svc)}
 	mux := http.NewServeMux()
 	s.registerRoutes(mux)
 	s.server = &http.Server{
@@ -37,14 +39,21 @@ func (s *Server) Start() error {
 		return fmt.Errorf("HTTP API サーバーのポートを開けません %s: %w", s.server.Addr, err)
 	}
 	go s.server.Serve(ln) //nolint:errcheck
+	s.stream.Start()
 	return nil
 }
 
 // Shutdown はHTTPサーバーをグレースフルに停止する
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.stream.Stop()
 	return s.server.Shutdown(ctx)
 }
 
+// StreamHub は WebSocket ストリームのハブを返す（通信イベントの配信先として登録する）
+func (s *Server) StreamHub() *StreamHub {
+	return s.stream
+}
+
 // Restart は新しいポートでHTTPサーバーを再起動する
 func (s *Server) Restart(port int) error {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -83,6 +92,7 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/servers/{protocolType}/status", s.handleGetServerStatus)
 	mux.HandleFunc("GET /api/servers/{protocolType}/config", s.handleGetServerConfig)
 	mux.HandleFunc("PUT /api/servers/{protocolType}/config", s.handleUpdateServerConfig)
+	mux.HandleFunc("GET /api/protocols/{protocolType}/config-schema", s.handleGetConfigJSONSchema)
 
 	// === メモリ操作 ===
 	mux.HandleFunc("GET /api/memory/{protocolType}/areas", s.handleGetMemoryAreas)
@@ -100,6 +110,9 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	// === プロジェクトエクスポート/インポート ===
 	mux.HandleFunc("GET /api/project/export", s.handleExportProject)
 	mux.HandleFunc("POST /api/project/import", s.handleImportProject)
+
+	// === ライブストリーム（WebSocket） ===
+	mux.Handle("GET /api/stream", s.stream)
 }
 
 // --- ヘルパー ---
@@ -194,6 +207,18 @@ func (s *Server) handleUpdateServerConfig(w http.ResponseWriter, r *http.Request
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (s *Server) handleGetConfigJSONSchema(w http.ResponseWriter, r *http.Request) {
+	pt := r.PathValue("protocolType")
+	schema, err := s.svc.GetConfigJSONSchema(pt)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(schema) //nolint:errcheck
+}
+
 // --- メモリ操作ハンドラー ---
 
 func (s *Server) handleGetMemoryAreas(w http.ResponseWriter, r *http.Request) {
@@ -345,12 +370,13 @@ func (s *Server) handleExportProject(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleImportProject(w http.ResponseWriter, r *http.Request) {
-	var data application.ProjectDataDTO
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+	data, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(data) {
 		writeError(w, http.StatusBadRequest, "リクエストボディが不正です")
 		return
 	}
-	if err := s.svc.ImportProject(&data); err != nil {
+	opts := application.ImportOptions{SkipChecksum: r.URL.Query().Get("skipChecksum") == "true"}
+	if err := s.svc.ImportProjectJSON(data, opts); err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
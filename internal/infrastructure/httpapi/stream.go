@@ -0,0 +1,338 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"modbus_simulator/internal/application"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// defaultStreamInterval はモニタリング値の配信周期
+	defaultStreamInterval = 200 * time.Millisecond
+	// streamSendBuffer はクライアントごとの送信キュー長（溢れた場合は破棄する）
+	streamSendBuffer = 64
+	// streamWriteTimeout は1メッセージの送信タイムアウト
+	streamWriteTimeout = 5 * time.Second
+)
+
+// streamSource はストリーム配信に必要な PLCService の機能
+type streamSource interface {
+	ReadMonitoringValues() []application.MonitoringValueDTO
+	ReadWords(protocolType, area string, address, count int) ([]int, error)
+	ReadBits(protocolType, area string, address, count int) ([]bool, error)
+}
+
+// AreaSubscription はメモリ範囲の購読指定
+type AreaSubscription struct {
+	ProtocolType string `json:"protocolType"`
+	Area         string `json:"area"`
+	Address      int    `json:"address"`
+	Count        int    `json:"count"`
+	IsBit        bool   `json:"isBit"`
+}
+
+// subscribeMessage はクライアントから送られる購読要求
+//
+//	{"type":"subscribe","allItems":true,"items":["id"],"areas":[...],"events":true}
+type subscribeMessage struct {
+	Type     string             `json:"type"`
+	AllItems bool               `json:"allItems"`
+	Items    []string           `json:"items"`
+	Areas    []AreaSubscription `json:"areas"`
+	Events   bool               `json:"events"`
+}
+
+// valuesMessage は周期配信されるモニタリング値・メモリ範囲の値
+type valuesMessage struct {
+	Type  string                           `json:"type"`
+	At    int64                            `json:"at"`
+	Items []application.MonitoringValueDTO `json:"items,omitempty"`
+	Areas []areaValues                     `json:"areas,omitempty"`
+}
+
+type areaValues struct {
+	AreaSubscription
+	Words []int  `json:"words,omitempty"`
+	Bits  []bool `json:"bits,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// commMessage は RX/TX/接続数イベント
+type commMessage struct {
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Count int    `json:"count"`
+}
+
+// streamClient は WebSocket クライアント1接続
+type streamClient struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	mu  sync.Mutex
+	sub subscribeMessage
+}
+
+func (c *streamClient) subscription() subscribeMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sub
+}
+
+func (c *streamClient) setSubscription(sub subscribeMessage) {
+	c.mu.Lock()
+	c.sub = sub
+	c.mu.Unlock()
+}
+
+// StreamHub は WebSocket クライアントを管理し、モニタリング値と通信イベントを配信する。
+// protocol.CommunicationEventEmitter を実装しているため、通信イベントの配信先として登録できる。
+type StreamHub struct {
+	source   streamSource
+	interval time.Duration
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*streamClient]struct{}
+	stopCh  chan struct{}
+	running bool
+}
+
+// NewStreamHub は新しい StreamHub を作成する
+func NewStreamHub(source streamSource) *StreamHub {
+	return &StreamHub{
+		source:   source,
+		interval: defaultStreamInterval,
+		upgrader: websocket.Upgrader{
+			// ブラウザダッシュボードからの接続を許可する（REST API と同様に CORS 制限なし）
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		clients: make(map[*streamClient]struct{}),
+	}
+}
+
+// Start は周期配信を開始する
+func (h *StreamHub) Start() {
+	h.mu.Lock()
+	if h.running {
+		h.mu.Unlock()
+		return
+	}
+	h.running = true
+	h.stopCh = make(chan struct{})
+	stopCh := h.stopCh
+	h.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				h.poll()
+			}
+		}
+	}()
+}
+
+// Stop は周期配信を停止し、全クライアントを切断する
+func (h *StreamHub) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.running {
+		close(h.stopCh)
+		h.running = false
+	}
+	for c := range h.clients {
+		c.conn.Close()
+	}
+}
+
+// ClientCount は接続中のクライアント数を返す
+func (h *StreamHub) ClientCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// ServeHTTP は WebSocket 接続を受け付ける
+func (h *StreamHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &streamClient{
+		conn: conn,
+		send: make(chan []byte, streamSendBuffer),
+	}
+
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+
+	go h.writeLoop(c)
+	h.readLoop(c)
+}
+
+// readLoop はクライアントからの購読要求を処理する（切断まで戻らない）
+func (h *StreamHub) readLoop(c *streamClient) {
+	defer h.removeClient(c)
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg subscribeMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.Type == "subscribe" {
+			c.setSubscription(msg)
+			// 購読直後に現在値を送る
+			h.sendValues(c, h.source.ReadMonitoringValues())
+		}
+	}
+}
+
+// writeLoop は送信キューのメッセージを書き出す
+func (h *StreamHub) writeLoop(c *streamClient) {
+	for data := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)) //nolint:errcheck
+		if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			c.conn.Close()
+			return
+		}
+	}
+}
+
+func (h *StreamHub) removeClient(c *streamClient) {
+	h.mu.Lock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+	h.mu.Unlock()
+	c.conn.Close()
+}
+
+// snapshotClients はロック外で扱うためにクライアント一覧をコピーする
+func (h *StreamHub) snapshotClients() []*streamClient {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	clients := make([]*streamClient, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// enqueue はクライアントの送信キューに積む（キューが溢れている場合は破棄）
+func (h *StreamHub) enqueue(c *streamClient, data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[c]; !ok {
+		return
+	}
+	select {
+	case c.send <- data:
+	default:
+	}
+}
+
+// poll はモニタリング値を1回読み取り、購読中の全クライアントへ配信する
+func (h *StreamHub) poll() {
+	clients := h.snapshotClients()
+	if len(clients) == 0 {
+		return
+	}
+	values := h.source.ReadMonitoringValues()
+	for _, c := range clients {
+		h.sendValues(c, values)
+	}
+}
+
+// sendValues はクライアントの購読内容に合わせて値を絞り込んで送る
+func (h *StreamHub) sendValues(c *streamClient, values []application.MonitoringValueDTO) {
+	sub := c.subscription()
+	msg := valuesMessage{Type: "values", At: time.Now().UnixMilli()}
+
+	if sub.AllItems {
+		msg.Items = values
+	} else if len(sub.Items) > 0 {
+		wanted := make(map[string]bool, len(sub.Items))
+		for _, id := range sub.Items {
+			wanted[id] = true
+		}
+		for _, v := range values {
+			if wanted[v.ID] {
+				msg.Items = append(msg.Items, v)
+			}
+		}
+	}
+
+	for _, a := range sub.Areas {
+		av := areaValues{AreaSubscription: a}
+		count := a.Count
+		if count <= 0 {
+			count = 1
+		}
+		var err error
+		if a.IsBit {
+			av.Bits, err = h.source.ReadBits(a.ProtocolType, a.Area, a.Address, count)
+		} else {
+			av.Words, err = h.source.ReadWords(a.ProtocolType, a.Area, a.Address, count)
+		}
+		if err != nil {
+			av.Error = err.Error()
+		}
+		msg.Areas = append(msg.Areas, av)
+	}
+
+	if len(msg.Items) == 0 && len(msg.Areas) == 0 {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	h.enqueue(c, data)
+}
+
+// broadcastEvent は通信イベントを購読中のクライアントへ配信する
+func (h *StreamHub) broadcastEvent(msg commMessage) {
+	clients := h.snapshotClients()
+	if len(clients) == 0 {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	for _, c := range clients {
+		if c.subscription().Events {
+			h.enqueue(c, data)
+		}
+	}
+}
+
+// EmitRx は受信イベントを配信する
+func (h *StreamHub) EmitRx() {
+	h.broadcastEvent(commMessage{Type: "comm", Event: "rx"})
+}
+
+// EmitTx は送信イベントを配信する
+func (h *StreamHub) EmitTx() {
+	h.broadcastEvent(commMessage{Type: "comm", Event: "tx"})
+}
+
+// EmitConnection は接続数変更イベントを配信する
+func (h *StreamHub) EmitConnection(count int) {
+	h.broadcastEvent(commMessage{Type: "comm", Event: "connection", Count: count})
+}
@@ -0,0 +1,238 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"modbus_simulator/internal/application"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeStreamSource はテスト用の streamSource 実装
+type fakeStreamSource struct {
+	mu     sync.Mutex
+	values []application.MonitoringValueDTO
+	words  []int
+}
+
+func (f *fakeStreamSource) ReadMonitoringValues() []application.MonitoringValueDTO {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]application.MonitoringValueDTO, len(f.values))
+	copy(result, f.values)
+	return result
+}
+
+func (f *fakeStreamSource) ReadWords(_, _ string, address, count int) ([]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.words[address : address+count], nil
+}
+
+func (f *fakeStreamSource) ReadBits(_, _ string, _, count int) ([]bool, error) {
+	return make([]bool, count), nil
+}
+
+func (f *fakeStreamSource) setValue(id string, value int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.values {
+		if f.values[i].ID == id {
+			f.values[i].Value = value
+		}
+	}
+}
+
+func newTestHub(t *testing.T, source *fakeStreamSource) (*StreamHub, string) {
+	t.Helper()
+	hub := NewStreamHub(source)
+	hub.interval = 10 * time.Millisecond
+	srv := httptest.NewServer(hub)
+	t.Cleanup(func() {
+		hub.Stop()
+		srv.Close()
+	})
+	return hub, "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func subscribe(t *testing.T, conn *websocket.Conn, msg subscribeMessage) {
+	t.Helper()
+	msg.Type = "subscribe"
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+}
+
+// readMessage は指定タイプのメッセージを受信するまで読み進める
+func readMessage(t *testing.T, conn *websocket.Conn, msgType string) map[string]interface{} {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage failed: %v", err)
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if msg["type"] == msgType {
+			return msg
+		}
+	}
+}
+
+func TestStreamHub_SubscribeItems(t *testing.T) {
+	source := &fakeStreamSource{values: []application.MonitoringValueDTO{
+		{ID: "a", Value: 1},
+		{ID: "b", Value: 2},
+	}}
+	_, url := newTestHub(t, source)
+	conn := dial(t, url)
+
+	subscribe(t, conn, subscribeMessage{Items: []string{"b"}})
+
+	// 購読直後に現在値が届き、購読した項目のみが含まれる
+	msg := readMessage(t, conn, "values")
+	items := msg["items"].([]interface{})
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %v", items)
+	}
+	item := items[0].(map[string]interface{})
+	if item["id"] != "b" || item["value"] != float64(2) {
+		t.Errorf("unexpected item: %v", item)
+	}
+}
+
+func TestStreamHub_BroadcastOnPoll(t *testing.T) {
+	source := &fakeStreamSource{values: []application.MonitoringValueDTO{
+		{ID: "a", Value: 1},
+	}}
+	hub, url := newTestHub(t, source)
+	hub.Start()
+
+	conn1 := dial(t, url)
+	conn2 := dial(t, url)
+	subscribe(t, conn1, subscribeMessage{AllItems: true})
+	subscribe(t, conn2, subscribeMessage{AllItems: true})
+
+	source.setValue("a", 42)
+
+	// 周期配信で全クライアントに新しい値が届く
+	for _, conn := range []*websocket.Conn{conn1, conn2} {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			msg := readMessage(t, conn, "values")
+			item := msg["items"].([]interface{})[0].(map[string]interface{})
+			if item["value"] == float64(42) {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("updated value was not broadcast")
+			}
+		}
+	}
+}
+
+func TestStreamHub_SubscribeAreas(t *testing.T) {
+	source := &fakeStreamSource{words: []int{10, 20, 30, 40}}
+	_, url := newTestHub(t, source)
+	conn := dial(t, url)
+
+	subscribe(t, conn, subscribeMessage{Areas: []AreaSubscription{
+		{ProtocolType: "modbus-tcp", Area: "holdingRegisters", Address: 1, Count: 2},
+	}})
+
+	msg := readMessage(t, conn, "values")
+	areas := msg["areas"].([]interface{})
+	if len(areas) != 1 {
+		t.Fatalf("expected 1 area, got %v", areas)
+	}
+	words := areas[0].(map[string]interface{})["words"].([]interface{})
+	if len(words) != 2 || words[0] != float64(20) || words[1] != float64(30) {
+		t.Errorf("unexpected words: %v", words)
+	}
+}
+
+func TestStreamHub_CommEvents(t *testing.T) {
+	source := &fakeStreamSource{}
+	hub, url := newTestHub(t, source)
+	subscribed := dial(t, url)
+	unsubscribed := dial(t, url)
+
+	subscribe(t, subscribed, subscribeMessage{Events: true})
+	subscribe(t, unsubscribed, subscribeMessage{})
+
+	// 購読要求が処理されるまで待つ
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.ClientCount() < 2 || !hub.snapshotHasEventsSubscriber() {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	hub.EmitRx()
+	hub.EmitConnection(3)
+
+	msg := readMessage(t, subscribed, "comm")
+	if msg["event"] != "rx" {
+		t.Errorf("expected rx event, got %v", msg)
+	}
+	msg = readMessage(t, subscribed, "comm")
+	if msg["event"] != "connection" || msg["count"] != float64(3) {
+		t.Errorf("expected connection event, got %v", msg)
+	}
+
+	// events を購読していないクライアントには届かない
+	unsubscribed.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, _, err := unsubscribed.ReadMessage(); err == nil {
+		t.Error("unexpected message for client without events subscription")
+	}
+}
+
+func TestStreamHub_RemoveClientOnClose(t *testing.T) {
+	hub, url := newTestHub(t, &fakeStreamSource{})
+	conn := dial(t, url)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.ClientCount() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("client was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	conn.Close()
+	for hub.ClientCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("client was not removed after close")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// snapshotHasEventsSubscriber は events を購読しているクライアントがいるかを返す
+func (h *StreamHub) snapshotHasEventsSubscriber() bool {
+	for _, c := range h.snapshotClients() {
+		if c.subscription().Events {
+			return true
+		}
+	}
+	return false
+}
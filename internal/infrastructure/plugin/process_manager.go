@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,6 +19,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	pb "modbus_simulator/pb/pluginpb"
+	"modbus_simulator/pkg/logging"
 )
 
 // ManifestCapabilities は plugin.json のプロトコル機能情報を表す
@@ -94,6 +96,7 @@ type PluginProcessManager struct {
 	plugins   []*PluginProcess
 	hostAddr  string  // HostGrpcServer のアドレス（プラグイン起動時に渡す）
 	jobHandle uintptr // Windows Job Object ハンドル（ホスト終了時に子プロセスを自動終了）
+	logger    *slog.Logger
 }
 
 // NewPluginProcessManager は PluginProcessManager を作成する
@@ -101,6 +104,27 @@ func NewPluginProcessManager(hostGrpcAddr string) *PluginProcessManager {
 	return &PluginProcessManager{
 		hostAddr:  hostGrpcAddr,
 		jobHandle: initProcessJobObject(),
+		logger:    logging.Default(),
+	}
+}
+
+// SetLogger はプラグインの stderr 出力を転送するロガーを設定する（Launch より前に呼ぶ）
+func (m *PluginProcessManager) SetLogger(logger *slog.Logger) {
+	m.mu.Lock()
+	m.logger = logger
+	m.mu.Unlock()
+}
+
+// forwardLog はプラグインの stderr の1行をロガーへ転送する。
+// logging.UseJSON で出力された行はレベルと属性を復元し、それ以外の行は Info として扱う。
+func forwardLog(logger *slog.Logger, line string) {
+	ctx := context.Background()
+	r, ok := logging.DecodeJSONRecord(line)
+	if !ok {
+		r = slog.NewRecord(time.Now(), slog.LevelInfo, line, 0)
+	}
+	if h := logger.Handler(); h.Enabled(ctx, r.Level) {
+		_ = h.Handle(ctx, r)
 	}
 }
 
@@ -206,14 +230,16 @@ func (m *PluginProcessManager) Launch(pluginPath string, extraArgs ...string) (*
 	}
 	proc.cmd = cmd
 
-	// プラグインの stderr をホストプロセスが明示的に読み取って転送する。
+	// プラグインの stderr をホストプロセスが明示的に読み取り、診断ログへ転送する。
 	// Windows では CREATE_NO_WINDOW とハンドル継承の相性が悪いため、
 	// cmd.Stderr = os.Stderr ではなくパイプ経由で確実に転送する。
-	pluginName := filepath.Base(pluginPath)
+	m.mu.RLock()
+	logger := m.logger.With("plugin", filepath.Base(pluginPath))
+	m.mu.RUnlock()
 	go func() {
 		scanner := bufio.NewScanner(stderr)
 		for scanner.Scan() {
-			fmt.Fprintf(os.Stderr, "[%s] %s\n", pluginName, scanner.Text())
+			forwardLog(logger, scanner.Text())
 		}
 	}()
 
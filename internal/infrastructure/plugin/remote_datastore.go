@@ -13,12 +13,20 @@ import (
 // RemoteDataStore は gRPC クライアントを通じてプラグインプロセスの DataStore を実装する
 type RemoteDataStore struct {
 	client pb.DataStoreServiceClient
+	// unitID は読み書きする UnitID のメモリ（0 の場合は共有メモリ）。
+	// Snapshot・Restore・ClearAll・SubscribeChanges は常に共有メモリが対象
+	unitID uint32
 }
 
 func NewRemoteDataStore(client pb.DataStoreServiceClient) *RemoteDataStore {
 	return &RemoteDataStore{client: client}
 }
 
+// NewRemoteUnitDataStore は UnitID 別メモリを読み書きする RemoteDataStore を作成する
+func NewRemoteUnitDataStore(client pb.DataStoreServiceClient, unitID uint8) *RemoteDataStore {
+	return &RemoteDataStore{client: client, unitID: uint32(unitID)}
+}
+
 func (d *RemoteDataStore) GetAreas() []protocol.MemoryArea {
 	resp, err := d.client.GetAreas(backgroundCtx(), &pb.Empty{})
 	if err != nil {
@@ -39,8 +47,18 @@ func (d *RemoteDataStore) GetAreas() []protocol.MemoryArea {
 	return areas
 }
 
+// GetArea は指定IDのメモリエリアを返す（プラグイン側に単独取得の RPC がないため GetAreas から探す）
+func (d *RemoteDataStore) GetArea(id string) (protocol.MemoryArea, bool) {
+	for _, a := range d.GetAreas() {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return protocol.MemoryArea{}, false
+}
+
 func (d *RemoteDataStore) ReadBit(area string, address uint32) (bool, error) {
-	resp, err := d.client.ReadBit(backgroundCtx(), &pb.ReadBitRequest{Area: area, Address: address})
+	resp, err := d.client.ReadBit(backgroundCtx(), &pb.ReadBitRequest{Area: area, Address: address, UnitId: d.unitID})
 	if err != nil {
 		return false, err
 	}
@@ -48,7 +66,7 @@ func (d *RemoteDataStore) ReadBit(area string, address uint32) (bool, error) {
 }
 
 func (d *RemoteDataStore) WriteBit(area string, address uint32, value bool) error {
-	_, err := d.client.WriteBit(backgroundCtx(), &pb.WriteBitRequest{Area: area, Address: address, Value: value})
+	_, err := d.client.WriteBit(backgroundCtx(), &pb.WriteBitRequest{Area: area, Address: address, Value: value, UnitId: d.unitID})
 	return err
 }
 
@@ -57,6 +75,7 @@ func (d *RemoteDataStore) ReadBits(area string, address uint32, count uint16) ([
 		Area:    area,
 		Address: address,
 		Count:   uint32(count),
+		UnitId:  d.unitID,
 	})
 	if err != nil {
 		return nil, err
@@ -69,12 +88,13 @@ func (d *RemoteDataStore) WriteBits(area string, address uint32, values []bool)
 		Area:    area,
 		Address: address,
 		Values:  values,
+		UnitId:  d.unitID,
 	})
 	return err
 }
 
 func (d *RemoteDataStore) ReadWord(area string, address uint32) (uint16, error) {
-	resp, err := d.client.ReadWord(backgroundCtx(), &pb.ReadWordRequest{Area: area, Address: address})
+	resp, err := d.client.ReadWord(backgroundCtx(), &pb.ReadWordRequest{Area: area, Address: address, UnitId: d.unitID})
 	if err != nil {
 		return 0, err
 	}
@@ -86,6 +106,7 @@ func (d *RemoteDataStore) WriteWord(area string, address uint32, value uint16) e
 		Area:    area,
 		Address: address,
 		Value:   uint32(value),
+		UnitId:  d.unitID,
 	})
 	return err
 }
@@ -95,6 +116,7 @@ func (d *RemoteDataStore) ReadWords(area string, address uint32, count uint16) (
 		Area:    area,
 		Address: address,
 		Count:   uint32(count),
+		UnitId:  d.unitID,
 	})
 	if err != nil {
 		return nil, err
@@ -115,6 +137,7 @@ func (d *RemoteDataStore) WriteWords(area string, address uint32, values []uint1
 		Area:    area,
 		Address: address,
 		Values:  pbValues,
+		UnitId:  d.unitID,
 	})
 	return err
 }
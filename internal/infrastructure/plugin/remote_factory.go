@@ -176,6 +176,19 @@ func (f *LazyRemoteServerFactory) GetConfigFields(variantID string) []protocol.C
 	return fields
 }
 
+// TransportFields はプラグインが GetMetadata で返す待ち受けに関わる設定フィールド名を返す（全バリアント共通）。
+// 取得できない場合は nil を返し、ホストは全フィールドを通信路の設定として扱う。
+func (f *LazyRemoteServerFactory) TransportFields(_ string) []string {
+	if err := f.EnsureStarted(); err != nil {
+		return nil
+	}
+	resp, err := f.client.GetMetadata(backgroundCtx(), &pb.Empty{})
+	if err != nil {
+		return nil
+	}
+	return resp.TransportFields
+}
+
 func (f *LazyRemoteServerFactory) CreateDataStore() protocol.DataStore {
 	f.mu.Lock()
 	conn := f.conn
@@ -22,6 +22,9 @@ type RemoteVariableChangeListener struct {
 
 	mu      sync.Mutex
 	syncing bool
+
+	hookMu    sync.RWMutex
+	writeHook func(area string, address uint32, count int)
 }
 
 // NewRemoteVariableChangeListener は RemoteVariableChangeListener を作成し、
@@ -47,6 +50,24 @@ func (l *RemoteVariableChangeListener) Detach() {
 	l.varStore.RemoveListener(l)
 }
 
+// SetWriteHook はプラグイン DataStore への書き込み後に呼ばれるコールバックを設定する。
+// 通信相手からの書き込み（DataChange ストリーム）と、変数値の反映による書き込みで呼ばれる。
+// ホスト（UI/スクリプト）からのメモリ直接書き込みは呼び出し元で通知すること。
+func (l *RemoteVariableChangeListener) SetWriteHook(hook func(area string, address uint32, count int)) {
+	l.hookMu.Lock()
+	l.writeHook = hook
+	l.hookMu.Unlock()
+}
+
+func (l *RemoteVariableChangeListener) notifyWrite(area string, address uint32, count int) {
+	l.hookMu.RLock()
+	hook := l.writeHook
+	l.hookMu.RUnlock()
+	if hook != nil {
+		hook(area, address, count)
+	}
+}
+
 // OnVariableChanged は VariableStore からの変更通知を処理する（Variable → Plugin DataStore）
 func (l *RemoteVariableChangeListener) OnVariableChanged(v *variable.Variable, mappings []variable.ProtocolMapping, _ string, _ interface{}) {
 	l.mu.Lock()
@@ -96,6 +117,12 @@ func (l *RemoteVariableChangeListener) StartChangeSubscription(ctx context.Conte
 		l.mu.Lock()
 		l.syncing = false
 		l.mu.Unlock()
+
+		if change.IsBit {
+			l.notifyWrite(change.Area, change.Address, len(change.BitValues))
+		} else {
+			l.notifyWrite(change.Area, change.Address, len(change.Values))
+		}
 	}
 }
 
@@ -156,24 +183,32 @@ func (l *RemoteVariableChangeListener) syncBitChangeToVariable(area string, addr
 func (l *RemoteVariableChangeListener) writeVariableToRemote(v *variable.Variable, m *variable.ProtocolMapping) {
 	if v.DataType.IsBitType() {
 		val := variable.ValueToBool(v.Value, v.DataType)
-		_ = l.remoteDS.WriteBit(m.MemoryArea, m.Address, val)
+		if l.remoteDS.WriteBit(m.MemoryArea, m.Address, val) == nil {
+			l.notifyWrite(m.MemoryArea, m.Address, 1)
+		}
 	} else if v.DataType.IsArrayType() {
 		elemType, size, err := variable.ParseArrayType(v.DataType)
 		if err != nil {
 			return
 		}
 		words := variable.ArrayValueToWords(v.Value, elemType, size, m.Endianness, l.varStore)
-		_ = l.remoteDS.WriteWords(m.MemoryArea, m.Address, words)
+		if l.remoteDS.WriteWords(m.MemoryArea, m.Address, words) == nil {
+			l.notifyWrite(m.MemoryArea, m.Address, len(words))
+		}
 	} else if v.DataType.IsStructType() {
 		structDef, err := l.varStore.GetStructType(string(v.DataType))
 		if err != nil || structDef == nil {
 			return
 		}
 		words := variable.StructValueToWords(v.Value, structDef, m.Endianness, l.varStore)
-		_ = l.remoteDS.WriteWords(m.MemoryArea, m.Address, words)
+		if l.remoteDS.WriteWords(m.MemoryArea, m.Address, words) == nil {
+			l.notifyWrite(m.MemoryArea, m.Address, len(words))
+		}
 	} else {
 		words := variable.ValueToWords(v.Value, v.DataType, m.Endianness)
-		_ = l.remoteDS.WriteWords(m.MemoryArea, m.Address, words)
+		if l.remoteDS.WriteWords(m.MemoryArea, m.Address, words) == nil {
+			l.notifyWrite(m.MemoryArea, m.Address, len(words))
+		}
 	}
 }
 
@@ -3,7 +3,9 @@ package plugin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -21,6 +23,20 @@ type RemoteProtocolServer struct {
 	hostGrpcAddr string
 }
 
+// プラグインが対応していない機能は RPC がエラーになり、各メソッドは空の結果を返す
+var (
+	_ protocol.ProtocolServer       = (*RemoteProtocolServer)(nil)
+	_ protocol.ErrorReporter        = (*RemoteProtocolServer)(nil)
+	_ protocol.UnitMemoryAccessor   = (*RemoteProtocolServer)(nil)
+	_ protocol.ClientLister         = (*RemoteProtocolServer)(nil)
+	_ protocol.AccessCounter        = (*RemoteProtocolServer)(nil)
+	_ protocol.FIFOQueuer           = (*RemoteProtocolServer)(nil)
+	_ protocol.CommEventCounter     = (*RemoteProtocolServer)(nil)
+	_ protocol.BusySimulator        = (*RemoteProtocolServer)(nil)
+	_ protocol.TrafficReporter      = (*RemoteProtocolServer)(nil)
+	_ protocol.UnitActivityReporter = (*RemoteProtocolServer)(nil)
+)
+
 func NewRemoteProtocolServer(client pb.PluginServiceClient, conn *grpc.ClientConn, config protocol.ProtocolConfig) *RemoteProtocolServer {
 	return &RemoteProtocolServer{
 		pluginClient: client,
@@ -67,6 +83,18 @@ func (s *RemoteProtocolServer) Status() protocol.ServerStatus {
 	}
 }
 
+// LastError はプラグイン側サーバーがエラー状態の場合にその原因を返す
+func (s *RemoteProtocolServer) LastError() error {
+	resp, err := s.pluginClient.GetStatus(backgroundCtx(), &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	if resp.ErrorMessage == "" {
+		return nil
+	}
+	return errors.New(resp.ErrorMessage)
+}
+
 func (s *RemoteProtocolServer) ProtocolType() protocol.ProtocolType {
 	return s.config.ProtocolType()
 }
@@ -76,8 +104,18 @@ func (s *RemoteProtocolServer) Config() protocol.ProtocolConfig {
 }
 
 func (s *RemoteProtocolServer) UpdateConfig(config protocol.ProtocolConfig) error {
-	// 呼び出し元（PLCService）がサーバー起動中の更新を禁止しているため、
-	// ここに到達する時点でサーバーは常に停止中。ローカルの config を更新するのみ。
+	// PLCService は通信路の設定が変わらない場合だけ呼び出すため、停止中・実行中ともプラグイン側に反映させる
+	// （停止中にエリアの点数などを変更した場合も、次の Start を待たずにメモリに反映される）。
+	rc, ok := config.(*remoteProtocolConfig)
+	if !ok {
+		return fmt.Errorf("設定の型が不正: %T", config)
+	}
+	if _, err := s.pluginClient.UpdateConfig(backgroundCtx(), &pb.UpdateConfigRequest{
+		VariantId:    rc.variantID,
+		SettingsJson: rc.settingsJSON,
+	}); err != nil {
+		return err
+	}
 	s.config = config
 	return nil
 }
@@ -117,6 +155,115 @@ func (s *RemoteProtocolServer) SetDisabledUnitIDs(ids []uint8) {
 	_, _ = s.pluginClient.SetDisabledUnitIDs(backgroundCtx(), &pb.SetDisabledUnitIDsRequest{Ids: pbIDs})
 }
 
+// UnitDataStore は protocol.UnitMemoryAccessor を満たすためのメソッド。
+// プラグイン側で指定 UnitID のリクエストが使うメモリを読み書きする
+func (s *RemoteProtocolServer) UnitDataStore(unitId uint8) protocol.DataStore {
+	return NewRemoteUnitDataStore(pb.NewDataStoreServiceClient(s.conn), unitId)
+}
+
+// GetClients は protocol.ClientLister を満たすためのメソッド。
+// プラグイン側で接続中のクライアント一覧を取得する（取得できない場合は nil）
+func (s *RemoteProtocolServer) GetClients() []protocol.ClientInfo {
+	resp, err := s.pluginClient.GetConnections(backgroundCtx(), &pb.Empty{})
+	if err != nil {
+		return nil
+	}
+	clients := make([]protocol.ClientInfo, len(resp.Clients))
+	for i, c := range resp.Clients {
+		clients[i] = protocol.ClientInfo{
+			RemoteAddr:   c.RemoteAddr,
+			UnitID:       uint8(c.UnitId),
+			LastActivity: time.UnixMilli(c.LastActivityMs),
+		}
+	}
+	return clients
+}
+
+// GetAccessCounts は protocol.AccessCounter を満たすためのメソッド。
+// プラグイン側で area のアドレスごとの読み書き回数を取得する（取得できない場合は nil）
+func (s *RemoteProtocolServer) GetAccessCounts(area string) []protocol.AccessCount {
+	resp, err := s.pluginClient.GetAccessCounts(backgroundCtx(), &pb.GetAccessCountsRequest{Area: area})
+	if err != nil {
+		return nil
+	}
+	counts := make([]protocol.AccessCount, len(resp.Counts))
+	for i, c := range resp.Counts {
+		counts[i] = protocol.AccessCount{Address: c.Address, Reads: c.Reads, Writes: c.Writes}
+	}
+	return counts
+}
+
+// ResetAccessCounts は protocol.AccessCounter を満たすためのメソッド
+func (s *RemoteProtocolServer) ResetAccessCounts() {
+	_, _ = s.pluginClient.ResetAccessCounts(backgroundCtx(), &pb.Empty{})
+}
+
+// EnqueueFIFO は protocol.FIFOQueuer を満たすためのメソッド。
+// プラグイン側のサーバーが持つ FC 0x18 のキューに値を追加する
+func (s *RemoteProtocolServer) EnqueueFIFO(pointer, value uint16) error {
+	_, err := s.pluginClient.EnqueueFIFO(backgroundCtx(), &pb.EnqueueFIFORequest{
+		Pointer: uint32(pointer),
+		Value:   uint32(value),
+	})
+	return err
+}
+
+// CommEventCount は protocol.CommEventCounter を満たすためのメソッド。
+// プラグイン側のサーバーが数えた正常応答数を返す
+func (s *RemoteProtocolServer) CommEventCount() (uint16, error) {
+	resp, err := s.pluginClient.GetCommEventCount(backgroundCtx(), &pb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+	return uint16(resp.Count), nil
+}
+
+// ClearCommEventCounter は protocol.CommEventCounter を満たすためのメソッド
+func (s *RemoteProtocolServer) ClearCommEventCounter() error {
+	_, err := s.pluginClient.ClearCommEventCounter(backgroundCtx(), &pb.Empty{})
+	return err
+}
+
+// SetBusy は protocol.BusySimulator を満たすためのメソッド。
+// プラグイン側のサーバーに d の間ビジー例外を返させる
+func (s *RemoteProtocolServer) SetBusy(d time.Duration) error {
+	_, err := s.pluginClient.SetBusy(backgroundCtx(), &pb.SetBusyRequest{DurationMs: d.Milliseconds()})
+	return err
+}
+
+// TrafficStats は protocol.TrafficReporter を満たすためのメソッド。
+// プラグイン側のサーバーが集計した通信統計を返す
+func (s *RemoteProtocolServer) TrafficStats() (protocol.TrafficSnapshot, error) {
+	resp, err := s.pluginClient.GetTrafficStats(backgroundCtx(), &pb.Empty{})
+	if err != nil {
+		return protocol.TrafficSnapshot{}, err
+	}
+	snap := protocol.TrafficSnapshot{
+		Requests:         resp.Requests,
+		Exceptions:       resp.Exceptions,
+		LastFunctionCode: uint8(resp.LastFunctionCode),
+		Connections:      int(resp.Connections),
+	}
+	if resp.LastRequestAtMs != 0 {
+		snap.LastRequestAt = time.UnixMilli(resp.LastRequestAtMs)
+	}
+	return snap, nil
+}
+
+// UnitActivity は protocol.UnitActivityReporter を満たすためのメソッド。
+// プラグイン側のサーバーが追跡している UnitID ごとの最終ポーリング時刻を返す
+func (s *RemoteProtocolServer) UnitActivity() (map[uint8]time.Time, error) {
+	resp, err := s.pluginClient.GetUnitActivity(backgroundCtx(), &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	activity := make(map[uint8]time.Time, len(resp.Units))
+	for _, u := range resp.Units {
+		activity[uint8(u.UnitId)] = time.UnixMilli(u.LastActivityMs)
+	}
+	return activity, nil
+}
+
 // ConfigSettingsToMap は設定を JSON から map に変換するユーティリティ
 func configSettingsFromJSON(settingsJSON string) map[string]interface{} {
 	var result map[string]interface{}
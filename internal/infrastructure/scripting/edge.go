@@ -0,0 +1,63 @@
+package scripting
+
+import (
+	"fmt"
+
+	"github.com/dop251/goja"
+)
+
+// エッジ検出の結果
+const (
+	edgeRising  = "rising"
+	edgeFalling = "falling"
+	edgeNone    = "none"
+)
+
+// detectEdge は前回値と比較してエッジを判定し、今回値を記録する。
+// 前回値はスクリプトごとに (プロトコル, エリア, アドレス) 単位で保持し、初回は "none" を返す。
+func (e *ScriptEngine) detectEdge(scriptID, protocolType, area string, address int, value bool) string {
+	key := fmt.Sprintf("%s/%s/%d", protocolType, area, address)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.edges == nil {
+		e.edges = make(map[string]map[string]bool)
+	}
+	prevs, ok := e.edges[scriptID]
+	if !ok {
+		prevs = make(map[string]bool)
+		e.edges[scriptID] = prevs
+	}
+	prev, seen := prevs[key]
+	prevs[key] = value
+
+	switch {
+	case !seen || prev == value:
+		return edgeNone
+	case value:
+		return edgeRising
+	default:
+		return edgeFalling
+	}
+}
+
+// clearEdges はスクリプトのエッジ検出状態を破棄する（e.mu を保持した状態で呼ぶこと）
+func (e *ScriptEngine) clearEdges(scriptID string) {
+	delete(e.edges, scriptID)
+}
+
+// registerEdgeFunction は plc.edge を登録する
+func (e *ScriptEngine) registerEdgeFunction(vm *goja.Runtime, plc *goja.Object, scriptID string, memory MemoryAccessor) {
+	// edge(area, address[, protocolType]) - 前回呼び出し時からのビットの変化を返す
+	// 例: if (plc.edge("coils", 0) === "rising") { ... }
+	plc.Set("edge", func(call goja.FunctionCall) goja.Value {
+		area := call.Argument(0).String()
+		address := int(call.Argument(1).ToInteger())
+		protocolType := optionalString(call.Argument(2))
+		value, err := memory.ReadBit(protocolType, area, address)
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return vm.ToValue(e.detectEdge(scriptID, protocolType, area, address, value))
+	})
+}
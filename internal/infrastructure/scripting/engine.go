@@ -3,6 +3,7 @@ package scripting
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"strings"
 	"sync"
@@ -10,6 +11,7 @@ import (
 
 	"modbus_simulator/internal/domain/script"
 	"modbus_simulator/internal/domain/variable"
+	"modbus_simulator/pkg/logging"
 
 	"github.com/dop251/goja"
 )
@@ -34,14 +36,31 @@ type ScriptEngine struct {
 	scripts       map[string]*runningScript
 	consoleLogs   []ConsoleLogEntry
 	onLogAdded    func(ConsoleLogEntry)
+	memory        MemoryAccessor
+	stats         StatsProvider
+	logger        *slog.Logger
+	maxRuntime    time.Duration // 1回の実行の最大実行時間（0 は無制限）
+	runLock       sync.Locker   // 1回の実行の間保持するロック（nil の場合は何もしない）
+
+	// libraries は require で読み込めるライブラリスクリプト（名前 → コンパイル済みプログラム）
+	libraries map[string]*library
+
+	// edges は plc.edge の前回値（スクリプトID → "プロトコル/エリア/アドレス" → 値）
+	edges map[string]map[string]bool
 }
 
 type runningScript struct {
 	script    *script.Script
-	cancel    context.CancelFunc
+	cancel    context.CancelFunc // 周期実行の停止関数（一時停止中は nil）
+	done      chan struct{}      // 周期実行ゴルーチンの終了通知（未開始なら nil）
 	vm        *goja.Runtime
+	program   *goja.Program
+	paused    bool
 	lastError string
 	errorAt   time.Time
+
+	// runMu は同一VMでの並行実行（周期実行とステップ実行の競合）を防ぐ
+	runMu sync.Mutex
 }
 
 // NewScriptEngine は新しいスクリプトエンジンを作成する
@@ -49,7 +68,45 @@ func NewScriptEngine(varStore *variable.VariableStore) *ScriptEngine {
 	return &ScriptEngine{
 		variableStore: varStore,
 		scripts:       make(map[string]*runningScript),
+		logger:        logging.Default().With("component", "script"),
+		maxRuntime:    defaultMaxRuntime,
+	}
+}
+
+// SetLogger はロガーを設定する（nil の場合は出力しない）
+func (e *ScriptEngine) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = logging.Discard()
 	}
+	e.mu.Lock()
+	e.logger = logger.With("component", "script")
+	e.mu.Unlock()
+}
+
+func (e *ScriptEngine) log() *slog.Logger {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.logger
+}
+
+// SetRunLock は1回の実行の間保持するロックを設定する。
+// 呼び出し側はこのロックを取ることで、実行の途中の状態を観測しないようにできる。
+func (e *ScriptEngine) SetRunLock(l sync.Locker) {
+	e.mu.Lock()
+	e.runLock = l
+	e.mu.Unlock()
+}
+
+// lockRun は runLock を取得し、解放する関数を返す
+func (e *ScriptEngine) lockRun() (unlock func()) {
+	e.mu.Lock()
+	l := e.runLock
+	e.mu.Unlock()
+	if l == nil {
+		return func() {}
+	}
+	l.Lock()
+	return l.Unlock
 }
 
 // SetOnLogAdded はコンソールログ追加時のコールバックを設定する
@@ -71,7 +128,7 @@ func (e *ScriptEngine) createVM(scriptID, scriptName string) *goja.Runtime {
 			parts[i] = fmt.Sprintf("%v", arg.Export())
 		}
 		message := strings.Join(parts, " ")
-		fmt.Printf("[%s] %s\n", scriptName, message)
+		e.log().Info("console.log", "scriptId", scriptID, "script", scriptName, "message", message)
 		entry := ConsoleLogEntry{
 			ScriptID:   scriptID,
 			ScriptName: scriptName,
@@ -92,12 +149,15 @@ func (e *ScriptEngine) createVM(scriptID, scriptName string) *goja.Runtime {
 	})
 	vm.Set("console", console)
 
+	// ライブラリスクリプトの読み込み
+	e.registerRequireFunction(vm)
+
 	// PLCオブジェクト - 変数アクセス用
 	plc := vm.NewObject()
 
 	// addConsoleWarn はコンソールログに警告を追加するヘルパー
 	addConsoleWarn := func(msg string) {
-		fmt.Printf("[WARN][%s] %s\n", scriptName, msg)
+		e.log().Warn(msg, "scriptId", scriptID, "script", scriptName)
 		entry := ConsoleLogEntry{
 			ScriptID:   scriptID,
 			ScriptName: scriptName,
@@ -372,6 +432,23 @@ func (e *ScriptEngine) createVM(scriptID, scriptName string) *goja.Runtime {
 		})
 	}
 
+	// メモリエリア直接操作
+	if e.memory != nil {
+		registerMemoryFunctions(vm, plc, e.memory)
+		e.registerEdgeFunction(vm, plc, scriptID, e.memory)
+	}
+
+	// 通信統計
+	if e.stats != nil {
+		registerStatsFunction(vm, plc, e.stats)
+	}
+
+	// 実行の一時停止
+	registerSleepFunction(plc)
+
+	// ワード列⇔バイト列の変換
+	registerPackFunctions(vm, plc)
+
 	// TIME/DATE型ユーティリティ（文字列⇔数値変換のみ）
 
 	// parseTime("T#1h30m45s") -> ミリ秒(number)
@@ -469,6 +546,35 @@ func toJSCompatibleValue(value any) any {
 	}
 }
 
+// compileProgram はスクリプトをIIFEでラップしてコンパイルする（const/letの再宣言エラーを防止）
+func compileProgram(name, code string) (*goja.Program, error) {
+	wrappedCode := "(function(){\n" + code + "\n})();"
+	program, err := goja.Compile(name, wrappedCode, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile script: %w", err)
+	}
+	return program, nil
+}
+
+// CheckSyntax はスクリプトを実行せずにコンパイルのみ行い、構文エラーがあれば返す
+func (e *ScriptEngine) CheckSyntax(name, code string) error {
+	_, err := compileProgram(name, code)
+	return err
+}
+
+// compileScript はスクリプトをコンパイルし、専用VMを持つ runningScript を作成する
+func (e *ScriptEngine) compileScript(s *script.Script) (*runningScript, error) {
+	program, err := compileProgram(s.Name, s.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &runningScript{
+		script:  s,
+		vm:      e.createVM(s.ID, s.Name),
+		program: program,
+	}, nil
+}
+
 // StartScript はスクリプトを開始する
 func (e *ScriptEngine) StartScript(s *script.Script) error {
 	e.mu.Lock()
@@ -476,31 +582,110 @@ func (e *ScriptEngine) StartScript(s *script.Script) error {
 
 	// 既に実行中の場合は停止
 	if existing, ok := e.scripts[s.ID]; ok {
-		existing.cancel()
+		existing.stopTicker()
 		delete(e.scripts, s.ID)
 	}
 
-	vm := e.createVM(s.ID, s.Name)
-
-	// スクリプトをIIFEでラップしてコンパイル（const/letの再宣言エラーを防止）
-	wrappedCode := "(function(){\n" + s.Code + "\n})();"
-	program, err := goja.Compile(s.Name, wrappedCode, false)
+	rs, err := e.compileScript(s)
 	if err != nil {
-		return fmt.Errorf("failed to compile script: %w", err)
+		return err
 	}
+	e.scripts[s.ID] = rs
+	e.startTicker(rs)
+	return nil
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+// LoadScript はスクリプトをコンパイルし、周期実行せずに一時停止状態で登録する。
+// 既に登録済みの場合は何もしない。StepScript で1周期ずつ実行できる。
+func (e *ScriptEngine) LoadScript(s *script.Script) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	rs := &runningScript{
-		script: s,
-		cancel: cancel,
-		vm:     vm,
+	if _, ok := e.scripts[s.ID]; ok {
+		return nil
 	}
+	rs, err := e.compileScript(s)
+	if err != nil {
+		return err
+	}
+	rs.paused = true
 	e.scripts[s.ID] = rs
+	return nil
+}
+
+// StepScript は登録済みスクリプトを1周期分だけ同期実行する。
+// 周期実行と同じVMを使うため、JSのグローバル状態は引き継がれる。
+func (e *ScriptEngine) StepScript(scriptID string) error {
+	e.mu.Lock()
+	rs, ok := e.scripts[scriptID]
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("script not found: %s", scriptID)
+	}
+	return e.execute(rs)
+}
+
+// PauseScript は周期実行を一時停止する。StopScript と異なりVM（JSのグローバル状態）は保持される。
+func (e *ScriptEngine) PauseScript(scriptID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rs, ok := e.scripts[scriptID]
+	if !ok {
+		return fmt.Errorf("script not found: %s", scriptID)
+	}
+	if rs.paused {
+		return nil
+	}
+	rs.stopTicker()
+	rs.paused = true
+	return nil
+}
+
+// ResumeScript は一時停止中のスクリプトの周期実行を同じVMで再開する
+func (e *ScriptEngine) ResumeScript(scriptID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rs, ok := e.scripts[scriptID]
+	if !ok {
+		return fmt.Errorf("script not found: %s", scriptID)
+	}
+	if !rs.paused {
+		return nil
+	}
+	e.startTicker(rs)
+	return nil
+}
+
+// ExecuteScript はスクリプトを1回だけ同期実行する。
+// 実行中・一時停止中の場合はそのVMで実行し（StepScript と同じ）、
+// 未登録の場合は一時的なVMで実行して破棄する。
+func (e *ScriptEngine) ExecuteScript(s *script.Script) error {
+	e.mu.Lock()
+	rs, ok := e.scripts[s.ID]
+	if !ok {
+		var err error
+		if rs, err = e.compileScript(s); err != nil {
+			e.mu.Unlock()
+			return err
+		}
+	}
+	e.mu.Unlock()
+	return e.execute(rs)
+}
+
+// startTicker は周期実行ゴルーチンを開始する（e.mu 保持前提）
+func (e *ScriptEngine) startTicker(rs *runningScript) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	rs.cancel = cancel
+	rs.done = done
+	rs.paused = false
 
-	// 周期実行ゴルーチン
 	go func() {
-		ticker := time.NewTicker(s.Interval)
+		defer close(done)
+		ticker := time.NewTicker(rs.script.Interval)
 		defer ticker.Stop()
 
 		for {
@@ -508,37 +693,57 @@ func (e *ScriptEngine) StartScript(s *script.Script) error {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							errMsg := fmt.Sprintf("panic: %v", r)
-							fmt.Printf("Script %s panicked: %v\n", s.Name, r)
-							e.mu.Lock()
-							if cur, ok := e.scripts[s.ID]; ok {
-								cur.lastError = errMsg
-								cur.errorAt = time.Now()
-							}
-							e.mu.Unlock()
-						}
-					}()
-					_, runErr := vm.RunProgram(program)
-					if runErr != nil {
-						fmt.Printf("Script %s error: %v\n", s.Name, runErr)
-						e.mu.Lock()
-						if cur, ok := e.scripts[s.ID]; ok {
-							cur.lastError = runErr.Error()
-							cur.errorAt = time.Now()
-						}
-						e.mu.Unlock()
-					}
-				}()
+				// 停止と同時にティックが来た場合は実行しない
+				if ctx.Err() != nil {
+					return
+				}
+				e.execute(rs) //nolint:errcheck // エラーは lastError に記録される
 			}
 		}
 	}()
+}
+
+// stopTicker は周期実行ゴルーチンを停止する（e.mu 保持前提）
+func (rs *runningScript) stopTicker() {
+	if rs.cancel != nil {
+		rs.cancel()
+		rs.cancel = nil
+	}
+}
 
+// execute はスクリプトを1回実行し、エラー・パニックを lastError に記録する
+func (e *ScriptEngine) execute(rs *runningScript) (err error) {
+	rs.runMu.Lock()
+	defer rs.runMu.Unlock()
+
+	s := rs.script
+	defer func() {
+		if r := recover(); r != nil {
+			e.log().Error("script panicked", "scriptId", s.ID, "script", s.Name, "panic", r)
+			err = fmt.Errorf("panic: %v", r)
+			e.recordError(rs, err)
+		}
+	}()
+	unlock := e.lockRun()
+	defer unlock()
+	_, runErr := e.runWithWatchdog(rs.vm, func() (goja.Value, error) {
+		return rs.vm.RunProgram(rs.program)
+	})
+	if runErr != nil {
+		e.log().Error("script error", "scriptId", s.ID, "script", s.Name, "error", runErr)
+		e.recordError(rs, runErr)
+		return runErr
+	}
 	return nil
 }
 
+func (e *ScriptEngine) recordError(rs *runningScript, err error) {
+	e.mu.Lock()
+	rs.lastError = err.Error()
+	rs.errorAt = time.Now()
+	e.mu.Unlock()
+}
+
 // StopScript はスクリプトを停止する
 func (e *ScriptEngine) StopScript(scriptID string) error {
 	e.mu.Lock()
@@ -549,24 +754,50 @@ func (e *ScriptEngine) StopScript(scriptID string) error {
 		return fmt.Errorf("script not found: %s", scriptID)
 	}
 
-	rs.cancel()
+	rs.stopTicker()
 	delete(e.scripts, scriptID)
+	e.clearEdges(scriptID)
 	return nil
 }
 
-// StopAll は全てのスクリプトを停止する
+// StopAll は全てのスクリプトを停止し、実行中の周期実行が終わるまで待つ
 func (e *ScriptEngine) StopAll() {
 	e.mu.Lock()
-	defer e.mu.Unlock()
-
+	var running []chan struct{}
 	for id, rs := range e.scripts {
-		rs.cancel()
+		if rs.done != nil {
+			running = append(running, rs.done)
+		}
+		rs.stopTicker()
 		delete(e.scripts, id)
+		e.clearEdges(id)
+	}
+	e.mu.Unlock()
+
+	// 実行中のスクリプトが終わるまで待つ（実行中のスクリプトは recordError で e.mu を取るためロック外で待つ）
+	for _, done := range running {
+		<-done
 	}
 }
 
-// IsRunning はスクリプトが実行中かどうかを返す
+// IsRunning はスクリプトが周期実行中かどうかを返す（一時停止中は false）
 func (e *ScriptEngine) IsRunning(scriptID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rs, ok := e.scripts[scriptID]
+	return ok && !rs.paused
+}
+
+// IsPaused はスクリプトが一時停止中かどうかを返す
+func (e *ScriptEngine) IsPaused(scriptID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rs, ok := e.scripts[scriptID]
+	return ok && rs.paused
+}
+
+// IsLoaded はスクリプトがエンジンに登録済み（実行中または一時停止中）かどうかを返す
+func (e *ScriptEngine) IsLoaded(scriptID string) bool {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	_, ok := e.scripts[scriptID]
@@ -579,8 +810,10 @@ func (e *ScriptEngine) GetRunningScripts() []string {
 	defer e.mu.Unlock()
 
 	ids := make([]string, 0, len(e.scripts))
-	for id := range e.scripts {
-		ids = append(ids, id)
+	for id, rs := range e.scripts {
+		if !rs.paused {
+			ids = append(ids, id)
+		}
 	}
 	return ids
 }
@@ -627,7 +860,11 @@ func (e *ScriptEngine) ClearConsoleLogs() {
 // RunOnce はスクリプトを1回だけ実行する（テスト用）
 func (e *ScriptEngine) RunOnce(code string) (any, error) {
 	vm := e.createVM("", "テスト実行")
-	result, err := vm.RunString(code)
+	unlock := e.lockRun()
+	defer unlock()
+	result, err := e.runWithWatchdog(vm, func() (goja.Value, error) {
+		return vm.RunString(code)
+	})
 	if err != nil {
 		return nil, err
 	}
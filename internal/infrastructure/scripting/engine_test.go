@@ -1,6 +1,11 @@
 package scripting
 
 import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -47,6 +52,20 @@ func TestScriptEngine_RunOnce_WithConsole(t *testing.T) {
 	}
 }
 
+func TestScriptEngine_SetLogger(t *testing.T) {
+	engine, _ := newTestEngine()
+	var buf bytes.Buffer
+	engine.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	if _, err := engine.RunOnce(`console.log("hello", 42)`); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "component=script") || !strings.Contains(out, `message="hello 42"`) {
+		t.Errorf("expected structured console.log record, got %q", out)
+	}
+}
+
 func TestScriptEngine_RunOnce_SyntaxError(t *testing.T) {
 	engine, _ := newTestEngine()
 
@@ -57,6 +76,17 @@ func TestScriptEngine_RunOnce_SyntaxError(t *testing.T) {
 	}
 }
 
+func TestScriptEngine_CheckSyntax(t *testing.T) {
+	engine, _ := newTestEngine()
+
+	if err := engine.CheckSyntax("ok", "const x = 1 + 1;"); err != nil {
+		t.Errorf("CheckSyntax(valid) = %v", err)
+	}
+	if err := engine.CheckSyntax("bad", "invalid syntax {{{"); err == nil {
+		t.Error("expected syntax error")
+	}
+}
+
 func TestScriptEngine_RunOnce_ReadWriteVariable(t *testing.T) {
 	engine, vs := newTestEngine()
 
@@ -364,3 +394,328 @@ func TestScriptEngine_IsRunning(t *testing.T) {
 		t.Error("expected false after stop")
 	}
 }
+
+func TestScriptEngine_StepScript(t *testing.T) {
+	engine, vs := newTestEngine()
+	_, _ = vs.CreateVariable("Counter", variable.TypeINT, int16(0))
+
+	// JS のグローバル状態をステップ間で引き継ぐことも確認する
+	s := script.NewScript("step-1", "step", `
+		globalThis.ticks = (globalThis.ticks || 0) + 1;
+		plc.writeVariable("Counter", globalThis.ticks);
+	`, time.Hour)
+
+	if err := engine.LoadScript(s); err != nil {
+		t.Fatalf("LoadScript failed: %v", err)
+	}
+	if engine.IsRunning("step-1") {
+		t.Error("loaded script should not be running")
+	}
+
+	for want := int16(1); want <= 3; want++ {
+		if err := engine.StepScript("step-1"); err != nil {
+			t.Fatalf("StepScript failed: %v", err)
+		}
+		v, _ := vs.GetVariableByName("Counter")
+		if got := v.Value.(int16); got != want {
+			t.Fatalf("after step: Counter = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestScriptEngine_StepScript_NotFound(t *testing.T) {
+	engine, _ := newTestEngine()
+	if err := engine.StepScript("missing"); err == nil {
+		t.Error("expected error for unknown script")
+	}
+}
+
+// waitForCounter は Counter 変数が cond を満たすまで待ち、その値を返す
+func waitForCounter(t *testing.T, vs *variable.VariableStore, cond func(int16) bool) int16 {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		v, _ := vs.ReadValue("Counter")
+		val := v.(int16)
+		if cond(val) {
+			return val
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for Counter (last %d)", val)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestScriptEngine_PauseResume_KeepsVMState(t *testing.T) {
+	engine, vs := newTestEngine()
+	_, _ = vs.CreateVariable("Counter", variable.TypeINT, int16(0))
+
+	s := script.NewScript("pause-1", "accumulate", `
+		globalThis.ticks = (globalThis.ticks || 0) + 1;
+		plc.writeVariable("Counter", globalThis.ticks);
+	`, 20*time.Millisecond)
+
+	if err := engine.StartScript(s); err != nil {
+		t.Fatalf("StartScript failed: %v", err)
+	}
+	defer engine.StopAll()
+	waitForCounter(t, vs, func(v int16) bool { return v >= 2 })
+
+	if err := engine.PauseScript("pause-1"); err != nil {
+		t.Fatalf("PauseScript failed: %v", err)
+	}
+	if engine.IsRunning("pause-1") || !engine.IsPaused("pause-1") {
+		t.Fatal("expected script to be paused")
+	}
+	time.Sleep(50 * time.Millisecond) // 実行中だったティックの完了を待つ
+	paused := waitForCounter(t, vs, func(int16) bool { return true })
+	time.Sleep(100 * time.Millisecond)
+	if got := waitForCounter(t, vs, func(int16) bool { return true }); got != paused {
+		t.Fatalf("Counter changed while paused: %d -> %d", paused, got)
+	}
+
+	// 再開後は JS の ticks を引き継いで続きから加算される
+	if err := engine.ResumeScript("pause-1"); err != nil {
+		t.Fatalf("ResumeScript failed: %v", err)
+	}
+	waitForCounter(t, vs, func(v int16) bool { return v > paused })
+
+	// Stop/Start では VM が作り直されるため 1 からやり直しになる
+	if err := engine.StopScript("pause-1"); err != nil {
+		t.Fatalf("StopScript failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	_ = vs.UpdateValueByName("Counter", int16(100))
+	if err := engine.StartScript(s); err != nil {
+		t.Fatalf("StartScript failed: %v", err)
+	}
+	if got := waitForCounter(t, vs, func(v int16) bool { return v != 100 }); got != 1 {
+		t.Errorf("after restart Counter = %d, want 1", got)
+	}
+}
+
+func TestScriptEngine_PauseScript_NotFound(t *testing.T) {
+	engine, _ := newTestEngine()
+	if err := engine.PauseScript("missing"); err == nil {
+		t.Error("expected error for unknown script")
+	}
+	if err := engine.ResumeScript("missing"); err == nil {
+		t.Error("expected error for unknown script")
+	}
+}
+
+func TestScriptEngine_Sleep(t *testing.T) {
+	engine, vs := newTestEngine()
+	if _, err := vs.CreateVariable("Step", variable.TypeINT, int16(0)); err != nil {
+		t.Fatalf("CreateVariable failed: %v", err)
+	}
+
+	// sleep を挟んでも実行は完了し、後続の書き込みが反映される
+	s := script.NewScript("sleep-1", "stagger", `
+		plc.writeVariable("Step", 1);
+		plc.sleep(30);
+		plc.writeVariable("Step", 2);
+	`, time.Second)
+	start := time.Now()
+	if err := engine.ExecuteScript(s); err != nil {
+		t.Fatalf("ExecuteScript failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected plc.sleep to block for 30ms, took %v", elapsed)
+	}
+	v, _ := vs.GetVariableByName("Step")
+	if v.Value.(int16) != 2 {
+		t.Errorf("expected Step = 2, got %v", v.Value)
+	}
+
+	// 上限を超える指定は maxScriptSleep に切り詰められる、負の値は待機しない
+	start = time.Now()
+	if _, err := engine.RunOnce("plc.sleep(-1); plc.sleep(60000)"); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > maxScriptSleep+500*time.Millisecond {
+		t.Errorf("expected sleep to be capped at %v, took %v", maxScriptSleep, elapsed)
+	}
+}
+
+func TestScriptEngine_Watchdog_InterruptsInfiniteLoop(t *testing.T) {
+	engine, vs := newTestEngine()
+	engine.SetMaxRuntime(100 * time.Millisecond)
+	if _, err := vs.CreateVariable("Runs", variable.TypeINT, int16(0)); err != nil {
+		t.Fatalf("CreateVariable failed: %v", err)
+	}
+
+	// RunOnce: 中断されてタイムアウトエラーになる
+	start := time.Now()
+	_, err := engine.RunOnce("while (true) {}")
+	if !errors.Is(err, ErrScriptTimeout) {
+		t.Fatalf("expected ErrScriptTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected interrupt within timeout, took %v", elapsed)
+	}
+
+	// 周期実行: 最初の周期だけ無限ループし、中断後も次の周期が実行される
+	s := script.NewScript("loop-1", "runaway", `
+		var n = plc.readVariable("Runs");
+		plc.writeVariable("Runs", n + 1);
+		if (n === 0) { while (true) {} }
+	`, 50*time.Millisecond)
+	if err := engine.StartScript(s); err != nil {
+		t.Fatalf("StartScript failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if msg, _ := engine.GetLastError("loop-1"); msg != "" {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if msg, _ := engine.GetLastError("loop-1"); !strings.Contains(msg, ErrScriptTimeout.Error()) {
+		t.Fatalf("expected timeout to be recorded, got %q", msg)
+	}
+
+	// 中断後も周期実行が続いている
+	time.Sleep(200 * time.Millisecond)
+	engine.StopAll()
+	if v, _ := vs.GetVariableByName("Runs"); v.Value.(int16) < 2 {
+		t.Errorf("expected script to keep running after timeout, Runs = %v", v.Value)
+	}
+}
+
+func TestScriptEngine_RunLock(t *testing.T) {
+	engine, _ := newTestEngine()
+	var mu sync.RWMutex
+	engine.SetRunLock(mu.RLocker())
+
+	// 排他ロックを持っている間は実行が始まらない
+	mu.Lock()
+	done := make(chan error, 1)
+	go func() {
+		_, err := engine.RunOnce("1 + 1")
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("script ran while the run lock was held")
+	case <-time.After(30 * time.Millisecond):
+	}
+	mu.Unlock()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RunOnce failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("script did not run after the run lock was released")
+	}
+
+	// 実行後はロックが解放されている
+	if !mu.TryLock() {
+		t.Error("run lock was not released after the script finished")
+	}
+}
+
+func TestScriptEngine_Require(t *testing.T) {
+	engine, _ := newTestEngine()
+	if err := engine.SetLibrary("mathlib", `
+		var calls = 0;
+		exports.double = function(x) { calls++; return x * 2; };
+		exports.calls = function() { return calls; };
+	`); err != nil {
+		t.Fatalf("SetLibrary failed: %v", err)
+	}
+
+	// 同じ VM 内では1回だけ評価され、状態を共有する
+	result, err := engine.RunOnce(`
+		var a = require("mathlib");
+		var b = require("mathlib");
+		a.double(1); b.double(2);
+		b.calls();
+	`)
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if result != int64(2) {
+		t.Errorf("expected both requires to share state (2 calls), got %v", result)
+	}
+
+	// module.exports の置き換えとライブラリからの require
+	if err := engine.SetLibrary("quad", `
+		var m = require("mathlib");
+		module.exports = function(x) { return m.double(m.double(x)); };
+	`); err != nil {
+		t.Fatalf("SetLibrary failed: %v", err)
+	}
+	if result, err := engine.RunOnce(`require("quad")(3)`); err != nil || result != int64(12) {
+		t.Errorf("expected 12, got %v, %v", result, err)
+	}
+
+	if _, err := engine.RunOnce(`require("missing")`); err == nil {
+		t.Error("expected error for unknown library")
+	}
+	if err := engine.SetLibrary("broken", `function (`); err == nil {
+		t.Error("expected compile error")
+	}
+
+	engine.RemoveLibrary("mathlib")
+	if _, err := engine.RunOnce(`require("mathlib")`); err == nil {
+		t.Error("expected error after RemoveLibrary")
+	}
+}
+
+func TestScriptEngine_Require_Circular(t *testing.T) {
+	engine, _ := newTestEngine()
+	engine.SetLibrary("a", `exports.b = require("b");`)
+	engine.SetLibrary("b", `exports.a = require("a");`)
+	if _, err := engine.RunOnce(`require("a")`); err == nil || !strings.Contains(err.Error(), "circular require") {
+		t.Errorf("expected circular require error, got %v", err)
+	}
+}
+
+func TestScriptEngine_PackUnpackWords(t *testing.T) {
+	engine, _ := newTestEngine()
+
+	tests := []struct {
+		endian string
+		want   string
+	}{
+		{`"big"`, "[18,52,171,205,0,1]"},
+		{`"little"`, "[52,18,205,171,1,0]"},
+		{`undefined`, "[18,52,171,205,0,1]"},
+	}
+	for _, tt := range tests {
+		code := `
+			var words = [0x1234, 0xABCD, 1];
+			var bytes = plc.packWords(words, ` + tt.endian + `);
+			var back = plc.unpackWords(bytes, ` + tt.endian + `);
+			JSON.stringify(bytes) + "|" + JSON.stringify(back);
+		`
+		result, err := engine.RunOnce(code)
+		if err != nil {
+			t.Fatalf("%s: RunOnce failed: %v", tt.endian, err)
+		}
+		if want := tt.want + "|[4660,43981,1]"; result != want {
+			t.Errorf("%s: got %v, want %s", tt.endian, result, want)
+		}
+	}
+
+	// 奇数個のバイトは末尾を 0 で補う
+	if result, err := engine.RunOnce(`JSON.stringify(plc.unpackWords([0x12, 0x34, 0x56]))`); err != nil || result != "[4660,22016]" {
+		t.Errorf("odd byte count: got %v, %v", result, err)
+	}
+
+	for _, code := range []string{
+		`plc.packWords([0x10000])`,
+		`plc.packWords([-1])`,
+		`plc.unpackWords([256])`,
+		`plc.packWords([1], "middle")`,
+	} {
+		if _, err := engine.RunOnce(code); err == nil {
+			t.Errorf("%s: expected error", code)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package scripting
+
+import (
+	"fmt"
+
+	"github.com/dop251/goja"
+)
+
+// library は require で読み込まれるライブラリスクリプト（コンパイルは登録時に1回だけ行う）
+type library struct {
+	program *goja.Program
+}
+
+// moduleCache は VM ごとのライブラリの評価結果
+type moduleCache struct {
+	program *goja.Program // 評価に使ったプログラム（再登録されたら評価し直す）
+	exports goja.Value
+	loading bool // 評価中（循環参照の検出用）
+}
+
+// SetLibrary は name で require できるライブラリスクリプトを登録する（既存の場合は置き換える）。
+// コードは CommonJS 形式で、module.exports または exports に公開する値を設定する。
+// ライブラリは呼び出し側の VM ごとに1回評価され、以降の require は同じ exports を返す。
+func (e *ScriptEngine) SetLibrary(name, code string) error {
+	if name == "" {
+		return fmt.Errorf("library name is empty")
+	}
+	wrapped := "(function(module, exports, require){\n" + code + "\n})"
+	program, err := goja.Compile(name, wrapped, false)
+	if err != nil {
+		return fmt.Errorf("failed to compile library: %w", err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.libraries == nil {
+		e.libraries = make(map[string]*library)
+	}
+	e.libraries[name] = &library{program: program}
+	return nil
+}
+
+// RemoveLibrary はライブラリスクリプトの登録を解除する（未登録なら何もしない）
+func (e *ScriptEngine) RemoveLibrary(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.libraries, name)
+}
+
+// lookupLibrary は登録済みのライブラリを返す
+func (e *ScriptEngine) lookupLibrary(name string) (*library, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	lib, ok := e.libraries[name]
+	return lib, ok
+}
+
+// registerRequireFunction は require をグローバルに登録する。
+// goja の VM はゴルーチン間で共有できないため、ライブラリは呼び出し側の VM で評価してキャッシュする。
+func (e *ScriptEngine) registerRequireFunction(vm *goja.Runtime) {
+	cache := make(map[string]*moduleCache)
+
+	var require func(call goja.FunctionCall) goja.Value
+	require = func(call goja.FunctionCall) goja.Value {
+		name := call.Argument(0).String()
+		lib, ok := e.lookupLibrary(name)
+		if !ok {
+			panic(vm.NewGoError(fmt.Errorf("library not found: %s", name)))
+		}
+		if m, ok := cache[name]; ok {
+			if m.loading {
+				panic(vm.NewGoError(fmt.Errorf("circular require: %s", name)))
+			}
+			if m.program == lib.program {
+				return m.exports
+			}
+		}
+
+		m := &moduleCache{program: lib.program, loading: true}
+		cache[name] = m
+		defer func() { m.loading = false }()
+
+		fn, err := vm.RunProgram(lib.program)
+		if err != nil {
+			delete(cache, name)
+			panic(err)
+		}
+		run, ok := goja.AssertFunction(fn)
+		if !ok {
+			delete(cache, name)
+			panic(vm.NewGoError(fmt.Errorf("invalid library: %s", name)))
+		}
+		module := vm.NewObject()
+		exports := vm.NewObject()
+		module.Set("exports", exports)
+		if _, err := run(goja.Undefined(), module, exports, vm.ToValue(require)); err != nil {
+			delete(cache, name)
+			panic(err)
+		}
+		m.exports = module.Get("exports")
+		return m.exports
+	}
+	vm.Set("require", require)
+}
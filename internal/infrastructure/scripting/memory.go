@@ -0,0 +1,137 @@
+package scripting
+
+import (
+	"modbus_simulator/internal/domain/datastore"
+
+	"github.com/dop251/goja"
+)
+
+// MemoryAccessor はスクリプトからプロトコルのメモリエリアを直接操作するためのインターフェース。
+// protocolType が空文字の場合は実装側で既定のサーバーを選択する。
+type MemoryAccessor interface {
+	FillArea(protocolType, area string, value int) error
+	ReadBit(protocolType, area string, address int) (bool, error)
+	ReadHex(protocolType, area string, start, count int) (string, error)
+	WriteHex(protocolType, area string, start int, hex string) error
+	LoadRecipe(name string) error
+	ReadWords(protocolType, area string, address, count int) ([]uint16, error)
+	WriteWords(protocolType, area string, address int, values []uint16) error
+	EnqueueFIFO(protocolType string, pointer, value int) error
+	// DefaultByteOrder はスクリプトでバイト順を省略した場合に使う順序を返す
+	DefaultByteOrder() datastore.ByteOrder
+}
+
+// SetMemoryAccessor はメモリ操作用のアクセサを設定する（以降に作成される VM に反映される）
+func (e *ScriptEngine) SetMemoryAccessor(m MemoryAccessor) {
+	e.mu.Lock()
+	e.memory = m
+	e.mu.Unlock()
+}
+
+// registerMemoryFunctions はメモリ操作用の関数を plc オブジェクトに登録する
+func registerMemoryFunctions(vm *goja.Runtime, plc *goja.Object, memory MemoryAccessor) {
+	// fill(area, value[, protocolType]) - エリア全体を同じ値で埋める
+	// 例: plc.fill("holdingRegisters", 0xFFFF)
+	plc.Set("fill", func(call goja.FunctionCall) goja.Value {
+		area := call.Argument(0).String()
+		value := int(call.Argument(1).ToInteger())
+		if err := memory.FillArea(optionalString(call.Argument(2)), area, value); err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return goja.Undefined()
+	})
+
+	// readHex(area, start, count[, protocolType]) - ワード範囲を "1234 ABCD" 形式で取得する
+	// 例: plc.readHex("holdingRegisters", 0, 4)
+	plc.Set("readHex", func(call goja.FunctionCall) goja.Value {
+		area := call.Argument(0).String()
+		start := int(call.Argument(1).ToInteger())
+		count := int(call.Argument(2).ToInteger())
+		hex, err := memory.ReadHex(optionalString(call.Argument(3)), area, start, count)
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return vm.ToValue(hex)
+	})
+
+	// writeHex(area, start, hex[, protocolType]) - 16進文字列をワード範囲に書き込む
+	// 例: plc.writeHex("holdingRegisters", 0, "0x1234,0xABCD")
+	plc.Set("writeHex", func(call goja.FunctionCall) goja.Value {
+		area := call.Argument(0).String()
+		start := int(call.Argument(1).ToInteger())
+		hex := call.Argument(2).String()
+		if err := memory.WriteHex(optionalString(call.Argument(3)), area, start, hex); err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return goja.Undefined()
+	})
+
+	// readFloat32(area, address[, byteOrder[, protocolType]]) - 2ワードを float32 として読み取る
+	// byteOrder は "ABCD" / "CDAB" / "BADC" / "DCBA"（省略時はサービスの既定値）
+	// 例: plc.readFloat32("holdingRegisters", 0, "CDAB")
+	plc.Set("readFloat32", func(call goja.FunctionCall) goja.Value {
+		area := call.Argument(0).String()
+		address := int(call.Argument(1).ToInteger())
+		order := scriptByteOrder(vm, memory, call.Argument(2))
+		words, err := memory.ReadWords(optionalString(call.Argument(3)), area, address, 2)
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return vm.ToValue(float64(datastore.WordsToFloat32([2]uint16{words[0], words[1]}, order)))
+	})
+
+	// writeFloat32(area, address, value[, byteOrder[, protocolType]]) - float32 を2ワードに書き込む
+	// 例: plc.writeFloat32("holdingRegisters", 0, 25.5)
+	plc.Set("writeFloat32", func(call goja.FunctionCall) goja.Value {
+		area := call.Argument(0).String()
+		address := int(call.Argument(1).ToInteger())
+		value := float32(call.Argument(2).ToFloat())
+		order := scriptByteOrder(vm, memory, call.Argument(3))
+		words := datastore.Float32ToWords(value, order)
+		if err := memory.WriteWords(optionalString(call.Argument(4)), area, address, words[:]); err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return goja.Undefined()
+	})
+
+	// enqueueFIFO(pointer, value[, protocolType]) - FC 0x18 で返す FIFO キューに値を追加する
+	// 例: plc.enqueueFIFO(100, 0x1234)
+	plc.Set("enqueueFIFO", func(call goja.FunctionCall) goja.Value {
+		pointer := int(call.Argument(0).ToInteger())
+		value := int(call.Argument(1).ToInteger())
+		if err := memory.EnqueueFIFO(optionalString(call.Argument(2)), pointer, value); err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return goja.Undefined()
+	})
+
+	// loadRecipe(name) - 保存済みのレシピをメモリに書き込む
+	// 例: plc.loadRecipe("製品A")
+	plc.Set("loadRecipe", func(call goja.FunctionCall) goja.Value {
+		if err := memory.LoadRecipe(call.Argument(0).String()); err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return goja.Undefined()
+	})
+}
+
+// scriptByteOrder はスクリプトのバイト順引数を解釈する（未指定の場合は既定値）
+func scriptByteOrder(vm *goja.Runtime, memory MemoryAccessor, v goja.Value) datastore.ByteOrder {
+	s := optionalString(v)
+	if s == "" {
+		return memory.DefaultByteOrder()
+	}
+	order, err := datastore.ParseByteOrder(s)
+	if err != nil {
+		panic(vm.NewGoError(err))
+	}
+	return order
+}
+
+// optionalString は省略可能な文字列引数を取り出す（未指定の場合は空文字）
+func optionalString(v goja.Value) string {
+	if goja.IsUndefined(v) || goja.IsNull(v) {
+		return ""
+	}
+	return v.String()
+}
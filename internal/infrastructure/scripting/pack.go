@@ -0,0 +1,100 @@
+package scripting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dop251/goja"
+)
+
+// parseWordEndian はワード内のバイト順の指定を解析する（省略時はビッグエンディアン）
+func parseWordEndian(v goja.Value) (bigEndian bool, err error) {
+	switch s := strings.ToLower(optionalString(v)); s {
+	case "", "big", "be":
+		return true, nil
+	case "little", "le":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown endian %q (use \"big\" or \"little\")", s)
+	}
+}
+
+// packWords はワード列をバイト列に変換する（1ワード → 2バイト）
+func packWords(words []int64, bigEndian bool) ([]int64, error) {
+	bytes := make([]int64, 0, len(words)*2)
+	for i, w := range words {
+		if w < 0 || w > 0xFFFF {
+			return nil, fmt.Errorf("word[%d] out of range: %d", i, w)
+		}
+		hi, lo := w>>8, w&0xFF
+		if bigEndian {
+			bytes = append(bytes, hi, lo)
+		} else {
+			bytes = append(bytes, lo, hi)
+		}
+	}
+	return bytes, nil
+}
+
+// unpackWords はバイト列をワード列に変換する（2バイト → 1ワード、奇数個の場合は末尾を 0 で補う）
+func unpackWords(bytes []int64, bigEndian bool) ([]int64, error) {
+	words := make([]int64, 0, (len(bytes)+1)/2)
+	for i := 0; i < len(bytes); i += 2 {
+		b0 := bytes[i]
+		var b1 int64
+		if i+1 < len(bytes) {
+			b1 = bytes[i+1]
+		}
+		for j, b := range [2]int64{b0, b1} {
+			if b < 0 || b > 0xFF {
+				return nil, fmt.Errorf("byte[%d] out of range: %d", i+j, b)
+			}
+		}
+		if bigEndian {
+			words = append(words, b0<<8|b1)
+		} else {
+			words = append(words, b1<<8|b0)
+		}
+	}
+	return words, nil
+}
+
+// registerPackFunctions はワード列とバイト列の変換関数を plc オブジェクトに登録する
+func registerPackFunctions(vm *goja.Runtime, plc *goja.Object) {
+	// packWords(words[, endian]) - ワード配列をバイト配列に変換する
+	// endian は "big"（既定、上位バイトが先）/ "little"
+	// 例: plc.packWords([0x1234]) → [0x12, 0x34]
+	plc.Set("packWords", func(call goja.FunctionCall) goja.Value {
+		var words []int64
+		if err := vm.ExportTo(call.Argument(0), &words); err != nil {
+			panic(vm.NewGoError(fmt.Errorf("packWords: %w", err)))
+		}
+		bigEndian, err := parseWordEndian(call.Argument(1))
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
+		bytes, err := packWords(words, bigEndian)
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return vm.ToValue(bytes)
+	})
+
+	// unpackWords(bytes[, endian]) - バイト配列をワード配列に変換する（奇数個の場合は末尾を 0 で補う）
+	// 例: plc.unpackWords([0x12, 0x34]) → [0x1234]
+	plc.Set("unpackWords", func(call goja.FunctionCall) goja.Value {
+		var bytes []int64
+		if err := vm.ExportTo(call.Argument(0), &bytes); err != nil {
+			panic(vm.NewGoError(fmt.Errorf("unpackWords: %w", err)))
+		}
+		bigEndian, err := parseWordEndian(call.Argument(1))
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
+		words, err := unpackWords(bytes, bigEndian)
+		if err != nil {
+			panic(vm.NewGoError(err))
+		}
+		return vm.ToValue(words)
+	})
+}
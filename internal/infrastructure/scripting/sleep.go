@@ -0,0 +1,31 @@
+package scripting
+
+import (
+	"math"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// maxScriptSleep は plc.sleep で1回に待機できる最大時間
+const maxScriptSleep = time.Second
+
+// registerSleepFunction は plc.sleep を登録する
+func registerSleepFunction(plc *goja.Object) {
+	// sleep(ms) - スクリプトの実行を ms ミリ秒止める（最大 maxScriptSleep）
+	// スクリプトごとに専用のゴルーチンで実行されるため他のスクリプトには影響しないが、
+	// 実行時間が周期を超えると次の周期の実行が遅れる。
+	// 例: plc.writeBit("coils", 0, true); plc.sleep(50); plc.writeBit("coils", 1, true);
+	plc.Set("sleep", func(call goja.FunctionCall) goja.Value {
+		ms := call.Argument(0).ToFloat()
+		if math.IsNaN(ms) || ms <= 0 {
+			return goja.Undefined()
+		}
+		d := maxScriptSleep
+		if ms < float64(maxScriptSleep.Milliseconds()) {
+			d = time.Duration(ms * float64(time.Millisecond))
+		}
+		time.Sleep(d)
+		return goja.Undefined()
+	})
+}
@@ -0,0 +1,41 @@
+package scripting
+
+import (
+	"modbus_simulator/internal/domain/protocol"
+
+	"github.com/dop251/goja"
+)
+
+// StatsProvider はスクリプトの plc.stats() に通信統計を提供する
+type StatsProvider interface {
+	TrafficStats() protocol.TrafficSnapshot
+}
+
+// SetStatsProvider は通信統計の提供元を設定する（以降に作成される VM に反映される）
+func (e *ScriptEngine) SetStatsProvider(p StatsProvider) {
+	e.mu.Lock()
+	e.stats = p
+	e.mu.Unlock()
+}
+
+// registerStatsFunction は plc.stats を登録する
+func registerStatsFunction(vm *goja.Runtime, plc *goja.Object, stats StatsProvider) {
+	// stats() - 通信統計を取得する
+	// 戻り値: {requests, exceptions, lastFunctionCode, connections, lastRequestAt}
+	// lastRequestAt は最後に要求を受信した時刻（Unix ミリ秒、未受信の場合は 0）
+	// 例: if (Date.now() - plc.stats().lastRequestAt > 5000) { ... }
+	plc.Set("stats", func(goja.FunctionCall) goja.Value {
+		snap := stats.TrafficStats()
+		var lastRequestAt int64
+		if !snap.LastRequestAt.IsZero() {
+			lastRequestAt = snap.LastRequestAt.UnixMilli()
+		}
+		return vm.ToValue(map[string]interface{}{
+			"requests":         snap.Requests,
+			"exceptions":       snap.Exceptions,
+			"lastFunctionCode": snap.LastFunctionCode,
+			"connections":      snap.Connections,
+			"lastRequestAt":    lastRequestAt,
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// ErrScriptTimeout は1回の実行が最大実行時間を超えて中断された場合のエラー
+var ErrScriptTimeout = errors.New("script timed out")
+
+// defaultMaxRuntime は1回の実行の最大実行時間の既定値
+const defaultMaxRuntime = 10 * time.Second
+
+// SetMaxRuntime は1回の実行の最大実行時間を設定する（0以下で無制限）。
+// 超過した実行は中断され、ErrScriptTimeout がエラーとして記録される。
+func (e *ScriptEngine) SetMaxRuntime(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	e.mu.Lock()
+	e.maxRuntime = d
+	e.mu.Unlock()
+}
+
+// MaxRuntime は1回の実行の最大実行時間を返す（0 は無制限）
+func (e *ScriptEngine) MaxRuntime() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.maxRuntime
+}
+
+// runWithWatchdog は run を実行し、最大実行時間を超えた場合は vm を中断する。
+// 中断された場合は ErrScriptTimeout を返し、VM は次の実行に使える状態に戻す。
+func (e *ScriptEngine) runWithWatchdog(vm *goja.Runtime, run func() (goja.Value, error)) (goja.Value, error) {
+	timeout := e.MaxRuntime()
+	if timeout <= 0 {
+		return run()
+	}
+
+	// 実行終了後に割り込みが届かないよう finished で排他する
+	var mu sync.Mutex
+	finished := false
+	timer := time.AfterFunc(timeout, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !finished {
+			vm.Interrupt(ErrScriptTimeout)
+		}
+	})
+
+	value, err := run()
+
+	mu.Lock()
+	finished = true
+	mu.Unlock()
+	timer.Stop()
+	vm.ClearInterrupt()
+
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) && interrupted.Value() == ErrScriptTimeout {
+		return nil, fmt.Errorf("%w after %v", ErrScriptTimeout, timeout)
+	}
+	return value, err
+}
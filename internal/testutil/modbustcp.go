@@ -0,0 +1,50 @@
+// Package testutil は複数パッケージのテストで共有するヘルパーを提供する。
+package testutil
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/simonvetter/modbus"
+)
+
+// FreeTCPPort はローカルホストで空いている TCP ポート番号を返す
+func FreeTCPPort(t testing.TB) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// DialModbusTCP は 127.0.0.1:port の Modbus TCP サーバーに接続したクライアントを返す。
+// サーバーの起動直後でも使えるよう、timeout まで接続をリトライする。
+// クライアントはテスト終了時に閉じられる。
+func DialModbusTCP(t testing.TB, port int, timeout time.Duration) *modbus.ModbusClient {
+	t.Helper()
+	client, err := modbus.NewClient(&modbus.ClientConfiguration{
+		URL:     fmt.Sprintf("tcp://127.0.0.1:%d", port),
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err = client.Open()
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("connect to Modbus TCP port %d: %v", port, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
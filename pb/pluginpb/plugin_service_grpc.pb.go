@@ -42,6 +42,22 @@ type PluginServiceClient interface {
 	GetUnitIDSettings(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UnitIDSettingsResponse, error)
 	SetUnitIDEnabled(ctx context.Context, in *SetUnitIDEnabledRequest, opts ...grpc.CallOption) (*Empty, error)
 	SetDisabledUnitIDs(ctx context.Context, in *SetDisabledUnitIDsRequest, opts ...grpc.CallOption) (*Empty, error)
+	// 接続中のクライアント一覧（クライアント単位で追跡できるプロトコル用、未対応の場合は空）
+	GetConnections(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetConnectionsResponse, error)
+	// マスターからのアドレスごとの読み書き回数（ヒートマップ用、未対応の場合は空）
+	GetAccessCounts(ctx context.Context, in *GetAccessCountsRequest, opts ...grpc.CallOption) (*GetAccessCountsResponse, error)
+	ResetAccessCounts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	// Modbus FC 0x18 (Read FIFO Queue) で返すキューへの追加（サーバー起動中のみ）
+	EnqueueFIFO(ctx context.Context, in *EnqueueFIFORequest, opts ...grpc.CallOption) (*Empty, error)
+	// Modbus FC 0x0B のイベントカウンタ（正常に応答したリクエスト数、サーバー起動中のみ）
+	GetCommEventCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetCommEventCountResponse, error)
+	ClearCommEventCounter(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	// duration_ms ミリ秒の間、全リクエストにビジー例外を返す（0 で解除、サーバー起動中のみ）
+	SetBusy(ctx context.Context, in *SetBusyRequest, opts ...grpc.CallOption) (*Empty, error)
+	// 処理したリクエスト数・例外応答数・最後のファンクションコード・接続数（plc.stats() 用、未起動・未対応の場合は 0）
+	GetTrafficStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetTrafficStatsResponse, error)
+	// UnitID ごとの最終ポーリング時刻（無通信でタイムアウトしたユニットは含まない、未起動・未対応の場合は空）
+	GetUnitActivity(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetUnitActivityResponse, error)
 }
 
 type pluginServiceClient struct {
@@ -178,6 +194,87 @@ func (c *pluginServiceClient) SetDisabledUnitIDs(ctx context.Context, in *SetDis
 	return out, nil
 }
 
+func (c *pluginServiceClient) GetConnections(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetConnectionsResponse, error) {
+	out := new(GetConnectionsResponse)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/GetConnections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) GetAccessCounts(ctx context.Context, in *GetAccessCountsRequest, opts ...grpc.CallOption) (*GetAccessCountsResponse, error) {
+	out := new(GetAccessCountsResponse)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/GetAccessCounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) ResetAccessCounts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/ResetAccessCounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) EnqueueFIFO(ctx context.Context, in *EnqueueFIFORequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/EnqueueFIFO", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) GetCommEventCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetCommEventCountResponse, error) {
+	out := new(GetCommEventCountResponse)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/GetCommEventCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) ClearCommEventCounter(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/ClearCommEventCounter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) SetBusy(ctx context.Context, in *SetBusyRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/SetBusy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) GetTrafficStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetTrafficStatsResponse, error) {
+	out := new(GetTrafficStatsResponse)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/GetTrafficStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) GetUnitActivity(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetUnitActivityResponse, error) {
+	out := new(GetUnitActivityResponse)
+	err := c.cc.Invoke(ctx, "/plugin.v1.PluginService/GetUnitActivity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PluginServiceServer is the server API for PluginService service.
 // All implementations must embed UnimplementedPluginServiceServer
 // for forward compatibility
@@ -202,6 +299,22 @@ type PluginServiceServer interface {
 	GetUnitIDSettings(context.Context, *Empty) (*UnitIDSettingsResponse, error)
 	SetUnitIDEnabled(context.Context, *SetUnitIDEnabledRequest) (*Empty, error)
 	SetDisabledUnitIDs(context.Context, *SetDisabledUnitIDsRequest) (*Empty, error)
+	// 接続中のクライアント一覧（クライアント単位で追跡できるプロトコル用、未対応の場合は空）
+	GetConnections(context.Context, *Empty) (*GetConnectionsResponse, error)
+	// マスターからのアドレスごとの読み書き回数（ヒートマップ用、未対応の場合は空）
+	GetAccessCounts(context.Context, *GetAccessCountsRequest) (*GetAccessCountsResponse, error)
+	ResetAccessCounts(context.Context, *Empty) (*Empty, error)
+	// Modbus FC 0x18 (Read FIFO Queue) で返すキューへの追加（サーバー起動中のみ）
+	EnqueueFIFO(context.Context, *EnqueueFIFORequest) (*Empty, error)
+	// Modbus FC 0x0B のイベントカウンタ（正常に応答したリクエスト数、サーバー起動中のみ）
+	GetCommEventCount(context.Context, *Empty) (*GetCommEventCountResponse, error)
+	ClearCommEventCounter(context.Context, *Empty) (*Empty, error)
+	// duration_ms ミリ秒の間、全リクエストにビジー例外を返す（0 で解除、サーバー起動中のみ）
+	SetBusy(context.Context, *SetBusyRequest) (*Empty, error)
+	// 処理したリクエスト数・例外応答数・最後のファンクションコード・接続数（plc.stats() 用、未起動・未対応の場合は 0）
+	GetTrafficStats(context.Context, *Empty) (*GetTrafficStatsResponse, error)
+	// UnitID ごとの最終ポーリング時刻（無通信でタイムアウトしたユニットは含まない、未起動・未対応の場合は空）
+	GetUnitActivity(context.Context, *Empty) (*GetUnitActivityResponse, error)
 	mustEmbedUnimplementedPluginServiceServer()
 }
 
@@ -251,6 +364,33 @@ func (UnimplementedPluginServiceServer) SetUnitIDEnabled(context.Context, *SetUn
 func (UnimplementedPluginServiceServer) SetDisabledUnitIDs(context.Context, *SetDisabledUnitIDsRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetDisabledUnitIDs not implemented")
 }
+func (UnimplementedPluginServiceServer) GetConnections(context.Context, *Empty) (*GetConnectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConnections not implemented")
+}
+func (UnimplementedPluginServiceServer) GetAccessCounts(context.Context, *GetAccessCountsRequest) (*GetAccessCountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccessCounts not implemented")
+}
+func (UnimplementedPluginServiceServer) ResetAccessCounts(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetAccessCounts not implemented")
+}
+func (UnimplementedPluginServiceServer) EnqueueFIFO(context.Context, *EnqueueFIFORequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnqueueFIFO not implemented")
+}
+func (UnimplementedPluginServiceServer) GetCommEventCount(context.Context, *Empty) (*GetCommEventCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommEventCount not implemented")
+}
+func (UnimplementedPluginServiceServer) ClearCommEventCounter(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearCommEventCounter not implemented")
+}
+func (UnimplementedPluginServiceServer) SetBusy(context.Context, *SetBusyRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBusy not implemented")
+}
+func (UnimplementedPluginServiceServer) GetTrafficStats(context.Context, *Empty) (*GetTrafficStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrafficStats not implemented")
+}
+func (UnimplementedPluginServiceServer) GetUnitActivity(context.Context, *Empty) (*GetUnitActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUnitActivity not implemented")
+}
 func (UnimplementedPluginServiceServer) mustEmbedUnimplementedPluginServiceServer() {}
 
 // UnsafePluginServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -516,6 +656,168 @@ func _PluginService_SetDisabledUnitIDs_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PluginService_GetConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).GetConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/GetConnections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).GetConnections(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_GetAccessCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccessCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).GetAccessCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/GetAccessCounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).GetAccessCounts(ctx, req.(*GetAccessCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_ResetAccessCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).ResetAccessCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/ResetAccessCounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).ResetAccessCounts(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_EnqueueFIFO_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnqueueFIFORequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).EnqueueFIFO(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/EnqueueFIFO",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).EnqueueFIFO(ctx, req.(*EnqueueFIFORequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_GetCommEventCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).GetCommEventCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/GetCommEventCount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).GetCommEventCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_ClearCommEventCounter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).ClearCommEventCounter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/ClearCommEventCounter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).ClearCommEventCounter(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_SetBusy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBusyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).SetBusy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/SetBusy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).SetBusy(ctx, req.(*SetBusyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_GetTrafficStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).GetTrafficStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/GetTrafficStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).GetTrafficStats(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_GetUnitActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).GetUnitActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.v1.PluginService/GetUnitActivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).GetUnitActivity(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PluginService_ServiceDesc is the grpc.ServiceDesc for PluginService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -579,6 +881,42 @@ var PluginService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetDisabledUnitIDs",
 			Handler:    _PluginService_SetDisabledUnitIDs_Handler,
 		},
+		{
+			MethodName: "GetConnections",
+			Handler:    _PluginService_GetConnections_Handler,
+		},
+		{
+			MethodName: "GetAccessCounts",
+			Handler:    _PluginService_GetAccessCounts_Handler,
+		},
+		{
+			MethodName: "ResetAccessCounts",
+			Handler:    _PluginService_ResetAccessCounts_Handler,
+		},
+		{
+			MethodName: "EnqueueFIFO",
+			Handler:    _PluginService_EnqueueFIFO_Handler,
+		},
+		{
+			MethodName: "GetCommEventCount",
+			Handler:    _PluginService_GetCommEventCount_Handler,
+		},
+		{
+			MethodName: "ClearCommEventCounter",
+			Handler:    _PluginService_ClearCommEventCounter_Handler,
+		},
+		{
+			MethodName: "SetBusy",
+			Handler:    _PluginService_SetBusy_Handler,
+		},
+		{
+			MethodName: "GetTrafficStats",
+			Handler:    _PluginService_GetTrafficStats_Handler,
+		},
+		{
+			MethodName: "GetUnitActivity",
+			Handler:    _PluginService_GetUnitActivity_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "plugin_service.proto",
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// DecodeJSONRecord は UseJSON で出力された1行を slog.Record に戻す。
+// JSON でない行や time/level/msg を持たない行の場合は false を返す。
+func DecodeJSONRecord(line string) (slog.Record, bool) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(line)))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return slog.Record{}, false
+	}
+
+	msg, ok := fields[slog.MessageKey].(string)
+	if !ok {
+		return slog.Record{}, false
+	}
+	levelText, ok := fields[slog.LevelKey].(string)
+	if !ok {
+		return slog.Record{}, false
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(levelText)); err != nil {
+		return slog.Record{}, false
+	}
+	var at time.Time
+	if s, ok := fields[slog.TimeKey].(string); ok {
+		at, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	delete(fields, slog.MessageKey)
+	delete(fields, slog.LevelKey)
+	delete(fields, slog.TimeKey)
+	r := slog.NewRecord(at, l, msg, 0)
+	r.AddAttrs(jsonAttrs(fields)...)
+	return r, true
+}
+
+// jsonAttrs は JSON オブジェクトをキー順の属性に変換する（入れ子のオブジェクトはグループにする）
+func jsonAttrs(fields map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		switch v := fields[k].(type) {
+		case map[string]interface{}:
+			attrs[i] = slog.Attr{Key: k, Value: slog.GroupValue(jsonAttrs(v)...)}
+		case json.Number:
+			attrs[i] = slog.String(k, v.String())
+		default:
+			attrs[i] = slog.Any(k, v)
+		}
+	}
+	return attrs
+}
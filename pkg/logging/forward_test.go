@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSONRecord_RoundTrip(t *testing.T) {
+	prev := defaultHandler.Load()
+	defer func() { defaultHandler.Store(prev) }()
+
+	var buf bytes.Buffer
+	UseJSON(&buf)
+	Default().With("protocol", "modbus-tcp").WithGroup("req").Warn("request rejected", "unitId", 3, "funcCode", "0x03")
+
+	r, ok := DecodeJSONRecord(strings.TrimSpace(buf.String()))
+	if !ok {
+		t.Fatalf("DecodeJSONRecord failed for %q", buf.String())
+	}
+	sink := NewSink(10)
+	if err := sink.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	e := sink.Entries(slog.LevelDebug, 0)[0]
+	if e.Level != slog.LevelWarn || e.Message != "request rejected" {
+		t.Errorf("entry = %+v, want warn \"request rejected\"", e)
+	}
+	if e.Attrs["protocol"] != "modbus-tcp" || e.Attrs["req.unitId"] != "3" || e.Attrs["req.funcCode"] != "0x03" {
+		t.Errorf("unexpected attrs: %v", e.Attrs)
+	}
+	if e.Time.IsZero() {
+		t.Error("expected time to be restored")
+	}
+}
+
+func TestDecodeJSONRecord_NotJSON(t *testing.T) {
+	for _, line := range []string{"Modbus Plugin starting...", `{"msg":"no level"}`, `[1,2]`} {
+		if _, ok := DecodeJSONRecord(line); ok {
+			t.Errorf("DecodeJSONRecord(%q) should fail", line)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package logging はサーバー・スクリプトエンジン共通の構造化ロガー (log/slog) を提供する
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// level はデフォルトロガーのレベルフィルタ（実行中に変更可能）
+var level = new(slog.LevelVar)
+
+// SetLevel はデフォルトロガーの出力レベルを設定する
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// Level はデフォルトロガーの出力レベルを返す
+func Level() slog.Level {
+	return level.Level()
+}
+
+// ParseLevel は "debug" / "info" / "warn" / "error" を slog.Level に変換する（大文字小文字は区別しない）
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level: %s", s)
+	}
+}
+
+// NewTextHandler は w にテキスト形式で出力する Handler を作成する。
+// 出力レベルは SetLevel で設定した共通のレベルフィルタに従う。
+func NewTextHandler(w io.Writer) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+}
+
+// NewTextLogger は w にテキスト形式で出力するロガーを作成する
+func NewTextLogger(w io.Writer) *slog.Logger {
+	return slog.New(NewTextHandler(w))
+}
+
+// defaultHandler は UseJSON で設定された Default の出力先（nil の場合は標準エラー出力へのテキスト形式）
+var defaultHandler atomic.Pointer[slog.Handler]
+
+// UseJSON は Default が返すロガーの出力を w への JSON 形式（1行1レコード）に切り替える。
+// プラグインプロセスが stderr に出力したログをホストが DecodeJSONRecord で復元できるようにする。
+func UseJSON(w io.Writer) {
+	var h slog.Handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	defaultHandler.Store(&h)
+}
+
+// Default は標準エラー出力へのテキストロガーを返す（SetLogger 未指定時の既定値）
+func Default() *slog.Logger {
+	if h := defaultHandler.Load(); h != nil {
+		return slog.New(*h)
+	}
+	return NewTextLogger(os.Stderr)
+}
+
+// Discard は何も出力しないロガーを返す
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"INFO", slog.LevelInfo},
+		{"warn", slog.LevelWarn},
+		{"warning", slog.LevelWarn},
+		{"Error", slog.LevelError},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if err != nil {
+			t.Errorf("ParseLevel(%q) failed: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestNewTextLogger_LevelFilter(t *testing.T) {
+	prev := Level()
+	defer SetLevel(prev)
+
+	var buf bytes.Buffer
+	logger := NewTextLogger(&buf)
+
+	SetLevel(slog.LevelWarn)
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("unexpected output at warn level: %q", buf.String())
+	}
+
+	// レベルの変更は作成済みのロガーにも反映される
+	buf.Reset()
+	SetLevel(slog.LevelDebug)
+	logger.Debug("debug record")
+	if !strings.Contains(buf.String(), "debug record") {
+		t.Errorf("expected debug record after lowering level, got %q", buf.String())
+	}
+}
@@ -0,0 +1,242 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Entry はメモリシンクに保持されるログ1件
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]string
+}
+
+// Sink は直近のログを上限付きで保持する slog.Handler。
+// パッケージ版アプリではコンソール出力が見えないため、UI からログを参照する用途に使う。
+type Sink struct {
+	state  *sinkState
+	attrs  []slog.Attr
+	groups []string
+}
+
+type sinkState struct {
+	mu       sync.Mutex
+	capacity int
+	entries  []Entry
+	onEntry  func(Entry)
+
+	// コールバック待ちのログ（配信用 goroutine が追加順に1件ずつ渡す）
+	pending    []Entry
+	wake       chan struct{}
+	delivering bool
+}
+
+// NewSink は最大 capacity 件を保持する Sink を作成する（超過分は古い順に破棄）
+func NewSink(capacity int) *Sink {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Sink{state: &sinkState{capacity: capacity}}
+}
+
+// SetOnEntry はログ追加時のコールバックを設定する（ライブ表示用）。
+// コールバックはログを出力した goroutine をブロックしないよう、専用の goroutine から追加順に呼ばれる。
+func (s *Sink) SetOnEntry(cb func(Entry)) {
+	st := s.state
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.onEntry = cb
+	if cb != nil && !st.delivering {
+		st.delivering = true
+		st.wake = make(chan struct{}, 1)
+		go st.deliver()
+	}
+}
+
+// deliver はコールバック待ちのログを追加順にコールバックへ渡し続ける
+func (st *sinkState) deliver() {
+	for range st.wake {
+		for {
+			st.mu.Lock()
+			batch := st.pending
+			st.pending = nil
+			cb := st.onEntry
+			st.mu.Unlock()
+			if len(batch) == 0 {
+				break
+			}
+			if cb == nil {
+				continue
+			}
+			for _, e := range batch {
+				cb(e)
+			}
+		}
+	}
+}
+
+// Entries は minLevel 以上のログを古い順に返す。limit > 0 の場合は新しいものから最大 limit 件。
+func (s *Sink) Entries(minLevel slog.Level, limit int) []Entry {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	var result []Entry
+	for _, e := range s.state.entries {
+		if e.Level >= minLevel {
+			result = append(result, e)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// Clear は保持しているログを破棄する
+func (s *Sink) Clear() {
+	s.state.mu.Lock()
+	s.state.entries = nil
+	s.state.mu.Unlock()
+}
+
+// Enabled は共通のレベルフィルタに従う
+func (s *Sink) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+// Handle はログを1件追加する
+func (s *Sink) Handle(_ context.Context, r slog.Record) error {
+	entry := Entry{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   make(map[string]string, len(s.attrs)+r.NumAttrs()),
+	}
+	prefix := ""
+	for _, g := range s.groups {
+		prefix += g + "."
+	}
+	for _, a := range s.attrs {
+		addAttr(entry.Attrs, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(entry.Attrs, prefix, a)
+		return true
+	})
+
+	st := s.state
+	st.mu.Lock()
+	st.entries = append(st.entries, entry)
+	if len(st.entries) > st.capacity {
+		st.entries = st.entries[len(st.entries)-st.capacity:]
+	}
+	if st.onEntry != nil {
+		// コールバックが追いつかない場合も保持件数を超えては溜めない
+		st.pending = append(st.pending, entry)
+		if len(st.pending) > st.capacity {
+			st.pending = st.pending[len(st.pending)-st.capacity:]
+		}
+		select {
+		case st.wake <- struct{}{}:
+		default:
+		}
+	}
+	st.mu.Unlock()
+	return nil
+}
+
+// WithAttrs は属性を付加した Handler を返す（保持領域は共有）
+func (s *Sink) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefix := ""
+	for _, g := range s.groups {
+		prefix += g + "."
+	}
+	next := &Sink{state: s.state, groups: s.groups}
+	next.attrs = append(append([]slog.Attr{}, s.attrs...), prefixAttrs(prefix, attrs)...)
+	return next
+}
+
+// WithGroup はグループ名を付加した Handler を返す（属性キーは "group.key" に平坦化する）
+func (s *Sink) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return s
+	}
+	return &Sink{
+		state:  s.state,
+		attrs:  s.attrs,
+		groups: append(append([]string{}, s.groups...), name),
+	}
+}
+
+func prefixAttrs(prefix string, attrs []slog.Attr) []slog.Attr {
+	if prefix == "" {
+		return attrs
+	}
+	result := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		result[i] = slog.Attr{Key: prefix + a.Key, Value: a.Value}
+	}
+	return result
+}
+
+func addAttr(dst map[string]string, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			addAttr(dst, prefix+a.Key+".", ga)
+		}
+		return
+	}
+	dst[prefix+a.Key] = fmt.Sprint(a.Value.Any())
+}
+
+// multiHandler は複数の Handler にログを配信する
+type multiHandler []slog.Handler
+
+// NewMultiHandler は複数の Handler へ同じログを配信する Handler を作成する
+func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
+	return multiHandler(handlers)
+}
+
+func (m multiHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range m {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make(multiHandler, len(m))
+	for i, h := range m {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	next := make(multiHandler, len(m))
+	for i, h := range m {
+		next[i] = h.WithGroup(name)
+	}
+	return next
+}
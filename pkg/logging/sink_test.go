@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSink_Bounded(t *testing.T) {
+	sink := NewSink(3)
+	logger := slog.New(sink)
+
+	for i := 0; i < 5; i++ {
+		logger.Info(fmt.Sprintf("msg%d", i))
+	}
+
+	entries := sink.Entries(slog.LevelDebug, 0)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Message != "msg2" || entries[2].Message != "msg4" {
+		t.Errorf("expected oldest entries to be dropped, got %q..%q", entries[0].Message, entries[2].Message)
+	}
+}
+
+func TestSink_LevelFilterAndLimit(t *testing.T) {
+	prev := Level()
+	defer SetLevel(prev)
+	SetLevel(slog.LevelDebug)
+
+	sink := NewSink(10)
+	logger := slog.New(sink)
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Warn("w1")
+	logger.Error("e")
+	logger.Warn("w2")
+
+	warn := sink.Entries(slog.LevelWarn, 0)
+	if len(warn) != 3 {
+		t.Fatalf("expected 3 warn+ entries, got %d", len(warn))
+	}
+	limited := sink.Entries(slog.LevelWarn, 2)
+	if len(limited) != 2 || limited[0].Message != "e" || limited[1].Message != "w2" {
+		t.Errorf("expected newest 2 entries [e w2], got %+v", limited)
+	}
+
+	// 共通のレベルフィルタより低いログは保持されない
+	SetLevel(slog.LevelError)
+	logger.Warn("dropped")
+	if got := sink.Entries(slog.LevelDebug, 0); got[len(got)-1].Message == "dropped" {
+		t.Error("expected warn record to be filtered at error level")
+	}
+}
+
+func TestSink_AttrsAndCallback(t *testing.T) {
+	sink := NewSink(10)
+	got := make(chan Entry, 1)
+	sink.SetOnEntry(func(e Entry) { got <- e })
+
+	logger := slog.New(sink).With("protocol", "modbus-rtu").WithGroup("req")
+	logger.Info("request handled", "unitId", 1)
+
+	entries := sink.Entries(slog.LevelDebug, 0)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	attrs := entries[0].Attrs
+	if attrs["protocol"] != "modbus-rtu" || attrs["req.unitId"] != "1" {
+		t.Errorf("unexpected attrs: %v", attrs)
+	}
+
+	select {
+	case e := <-got:
+		if e.Message != "request handled" {
+			t.Errorf("unexpected callback entry: %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("callback not called")
+	}
+}
+
+func TestMultiHandler(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewSink(10)
+	logger := slog.New(NewMultiHandler(slog.NewTextHandler(&buf, nil), sink))
+
+	logger.Info("both")
+	if !strings.Contains(buf.String(), "both") {
+		t.Errorf("expected text output, got %q", buf.String())
+	}
+	if len(sink.Entries(slog.LevelDebug, 0)) != 1 {
+		t.Error("expected sink to receive the record")
+	}
+}
+
+func TestSink_CallbackInOrder(t *testing.T) {
+	const n = 200
+	sink := NewSink(n)
+	got := make(chan string, n)
+	sink.SetOnEntry(func(e Entry) { got <- e.Message })
+
+	logger := slog.New(sink)
+	for i := 0; i < n; i++ {
+		logger.Info(fmt.Sprintf("msg%d", i))
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-got:
+			if want := fmt.Sprintf("msg%d", i); msg != want {
+				t.Fatalf("callback %d = %q, want %q", i, msg, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("callback %d not called", i)
+		}
+	}
+}